@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"crypto/ecdsa"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -13,6 +14,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/NilFoundation/nil/nil/services/txnpool"
 )
@@ -56,6 +58,10 @@ type Client interface {
 		stateOverride *jsonrpc.StateOverrides,
 	) (*jsonrpc.CallRes, error)
 	GetCode(ctx context.Context, addr types.Address, blockId any) (types.Code, error)
+	GetStorageAt(ctx context.Context, addr types.Address, key common.Hash, blockId any) (common.Hash, error)
+	GetProof(
+		ctx context.Context, addr types.Address, keys []common.Hash, blockId any,
+	) (*jsonrpc.RPCAccountProof, error)
 	GetBlock(ctx context.Context, shardId types.ShardId, blockId any, fullTx bool) (*jsonrpc.RPCBlock, error)
 	GetBlocksRange(
 		ctx context.Context,
@@ -78,12 +84,22 @@ type Client interface {
 	SendRawTransaction(ctx context.Context, data []byte) (common.Hash, error)
 	GetInTransactionByHash(ctx context.Context, hash common.Hash) (*jsonrpc.RPCInTransaction, error)
 	GetInTransactionReceipt(ctx context.Context, hash common.Hash) (*jsonrpc.RPCReceipt, error)
+	GetTransactionTree(ctx context.Context, hash common.Hash) (*jsonrpc.RPCTransactionTree, error)
 	GetTransactionCount(ctx context.Context, address types.Address, blockId any) (types.Seqno, error)
 	GetBlockTransactionCount(ctx context.Context, shardId types.ShardId, blockId any) (uint64, error)
 	GetBalance(ctx context.Context, address types.Address, blockId any) (types.Value, error)
 	GetShardIdList(ctx context.Context) ([]types.ShardId, error)
 	GetNumShards(ctx context.Context) (uint64, error)
+	GetLogs(ctx context.Context, shardId types.ShardId, query filters.FilterQuery) ([]*jsonrpc.RPCLog, error)
 	GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error)
+	FeeHistory(
+		ctx context.Context,
+		shardId types.ShardId,
+		blockCount uint64,
+		newestBlockId any,
+		rewardPercentiles []float64,
+	) (*jsonrpc.FeeHistory, error)
+	MaxPriorityFeePerGas(ctx context.Context, shardId types.ShardId) (types.Value, error)
 	ChainId(ctx context.Context) (types.ChainId, error)
 
 	DeployContract(
@@ -132,6 +148,26 @@ type Client interface {
 
 	// GetDebugContract retrieves smart contract with its data, such as code, storage and proof
 	GetDebugContract(ctx context.Context, contractAddr types.Address, blockId any) (*jsonrpc.DebugRPCContract, error)
+
+	// TraceTransaction re-executes the transaction and returns its trace produced by the configured tracer
+	TraceTransaction(ctx context.Context, hash common.Hash, config *jsonrpc.TraceConfig) (json.RawMessage, error)
+
+	// GetStateDiff returns the contracts changed in the shard by the blocks after `from` up to `to` inclusive
+	GetStateDiff(
+		ctx context.Context,
+		shardId types.ShardId,
+		from common.Hash,
+		to common.Hash,
+	) ([]*jsonrpc.DebugRPCContractDiff, error)
+
+	// TraceCall executes the call on top of the given block and returns its trace
+	TraceCall(
+		ctx context.Context,
+		args *jsonrpc.CallArgs,
+		blockId any,
+		stateOverride *jsonrpc.StateOverrides,
+		config *jsonrpc.TraceConfig,
+	) (json.RawMessage, error)
 }
 
 func EstimateFeeExternal(
@@ -310,3 +346,21 @@ func WaitForReceipt(
 
 	return receipt, err
 }
+
+// WaitForTransactionTree follows the transaction and all the transactions spawned by it across the shards
+// until the tree is settled or fails. The last fetched tree is returned on timeout.
+func WaitForTransactionTree(
+	ctx context.Context,
+	client Client,
+	hash common.Hash,
+	timeout time.Duration,
+) (*jsonrpc.RPCTransactionTree, error) {
+	var tree *jsonrpc.RPCTransactionTree
+	var err error
+	err = common.WaitFor(ctx, timeout, 500*time.Millisecond, func(ctx context.Context) bool {
+		tree, err = client.GetTransactionTree(ctx, hash)
+		return err == nil && tree != nil && tree.Status != jsonrpc.TransactionTreePending
+	})
+
+	return tree, err
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
@@ -35,7 +36,7 @@ func NewEthClient(
 	localApi rawapi.NodeApi,
 	logger logging.Logger,
 ) (*DirectClient, error) {
-	ethApi := jsonrpc.NewEthAPI(ctx, localApi, db, true, false)
+	ethApi := jsonrpc.NewEthAPI(ctx, localApi, db, nil, nil, true, false)
 	debugApi := jsonrpc.NewDebugAPI(localApi, logger)
 	dbApi := jsonrpc.NewDbAPI(db, logger)
 	web3Api := jsonrpc.NewWeb3API(localApi)
@@ -63,6 +64,28 @@ func (c *DirectClient) GetCode(ctx context.Context, addr types.Address, blockId
 	return types.Code(raw), err
 }
 
+func (c *DirectClient) GetStorageAt(
+	ctx context.Context, addr types.Address, key common.Hash, blockId any,
+) (common.Hash, error) {
+	blockNrOrHash, err := transport.AsBlockReference(blockId)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+
+	return c.ethApi.GetStorageAt(ctx, addr, key, transport.BlockNumberOrHash(blockNrOrHash))
+}
+
+func (c *DirectClient) GetProof(
+	ctx context.Context, addr types.Address, keys []common.Hash, blockId any,
+) (*jsonrpc.RPCAccountProof, error) {
+	blockNrOrHash, err := transport.AsBlockReference(blockId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ethApi.GetProof(ctx, addr, keys, transport.BlockNumberOrHash(blockNrOrHash))
+}
+
 func (c *DirectClient) GetBlock(
 	ctx context.Context,
 	shardId types.ShardId,
@@ -184,6 +207,10 @@ func (c *DirectClient) GetInTransactionReceipt(ctx context.Context, hash common.
 	return c.ethApi.GetInTransactionReceipt(ctx, hash)
 }
 
+func (c *DirectClient) GetTransactionTree(ctx context.Context, hash common.Hash) (*jsonrpc.RPCTransactionTree, error) {
+	return c.ethApi.GetTransactionTree(ctx, hash)
+}
+
 func (c *DirectClient) GetTransactionCount(
 	ctx context.Context,
 	address types.Address,
@@ -255,6 +282,24 @@ func (c *DirectClient) GasPrice(ctx context.Context, shardId types.ShardId) (typ
 	return c.ethApi.GasPrice(ctx, shardId)
 }
 
+func (c *DirectClient) FeeHistory(
+	ctx context.Context,
+	shardId types.ShardId,
+	blockCount uint64,
+	newestBlockId any,
+	rewardPercentiles []float64,
+) (*jsonrpc.FeeHistory, error) {
+	newestBlock, err := transport.AsBlockNumber(newestBlockId)
+	if err != nil {
+		return nil, err
+	}
+	return c.ethApi.FeeHistory(ctx, shardId, blockCount, newestBlock, rewardPercentiles)
+}
+
+func (c *DirectClient) MaxPriorityFeePerGas(ctx context.Context, shardId types.ShardId) (types.Value, error) {
+	return c.ethApi.MaxPriorityFeePerGas(ctx, shardId)
+}
+
 func (c *DirectClient) ChainId(ctx context.Context) (types.ChainId, error) {
 	res, err := c.ethApi.ChainId(ctx)
 	if err != nil {
@@ -272,6 +317,12 @@ func (c *DirectClient) GetNumShards(ctx context.Context) (uint64, error) {
 	return c.ethApi.GetNumShards(ctx)
 }
 
+func (c *DirectClient) GetLogs(
+	ctx context.Context, shardId types.ShardId, query filters.FilterQuery,
+) ([]*jsonrpc.RPCLog, error) {
+	return c.ethApi.GetLogs(ctx, shardId, query)
+}
+
 func (c *DirectClient) DeployContract(
 	ctx context.Context, shardId types.ShardId, smartAccountAddress types.Address, payload types.DeployPayload,
 	value types.Value, fee types.FeePack, pk *ecdsa.PrivateKey,
@@ -423,6 +474,37 @@ func (c *DirectClient) GetDebugContract(
 	return c.debugApi.GetContract(ctx, contractAddr, transport.BlockNumberOrHash(blockNrOrHash))
 }
 
+func (c *DirectClient) TraceTransaction(
+	ctx context.Context,
+	hash common.Hash,
+	config *jsonrpc.TraceConfig,
+) (json.RawMessage, error) {
+	return c.debugApi.TraceTransaction(ctx, hash, config)
+}
+
+func (c *DirectClient) TraceCall(
+	ctx context.Context,
+	args *jsonrpc.CallArgs,
+	blockId any,
+	stateOverride *jsonrpc.StateOverrides,
+	config *jsonrpc.TraceConfig,
+) (json.RawMessage, error) {
+	blockNrOrHash, err := transport.AsBlockReference(blockId)
+	if err != nil {
+		return nil, err
+	}
+	return c.debugApi.TraceCall(ctx, *args, transport.BlockNumberOrHash(blockNrOrHash), stateOverride, config)
+}
+
+func (c *DirectClient) GetStateDiff(
+	ctx context.Context,
+	shardId types.ShardId,
+	from common.Hash,
+	to common.Hash,
+) ([]*jsonrpc.DebugRPCContractDiff, error) {
+	return c.debugApi.GetStateDiff(ctx, shardId, from, to)
+}
+
 func (c *DirectClient) ClientVersion(ctx context.Context) (string, error) {
 	return c.web3Api.ClientVersion(ctx)
 }
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+)
+
+var ErrInvalidProof = errors.New("invalid proof")
+
+// VerifyAccountProof checks the result of eth_getProof against the contract trie root of the block
+// (types.Block.SmartContractsRoot), which the caller must obtain from a trusted source.
+// On success, the account fields and the storage values of the proof can be used without trusting the RPC node.
+func VerifyAccountProof(proof *jsonrpc.RPCAccountProof, smartContractsRoot common.Hash) error {
+	if err := verifyMptRead(
+		proof.AccountProof, proof.Address.Hash().Bytes(), proof.Contract, smartContractsRoot,
+	); err != nil {
+		return fmt.Errorf("account %s: %w", proof.Address, err)
+	}
+
+	// The account doesn't exist, so none of its fields or slots may be set.
+	if len(proof.Contract) == 0 {
+		if !proof.Balance.IsZero() || !proof.CodeHash.Empty() || proof.Seqno != 0 || proof.ExtSeqno != 0 ||
+			!proof.StorageRoot.Empty() {
+			return fmt.Errorf("%w: fields of the absent account %s are set", ErrInvalidProof, proof.Address)
+		}
+		for _, sp := range proof.StorageProof {
+			if !sp.Value.Empty() {
+				return fmt.Errorf("%w: slot %s of the absent account %s is set", ErrInvalidProof, sp.Key, proof.Address)
+			}
+		}
+		return nil
+	}
+
+	var contract types.SmartContract
+	if err := contract.UnmarshalSSZ(proof.Contract); err != nil {
+		return fmt.Errorf("%w: failed to decode account %s: %w", ErrInvalidProof, proof.Address, err)
+	}
+	if contract.Balance.Cmp(proof.Balance) != 0 ||
+		contract.CodeHash != proof.CodeHash ||
+		contract.Seqno != types.Seqno(proof.Seqno) ||
+		contract.ExtSeqno != types.Seqno(proof.ExtSeqno) ||
+		contract.StorageRoot != proof.StorageRoot {
+		return fmt.Errorf("%w: fields of the account %s don't match the proven state", ErrInvalidProof, proof.Address)
+	}
+
+	for _, sp := range proof.StorageProof {
+		// Zero values are not stored in the trie, so their proofs are proofs of absence.
+		var value []byte
+		if !sp.Value.Empty() {
+			var err error
+			if value, err = (*types.Uint256)(sp.Value.Uint256()).MarshalSSZ(); err != nil {
+				return err
+			}
+		}
+		if err := verifyMptRead(sp.Proof, sp.Key.Bytes(), value, contract.StorageRoot); err != nil {
+			return fmt.Errorf("slot %s of account %s: %w", sp.Key, proof.Address, err)
+		}
+	}
+	return nil
+}
+
+func verifyMptRead(encodedProof []byte, key []byte, value []byte, root common.Hash) error {
+	proof, err := mpt.DecodeProof(encodedProof)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+	ok, err := proof.VerifyRead(key, value, root)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+	if !ok {
+		return ErrInvalidProof
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyAccountProof(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	shardId := types.BaseShardId
+	addr := types.GenerateRandomAddress(shardId)
+	key := common.HexToHash("0x01")
+	value := common.HexToHash("0xdeadbeef")
+
+	tx, err := database.CreateRwTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, es.CreateAccount(addr))
+	require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(1234)))
+	require.NoError(t, es.SetState(addr, key, value))
+
+	blockRes, err := es.Commit(0, nil)
+	require.NoError(t, err)
+	require.NoError(t, execution.PostprocessBlock(tx, shardId, blockRes, execution.ModeVerify))
+	require.NoError(t, tx.Commit())
+	root := blockRes.Block.SmartContractsRoot
+
+	nodeApi := rawapi.NodeApiBuilder(database, nil).WithLocalShardApiRo(shardId).BuildAndReset()
+	c, err := NewEthClient(ctx, database, nodeApi, logging.Nop())
+	require.NoError(t, err)
+
+	emptyKey := common.HexToHash("0x02")
+	proof, err := c.GetProof(ctx, addr, []common.Hash{key, emptyKey}, blockRes.BlockHash)
+	require.NoError(t, err)
+	require.NoError(t, VerifyAccountProof(proof, root))
+
+	t.Run("WrongRoot", func(t *testing.T) {
+		require.ErrorIs(t, VerifyAccountProof(proof, common.HexToHash("0x1234")), ErrInvalidProof)
+	})
+
+	t.Run("TamperedBalance", func(t *testing.T) {
+		tampered := *proof
+		tampered.Balance = types.NewValueFromUint64(4321)
+		require.ErrorIs(t, VerifyAccountProof(&tampered, root), ErrInvalidProof)
+	})
+
+	t.Run("TamperedSlot", func(t *testing.T) {
+		tampered := *proof
+		tampered.StorageProof = slices.Clone(proof.StorageProof)
+		tampered.StorageProof[1].Value = value
+		require.ErrorIs(t, VerifyAccountProof(&tampered, root), ErrInvalidProof)
+	})
+
+	t.Run("MalformedProof", func(t *testing.T) {
+		tampered := *proof
+		tampered.AccountProof = proof.AccountProof[:len(proof.AccountProof)/2]
+		require.ErrorIs(t, VerifyAccountProof(&tampered, root), ErrInvalidProof)
+	})
+
+	t.Run("Absent", func(t *testing.T) {
+		absent, err := c.GetProof(ctx, types.GenerateRandomAddress(shardId), []common.Hash{key}, blockRes.BlockHash)
+		require.NoError(t, err)
+		require.NoError(t, VerifyAccountProof(absent, root))
+
+		absent.StorageProof[0].Value = value
+		require.ErrorIs(t, VerifyAccountProof(absent, root), ErrInvalidProof)
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 )
@@ -62,11 +63,14 @@ const (
 	Eth_call                             = "eth_call"
 	Eth_estimateFee                      = "eth_estimateFee"
 	Eth_getCode                          = "eth_getCode"
+	Eth_getStorageAt                     = "eth_getStorageAt"
+	Eth_getProof                         = "eth_getProof"
 	Eth_getBlockByHash                   = "eth_getBlockByHash"
 	Eth_getBlockByNumber                 = "eth_getBlockByNumber"
 	Eth_sendRawTransaction               = "eth_sendRawTransaction"
 	Eth_getInTransactionByHash           = "eth_getInTransactionByHash"
 	Eth_getInTransactionReceipt          = "eth_getInTransactionReceipt"
+	Eth_getTransactionTree               = "eth_getTransactionTree"
 	Eth_getTransactionCount              = "eth_getTransactionCount"
 	Eth_getBlockTransactionCountByNumber = "eth_getBlockTransactionCountByNumber"
 	Eth_getBlockTransactionCountByHash   = "eth_getBlockTransactionCountByHash"
@@ -75,10 +79,16 @@ const (
 	Eth_getShardIdList                   = "eth_getShardIdList"
 	Eth_getNumShards                     = "eth_getNumShards"
 	Eth_gasPrice                         = "eth_gasPrice"
+	Eth_feeHistory                       = "eth_feeHistory"
+	Eth_maxPriorityFeePerGas             = "eth_maxPriorityFeePerGas"
 	Eth_chainId                          = "eth_chainId"
+	Eth_getLogs                          = "eth_getLogs"
 	Debug_getBlockByHash                 = "debug_getBlockByHash"
 	Debug_getBlockByNumber               = "debug_getBlockByNumber"
 	Debug_getContract                    = "debug_getContract"
+	Debug_traceTransaction               = "debug_traceTransaction"
+	Debug_traceCall                      = "debug_traceCall"
+	Debug_getStateDiff                   = "debug_getStateDiff"
 	Web3_clientVersion                   = "web3_clientVersion"
 	Dev_doPanicOnShard                   = "dev_doPanicOnShard"
 	Txpool_getTxpoolStatus               = "txpool_getTxpoolStatus"
@@ -393,6 +403,26 @@ func (c *Client) GetCode(ctx context.Context, addr types.Address, blockId any) (
 	return hexutil.FromHex(codeHex), nil
 }
 
+func (c *Client) GetStorageAt(
+	ctx context.Context, addr types.Address, key common.Hash, blockId any,
+) (common.Hash, error) {
+	blockNrOrHash, err := transport.AsBlockReference(blockId)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	return simpleCall[common.Hash](ctx, c, Eth_getStorageAt, addr, key, blockNrOrHash)
+}
+
+func (c *Client) GetProof(
+	ctx context.Context, addr types.Address, keys []common.Hash, blockId any,
+) (*jsonrpc.RPCAccountProof, error) {
+	blockNrOrHash, err := transport.AsBlockReference(blockId)
+	if err != nil {
+		return nil, err
+	}
+	return simpleCall[*jsonrpc.RPCAccountProof](ctx, c, Eth_getProof, addr, keys, blockNrOrHash)
+}
+
 func (c *Client) getBlockRequest(shardId types.ShardId, blockId any, fullTx bool, isDebug bool) (*Request, error) {
 	blockNrOrHash, err := transport.AsBlockReference(blockId)
 	if err != nil {
@@ -527,6 +557,10 @@ func (c *Client) GetInTransactionReceipt(ctx context.Context, hash common.Hash)
 	return simpleCall[*jsonrpc.RPCReceipt](ctx, c, Eth_getInTransactionReceipt, hash)
 }
 
+func (c *Client) GetTransactionTree(ctx context.Context, hash common.Hash) (*jsonrpc.RPCTransactionTree, error) {
+	return simpleCall[*jsonrpc.RPCTransactionTree](ctx, c, Eth_getTransactionTree, hash)
+}
+
 func (c *Client) GetTransactionCount(ctx context.Context, address types.Address, blockId any) (types.Seqno, error) {
 	blockNrOrHash, err := transport.AsBlockReference(blockId)
 	if err != nil {
@@ -604,6 +638,24 @@ func (c *Client) GasPrice(ctx context.Context, shardId types.ShardId) (types.Val
 	return simpleCall[types.Value](ctx, c, Eth_gasPrice, shardId)
 }
 
+func (c *Client) FeeHistory(
+	ctx context.Context,
+	shardId types.ShardId,
+	blockCount uint64,
+	newestBlockId any,
+	rewardPercentiles []float64,
+) (*jsonrpc.FeeHistory, error) {
+	newestBlock, err := transport.AsBlockNumber(newestBlockId)
+	if err != nil {
+		return nil, err
+	}
+	return simpleCall[*jsonrpc.FeeHistory](ctx, c, Eth_feeHistory, shardId, blockCount, newestBlock, rewardPercentiles)
+}
+
+func (c *Client) MaxPriorityFeePerGas(ctx context.Context, shardId types.ShardId) (types.Value, error) {
+	return simpleCall[types.Value](ctx, c, Eth_maxPriorityFeePerGas, shardId)
+}
+
 func (c *Client) ChainId(ctx context.Context) (types.ChainId, error) {
 	res, err := c.call(ctx, Eth_chainId)
 	if err != nil {
@@ -625,6 +677,12 @@ func (c *Client) GetNumShards(ctx context.Context) (uint64, error) {
 	return simpleCallUint64[uint64](ctx, c, Eth_getNumShards)
 }
 
+func (c *Client) GetLogs(
+	ctx context.Context, shardId types.ShardId, query filters.FilterQuery,
+) ([]*jsonrpc.RPCLog, error) {
+	return simpleCall[[]*jsonrpc.RPCLog](ctx, c, Eth_getLogs, shardId, query)
+}
+
 func (c *Client) ClientVersion(ctx context.Context) (string, error) {
 	res, err := c.call(ctx, Web3_clientVersion)
 	if err != nil {
@@ -946,6 +1004,37 @@ func (c *Client) GetDebugContract(
 	return simpleCall[*jsonrpc.DebugRPCContract](ctx, c, Debug_getContract, contractAddr, blockRef)
 }
 
+func (c *Client) TraceTransaction(
+	ctx context.Context,
+	hash common.Hash,
+	config *jsonrpc.TraceConfig,
+) (json.RawMessage, error) {
+	return c.call(ctx, Debug_traceTransaction, hash, config)
+}
+
+func (c *Client) TraceCall(
+	ctx context.Context,
+	args *jsonrpc.CallArgs,
+	blockId any,
+	stateOverride *jsonrpc.StateOverrides,
+	config *jsonrpc.TraceConfig,
+) (json.RawMessage, error) {
+	blockNrOrHash, err := transport.AsBlockReference(blockId)
+	if err != nil {
+		return nil, err
+	}
+	return c.call(ctx, Debug_traceCall, args, blockNrOrHash, stateOverride, config)
+}
+
+func (c *Client) GetStateDiff(
+	ctx context.Context,
+	shardId types.ShardId,
+	from common.Hash,
+	to common.Hash,
+) ([]*jsonrpc.DebugRPCContractDiff, error) {
+	return simpleCall[[]*jsonrpc.DebugRPCContractDiff](ctx, c, Debug_getStateDiff, shardId, from, to)
+}
+
 func (c *Client) DoPanicOnShard(ctx context.Context, shardId types.ShardId) (uint64, error) {
 	_, err := c.call(ctx, Dev_doPanicOnShard, shardId)
 	return 0, err
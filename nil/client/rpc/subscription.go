@@ -0,0 +1,366 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	Eth_subscribe   = "eth_subscribe"
+	Eth_unsubscribe = "eth_unsubscribe"
+
+	notificationMethod = "eth_subscription"
+
+	// subscriptionBufferSize is the number of notifications buffered per subscription.
+	// A subscription that is not read fast enough is dropped with ErrSubscriptionQueueOverflow.
+	subscriptionBufferSize = 1000
+)
+
+var (
+	ErrClientClosed              = errors.New("client is closed")
+	ErrSubscriptionQueueOverflow = errors.New("subscription queue overflow")
+)
+
+// WsClient is a JSON-RPC client over a WebSocket connection. Unlike Client, it supports subscriptions.
+type WsClient struct {
+	conn   *websocket.Conn
+	logger logging.Logger
+	seqno  atomic.Uint64
+
+	writeLock sync.Mutex
+
+	mu      sync.Mutex
+	pending map[uint64]*pendingCall
+	subs    map[string]subscriber
+	err     error // set when the connection is closed
+
+	closed chan struct{}
+	done   chan struct{}
+}
+
+type wsMessage struct {
+	Id     *uint64         `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+type pendingCall struct {
+	resp chan *wsMessage
+	// sub is registered by the read loop as soon as the subscribe call succeeds,
+	// so no notification sent right after the response is missed.
+	sub subscriber
+}
+
+type subscriber interface {
+	setId(id string)
+	deliver(raw json.RawMessage) bool
+	close(err error)
+}
+
+// wsEndpoint converts the endpoint used by Client to the WebSocket one.
+func wsEndpoint(url string) (string, func(ctx context.Context, network, addr string) (net.Conn, error)) {
+	switch {
+	case strings.HasPrefix(url, "unix://"):
+		socketPath := strings.TrimPrefix(url, "unix://")
+		return "ws://unix", func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socketPath)
+		}
+	case strings.HasPrefix(url, "tcp://"):
+		return "ws://" + strings.TrimPrefix(url, "tcp://"), nil
+	case strings.HasPrefix(url, "http://"):
+		return "ws://" + strings.TrimPrefix(url, "http://"), nil
+	case strings.HasPrefix(url, "https://"):
+		return "wss://" + strings.TrimPrefix(url, "https://"), nil
+	}
+	return url, nil
+}
+
+// NewWsClient connects to the WebSocket endpoint of the RPC server.
+// The endpoint may be given in any form accepted by NewClient, as well as ws:// or wss://.
+func NewWsClient(ctx context.Context, url string, logger logging.Logger) (*WsClient, error) {
+	endpoint, dial := wsEndpoint(url)
+	dialer := websocket.Dialer{NetDialContext: dial}
+	conn, resp, err := dialer.DialContext(ctx, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToSendRequest, err)
+	}
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+
+	c := &WsClient{
+		conn:    conn,
+		logger:  logger,
+		pending: make(map[uint64]*pendingCall),
+		subs:    make(map[string]subscriber),
+		closed:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+// Close closes the connection and all the subscriptions.
+func (c *WsClient) Close() {
+	c.mu.Lock()
+	select {
+	case <-c.closed:
+	default:
+		close(c.closed)
+	}
+	c.mu.Unlock()
+
+	_ = c.conn.Close()
+	<-c.done
+}
+
+func (c *WsClient) readLoop() {
+	defer close(c.done)
+
+	var err error
+	for {
+		var msg wsMessage
+		if err = c.conn.ReadJSON(&msg); err != nil {
+			break
+		}
+		c.handleMessage(&msg)
+	}
+
+	select {
+	case <-c.closed:
+		err = ErrClientClosed
+	default:
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+	for id, call := range c.pending {
+		close(call.resp)
+		delete(c.pending, id)
+	}
+	for id, sub := range c.subs {
+		sub.close(err)
+		delete(c.subs, id)
+	}
+}
+
+func (c *WsClient) handleMessage(msg *wsMessage) {
+	if msg.Id == nil {
+		if msg.Method == notificationMethod {
+			c.handleNotification(msg.Params)
+		}
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	call, ok := c.pending[*msg.Id]
+	if !ok {
+		return
+	}
+	delete(c.pending, *msg.Id)
+
+	if call.sub != nil && len(msg.Error) == 0 {
+		var id string
+		if err := json.Unmarshal(msg.Result, &id); err == nil {
+			call.sub.setId(id)
+			c.subs[id] = call.sub
+		}
+	}
+	call.resp <- msg
+}
+
+func (c *WsClient) handleNotification(params json.RawMessage) {
+	var notification struct {
+		Subscription string          `json:"subscription"`
+		Result       json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(params, &notification); err != nil {
+		c.logger.Debug().Err(err).Msg("failed to unmarshal notification")
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sub, ok := c.subs[notification.Subscription]
+	if !ok {
+		return
+	}
+	if !sub.deliver(notification.Result) {
+		delete(c.subs, notification.Subscription)
+		sub.close(ErrSubscriptionQueueOverflow)
+	}
+}
+
+func (c *WsClient) call(ctx context.Context, sub subscriber, method string, params ...any) (json.RawMessage, error) {
+	request := NewRequest(c.seqno.Add(1), method, params)
+	call := &pendingCall{resp: make(chan *wsMessage, 1), sub: sub}
+
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return nil, err
+	}
+	c.pending[request.Id] = call
+	c.mu.Unlock()
+
+	c.writeLock.Lock()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = c.conn.SetWriteDeadline(deadline)
+	}
+	err := c.conn.WriteJSON(request)
+	c.writeLock.Unlock()
+	if err != nil {
+		c.mu.Lock()
+		delete(c.pending, request.Id)
+		c.mu.Unlock()
+		return nil, fmt.Errorf("%w: %w", ErrFailedToSendRequest, err)
+	}
+
+	select {
+	case resp, ok := <-call.resp:
+		if !ok {
+			return nil, ErrClientClosed
+		}
+		if len(resp.Error) > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrRPCError, resp.Error)
+		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		delete(c.pending, request.Id)
+		c.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// Subscription delivers notifications of an eth_subscribe subscription.
+type Subscription[T any] struct {
+	client *WsClient
+	logger logging.Logger
+
+	id   string
+	ch   chan T
+	err  chan error
+	once sync.Once
+}
+
+func newSubscription[T any](client *WsClient) *Subscription[T] {
+	return &Subscription[T]{
+		client: client,
+		logger: client.logger,
+		ch:     make(chan T, subscriptionBufferSize),
+		err:    make(chan error, 1),
+	}
+}
+
+// ID returns the identifier of the subscription assigned by the server.
+func (s *Subscription[T]) ID() string {
+	return s.id
+}
+
+// Channel returns the channel of notifications. It is closed when the subscription ends.
+func (s *Subscription[T]) Channel() <-chan T {
+	return s.ch
+}
+
+// Err returns a channel that receives the reason of the subscription end
+// (nil if it was canceled by Unsubscribe).
+func (s *Subscription[T]) Err() <-chan error {
+	return s.err
+}
+
+// Unsubscribe cancels the subscription on the server and closes its channels.
+func (s *Subscription[T]) Unsubscribe(ctx context.Context) error {
+	s.client.mu.Lock()
+	_, ok := s.client.subs[s.id]
+	delete(s.client.subs, s.id)
+	s.client.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	s.close(nil)
+	_, err := s.client.call(ctx, nil, Eth_unsubscribe, s.id)
+	return err
+}
+
+func (s *Subscription[T]) setId(id string) {
+	s.id = id
+}
+
+// deliver is called by the read loop with the client lock held.
+func (s *Subscription[T]) deliver(raw json.RawMessage) bool {
+	var value T
+	if err := json.Unmarshal(raw, &value); err != nil {
+		s.logger.Debug().Err(err).Str("subscription", s.id).Msg("failed to unmarshal notification")
+		return true
+	}
+	select {
+	case s.ch <- value:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Subscription[T]) close(err error) {
+	s.once.Do(func() {
+		s.err <- err
+		close(s.err)
+		close(s.ch)
+	})
+}
+
+func subscribe[T any](
+	ctx context.Context, c *WsClient, kind string, shardId types.ShardId, query *filters.FilterQuery,
+) (*Subscription[T], error) {
+	sub := newSubscription[T](c)
+	params := []any{kind, shardId}
+	if query != nil {
+		params = append(params, query)
+	}
+	if _, err := c.call(ctx, sub, Eth_subscribe, params...); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// SubscribeNewHeads subscribes to the headers of the new blocks of the shard.
+func (c *WsClient) SubscribeNewHeads(
+	ctx context.Context, shardId types.ShardId,
+) (*Subscription[*jsonrpc.RPCBlock], error) {
+	return subscribe[*jsonrpc.RPCBlock](ctx, c, jsonrpc.SubscriptionNewHeads, shardId, nil)
+}
+
+// SubscribeLogs subscribes to the new logs of the shard matching the query.
+func (c *WsClient) SubscribeLogs(
+	ctx context.Context, shardId types.ShardId, query filters.FilterQuery,
+) (*Subscription[*jsonrpc.RPCLog], error) {
+	return subscribe[*jsonrpc.RPCLog](ctx, c, jsonrpc.SubscriptionLogs, shardId, &query)
+}
+
+// SubscribeNewPendingTransactions subscribes to the hashes of the transactions accepted by the txnpool of the shard.
+func (c *WsClient) SubscribeNewPendingTransactions(
+	ctx context.Context, shardId types.ShardId,
+) (*Subscription[common.Hash], error) {
+	return subscribe[common.Hash](ctx, c, jsonrpc.SubscriptionNewPendingTransactions, shardId, nil)
+}
@@ -22,6 +22,7 @@ type config struct {
 	command  Command
 	port     int
 	endpoint string
+	faucet   *faucet.Config
 }
 
 func main() {
@@ -44,7 +45,7 @@ func processRun(cfg *config) error {
 	addr := fmt.Sprintf("tcp://127.0.0.1:%d", cfg.port)
 	client := rpc_client.NewClient(cfg.endpoint, logging.NewLogger("faucet"))
 
-	serviceFaucet, err := faucet.NewService(client)
+	serviceFaucet, err := faucet.NewService(client, cfg.faucet)
 	if err != nil {
 		return err
 	}
@@ -52,7 +53,7 @@ func processRun(cfg *config) error {
 }
 
 func parseArgs() *config {
-	cfg := &config{}
+	cfg := &config{faucet: faucet.NewDefaultConfig()}
 	rootCmd := &cobra.Command{
 		Use:           "faucet [global flags] [command]",
 		Short:         "faucet server",
@@ -61,6 +62,22 @@ func parseArgs() *config {
 	}
 	rootCmd.PersistentFlags().StringVar(&cfg.endpoint, "node-endpoint", "http://127.0.0.1:8529", "nil node endpoint")
 	rootCmd.PersistentFlags().IntVar(&cfg.port, "port", 8527, "http service port")
+	rootCmd.PersistentFlags().StringVar(
+		&cfg.faucet.DbPath, "db-path", cfg.faucet.DbPath, "path to the quota database, kept in memory if empty")
+	rootCmd.PersistentFlags().Var(&cfg.faucet.MaxAmount, "max-amount", "maximum amount of a single top-up")
+	rootCmd.PersistentFlags().DurationVar(
+		&cfg.faucet.QuotaWindow, "quota-window", cfg.faucet.QuotaWindow, "sliding window of the quotas")
+	rootCmd.PersistentFlags().Var(
+		&cfg.faucet.RecipientQuota, "recipient-quota", "maximum amount a single address may receive within the window")
+	rootCmd.PersistentFlags().Var(
+		&cfg.faucet.ClientQuota, "client-quota", "maximum amount a single client IP may request within the window")
+	rootCmd.PersistentFlags().DurationVar(
+		&cfg.faucet.Cooldown, "cooldown", cfg.faucet.Cooldown, "minimum interval between top-ups of the same address")
+	rootCmd.PersistentFlags().StringVar(
+		&cfg.faucet.ClientIpHeader,
+		"client-ip-header",
+		cfg.faucet.ClientIpHeader,
+		"http header with the client IP set by a reverse proxy, the remote address is used if empty")
 
 	runCmd := &cobra.Command{
 		Use:   "run",
@@ -17,6 +17,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
 	"github.com/NilFoundation/nil/nil/services/nilservice"
+	"github.com/NilFoundation/nil/nil/services/rollup"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -29,6 +30,8 @@ type nodeSpec struct {
 	BootstrapPeersIdx    []int  `yaml:"bootstrapPeersIdx"`
 	DHTBootstrapPeersIdx []int  `yaml:"dhtBootstrapPeersIdx"`
 	ArchiveNodeIndices   []int  `yaml:"archiveNodeIndices"`
+	// ValidatorWeight is the voting power of the node in the shards it validates, zero means the default one.
+	ValidatorWeight uint64 `yaml:"validatorWeight"`
 }
 
 type clusterSpec struct {
@@ -41,6 +44,7 @@ type clusterSpec struct {
 	PprofBaseTCPPort       int      `yaml:"pprof_base_tcp_port"`
 	NilWipeOnUpdate        bool     `yaml:"nil_wipe_on_update"`
 	NShards                uint32   `yaml:"nShards"`
+	EpochLength            uint64   `yaml:"epochLength"`
 	NilRPCHost             string   `yaml:"nil_rpc_host"`
 	NilRPCPort             int      `yaml:"nil_rpc_port"`
 	EnableRPCOnValidators  bool     `yaml:"nil_rpc_enable_on_validators"`
@@ -52,6 +56,8 @@ type clusterSpec struct {
 	CometaPort             int      `yaml:"cometa_port"`
 	FaucetRPCHost          string   `yaml:"faucet_rpc_host"`
 	FaucetPort             int      `yaml:"faucet_port"`
+	NilL1Backend           string   `yaml:"nil_l1_backend"`
+	NilL1Endpoints         []string `yaml:"nil_l1_endpoints"`
 	NilLoadgenHost         string   `yaml:"nil_loadgen_host"`
 	NilLoadgenPort         int      `yaml:"nil_loadgen_port"`
 	NilUpdateRetryInterval int      `yaml:"nil_update_retry_interval_sec"`
@@ -129,6 +135,7 @@ func (c *cluster) generateZeroState(nShards uint32, servers []server) (*executio
 			idx := id - 1
 			validators[idx].List = append(validators[idx].List, config.ValidatorInfo{
 				PublicKey: config.Pubkey(key),
+				Weight:    srv.nodeSpec.ValidatorWeight,
 			})
 		}
 	}
@@ -144,6 +151,7 @@ func (c *cluster) generateZeroState(nShards uint32, servers []server) (*executio
 		return nil, err
 	}
 	zeroState.ConfigParams.Validators = config.ParamValidators{Validators: validators}
+	zeroState.ConfigParams.EpochLength = c.spec.EpochLength
 
 	return zeroState, nil
 }
@@ -378,6 +386,16 @@ func (c *cluster) writeServerConfig(instanceId int, srv server, only string) err
 		}(),
 	}
 
+	if spec.NilL1Backend != "" || len(spec.NilL1Endpoints) > 0 {
+		cfg.L1 = rollup.NewDefaultL1FetcherConfig()
+		if spec.NilL1Backend != "" {
+			cfg.L1.Backend = spec.NilL1Backend
+		}
+		if len(spec.NilL1Endpoints) > 0 {
+			cfg.L1.Endpoints = spec.NilL1Endpoints
+		}
+	}
+
 	var err error
 	cfg.Network.KeysPath, err = filepath.Abs(srv.NetworkKeysFile())
 	if err != nil {
@@ -100,6 +100,11 @@ func addBasicFlags(fset *pflag.FlagSet, cfg *nildconfig.Config) {
 		&cfg.CollatorTickPeriodMs, "collator-tick-ms", cfg.CollatorTickPeriodMs, "collator tick period in milliseconds")
 }
 
+func addChainDataFlags(fset *pflag.FlagSet, cfg *nildconfig.Config) {
+	fset.StringVar(&cfg.ChainData.Path, "file", cfg.ChainData.Path, "path to chain data file")
+	fset.Uint32Var(&cfg.NShards, "nshards", cfg.NShards, "number of shardchains")
+}
+
 func parseArgs() *nildconfig.Config {
 	cfg, err := loadConfig()
 	check.PanicIfErr(err)
@@ -123,10 +128,85 @@ func parseArgs() *nildconfig.Config {
 	rootCmd.PersistentFlags().DurationVar(
 		&cfg.DB.GcFrequency, "db-gc-interval", cfg.DB.GcFrequency, "frequency for badger GC")
 	rootCmd.PersistentFlags().IntVar(&cfg.RPCPort, "http-port", cfg.RPCPort, "http port for rpc server")
+	rootCmd.PersistentFlags().BoolVar(
+		&cfg.EnableWebsocket, "websocket", cfg.EnableWebsocket, "accept websocket connections on the rpc endpoint")
+	rootCmd.PersistentFlags().Float64Var(
+		&cfg.RPCLimits.RateLimit,
+		"rpc-rate-limit",
+		cfg.RPCLimits.RateLimit,
+		"rpc request cost units per second allowed for a single client, 0 disables rate limiting")
+	rootCmd.PersistentFlags().IntVar(
+		&cfg.RPCLimits.RateBurst, "rpc-rate-burst", cfg.RPCLimits.RateBurst, "rpc rate limit burst for a single client")
+	rootCmd.PersistentFlags().StringVar(
+		&cfg.RPCLimits.ClientKeyHeader,
+		"rpc-client-key-header",
+		cfg.RPCLimits.ClientKeyHeader,
+		"http header identifying the rpc client (e.g., an API key), the remote address is used if empty")
+	rootCmd.PersistentFlags().StringSliceVar(
+		&cfg.RPCLimits.ClientKeys,
+		"rpc-client-keys",
+		cfg.RPCLimits.ClientKeys,
+		"api keys with their own rate limit budget, requests with other keys are limited by the remote address")
+	rootCmd.PersistentFlags().StringVar(
+		&cfg.RPCLimits.ClientIpHeader,
+		"rpc-client-ip-header",
+		cfg.RPCLimits.ClientIpHeader,
+		"http header with the client IP set by a trusted reverse proxy, the remote address is used if empty")
+	rootCmd.PersistentFlags().IntVar(
+		&cfg.RPCLimits.MaxResponseSize,
+		"rpc-max-response-size",
+		cfg.RPCLimits.MaxResponseSize,
+		"maximum size of a single rpc response in bytes, 0 means unlimited")
+	rootCmd.PersistentFlags().IntVar(
+		&cfg.RPCLimits.MaxConnRequests,
+		"rpc-max-conn-requests",
+		cfg.RPCLimits.MaxConnRequests,
+		"maximum number of rpc requests processed concurrently for a single websocket connection, "+
+			"0 means the default")
+	rootCmd.PersistentFlags().StringVar(
+		&cfg.Faucet.DbPath,
+		"faucet-db-path",
+		cfg.Faucet.DbPath,
+		"path to the faucet quota database, kept in memory if empty")
+	rootCmd.PersistentFlags().Var(&cfg.Faucet.MaxAmount, "faucet-max-amount", "maximum amount of a single faucet top-up")
+	rootCmd.PersistentFlags().DurationVar(
+		&cfg.Faucet.QuotaWindow, "faucet-quota-window", cfg.Faucet.QuotaWindow, "sliding window of the faucet quotas")
+	rootCmd.PersistentFlags().Var(
+		&cfg.Faucet.RecipientQuota,
+		"faucet-recipient-quota",
+		"maximum amount a single address may receive from the faucet within the window")
+	rootCmd.PersistentFlags().Var(
+		&cfg.Faucet.ClientQuota,
+		"faucet-client-quota",
+		"maximum amount a single client IP may request from the faucet within the window")
+	rootCmd.PersistentFlags().DurationVar(
+		&cfg.Faucet.Cooldown,
+		"faucet-cooldown",
+		cfg.Faucet.Cooldown,
+		"minimum interval between faucet top-ups of the same address")
+	rootCmd.PersistentFlags().StringVar(
+		&cfg.Faucet.ClientIpHeader,
+		"faucet-client-ip-header",
+		cfg.Faucet.ClientIpHeader,
+		"http header with the client IP set by a reverse proxy for the faucet, the remote address is used if empty")
 	rootCmd.PersistentFlags().Var(
 		&cfg.BootstrapPeers,
 		"bootstrap-peers",
 		"peers for snapshot fetching or transaction sending, must go in the order of shards")
+	rootCmd.PersistentFlags().BoolVar(
+		&cfg.StateSync.Enabled,
+		"state-sync",
+		cfg.StateSync.Enabled,
+		"download and verify the state of a recent block from several peers instead of copying a peer's database")
+	rootCmd.PersistentFlags().IntVar(
+		&cfg.StateSync.Quorum,
+		"state-sync-quorum",
+		cfg.StateSync.Quorum,
+		"number of peers that must agree on the block to sync the state of")
+	rootCmd.PersistentFlags().Var(
+		&cfg.StateSync.PivotHash,
+		"state-sync-pivot",
+		"hash of a trusted main shard block to sync the state of, chosen by the peers if empty")
 	rootCmd.PersistentFlags().StringVar(
 		&cfg.AdminSocketPath,
 		"admin-socket-path",
@@ -163,6 +243,47 @@ func parseArgs() *nildconfig.Config {
 		&cfg.ValidatorKeysPath, "validator-keys-path", cfg.ValidatorKeysPath, "path to write validator keys")
 	runCmd.Flags().BoolVar(&cfg.EnableDevApi, "dev-api", cfg.EnableDevApi, "enable development API")
 	runCmd.Flags().StringVar(&cfg.IndexerConfig, "indexer-config", "", "path to Indexer config")
+	runCmd.Flags().StringVar(
+		&cfg.TxnPoolJournalDir,
+		"txnpool-journal-dir",
+		cfg.TxnPoolJournalDir,
+		"directory to persist pending transactions across restarts (disabled if empty)")
+	runCmd.Flags().DurationVar(
+		&cfg.TxnPoolRejournal, "txnpool-rejournal", cfg.TxnPoolRejournal, "transaction pool journal rotation interval")
+	runCmd.Flags().Uint64Var(
+		&cfg.TxnPoolAccountSlots,
+		"txnpool-account-slots",
+		cfg.TxnPoolAccountSlots,
+		"maximum number of pending transactions per account")
+	runCmd.Flags().Uint64Var(
+		&cfg.TxnPoolAccountQueue,
+		"txnpool-account-queue",
+		cfg.TxnPoolAccountQueue,
+		"maximum number of transactions per account waiting for a seqno gap to be filled")
+	runCmd.Flags().DurationVar(
+		&cfg.TxnPoolLifetime, "txnpool-lifetime", cfg.TxnPoolLifetime, "maximum time a transaction stays in the pool")
+
+	runCmd.Flags().StringVar(
+		&cfg.L1.Backend,
+		"l1-backend",
+		cfg.L1.Backend,
+		"source of L1 blocks: rpc, simulated (local deterministic chain) or file")
+	runCmd.Flags().StringSliceVar(
+		&cfg.L1.Endpoints, "l1-endpoints", cfg.L1.Endpoints, "L1 rpc endpoints in the order of preference")
+	runCmd.Flags().DurationVar(
+		&cfg.L1.PollInterval, "l1-poll-interval", cfg.L1.PollInterval, "interval of fetching the latest L1 block")
+	runCmd.Flags().Uint64Var(
+		&cfg.L1.Confirmations,
+		"l1-confirmations",
+		cfg.L1.Confirmations,
+		"number of L1 blocks built on top of the block before it is used")
+	runCmd.Flags().StringVar(
+		&cfg.L1.HeadersFile, "l1-headers-file", cfg.L1.HeadersFile, "JSON file with L1 headers for the file backend")
+	runCmd.Flags().DurationVar(
+		&cfg.L1.SimulatedBlockTime,
+		"l1-simulated-block-time",
+		cfg.L1.SimulatedBlockTime,
+		"block time of the simulated L1 chain")
 
 	addBasicFlags(runCmd.Flags(), cfg)
 	cmdflags.AddNetwork(runCmd.Flags(), cfg.Network)
@@ -205,10 +326,37 @@ func parseArgs() *nildconfig.Config {
 	cmdflags.AddNetwork(rpcCmd.Flags(), cfg.Network)
 	cmdflags.AddTelemetry(rpcCmd.Flags(), cfg.Telemetry)
 
+	exportChainCmd := &cobra.Command{
+		Use:   "export-chain",
+		Short: "Export blocks of the local database into a chain data file",
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg.RunMode = nilservice.ExportChainRunMode
+		},
+	}
+	exportChainCmd.Flags().UintSliceVar(
+		&cfg.ChainData.Shards, "shards", cfg.ChainData.Shards, "export only specified shard(s)")
+	exportChainCmd.Flags().Var(&cfg.ChainData.FirstBlock, "first-block", "first block id to export")
+	exportChainCmd.Flags().Var(
+		&cfg.ChainData.LastBlock, "last-block", "last block id to export, the latest block by default")
+	addChainDataFlags(exportChainCmd.Flags(), cfg)
+
+	importChainCmd := &cobra.Command{
+		Use:   "import-chain",
+		Short: "Import blocks from a chain data file, every block is re-executed and validated",
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg.RunMode = nilservice.ImportChainRunMode
+		},
+	}
+	importChainCmd.Flags().Uint32Var(
+		&cfg.CollatorTickPeriodMs, "collator-tick-ms", cfg.CollatorTickPeriodMs, "collator tick period in milliseconds")
+	addAllowDbClearFlag(importChainCmd.Flags(), cfg)
+	addChainDataFlags(importChainCmd.Flags(), cfg)
+
 	versionCmd := cobrax.VersionCmd(appTitle)
 	devnetCmd := DevnetCommand()
 
-	rootCmd.AddCommand(runCmd, replayCmd, archiveCmd, rpcCmd, devnetCmd, versionCmd)
+	rootCmd.AddCommand(
+		runCmd, replayCmd, archiveCmd, rpcCmd, exportChainCmd, importChainCmd, devnetCmd, versionCmd)
 	cobrax.ExitOnHelp(rootCmd)
 
 	check.PanicIfErr(rootCmd.Execute())
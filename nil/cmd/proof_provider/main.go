@@ -100,6 +100,12 @@ func addFlags(cmd *cobra.Command, cfg *cmdConfig) {
 		cfg.MaxConcurrentBatches,
 		"maximum value of batches that proof provider can handle concurrently",
 	)
+	cmd.Flags().Uint32Var(
+		&cfg.TaskArchiverConfig.RetainedBatches,
+		"task-retained-batches",
+		cfg.TaskArchiverConfig.RetainedBatches,
+		"number of the most recent batches whose task trees are kept in the task tables, "+
+			"older ones are moved into the task archive (0 disables archival)")
 	logLevel := cmd.Flags().String(
 		"log-level",
 		"info",
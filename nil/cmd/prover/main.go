@@ -59,6 +59,21 @@ func execute() error {
 	commonCfg := runConfig.CommonConfig
 	addCommonFlags(rootCmd, commonCfg)
 	runCmd.Flags().StringVar(&runConfig.DbPath, "db-path", runConfig.DbPath, "path to database")
+	runCmd.Flags().StringSliceVar(
+		&runConfig.TaskTypes,
+		"task-types",
+		runConfig.TaskTypes,
+		"task types the prover is able to handle, all types are accepted if not set")
+	runCmd.Flags().StringSliceVar(
+		&runConfig.CircuitTypes,
+		"circuit-types",
+		runConfig.CircuitTypes,
+		"circuit types the prover is able to handle, all circuits are accepted if not set")
+	runCmd.Flags().StringVar(
+		&runConfig.MemoryClass,
+		"memory-class",
+		runConfig.MemoryClass,
+		"memory class of the prover host: Low|Medium|High, any task is accepted if not set")
 
 	traceConfig := tracer.TraceConfig{}
 	var marshalModePlaceholder string
@@ -149,6 +164,9 @@ func run(cfg *RunConfig) error {
 	serviceConfig := prover.Config{
 		NilRpcEndpoint:           cfg.NilRpcEndpoint,
 		ProofProviderRpcEndpoint: cfg.ProofProviderRpcEndpoint,
+		TaskTypes:                cfg.TaskTypes,
+		CircuitTypes:             cfg.CircuitTypes,
+		MemoryClass:              cfg.MemoryClass,
 	}
 
 	database, err := db.NewBadgerDb(cfg.DbPath)
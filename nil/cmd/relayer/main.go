@@ -162,6 +162,44 @@ func addRunCommandFlags(runCmd *cobra.Command, cfg *Config) error {
 		"Poll interval for L2 transaction sender",
 	)
 
+	runCmd.Flags().IntVar(
+		&cfg.TransactionSenderConfig.MaxRelayAttempts,
+		"l2-max-relay-attempts",
+		cfg.TransactionSenderConfig.MaxRelayAttempts,
+		"Number of failed attempts to relay an event to L2 before moving it to the dead-letter queue",
+	)
+	runCmd.Flags().DurationVar(
+		&cfg.TransactionSenderConfig.ReceiptTimeout,
+		"l2-receipt-timeout",
+		cfg.TransactionSenderConfig.ReceiptTimeout,
+		"Max time to wait for L2 receipt of relay transaction before sending it again",
+	)
+	runCmd.Flags().DurationVar(
+		&cfg.TransactionSenderConfig.MaxSendBackoff,
+		"l2-max-send-backoff",
+		cfg.TransactionSenderConfig.MaxSendBackoff,
+		"Max delay between retries of relay transactions failed to be sent to L2",
+	)
+	runCmd.Flags().StringVar(
+		&cfg.StatusServerConfig.HttpEndpoint,
+		"status-endpoint",
+		"",
+		"HTTP endpoint for relayer status JSON-RPC API (e.g. tcp://127.0.0.1:8531), disabled if empty",
+	)
+	runCmd.Flags().StringVar(
+		&cfg.StatusServerConfig.AdminEndpoint,
+		"admin-endpoint",
+		"",
+		"Endpoint for relayer admin JSON-RPC API to requeue or drop dead-letter events "+
+			"(e.g. unix:///var/run/relayer-admin.sock), must not be exposed publicly, disabled if empty",
+	)
+	runCmd.Flags().DurationVar(
+		&cfg.StatusPrunerConfig.RetentionPeriod,
+		"status-retention-period",
+		cfg.StatusPrunerConfig.RetentionPeriod,
+		"Time to keep the statuses of executed, orphaned and dropped events for, 0 keeps them forever",
+	)
+
 	// L2 debug mode flags
 	runCmd.Flags().BoolVar(&cfg.L2ContractConfig.DebugMode,
 		"l2-debug-mode", false, "Enable debug mode for L2 transaction sender",
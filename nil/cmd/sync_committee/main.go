@@ -81,6 +81,12 @@ func addFlags(cmd *cobra.Command, cfg *cmdConfig) {
 		"polling-delay",
 		cfg.AggregatorConfig.RpcPollingInterval,
 		"delay between new block polling")
+	cmd.Flags().StringVar(
+		&cfg.AggregatorConfig.BatchEncoding,
+		"batch-encoding",
+		cfg.AggregatorConfig.BatchEncoding,
+		"encoding of the batches published to L1: v1 (transactions), v2 (state diffs) "+
+			"or v3 (re-executable transactions, the only one supported by state reconstruction)")
 	cmd.Flags().StringVar(
 		&cfg.DbPath,
 		"db-path",
@@ -111,6 +117,27 @@ func addFlags(cmd *cobra.Command, cfg *cmdConfig) {
 		"disable-l1",
 		cfg.ContractWrapperConfig.DisableL1,
 		"Disable send trancations to L1")
+	cmd.Flags().StringVar(
+		(*string)(&cfg.DAConfig.Backend),
+		"da-backend",
+		string(cfg.DAConfig.Backend),
+		"data availability backend: l1-blob|l1-calldata|local")
+	cmd.Flags().Uint64Var(
+		&cfg.DAConfig.CalldataFallbackBlobFee,
+		"da-calldata-fallback-blob-fee",
+		cfg.DAConfig.CalldataFallbackBlobFee,
+		"blob base fee (wei) above which l1-blob backend publishes calldata instead, 0 disables the fallback")
+	cmd.Flags().StringVar(
+		&cfg.DAConfig.LocalDir,
+		"da-local-dir",
+		cfg.DAConfig.LocalDir,
+		"directory the local data availability backend stores batches in")
+	cmd.Flags().Uint32Var(
+		&cfg.TaskArchiverConfig.RetainedBatches,
+		"task-retained-batches",
+		cfg.TaskArchiverConfig.RetainedBatches,
+		"number of the most recent batches whose task trees are kept in the task tables, "+
+			"older ones are moved into the task archive (0 disables archival)")
 	logLevel := cmd.Flags().String(
 		"log-level",
 		"info",
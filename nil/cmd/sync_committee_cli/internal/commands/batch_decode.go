@@ -10,6 +10,8 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
 	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
+	v2 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v2"
+	v3 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v3"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
 )
 
@@ -34,6 +36,8 @@ func initDecoders(logger logging.Logger) {
 	decoderLoader.Do(func() {
 		knownDecoders = append(knownDecoders,
 			v1.NewDecoder(logger),
+			v2.NewDecoder(logger),
+			v3.NewDecoder(logger),
 			// each new implemented decoder needs to be added here
 		)
 	})
@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatJson ExportFormat = "json"
+	ExportFormatCsv  ExportFormat = "csv"
+)
+
+var ExportFormats = map[string]ExportFormat{
+	string(ExportFormatJson): ExportFormatJson,
+	string(ExportFormatCsv):  ExportFormatCsv,
+}
+
+func (f *ExportFormat) String() string {
+	return string(*f)
+}
+
+func (f *ExportFormat) Set(str string) error {
+	value, ok := ExportFormats[str]
+	if !ok {
+		return fmt.Errorf("unknown export format: %s", str)
+	}
+	*f = value
+	return nil
+}
+
+func (*ExportFormat) Type() string {
+	return "ExportFormat"
+}
+
+func (*ExportFormat) PossibleValues() []string {
+	return slices.Sorted(maps.Keys(ExportFormats))
+}
+
+type ExportArchiveParams struct {
+	ExecutorParams
+	public.TaskArchiveRequest
+	Format     ExportFormat
+	OutputFile string
+}
+
+func (p *ExportArchiveParams) Validate() error {
+	if err := p.ExecutorParams.Validate(); err != nil {
+		return err
+	}
+	if err := p.TaskArchiveRequest.Validate(); err != nil {
+		return err
+	}
+	if _, ok := ExportFormats[string(p.Format)]; !ok {
+		return fmt.Errorf("unknown export format: %s", p.Format)
+	}
+	return nil
+}
+
+func (p *ExportArchiveParams) GetExecutorParams() *ExecutorParams {
+	return &p.ExecutorParams
+}
+
+func ExportArchive(ctx context.Context, params *ExportArchiveParams, api public.TaskDebugApi) (CmdOutput, error) {
+	trees, err := api.GetArchivedTasks(ctx, &params.TaskArchiveRequest)
+	if err != nil {
+		return EmptyOutput, fmt.Errorf("failed to get archived tasks from debug API: %w", err)
+	}
+	if len(trees) == 0 {
+		return EmptyOutput, fmt.Errorf("%w: no archived tasks satisfying the request were found", ErrNoDataFound)
+	}
+
+	switch params.Format {
+	case ExportFormatCsv:
+		return archiveToCsv(trees)
+	default:
+		data, err := json.MarshalIndent(trees, "", "  ")
+		if err != nil {
+			return EmptyOutput, err
+		}
+		return string(data) + "\n", nil
+	}
+}
+
+var archiveCsvHeader = []string{
+	"BatchId", "Id", "ParentTaskId", "Type", "CircuitType", "Dependencies",
+	"Owner", "Status", "RetryCount", "ErrorText", "CreatedAt", "StartedAt", "FinishedAt",
+}
+
+// archiveToCsv writes one row per archived task, the tree structure is kept by the dependency ids
+func archiveToCsv(trees []*public.ArchivedTaskTree) (CmdOutput, error) {
+	var builder strings.Builder
+	writer := csv.NewWriter(&builder)
+	if err := writer.Write(archiveCsvHeader); err != nil {
+		return EmptyOutput, err
+	}
+
+	optionalTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format(timeFormat)
+	}
+
+	for _, tree := range trees {
+		for _, task := range tree.Tasks {
+			var parentTaskId string
+			if task.ParentTaskId != nil {
+				parentTaskId = task.ParentTaskId.String()
+			}
+			dependencies := make([]string, 0, len(task.Dependencies))
+			for _, dependency := range task.Dependencies {
+				dependencies = append(dependencies, dependency.String())
+			}
+
+			row := []string{
+				tree.BatchId.String(),
+				task.Id.String(),
+				parentTaskId,
+				task.TaskType.String(),
+				task.CircuitType.String(),
+				strings.Join(dependencies, " "),
+				task.Owner.String(),
+				task.Status.String(),
+				strconv.Itoa(task.RetryCount),
+				task.ErrorText,
+				task.Created.Format(timeFormat),
+				optionalTime(task.Started),
+				optionalTime(task.Finished),
+			}
+			if err := writer.Write(row); err != nil {
+				return EmptyOutput, err
+			}
+		}
+	}
+
+	writer.Flush()
+	return builder.String(), writer.Error()
+}
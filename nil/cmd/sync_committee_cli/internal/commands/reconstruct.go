@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/reconstruct"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrStateDiverged = errors.New("reconstructed state diverged from the recorded one")
+
+type ReconstructParams struct {
+	NShards       uint32
+	ZeroStateFile string
+
+	// BatchDir is the directory of the local data availability backend,
+	// the batches are loaded from it alone if L1 is not set
+	BatchDir string
+
+	// the batches are loaded from the data availability backends of the batches committed to L1,
+	// and the reconstructed blocks are checked against the finalized state roots, if set
+	L1Endpoint           string
+	L1ContractAddressHex string
+	L1FromBlock          uint64
+	BeaconEndpoint       string
+}
+
+func DefaultReconstructParams() *ReconstructParams {
+	return &ReconstructParams{}
+}
+
+func Reconstruct(ctx context.Context, params *ReconstructParams, out io.Writer, logger logging.Logger) error {
+	zeroState, err := loadZeroState(params.ZeroStateFile)
+	if err != nil {
+		return fmt.Errorf("failed to load zero state config: %w", err)
+	}
+
+	var (
+		source reconstruct.BatchSource
+		l1     reconstruct.StateRootReader
+	)
+	switch {
+	case params.L1Endpoint != "":
+		l1Source, err := newL1Source(ctx, params, logger)
+		if err != nil {
+			return err
+		}
+		source, l1 = l1Source, l1Source
+	case params.BatchDir != "":
+		source = reconstruct.NewDirSource(params.BatchDir, logger)
+	default:
+		return errors.New("either L1 endpoint or batch directory is required")
+	}
+
+	batches, err := source.Batches(ctx)
+	if err != nil {
+		return err
+	}
+	logger.Info().Int("batches", len(batches)).Msg("loaded published batches")
+
+	reconstructor, err := reconstruct.New(params.NShards, zeroState, l1, logger)
+	if err != nil {
+		return err
+	}
+	report, err := reconstructor.Run(ctx, batches)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	if report.Divergence != nil {
+		return fmt.Errorf("%w: %s", ErrStateDiverged, report.Divergence)
+	}
+	return nil
+}
+
+func newL1Source(
+	ctx context.Context, params *ReconstructParams, logger logging.Logger,
+) (*reconstruct.L1Source, error) {
+	if !ethcommon.IsHexAddress(params.L1ContractAddressHex) {
+		return nil, fmt.Errorf("invalid L1 contract address %q", params.L1ContractAddressHex)
+	}
+	client, err := ethclient.DialContext(ctx, params.L1Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to L1: %w", err)
+	}
+
+	config := reconstruct.NewDefaultL1SourceConfig()
+	config.ContractAddress = ethcommon.HexToAddress(params.L1ContractAddressHex)
+	config.FromBlock = params.L1FromBlock
+	config.BeaconEndpoint = params.BeaconEndpoint
+	config.LocalDir = params.BatchDir
+	return reconstruct.NewL1Source(client, config, logger)
+}
+
+// loadZeroState reads the zero state config in the format of the `zeroState` section of the nild config,
+// the nild default is used if the file is not specified.
+func loadZeroState(file string) (*execution.ZeroStateConfig, error) {
+	if file == "" {
+		return execution.CreateDefaultZeroStateConfig(nil)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var zeroState execution.ZeroStateConfig
+	if err := yaml.Unmarshal(data, &zeroState); err != nil {
+		return nil, err
+	}
+	return &zeroState, nil
+}
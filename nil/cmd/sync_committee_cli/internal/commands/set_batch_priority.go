@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+)
+
+type SetBatchPriorityParams struct {
+	ExecutorParams
+	public.SetBatchPriorityRequest
+}
+
+func (p *SetBatchPriorityParams) GetExecutorParams() *ExecutorParams {
+	return &p.ExecutorParams
+}
+
+func SetBatchPriority(
+	ctx context.Context,
+	params *SetBatchPriorityParams,
+	api public.TaskDebugApi,
+) (CmdOutput, error) {
+	updated, err := api.SetBatchPriority(ctx, &params.SetBatchPriorityRequest)
+	if err != nil {
+		return EmptyOutput, fmt.Errorf("failed to set batch priority via debug API: %w", err)
+	}
+	if updated == 0 {
+		return EmptyOutput, fmt.Errorf("%w: no tasks of batch with id=%s were found", ErrNoDataFound, params.BatchId)
+	}
+
+	return fmt.Sprintf("Priority %s is set for %d tasks of batch %s\n", params.Priority, updated, params.BatchId), nil
+}
@@ -37,8 +37,9 @@ var TaskViewFields = map[TaskField]struct {
 		}
 		return emptyCell
 	}, true},
-	"Owner":  {func(task *public.TaskView) string { return task.Owner.String() }, true},
-	"Status": {func(task *public.TaskView) string { return task.Status.String() }, true},
+	"Owner":    {func(task *public.TaskView) string { return task.Owner.String() }, true},
+	"Status":   {func(task *public.TaskView) string { return task.Status.String() }, true},
+	"Priority": {func(task *public.TaskView) string { return task.Priority.String() }, false},
 }
 
 func AllFields() []TaskField {
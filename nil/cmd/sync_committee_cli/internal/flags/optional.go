@@ -0,0 +1,57 @@
+package flags
+
+import (
+	"time"
+
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+)
+
+// TimeFlag sets an optional time value in RFC3339 format.
+type TimeFlag struct {
+	Value **time.Time
+}
+
+func (f TimeFlag) String() string {
+	if *f.Value == nil {
+		return ""
+	}
+	return (*f.Value).Format(time.RFC3339)
+}
+
+func (f TimeFlag) Set(str string) error {
+	value, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		return err
+	}
+	*f.Value = &value
+	return nil
+}
+
+func (TimeFlag) Type() string {
+	return "RFC3339Time"
+}
+
+// BatchIdFlag sets an optional batch id.
+type BatchIdFlag struct {
+	Value **public.BatchId
+}
+
+func (f BatchIdFlag) String() string {
+	if *f.Value == nil {
+		return ""
+	}
+	return (*f.Value).String()
+}
+
+func (f BatchIdFlag) Set(str string) error {
+	var batchId public.BatchId
+	if err := batchId.Set(str); err != nil {
+		return err
+	}
+	*f.Value = &batchId
+	return nil
+}
+
+func (BatchIdFlag) Type() string {
+	return "BatchId"
+}
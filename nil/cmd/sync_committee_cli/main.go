@@ -43,9 +43,28 @@ func execute() error {
 		return err
 	}
 
+	setBatchPriorityCmd, err := buildSetBatchPriorityCmd(executorParams, logger)
+	if err != nil {
+		return err
+	}
+
+	exportArchiveCmd := buildExportArchiveCmd(executorParams, logger)
+
 	decodeBatchCmd := buildDecodeBatchCmd(executorParams, logger)
+	reconstructCmd, err := buildReconstructCmd(logger)
+	if err != nil {
+		return err
+	}
 	versionCmd := cobrax.VersionCmd(appTitle)
-	rootCmd.AddCommand(getTaskTreeCmd, decodeBatchCmd, resetContractCmd, versionCmd)
+	rootCmd.AddCommand(
+		getTaskTreeCmd,
+		setBatchPriorityCmd,
+		exportArchiveCmd,
+		decodeBatchCmd,
+		reconstructCmd,
+		resetContractCmd,
+		versionCmd,
+	)
 	return rootCmd.Execute()
 }
 
@@ -118,6 +137,84 @@ func buildGetTaskTreeCmd(commonParam *commands.ExecutorParams, logger logging.Lo
 	return cmd, nil
 }
 
+func buildSetBatchPriorityCmd(commonParam *commands.ExecutorParams, logger logging.Logger) (*cobra.Command, error) {
+	cmdParams := &commands.SetBatchPriorityParams{
+		ExecutorParams: *commonParam,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "set-batch-priority",
+		Short: "Set priority class of the batch tasks, tasks of higher classes are executed first",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commands.NewExecutor(os.Stdout, cmdParams, logger).Run(commands.SetBatchPriority)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&cmdParams.DebugRpcEndpoint, "endpoint", cmdParams.DebugRpcEndpoint, "debug rpc endpoint")
+
+	const batchIdFlag = "batch-id"
+	cmd.Flags().Var(&cmdParams.BatchId, batchIdFlag, "id of the batch")
+	const priorityFlag = "priority"
+	flags.EnumVar(cmd.Flags(), &cmdParams.Priority, priorityFlag, "priority class of the batch")
+
+	for _, flagId := range []string{batchIdFlag, priorityFlag} {
+		if err := cmd.MarkFlagRequired(flagId); err != nil {
+			return nil, err
+		}
+	}
+
+	return cmd, nil
+}
+
+func buildExportArchiveCmd(commonParam *commands.ExecutorParams, logger logging.Logger) *cobra.Command {
+	cmdParams := &commands.ExportArchiveParams{
+		ExecutorParams:     *commonParam,
+		TaskArchiveRequest: public.DefaultTaskArchiveRequest(),
+		Format:             commands.ExportFormatJson,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "export-archive",
+		Short: "Export archived task trees matching the filter for offline analysis",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output := os.Stdout
+			if cmdParams.OutputFile != "" {
+				file, err := os.Create(cmdParams.OutputFile)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				output = file
+			}
+			return commands.NewExecutor(output, cmdParams, logger).Run(commands.ExportArchive)
+		},
+	}
+
+	cmdFlags := cmd.Flags()
+	cmdFlags.StringVar(
+		&cmdParams.DebugRpcEndpoint, "endpoint", cmdParams.DebugRpcEndpoint, "debug rpc endpoint")
+
+	cmdFlags.Var(flags.BatchIdFlag{Value: &cmdParams.BatchId}, "batch-id", "id of the archived batch")
+	cmdFlags.Var(flags.TimeFlag{Value: &cmdParams.From}, "from", "start of the task update time range (RFC3339)")
+	cmdFlags.Var(flags.TimeFlag{Value: &cmdParams.To}, "to", "end of the task update time range (RFC3339)")
+	cmdFlags.Var(&cmdParams.Owner, "owner", "id of the executor of any task of the tree")
+	cmdFlags.IntVar(
+		&cmdParams.Limit,
+		"limit",
+		cmdParams.Limit,
+		fmt.Sprintf(
+			"limit the number of task trees returned, should be in range [%d, %d]",
+			public.TaskArchiveMinLimit, public.TaskArchiveMaxLimit,
+		),
+	)
+
+	flags.EnumVar(cmdFlags, &cmdParams.Format, "format", "output format")
+	cmdFlags.StringVar(&cmdParams.OutputFile, "output-file", "", "target file, stdout is used if not set")
+
+	return cmd
+}
+
 func buildDecodeBatchCmd(_ *commands.ExecutorParams, logger logging.Logger) *cobra.Command {
 	params := &commands.DecodeBatchParams{}
 
@@ -140,6 +237,64 @@ func buildDecodeBatchCmd(_ *commands.ExecutorParams, logger logging.Logger) *cob
 	return cmd
 }
 
+func buildReconstructCmd(logger logging.Logger) (*cobra.Command, error) {
+	params := commands.DefaultReconstructParams()
+
+	cmd := &cobra.Command{
+		Use:   "reconstruct",
+		Short: "Rebuild L2 state from the published batches and report the first divergence from the recorded roots",
+		Long: "Rebuild L2 state from the published batches and report the first divergence from the recorded roots.\n" +
+			"Only the batches published with the v3 batch encoding can be re-executed, v1 and v2 batches are rejected.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commands.Reconstruct(context.Background(), params, os.Stdout, logger)
+		},
+	}
+
+	nShardsFlag := "nshards"
+	cmd.Flags().Uint32Var(
+		&params.NShards,
+		nShardsFlag,
+		params.NShards,
+		"number of shardchains of the network")
+	cmd.Flags().StringVar(
+		&params.ZeroStateFile,
+		"zero-state",
+		params.ZeroStateFile,
+		"yaml file with the zero state config of the network, the nild default is used if not set")
+	cmd.Flags().StringVar(
+		&params.BatchDir,
+		"batch-dir",
+		params.BatchDir,
+		"directory with the batches published by the local data availability backend, "+
+			"the batches are loaded from it alone if the L1 endpoint is not set")
+	cmd.Flags().StringVar(
+		&params.L1Endpoint,
+		"l1-endpoint",
+		params.L1Endpoint,
+		"L1 endpoint, the committed batches are loaded and checked against the finalized state roots if set")
+	cmd.Flags().StringVar(
+		&params.L1ContractAddressHex,
+		"l1-contract-address",
+		params.L1ContractAddressHex,
+		"L1 update state contract address")
+	cmd.Flags().Uint64Var(
+		&params.L1FromBlock,
+		"l1-from-block",
+		params.L1FromBlock,
+		"L1 block the committed batches are searched from")
+	cmd.Flags().StringVar(
+		&params.BeaconEndpoint,
+		"beacon-endpoint",
+		params.BeaconEndpoint,
+		"L1 beacon API endpoint, required for the batches published as blobs")
+
+	if err := cmd.MarkFlagRequired(nShardsFlag); err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
 func buildResetContractCmd(_ *commands.ExecutorParams, logger logging.Logger) (*cobra.Command, error) {
 	params := &commands.ResetStateParams{}
 
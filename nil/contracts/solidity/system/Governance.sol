@@ -25,6 +25,26 @@ contract Governance is NilBase {
         );
     }
 
+    function addValidator(
+        uint32 shardId,
+        bytes memory validatorPubkey,
+        address withdrawalAddress,
+        uint64 weight
+    ) external onlyExternal {
+        Nil.scheduleValidatorChange(shardId, false, validatorPubkey, withdrawalAddress, weight);
+    }
+
+    function removeValidator(
+        uint32 shardId,
+        bytes memory validatorPubkey
+    ) external onlyExternal {
+        Nil.scheduleValidatorChange(shardId, true, validatorPubkey, address(0), 0);
+    }
+
+    function reportDoubleSign(bytes memory evidence) external onlyExternal {
+        Nil.reportDoubleSign(evidence);
+    }
+
     bytes pubkey;
 
     constructor(bytes memory _pubkey) payable {
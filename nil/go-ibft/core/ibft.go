@@ -12,9 +12,13 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
-var meter = otel.Meter("go-ibft")
+var (
+	meter  = otel.Meter("go-ibft")
+	tracer = otel.Tracer("go-ibft")
+)
 
 // Logger represents the logger behaviour
 type Logger interface {
@@ -391,6 +395,12 @@ func (i *IBFT) RunSequence(ctx context.Context, h uint64) {
 
 		currentRound := view.GetRound()
 		ctxRound, cancelRound := context.WithCancel(ctx)
+		ctxRound, roundSpan := tracer.Start(ctxRound, "ibft.Round", trace.WithAttributes(
+			append([]attribute.KeyValue{
+				attribute.Int64("height", int64(h)),
+				attribute.Int64("round", int64(currentRound)),
+			}, i.metricAttrs...)...,
+		))
 
 		i.wg.Add(4)
 
@@ -406,14 +416,16 @@ func (i *IBFT) RunSequence(ctx context.Context, h uint64) {
 		// Start the state machine worker
 		go i.startRound(ctxRound)
 
-		teardown := func() {
+		teardown := func(outcome string) {
 			cancelRound()
 			i.wg.Wait()
+			roundSpan.SetAttributes(attribute.String("outcome", outcome))
+			roundSpan.End()
 		}
 
 		select {
 		case ev := <-i.newProposal:
-			teardown()
+			teardown("future proposal")
 			i.log.Info("received future proposal", "round", ev.round)
 
 			i.moveToNewRound(ev.round)
@@ -421,12 +433,12 @@ func (i *IBFT) RunSequence(ctx context.Context, h uint64) {
 			i.state.setRoundStarted(true)
 			i.sendPrepareMessage(view)
 		case round := <-i.roundCertificate:
-			teardown()
+			teardown("round change certificate")
 			i.log.Info("received future RCC", "round", round)
 
 			i.moveToNewRound(round)
 		case <-i.roundExpired:
-			teardown()
+			teardown("timeout")
 			i.log.Info("round timeout expired", "round", currentRound)
 
 			newRound := currentRound + 1
@@ -436,12 +448,12 @@ func (i *IBFT) RunSequence(ctx context.Context, h uint64) {
 		case <-i.roundDone:
 			// The consensus cycle for the block height is finished.
 			// Stop all running worker threads
-			teardown()
+			teardown("done")
 			i.insertBlock()
 
 			return
 		case <-ctxRound.Done():
-			teardown()
+			teardown("cancelled")
 			i.log.Debug("sequence cancelled")
 
 			return
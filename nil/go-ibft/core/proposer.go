@@ -0,0 +1,69 @@
+package core
+
+import (
+	"errors"
+	"math"
+)
+
+var errProposerScheduleTooLong = errors.New("total voting power is too large for the proposer schedule")
+
+// MaxProposerScheduleLength limits the total voting power (divided by the GCD of the powers)
+// that the proposer schedule can be built for.
+const MaxProposerScheduleLength = 1 << 22
+
+// ProposerSchedule returns the order in which the validators with the given voting powers propose.
+//
+// It is the Tendermint proposer-priority algorithm started from zero priorities:
+// at every step the priority of each validator is increased by its voting power,
+// the validator with the highest priority (the lowest index on ties) proposes,
+// and its priority is decreased by the total voting power.
+// The priorities return to zero after the total voting power steps, so the returned schedule
+// repeats with that period, and every validator appears in it as many times as its voting power.
+// With equal voting powers it is a plain round-robin over the validators.
+//
+// The schedule depends only on the voting powers and their order, so it is the same on all nodes.
+func ProposerSchedule(votingPowers []uint64) ([]int, error) {
+	var divisor uint64
+	for _, power := range votingPowers {
+		divisor = gcd(divisor, power)
+	}
+	if divisor == 0 {
+		return nil, errVotingPowerNotCorrect
+	}
+
+	powers := make([]int64, len(votingPowers))
+	var total uint64
+	for i, power := range votingPowers {
+		power /= divisor
+		if power > math.MaxInt64-total {
+			return nil, errProposerScheduleTooLong
+		}
+		powers[i] = int64(power)
+		total += power
+	}
+	if total > MaxProposerScheduleLength {
+		return nil, errProposerScheduleTooLong
+	}
+
+	schedule := make([]int, total)
+	priorities := make([]int64, len(powers))
+	for step := range schedule {
+		proposer := 0
+		for i, power := range powers {
+			priorities[i] += power
+			if priorities[i] > priorities[proposer] {
+				proposer = i
+			}
+		}
+		priorities[proposer] -= int64(total)
+		schedule[step] = proposer
+	}
+	return schedule, nil
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
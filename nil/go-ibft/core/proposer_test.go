@@ -0,0 +1,91 @@
+package core
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ProposerSchedule(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		votingPowers []uint64
+		schedule     []int
+	}{
+		{
+			// equal voting powers, plain round-robin
+			votingPowers: []uint64{1, 1, 1, 1},
+			schedule:     []int{0, 1, 2, 3},
+		},
+		{
+			// equal voting powers are normalized
+			votingPowers: []uint64{7, 7, 7},
+			schedule:     []int{0, 1, 2},
+		},
+		{
+			// the heavy validator proposes in between the others
+			votingPowers: []uint64{1, 1, 1, 5},
+			schedule:     []int{3, 0, 3, 1, 3, 2, 3, 3},
+		},
+		{
+			votingPowers: []uint64{2, 4, 6},
+			schedule:     []int{2, 1, 0, 2, 1, 2},
+		},
+		{
+			// validators without voting power never propose
+			votingPowers: []uint64{0, 1, 2},
+			schedule:     []int{2, 1, 2},
+		},
+	}
+
+	for _, c := range cases {
+		schedule, err := ProposerSchedule(c.votingPowers)
+		require.NoError(t, err)
+		require.Equal(t, c.schedule, schedule, "voting powers: %v", c.votingPowers)
+	}
+}
+
+func Test_ProposerScheduleFairness(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(0)) //nolint:gosec
+
+	for range 100 {
+		votingPowers := make([]uint64, 1+rnd.Intn(20))
+		for i := range votingPowers {
+			votingPowers[i] = uint64(1 + rnd.Intn(100))
+		}
+
+		schedule, err := ProposerSchedule(votingPowers)
+		require.NoError(t, err)
+
+		var divisor uint64
+		for _, power := range votingPowers {
+			divisor = gcd(divisor, power)
+		}
+
+		// Every validator proposes as many times as its (normalized) voting power.
+		counts := make([]uint64, len(votingPowers))
+		for _, proposer := range schedule {
+			counts[proposer]++
+		}
+		for i, power := range votingPowers {
+			require.Equal(t, power/divisor, counts[i], "voting powers: %v", votingPowers)
+		}
+	}
+}
+
+func Test_ProposerScheduleInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := ProposerSchedule(nil)
+	require.ErrorIs(t, err, errVotingPowerNotCorrect)
+
+	_, err = ProposerSchedule([]uint64{0, 0})
+	require.ErrorIs(t, err, errVotingPowerNotCorrect)
+
+	_, err = ProposerSchedule([]uint64{MaxProposerScheduleLength, 1})
+	require.ErrorIs(t, err, errProposerScheduleTooLong)
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/go-ibft/messages"
+	"github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWeightedCluster creates a cluster where the voting power of the nodes is given by weights
+// and the proposers are picked according to the weighted proposer schedule
+func newWeightedCluster(t *testing.T, weights []uint64, insertedBlocks [][]byte) *cluster {
+	t.Helper()
+
+	schedule, err := ProposerSchedule(weights)
+	require.NoError(t, err)
+
+	return newCluster(
+		uint64(len(weights)),
+		func(c *cluster) {
+			isProposer := func(from []byte, height, round uint64) bool {
+				proposer := schedule[(height+round)%uint64(len(schedule))]
+
+				return bytes.Equal(from, c.nodes[proposer].address)
+			}
+
+			getVotingPowers := func(uint64) (map[string]*big.Int, error) {
+				result := make(map[string]*big.Int, len(c.nodes))
+				for i, node := range c.nodes {
+					result[string(node.address)] = new(big.Int).SetUint64(weights[i])
+				}
+
+				return result, nil
+			}
+
+			for nodeIndex, node := range c.nodes {
+				currentNode := node
+				node.core = NewIBFT(
+					mockLogger{},
+					&mockBackend{
+						isValidProposalFn:     isValidProposal,
+						isValidProposalHashFn: isValidProposalHash,
+						isProposerFn:          isProposer,
+
+						idFn: node.addr,
+
+						buildProposalFn:           buildValidEthereumBlock,
+						buildPrePrepareMessageFn:  node.buildPrePrepare,
+						buildPrepareMessageFn:     node.buildPrepare,
+						buildCommitMessageFn:      node.buildCommit,
+						buildRoundChangeMessageFn: node.buildRoundChange,
+
+						insertProposalFn: func(proposal *proto.Proposal, _ []*messages.CommittedSeal) {
+							insertedBlocks[nodeIndex] = proposal.GetRawProposal()
+						},
+						getVotingPowerFn: getVotingPowers,
+					},
+					&mockTransport{multicastFn: func(message *proto.IbftMessage) {
+						if currentNode.offline {
+							return
+						}
+
+						c.gossip(message)
+					}},
+				)
+				node.core.baseRoundTimeout = 100 * time.Millisecond
+			}
+		},
+	)
+}
+
+// TestWeightedQuorum_HeavyMinority tests the following scenario:
+// N = 4, voting powers are 1, 1, 1 and 5 (quorum is 6)
+//
+// - The first two nodes are offline
+// - The remaining nodes have voting power of 6, which is enough to reach quorum,
+// although a count-based quorum would require 3 nodes
+func TestWeightedQuorum_HeavyMinority(t *testing.T) {
+	t.Parallel()
+
+	insertedBlocks := make([][]byte, 4)
+	cluster := newWeightedCluster(t, []uint64{1, 1, 1, 5}, insertedBlocks)
+
+	cluster.stopN(2)
+	require.NoError(t, cluster.progressToHeight(20*time.Second, 5))
+	assert.Equal(t, uint64(5), cluster.latestHeight)
+
+	assertNInsertedBlocks(t, 2, insertedBlocks)
+	assertValidInsertedBlocks(t, insertedBlocks[2:])
+}
+
+// TestWeightedQuorum_LightMajority tests the following scenario:
+// N = 4, voting powers are 1, 1, 1 and 5 (quorum is 6)
+//
+// - The heaviest node is offline
+// - The remaining nodes have voting power of 3, which is not enough to reach quorum,
+// although they would reach a count-based quorum
+func TestWeightedQuorum_LightMajority(t *testing.T) {
+	t.Parallel()
+
+	insertedBlocks := make([][]byte, 4)
+	cluster := newWeightedCluster(t, []uint64{1, 1, 1, 5}, insertedBlocks)
+
+	cluster.nodes[3].offline = true
+	require.Error(t, cluster.progressToHeight(2*time.Second, 1))
+	assertNInsertedBlocks(t, 0, insertedBlocks)
+}
+
+func Test_HasPrepareQuorumWeighted(t *testing.T) {
+	t.Parallel()
+
+	vm := NewValidatorManager(mockBackend{}, mockLogger{})
+	require.NoError(t, vm.setCurrentVotingPower(map[string]*big.Int{
+		"A": big.NewInt(1),
+		"B": big.NewInt(1),
+		"C": big.NewInt(1),
+		"D": big.NewInt(5),
+	}))
+
+	view := &proto.View{Height: 1, Round: 0}
+	proposal := buildBasicPreprepareMessage(validEthereumBlock, validProposalHash, nil, []byte("D"), view)
+	prepareFrom := func(from string) *proto.IbftMessage {
+		return buildBasicPrepareMessage(validProposalHash, []byte(from), view)
+	}
+
+	// The proposer's voting power is counted along with the prepare senders
+	assert.True(t, vm.HasPrepareQuorum(prepare, proposal, []*proto.IbftMessage{prepareFrom("A")}))
+	assert.False(t, vm.HasPrepareQuorum(prepare, proposal, nil))
+
+	// Without the heaviest validator there is no quorum even if all the others agree
+	lightProposal := buildBasicPreprepareMessage(validEthereumBlock, validProposalHash, nil, []byte("A"), view)
+	assert.False(t, vm.HasPrepareQuorum(prepare, lightProposal,
+		[]*proto.IbftMessage{prepareFrom("B"), prepareFrom("C")}))
+	assert.True(t, vm.HasPrepareQuorum(prepare, lightProposal, []*proto.IbftMessage{prepareFrom("D")}))
+}
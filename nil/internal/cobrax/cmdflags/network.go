@@ -16,6 +16,9 @@ func AddNetwork(fset *pflag.FlagSet, cfg *network.Config) {
 	fset.BoolVar(&cfg.ServeRelay, "serve-relay", cfg.ServeRelay, "enable relay")
 	fset.Var(&cfg.Relays, "relays", "relay peers")
 
+	fset.BoolVar(&cfg.PubSubTraceContext, "pubsub-trace-context", cfg.PubSubTraceContext,
+		"publish messages with the trace context (all nodes of the network must support it)")
+
 	fset.BoolVar(&cfg.DHTEnabled, "with-discovery", cfg.DHTEnabled, "enable discovery (with Kademlia DHT)")
 	fset.Var(&cfg.DHTBootstrapPeers, "discovery-bootstrap-peers", "bootstrap peers for discovery")
 	check.PanicIfErr(
@@ -12,4 +12,10 @@ func AddTelemetry(fset *pflag.FlagSet, config *telemetry.Config) {
 		"prometheus-port",
 		config.PrometheusPort,
 		"port to serve prometheus metrics; 0 to disable")
+	fset.BoolVar(&config.ExportTraces, "traces", config.ExportTraces, "export traces via grpc")
+	fset.Float64Var(
+		&config.TraceSampleRatio,
+		"trace-sample-ratio",
+		config.TraceSampleRatio,
+		"fraction of the traces to sample; 0 to sample all")
 }
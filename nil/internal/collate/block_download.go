@@ -0,0 +1,411 @@
+package collate
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	cerrors "github.com/NilFoundation/nil/nil/internal/collate/errors"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	cm "github.com/NilFoundation/nil/nil/internal/network/connection_manager"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+const (
+	// blocksRangeSize is the number of blocks requested from a single peer at once.
+	blocksRangeSize = 128
+	// rangesPerPeer is the maximum number of concurrent range requests to a single peer.
+	rangesPerPeer = 2
+	// maxPendingRanges limits the number of ranges being downloaded or waiting to be applied.
+	maxPendingRanges = 16
+
+	peerScoreSuccess = 1
+	peerScoreFailure = -10
+)
+
+var (
+	errSlowPeer           = errors.New("peer did not serve the blocks range in time")
+	errInvalidBlocksRange = errors.New("invalid blocks range")
+)
+
+// SyncProgress describes the progress of the catch-up of a shard.
+type SyncProgress struct {
+	// Syncing is set while the blocks are being downloaded; the other fields describe the last catch-up otherwise.
+	Syncing       bool
+	StartingBlock types.BlockNumber
+	CurrentBlock  types.BlockNumber
+	HighestBlock  types.BlockNumber
+	// Peers are the peers that served the blocks.
+	Peers []network.PeerID
+}
+
+type blocksRange struct {
+	from  types.BlockNumber
+	count uint64
+}
+
+func (r blocksRange) last() types.BlockNumber {
+	return r.from + types.BlockNumber(r.count) - 1
+}
+
+// rangeQueue generates the ranges up to the target on demand. The ranges to retry are served first.
+type rangeQueue struct {
+	retry  []blocksRange
+	from   types.BlockNumber
+	target types.BlockNumber
+	size   uint64
+}
+
+func (q *rangeQueue) peek() (blocksRange, bool) {
+	if len(q.retry) > 0 {
+		return q.retry[0], true
+	}
+	if q.from > q.target {
+		return blocksRange{}, false
+	}
+	return blocksRange{from: q.from, count: min(q.size, uint64(q.target-q.from)+1)}, true
+}
+
+func (q *rangeQueue) pop() {
+	if len(q.retry) > 0 {
+		q.retry = q.retry[1:]
+		return
+	}
+	q.from += types.BlockNumber(q.size)
+}
+
+func (q *rangeQueue) pushFront(r blocksRange) {
+	q.retry = append([]blocksRange{r}, q.retry...)
+}
+
+type blocksRangeResult struct {
+	blocksRange
+
+	peer   network.PeerID
+	blocks []*types.BlockWithExtractedData
+	err    error
+}
+
+// blockDownloader downloads the ranges of blocks from several peers concurrently
+// and applies them in order. The peers that fail to serve the ranges are penalized.
+type blockDownloader struct {
+	shardId        types.ShardId
+	networkManager network.Manager
+	logger         logging.Logger
+
+	rangeSize uint64
+	timeout   time.Duration
+
+	lastBlock func(ctx context.Context) (*types.Block, common.Hash, error)
+	apply     func(ctx context.Context, block *types.BlockWithExtractedData) error
+
+	// scores rank the peers by the ranges they served; they are only accessed by the downloading goroutine.
+	scores map[network.PeerID]int
+
+	mu       sync.Mutex
+	progress SyncProgress
+}
+
+func newBlockDownloader(
+	shardId types.ShardId,
+	nm network.Manager,
+	lastBlock func(ctx context.Context) (*types.Block, common.Hash, error),
+	apply func(ctx context.Context, block *types.BlockWithExtractedData) error,
+	logger logging.Logger,
+) *blockDownloader {
+	return &blockDownloader{
+		shardId:        shardId,
+		networkManager: nm,
+		logger:         logger,
+		rangeSize:      blocksRangeSize,
+		timeout:        requestTimeout,
+		lastBlock:      lastBlock,
+		apply:          apply,
+		scores:         make(map[network.PeerID]int),
+	}
+}
+
+func (d *blockDownloader) Progress() SyncProgress {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	progress := d.progress
+	progress.Peers = slices.Clone(d.progress.Peers)
+	return progress
+}
+
+func (d *blockDownloader) updateProgress(f func(p *SyncProgress)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	f(&d.progress)
+}
+
+// requestHeads requests the last block numbers of the peers. Peers that fail to respond are skipped.
+func (d *blockDownloader) requestHeads(
+	ctx context.Context, peers []network.PeerID,
+) map[network.PeerID]types.BlockNumber {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	heads := make(map[network.PeerID]types.BlockNumber, len(peers))
+	for _, peer := range peers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			head, err := RequestBlockHead(ctx, d.networkManager, peer, d.shardId)
+			if err != nil {
+				d.logger.Debug().Err(err).Stringer(logging.FieldPeerId, peer).Msg("Failed to request block head")
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			heads[peer] = head
+		}()
+	}
+	wg.Wait()
+	return heads
+}
+
+// confirmedHead returns the highest block that at least two peers have, so that a single peer
+// can't make the node chase a made-up head. The head of the only peer is taken as is.
+func confirmedHead(heads map[network.PeerID]types.BlockNumber) types.BlockNumber {
+	sorted := slices.SortedFunc(maps.Values(heads), func(a, b types.BlockNumber) int {
+		return cmp.Compare(b, a)
+	})
+	switch len(sorted) {
+	case 0:
+		return 0
+	case 1:
+		return sorted[0]
+	default:
+		return sorted[1]
+	}
+}
+
+// choosePeer returns the peer with the best score among the ones that have the whole range
+// and are not overloaded with requests.
+func (d *blockDownloader) choosePeer(
+	r blocksRange, heads map[network.PeerID]types.BlockNumber, inFlight map[network.PeerID]int,
+	failed map[network.PeerID]bool,
+) (network.PeerID, bool) {
+	var best network.PeerID
+	found := false
+	for peer, head := range heads {
+		if head < r.last() || failed[peer] || inFlight[peer] >= rangesPerPeer {
+			continue
+		}
+		if !found ||
+			d.scores[peer] > d.scores[best] ||
+			(d.scores[peer] == d.scores[best] && inFlight[peer] < inFlight[best]) {
+			best, found = peer, true
+		}
+	}
+	return best, found
+}
+
+func (d *blockDownloader) downloadRange(ctx context.Context, peer network.PeerID, r blocksRange) *blocksRangeResult {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	res := &blocksRangeResult{blocksRange: r, peer: peer}
+	blocksCh, err := RequestBlocksRange(ctx, d.networkManager, peer, d.shardId, r.from, r.count, d.logger)
+	if err != nil {
+		res.err = err
+		return res
+	}
+
+	for block := range blocksCh {
+		if len(res.blocks) > 0 && block.PrevBlock != res.blocks[len(res.blocks)-1].Hash(d.shardId) {
+			res.err = fmt.Errorf("%w: block %d is not linked to the previous one", errInvalidBlocksRange, block.Id)
+			return res
+		}
+		if block.Id != r.from+types.BlockNumber(len(res.blocks)) {
+			res.err = fmt.Errorf("%w: unexpected block %d", errInvalidBlocksRange, block.Id)
+			return res
+		}
+		res.blocks = append(res.blocks, block)
+		if uint64(len(res.blocks)) == r.count {
+			// Peers of older versions ignore the count and stream all the blocks.
+			return res
+		}
+	}
+
+	if ctx.Err() != nil {
+		res.err = errSlowPeer
+	} else {
+		res.err = fmt.Errorf("%w: got %d of %d blocks", errInvalidBlocksRange, len(res.blocks), r.count)
+	}
+	return res
+}
+
+// penalize lowers the score of the peer and reports it to the reputation tracker if the peer misbehaved.
+func (d *blockDownloader) penalize(peer network.PeerID, err error) {
+	d.scores[peer] += peerScoreFailure
+	d.logger.Warn().Err(err).Stringer(logging.FieldPeerId, peer).Msg("Failed to download blocks from peer")
+
+	tracker := network.TryGetPeerReputationTracker(d.networkManager)
+	if tracker == nil {
+		return
+	}
+	switch {
+	case errors.Is(err, errSlowPeer):
+		tracker.ReportPeer(peer, cm.ReputationChangeSlowResponse)
+	case errors.Is(err, errInvalidBlocksRange):
+		tracker.ReportPeer(peer, cm.ReputationChangeInvalidBlocksRange)
+	case errors.As(err, new(invalidSignatureError)):
+		tracker.ReportPeer(peer, cm.ReputationChangeInvalidBlockSignature)
+	}
+}
+
+// applyRange applies the downloaded blocks following the block with prevHash.
+// It returns the hash of the last applied block and the number of applied blocks.
+func (d *blockDownloader) applyRange(
+	ctx context.Context, res *blocksRangeResult, prevHash common.Hash,
+) (common.Hash, int, error) {
+	for i, block := range res.blocks {
+		if block.PrevBlock != prevHash {
+			return prevHash, i, fmt.Errorf(
+				"%w: block %d is not linked to the local chain", errInvalidBlocksRange, block.Id)
+		}
+		if err := d.apply(ctx, block); err != nil && !errors.Is(err, cerrors.ErrOldBlock) {
+			if errors.As(err, new(invalidBlockError)) {
+				// The peer served a block that differs from its replay, e.g., a correctly signed header
+				// with a tampered body.
+				err = fmt.Errorf("%w: %w", errInvalidBlocksRange, err)
+			}
+			return prevHash, i, err
+		}
+		prevHash = block.Hash(d.shardId)
+
+		d.updateProgress(func(p *SyncProgress) {
+			p.CurrentBlock = block.Id
+			if !slices.Contains(p.Peers, res.peer) {
+				p.Peers = append(p.Peers, res.peer)
+			}
+		})
+	}
+	return prevHash, len(res.blocks), nil
+}
+
+// catchUp downloads the blocks up to the head confirmed by the peers if the shard is behind it
+// by more than a single range. The remaining blocks are left for the sequential sync.
+func (d *blockDownloader) catchUp(ctx context.Context) error {
+	lastBlock, prevHash, err := d.lastBlock(ctx)
+	if err != nil {
+		return err
+	}
+	if lastBlock == nil {
+		return errors.New("no last block found")
+	}
+	next := lastBlock.Id + 1
+
+	heads := d.requestHeads(ctx, ListPeers(d.networkManager, d.shardId))
+	target := max(lastBlock.Id, confirmedHead(heads))
+	if target < next+types.BlockNumber(d.rangeSize) {
+		return nil
+	}
+
+	d.logger.Info().
+		Stringer(logging.FieldBlockNumber, lastBlock.Id).
+		Msgf("Downloading blocks up to %d from %d peers", target, len(heads))
+
+	d.updateProgress(func(p *SyncProgress) {
+		*p = SyncProgress{
+			Syncing:       true,
+			StartingBlock: lastBlock.Id,
+			CurrentBlock:  lastBlock.Id,
+			HighestBlock:  target,
+		}
+	})
+	defer d.updateProgress(func(p *SyncProgress) {
+		p.Syncing = false
+	})
+
+	queue := &rangeQueue{from: next, target: target, size: d.rangeSize}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The buffer holds all the ranges in flight, so the downloading goroutines never block.
+	results := make(chan *blocksRangeResult, maxPendingRanges)
+	pending := make(map[types.BlockNumber]*blocksRangeResult)
+	inFlight := make(map[network.PeerID]int)
+	failed := make(map[network.PeerID]bool)
+	requests := 0
+	for next <= target {
+		for requests+len(pending) < maxPendingRanges {
+			r, ok := queue.peek()
+			if !ok {
+				break
+			}
+			peer, ok := d.choosePeer(r, heads, inFlight, failed)
+			if !ok {
+				break
+			}
+			queue.pop()
+			inFlight[peer]++
+			requests++
+			go func() {
+				results <- d.downloadRange(ctx, peer, r)
+			}()
+		}
+		if requests == 0 {
+			r, _ := queue.peek()
+			return fmt.Errorf("no peers to download blocks from %d", r.from)
+		}
+
+		var res *blocksRangeResult
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res = <-results:
+		}
+		requests--
+		inFlight[res.peer]--
+
+		if res.err != nil {
+			failed[res.peer] = true
+			d.penalize(res.peer, res.err)
+			queue.pushFront(res.blocksRange)
+			continue
+		}
+		d.scores[res.peer] += peerScoreSuccess
+		pending[res.from] = res
+
+		for ready, ok := pending[next]; ok; ready, ok = pending[next] {
+			delete(pending, next)
+
+			var applied int
+			prevHash, applied, err = d.applyRange(ctx, ready, prevHash)
+			next += types.BlockNumber(applied)
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, errInvalidBlocksRange) && !errors.As(err, new(invalidSignatureError)) {
+				return err
+			}
+
+			// The rest of the range is downloaded from another peer.
+			failed[ready.peer] = true
+			d.penalize(ready.peer, err)
+			queue.pushFront(blocksRange{from: next, count: ready.count - uint64(applied)})
+		}
+	}
+
+	d.logger.Info().
+		Stringer(logging.FieldBlockNumber, target).
+		Msg("Downloaded blocks")
+	return nil
+}
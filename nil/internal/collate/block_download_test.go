@@ -0,0 +1,267 @@
+package collate
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/proto"
+)
+
+type BlockDownloadTestSuite struct {
+	suite.Suite
+
+	shardId types.ShardId
+	chain   []*types.Block
+	// applied are the blocks applied by the downloader, starting from the genesis block.
+	applied []*types.Block
+}
+
+func (s *BlockDownloadTestSuite) SetupTest() {
+	s.shardId = 1
+	s.chain = nil
+	s.applied = nil
+}
+
+func (s *BlockDownloadTestSuite) writeChain(database db.DB, n int, baseFee uint64) []*types.Block {
+	s.T().Helper()
+
+	return writeTestChain(s.T(), database, s.shardId, n, baseFee)
+}
+
+func (s *BlockDownloadTestSuite) newDownloader(nm network.Manager) *blockDownloader {
+	s.T().Helper()
+
+	lastBlock := func(context.Context) (*types.Block, common.Hash, error) {
+		block := s.applied[len(s.applied)-1]
+		return block, block.Hash(s.shardId), nil
+	}
+	apply := func(_ context.Context, block *types.BlockWithExtractedData) error {
+		s.Require().Equal(types.BlockNumber(len(s.applied)), block.Id)
+		s.applied = append(s.applied, block.Block)
+		return nil
+	}
+	d := newBlockDownloader(s.shardId, nm, lastBlock, apply, logging.NewLogger("block_download_test"))
+	d.rangeSize = 4
+	d.timeout = time.Second
+	return d
+}
+
+func (s *BlockDownloadTestSuite) TestCatchUp() {
+	ctx := s.T().Context()
+
+	nms := network.NewTestManagers(ctx, s.T(), 9320, 5)
+	client, good1, good2, bad, slow := nms[0], nms[1], nms[2], nms[3], nms[4]
+	defer func() {
+		for _, nm := range nms {
+			nm.Close()
+		}
+	}()
+
+	const nBlocks = 30
+	for _, nm := range []network.Manager{good1, good2, bad, slow} {
+		database, err := db.NewBadgerDbInMemory()
+		s.Require().NoError(err)
+		defer database.Close()
+
+		if nm == bad {
+			// The bad peer serves another chain.
+			s.writeChain(database, nBlocks, 20)
+		} else {
+			s.chain = s.writeChain(database, nBlocks, 10)
+		}
+		SetBlockRequestHandler(ctx, nm, s.shardId, database, logging.Nop())
+		network.ConnectManagers(s.T(), client, nm)
+	}
+	// The slow peer knows the head, but does not serve the blocks.
+	slow.SetStreamHandler(ctx, protocolShardBlock(s.shardId), func(stream network.Stream) {
+		select {
+		case <-time.After(3 * time.Second):
+		case <-ctx.Done():
+		}
+	})
+
+	s.applied = []*types.Block{s.chain[0]}
+	d := s.newDownloader(client)
+	// The misbehaving peers are preferred until they fail.
+	d.scores[bad.ID()] = 100
+	d.scores[slow.ID()] = 100
+
+	s.Require().NoError(d.catchUp(ctx))
+
+	s.Require().Len(s.applied, nBlocks)
+	for i, block := range s.applied {
+		s.Equal(s.chain[i].Hash(s.shardId), block.Hash(s.shardId))
+	}
+
+	s.Less(d.scores[bad.ID()], 100)
+	s.Less(d.scores[slow.ID()], 100)
+
+	progress := d.Progress()
+	s.False(progress.Syncing)
+	s.Equal(types.BlockNumber(0), progress.StartingBlock)
+	s.Equal(types.BlockNumber(nBlocks-1), progress.CurrentBlock)
+	s.Equal(types.BlockNumber(nBlocks-1), progress.HighestBlock)
+	s.NotEmpty(progress.Peers)
+	for _, peer := range progress.Peers {
+		s.True(slices.Contains([]network.PeerID{good1.ID(), good2.ID()}, peer))
+	}
+
+	// Nothing is downloaded if the shard is up to date.
+	s.Require().NoError(d.catchUp(ctx))
+	s.Len(s.applied, nBlocks)
+}
+
+func (s *BlockDownloadTestSuite) TestNoPeers() {
+	ctx := s.T().Context()
+
+	nms := network.NewTestManagers(ctx, s.T(), 9330, 2)
+	client, bad := nms[0], nms[1]
+	defer func() {
+		for _, nm := range nms {
+			nm.Close()
+		}
+	}()
+
+	database, err := db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+	defer database.Close()
+	s.writeChain(database, 10, 20)
+	SetBlockRequestHandler(ctx, bad, s.shardId, database, logging.Nop())
+	network.ConnectManagers(s.T(), client, bad)
+
+	local, err := db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+	defer local.Close()
+	s.applied = s.writeChain(local, 1, 10)
+
+	d := s.newDownloader(client)
+	s.Require().ErrorContains(d.catchUp(ctx), "no peers to download blocks from 1")
+	s.Len(s.applied, 1)
+	progress := d.Progress()
+	s.False(progress.Syncing)
+	s.Equal(types.BlockNumber(9), progress.HighestBlock)
+}
+
+func (s *BlockDownloadTestSuite) TestTamperedBody() {
+	ctx := s.T().Context()
+
+	nms := network.NewTestManagers(ctx, s.T(), 9335, 3)
+	client, good, tampering := nms[0], nms[1], nms[2]
+	defer func() {
+		for _, nm := range nms {
+			nm.Close()
+		}
+	}()
+
+	const nBlocks = 12
+	for _, nm := range []network.Manager{good, tampering} {
+		database, err := db.NewBadgerDbInMemory()
+		s.Require().NoError(err)
+		defer database.Close()
+
+		s.chain = s.writeChain(database, nBlocks, 10)
+		SetBlockRequestHandler(ctx, nm, s.shardId, database, logging.Nop())
+		network.ConnectManagers(s.T(), client, nm)
+	}
+	// The tampering peer serves the correct headers with extra transactions.
+	tampering.SetStreamHandler(ctx, protocolShardBlock(s.shardId), func(stream network.Stream) {
+		data, err := io.ReadAll(stream)
+		if err != nil {
+			return
+		}
+		var req pb.BlocksRangeRequest
+		if err := proto.Unmarshal(data, &req); err != nil {
+			return
+		}
+
+		for id := req.GetId(); id < req.GetId()+req.GetCount() && id < nBlocks; id++ {
+			block := &types.BlockWithExtractedData{
+				Block:          s.chain[id],
+				InTransactions: []*types.Transaction{types.NewEmptyTransaction()},
+			}
+			pbBlock, err := marshalBlockSSZ(block)
+			if err != nil {
+				return
+			}
+			if err := writeBlockToStream(stream, pbBlock); err != nil {
+				return
+			}
+		}
+	})
+
+	s.applied = []*types.Block{s.chain[0]}
+	d := s.newDownloader(client)
+	apply := d.apply
+	d.apply = func(ctx context.Context, block *types.BlockWithExtractedData) error {
+		if len(block.InTransactions) > 0 {
+			return newErrInvalidBlock(errors.New("transactions root mismatch"))
+		}
+		return apply(ctx, block)
+	}
+	d.scores[tampering.ID()] = 100
+
+	s.Require().NoError(d.catchUp(ctx))
+	s.Require().Len(s.applied, nBlocks)
+	s.Less(d.scores[tampering.ID()], 100)
+	s.Equal([]network.PeerID{good.ID()}, d.Progress().Peers)
+}
+
+func (s *BlockDownloadTestSuite) TestLyingHead() {
+	ctx := s.T().Context()
+
+	nms := network.NewTestManagers(ctx, s.T(), 9340, 5)
+	client, good1, good2, liar, liarOnlyClient := nms[0], nms[1], nms[2], nms[3], nms[4]
+	defer func() {
+		for _, nm := range nms {
+			nm.Close()
+		}
+	}()
+
+	const nBlocks = 12
+	for _, nm := range []network.Manager{good1, good2, liar} {
+		database, err := db.NewBadgerDbInMemory()
+		s.Require().NoError(err)
+		defer database.Close()
+
+		s.chain = s.writeChain(database, nBlocks, 10)
+		SetBlockRequestHandler(ctx, nm, s.shardId, database, logging.Nop())
+		network.ConnectManagers(s.T(), client, nm)
+	}
+	network.ConnectManagers(s.T(), liarOnlyClient, liar)
+	// The liar serves the chain, but reports a head far beyond it.
+	const liarHead = types.BlockNumber(1 << 40)
+	liar.SetRequestHandler(ctx, protocolShardHead(s.shardId), func(context.Context, []byte) ([]byte, error) {
+		return proto.Marshal(&pb.BlockHead{Id: int64(liarHead)})
+	})
+
+	s.applied = []*types.Block{s.chain[0]}
+	d := s.newDownloader(client)
+	s.Require().NoError(d.catchUp(ctx))
+	s.Require().Len(s.applied, nBlocks)
+	s.Equal(types.BlockNumber(nBlocks-1), d.Progress().HighestBlock)
+
+	// The head of the only peer can't be confirmed, the downloader stops once the peer fails to serve it
+	// and leaves the rest to the sequential sync.
+	s.applied = []*types.Block{s.chain[0]}
+	d = s.newDownloader(liarOnlyClient)
+	s.Require().ErrorContains(d.catchUp(ctx), "no peers to download blocks from")
+	s.LessOrEqual(len(s.applied), nBlocks)
+	s.Equal(liarHead, d.Progress().HighestBlock)
+}
+
+func TestBlockDownload(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(BlockDownloadTestSuite))
+}
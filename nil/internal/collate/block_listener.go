@@ -27,6 +27,10 @@ func protocolShardBlock(shardId types.ShardId) network.ProtocolID {
 	return network.ProtocolID(fmt.Sprintf("/shard/%s/block", shardId))
 }
 
+func protocolShardHead(shardId types.ShardId) network.ProtocolID {
+	return network.ProtocolID(fmt.Sprintf("/shard/%s/head", shardId))
+}
+
 // ListPeers returns a list of peers that may support block exchange protocol.
 func ListPeers(networkManager network.Manager, shardId types.ShardId) []network.PeerID {
 	// Try to get peers supporting the protocol.
@@ -68,13 +72,23 @@ func logError(logger logging.Logger, err error, msg string) {
 // 1. Write block size as 8 bytes (big-endian).
 // 2. Write block data (in protobuf format).
 // That's actually "Length-Delimited Messages".
-func readBlockFromStream(s network.Stream) (*types.BlockWithExtractedData, error) {
+func readBlockFromStream(s io.Reader) (*types.BlockWithExtractedData, error) {
+	length, err := readBlockSize(s)
+	if err != nil {
+		return nil, err
+	}
+	return readBlockBody(s, length)
+}
+
+func readBlockSize(s io.Reader) (uint64, error) {
 	header := make([]byte, 8)
 	if _, err := io.ReadFull(s, header); err != nil {
-		return nil, fmt.Errorf("failed to read block size: %w", err)
+		return 0, fmt.Errorf("failed to read block size: %w", err)
 	}
+	return binary.BigEndian.Uint64(header), nil
+}
 
-	length := binary.BigEndian.Uint64(header)
+func readBlockBody(s io.Reader, length uint64) (*types.BlockWithExtractedData, error) {
 	buf := make([]byte, length)
 	if _, err := io.ReadFull(s, buf); err != nil {
 		return nil, fmt.Errorf("failed to read block: %w", err)
@@ -88,7 +102,7 @@ func readBlockFromStream(s network.Stream) (*types.BlockWithExtractedData, error
 	return unmarshalBlockSSZ(&pbBlock)
 }
 
-func writeBlockToStream(s network.Stream, block *pb.RawFullBlock) error {
+func writeBlockToStream(s io.Writer, block *pb.RawFullBlock) error {
 	data, err := proto.Marshal(block)
 	if err != nil {
 		return fmt.Errorf("failed to marshal block to Protobuf: %w", err)
@@ -108,9 +122,16 @@ func writeBlockToStream(s network.Stream, block *pb.RawFullBlock) error {
 
 func RequestBlocks(ctx context.Context, networkManager network.Manager, peerID network.PeerID,
 	shardId types.ShardId, blockNumber types.BlockNumber, logger logging.Logger,
+) (<-chan *types.BlockWithExtractedData, error) {
+	return RequestBlocksRange(ctx, networkManager, peerID, shardId, blockNumber, 0, logger)
+}
+
+// RequestBlocksRange requests at most count blocks starting from blockNumber (all the following blocks if count is 0).
+func RequestBlocksRange(ctx context.Context, networkManager network.Manager, peerID network.PeerID,
+	shardId types.ShardId, blockNumber types.BlockNumber, count uint64, logger logging.Logger,
 ) (<-chan *types.BlockWithExtractedData, error) {
 	var err error
-	req, err := proto.Marshal(&pb.BlocksRangeRequest{Id: int64(blockNumber)})
+	req, err := proto.Marshal(&pb.BlocksRangeRequest{Id: int64(blockNumber), Count: int64(count)})
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal blocks request: %w", err)
 	}
@@ -219,7 +240,7 @@ func SetBlockRequestHandler(
 			WithChildBlocks().
 			WithConfig()
 
-		for id := blockReq.GetId(); ; id++ {
+		for id := blockReq.GetId(); blockReq.GetCount() <= 0 || id < blockReq.GetId()+blockReq.GetCount(); id++ {
 			resp, err := acc.ByNumber(types.BlockNumber(id))
 			if err != nil {
 				if !errors.Is(err, db.ErrKeyNotFound) {
@@ -244,4 +265,37 @@ func SetBlockRequestHandler(
 	}
 
 	networkManager.SetStreamHandler(ctx, protocolShardBlock(shardId), handler)
+
+	networkManager.SetRequestHandler(ctx, protocolShardHead(shardId), func(ctx context.Context, _ []byte) ([]byte, error) {
+		tx, err := database.CreateRoTx(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		block, _, err := db.ReadLastBlock(tx, shardId)
+		if err != nil {
+			return nil, err
+		}
+		return proto.Marshal(&pb.BlockHead{Id: int64(block.Id)})
+	})
+}
+
+// RequestBlockHead requests the number of the last block of the shard known to the peer.
+func RequestBlockHead(
+	ctx context.Context, networkManager network.Manager, peerID network.PeerID, shardId types.ShardId,
+) (types.BlockNumber, error) {
+	resp, err := networkManager.SendRequestAndGetResponse(ctx, peerID, protocolShardHead(shardId), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var head pb.BlockHead
+	if err := proto.Unmarshal(resp, &head); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal block head: %w", err)
+	}
+	if head.GetId() < 0 {
+		return 0, fmt.Errorf("invalid block head %d", head.GetId())
+	}
+	return types.BlockNumber(head.GetId()), nil
 }
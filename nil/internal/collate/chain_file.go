@@ -0,0 +1,294 @@
+package collate
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+)
+
+// Chain data file layout:
+// 1. Magic string and format version (4 bytes, big-endian).
+// 2. For every exported shard: shard id (4 bytes, big-endian) followed by its blocks
+// in the format of the block exchange protocol, terminated by a zero block size.
+// Shards go in ascending order, so main shard blocks are imported before the blocks that refer to them.
+const (
+	chainFileMagic = "NILCHAIN"
+
+	ChainFileVersion uint32 = 1
+
+	// maxChainFileBlockSize protects from allocating huge buffers when reading corrupted files.
+	maxChainFileBlockSize = 1 << 28
+)
+
+var (
+	ErrNotChainFile           = errors.New("not a chain data file")
+	ErrUnsupportedChainFile   = errors.New("unsupported chain data file version")
+	ErrChainFileShardNotFound = errors.New("chain data file contains unknown shard")
+)
+
+type ChainExportParams struct {
+	ShardIds []types.ShardId
+
+	// FirstBlock and LastBlock define the exported range (inclusive).
+	// The range is truncated to the last block of the shard.
+	FirstBlock types.BlockNumber
+	LastBlock  types.BlockNumber
+}
+
+// ExportChain writes the blocks of the requested shards into w.
+// Returns the number of exported blocks per shard.
+func ExportChain(
+	ctx context.Context, database db.DB, w io.Writer, params ChainExportParams,
+) (map[types.ShardId]uint64, error) {
+	if params.FirstBlock > params.LastBlock {
+		return nil, fmt.Errorf("invalid block range [%d, %d]", params.FirstBlock, params.LastBlock)
+	}
+
+	shardIds := slices.Clone(params.ShardIds)
+	slices.Sort(shardIds)
+	shardIds = slices.Compact(shardIds)
+
+	bw := bufio.NewWriter(w)
+	if err := writeChainFileHeader(bw); err != nil {
+		return nil, err
+	}
+
+	accessor := execution.NewStateAccessor()
+	res := make(map[types.ShardId]uint64, len(shardIds))
+	for _, shardId := range shardIds {
+		n, err := exportShard(ctx, database, accessor, bw, shardId, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export shard %s: %w", shardId, err)
+		}
+		res[shardId] = n
+	}
+
+	if err := bw.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to flush chain data: %w", err)
+	}
+	return res, nil
+}
+
+func exportShard(
+	ctx context.Context,
+	database db.DB,
+	accessor *execution.StateAccessor,
+	w io.Writer,
+	shardId types.ShardId,
+	params ChainExportParams,
+) (uint64, error) {
+	tx, err := database.CreateRoTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := binary.Write(w, binary.BigEndian, uint32(shardId)); err != nil {
+		return 0, fmt.Errorf("failed to write shard id: %w", err)
+	}
+
+	acc := accessor.RawAccess(tx, shardId).
+		GetBlock().
+		WithOutTransactions().
+		WithInTransactions().
+		WithReceipts().
+		WithChildBlocks().
+		WithConfig()
+
+	var n uint64
+	for id := params.FirstBlock; id <= params.LastBlock; id++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		resp, err := acc.ByNumber(id)
+		if errors.Is(err, db.ErrKeyNotFound) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		b := &pb.RawFullBlock{
+			BlockSSZ:           resp.Block(),
+			OutTransactionsSSZ: resp.OutTransactions(),
+			OutTxCountsSSZ:     resp.OutTxCounts(),
+			InTransactionsSSZ:  resp.InTransactions(),
+			InTxCountsSSZ:      resp.InTxCounts(),
+			ReceiptsSSZ:        resp.Receipts(),
+			ChildBlocks:        pb.PackHashes(resp.ChildBlocks()),
+			Config:             resp.Config(),
+		}
+		if err := writeBlockToStream(w, b); err != nil {
+			return 0, err
+		}
+		n++
+
+		// Avoid overflow when exporting up to the maximal block number.
+		if id == types.InvalidBlockNumber {
+			break
+		}
+	}
+
+	if err := binary.Write(w, binary.BigEndian, uint64(0)); err != nil {
+		return 0, fmt.Errorf("failed to write end of shard: %w", err)
+	}
+	return n, nil
+}
+
+func writeChainFileHeader(w io.Writer) error {
+	if _, err := io.WriteString(w, chainFileMagic); err != nil {
+		return fmt.Errorf("failed to write chain data header: %w", err)
+	}
+	if err := binary.Write(w, binary.BigEndian, ChainFileVersion); err != nil {
+		return fmt.Errorf("failed to write chain data header: %w", err)
+	}
+	return nil
+}
+
+func readChainFileHeader(r io.Reader) error {
+	magic := make([]byte, len(chainFileMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrNotChainFile
+		}
+		return err
+	}
+	if string(magic) != chainFileMagic {
+		return ErrNotChainFile
+	}
+
+	var version uint32
+	if err := binary.Read(r, binary.BigEndian, &version); err != nil {
+		return fmt.Errorf("failed to read chain data version: %w", err)
+	}
+	if version != ChainFileVersion {
+		return fmt.Errorf("%w: %d (expected %d)", ErrUnsupportedChainFile, version, ChainFileVersion)
+	}
+	return nil
+}
+
+// ImportChain replays the blocks from r via the validators of the corresponding shards,
+// so every block is re-executed and checked before it is written into the database.
+// Blocks which are already present in the database are skipped, which makes it safe to restart an interrupted import.
+// Returns the number of imported blocks per shard.
+func ImportChain(
+	ctx context.Context, r io.Reader, validators []*Validator, logger logging.Logger,
+) (map[types.ShardId]uint64, error) {
+	br := bufio.NewReader(r)
+	if err := readChainFileHeader(br); err != nil {
+		return nil, err
+	}
+
+	res := make(map[types.ShardId]uint64)
+	for {
+		var rawShardId uint32
+		if err := binary.Read(br, binary.BigEndian, &rawShardId); err != nil {
+			if errors.Is(err, io.EOF) {
+				return res, nil
+			}
+			return nil, fmt.Errorf("failed to read shard id: %w", err)
+		}
+
+		shardId := types.ShardId(rawShardId)
+		if int(shardId) >= len(validators) {
+			return nil, fmt.Errorf("%w: %s", ErrChainFileShardNotFound, shardId)
+		}
+
+		n, err := importShard(ctx, br, validators[shardId], logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import shard %s: %w", shardId, err)
+		}
+		res[shardId] += n
+	}
+}
+
+func importShard(ctx context.Context, r io.Reader, validator *Validator, logger logging.Logger) (uint64, error) {
+	shardId := validator.params.ShardId
+	logger = logger.With().Stringer(logging.FieldShardId, shardId).Logger()
+
+	var n uint64
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		length, err := readBlockSize(r)
+		if err != nil {
+			return 0, err
+		}
+		if length == 0 {
+			return n, nil
+		}
+		if length > maxChainFileBlockSize {
+			return 0, fmt.Errorf("block size %d exceeds the limit of %d bytes", length, maxChainFileBlockSize)
+		}
+
+		block, err := readBlockBody(r, length)
+		if err != nil {
+			return 0, err
+		}
+
+		imported, err := importBlock(ctx, validator, block)
+		if err != nil {
+			return 0, fmt.Errorf("block %d: %w", block.Id, err)
+		}
+		if !imported {
+			continue
+		}
+		n++
+
+		if uint64(block.Id)%uint64(blockReportInterval) == 0 {
+			logger.Info().
+				Stringer(logging.FieldBlockNumber, block.Id).
+				Msg("Imported block")
+		}
+	}
+}
+
+func importBlock(ctx context.Context, validator *Validator, block *types.BlockWithExtractedData) (bool, error) {
+	shardId := validator.params.ShardId
+	blockHash := block.Hash(shardId)
+
+	lastBlock, lastBlockHash, err := validator.GetLastBlock(ctx)
+	if err != nil {
+		return false, err
+	}
+	if lastBlock == nil {
+		return false, errors.New("shard has no zero state")
+	}
+
+	if block.Id <= lastBlock.Id {
+		// The block is already known, just make sure that the file describes the same chain.
+		if _, err := validator.getBlock(ctx, blockHash); err != nil {
+			if errors.Is(err, db.ErrKeyNotFound) {
+				return false, fmt.Errorf("block %x conflicts with the local chain", blockHash)
+			}
+			return false, err
+		}
+		return false, nil
+	}
+
+	// Check the linkage here, because the validator treats such errors as fatal.
+	if block.Id != lastBlock.Id+1 {
+		return false, fmt.Errorf("gap in the chain: the last local block is %d", lastBlock.Id)
+	}
+	if block.PrevBlock != lastBlockHash {
+		return false, fmt.Errorf("previous block hash mismatch: expected %x, got %x", lastBlockHash, block.PrevBlock)
+	}
+
+	if err := validator.ReplayBlock(ctx, block); err != nil {
+		return false, err
+	}
+	return true, nil
+}
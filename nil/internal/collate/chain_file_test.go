@@ -0,0 +1,298 @@
+package collate
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type ChainFileTestSuite struct {
+	suite.Suite
+
+	nShards uint32
+	db      db.DB
+}
+
+func (s *ChainFileTestSuite) SetupSuite() {
+	s.nShards = 2
+}
+
+func (s *ChainFileTestSuite) SetupTest() {
+	s.db = s.newDb()
+}
+
+func (s *ChainFileTestSuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *ChainFileTestSuite) newDb() db.DB {
+	s.T().Helper()
+
+	database, err := db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+	return database
+}
+
+// writeChain writes n linked blocks without any state into every shard, starting from the given base fee.
+func (s *ChainFileTestSuite) writeChain(database db.DB, n int, baseFee uint64) {
+	s.T().Helper()
+
+	for shardId := range types.ShardId(s.nShards) {
+		writeTestChain(s.T(), database, shardId, n, baseFee)
+	}
+}
+
+func (s *ChainFileTestSuite) newValidators(database db.DB) []*Validator {
+	s.T().Helper()
+
+	validators := make([]*Validator, s.nShards)
+	for i := range s.nShards {
+		shardId := types.ShardId(i)
+		params := &Params{
+			BlockGeneratorParams: execution.NewBlockGeneratorParams(shardId, s.nShards),
+			Topology:             new(TrivialShardTopology),
+		}
+		var err error
+		validators[i], err = NewValidator(params, validators[0], database, &MockTxnPool{}, nil)
+		s.Require().NoError(err)
+	}
+	return validators
+}
+
+func (s *ChainFileTestSuite) export(params ChainExportParams) ([]byte, map[types.ShardId]uint64) {
+	s.T().Helper()
+
+	var buf bytes.Buffer
+	exported, err := ExportChain(s.T().Context(), s.db, &buf, params)
+	s.Require().NoError(err)
+	return buf.Bytes(), exported
+}
+
+func (s *ChainFileTestSuite) importChain(database db.DB, data []byte) (map[types.ShardId]uint64, error) {
+	s.T().Helper()
+
+	return ImportChain(s.T().Context(), bytes.NewReader(data), s.newValidators(database), logging.Nop())
+}
+
+func (s *ChainFileTestSuite) TestHeader() {
+	_, err := s.importChain(s.db, nil)
+	s.Require().ErrorIs(err, ErrNotChainFile)
+
+	_, err = s.importChain(s.db, []byte("NOTCHAIN\x00\x00\x00\x01"))
+	s.Require().ErrorIs(err, ErrNotChainFile)
+
+	data, _ := s.export(ChainExportParams{LastBlock: types.InvalidBlockNumber})
+	data = bytes.Clone(data)
+	binary.BigEndian.PutUint32(data[len(chainFileMagic):], ChainFileVersion+1)
+	_, err = s.importChain(s.db, data)
+	s.Require().ErrorIs(err, ErrUnsupportedChainFile)
+}
+
+func (s *ChainFileTestSuite) TestExport() {
+	s.writeChain(s.db, 5, 10)
+
+	s.Run("All", func() {
+		data, exported := s.export(ChainExportParams{
+			ShardIds:  []types.ShardId{1, 0, 1},
+			LastBlock: types.InvalidBlockNumber,
+		})
+		s.Equal(map[types.ShardId]uint64{0: 5, 1: 5}, exported)
+
+		r := bytes.NewReader(data)
+		s.Require().NoError(readChainFileHeader(r))
+		for shardId := range types.ShardId(s.nShards) {
+			var rawShardId uint32
+			s.Require().NoError(binary.Read(r, binary.BigEndian, &rawShardId))
+			s.Equal(shardId, types.ShardId(rawShardId))
+
+			for i := range 5 {
+				length, err := readBlockSize(r)
+				s.Require().NoError(err)
+				block, err := readBlockBody(r, length)
+				s.Require().NoError(err)
+				s.Equal(types.BlockNumber(i), block.Id)
+			}
+
+			length, err := readBlockSize(r)
+			s.Require().NoError(err)
+			s.Zero(length)
+		}
+		s.Zero(r.Len())
+	})
+
+	s.Run("Range", func() {
+		_, exported := s.export(ChainExportParams{
+			ShardIds:   []types.ShardId{types.MainShardId},
+			FirstBlock: 1,
+			LastBlock:  3,
+		})
+		s.Equal(map[types.ShardId]uint64{types.MainShardId: 3}, exported)
+
+		_, exported = s.export(ChainExportParams{
+			ShardIds:   []types.ShardId{types.MainShardId},
+			FirstBlock: 3,
+			LastBlock:  10,
+		})
+		s.Equal(map[types.ShardId]uint64{types.MainShardId: 2}, exported)
+	})
+
+	s.Run("InvalidRange", func() {
+		_, err := ExportChain(s.T().Context(), s.db, new(bytes.Buffer), ChainExportParams{
+			FirstBlock: 3,
+			LastBlock:  1,
+		})
+		s.Require().Error(err)
+	})
+}
+
+func (s *ChainFileTestSuite) TestImportKnownBlocks() {
+	s.writeChain(s.db, 5, 10)
+
+	data, _ := s.export(ChainExportParams{
+		ShardIds:  []types.ShardId{0, 1},
+		LastBlock: types.InvalidBlockNumber,
+	})
+
+	// Importing the chain into the same database is a no-op.
+	imported, err := s.importChain(s.db, data)
+	s.Require().NoError(err)
+	s.Equal(map[types.ShardId]uint64{0: 0, 1: 0}, imported)
+
+	s.Run("Conflict", func() {
+		other := s.newDb()
+		defer other.Close()
+		s.writeChain(other, 5, 20)
+
+		_, err := s.importChain(other, data)
+		s.Require().ErrorContains(err, "conflicts with the local chain")
+	})
+
+	s.Run("Gap", func() {
+		other := s.newDb()
+		defer other.Close()
+		s.writeChain(other, 1, 10)
+
+		data, _ := s.export(ChainExportParams{
+			ShardIds:   []types.ShardId{types.MainShardId},
+			FirstBlock: 2,
+			LastBlock:  types.InvalidBlockNumber,
+		})
+		_, err := s.importChain(other, data)
+		s.Require().ErrorContains(err, "gap in the chain")
+	})
+
+	s.Run("NoZeroState", func() {
+		other := s.newDb()
+		defer other.Close()
+
+		_, err := s.importChain(other, data)
+		s.Require().ErrorContains(err, "shard has no zero state")
+	})
+
+	s.Run("UnknownShard", func() {
+		var buf bytes.Buffer
+		s.Require().NoError(writeChainFileHeader(&buf))
+		s.Require().NoError(binary.Write(&buf, binary.BigEndian, s.nShards))
+
+		_, err := s.importChain(s.db, buf.Bytes())
+		s.Require().ErrorIs(err, ErrChainFileShardNotFound)
+	})
+}
+
+// readChainFile returns the blocks of every shard in the file.
+func (s *ChainFileTestSuite) readChainFile(data []byte) map[types.ShardId][]*types.BlockWithExtractedData {
+	s.T().Helper()
+
+	r := bytes.NewReader(data)
+	s.Require().NoError(readChainFileHeader(r))
+
+	res := make(map[types.ShardId][]*types.BlockWithExtractedData)
+	for r.Len() > 0 {
+		var rawShardId uint32
+		s.Require().NoError(binary.Read(r, binary.BigEndian, &rawShardId))
+		for {
+			length, err := readBlockSize(r)
+			s.Require().NoError(err)
+			if length == 0 {
+				break
+			}
+			block, err := readBlockBody(r, length)
+			s.Require().NoError(err)
+			res[types.ShardId(rawShardId)] = append(res[types.ShardId(rawShardId)], block)
+		}
+	}
+	return res
+}
+
+func (s *ChainFileTestSuite) TestRoundTrip() {
+	ctx := s.T().Context()
+
+	other := s.newDb()
+	defer other.Close()
+	for _, database := range []db.DB{s.db, other} {
+		execution.GenerateZeroState(s.T(), types.MainShardId, database)
+		execution.GenerateZeroState(s.T(), types.BaseShardId, database)
+	}
+
+	// The transfer from the smart account of the base shard produces
+	// the external transaction there, the internal one in the main shard and the refund back.
+	validators := s.newValidators(s.db)
+	pool, ok := validators[types.BaseShardId].pool.(*MockTxnPool)
+	s.Require().True(ok)
+	pool.Add(execution.NewSendMoneyTransaction(s.T(), types.GenerateRandomAddress(types.MainShardId), 0))
+
+	const nRounds = 4
+	for range nRounds {
+		for _, shardId := range []types.ShardId{types.BaseShardId, types.MainShardId} {
+			proposal, err := validators[shardId].BuildProposal(ctx)
+			s.Require().NoError(err)
+			s.Require().NoError(validators[shardId].InsertProposal(ctx, proposal, &types.ConsensusParams{}))
+		}
+		pool.Reset()
+	}
+
+	data, exported := s.export(ChainExportParams{
+		ShardIds:   []types.ShardId{types.MainShardId, types.BaseShardId},
+		FirstBlock: 1,
+		LastBlock:  types.InvalidBlockNumber,
+	})
+	s.Equal(map[types.ShardId]uint64{types.MainShardId: nRounds, types.BaseShardId: nRounds}, exported)
+	for shardId, blocks := range s.readChainFile(data) {
+		hasTxns := slices.ContainsFunc(blocks, func(block *types.BlockWithExtractedData) bool {
+			return len(block.InTransactions) > 0
+		})
+		s.True(hasTxns, "shard %s", shardId)
+	}
+
+	imported, err := s.importChain(other, data)
+	s.Require().NoError(err)
+	s.Equal(exported, imported)
+
+	srcTx, err := s.db.CreateRoTx(ctx)
+	s.Require().NoError(err)
+	defer srcTx.Rollback()
+	dstTx, err := other.CreateRoTx(ctx)
+	s.Require().NoError(err)
+	defer dstTx.Rollback()
+
+	for shardId := range types.ShardId(s.nShards) {
+		expected, err := db.ReadLastBlockHash(srcTx, shardId)
+		s.Require().NoError(err)
+		got, err := db.ReadLastBlockHash(dstTx, shardId)
+		s.Require().NoError(err)
+		s.Equal(expected, got, "shard %s", shardId)
+	}
+}
+
+func TestChainFile(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(ChainFileTestSuite))
+}
@@ -14,10 +14,14 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rollup"
 	"github.com/NilFoundation/nil/nil/services/txnpool"
 	l1types "github.com/ethereum/go-ethereum/core/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -25,7 +29,7 @@ const (
 	maxTxnsFromPool                      = 10_000
 	defaultMaxForwardTransactionsInBlock = 200
 
-	validatorPatchLevel = 1
+	validatorPatchLevel = execution.PatchLevelWeightedProposers
 )
 
 type proposer struct {
@@ -60,7 +64,14 @@ func newProposer(params *Params, topology ShardTopology, pool TxnPool, logger lo
 	}
 }
 
-func (p *proposer) GenerateProposal(ctx context.Context, txFabric db.DB) (*execution.ProposalSSZ, error) {
+func (p *proposer) GenerateProposal(ctx context.Context, txFabric db.DB) (_ *execution.ProposalSSZ, err error) {
+	ctx, span := tracer.Start(ctx, "collate.GenerateProposal",
+		trace.WithAttributes(telattr.ShardId(p.params.ShardId)))
+	defer func() {
+		telemetry.EndSpan(span, err)
+	}()
+
+	p.ctx = ctx
 	p.proposal = &execution.ProposalSSZ{}
 
 	tx, err := txFabric.CreateRoTx(ctx)
@@ -80,6 +91,7 @@ func (p *proposer) GenerateProposal(ctx context.Context, txFabric db.DB) (*execu
 	}
 
 	p.setPrevBlockData(prevBlock, prevBlockHash)
+	span.SetAttributes(telattr.BlockNumber(prevBlock.Id + 1))
 
 	configAccessor, err := config.NewConfigAccessorFromBlockWithTx(tx, prevBlock, p.params.ShardId)
 	if err != nil {
@@ -270,17 +282,24 @@ func (p *proposer) handleTransaction(txn *types.Transaction, txnHash common.Hash
 		}()
 	}
 
+	ctx, span := startTransactionSpan(p.ctx, "collate.HandleTransaction", p.params.ShardId, txnHash)
+	defer span.End()
+
 	p.executionState.AddInTransactionWithHash(txn, txnHash)
 
-	res := p.executionState.HandleTransaction(p.ctx, txn, payer)
+	res := p.executionState.HandleTransaction(ctx, txn, payer)
 	if res.FatalError != nil {
+		telemetry.RecordError(span, res.FatalError)
 		return res.FatalError
 	} else if res.Failed() {
 		p.logger.Debug().Stringer(logging.FieldTransactionHash, txnHash).
 			Err(res.Error).
 			Msg("Transaction execution failed. It doesn't prevent adding it to the block.")
+		span.SetAttributes(attribute.String("executionError", res.Error.Error()))
 	}
 
+	telemetry.RegisterTransactionSpan(txnHash, span)
+	traceOutboundTransactions(ctx, p.params.ShardId, p.executionState.OutTransactions[txnHash])
 	return nil
 }
 
@@ -469,6 +488,11 @@ func (p *proposer) handleTransactionsFromNeighbors(tx db.RoTx) error {
 							return err
 						}
 						p.proposal.ForwardTxnRefs = append(p.proposal.ForwardTxnRefs, ref)
+
+						txnHash := txn.Hash()
+						_, span := startTransactionSpan(p.ctx, "collate.ForwardTransaction", p.params.ShardId, txnHash)
+						telemetry.RegisterTransactionSpan(txnHash, span)
+						span.End()
 					}
 				}
 			}
@@ -9,9 +9,13 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/txnpool"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 type ProposerTestSuite struct {
@@ -216,6 +220,85 @@ func (s *ProposerTestSuite) TestCollator() {
 	})
 }
 
+func (s *ProposerTestSuite) TestTracing() {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	to := types.GenerateRandomAddress(s.shardId)
+	pool := &MockTxnPool{}
+	params := s.newParams()
+	p := newTestProposer(params, pool)
+
+	generateBlock := func() {
+		proposal := s.generateProposal(p)
+
+		block, err := execution.NewBlockGenerator(
+			s.T().Context(), params.BlockGeneratorParams, s.db, s.readBlock(proposal.PrevBlockHash))
+		s.Require().NoError(err)
+		defer block.Rollback()
+
+		_, err = block.GenerateBlock(proposal, &types.ConsensusParams{})
+		s.Require().NoError(err)
+	}
+
+	findSpans := func(name string) []sdktrace.ReadOnlySpan {
+		var spans []sdktrace.ReadOnlySpan
+		for _, span := range recorder.Ended() {
+			if span.Name() == name {
+				spans = append(spans, span)
+			}
+		}
+		return spans
+	}
+
+	execution.GenerateZeroState(s.T(), types.MainShardId, s.db)
+	execution.GenerateZeroState(s.T(), s.shardId, s.db)
+
+	txn := execution.NewSendMoneyTransaction(s.T(), to, 0)
+	_, rootSpan := otel.Tracer("test").Start(s.T().Context(), "rpc.SendRawTransaction")
+	telemetry.RegisterTransactionSpan(txn.Hash(), rootSpan)
+	rootSpan.End()
+	traceId := rootSpan.SpanContext().TraceID()
+
+	pool.Add(txn)
+	generateBlock()
+
+	handled := findSpans("collate.HandleTransaction")
+	s.Require().Len(handled, 1)
+	s.Equal(traceId, handled[0].SpanContext().TraceID())
+	s.Equal(rootSpan.SpanContext().SpanID(), handled[0].Parent().SpanID())
+
+	proposals := findSpans("collate.GenerateProposal")
+	s.Require().Len(proposals, 1)
+	s.Require().Len(handled[0].Links(), 1)
+	s.Equal(proposals[0].SpanContext(), handled[0].Links()[0].SpanContext)
+
+	outbound := findSpans("collate.OutboundTransaction")
+	s.Require().Len(outbound, 1)
+	s.Equal(traceId, outbound[0].SpanContext().TraceID())
+
+	// the internal transaction emitted by the external one continues its trace
+	pool.Reset()
+	generateBlock()
+
+	handled = findSpans("collate.HandleTransaction")
+	s.Require().Len(handled, 2)
+	s.Equal(traceId, handled[1].SpanContext().TraceID())
+	s.Equal(outbound[0].SpanContext().SpanID(), handled[1].Parent().SpanID())
+}
+
+func (s *ProposerTestSuite) readBlock(hash common.Hash) *types.Block {
+	s.T().Helper()
+
+	tx, err := s.db.CreateRoTx(s.T().Context())
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	block, err := db.ReadBlock(tx, s.shardId, hash)
+	s.Require().NoError(err)
+	return block
+}
+
 func (s *ProposerTestSuite) getMainBalance() types.Value {
 	s.T().Helper()
 
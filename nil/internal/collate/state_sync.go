@@ -0,0 +1,969 @@
+package collate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	cm "github.com/NilFoundation/nil/nil/internal/network/connection_manager"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"golang.org/x/sync/errgroup"
+)
+
+// StateSyncConfig configures the state sync, which downloads the state of a recent main shard block
+// from several peers and verifies it against the block instead of copying the database of a single peer.
+type StateSyncConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// Quorum is the number of peers that must agree on the pivot block if PivotHash is not set.
+	// The pivot is verified against the local genesis block in any case, the quorum protects from a stale pivot.
+	Quorum int `yaml:"quorum,omitempty"`
+	// PivotHash is the hash of a trusted main shard block to sync the state of.
+	PivotHash common.Hash `yaml:"pivotHash,omitempty"`
+	// ChunkSize is the number of entries requested from a peer at once.
+	ChunkSize int `yaml:"chunkSize,omitempty"`
+	// RequestsPerPeer limits the number of concurrent requests in proportion to the number of peers.
+	RequestsPerPeer int `yaml:"requestsPerPeer,omitempty"`
+}
+
+func NewDefaultStateSyncConfig() *StateSyncConfig {
+	return &StateSyncConfig{
+		Quorum:          3,
+		ChunkSize:       256,
+		RequestsPerPeer: 4,
+	}
+}
+
+var (
+	errInvalidStateSyncData  = errors.New("invalid state sync data")
+	errInvalidStateSyncChain = errors.New("invalid state sync chain")
+)
+
+type stateSyncHeader struct {
+	block       *types.Block
+	hash        common.Hash
+	childBlocks []common.Hash
+}
+
+type stateSyncEntry struct {
+	trie stateSyncTrie
+	key  common.Hash
+}
+
+type stateSyncer struct {
+	config StateSyncConfig
+	params execution.BlockGeneratorParams
+
+	db             db.DB
+	networkManager network.Manager
+
+	logger logging.Logger
+
+	mu       sync.Mutex
+	peers    []network.PeerID
+	nextPeer int
+
+	requests chan struct{}
+}
+
+func newStateSyncer(
+	config *StateSyncConfig,
+	params execution.BlockGeneratorParams,
+	db db.DB,
+	networkManager network.Manager,
+	logger logging.Logger,
+) *stateSyncer {
+	cfg := *config
+	cfg.Quorum = max(cfg.Quorum, 1)
+	cfg.ChunkSize = min(max(cfg.ChunkSize, 1), maxStateSyncKeys)
+	cfg.RequestsPerPeer = max(cfg.RequestsPerPeer, 1)
+
+	return &stateSyncer{
+		config:         cfg,
+		params:         params,
+		db:             db,
+		networkManager: networkManager,
+		logger:         logger,
+	}
+}
+
+// Sync downloads the blocks and the state of all shards at the pivot main shard block.
+// The genesis block, which the node generates locally, is the root of trust: the main shard chain
+// from the genesis to the pivot is downloaded and every block of it is checked to be signed by the validators
+// of its predecessors, then every downloaded block and trie node is verified against the pivot.
+// The pivot is persisted, so that the sync is resumed from the same block after interruption,
+// and the entries that are already stored locally are not downloaded again.
+// The last blocks of the shards are written at the very end, after which the regular block sync takes over.
+func (s *stateSyncer) Sync(ctx context.Context, bootstrapPeers []network.AddrInfo, genesisHash common.Hash) error {
+	s.connectPeers(ctx, bootstrapPeers)
+	if len(s.peers) == 0 {
+		return errors.New("no peers to sync state from")
+	}
+	s.requests = make(chan struct{}, len(s.peers)*s.config.RequestsPerPeer)
+
+	pivotHash, err := s.choosePivot(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to choose pivot block: %w", err)
+	}
+	if err := s.writePivot(ctx, pivotHash); err != nil {
+		return err
+	}
+	s.logger.Info().
+		Stringer(logging.FieldBlockHash, pivotHash).
+		Int("peers", len(s.peers)).
+		Msg("Starting state sync")
+
+	pivot, err := s.fetchHeader(ctx, types.MainShardId, pivotHash)
+	if err != nil {
+		return err
+	}
+	if err := s.syncMainChain(ctx, pivot, genesisHash); err != nil {
+		if errors.Is(err, errInvalidStateSyncChain) {
+			// The pivot can't be trusted, so the next attempt must choose another one.
+			return errors.Join(err, s.deletePivot(ctx))
+		}
+		return err
+	}
+	shardHeaders, err := s.fetchShardHeaders(ctx, pivot)
+	if err != nil {
+		return err
+	}
+
+	eg, gctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		roots := []stateSyncEntry{{stateSyncContractTrie, pivot.block.SmartContractsRoot}}
+		roots = append(roots, blockTrieRoots(pivot.block)...)
+		return s.syncTries(gctx, types.MainShardId, roots)
+	})
+	for i, h := range shardHeaders {
+		eg.Go(func() error {
+			roots := []stateSyncEntry{{stateSyncContractTrie, h.block.SmartContractsRoot}}
+			roots = append(roots, blockTrieRoots(h.block)...)
+			return s.syncTries(gctx, types.ShardId(i+1), roots)
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	if err := s.finish(ctx, pivot, shardHeaders); err != nil {
+		return err
+	}
+
+	s.logger.Info().
+		Stringer(logging.FieldBlockHash, pivotHash).
+		Stringer(logging.FieldBlockNumber, pivot.block.Id).
+		Msg("State sync completed")
+	return nil
+}
+
+func blockTrieRoots(block *types.Block) []stateSyncEntry {
+	return []stateSyncEntry{
+		{stateSyncTransactionTrie, block.InTransactionsRoot},
+		{stateSyncTransactionTrie, block.OutTransactionsRoot},
+		{stateSyncReceiptTrie, block.ReceiptsRoot},
+	}
+}
+
+func (s *stateSyncer) connectPeers(ctx context.Context, bootstrapPeers []network.AddrInfo) {
+	add := func(peer network.PeerID) {
+		if peer != s.networkManager.ID() && !slices.Contains(s.peers, peer) {
+			s.peers = append(s.peers, peer)
+		}
+	}
+
+	for _, addr := range bootstrapPeers {
+		peer, err := s.networkManager.Connect(ctx, addr)
+		if err != nil {
+			s.logger.Warn().Err(err).Msgf("Failed to connect to %s to sync state", addr)
+			continue
+		}
+		add(peer)
+	}
+	for _, peer := range s.networkManager.GetPeersForProtocol(protocolStateSyncNodes()) {
+		add(peer)
+	}
+}
+
+// getPeers returns the peers starting from the next one in the round-robin order.
+func (s *stateSyncer) getPeers() []network.PeerID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.peers) == 0 {
+		return nil
+	}
+	start := s.nextPeer % len(s.peers)
+	s.nextPeer++
+	return append(slices.Clone(s.peers[start:]), s.peers[:start]...)
+}
+
+func (s *stateSyncer) dropPeer(peer network.PeerID, err error) {
+	s.mu.Lock()
+	n := len(s.peers)
+	s.peers = slices.DeleteFunc(s.peers, func(p network.PeerID) bool { return p == peer })
+	dropped := len(s.peers) < n
+	s.mu.Unlock()
+
+	// Concurrent requests to the peer might have failed as well.
+	if !dropped {
+		return
+	}
+
+	s.logger.Warn().Err(err).Stringer(logging.FieldPeerId, peer).Msg("Dropping state sync peer")
+	if tracker := network.TryGetPeerReputationTracker(s.networkManager); tracker != nil {
+		tracker.ReportPeer(peer, cm.ReputationChangeInvalidStateSyncData)
+	}
+}
+
+func (s *stateSyncer) request(
+	ctx context.Context, peer network.PeerID, protocolId network.ProtocolID, req []byte,
+) ([]byte, error) {
+	select {
+	case s.requests <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-s.requests }()
+
+	return s.networkManager.SendRequestAndGetResponse(ctx, peer, protocolId, req)
+}
+
+func (s *stateSyncer) requestHeader(
+	ctx context.Context, peer network.PeerID, req *StateSyncHeaderRequest,
+) (*stateSyncHeader, error) {
+	data, err := req.MarshalSSZ()
+	if err != nil {
+		return nil, err
+	}
+	data, err = s.request(ctx, peer, protocolStateSyncHeader(), data)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp StateSyncHeaderResponse
+	if err := resp.UnmarshalSSZ(data); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidStateSyncData, err)
+	}
+	if len(resp.Block) == 0 {
+		return nil, nil
+	}
+
+	block := &types.Block{}
+	if err := block.UnmarshalSSZ(resp.Block); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidStateSyncData, err)
+	}
+	header := &stateSyncHeader{
+		block:       block,
+		hash:        block.Hash(req.ShardId),
+		childBlocks: resp.ChildBlocks,
+	}
+	if req.ShardId.IsMainShard() {
+		root, err := childBlocksRootHash(header.childBlocks)
+		if err != nil {
+			return nil, err
+		}
+		if root != block.ChildBlocksRootHash {
+			return nil, fmt.Errorf("%w: child blocks do not match block %s", errInvalidStateSyncData, header.hash)
+		}
+	}
+	return header, nil
+}
+
+func childBlocksRootHash(childBlocks []common.Hash) (common.Hash, error) {
+	if len(childBlocks) == 0 {
+		return common.EmptyHash, nil
+	}
+
+	shards := make(map[types.ShardId]common.Hash, len(childBlocks))
+	for i, h := range childBlocks {
+		shards[types.ShardId(i+1)] = h
+	}
+	trie := execution.NewShardBlocksTrie(mpt.NewInMemMPT())
+	if err := execution.UpdateFromMap(trie, shards, func(v common.Hash) *common.Hash { return &v }); err != nil {
+		return common.EmptyHash, err
+	}
+	return trie.RootHash(), nil
+}
+
+// fetchHeader fetches the block by hash from the first peer that has it.
+func (s *stateSyncer) fetchHeader(
+	ctx context.Context, shardId types.ShardId, hash common.Hash,
+) (*stateSyncHeader, error) {
+	for _, peer := range s.getPeers() {
+		header, err := s.requestHeader(ctx, peer, &StateSyncHeaderRequest{ShardId: shardId, Hash: hash})
+		if err == nil && header != nil && header.hash != hash {
+			err = fmt.Errorf("%w: got block %s instead of %s", errInvalidStateSyncData, header.hash, hash)
+		}
+		if errors.Is(err, errInvalidStateSyncData) {
+			s.dropPeer(peer, err)
+			continue
+		}
+		if err != nil {
+			s.logger.Debug().Err(err).Stringer(logging.FieldPeerId, peer).Msg("Failed to fetch block header")
+			continue
+		}
+		if header != nil {
+			return header, nil
+		}
+	}
+	return nil, fmt.Errorf("block %s of shard %s is not available from any peer", hash, shardId)
+}
+
+// choosePivot returns the configured pivot, the pivot of the interrupted sync,
+// or the latest main shard block the quorum of peers agrees on.
+func (s *stateSyncer) choosePivot(ctx context.Context) (common.Hash, error) {
+	if !s.config.PivotHash.Empty() {
+		return s.config.PivotHash, nil
+	}
+
+	tx, err := s.db.CreateRoTx(ctx)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	defer tx.Rollback()
+
+	pivot, err := db.ReadStateSyncPivot(tx)
+	if err == nil {
+		s.logger.Info().Stringer(logging.FieldBlockHash, pivot).Msg("Resuming interrupted state sync")
+		return pivot, nil
+	}
+	if !errors.Is(err, db.ErrKeyNotFound) {
+		return common.EmptyHash, err
+	}
+
+	type peerHead struct {
+		peer   network.PeerID
+		number types.BlockNumber
+	}
+	var heads []peerHead
+	for _, peer := range s.getPeers() {
+		header, err := s.requestHeader(ctx, peer, &StateSyncHeaderRequest{ShardId: types.MainShardId, Latest: true})
+		if err != nil || header == nil {
+			continue
+		}
+		heads = append(heads, peerHead{peer, header.block.Id})
+	}
+	if len(heads) < s.config.Quorum {
+		return common.EmptyHash, fmt.Errorf(
+			"only %d peers reported their heads, while quorum is %d", len(heads), s.config.Quorum)
+	}
+
+	// The highest block that is known to the quorum of peers.
+	sort.Slice(heads, func(i, j int) bool { return heads[i].number > heads[j].number })
+	number := heads[s.config.Quorum-1].number
+
+	votes := make(map[common.Hash]int)
+	answers := 0
+	for _, head := range heads {
+		if head.number < number {
+			continue
+		}
+		req := &StateSyncHeaderRequest{ShardId: types.MainShardId, Number: number.Uint64()}
+		header, err := s.requestHeader(ctx, head.peer, req)
+		if err != nil || header == nil {
+			continue
+		}
+		answers++
+		votes[header.hash]++
+	}
+	for hash, n := range votes {
+		if n >= s.config.Quorum && 2*n > answers {
+			return hash, nil
+		}
+	}
+	return common.EmptyHash, fmt.Errorf("peers do not agree on main shard block %d", number)
+}
+
+func (s *stateSyncer) writePivot(ctx context.Context, pivotHash common.Hash) error {
+	tx, err := s.db.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := db.WriteStateSyncPivot(tx, pivotHash); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// syncMainChain downloads the main shard blocks from the pivot down to the genesis block with their configs
+// and verifies the chain.
+func (s *stateSyncer) syncMainChain(ctx context.Context, pivot *stateSyncHeader, genesisHash common.Hash) error {
+	if err := s.fetchMainChain(ctx, pivot, genesisHash); err != nil {
+		return err
+	}
+	return s.verifyMainChain(ctx, pivot.block.Id)
+}
+
+// fetchMainChain downloads the main shard blocks that precede the pivot in windows.
+// The blocks of a window are requested by number from different peers in parallel and checked
+// to be linked by hashes with the already downloaded ones, the unlinked blocks are requested by hash.
+// The chain must end with the local genesis block.
+func (s *stateSyncer) fetchMainChain(ctx context.Context, pivot *stateSyncHeader, genesisHash common.Hash) error {
+	if err := s.writeMainHeaders(ctx, []*stateSyncHeader{pivot}); err != nil {
+		return err
+	}
+
+	window := types.BlockNumber(s.config.ChunkSize * cap(s.requests))
+	next, nextHash := pivot.block, pivot.hash
+	for next.Id > 0 {
+		// The blocks stored by the interrupted sync are not downloaded again.
+		var err error
+		if next, nextHash, err = s.storedAncestor(ctx, next, nextHash); err != nil {
+			return err
+		}
+		if next.Id == 0 {
+			break
+		}
+
+		from := next.Id - min(next.Id, window)
+		headers := s.fetchMainHeadersByNumber(ctx, from, next.Id)
+		chain := make([]*stateSyncHeader, 0, len(headers))
+		for i := len(headers) - 1; i >= 0; i-- {
+			h := headers[i]
+			if h == nil || h.hash != next.PrevBlock {
+				if h, err = s.fetchHeader(ctx, types.MainShardId, next.PrevBlock); err != nil {
+					return err
+				}
+			}
+			if h.block.Id+1 != next.Id {
+				return fmt.Errorf("%w: block %s has number %d instead of %d",
+					errInvalidStateSyncChain, h.hash, h.block.Id, next.Id-1)
+			}
+			chain = append(chain, h)
+			next, nextHash = h.block, h.hash
+		}
+		if err := s.writeMainHeaders(ctx, chain); err != nil {
+			return err
+		}
+	}
+
+	if nextHash != genesisHash {
+		return fmt.Errorf("%w: the chain starts with block %s instead of the genesis block %s",
+			errInvalidStateSyncChain, nextHash, genesisHash)
+	}
+	return nil
+}
+
+// storedAncestor returns the earliest ancestor of the block that is stored by the interrupted sync.
+// The number index of the stored blocks is rewritten, since it could be overwritten by the chain of another pivot.
+func (s *stateSyncer) storedAncestor(
+	ctx context.Context, block *types.Block, hash common.Hash,
+) (*types.Block, common.Hash, error) {
+	tx, err := s.db.CreateRwTx(ctx)
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+	defer tx.Rollback()
+
+	for block.Id > 0 {
+		prev, err := db.ReadBlock(tx, types.MainShardId, block.PrevBlock)
+		if errors.Is(err, db.ErrKeyNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, common.EmptyHash, err
+		}
+		if prev.Id+1 != block.Id {
+			return nil, common.EmptyHash, fmt.Errorf("%w: block %s has number %d instead of %d",
+				errInvalidStateSyncChain, block.PrevBlock, prev.Id, block.Id-1)
+		}
+		err = tx.PutToShard(types.MainShardId, db.BlockHashByNumberIndex, prev.Id.Bytes(), block.PrevBlock.Bytes())
+		if err != nil {
+			return nil, common.EmptyHash, err
+		}
+		block, hash = prev, block.PrevBlock
+	}
+	return block, hash, tx.Commit()
+}
+
+// fetchMainHeadersByNumber requests the main shard blocks in [from, to) by number.
+// The blocks that are not received are nil.
+func (s *stateSyncer) fetchMainHeadersByNumber(ctx context.Context, from, to types.BlockNumber) []*stateSyncHeader {
+	res := make([]*stateSyncHeader, to-from)
+	var wg sync.WaitGroup
+	for i := range res {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			req := &StateSyncHeaderRequest{ShardId: types.MainShardId, Number: uint64(from) + uint64(i)}
+			for _, peer := range s.getPeers() {
+				header, err := s.requestHeader(ctx, peer, req)
+				if errors.Is(err, errInvalidStateSyncData) {
+					s.dropPeer(peer, err)
+					continue
+				}
+				if err == nil && header != nil && header.block.Id.Uint64() == req.Number {
+					res[i] = header
+					return
+				}
+				if ctx.Err() != nil {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return res
+}
+
+// verifyMainChain downloads the configs of the stored main shard chain up to the pivot
+// and checks that every block is signed by the validators defined by the config of the block before its parent.
+func (s *stateSyncer) verifyMainChain(ctx context.Context, pivotId types.BlockNumber) error {
+	window := types.BlockNumber(s.config.ChunkSize * cap(s.requests))
+	for from := types.BlockNumber(0); from <= pivotId; from += window {
+		to := min(from+window, pivotId+1)
+
+		blocks, err := s.readMainBlocks(ctx, from, to)
+		if err != nil {
+			return err
+		}
+		roots := make([]stateSyncEntry, 0, len(blocks))
+		for _, block := range blocks {
+			roots = append(roots, stateSyncEntry{stateSyncConfigTrie, block.ConfigRoot})
+		}
+		if err := s.syncTries(ctx, types.MainShardId, roots); err != nil {
+			return err
+		}
+
+		if s.params.DisableConsensus {
+			continue
+		}
+		if err := s.verifyMainBlocks(ctx, blocks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *stateSyncer) readMainBlocks(ctx context.Context, from, to types.BlockNumber) ([]*types.Block, error) {
+	tx, err := s.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blocks := make([]*types.Block, 0, to-from)
+	for id := from; id < to; id++ {
+		block, err := db.ReadBlockByNumber(tx, types.MainShardId, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read main shard block %d: %w", id, err)
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+func (s *stateSyncer) verifyMainBlocks(ctx context.Context, blocks []*types.Block) error {
+	tx, err := s.db.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, block := range blocks {
+		if block.Id == 0 {
+			continue
+		}
+		// The same validators are used by the consensus, see config.GetConfigParams.
+		prev, err := db.ReadBlockByNumber(tx, types.MainShardId, block.Id-1)
+		if err != nil {
+			return err
+		}
+		c, err := config.NewConfigAccessorFromBlockWithTx(tx, prev, types.MainShardId)
+		if err != nil {
+			return err
+		}
+		validators, err := config.GetShardValidators(c, types.MainShardId)
+		if err != nil {
+			return err
+		}
+		keys, err := config.CreateValidatorsPublicKeyMap(validators)
+		if err != nil {
+			return err
+		}
+		if err := block.VerifySignature(keys.Keys(), types.MainShardId); err != nil {
+			return fmt.Errorf("%w: failed to verify main shard block %d: %w", errInvalidStateSyncChain, block.Id, err)
+		}
+	}
+	return nil
+}
+
+// fetchShardHeaders fetches the blocks of other shards the pivot block refers to.
+func (s *stateSyncer) fetchShardHeaders(ctx context.Context, pivot *stateSyncHeader) ([]*stateSyncHeader, error) {
+	tx, err := s.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	shardHeaders := make([]*stateSyncHeader, 0, len(pivot.childBlocks))
+	for i, hash := range pivot.childBlocks {
+		header, err := s.fetchHeader(ctx, types.ShardId(i+1), hash)
+		if err != nil {
+			return nil, err
+		}
+		shardHeaders = append(shardHeaders, header)
+
+		// A shard block is verified with the config of the main shard block its parent refers to,
+		// which must be a block of the verified chain.
+		if !header.block.MainShardHash.Empty() {
+			if _, err := db.ReadBlock(tx, types.MainShardId, header.block.MainShardHash); err != nil {
+				return nil, fmt.Errorf("main shard block %s of shard %s block %s is not synced: %w",
+					header.block.MainShardHash, types.ShardId(i+1), header.hash, err)
+			}
+		}
+	}
+	return shardHeaders, nil
+}
+
+func writeStateSyncBlock(tx db.RwTx, shardId types.ShardId, header *stateSyncHeader) error {
+	block := header.block
+	if err := db.WriteBlock(tx, shardId, header.hash, block); err != nil {
+		return err
+	}
+	if err := tx.PutToShard(shardId, db.BlockHashByNumberIndex, block.Id.Bytes(), header.hash.Bytes()); err != nil {
+		return err
+	}
+	if err := db.WriteBlockLogsBloom(tx, shardId, block.Id, header.hash, block.LogsBloom); err != nil {
+		return err
+	}
+
+	if len(header.childBlocks) > 0 {
+		shards := make(map[types.ShardId]common.Hash, len(header.childBlocks))
+		for i, h := range header.childBlocks {
+			shards[types.ShardId(i+1)] = h
+		}
+		trie := execution.NewDbShardBlocksTrie(tx, shardId, block.Id)
+		if err := execution.UpdateFromMap(trie, shards, func(v common.Hash) *common.Hash { return &v }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *stateSyncer) deletePivot(ctx context.Context) error {
+	tx, err := s.db.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := db.DeleteStateSyncPivot(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *stateSyncer) writeMainHeaders(ctx context.Context, headers []*stateSyncHeader) error {
+	tx, err := s.db.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, h := range headers {
+		if err := writeStateSyncBlock(tx, types.MainShardId, h); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// syncTries downloads the tries with the given roots along with the contracts' tries and codes.
+func (s *stateSyncer) syncTries(ctx context.Context, shardId types.ShardId, roots []stateSyncEntry) error {
+	seen := make(map[stateSyncEntry]struct{})
+	var stack []stateSyncEntry
+	push := func(e stateSyncEntry) {
+		if e.key.Empty() {
+			return
+		}
+		if _, ok := seen[e]; ok {
+			return
+		}
+		seen[e] = struct{}{}
+		stack = append(stack, e)
+	}
+	for _, root := range roots {
+		push(root)
+	}
+
+	batchSize := s.config.ChunkSize * cap(s.requests)
+	for len(stack) > 0 {
+		n := min(len(stack), batchSize)
+		batch := slices.Clone(stack[len(stack)-n:])
+		stack = stack[:len(stack)-n]
+
+		data, err := s.loadEntries(ctx, shardId, batch)
+		if err != nil {
+			return err
+		}
+		for i, e := range batch {
+			if err := visitStateSyncEntry(e.trie, data[i], push); err != nil {
+				return fmt.Errorf("failed to process entry %s of shard %s: %w", e.key, shardId, err)
+			}
+		}
+
+		s.logger.Debug().
+			Stringer(logging.FieldShardId, shardId).
+			Int("processed", len(seen)-len(stack)).
+			Int("pending", len(stack)).
+			Msg("State sync progress")
+	}
+
+	s.logger.Info().
+		Stringer(logging.FieldShardId, shardId).
+		Int("entries", len(seen)).
+		Msg("Shard tries synced")
+	return nil
+}
+
+func visitStateSyncEntry(trie stateSyncTrie, data []byte, push func(stateSyncEntry)) error {
+	if trie == stateSyncCode {
+		return nil
+	}
+	node, err := mpt.DecodeNode(data)
+	if err != nil {
+		return err
+	}
+	return visitStateSyncNode(trie, node, push)
+}
+
+func visitStateSyncNode(trie stateSyncTrie, node mpt.Node, push func(stateSyncEntry)) error {
+	var refs []mpt.Reference
+	var value []byte
+	switch n := node.(type) {
+	case *mpt.LeafNode:
+		value = n.LeafData
+	case *mpt.ExtensionNode:
+		refs = []mpt.Reference{n.NextRef}
+	case *mpt.BranchNode:
+		refs = n.Branches[:]
+		value = n.Value
+	}
+
+	for _, ref := range refs {
+		switch {
+		case !ref.IsValid():
+		case len(ref) < common.HashSize:
+			// Short nodes are embedded into their parents.
+			child, err := mpt.DecodeNode(ref)
+			if err != nil {
+				return err
+			}
+			if err := visitStateSyncNode(trie, child, push); err != nil {
+				return err
+			}
+		default:
+			push(stateSyncEntry{trie, common.BytesToHash(ref)})
+		}
+	}
+
+	if trie == stateSyncContractTrie && len(value) > 0 {
+		var contract types.SmartContract
+		if err := contract.UnmarshalSSZ(value); err != nil {
+			return err
+		}
+		push(stateSyncEntry{stateSyncStorageTrie, contract.StorageRoot})
+		push(stateSyncEntry{stateSyncTokenTrie, contract.TokenRoot})
+		push(stateSyncEntry{stateSyncAsyncContextTrie, contract.AsyncContextRoot})
+		push(stateSyncEntry{stateSyncCode, contract.CodeHash})
+	}
+	return nil
+}
+
+// loadEntries reads the entries that are already stored locally and downloads the rest.
+func (s *stateSyncer) loadEntries(
+	ctx context.Context, shardId types.ShardId, batch []stateSyncEntry,
+) ([][]byte, error) {
+	data := make([][]byte, len(batch))
+	missing := make(map[stateSyncTrie][]int)
+	if err := func() error {
+		tx, err := s.db.CreateRoTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		for i, e := range batch {
+			data[i], err = readStateSyncEntry(tx, shardId, e.trie, e.key)
+			if errors.Is(err, db.ErrKeyNotFound) {
+				missing[e.trie] = append(missing[e.trie], i)
+			} else if err != nil {
+				return err
+			}
+		}
+		return nil
+	}(); err != nil {
+		return nil, err
+	}
+	if len(missing) == 0 {
+		return data, nil
+	}
+
+	eg, gctx := errgroup.WithContext(ctx)
+	for trie, indices := range missing {
+		for chunk := range slices.Chunk(indices, s.config.ChunkSize) {
+			eg.Go(func() error {
+				keys := make([]common.Hash, len(chunk))
+				for i, idx := range chunk {
+					keys[i] = batch[idx].key
+				}
+				nodes, err := s.fetchEntries(gctx, shardId, trie, keys)
+				if err != nil {
+					return err
+				}
+				for i, idx := range chunk {
+					data[idx] = nodes[i]
+				}
+				return nil
+			})
+		}
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.CreateRwTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for trie, indices := range missing {
+		for _, idx := range indices {
+			if err := writeStateSyncEntry(tx, shardId, trie, batch[idx].key, data[idx]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// fetchEntries requests the entries from the peers one by one until all of them are received.
+func (s *stateSyncer) fetchEntries(
+	ctx context.Context, shardId types.ShardId, trie stateSyncTrie, keys []common.Hash,
+) ([][]byte, error) {
+	res := make([][]byte, len(keys))
+	missing := make([]int, len(keys))
+	for i := range keys {
+		missing[i] = i
+	}
+
+	for _, peer := range s.getPeers() {
+		req := &StateSyncNodesRequest{ShardId: shardId, Trie: trie, Keys: make([]common.Hash, len(missing))}
+		for i, idx := range missing {
+			req.Keys[i] = keys[idx]
+		}
+
+		nodes, err := s.requestEntries(ctx, peer, req)
+		if errors.Is(err, errInvalidStateSyncData) {
+			s.dropPeer(peer, err)
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			s.logger.Debug().Err(err).Stringer(logging.FieldPeerId, peer).Msg("Failed to fetch state entries")
+			continue
+		}
+
+		rest := make([]int, 0, len(missing))
+		for i, idx := range missing {
+			if len(nodes[i]) == 0 {
+				rest = append(rest, idx)
+				continue
+			}
+			res[idx] = nodes[i]
+		}
+		if missing = rest; len(missing) == 0 {
+			return res, nil
+		}
+	}
+	return nil, fmt.Errorf("%d entries of shard %s are not available from any peer", len(missing), shardId)
+}
+
+func (s *stateSyncer) requestEntries(
+	ctx context.Context, peer network.PeerID, req *StateSyncNodesRequest,
+) ([][]byte, error) {
+	data, err := req.MarshalSSZ()
+	if err != nil {
+		return nil, err
+	}
+	data, err = s.request(ctx, peer, protocolStateSyncNodes(), data)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp StateSyncNodesResponse
+	if err := resp.UnmarshalSSZ(data); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidStateSyncData, err)
+	}
+	if len(resp.Nodes) != len(req.Keys) {
+		return nil, fmt.Errorf("%w: got %d entries instead of %d",
+			errInvalidStateSyncData, len(resp.Nodes), len(req.Keys))
+	}
+	for i, node := range resp.Nodes {
+		if len(node) > 0 && !checkStateSyncEntry(req.Trie, req.Keys[i], node) {
+			return nil, fmt.Errorf("%w: entry %s does not match its hash", errInvalidStateSyncData, req.Keys[i])
+		}
+	}
+	return resp.Nodes, nil
+}
+
+// checkStateSyncEntry checks that the entry is stored under the hash of its contents.
+func checkStateSyncEntry(trie stateSyncTrie, key common.Hash, data []byte) bool {
+	if trie == stateSyncCode {
+		return types.Code(data).Hash() == key
+	}
+	if common.KeccakHash(data) != key {
+		// Short root nodes are stored under the root hash, which is the node itself widened to 32 bytes.
+		if len(data) >= common.HashSize || common.BytesToHash(data) != key {
+			return false
+		}
+	}
+	_, err := mpt.DecodeNode(data)
+	return err == nil
+}
+
+// finish writes the blocks of the shards and makes the pivot blocks the last ones.
+func (s *stateSyncer) finish(ctx context.Context, pivot *stateSyncHeader, shardHeaders []*stateSyncHeader) error {
+	tx, err := s.db.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, h := range shardHeaders {
+		shardId := types.ShardId(i + 1)
+		if err := writeStateSyncBlock(tx, shardId, h); err != nil {
+			return err
+		}
+		if err := db.WriteLastBlockHash(tx, shardId, h.hash); err != nil {
+			return err
+		}
+	}
+	if err := db.WriteLastBlockHash(tx, types.MainShardId, pivot.hash); err != nil {
+		return err
+	}
+	if err := db.DeleteStateSyncPivot(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,166 @@
+package collate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+func protocolStateSyncHeader() network.ProtocolID {
+	return "/nil/statesync/header"
+}
+
+func protocolStateSyncNodes() network.ProtocolID {
+	return "/nil/statesync/nodes"
+}
+
+func stateSyncTable(trie stateSyncTrie) (db.ShardedTableName, error) {
+	switch trie {
+	case stateSyncContractTrie:
+		return db.ContractTrieTable, nil
+	case stateSyncStorageTrie:
+		return db.StorageTrieTable, nil
+	case stateSyncTokenTrie:
+		return db.TokenTrieTable, nil
+	case stateSyncAsyncContextTrie:
+		return db.AsyncCallContextTable, nil
+	case stateSyncConfigTrie:
+		return db.ConfigTrieTable, nil
+	case stateSyncTransactionTrie:
+		return db.TransactionTrieTable, nil
+	case stateSyncReceiptTrie:
+		return db.ReceiptTrieTable, nil
+	}
+	return "", fmt.Errorf("unknown state sync trie %d", trie)
+}
+
+func readStateSyncEntry(tx db.RoTx, shardId types.ShardId, trie stateSyncTrie, key common.Hash) ([]byte, error) {
+	if trie == stateSyncCode {
+		return db.ReadCode(tx, shardId, key)
+	}
+	table, err := stateSyncTable(trie)
+	if err != nil {
+		return nil, err
+	}
+	return tx.GetFromShard(shardId, table, key.Bytes())
+}
+
+func writeStateSyncEntry(tx db.RwTx, shardId types.ShardId, trie stateSyncTrie, key common.Hash, data []byte) error {
+	if trie == stateSyncCode {
+		return db.WriteCode(tx, shardId, key, data)
+	}
+	table, err := stateSyncTable(trie)
+	if err != nil {
+		return err
+	}
+	return tx.PutToShard(shardId, table, key.Bytes(), data)
+}
+
+func handleStateSyncHeaderRequest(
+	tx db.RoTx, accessor *execution.StateAccessor, req *StateSyncHeaderRequest,
+) (*StateSyncHeaderResponse, error) {
+	hash := req.Hash
+	if hash.Empty() {
+		var err error
+		if req.Latest {
+			hash, err = db.ReadLastBlockHash(tx, req.ShardId)
+		} else {
+			hash, err = db.ReadBlockHashByNumber(tx, req.ShardId, types.BlockNumber(req.Number))
+		}
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return &StateSyncHeaderResponse{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	acc := accessor.RawAccess(tx, req.ShardId).GetBlock()
+	if req.ShardId.IsMainShard() {
+		acc = acc.WithChildBlocks()
+	}
+	block, err := acc.ByHash(hash)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return &StateSyncHeaderResponse{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &StateSyncHeaderResponse{Block: block.Block()}
+	if req.ShardId.IsMainShard() {
+		resp.ChildBlocks = block.ChildBlocks()
+	}
+	return resp, nil
+}
+
+func handleStateSyncNodesRequest(tx db.RoTx, req *StateSyncNodesRequest) (*StateSyncNodesResponse, error) {
+	resp := &StateSyncNodesResponse{Nodes: make([][]byte, len(req.Keys))}
+	for i, key := range req.Keys {
+		data, err := readStateSyncEntry(tx, req.ShardId, req.Trie, key)
+		if errors.Is(err, db.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp.Nodes[i] = data
+	}
+	return resp, nil
+}
+
+// SetStateSyncHandlers sets the handlers that serve block headers and state trie nodes to the syncing peers.
+func SetStateSyncHandlers(ctx context.Context, nm network.Manager, database db.DB) {
+	logger := logging.NewLogger("state_sync").With().
+		Stringer(logging.FieldP2PIdentity, nm.ID()).
+		Logger()
+
+	// Sharing accessor between all handlers enables caching.
+	accessor := execution.NewStateAccessor()
+	nm.SetRequestHandler(ctx, protocolStateSyncHeader(), func(ctx context.Context, data []byte) ([]byte, error) {
+		var req StateSyncHeaderRequest
+		if err := req.UnmarshalSSZ(data); err != nil {
+			return nil, err
+		}
+
+		tx, err := database.CreateRoTx(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		resp, err := handleStateSyncHeaderRequest(tx, accessor, &req)
+		if err != nil {
+			return nil, err
+		}
+		return resp.MarshalSSZ()
+	})
+
+	nm.SetRequestHandler(ctx, protocolStateSyncNodes(), func(ctx context.Context, data []byte) ([]byte, error) {
+		var req StateSyncNodesRequest
+		if err := req.UnmarshalSSZ(data); err != nil {
+			return nil, err
+		}
+
+		tx, err := database.CreateRoTx(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		resp, err := handleStateSyncNodesRequest(tx, &req)
+		if err != nil {
+			return nil, err
+		}
+		return resp.MarshalSSZ()
+	})
+
+	logger.Info().Msg("Enabled state sync endpoints")
+}
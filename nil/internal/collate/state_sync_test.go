@@ -0,0 +1,404 @@
+package collate
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type StateSyncTestSuite struct {
+	suite.Suite
+
+	serverDb db.DB
+	clientDb db.DB
+
+	validatorKey bls.PrivateKey
+
+	mainAddress  types.Address
+	shardAddress types.Address
+
+	genesisHash common.Hash
+	pivotHash   common.Hash
+	shardHash   common.Hash
+}
+
+func (s *StateSyncTestSuite) SetupTest() {
+	var err error
+	s.serverDb, err = db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+	s.clientDb, err = db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+
+	s.validatorKey = bls.NewRandomKey()
+	s.mainAddress = types.GenerateRandomAddress(types.MainShardId)
+	s.shardAddress = types.GenerateRandomAddress(1)
+
+	s.genesisHash = s.generateBlock(types.MainShardId, 0, common.EmptyHash, s.fillGenesis)
+	shardGenesisHash := s.generateBlock(1, 0, common.EmptyHash, func(es *execution.ExecutionState) {
+		s.fillContract(es, s.shardAddress, 300)
+		s.Require().NoError(es.AddToken(s.shardAddress, types.TokenId(s.mainAddress), types.NewValueFromUint64(5)))
+	})
+	s.shardHash = s.generateBlock(1, 1, shardGenesisHash, func(es *execution.ExecutionState) {
+		es.MainShardHash = s.genesisHash
+		s.Require().NoError(es.SetState(s.shardAddress, common.HexToHash("0x01"), common.HexToHash("0xff")))
+		s.addTransaction(es, s.shardAddress)
+	})
+
+	prevHash := s.generateBlock(types.MainShardId, 1, s.genesisHash, func(es *execution.ExecutionState) {
+		es.ChildShardBlocks = map[types.ShardId]common.Hash{1: shardGenesisHash}
+	})
+	prevHash = s.generateBlock(types.MainShardId, 2, prevHash, func(es *execution.ExecutionState) {
+		es.ChildShardBlocks = map[types.ShardId]common.Hash{1: s.shardHash}
+		s.addTransaction(es, s.mainAddress)
+	})
+	s.pivotHash = s.generateBlock(types.MainShardId, 3, prevHash, func(es *execution.ExecutionState) {
+		es.ChildShardBlocks = map[types.ShardId]common.Hash{1: s.shardHash}
+		s.Require().NoError(es.SetState(s.mainAddress, common.HexToHash("0x02"), common.HexToHash("0xee")))
+	})
+}
+
+func (s *StateSyncTestSuite) TearDownTest() {
+	s.serverDb.Close()
+	s.clientDb.Close()
+}
+
+func (s *StateSyncTestSuite) generateBlock(
+	shardId types.ShardId, blockId types.BlockNumber, prevHash common.Hash, fill func(es *execution.ExecutionState),
+) common.Hash {
+	s.T().Helper()
+
+	return s.generateSignedBlock(s.serverDb, s.validatorKey, shardId, blockId, prevHash, fill)
+}
+
+// generateSignedBlock generates the block in the database and signs it by the key as the only validator would.
+func (s *StateSyncTestSuite) generateSignedBlock(
+	database db.DB,
+	key bls.PrivateKey,
+	shardId types.ShardId,
+	blockId types.BlockNumber,
+	prevHash common.Hash,
+	fill func(es *execution.ExecutionState),
+) common.Hash {
+	s.T().Helper()
+
+	tx, err := database.CreateRwTx(s.T().Context())
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	var prevBlock *types.Block
+	if !prevHash.Empty() {
+		prevBlock, err = db.ReadBlock(tx, shardId, prevHash)
+		s.Require().NoError(err)
+	}
+
+	configAccessor := config.GetStubAccessor()
+	if shardId.IsMainShard() {
+		configAccessor = config.NewConfigAccessorFromMap(make(map[string][]byte))
+	}
+	es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+		Block:          prevBlock,
+		ConfigAccessor: configAccessor,
+	})
+	s.Require().NoError(err)
+	fill(es)
+
+	blockRes, err := es.BuildBlock(blockId)
+	s.Require().NoError(err)
+	sig, err := key.Sign(blockRes.BlockHash.Bytes())
+	s.Require().NoError(err)
+	mask, err := bls.NewMask([]bls.PublicKey{key.PublicKey()})
+	s.Require().NoError(err)
+	s.Require().NoError(mask.SetParticipants([]uint32{0}))
+	sig, err = bls.AggregateSignatures([]bls.Signature{sig}, mask)
+	s.Require().NoError(err)
+	sigBytes, err := sig.Marshal()
+	s.Require().NoError(err)
+	params := &types.ConsensusParams{Signature: &types.BlsAggregateSignature{Sig: sigBytes, Mask: []byte{1}}}
+	s.Require().NoError(es.CommitBlock(blockRes, params))
+
+	s.Require().NoError(execution.PostprocessBlock(tx, shardId, blockRes, execution.ModeVerify))
+	s.Require().NoError(tx.Commit())
+	return blockRes.BlockHash
+}
+
+func (s *StateSyncTestSuite) setValidator(es *execution.ExecutionState, key bls.PrivateKey) {
+	s.T().Helper()
+
+	pubkey, err := key.PublicKey().Marshal()
+	s.Require().NoError(err)
+	var validator config.ValidatorInfo
+	copy(validator.PublicKey[:], pubkey)
+	s.Require().NoError(config.SetParamValidators(es.GetConfigAccessor(), &config.ParamValidators{
+		Validators: []config.ListValidators{{List: []config.ValidatorInfo{validator}}},
+	}))
+}
+
+func (s *StateSyncTestSuite) fillGenesis(es *execution.ExecutionState) {
+	s.T().Helper()
+
+	s.fillContract(es, s.mainAddress, 10)
+	s.setValidator(es, s.validatorKey)
+}
+
+func (s *StateSyncTestSuite) fillContract(es *execution.ExecutionState, addr types.Address, nSlots int) {
+	s.T().Helper()
+
+	s.Require().NoError(es.CreateAccount(addr))
+	s.Require().NoError(es.SetCode(addr, addr.Bytes()))
+	s.Require().NoError(es.SetBalance(addr, types.NewValueFromUint64(1000)))
+	for i := range nSlots {
+		key := common.BytesToHash([]byte{byte(i >> 8), byte(i)})
+		s.Require().NoError(es.SetState(addr, key, common.KeccakHash(key.Bytes())))
+	}
+}
+
+func (s *StateSyncTestSuite) addTransaction(es *execution.ExecutionState, addr types.Address) {
+	s.T().Helper()
+
+	txn := &types.Transaction{TransactionDigest: types.TransactionDigest{To: addr}, From: addr}
+	txn.TxId = es.InTxCounts[txn.From.ShardId()]
+	es.AddInTransaction(txn)
+	es.AddReceipt(execution.NewExecutionResult())
+}
+
+func (s *StateSyncTestSuite) newStateSyncer(nm network.Manager, quorum int) *stateSyncer {
+	s.T().Helper()
+
+	cfg := &StateSyncConfig{
+		Enabled:         true,
+		Quorum:          quorum,
+		ChunkSize:       16,
+		RequestsPerPeer: 2,
+	}
+	params := execution.BlockGeneratorParams{NShards: 2}
+	return newStateSyncer(cfg, params, s.clientDb, nm, logging.NewLogger("state_sync_test"))
+}
+
+func (s *StateSyncTestSuite) checkSynced() {
+	s.T().Helper()
+
+	tx, err := s.clientDb.CreateRoTx(s.T().Context())
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	_, err = db.ReadStateSyncPivot(tx)
+	s.Require().ErrorIs(err, db.ErrKeyNotFound)
+
+	genesisHash, err := db.ReadBlockHashByNumber(tx, types.MainShardId, 0)
+	s.Require().NoError(err)
+	s.Equal(s.genesisHash, genesisHash)
+
+	serverTx, err := s.serverDb.CreateRoTx(s.T().Context())
+	s.Require().NoError(err)
+	defer serverTx.Rollback()
+
+	for shardId, addr := range map[types.ShardId]types.Address{
+		types.MainShardId: s.mainAddress,
+		1:                 s.shardAddress,
+	} {
+		lastHash, err := db.ReadLastBlockHash(tx, shardId)
+		s.Require().NoError(err)
+		if shardId.IsMainShard() {
+			s.Require().Equal(s.pivotHash, lastHash)
+		} else {
+			s.Require().Equal(s.shardHash, lastHash)
+		}
+
+		readState := func(tx db.RoTx) *execution.ExecutionState {
+			block, err := db.ReadBlock(tx, shardId, lastHash)
+			s.Require().NoError(err)
+			es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+				Block:          block,
+				ConfigAccessor: config.GetStubAccessor(),
+			})
+			s.Require().NoError(err)
+			return es
+		}
+		expected, actual := readState(serverTx), readState(tx)
+
+		expectedAcc, err := expected.GetAccount(addr)
+		s.Require().NoError(err)
+		actualAcc, err := actual.GetAccount(addr)
+		s.Require().NoError(err)
+		s.Require().NotNil(actualAcc)
+		s.Equal(expectedAcc.Balance, actualAcc.Balance)
+
+		code, _, err := actual.GetCode(addr)
+		s.Require().NoError(err)
+		s.Equal(addr.Bytes(), code)
+
+		for i := range 10 {
+			key := common.BytesToHash([]byte{0, byte(i)})
+			expectedVal, err := expected.GetState(addr, key)
+			s.Require().NoError(err)
+			actualVal, err := actual.GetState(addr, key)
+			s.Require().NoError(err)
+			s.Equal(expectedVal, actualVal)
+		}
+	}
+
+	// The synced node serves the same blocks as the peers.
+	req := &StateSyncHeaderRequest{ShardId: types.MainShardId, Latest: true}
+	expected, err := handleStateSyncHeaderRequest(serverTx, execution.NewStateAccessor(), req)
+	s.Require().NoError(err)
+	actual, err := handleStateSyncHeaderRequest(tx, execution.NewStateAccessor(), req)
+	s.Require().NoError(err)
+	s.Equal(expected, actual)
+
+	// The transactions of the pivot blocks are available.
+	block, err := db.ReadBlock(tx, 1, s.shardHash)
+	s.Require().NoError(err)
+	txnTrie := execution.NewDbTransactionTrieReader(tx, 1)
+	txnTrie.SetRootHash(block.InTransactionsRoot)
+	txns, err := txnTrie.Values()
+	s.Require().NoError(err)
+	s.Len(txns, 1)
+}
+
+func (s *StateSyncTestSuite) TestSync() {
+	ctx := s.T().Context()
+
+	nms := network.NewTestManagers(ctx, s.T(), 9300, 3)
+	client, good, bad := nms[0], nms[1], nms[2]
+	defer func() {
+		for _, nm := range nms {
+			nm.Close()
+		}
+	}()
+
+	SetStateSyncHandlers(ctx, good, s.serverDb)
+	SetStateSyncHandlers(ctx, bad, s.serverDb)
+	// The bad peer serves correct headers, but corrupts the state.
+	bad.SetRequestHandler(ctx, protocolStateSyncNodes(), func(ctx context.Context, data []byte) ([]byte, error) {
+		var req StateSyncNodesRequest
+		s.Require().NoError(req.UnmarshalSSZ(data))
+
+		tx, err := s.serverDb.CreateRoTx(ctx)
+		s.Require().NoError(err)
+		defer tx.Rollback()
+
+		resp, err := handleStateSyncNodesRequest(tx, &req)
+		s.Require().NoError(err)
+		for _, node := range resp.Nodes {
+			if len(node) > 0 {
+				node[len(node)-1] ^= 0xff
+			}
+		}
+		return resp.MarshalSSZ()
+	})
+
+	syncer := s.newStateSyncer(client, 2)
+	peers := []network.AddrInfo{network.CalcAddress(good), network.CalcAddress(bad)}
+	s.Require().NoError(syncer.Sync(ctx, peers, s.genesisHash))
+
+	s.checkSynced()
+	s.Equal([]network.PeerID{good.ID()}, syncer.peers)
+}
+
+func (s *StateSyncTestSuite) TestResume() {
+	ctx := s.T().Context()
+
+	nms := network.NewTestManagers(ctx, s.T(), 9310, 3)
+	client, server, other := nms[0], nms[1], nms[2]
+	defer func() {
+		for _, nm := range nms {
+			nm.Close()
+		}
+	}()
+
+	SetStateSyncHandlers(ctx, server, s.serverDb)
+	peers := []network.AddrInfo{network.CalcAddress(server)}
+
+	// The sync is interrupted after the main shard is synced.
+	syncer := s.newStateSyncer(client, 1)
+	syncer.connectPeers(ctx, peers)
+	syncer.requests = make(chan struct{}, 2)
+	s.Require().NoError(syncer.writePivot(ctx, s.pivotHash))
+	pivot, err := syncer.fetchHeader(ctx, types.MainShardId, s.pivotHash)
+	s.Require().NoError(err)
+	s.Require().NoError(syncer.syncMainChain(ctx, pivot, s.genesisHash))
+	roots := append(
+		[]stateSyncEntry{{stateSyncContractTrie, pivot.block.SmartContractsRoot}}, blockTrieRoots(pivot.block)...)
+	s.Require().NoError(syncer.syncTries(ctx, types.MainShardId, roots))
+
+	// The new peer knows nothing, but the sync is resumed from the stored pivot.
+	otherDb, err := db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+	defer otherDb.Close()
+	SetStateSyncHandlers(ctx, other, otherDb)
+
+	syncer = s.newStateSyncer(client, 1)
+	peers = append(peers, network.CalcAddress(other))
+	s.Require().NoError(syncer.Sync(ctx, peers, s.genesisHash))
+
+	s.checkSynced()
+	s.True(slices.Contains(syncer.peers, other.ID()))
+}
+
+func (s *StateSyncTestSuite) TestForgedPivot() {
+	ctx := s.T().Context()
+
+	nms := network.NewTestManagers(ctx, s.T(), 9320, 2)
+	client, forger := nms[0], nms[1]
+	defer func() {
+		for _, nm := range nms {
+			nm.Close()
+		}
+	}()
+
+	// The forger starts from the same genesis block, but replaces the validators with its own key.
+	forgedDb, err := db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+	defer forgedDb.Close()
+	forgerKey := bls.NewRandomKey()
+	genesisHash := s.generateSignedBlock(
+		forgedDb, s.validatorKey, types.MainShardId, 0, common.EmptyHash, s.fillGenesis)
+	s.Require().Equal(s.genesisHash, genesisHash)
+	prevHash := s.generateSignedBlock(
+		forgedDb, forgerKey, types.MainShardId, 1, genesisHash, func(es *execution.ExecutionState) {
+			s.setValidator(es, forgerKey)
+		})
+	for blockId := range types.BlockNumber(3) {
+		prevHash = s.generateSignedBlock(
+			forgedDb, forgerKey, types.MainShardId, blockId+2, prevHash, func(es *execution.ExecutionState) {
+				s.Require().NoError(es.SetBalance(s.mainAddress, types.NewValueFromUint64(1_000_000)))
+			})
+	}
+	SetStateSyncHandlers(ctx, forger, forgedDb)
+	peers := []network.AddrInfo{network.CalcAddress(forger)}
+
+	// The pivot is signed by the validators the forged chain defines, but the chain is checked from the genesis.
+	err = s.newStateSyncer(client, 1).Sync(ctx, peers, s.genesisHash)
+	s.Require().ErrorIs(err, errInvalidStateSyncChain)
+	s.Require().ErrorContains(err, "failed to verify main shard block 1")
+
+	// The chain that doesn't start with the local genesis block is rejected as well.
+	err = s.newStateSyncer(client, 1).Sync(ctx, peers, common.HexToHash("0x01"))
+	s.Require().ErrorIs(err, errInvalidStateSyncChain)
+
+	tx, err := s.clientDb.CreateRoTx(ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	// The next attempt chooses another pivot, and nothing is considered synced.
+	_, err = db.ReadStateSyncPivot(tx)
+	s.Require().ErrorIs(err, db.ErrKeyNotFound)
+	_, err = db.ReadLastBlockHash(tx, types.MainShardId)
+	s.Require().ErrorIs(err, db.ErrKeyNotFound)
+}
+
+func TestStateSync(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(StateSyncTestSuite))
+}
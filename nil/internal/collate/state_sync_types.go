@@ -0,0 +1,55 @@
+package collate
+
+import (
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// stateSyncTrie identifies the table the requested state entries are read from.
+type stateSyncTrie = uint8
+
+const (
+	stateSyncContractTrie stateSyncTrie = iota
+	stateSyncStorageTrie
+	stateSyncTokenTrie
+	stateSyncAsyncContextTrie
+	stateSyncConfigTrie
+	stateSyncTransactionTrie
+	stateSyncReceiptTrie
+	stateSyncCode
+)
+
+// maxStateSyncKeys limits the number of entries requested at once.
+const maxStateSyncKeys = 1024
+
+// StateSyncHeaderRequest requests a block header of the shard by hash.
+// If the hash is empty, the block is requested by number, or the latest one if Latest is set.
+type StateSyncHeaderRequest struct {
+	ShardId types.ShardId
+	Hash    common.Hash
+	Number  uint64
+	Latest  bool
+}
+
+// StateSyncHeaderResponse contains the SSZ-encoded block, which is empty if the block is not found.
+// For the main shard blocks, the hashes of the child blocks are also returned.
+type StateSyncHeaderResponse struct {
+	Block       []byte        `ssz-max:"1048576"`
+	ChildBlocks []common.Hash `ssz-max:"4096"`
+}
+
+// StateSyncNodesRequest requests the entries of the shard table by their hashes.
+// Trie nodes and contract codes are keyed by the hashes of their contents, so they can be checked by the requester.
+type StateSyncNodesRequest struct {
+	ShardId types.ShardId
+	Trie    stateSyncTrie
+	Keys    []common.Hash `ssz-max:"1024"`
+}
+
+// StateSyncNodesResponse contains the requested entries in the order of the keys.
+// The entries unknown to the peer are empty.
+type StateSyncNodesResponse struct {
+	Nodes [][]byte `ssz-max:"1024,100000000"`
+}
+
+//go:generate go run github.com/NilFoundation/fastssz/sszgen --path state_sync_types.go -include ../types/shard.go,../../common/hash.go,../../common/length.go --objs StateSyncHeaderRequest,StateSyncHeaderResponse,StateSyncNodesRequest,StateSyncNodesResponse
@@ -16,9 +16,12 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	cm "github.com/NilFoundation/nil/nil/internal/network/connection_manager"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
 	"github.com/multiformats/go-multistream"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -39,6 +42,7 @@ type SyncerConfig struct {
 	Timeout         time.Duration // pull blocks if no new blocks appear in the topic for this duration
 	BootstrapPeers  []network.AddrInfo
 	ZeroStateConfig *execution.ZeroStateConfig
+	StateSync       *StateSyncConfig
 }
 
 // every n-th block will be reported to info log (to avoid spamming)
@@ -57,6 +61,8 @@ type Syncer struct {
 	waitForSync *sync.WaitGroup
 
 	validator *Validator
+
+	downloader *blockDownloader
 }
 
 func NewSyncer(cfg *SyncerConfig, validator *Validator, db db.DB, networkManager network.Manager) (*Syncer, error) {
@@ -69,7 +75,7 @@ func NewSyncer(cfg *SyncerConfig, validator *Validator, db db.DB, networkManager
 		loggerCtx = loggerCtx.Stringer(logging.FieldP2PIdentity, networkManager.ID())
 	}
 
-	return &Syncer{
+	s := &Syncer{
 		config:         cfg,
 		topic:          topicShardBlocks(cfg.ShardId),
 		db:             db,
@@ -77,7 +83,14 @@ func NewSyncer(cfg *SyncerConfig, validator *Validator, db db.DB, networkManager
 		logger:         loggerCtx.Logger(),
 		waitForSync:    &waitForSync,
 		validator:      validator,
-	}, nil
+	}
+	s.downloader = newBlockDownloader(cfg.ShardId, networkManager, validator.GetLastBlock, s.saveBlock, s.logger)
+	return s, nil
+}
+
+// Progress returns the progress of the parallel download of the blocks.
+func (s *Syncer) Progress() SyncProgress {
+	return s.downloader.Progress()
 }
 
 func (s *Syncer) shardIsEmpty(ctx context.Context) (bool, error) {
@@ -150,7 +163,63 @@ func (s *Syncer) fetchRemoteVersion(ctx context.Context) (NodeVersion, error) {
 	return NodeVersion{}, fmt.Errorf("failed to fetch version from all peers; last error: %w", err)
 }
 
-func (s *Syncer) fetchSnapshot(ctx context.Context) error {
+func (s *Syncer) stateSyncEnabled() bool {
+	return s.config.StateSync != nil && s.config.StateSync.Enabled
+}
+
+// stateSyncInterrupted checks whether the state sync has not been completed.
+func (s *Syncer) stateSyncInterrupted(ctx context.Context) (bool, error) {
+	tx, err := s.db.CreateRoTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	_, err = db.ReadStateSyncPivot(tx)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// localGenesisHash generates the main shard zero state in memory to get the hash of the genesis block.
+func (s *Syncer) localGenesisHash(ctx context.Context) (common.Hash, error) {
+	memDb, err := db.NewBadgerDbInMemory()
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	defer memDb.Close()
+
+	params := s.config.BlockGeneratorParams
+	params.ShardId = types.MainShardId
+	gen, err := execution.NewBlockGenerator(ctx, params, memDb, nil)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	defer gen.Rollback()
+
+	block, err := gen.GenerateZeroState(s.config.ZeroStateConfig)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	return block.Hash(types.MainShardId), nil
+}
+
+func (s *Syncer) fetchSnapshot(ctx context.Context, genesisHash common.Hash) error {
+	if s.stateSyncEnabled() {
+		// The state is verified against the genesis block of the local zero state, not the one of the peers.
+		localGenesisHash, err := s.localGenesisHash(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to generate zero state: %w", err)
+		}
+		if localGenesisHash != genesisHash {
+			return fmt.Errorf("genesis block %s of the peers does not match the local zero state %s",
+				genesisHash, localGenesisHash)
+		}
+		return newStateSyncer(s.config.StateSync, s.config.BlockGeneratorParams, s.db, s.networkManager, s.logger).
+			Sync(ctx, s.config.BootstrapPeers, localGenesisHash)
+	}
+
 	var err error
 	for _, peer := range s.config.BootstrapPeers {
 		err = fetchSnapshot(ctx, s.networkManager, peer, s.db, s.logger)
@@ -197,10 +266,20 @@ func (s *Syncer) Init(ctx context.Context, allowDbDrop bool) error {
 
 	if version.GenesisBlockHash.Empty() {
 		s.logger.Info().Msg("Local version is empty. Fetching snapshot...")
-		return s.fetchSnapshot(ctx)
+		return s.fetchSnapshot(ctx, remoteVersion.GenesisBlockHash)
 	}
 
 	if version.GenesisBlockHash == remoteVersion.GenesisBlockHash {
+		if s.stateSyncEnabled() {
+			interrupted, err := s.stateSyncInterrupted(ctx)
+			if err != nil {
+				return err
+			}
+			if interrupted {
+				s.logger.Info().Msg("State sync was interrupted. Resuming...")
+				return s.fetchSnapshot(ctx, remoteVersion.GenesisBlockHash)
+			}
+		}
 		s.logger.Info().Msgf("Local version %s is up to date. Finished initialization", version)
 		return nil
 	}
@@ -214,7 +293,7 @@ func (s *Syncer) Init(ctx context.Context, allowDbDrop bool) error {
 		return fmt.Errorf("failed to drop db: %w", err)
 	}
 	s.logger.Info().Msg("DB dropped. Fetching snapshot...")
-	return s.fetchSnapshot(ctx)
+	return s.fetchSnapshot(ctx, remoteVersion.GenesisBlockHash)
 }
 
 // SetHandlers sets the handlers for generic (shard-independent) protocols.
@@ -227,6 +306,7 @@ func (s *Syncer) SetHandlers(ctx context.Context) error {
 	}
 
 	SetBootstrapHandler(ctx, s.networkManager, s.db)
+	SetStateSyncHandlers(ctx, s.networkManager, s.db)
 	return nil
 }
 
@@ -268,7 +348,7 @@ func (s *Syncer) Run(ctx context.Context) error {
 			s.logger.Debug().Msg("Syncer is terminated")
 			return nil
 		case msg := <-ch:
-			saved, err := s.processTopicTransaction(ctx, msg.Data)
+			saved, err := s.processTopicTransaction(msg.Context(ctx), msg.Data)
 			if err != nil {
 				if errors.As(err, new(invalidSignatureError)) {
 					peerReputationTracker := network.TryGetPeerReputationTracker(s.networkManager)
@@ -291,7 +371,14 @@ func (s *Syncer) Run(ctx context.Context) error {
 	}
 }
 
-func (s *Syncer) processTopicTransaction(ctx context.Context, data []byte) (bool, error) {
+func (s *Syncer) processTopicTransaction(ctx context.Context, data []byte) (_ bool, err error) {
+	// the span continues the trace of the block commit on the node which has published the block
+	ctx, span := tracer.Start(ctx, "collate.ReceiveBlock",
+		trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(telattr.ShardId(s.config.ShardId)))
+	defer func() {
+		telemetry.EndSpan(span, err)
+	}()
+
 	var pbBlock pb.RawFullBlock
 	if err := proto.Unmarshal(data, &pbBlock); err != nil {
 		return false, err
@@ -305,6 +392,7 @@ func (s *Syncer) processTopicTransaction(ctx context.Context, data []byte) (bool
 	s.logger.Debug().
 		Stringer(logging.FieldBlockNumber, block.Id).
 		Msg("Received block")
+	span.SetAttributes(telattr.BlockNumber(block.Id))
 
 	if err := s.saveBlock(ctx, b); err != nil {
 		switch {
@@ -322,6 +410,14 @@ func (s *Syncer) processTopicTransaction(ctx context.Context, data []byte) (bool
 }
 
 func (s *Syncer) fetchBlocks(ctx context.Context) {
+	// If the shard is far behind the peers, most of the blocks are downloaded from several peers in parallel.
+	if err := s.downloader.catchUp(ctx); err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		s.logger.Warn().Err(err).Msg("Failed to download blocks in parallel, falling back to sequential sync")
+	}
+
 	// todo: fetch blocks until the queue (see todo above) is empty
 	for {
 		s.logger.Trace().Msg("Fetching next blocks")
@@ -5,10 +5,14 @@ package collate
 import (
 	"context"
 	"slices"
+	"testing"
 
 	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/stretchr/testify/require"
 )
 
 type MockTxnPool struct {
@@ -53,3 +57,32 @@ func (m *MockTxnPool) Add(txns ...*types.Transaction) {
 		m.MetaTxns = append(m.MetaTxns, types.NewTxnWithHash(txn))
 	}
 }
+
+// writeTestChain writes n linked blocks without any state into the shard, starting from the given base fee.
+func writeTestChain(t *testing.T, database db.DB, shardId types.ShardId, n int, baseFee uint64) []*types.Block {
+	t.Helper()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	blocks := make([]*types.Block, n)
+	prevHash := common.EmptyHash
+	for i := range n {
+		blocks[i] = &types.Block{
+			BlockData: types.BlockData{
+				Id:        types.BlockNumber(i),
+				PrevBlock: prevHash,
+				BaseFee:   types.NewValueFromUint64(baseFee),
+			},
+		}
+		prevHash = blocks[i].Hash(shardId)
+		require.NoError(t, db.WriteBlock(tx, shardId, prevHash, blocks[i]))
+		require.NoError(t, execution.PostprocessBlock(tx, shardId, &execution.BlockGenerationResult{
+			BlockHash: prevHash,
+			Block:     blocks[i],
+		}, execution.ModeVerify))
+	}
+	require.NoError(t, tx.Commit())
+	return blocks
+}
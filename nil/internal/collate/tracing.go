@@ -0,0 +1,57 @@
+package collate
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = telemetry.NewTracer("github.com/NilFoundation/nil/nil/internal/collate")
+
+// startTransactionSpan starts the span of a transaction processing stage within a block.
+// The span continues the trace of the transaction if it is known (i.e. it was received via RPC or from the network,
+// or it was emitted by a transaction executed on this node), and it is linked to the span of the block.
+func startTransactionSpan(
+	blockCtx context.Context, name string, shardId types.ShardId, txnHash common.Hash,
+) (context.Context, telemetry.Span) {
+	opts := []trace.SpanStartOption{
+		trace.WithAttributes(telattr.ShardId(shardId), telattr.TransactionHash(txnHash)),
+	}
+
+	ctx := blockCtx
+	if sc, ok := telemetry.TransactionSpanContext(txnHash); ok {
+		ctx = telemetry.ContextWithRemoteSpanContext(blockCtx, sc)
+		opts = append(opts, telemetry.WithLinks(telemetry.SpanContextFromContext(blockCtx)))
+	}
+	return tracer.Start(ctx, name, opts...)
+}
+
+// traceOutboundTransactions registers the spans of the transactions emitted by the executed one,
+// so that their processing in the destination shards continues the same trace.
+func traceOutboundTransactions(ctx context.Context, shardId types.ShardId, outTxns []*types.OutboundTransaction) {
+	for _, outTxn := range outTxns {
+		_, span := tracer.Start(ctx, "collate.OutboundTransaction", trace.WithAttributes(
+			telattr.ShardId(shardId),
+			telattr.TransactionHash(outTxn.TxnHash),
+			attribute.Int("dstShardId", int(outTxn.To.ShardId())),
+		))
+		telemetry.RegisterTransactionSpan(outTxn.TxnHash, span)
+		span.End()
+	}
+}
+
+// traceCommittedTransactions marks the commit of the known transactions in their traces.
+func traceCommittedTransactions(blockCtx context.Context, shardId types.ShardId, txnHashes []common.Hash) {
+	for _, txnHash := range txnHashes {
+		if _, ok := telemetry.TransactionSpanContext(txnHash); !ok {
+			continue
+		}
+		_, span := startTransactionSpan(blockCtx, "collate.CommitTransaction", shardId, txnHash)
+		span.End()
+	}
+}
@@ -19,7 +19,10 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/signer"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type invalidSignatureError struct {
@@ -34,6 +37,23 @@ func newErrInvalidSignature(inner error) invalidSignatureError {
 	return invalidSignatureError{inner: inner}
 }
 
+// invalidBlockError is returned if the replayed block does not match the received one.
+type invalidBlockError struct {
+	inner error
+}
+
+func (e invalidBlockError) Error() string {
+	return fmt.Sprintf("invalid block: %v", e.inner)
+}
+
+func (e invalidBlockError) Unwrap() error {
+	return e.inner
+}
+
+func newErrInvalidBlock(inner error) invalidBlockError {
+	return invalidBlockError{inner: inner}
+}
+
 type eventType int
 
 const (
@@ -193,7 +213,16 @@ func (s *Validator) buildBlockHashByProposal(ctx context.Context, proposal *exec
 	return res.BlockHash, nil
 }
 
-func (s *Validator) IsValidProposal(ctx context.Context, proposal *execution.ProposalSSZ) error {
+func (s *Validator) IsValidProposal(ctx context.Context, proposal *execution.ProposalSSZ) (err error) {
+	ctx, span := tracer.Start(ctx, "collate.ValidateProposal", trace.WithAttributes(
+		telattr.ShardId(s.params.ShardId),
+		telattr.BlockNumber(proposal.PrevBlockId+1),
+		telattr.BlockHash(proposal.BlockHash),
+	))
+	defer func() {
+		telemetry.EndSpan(span, err)
+	}()
+
 	p, err := execution.ConvertProposal(proposal)
 	if err != nil {
 		return err
@@ -224,7 +253,17 @@ func (s *Validator) InsertProposal(
 	ctx context.Context,
 	proposal *execution.ProposalSSZ,
 	params *types.ConsensusParams,
-) error {
+) (err error) {
+	ctx, span := tracer.Start(ctx, "collate.CommitBlock", trace.WithAttributes(
+		telattr.ShardId(s.params.ShardId),
+		telattr.BlockNumber(proposal.PrevBlockId+1),
+		telattr.BlockHash(proposal.BlockHash),
+		telattr.Round(params.Round),
+	))
+	defer func() {
+		telemetry.EndSpan(span, err)
+	}()
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	return s.insertProposalUnlocked(ctx, proposal, params)
@@ -292,6 +331,8 @@ func (s *Validator) onBlockCommitUnlocked(
 		}
 	}
 
+	traceCommittedTransactions(ctx, s.params.ShardId, res.InTxnHashes)
+
 	s.notify(&event{evType, res.Block.Id})
 }
 
@@ -436,7 +477,7 @@ func (s *Validator) replayBlockUnlocked(ctx context.Context, block *types.BlockW
 		if gasPricesBytes, ok := block.Config[config.NameGasPrice]; ok {
 			param := &config.ParamGasPrice{}
 			if err := param.UnmarshalSSZ(gasPricesBytes); err != nil {
-				return fmt.Errorf("failed to unmarshal gas prices: %w", err)
+				return newErrInvalidBlock(fmt.Errorf("failed to unmarshal gas prices: %w", err))
 			}
 			gasPrices = param.Shards
 		}
@@ -485,7 +526,7 @@ func (s *Validator) replayBlockUnlocked(ctx context.Context, block *types.BlockW
 
 	// Check generated block and proposed are equal
 	if err = s.validateRepliedBlock(block, resBlock, blockHash, block.OutTransactions); err != nil {
-		return fmt.Errorf("failed to validate replied block: %w", err)
+		return newErrInvalidBlock(fmt.Errorf("failed to validate replied block: %w", err))
 	}
 
 	// Finally, write generated block into the database
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	ssz "github.com/NilFoundation/fastssz"
 	"github.com/NilFoundation/nil/nil/common"
@@ -19,7 +20,7 @@ var (
 )
 
 func init() {
-	for _, param := range ParamsList {
+	for _, param := range slices.Concat(ParamsList, lazyParamsList) {
 		ParamsMap[param.Name()] = param.Accessor()
 	}
 }
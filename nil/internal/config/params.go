@@ -3,6 +3,8 @@ package config
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
@@ -20,6 +22,10 @@ const (
 	NameValidators = "curr_validators"
 	NameGasPrice   = "gas_price"
 	NameL1Block    = "l1block"
+
+	NameValidatorSchedule = "validator_schedule"
+	NameValidatorWeights  = "validator_weights"
+	NameValidatorHistory  = "validator_history"
 )
 
 var ParamsList = []IConfigParam{
@@ -28,6 +34,14 @@ var ParamsList = []IConfigParam{
 	new(ParamL1BlockInfo),
 }
 
+// lazyParamsList holds the params added after the networks were launched. They are not written into the zero state,
+// so the genesis hash of the existing networks is kept, and a missing param means its empty value.
+var lazyParamsList = []IConfigParam{
+	new(ParamValidatorSchedule),
+	new(ParamValidatorWeights),
+	new(ParamValidatorHistory),
+}
+
 type Pubkey [ValidatorPubkeySize]byte
 
 func (k Pubkey) MarshalText() ([]byte, error) {
@@ -67,6 +81,49 @@ type ParamValidators struct {
 type ValidatorInfo struct {
 	PublicKey         Pubkey        `json:"pubKey" yaml:"pubKey" ssz-size:"128"`
 	WithdrawalAddress types.Address `json:"withdrawalAddress" yaml:"withdrawalAddress"`
+	// Weight is the voting power of the validator in the consensus.
+	// It is stored in ParamValidatorWeights, so that the encoding of the validator sets
+	// of the existing networks is not changed.
+	Weight uint64 `json:"weight,omitempty" yaml:"weight,omitempty" ssz:"-"`
+}
+
+// MaxValidatorWeight bounds the weight of a validator,
+// so that the proposer schedule of the largest validator set stays reasonably small.
+const MaxValidatorWeight = 1000
+
+// VotingPower returns the voting power of the validator.
+// Zero weight (e.g., not specified in the config) means the default power of one.
+func (v *ValidatorInfo) VotingPower() uint64 {
+	return max(v.Weight, 1)
+}
+
+var ErrValidatorInvalidWeight = errors.New("invalid validator weight")
+
+func checkValidatorWeight(weight uint64) error {
+	if weight > MaxValidatorWeight {
+		return fmt.Errorf("%w: %d is greater than %d", ErrValidatorInvalidWeight, weight, MaxValidatorWeight)
+	}
+	return nil
+}
+
+// CheckWeights returns an error if the weight of some validator exceeds MaxValidatorWeight.
+func (p *ParamValidators) CheckWeights() error {
+	for _, list := range p.Validators {
+		if err := CheckValidatorWeights(list.List); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckValidatorWeights returns an error if the weight of some validator exceeds MaxValidatorWeight.
+func CheckValidatorWeights(validators []ValidatorInfo) error {
+	for _, v := range validators {
+		if err := checkValidatorWeight(v.Weight); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 var _ IConfigParam = new(ParamValidators)
@@ -111,6 +168,80 @@ func (p *ParamL1BlockInfo) Accessor() *ParamAccessor {
 	return CreateAccessor[ParamL1BlockInfo]()
 }
 
+type ValidatorChange struct {
+	ShardId   uint32        `json:"shardId" yaml:"shardId"`
+	Remove    bool          `json:"remove" yaml:"remove"`
+	Validator ValidatorInfo `json:"validator" yaml:"validator"`
+	// Weight is the weight of the added validator, as it is not encoded with ValidatorInfo.
+	Weight uint64 `json:"weight" yaml:"weight"`
+}
+
+// ParamValidatorSchedule holds the validator set changes waiting for the next epoch boundary of the main shard.
+type ParamValidatorSchedule struct {
+	EpochLength uint64            `json:"epochLength" yaml:"epochLength"`
+	Pending     []ValidatorChange `json:"pending" ssz-max:"4096" yaml:"pending"`
+}
+
+var _ IConfigParam = new(ParamValidatorSchedule)
+
+func (p *ParamValidatorSchedule) Name() string {
+	return NameValidatorSchedule
+}
+
+func (p *ParamValidatorSchedule) Accessor() *ParamAccessor {
+	return CreateAccessor[ParamValidatorSchedule]()
+}
+
+type ValidatorWeight struct {
+	PublicKey Pubkey `json:"pubKey" yaml:"pubKey" ssz-size:"128"`
+	Weight    uint64 `json:"weight" yaml:"weight"`
+}
+
+type ListValidatorWeights struct {
+	List []ValidatorWeight `json:"list" ssz-max:"4096" yaml:"list"`
+}
+
+// ParamValidatorWeights holds the weights of the validators in ParamValidators per shard.
+// Only the validators with non-default weights are listed.
+type ParamValidatorWeights struct {
+	Weights []ListValidatorWeights `json:"weights" ssz-max:"4096" yaml:"weights"`
+}
+
+var _ IConfigParam = new(ParamValidatorWeights)
+
+func (p *ParamValidatorWeights) Name() string {
+	return NameValidatorWeights
+}
+
+func (p *ParamValidatorWeights) Accessor() *ParamAccessor {
+	return CreateAccessor[ParamValidatorWeights]()
+}
+
+// ValidatorSetChange keeps the validator set of the shard that was replaced at an epoch boundary.
+type ValidatorSetChange struct {
+	ShardId uint32 `json:"shardId" yaml:"shardId"`
+	// From and Height are the first and the last heights of the shard that are known to be verified by the set.
+	From       uint64          `json:"from" yaml:"from"`
+	Height     uint64          `json:"height" yaml:"height"`
+	Validators []ValidatorInfo `json:"validators" ssz-max:"4096" yaml:"validators"`
+}
+
+// ParamValidatorHistory holds the last validator set changes of every shard in the order of activation,
+// so that the validators of the past heights can be found in the state.
+type ParamValidatorHistory struct {
+	Changes []ValidatorSetChange `json:"changes" ssz-max:"4096" yaml:"changes"`
+}
+
+var _ IConfigParam = new(ParamValidatorHistory)
+
+func (p *ParamValidatorHistory) Name() string {
+	return NameValidatorHistory
+}
+
+func (p *ParamValidatorHistory) Accessor() *ParamAccessor {
+	return CreateAccessor[ParamValidatorHistory]()
+}
+
 func CreateAccessor[T any, paramPtr IConfigParamPointer[T]]() *ParamAccessor {
 	return &ParamAccessor{
 		func(c ConfigAccessor) (any, error) {
@@ -132,8 +263,31 @@ func CreateAccessor[T any, paramPtr IConfigParamPointer[T]]() *ParamAccessor {
 	}
 }
 
+// GetParamValidators returns the validator sets with the weights of the validators.
 func GetParamValidators(c ConfigAccessor) (*ParamValidators, error) {
-	return getParamImpl[ParamValidators](c)
+	validators, err := getParamImpl[ParamValidators](c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Networks created before the weights were introduced don't have the param, all weights are default for them.
+	weights, err := getParamImpl[ParamValidatorWeights](c)
+	if errors.Is(err, ErrParamNotFound) || errors.Is(err, db.ErrKeyNotFound) {
+		return validators, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range min(len(validators.Validators), len(weights.Weights)) {
+		list := validators.Validators[i].List
+		for _, w := range weights.Weights[i].List {
+			index := slices.IndexFunc(list, func(v ValidatorInfo) bool { return v.PublicKey == w.PublicKey })
+			if index >= 0 {
+				list[index].Weight = w.Weight
+			}
+		}
+	}
+	return validators, nil
 }
 
 func NewConfigAccessorFromBlock(
@@ -227,8 +381,37 @@ func CreateValidatorsPublicKeyMap(validators []ValidatorInfo) (*PublicKeyMap, er
 	return m, nil
 }
 
+// SetParamValidators sets the validator sets and the weights of the validators.
 func SetParamValidators(c ConfigAccessor, params *ParamValidators) error {
-	return setParamImpl(c, params)
+	if err := params.CheckWeights(); err != nil {
+		return err
+	}
+	if err := setParamImpl(c, params); err != nil {
+		return err
+	}
+
+	weights := &ParamValidatorWeights{Weights: make([]ListValidatorWeights, len(params.Validators))}
+	weighted := false
+	for i, list := range params.Validators {
+		for _, v := range list.List {
+			if v.Weight != 0 {
+				weights.Weights[i].List = append(weights.Weights[i].List, ValidatorWeight{PublicKey: v.PublicKey, Weight: v.Weight})
+				weighted = true
+			}
+		}
+	}
+	// A missing param means equal weights, so it is written only to set the weights or to reset the ones set before.
+	// Otherwise the zero state and the blocks changing the validators would differ from the existing networks.
+	if !weighted {
+		_, err := c.GetParamData(NameValidatorWeights)
+		if errors.Is(err, ErrParamNotFound) || errors.Is(err, db.ErrKeyNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return setParamImpl(c, weights)
 }
 
 func GetParamGasPrice(c ConfigAccessor) (*ParamGasPrice, error) {
@@ -247,6 +430,14 @@ func SetParamL1Block(c ConfigAccessor, params *ParamL1BlockInfo) error {
 	return setParamImpl(c, params)
 }
 
+func SetParamValidatorSchedule(c ConfigAccessor, params *ParamValidatorSchedule) error {
+	return setParamImpl(c, params)
+}
+
+func SetParamValidatorHistory(c ConfigAccessor, params *ParamValidatorHistory) error {
+	return setParamImpl(c, params)
+}
+
 func GetParamNShards(c ConfigAccessor) (uint32, error) {
 	param, err := getParamImpl[ParamGasPrice](c)
 	if err != nil {
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorWeights(t *testing.T) {
+	t.Parallel()
+
+	v1, v2, v3 := newTestValidator(t), newTestValidator(t), newTestValidator(t)
+	v2.Weight = 3
+	v3.Weight = MaxValidatorWeight
+
+	c := NewConfigAccessorFromMap(make(map[string][]byte))
+	require.NoError(t, SetParamValidators(c, &ParamValidators{
+		Validators: []ListValidators{{List: []ValidatorInfo{v1, v2}}, {List: []ValidatorInfo{v3}}},
+	}))
+	require.Equal(t, []ValidatorInfo{v1, v2}, getShardValidators(t, c, 1))
+	require.Equal(t, []ValidatorInfo{v3}, getShardValidators(t, c, 2))
+
+	// The weights are stored apart from the validator sets.
+	data, err := c.GetParamData(NameValidators)
+	require.NoError(t, err)
+	var validators ParamValidators
+	require.NoError(t, validators.UnmarshalSSZ(data))
+	require.Zero(t, validators.Validators[0].List[1].Weight)
+
+	heavy := newTestValidator(t)
+	heavy.Weight = MaxValidatorWeight + 1
+	require.ErrorIs(t, SetParamValidators(c, &ParamValidators{
+		Validators: []ListValidators{{List: []ValidatorInfo{heavy}}},
+	}), ErrValidatorInvalidWeight)
+	require.Equal(t, []ValidatorInfo{v1, v2}, getShardValidators(t, c, 1))
+}
+
+func TestValidatorsWithoutWeights(t *testing.T) {
+	t.Parallel()
+
+	// Networks created before the weights were introduced have neither the weights param
+	// nor the weights in the encoding of the validator sets.
+	require.Equal(t, 148, new(ValidatorInfo).SizeSSZ())
+
+	v1 := newTestValidator(t)
+	data, err := (&ParamValidators{Validators: []ListValidators{{List: []ValidatorInfo{v1}}}}).MarshalSSZ()
+	require.NoError(t, err)
+	c := NewConfigAccessorFromMap(map[string][]byte{NameValidators: data})
+
+	validators := getShardValidators(t, c, 1)
+	require.Equal(t, []ValidatorInfo{v1}, validators)
+	require.Equal(t, uint64(1), validators[0].VotingPower())
+}
+
+func TestValidatorsWeightsParam(t *testing.T) {
+	t.Parallel()
+
+	v1, v2 := newTestValidator(t), newTestValidator(t)
+	c := NewConfigAccessorFromMap(make(map[string][]byte))
+
+	// The weights param is not written for the validators with equal weights.
+	require.NoError(t, SetParamValidators(c, &ParamValidators{
+		Validators: []ListValidators{{List: []ValidatorInfo{v1, v2}}},
+	}))
+	_, err := c.GetParamData(NameValidatorWeights)
+	require.ErrorIs(t, err, ErrParamNotFound)
+
+	v2.Weight = 3
+	require.NoError(t, SetParamValidators(c, &ParamValidators{
+		Validators: []ListValidators{{List: []ValidatorInfo{v1, v2}}},
+	}))
+	require.Equal(t, []ValidatorInfo{v1, v2}, getShardValidators(t, c, 1))
+
+	// The weights set before are reset.
+	v2.Weight = 0
+	require.NoError(t, SetParamValidators(c, &ParamValidators{
+		Validators: []ListValidators{{List: []ValidatorInfo{v1, v2}}},
+	}))
+	require.Equal(t, []ValidatorInfo{v1, v2}, getShardValidators(t, c, 1))
+}
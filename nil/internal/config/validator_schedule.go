@@ -0,0 +1,334 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+var (
+	ErrValidatorChangeWrongShard   = errors.New("validators can be changed only for existing non-main shards")
+	ErrValidatorAlreadyExists      = errors.New("validator is already in the set")
+	ErrValidatorNotFound           = errors.New("validator is not in the set")
+	ErrValidatorSetCannotBeEmpty   = errors.New("the last validator of the shard cannot be removed")
+	ErrValidatorInvalidPublicKey   = errors.New("invalid validator public key")
+	ErrValidatorScheduleIsTooLarge = errors.New("too many pending validator changes")
+	ErrValidatorHistoryIsPruned    = errors.New("validators of the height are not kept anymore")
+)
+
+const maxPendingValidatorChanges = 4096
+
+// maxValidatorHistory bounds the number of the replaced validator sets that are kept for every shard.
+const maxValidatorHistory = 16
+
+// GetParamValidatorSchedule returns the validator schedule.
+// Networks created before the schedule was introduced don't have the param, an empty schedule is returned for them.
+func GetParamValidatorSchedule(c ConfigAccessor) (*ParamValidatorSchedule, error) {
+	res, err := getParamImpl[ParamValidatorSchedule](c)
+	if errors.Is(err, ErrParamNotFound) || errors.Is(err, db.ErrKeyNotFound) {
+		return &ParamValidatorSchedule{}, nil
+	}
+	return res, err
+}
+
+// GetParamValidatorHistory returns the history of the validator sets.
+// Networks created before the history was introduced don't have the param, an empty history is returned for them.
+func GetParamValidatorHistory(c ConfigAccessor) (*ParamValidatorHistory, error) {
+	res, err := getParamImpl[ParamValidatorHistory](c)
+	if errors.Is(err, ErrParamNotFound) || errors.Is(err, db.ErrKeyNotFound) {
+		return &ParamValidatorHistory{}, nil
+	}
+	return res, err
+}
+
+// add records that the set verified the heights of the shard up to the given one.
+// The oldest set of the shard is dropped if the history of the shard is full.
+func (p *ParamValidatorHistory) add(shardId types.ShardId, height uint64, validators []ValidatorInfo) {
+	from := uint64(1)
+	count := 0
+	for _, change := range p.Changes {
+		if change.ShardId == uint32(shardId) {
+			from = change.Height + 1
+			count++
+		}
+	}
+	// The shard has not produced blocks since the previous change, so the set verified nothing.
+	if height < from {
+		return
+	}
+
+	p.Changes = append(p.Changes, ValidatorSetChange{
+		ShardId:    uint32(shardId),
+		From:       from,
+		Height:     height,
+		Validators: validators,
+	})
+	if count == maxValidatorHistory {
+		index := slices.IndexFunc(p.Changes, func(change ValidatorSetChange) bool {
+			return change.ShardId == uint32(shardId)
+		})
+		p.Changes = slices.Delete(p.Changes, index, index+1)
+	}
+}
+
+// GetShardValidatorsAt returns the validators that verify the blocks of the shard with the given height.
+// The heights after the last change of the shard are verified by the current set.
+// The sets from the history don't have the weights of the validators.
+func GetShardValidatorsAt(c ConfigAccessor, shardId types.ShardId, height uint64) ([]ValidatorInfo, error) {
+	history, err := GetParamValidatorHistory(c)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range history.Changes {
+		if change.ShardId != uint32(shardId) || height > change.Height {
+			continue
+		}
+		if height < change.From {
+			return nil, fmt.Errorf("%w: height %d of shard %s", ErrValidatorHistoryIsPruned, height, shardId)
+		}
+		return change.Validators, nil
+	}
+	return GetShardValidators(c, shardId)
+}
+
+// recordValidatorHistory adds the sets of the shards changed by the epoch boundary to the history.
+// The heights after the last one verified by the previous set for sure are attributed to the new set.
+// It doesn't let the removed validators escape, as they can't be removed once more anyway,
+// and the added validators sign the blocks of the previous set only if they misbehave.
+func recordValidatorHistory(
+	c ConfigAccessor, prev, curr *ParamValidators, lastReplaced func(types.ShardId) (uint64, error),
+) error {
+	history, err := GetParamValidatorHistory(c)
+	if err != nil {
+		return err
+	}
+	record := func(shardId types.ShardId, prevList, currList []ValidatorInfo) error {
+		if slices.EqualFunc(prevList, currList, func(a, b ValidatorInfo) bool { return a.PublicKey == b.PublicKey }) {
+			return nil
+		}
+		height, err := lastReplaced(shardId)
+		if err != nil {
+			return err
+		}
+		history.add(shardId, height, prevList)
+		return nil
+	}
+
+	if err := record(types.MainShardId, mergeValidators(prev.Validators), mergeValidators(curr.Validators)); err != nil {
+		return err
+	}
+	for i := range curr.Validators {
+		if err := record(types.ShardId(i+1), prev.Validators[i].List, curr.Validators[i].List); err != nil {
+			return err
+		}
+	}
+	return SetParamValidatorHistory(c, history)
+}
+
+// IsEpochBoundary returns true if the main shard block starts a new epoch.
+// Zero epoch length means that every block starts a new epoch.
+func (p *ParamValidatorSchedule) IsEpochBoundary(mainBlockId types.BlockNumber) bool {
+	return p.EpochLength == 0 || uint64(mainBlockId)%p.EpochLength == 0
+}
+
+func applyValidatorChange(validators *ParamValidators, change ValidatorChange) error {
+	if change.ShardId == uint32(types.MainShardId) || int(change.ShardId) > len(validators.Validators) {
+		return fmt.Errorf("%w: %d", ErrValidatorChangeWrongShard, change.ShardId)
+	}
+
+	list := &validators.Validators[change.ShardId-1]
+	index := slices.IndexFunc(list.List, func(v ValidatorInfo) bool {
+		return v.PublicKey == change.Validator.PublicKey
+	})
+
+	if change.Remove {
+		if index < 0 {
+			return ErrValidatorNotFound
+		}
+		if len(list.List) == 1 {
+			return ErrValidatorSetCannotBeEmpty
+		}
+		list.List = slices.Delete(slices.Clone(list.List), index, index+1)
+		return nil
+	}
+
+	if index >= 0 {
+		return ErrValidatorAlreadyExists
+	}
+	validator := change.Validator
+	validator.Weight = change.Weight
+	list.List = append(slices.Clone(list.List), validator)
+	return nil
+}
+
+func applyValidatorChanges(validators *ParamValidators, changes []ValidatorChange) error {
+	for _, change := range changes {
+		if err := applyValidatorChange(validators, change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ScheduleValidatorChange adds the change to the schedule.
+// The change is checked against the validator set with all the pending changes applied,
+// so it is known to be applicable at the epoch boundary.
+func ScheduleValidatorChange(c ConfigAccessor, change ValidatorChange) error {
+	if !change.Remove {
+		if _, err := bls.PublicKeyFromBytes(change.Validator.PublicKey[:]); err != nil {
+			return fmt.Errorf("%w: %w", ErrValidatorInvalidPublicKey, err)
+		}
+		if err := checkValidatorWeight(change.Weight); err != nil {
+			return err
+		}
+	}
+
+	schedule, err := GetParamValidatorSchedule(c)
+	if err != nil {
+		return err
+	}
+	if len(schedule.Pending) >= maxPendingValidatorChanges {
+		return ErrValidatorScheduleIsTooLarge
+	}
+
+	validators, err := GetParamValidators(c)
+	if err != nil {
+		return err
+	}
+	if err := applyValidatorChanges(validators, schedule.Pending); err != nil {
+		return err
+	}
+	if err := applyValidatorChange(validators, change); err != nil {
+		return err
+	}
+
+	schedule.Pending = append(schedule.Pending, change)
+	return SetParamValidatorSchedule(c, schedule)
+}
+
+// ScheduleValidatorRemoval schedules the removal of the validator from the shard.
+// The validators of the main shard are the union of all shards' ones,
+// so the removal from the main shard means the removal from all the shards the validator belongs to.
+func ScheduleValidatorRemoval(c ConfigAccessor, shardId types.ShardId, pubkey Pubkey) error {
+	change := ValidatorChange{
+		ShardId:   uint32(shardId),
+		Remove:    true,
+		Validator: ValidatorInfo{PublicKey: pubkey},
+	}
+	if !shardId.IsMainShard() {
+		return ScheduleValidatorChange(c, change)
+	}
+
+	schedule, err := GetParamValidatorSchedule(c)
+	if err != nil {
+		return err
+	}
+	validators, err := GetParamValidators(c)
+	if err != nil {
+		return err
+	}
+	if err := applyValidatorChanges(validators, schedule.Pending); err != nil {
+		return err
+	}
+
+	var changes []ValidatorChange
+	for i, list := range validators.Validators {
+		if slices.ContainsFunc(list.List, func(v ValidatorInfo) bool { return v.PublicKey == pubkey }) {
+			change.ShardId = uint32(i + 1)
+			changes = append(changes, change)
+		}
+	}
+	if len(changes) == 0 {
+		return ErrValidatorNotFound
+	}
+
+	// Check all the removals first, so that either all or none of them are scheduled.
+	if err := applyValidatorChanges(validators, changes); err != nil {
+		return err
+	}
+	for _, change := range changes {
+		if err := ScheduleValidatorChange(c, change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadValidatorParams copies the validator set with its schedule and history from src to dst.
+// The main shard blocks are executed on top of the configuration of the block before the previous one,
+// so the changes scheduled or activated by the previous block must be loaded before the schedule is used,
+// otherwise they would be overwritten. The params that are missing in src or equal in both are not copied.
+func LoadValidatorParams(dst, src ConfigAccessor) error {
+	for _, name := range []string{
+		NameValidators, NameValidatorWeights, NameValidatorSchedule, NameValidatorHistory,
+	} {
+		data, err := src.GetParamData(name)
+		if errors.Is(err, ErrParamNotFound) || errors.Is(err, db.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if current, err := dst.GetParamData(name); err == nil && bytes.Equal(current, data) {
+			continue
+		}
+		if err := dst.SetParamData(name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DroppedValidatorChange is a pending change that could not be applied at the epoch boundary.
+type DroppedValidatorChange struct {
+	Change ValidatorChange
+	Err    error
+}
+
+// ApplyValidatorSchedule moves the pending changes into the current validator set
+// if the main shard block starts a new epoch, the replaced sets are added to the history.
+// lastReplaced returns the last height of the shard that is verified by the replaced set for sure.
+// Returns true if the validator set was changed and the changes that were dropped.
+func ApplyValidatorSchedule(
+	c ConfigAccessor, mainBlockId types.BlockNumber, lastReplaced func(types.ShardId) (uint64, error),
+) (bool, []DroppedValidatorChange, error) {
+	schedule, err := GetParamValidatorSchedule(c)
+	if err != nil {
+		return false, nil, err
+	}
+	if len(schedule.Pending) == 0 || !schedule.IsEpochBoundary(mainBlockId) {
+		return false, nil, nil
+	}
+
+	validators, err := GetParamValidators(c)
+	if err != nil {
+		return false, nil, err
+	}
+	// The changes clone the lists they modify, so the previous set stays intact.
+	prev := &ParamValidators{Validators: slices.Clone(validators.Validators)}
+	var dropped []DroppedValidatorChange
+	for _, change := range schedule.Pending {
+		// The changes were checked on scheduling, but the set could be overwritten directly since then.
+		// A change that doesn't fit anymore must not stop the chain, so it is dropped and reported to the caller.
+		if err := applyValidatorChange(validators, change); err != nil {
+			dropped = append(dropped, DroppedValidatorChange{Change: change, Err: err})
+		}
+	}
+	if err := recordValidatorHistory(c, prev, validators, lastReplaced); err != nil {
+		return false, nil, err
+	}
+	if err := SetParamValidators(c, validators); err != nil {
+		return false, nil, err
+	}
+
+	schedule.Pending = nil
+	if err := SetParamValidatorSchedule(c, schedule); err != nil {
+		return false, nil, err
+	}
+	return true, dropped, nil
+}
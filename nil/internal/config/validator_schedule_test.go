@@ -0,0 +1,294 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestValidator(t *testing.T) ValidatorInfo {
+	t.Helper()
+
+	pubkey, err := bls.NewRandomKey().PublicKey().Marshal()
+	require.NoError(t, err)
+
+	var res ValidatorInfo
+	copy(res.PublicKey[:], pubkey)
+	return res
+}
+
+func newTestAccessor(t *testing.T, epochLength uint64, validators ...ValidatorInfo) ConfigAccessor {
+	t.Helper()
+
+	c := NewConfigAccessorFromMap(make(map[string][]byte))
+	require.NoError(t, SetParamValidators(c, &ParamValidators{
+		Validators: []ListValidators{{List: validators}, {List: validators}},
+	}))
+	require.NoError(t, SetParamValidatorSchedule(c, &ParamValidatorSchedule{EpochLength: epochLength}))
+	return c
+}
+
+func getShardValidators(t *testing.T, c ConfigAccessor, shardId types.ShardId) []ValidatorInfo {
+	t.Helper()
+
+	validators, err := GetParamValidators(c)
+	require.NoError(t, err)
+	return validators.Validators[shardId-1].List
+}
+
+func noShardBlocks(types.ShardId) (uint64, error) {
+	return 0, nil
+}
+
+func TestValidatorScheduleEpochBoundary(t *testing.T) {
+	t.Parallel()
+
+	v1, v2 := newTestValidator(t), newTestValidator(t)
+	c := newTestAccessor(t, 10, v1)
+
+	require.NoError(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Validator: v2}))
+
+	// The change is pending until the epoch boundary.
+	require.Equal(t, []ValidatorInfo{v1}, getShardValidators(t, c, 1))
+	updated, dropped, err := ApplyValidatorSchedule(c, 15, noShardBlocks)
+	require.NoError(t, err)
+	require.Empty(t, dropped)
+	require.False(t, updated)
+	require.Equal(t, []ValidatorInfo{v1}, getShardValidators(t, c, 1))
+
+	updated, dropped, err = ApplyValidatorSchedule(c, 20, noShardBlocks)
+	require.NoError(t, err)
+	require.Empty(t, dropped)
+	require.True(t, updated)
+	require.Equal(t, []ValidatorInfo{v1, v2}, getShardValidators(t, c, 1))
+	require.Equal(t, []ValidatorInfo{v1}, getShardValidators(t, c, 2))
+
+	schedule, err := GetParamValidatorSchedule(c)
+	require.NoError(t, err)
+	require.Empty(t, schedule.Pending)
+	require.Equal(t, uint64(10), schedule.EpochLength)
+
+	// Nothing to apply at the next boundary.
+	updated, dropped, err = ApplyValidatorSchedule(c, 30, noShardBlocks)
+	require.NoError(t, err)
+	require.Empty(t, dropped)
+	require.False(t, updated)
+}
+
+func TestValidatorScheduleRotation(t *testing.T) {
+	t.Parallel()
+
+	v1, v2 := newTestValidator(t), newTestValidator(t)
+	c := newTestAccessor(t, 0, v1)
+
+	// Replace the only validator: the removal is checked against the set with the pending addition.
+	require.ErrorIs(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Remove: true, Validator: v1}),
+		ErrValidatorSetCannotBeEmpty)
+	require.NoError(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Validator: v2}))
+	require.NoError(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Remove: true, Validator: v1}))
+
+	// Zero epoch length activates the changes in the next main shard block.
+	updated, dropped, err := ApplyValidatorSchedule(c, 1, noShardBlocks)
+	require.NoError(t, err)
+	require.Empty(t, dropped)
+	require.True(t, updated)
+	require.Equal(t, []ValidatorInfo{v2}, getShardValidators(t, c, 1))
+}
+
+func TestValidatorScheduleInvalidChanges(t *testing.T) {
+	t.Parallel()
+
+	v1, v2 := newTestValidator(t), newTestValidator(t)
+	c := newTestAccessor(t, 0, v1)
+
+	require.ErrorIs(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 0, Validator: v2}),
+		ErrValidatorChangeWrongShard)
+	require.ErrorIs(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 3, Validator: v2}),
+		ErrValidatorChangeWrongShard)
+	require.ErrorIs(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Validator: v1}),
+		ErrValidatorAlreadyExists)
+	require.ErrorIs(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Remove: true, Validator: v2}),
+		ErrValidatorNotFound)
+	var invalid ValidatorInfo
+	for i := range invalid.PublicKey {
+		invalid.PublicKey[i] = 0xff
+	}
+	require.ErrorIs(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Validator: invalid}),
+		ErrValidatorInvalidPublicKey)
+	require.ErrorIs(t,
+		ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Validator: v2, Weight: MaxValidatorWeight + 1}),
+		ErrValidatorInvalidWeight)
+
+	require.NoError(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Validator: v2}))
+	require.ErrorIs(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Validator: v2}),
+		ErrValidatorAlreadyExists)
+
+	schedule, err := GetParamValidatorSchedule(c)
+	require.NoError(t, err)
+	require.Len(t, schedule.Pending, 1)
+}
+
+func TestValidatorScheduleWeight(t *testing.T) {
+	t.Parallel()
+
+	v1, v2 := newTestValidator(t), newTestValidator(t)
+	c := newTestAccessor(t, 0, v1)
+
+	require.NoError(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Validator: v2, Weight: 5}))
+	updated, dropped, err := ApplyValidatorSchedule(c, 1, noShardBlocks)
+	require.NoError(t, err)
+	require.Empty(t, dropped)
+	require.True(t, updated)
+
+	v2.Weight = 5
+	require.Equal(t, []ValidatorInfo{v1, v2}, getShardValidators(t, c, 1))
+	require.Equal(t, []ValidatorInfo{v1}, getShardValidators(t, c, 2))
+}
+
+func TestValidatorScheduleDroppedChanges(t *testing.T) {
+	t.Parallel()
+
+	v1, v2, v3 := newTestValidator(t), newTestValidator(t), newTestValidator(t)
+	c := newTestAccessor(t, 0, v1)
+
+	require.NoError(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Validator: v2}))
+	require.NoError(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Validator: v3}))
+
+	// The set is overwritten directly after the changes are scheduled.
+	require.NoError(t, SetParamValidators(c, &ParamValidators{
+		Validators: []ListValidators{{List: []ValidatorInfo{v1, v2}}, {List: []ValidatorInfo{v1}}},
+	}))
+
+	updated, dropped, err := ApplyValidatorSchedule(c, 1, noShardBlocks)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Len(t, dropped, 1)
+	require.Equal(t, v2.PublicKey, dropped[0].Change.Validator.PublicKey)
+	require.ErrorIs(t, dropped[0].Err, ErrValidatorAlreadyExists)
+	require.Equal(t, []ValidatorInfo{v1, v2, v3}, getShardValidators(t, c, 1))
+}
+
+func TestValidatorScheduleMissingParam(t *testing.T) {
+	t.Parallel()
+
+	// Networks created before validator schedule have no such param.
+	c := NewConfigAccessorFromMap(make(map[string][]byte))
+	schedule, err := GetParamValidatorSchedule(c)
+	require.NoError(t, err)
+	require.Empty(t, schedule.Pending)
+
+	updated, dropped, err := ApplyValidatorSchedule(c, 1, noShardBlocks)
+	require.NoError(t, err)
+	require.Empty(t, dropped)
+	require.False(t, updated)
+}
+
+func TestValidatorScheduleRemoval(t *testing.T) {
+	t.Parallel()
+
+	v1, v2, v3 := newTestValidator(t), newTestValidator(t), newTestValidator(t)
+	c := NewConfigAccessorFromMap(make(map[string][]byte))
+	require.NoError(t, SetParamValidators(c, &ParamValidators{
+		Validators: []ListValidators{{List: []ValidatorInfo{v1, v2}}, {List: []ValidatorInfo{v1, v3}}},
+	}))
+
+	require.ErrorIs(t, ScheduleValidatorRemoval(c, types.MainShardId, newTestValidator(t).PublicKey),
+		ErrValidatorNotFound)
+
+	// The removal from the main shard removes the validator from all shards.
+	require.NoError(t, ScheduleValidatorRemoval(c, types.MainShardId, v1.PublicKey))
+	updated, dropped, err := ApplyValidatorSchedule(c, 1, noShardBlocks)
+	require.NoError(t, err)
+	require.Empty(t, dropped)
+	require.True(t, updated)
+	require.Equal(t, []ValidatorInfo{v2}, getShardValidators(t, c, 1))
+	require.Equal(t, []ValidatorInfo{v3}, getShardValidators(t, c, 2))
+
+	// Nothing is scheduled if the validator can't be removed from one of the shards.
+	require.NoError(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Validator: v3}))
+	require.ErrorIs(t, ScheduleValidatorRemoval(c, types.MainShardId, v3.PublicKey), ErrValidatorSetCannotBeEmpty)
+	schedule, err := GetParamValidatorSchedule(c)
+	require.NoError(t, err)
+	require.Len(t, schedule.Pending, 1)
+
+	require.NoError(t, ScheduleValidatorRemoval(c, 1, v2.PublicKey))
+	require.ErrorIs(t, ScheduleValidatorRemoval(c, 2, v2.PublicKey), ErrValidatorNotFound)
+}
+
+func TestValidatorHistory(t *testing.T) {
+	t.Parallel()
+
+	v1, v2, v3 := newTestValidator(t), newTestValidator(t), newTestValidator(t)
+	c := newTestAccessor(t, 0, v1)
+	mainReplaced, shardReplaced := uint64(11), uint64(6)
+	lastReplaced := func(shardId types.ShardId) (uint64, error) {
+		if shardId.IsMainShard() {
+			return mainReplaced, nil
+		}
+		return shardReplaced, nil
+	}
+
+	require.NoError(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Validator: v2}))
+	updated, _, err := ApplyValidatorSchedule(c, 10, lastReplaced)
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	check := func(shardId types.ShardId, height uint64, expected ...ValidatorInfo) {
+		t.Helper()
+
+		validators, err := GetShardValidatorsAt(c, shardId, height)
+		require.NoError(t, err)
+		require.Equal(t, expected, validators)
+	}
+
+	check(1, 1, v1)
+	check(1, 6, v1)
+	check(1, 7, v1, v2)
+	check(types.MainShardId, 11, v1)
+	check(types.MainShardId, 12, v1, v2)
+	// The set of the second shard is not changed.
+	check(2, 1, v1)
+	history, err := GetParamValidatorHistory(c)
+	require.NoError(t, err)
+	require.Len(t, history.Changes, 2)
+
+	// Only the last sets of the shard are kept.
+	for i := range maxValidatorHistory {
+		mainReplaced++
+		shardReplaced++
+		require.NoError(t, ScheduleValidatorChange(c, ValidatorChange{ShardId: 1, Remove: i%2 == 1, Validator: v3}))
+		_, _, err = ApplyValidatorSchedule(c, types.BlockNumber(mainReplaced-1), lastReplaced)
+		require.NoError(t, err)
+	}
+	_, err = GetShardValidatorsAt(c, 1, 6)
+	require.ErrorIs(t, err, ErrValidatorHistoryIsPruned)
+	check(1, 7, v1, v2)
+	check(1, 8, v1, v2, v3)
+	check(2, 1, v1)
+}
+
+func TestLoadValidatorParams(t *testing.T) {
+	t.Parallel()
+
+	v1, v2 := newTestValidator(t), newTestValidator(t)
+	src := newTestAccessor(t, 10, v1)
+	require.NoError(t, ScheduleValidatorChange(src, ValidatorChange{ShardId: 1, Validator: v2}))
+	dst := newTestAccessor(t, 10, v1)
+	require.NoError(t, SetParamGasPrice(dst, &ParamGasPrice{Shards: []types.Uint256{*types.NewUint256(1)}}))
+
+	require.NoError(t, LoadValidatorParams(dst, src))
+	schedule, err := GetParamValidatorSchedule(dst)
+	require.NoError(t, err)
+	require.Len(t, schedule.Pending, 1)
+	_, err = GetParamGasPrice(dst)
+	require.NoError(t, err)
+
+	// Networks created before validator schedule have no such param, nothing is copied.
+	empty := NewConfigAccessorFromMap(make(map[string][]byte))
+	require.NoError(t, LoadValidatorParams(empty, NewConfigAccessorFromMap(make(map[string][]byte))))
+	params, err := empty.GetParams()
+	require.NoError(t, err)
+	require.Empty(t, params)
+}
@@ -0,0 +1,122 @@
+package ibft
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+type signedMessageKey struct {
+	height  uint64
+	round   uint64
+	msgType proto.MessageType
+	from    string
+}
+
+type signedMessage struct {
+	msg      *proto.IbftMessage
+	reported bool
+}
+
+// equivocationDetector remembers the first validly signed message of every validator per view and type
+// and reports the messages conflicting with it.
+type equivocationDetector struct {
+	mu   sync.Mutex
+	seen map[signedMessageKey]*signedMessage
+}
+
+func newEquivocationDetector() *equivocationDetector {
+	return &equivocationDetector{
+		seen: make(map[signedMessageKey]*signedMessage),
+	}
+}
+
+// observe returns the previously seen message that conflicts with the given one.
+// Every equivocation is reported once.
+func (d *equivocationDetector) observe(msg *proto.IbftMessage) *proto.IbftMessage {
+	hash := types.ConflictingProposalHash(msg)
+	if hash == nil {
+		return nil
+	}
+
+	key := signedMessageKey{
+		height:  msg.GetView().GetHeight(),
+		round:   msg.GetView().GetRound(),
+		msgType: msg.GetType(),
+		from:    string(msg.GetFrom()),
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	seen, ok := d.seen[key]
+	if !ok {
+		d.seen[key] = &signedMessage{msg: msg}
+		return nil
+	}
+	if seen.reported || bytes.Equal(types.ConflictingProposalHash(seen.msg), hash) {
+		return nil
+	}
+	seen.reported = true
+	return seen.msg
+}
+
+// prune forgets the messages below the height, the consensus doesn't accept them anymore.
+func (d *equivocationDetector) prune(height uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for key := range d.seen {
+		if key.height < height {
+			delete(d.seen, key)
+		}
+	}
+}
+
+// checkEquivocation records the evidence if the validator has already signed a conflicting message.
+// The message must have a valid signature.
+func (i *backendIBFT) checkEquivocation(msg *proto.IbftMessage) {
+	prev := i.equivocations.observe(msg)
+	if prev == nil {
+		return
+	}
+
+	logger := i.logger.With().
+		Hex(logging.FieldPublicKey, msg.GetFrom()).
+		Uint64(logging.FieldHeight, msg.GetView().GetHeight()).
+		Uint64(logging.FieldRound, msg.GetView().GetRound()).
+		Stringer(logging.FieldType, msg.GetType()).
+		Logger()
+	logger.Warn().Msg("Validator signed conflicting messages")
+	i.mh.IncDoubleSigns(i.transportCtx, msg.GetType().String())
+
+	evidence, err := types.NewDoubleSignEvidence(i.shardId, prev, msg)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create double sign evidence")
+		return
+	}
+	if err := evidence.Verify(); err != nil {
+		// The messages signed before the activation of the signing domain can't be proven.
+		logger.Debug().Err(err).Msg("Double sign evidence can't be verified")
+		return
+	}
+
+	tx, err := i.txFabric.CreateRwTx(i.transportCtx)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to open transaction")
+		return
+	}
+	defer tx.Rollback()
+
+	if err := db.WriteDoubleSignEvidence(tx, evidence); err != nil {
+		logger.Error().Err(err).Msg("Failed to write double sign evidence")
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		logger.Error().Err(err).Msg("Failed to commit double sign evidence")
+	}
+}
@@ -17,12 +17,16 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
 	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const ibftProto = "/ibft/0.2"
 
+var tracer = telemetry.NewTracer("github.com/NilFoundation/nil/nil/internal/consensus")
+
 type ConsensusParams struct {
 	ShardId    types.ShardId
 	Db         db.DB
@@ -53,6 +57,9 @@ type backendIBFT struct {
 	signer       *Signer
 	mh           *MetricsHandler
 	txFabric     db.DB
+
+	proposerSchedule proposerScheduleCache
+	equivocations    *equivocationDetector
 }
 
 var _ core.Backend = &backendIBFT{}
@@ -69,15 +76,21 @@ func (i *backendIBFT) BuildProposal(view *protoIBFT.View) []byte {
 	i.mh.StartBuildProposalMeasurement(i.transportCtx, view.GetRound())
 	defer i.mh.EndBuildProposalMeasurement(i.transportCtx)
 
-	proposal, err := i.validator.BuildProposal(i.ctx)
+	ctx, span := tracer.Start(i.ctx, "ibft.BuildProposal", trace.WithAttributes(
+		telattr.ShardId(i.shardId), telattr.Height(view.GetHeight()), telattr.Round(view.GetRound())))
+	defer span.End()
+
+	proposal, err := i.validator.BuildProposal(ctx)
 	if err != nil {
 		i.logger.Error().Err(err).Msg("failed to build proposal")
+		telemetry.RecordError(span, err)
 		return nil
 	}
 
 	data, err := proposal.MarshalSSZ()
 	if err != nil {
 		i.logger.Error().Err(err).Msg("failed to marshal proposal")
+		telemetry.RecordError(span, err)
 		return nil
 	}
 
@@ -170,14 +183,17 @@ func (i *backendIBFT) InsertProposal(proposal *protoIBFT.Proposal, committedSeal
 		return // error is logged in buildSignature
 	}
 
-	prevProposerIndex := i.getPrevProposer(height)
-	_, proposerIndex, err := i.calcProposer(height, proposal.GetRound(), prevProposerIndex)
+	_, proposerIndex, err := i.calcProposer(height, proposal.GetRound())
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to calculate current proposer")
 		return
 	}
 
-	if err := i.validator.InsertProposal(i.ctx, proposalBlock, &types.ConsensusParams{
+	ctx, span := tracer.Start(i.ctx, "ibft.InsertProposal", trace.WithAttributes(
+		telattr.ShardId(i.shardId), telattr.Height(height), telattr.Round(proposal.GetRound())))
+	defer span.End()
+
+	if err := i.validator.InsertProposal(ctx, proposalBlock, &types.ConsensusParams{
 		Round:         proposal.GetRound(),
 		ProposerIndex: proposerIndex,
 		Signature:     sig,
@@ -187,6 +203,7 @@ func (i *backendIBFT) InsertProposal(proposal *protoIBFT.Proposal, committedSeal
 			event = i.logger.Debug()
 		}
 		event.Err(err).Msg("Failed to insert proposal")
+		telemetry.RecordError(span, err)
 	}
 }
 
@@ -217,13 +234,14 @@ func NewConsensus(cfg *ConsensusParams) (*backendIBFT, error) {
 	}
 
 	backend := &backendIBFT{
-		shardId:   cfg.ShardId,
-		validator: cfg.Validator,
-		logger:    logger,
-		nm:        cfg.NetManager,
-		signer:    NewSigner(cfg.PrivateKey),
-		mh:        mh,
-		txFabric:  cfg.Db,
+		shardId:       cfg.ShardId,
+		validator:     cfg.Validator,
+		logger:        logger,
+		nm:            cfg.NetManager,
+		signer:        NewSigner(cfg.PrivateKey),
+		mh:            mh,
+		txFabric:      cfg.Db,
+		equivocations: newEquivocationDetector(),
 	}
 	if backend.consensus, err = core.NewIBFTWithMetrics(l, backend, backend, telattr.ShardId(cfg.ShardId)); err != nil {
 		return nil, err
@@ -254,7 +272,7 @@ func (i *backendIBFT) GetVotingPowers(height uint64) (map[string]*big.Int, error
 	count := len(validators)
 	result := make(map[string]*big.Int, count)
 	for _, v := range validators {
-		result[string(v.PublicKey[:])] = big.NewInt(1)
+		result[string(v.PublicKey[:])] = new(big.Int).SetUint64(v.VotingPower())
 	}
 	i.mh.SetValidatorsCount(i.transportCtx, count)
 	return result, nil
@@ -263,7 +281,12 @@ func (i *backendIBFT) GetVotingPowers(height uint64) (map[string]*big.Int, error
 func (i *backendIBFT) RunSequence(ctx context.Context, height uint64) error {
 	i.mh.StartSequence(ctx, height)
 
+	ctx, span := tracer.Start(ctx, "ibft.Sequence", trace.WithAttributes(
+		telattr.ShardId(i.shardId), telattr.Height(height)))
+	defer span.End()
+
 	i.ctx = ctx
+	i.equivocations.prune(height)
 	i.consensus.RunSequence(ctx, height)
 	return nil
 }
@@ -3,9 +3,35 @@ package ibft
 import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	protoIBFT "github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
 	"google.golang.org/protobuf/proto"
 )
 
+// signingDomainActive reports whether the messages following the last block are signed with the shard bound in.
+func signingDomainActive(lastBlock *types.Block) bool {
+	return lastBlock.PatchLevel >= execution.PatchLevelConsensusSigningDomain
+}
+
+// signingData returns the data that is signed for the message payload following the last block.
+func (i *backendIBFT) signingData(lastBlock *types.Block, payload []byte) []byte {
+	if signingDomainActive(lastBlock) {
+		return types.ConsensusSigningData(i.shardId, payload)
+	}
+	return payload
+}
+
+// verifySignature checks the signature of the message payload following the last block.
+// Before the activation, the messages of both formats are accepted: the upgraded validators
+// may already sign the next height with the shard bound in.
+func (i *backendIBFT) verifySignature(lastBlock *types.Block, from, payload []byte, sig types.BlsSignature) error {
+	err := i.signer.VerifyWithKey(from, types.ConsensusSigningData(i.shardId, payload), sig)
+	if err != nil && !signingDomainActive(lastBlock) {
+		err = i.signer.VerifyWithKey(from, payload, sig)
+	}
+	return err
+}
+
 func (i *backendIBFT) signMessage(msg *protoIBFT.IbftMessage) *protoIBFT.IbftMessage {
 	raw, err := proto.Marshal(msg)
 	if err != nil {
@@ -13,7 +39,12 @@ func (i *backendIBFT) signMessage(msg *protoIBFT.IbftMessage) *protoIBFT.IbftMes
 		return nil
 	}
 
-	if msg.Signature, err = i.signer.Sign(raw); err != nil {
+	lastBlock, _, err := i.validator.GetLastBlock(i.ctx)
+	if err != nil {
+		i.logger.Error().Err(err).Msg("failed to get last block")
+		return nil
+	}
+	if msg.Signature, err = i.signer.Sign(i.signingData(lastBlock, raw)); err != nil {
 		event := i.logger.Error().Err(err).
 			Stringer("type", msg.GetType())
 		if view := msg.GetView(); view != nil {
@@ -0,0 +1,37 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningDomainActivation(t *testing.T) {
+	t.Parallel()
+
+	signer := NewSigner(bls.NewRandomKey())
+	backend := &backendIBFT{shardId: 1, signer: signer}
+	otherShard := &backendIBFT{shardId: 2, signer: signer}
+	payload := []byte("payload")
+
+	before := &types.Block{BlockData: types.BlockData{PatchLevel: execution.PatchLevelGovernanceUpgrade}}
+	after := &types.Block{BlockData: types.BlockData{PatchLevel: execution.PatchLevelConsensusSigningDomain}}
+
+	legacy, err := signer.Sign(backend.signingData(before, payload))
+	require.NoError(t, err)
+	bound, err := signer.Sign(backend.signingData(after, payload))
+	require.NoError(t, err)
+	require.NotEqual(t, legacy, bound)
+
+	// Both formats are accepted until the activation.
+	require.NoError(t, backend.verifySignature(before, signer.rawPublicKey, payload, legacy))
+	require.NoError(t, backend.verifySignature(before, signer.rawPublicKey, payload, bound))
+
+	// Only the shard-bound one after it.
+	require.Error(t, backend.verifySignature(after, signer.rawPublicKey, payload, legacy))
+	require.NoError(t, backend.verifySignature(after, signer.rawPublicKey, payload, bound))
+	require.Error(t, otherShard.verifySignature(after, signer.rawPublicKey, payload, bound))
+}
@@ -20,6 +20,7 @@ type MetricsHandler struct {
 	validatorsCount  telemetry.Gauge
 	sentMessages     telemetry.Counter
 	receivedMessages telemetry.Counter
+	doubleSigns      telemetry.Counter
 }
 
 func NewMetricsHandler(name string, shardId types.ShardId) (*MetricsHandler, error) {
@@ -74,6 +75,10 @@ func (mh *MetricsHandler) initMetrics(meter metric.Meter) error {
 		return err
 	}
 
+	if mh.doubleSigns, err = meter.Int64Counter("double_signs"); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -111,3 +116,7 @@ func (mh *MetricsHandler) IncSentMessages(ctx context.Context, t string) {
 func (mh *MetricsHandler) IncReceivedMessages(ctx context.Context, t string) {
 	mh.receivedMessages.Add(ctx, 1, mh.option, telattr.With(telattr.Type(t)))
 }
+
+func (mh *MetricsHandler) IncDoubleSigns(ctx context.Context, t string) {
+	mh.doubleSigns.Add(ctx, 1, mh.option, telattr.With(telattr.Type(t)))
+}
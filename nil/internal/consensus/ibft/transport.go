@@ -7,29 +7,35 @@ import (
 	"github.com/NilFoundation/nil/nil/go-ibft/core"
 	"github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
 	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
 	protobuf "google.golang.org/protobuf/proto"
 )
 
 type transport interface {
-	Multicast(msg *proto.IbftMessage) error
+	// Multicast sends the message to all validators, ctx defines the trace the message belongs to.
+	Multicast(ctx context.Context, msg *proto.IbftMessage) error
 }
 
 type gossipTransport struct {
-	ctx   context.Context
 	topic *network.PubSub
 	proto string
 }
 
-func (g *gossipTransport) Multicast(msg *proto.IbftMessage) error {
+func (g *gossipTransport) Multicast(ctx context.Context, msg *proto.IbftMessage) error {
 	data, err := protobuf.Marshal(msg)
 	if err != nil {
 		return err
 	}
-	return g.topic.Publish(g.ctx, g.proto, data)
+	return g.topic.Publish(ctx, g.proto, data)
 }
 
 func (i *backendIBFT) Multicast(msg *proto.IbftMessage) {
-	if err := i.transport.Multicast(msg); err != nil {
+	// messages are sent within the sequence, so the receivers continue its trace
+	ctx := i.transportCtx
+	if i.ctx != nil {
+		ctx = telemetry.ContextWithRemoteSpanContext(ctx, telemetry.SpanContextFromContext(i.ctx))
+	}
+	if err := i.transport.Multicast(ctx, msg); err != nil {
 		i.logger.Error().Err(err).Msg("Fail to gossip")
 	}
 	i.mh.IncSentMessages(i.transportCtx, msg.GetType().String())
@@ -97,7 +103,6 @@ func (i *backendIBFT) setupTransport(ctx context.Context) error {
 	}(ctx)
 
 	i.transport = &gossipTransport{
-		ctx:   ctx,
 		topic: topic,
 		proto: i.getProto(),
 	}
@@ -109,7 +114,7 @@ type localTransport struct {
 	consensus *core.IBFT
 }
 
-func (l *localTransport) Multicast(msg *proto.IbftMessage) error {
+func (l *localTransport) Multicast(_ context.Context, msg *proto.IbftMessage) error {
 	l.consensus.AddMessage(msg)
 	return nil
 }
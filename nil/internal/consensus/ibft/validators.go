@@ -1,28 +1,117 @@
 package ibft
 
 import (
+	"slices"
+	"sync"
+
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/go-ibft/core"
 	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
 )
 
-func (i *backendIBFT) calcProposer(height, round uint64, prevValidator *uint64) (*config.ValidatorInfo, uint64, error) {
+// proposerScheduleCache keeps the proposer schedule of the last used validator set.
+// The set changes only at epoch boundaries, so the schedule is rarely rebuilt.
+type proposerScheduleCache struct {
+	mu sync.Mutex
+	// validators is the set the schedule is built for. The config params are cached per height,
+	// so the set is compared by the keys and the voting powers rather than by the params.
+	validators []config.ValidatorInfo
+	schedule   []int
+}
+
+func sameVotingPowers(a, b []config.ValidatorInfo) bool {
+	return slices.EqualFunc(a, b, func(x, y config.ValidatorInfo) bool {
+		return x.PublicKey == y.PublicKey && x.VotingPower() == y.VotingPower()
+	})
+}
+
+func (c *proposerScheduleCache) get(params *config.ConfigParams) ([]int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.schedule != nil && sameVotingPowers(c.validators, params.ValidatorInfo) {
+		return c.schedule, nil
+	}
+
+	votingPowers := make([]uint64, len(params.ValidatorInfo))
+	for i := range params.ValidatorInfo {
+		votingPowers[i] = params.ValidatorInfo[i].VotingPower()
+	}
+	schedule, err := core.ProposerSchedule(votingPowers)
+	if err != nil {
+		return nil, err
+	}
+
+	c.validators = slices.Clone(params.ValidatorInfo)
+	c.schedule = schedule
+	return schedule, nil
+}
+
+// weightedProposersActive reports whether the proposers following the last block follow the weighted schedule.
+func weightedProposersActive(lastBlock *types.Block) bool {
+	return lastBlock.PatchLevel >= execution.PatchLevelWeightedProposers
+}
+
+// calcProposer returns the proposer of the round and its index in the validator list.
+// The proposers follow the weighted proposer schedule of the validator set. The schedule is walked by height,
+// and the failed rounds move to the next validators in it, as Tendermint does.
+// Thus, the proposer depends only on the height, the round and the validator set,
+// and it is the same on all nodes.
+// Until the chain reaches execution.PatchLevelWeightedProposers, the proposers are chosen in turn
+// after the proposer of the last block, so the validators can be upgraded one by one.
+func (i *backendIBFT) calcProposer(height, round uint64) (*config.ValidatorInfo, uint64, error) {
+	logger := i.logger.With().
+		Uint64(logging.FieldRound, round).
+		Uint64(logging.FieldHeight, height).
+		Logger()
+
 	params, err := config.GetConfigParams(i.ctx, i.txFabric, i.shardId, height)
 	if err != nil {
-		i.logger.Error().
+		logger.Error().
 			Err(err).
-			Uint64(logging.FieldRound, round).
-			Uint64(logging.FieldHeight, height).
 			Msg("Failed to get validators' params")
 		return nil, 0, err
 	}
 
-	var seed uint64
-	if prevValidator == nil {
-		seed = round
-	} else {
-		seed = *prevValidator + round + 1
+	lastBlock, _, err := i.validator.GetLastBlock(i.ctx)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to get last block")
+		return nil, 0, err
 	}
 
-	index := seed % uint64(len(params.ValidatorInfo))
+	index, err := i.proposerIndex(lastBlock, params, height, round)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Msg("Failed to build proposer schedule")
+		return nil, 0, err
+	}
 	return &params.ValidatorInfo[index], index, nil
 }
+
+// proposerIndex returns the index of the proposer of the round following the last block.
+func (i *backendIBFT) proposerIndex(
+	lastBlock *types.Block, params *config.ConfigParams, height, round uint64,
+) (uint64, error) {
+	if !weightedProposersActive(lastBlock) {
+		// The first block is proposed by the first validator.
+		seed := round
+		if height >= 2 {
+			seed = lastBlock.ProposerIndex + round + 1
+		}
+		return seed % uint64(len(params.ValidatorInfo)), nil
+	}
+
+	schedule, err := i.proposerSchedule.get(params)
+	if err != nil {
+		return 0, err
+	}
+
+	// The first block is proposed at the start of the schedule.
+	step := max(height, 1) - 1 + round
+	return uint64(schedule[step%uint64(len(schedule))]), nil
+}
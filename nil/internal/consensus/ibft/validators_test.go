@@ -0,0 +1,88 @@
+package ibft
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposerScheduleCache(t *testing.T) {
+	t.Parallel()
+
+	validators := []config.ValidatorInfo{{PublicKey: config.Pubkey{1}}, {PublicKey: config.Pubkey{2}, Weight: 2}}
+
+	var cache proposerScheduleCache
+	schedule, err := cache.get(&config.ConfigParams{ValidatorInfo: validators})
+	require.NoError(t, err)
+	require.Len(t, schedule, 3)
+
+	// The params of another height with the same set reuse the schedule.
+	cached, err := cache.get(&config.ConfigParams{ValidatorInfo: []config.ValidatorInfo{
+		{PublicKey: config.Pubkey{1}, Weight: 1}, {PublicKey: config.Pubkey{2}, Weight: 2},
+	}})
+	require.NoError(t, err)
+	require.Same(t, &schedule[0], &cached[0])
+
+	// The schedule is rebuilt once the weights change.
+	validators[1].Weight = 3
+	schedule, err = cache.get(&config.ConfigParams{ValidatorInfo: validators})
+	require.NoError(t, err)
+	require.Len(t, schedule, 4)
+
+	// Or the keys.
+	schedule, err = cache.get(&config.ConfigParams{ValidatorInfo: []config.ValidatorInfo{
+		{PublicKey: config.Pubkey{1}}, {PublicKey: config.Pubkey{3}, Weight: 3},
+	}})
+	require.NoError(t, err)
+	require.Len(t, schedule, 4)
+	require.Equal(t, []config.ValidatorInfo{{PublicKey: config.Pubkey{1}}, {PublicKey: config.Pubkey{3}, Weight: 3}},
+		cache.validators)
+}
+
+func TestProposerActivation(t *testing.T) {
+	t.Parallel()
+
+	backend := &backendIBFT{}
+	params := &config.ConfigParams{ValidatorInfo: []config.ValidatorInfo{
+		{PublicKey: config.Pubkey{1}}, {PublicKey: config.Pubkey{2}}, {PublicKey: config.Pubkey{3}},
+	}}
+
+	// The last block at height 10 was proposed by the first validator after a failed round.
+	lastBlock := func(patchLevel uint32) *types.Block {
+		return &types.Block{
+			BlockData:       types.BlockData{Id: 10, PatchLevel: patchLevel},
+			ConsensusParams: types.ConsensusParams{ProposerIndex: 0, Round: 1},
+		}
+	}
+	before := lastBlock(execution.PatchLevelConsensusSigningDomain)
+	after := lastBlock(execution.PatchLevelWeightedProposers)
+
+	proposers := func(lastBlock *types.Block, height uint64) []uint64 {
+		t.Helper()
+
+		var res []uint64
+		for round := range uint64(3) {
+			index, err := backend.proposerIndex(lastBlock, params, height, round)
+			require.NoError(t, err)
+			res = append(res, index)
+		}
+		return res
+	}
+
+	// Before the activation, the proposers follow the proposer of the last block.
+	require.Equal(t, []uint64{1, 2, 0}, proposers(before, 11))
+	require.Equal(t, []uint64{0, 1, 2}, proposers(before, 1))
+
+	// After it, they follow the schedule by height regardless of the last proposer.
+	require.Equal(t, []uint64{1, 2, 0}, proposers(after, 11))
+	require.Equal(t, []uint64{2, 0, 1}, proposers(after, 12))
+	require.Equal(t, []uint64{0, 1, 2}, proposers(after, 1))
+
+	after.ProposerIndex = 2
+	require.Equal(t, []uint64{1, 2, 0}, proposers(after, 11))
+	before.ProposerIndex = 2
+	require.Equal(t, []uint64{0, 1, 2}, proposers(before, 11))
+}
@@ -62,8 +62,9 @@ func (i *backendIBFT) IsValidValidator(msg *protoIBFT.IbftMessage) bool {
 
 	// Current message is from future.
 	// Some validator could commit block and start new sequence before we committed that block.
-	// Use last known config since validators list is static for now.
-	// TODO: consider some options to fix it.
+	// Use the validators of the next height, which are known already. They may differ from the validators
+	// of the message height only when a validator change is activated, then such message is dropped
+	// and the node catches up via block sync.
 	if expectedHeight := uint64(lastBlock.Id + 1); height > expectedHeight {
 		logger.Warn().Msgf("Got message with height=%d while expected=%d", height, expectedHeight)
 		height = expectedHeight
@@ -84,32 +85,17 @@ func (i *backendIBFT) IsValidValidator(msg *protoIBFT.IbftMessage) bool {
 		return false
 	}
 
-	if err := i.signer.VerifyWithKey(msg.GetFrom(), msgNoSig, msg.GetSignature()); err != nil {
+	if err := i.verifySignature(lastBlock, msg.GetFrom(), msgNoSig, msg.GetSignature()); err != nil {
 		logger.Err(err).Msg("Failed to verify signature")
 		return false
 	}
 
+	i.checkEquivocation(msg)
 	return true
 }
 
-func (i *backendIBFT) getPrevProposer(height uint64) *uint64 {
-	// It doesn't make sense for 0 block
-	// For the first block we should start from the first validator (offset = 0)
-	if height < 2 {
-		return nil
-	}
-
-	block, _, err := i.validator.GetLastBlock(i.ctx)
-	if err != nil {
-		return nil
-	}
-
-	return &block.ProposerIndex
-}
-
 func (i *backendIBFT) IsProposer(id []byte, height, round uint64) bool {
-	prevProposerIndex := i.getPrevProposer(height)
-	proposer, _, err := i.calcProposer(height, round, prevProposerIndex)
+	proposer, _, err := i.calcProposer(height, round)
 	if err != nil {
 		i.logger.Error().
 			Err(err).
@@ -29,18 +29,28 @@ var (
 )
 
 func GetCode(name string) (types.Code, error) {
+	return readCode(name + ".bin")
+}
+
+// GetRuntimeCode returns the code that the contract's constructor leaves in the state.
+// It is available only for the system contracts.
+func GetRuntimeCode(name string) (types.Code, error) {
+	return readCode(name + ".bin-runtime")
+}
+
+func readCode(fileName string) (types.Code, error) {
 	// The result taken from the cache must be cloned.
-	if res, ok := codeCache.Get(name); ok {
+	if res, ok := codeCache.Get(fileName); ok {
 		return res.Clone(), nil
 	}
 
-	code, err := contracts.Fs.ReadFile("compiled/" + name + ".bin")
+	code, err := contracts.Fs.ReadFile("compiled/" + fileName)
 	if err != nil {
 		return nil, err
 	}
 
 	res := types.Code(hexutil.FromHex(string(code)))
-	codeCache.Put(name, res)
+	codeCache.Put(fileName, res)
 	return res.Clone(), nil
 }
 
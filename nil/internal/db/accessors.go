@@ -3,6 +3,7 @@ package db
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"reflect"
 
 	fastssz "github.com/NilFoundation/fastssz"
@@ -133,6 +134,22 @@ func WriteLastBlockHash(tx RwTx, shardId types.ShardId, hash common.Hash) error
 	return tx.Put(LastBlockTable, shardId.Bytes(), hash.Bytes())
 }
 
+var stateSyncPivotKey = []byte("pivot")
+
+// ReadStateSyncPivot returns the hash of the main shard block the interrupted state sync was downloading.
+func ReadStateSyncPivot(tx RoTx) (common.Hash, error) {
+	h, err := tx.Get(stateSyncTable, stateSyncPivotKey)
+	return common.BytesToHash(h), err
+}
+
+func WriteStateSyncPivot(tx RwTx, hash common.Hash) error {
+	return tx.Put(stateSyncTable, stateSyncPivotKey, hash.Bytes())
+}
+
+func DeleteStateSyncPivot(tx RwTx) error {
+	return tx.Delete(stateSyncTable, stateSyncPivotKey)
+}
+
 func WriteBlockTimestamp(tx RwTx, shardId types.ShardId, blockHash common.Hash, timestamp uint64) error {
 	value := make([]byte, 8)
 	binary.LittleEndian.PutUint64(value, timestamp)
@@ -183,3 +200,118 @@ func ReadBlockByNumber(tx RoTx, shardId types.ShardId, blockNumber types.BlockNu
 	}
 	return ReadBlock(tx, shardId, blockHash)
 }
+
+func logsBloomIndexKey(blockNumber types.BlockNumber) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(blockNumber))
+}
+
+func WriteBlockLogsBloom(
+	tx RwTx, shardId types.ShardId, blockNumber types.BlockNumber, blockHash common.Hash, bloom types.Bloom,
+) error {
+	value := make([]byte, 0, common.HashSize+types.BloomByteLength)
+	value = append(value, blockHash.Bytes()...)
+	value = append(value, bloom.Bytes()...)
+	return tx.PutToShard(shardId, LogsBloomIndex, logsBloomIndexKey(blockNumber), value)
+}
+
+func decodeBlockLogsBloom(value []byte) (common.Hash, types.Bloom, error) {
+	if len(value) != common.HashSize+types.BloomByteLength {
+		return common.EmptyHash, types.Bloom{}, fmt.Errorf("invalid logs bloom index entry size %d", len(value))
+	}
+	return common.BytesToHash(value[:common.HashSize]), types.BytesToBloom(value[common.HashSize:]), nil
+}
+
+// ReadBlockLogsBloom returns the hash and the logs bloom of the block with the given number
+// from the logs bloom index.
+func ReadBlockLogsBloom(
+	tx RoTx, shardId types.ShardId, blockNumber types.BlockNumber,
+) (common.Hash, types.Bloom, error) {
+	value, err := tx.GetFromShard(shardId, LogsBloomIndex, logsBloomIndexKey(blockNumber))
+	if err != nil {
+		return common.EmptyHash, types.Bloom{}, err
+	}
+	return decodeBlockLogsBloom(value)
+}
+
+// ReadLogsIndexLowestBlock returns the hash of the lowest block the logs bloom index rebuild has reached.
+// The blocks from it up to the head are in the index.
+func ReadLogsIndexLowestBlock(tx RoTx, shardId types.ShardId) (common.Hash, error) {
+	h, err := tx.Get(logsIndexTable, shardId.Bytes())
+	return common.BytesToHash(h), err
+}
+
+func WriteLogsIndexLowestBlock(tx RwTx, shardId types.ShardId, hash common.Hash) error {
+	return tx.Put(logsIndexTable, shardId.Bytes(), hash.Bytes())
+}
+
+// IterBlockLogsBloom calls fn for every logs bloom index entry in [from, to] in ascending order.
+// Blocks missing from the index are silently skipped, so the caller must check for gaps itself.
+func IterBlockLogsBloom(
+	tx RoTx,
+	shardId types.ShardId,
+	from, to types.BlockNumber,
+	fn func(blockNumber types.BlockNumber, blockHash common.Hash, bloom types.Bloom) (bool, error),
+) error {
+	it, err := tx.RangeByShard(shardId, LogsBloomIndex, logsBloomIndexKey(from), logsBloomIndexKey(to))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	for it.HasNext() {
+		key, value, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if len(key) != 8 {
+			return fmt.Errorf("invalid logs bloom index key size %d", len(key))
+		}
+		hash, bloom, err := decodeBlockLogsBloom(value)
+		if err != nil {
+			return err
+		}
+		if next, err := fn(types.BlockNumber(binary.BigEndian.Uint64(key)), hash, bloom); err != nil || !next {
+			return err
+		}
+	}
+	return nil
+}
+
+func doubleSignEvidenceKey(evidence *types.DoubleSignEvidence) []byte {
+	key := binary.BigEndian.AppendUint64(nil, evidence.Height)
+	key = binary.BigEndian.AppendUint64(key, evidence.Round)
+	key = binary.BigEndian.AppendUint32(key, evidence.Type)
+	return append(key, evidence.PublicKey...)
+}
+
+// WriteDoubleSignEvidence stores the evidence. There is a single evidence per validator and view of a message type,
+// so the repeated equivocation of the validator in the same view overwrites the previous evidence.
+func WriteDoubleSignEvidence(tx RwTx, evidence *types.DoubleSignEvidence) error {
+	return writeRawKeyEncodable(tx, DoubleSignEvidenceTable, evidence.ShardId, doubleSignEvidenceKey(evidence), evidence)
+}
+
+// ReadDoubleSignEvidence returns the evidence of the shard for the heights starting from the given one.
+// At most limit entries are returned, ordered by height.
+func ReadDoubleSignEvidence(
+	tx RoTx, shardId types.ShardId, fromHeight uint64, limit int,
+) ([]*types.DoubleSignEvidence, error) {
+	it, err := tx.RangeByShard(shardId, DoubleSignEvidenceTable, binary.BigEndian.AppendUint64(nil, fromHeight), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	var res []*types.DoubleSignEvidence
+	for it.HasNext() && len(res) < limit {
+		_, value, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		evidence := &types.DoubleSignEvidence{}
+		if err := evidence.UnmarshalSSZ(value); err != nil {
+			return nil, err
+		}
+		res = append(res, evidence)
+	}
+	return res, nil
+}
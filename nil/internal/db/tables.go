@@ -30,11 +30,21 @@ const (
 	BlockHashAndOutTransactionIndexByTransactionHash = ShardedTableName(
 		"BlockHashAndOutTransactionIndexByTransactionHash")
 	AsyncCallContextTable = ShardedTableName("AsyncCallContext")
+	// LogsBloomIndex maps a block number (big-endian, so that ranges are ordered) to the block hash
+	// and the aggregated logs bloom of the block. It allows eth_getLogs to skip blocks without decoding them.
+	LogsBloomIndex = ShardedTableName("LogsBloomIndex")
+	// DoubleSignEvidenceTable keeps the evidence of the validators' equivocation detected by the consensus.
+	// The keys start with the big-endian height, so that the evidence is ordered by height.
+	DoubleSignEvidenceTable = ShardedTableName("DoubleSignEvidence")
 
 	collatorStateTable          = TableName("CollatorState")
 	errorByTransactionHashTable = TableName("ErrorByTransactionHash")
 	schemeVersionTable          = TableName("SchemeVersion")
 	LastBlockTable              = TableName("LastBlock")
+	// stateSyncTable keeps the progress of the state sync, so that it can be resumed after interruption.
+	stateSyncTable = TableName("StateSync")
+	// logsIndexTable keeps the progress of the logs bloom index rebuild, so that it can be resumed after interruption.
+	logsIndexTable = TableName("LogsIndex")
 
 	DHTTable = TableName("DHT")
 )
@@ -4,17 +4,32 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/assert"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/tracing"
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
 
+// PatchLevelGovernanceUpgrade is the patch level that upgrades the Governance contract of the main shard.
+// The chain is moved to it with the Governance.rollback call.
+const PatchLevelGovernanceUpgrade = 2
+
+// PatchLevelConsensusSigningDomain is the patch level from which the consensus messages are signed
+// with the shard bound in (see types.ConsensusSigningData). The messages of the previous format are accepted
+// until the chain is moved to it, so the validators can be upgraded one by one.
+const PatchLevelConsensusSigningDomain = 3
+
+// PatchLevelWeightedProposers is the patch level from which the proposers follow the weighted proposer schedule
+// of the validator set. Before it, the proposer is the one following the proposer of the last block.
+const PatchLevelWeightedProposers = 4
+
 type BlockGeneratorParams struct {
 	ShardId          types.ShardId
 	NShards          uint32
@@ -51,6 +66,10 @@ type BlockGenerator struct {
 
 	logger   logging.Logger
 	counters *BlockGeneratorCounters
+
+	// tracedTxn is the transaction that is executed with tracedHooks by ReplayTransaction.
+	tracedTxn   common.Hash
+	tracedHooks *tracing.Hooks
 }
 
 type BlockGenerationResult struct {
@@ -102,6 +121,18 @@ func NewBlockGeneratorWithEs(
 	rwTx db.RwTx,
 	es *ExecutionState,
 ) (*BlockGenerator, error) {
+	// The main shard configuration is taken from the block before the previous one,
+	// so the validator set and its schedule are reloaded to keep the changes made by the previous block.
+	if params.ShardId.IsMainShard() && !es.PrevBlock.Empty() {
+		prevConfig, err := config.NewConfigReader(rwTx, &es.PrevBlock)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read previous block config: %w", err)
+		}
+		if err := config.LoadValidatorParams(es.GetConfigAccessor(), prevConfig); err != nil {
+			return nil, fmt.Errorf("failed to load validator params: %w", err)
+		}
+	}
+
 	return &BlockGenerator{
 		ctx:            ctx,
 		params:         params,
@@ -185,6 +216,77 @@ func (g *BlockGenerator) updateGasPrices(gasPrices []types.Uint256) error {
 	return nil
 }
 
+// updateValidators activates the scheduled validator changes at the main shard epoch boundary.
+// The new set is used by every shard starting from the block that refers to this main shard block.
+func (g *BlockGenerator) updateValidators(blockId types.BlockNumber) error {
+	if !g.params.ShardId.IsMainShard() {
+		return nil
+	}
+
+	updated, dropped, err := config.ApplyValidatorSchedule(
+		g.executionState.GetConfigAccessor(), blockId, g.executionState.lastReplacedHeight)
+	if err != nil {
+		return err
+	}
+	for _, d := range dropped {
+		g.logger.Warn().
+			Err(d.Err).
+			Stringer(logging.FieldBlockNumber, blockId).
+			Uint32("changeShardId", d.Change.ShardId).
+			Bool("remove", d.Change.Remove).
+			Hex(logging.FieldPublicKey, d.Change.Validator.PublicKey[:]).
+			Msg("Scheduled validator change is dropped")
+	}
+	if updated {
+		g.logger.Info().
+			Stringer(logging.FieldBlockNumber, blockId).
+			Msg("Scheduled validator changes are activated")
+	}
+	return nil
+}
+
+// applyPatches performs the state migrations of the patch levels that the block raises the chain to.
+func (g *BlockGenerator) applyPatches(prevBlockHash common.Hash, patchLevel uint32) error {
+	if !g.params.ShardId.IsMainShard() || patchLevel < PatchLevelGovernanceUpgrade {
+		return nil
+	}
+
+	prevBlock, err := db.ReadBlock(g.rwTx, g.params.ShardId, prevBlockHash)
+	if err != nil {
+		return fmt.Errorf("failed to read previous block: %w", err)
+	}
+	if prevBlock.PatchLevel >= PatchLevelGovernanceUpgrade {
+		return nil
+	}
+	return g.upgradeGovernance()
+}
+
+// upgradeGovernance replaces the code of the Governance contract with the current one.
+// The contract is deployed by the zero state, so networks created before the validator management methods
+// were added have no other way to get them. The storage of the contract, including the owner's key, is kept.
+func (g *BlockGenerator) upgradeGovernance() error {
+	acc, err := g.executionState.GetAccount(types.GovernanceAddress)
+	if err != nil {
+		return err
+	}
+	if acc == nil {
+		g.logger.Warn().Msg("Governance contract is not deployed, nothing to upgrade")
+		return nil
+	}
+
+	code, err := contracts.GetRuntimeCode(contracts.NameGovernance)
+	if err != nil {
+		return fmt.Errorf("failed to get Governance code: %w", err)
+	}
+	if err := g.executionState.SetCode(types.GovernanceAddress, code); err != nil {
+		return err
+	}
+	g.logger.Info().
+		Stringer("codeHash", code.Hash()).
+		Msg("Governance contract is upgraded")
+	return nil
+}
+
 func (g *BlockGenerator) GenerateZeroState(config *ZeroStateConfig) (*types.Block, error) {
 	g.logger.Info().Msg("Generating zero-state...")
 	g.executionState.BaseFee = types.DefaultGasPrice
@@ -210,6 +312,37 @@ func (g *BlockGenerator) GenerateZeroState(config *ZeroStateConfig) (*types.Bloc
 }
 
 func (g *BlockGenerator) prepareExecutionState(proposal *Proposal, gasPrices []types.Uint256) error {
+	if err := g.initExecutionState(proposal, gasPrices); err != nil {
+		return err
+	}
+
+	for _, txn := range proposal.InternalTxns {
+		if _, err := g.handleTxn(txn); err != nil {
+			return err
+		}
+	}
+
+	for _, txn := range proposal.ExternalTxns {
+		if _, err := g.handleTxn(txn); err != nil {
+			return err
+		}
+	}
+
+	for _, txn := range proposal.ForwardTxns {
+		g.executionState.AppendForwardTransaction(txn)
+	}
+
+	g.executionState.ChildShardBlocks = make(map[types.ShardId]common.Hash, len(proposal.ShardHashes))
+	for i, shardHash := range proposal.ShardHashes {
+		g.executionState.ChildShardBlocks[types.ShardId(i+1)] = shardHash
+	}
+
+	g.counters.GasPrice = g.executionState.GasPrice
+
+	return nil
+}
+
+func (g *BlockGenerator) initExecutionState(proposal *Proposal, gasPrices []types.Uint256) error {
 	if g.executionState.PrevBlock != proposal.PrevBlockHash {
 		esJson, err := g.executionState.MarshalJSON()
 		if err != nil {
@@ -242,37 +375,50 @@ func (g *BlockGenerator) prepareExecutionState(proposal *Proposal, gasPrices []t
 		return fmt.Errorf("failed to update gas prices: %w", err)
 	}
 
-	g.executionState.MainShardHash = proposal.MainShardHash
-	g.executionState.PatchLevel = proposal.PatchLevel
-	g.executionState.RollbackCounter = proposal.RollbackCounter
-
-	for _, txn := range proposal.InternalTxns {
-		if err := g.handleTxn(txn); err != nil {
-			return err
-		}
+	if err := g.updateValidators(proposal.PrevBlockId + 1); err != nil {
+		return fmt.Errorf("failed to update validators: %w", err)
 	}
 
-	for _, txn := range proposal.ExternalTxns {
-		if err := g.handleTxn(txn); err != nil {
-			return err
-		}
+	if err := g.applyPatches(proposal.PrevBlockHash, proposal.PatchLevel); err != nil {
+		return fmt.Errorf("failed to apply patches: %w", err)
 	}
 
-	for _, txn := range proposal.ForwardTxns {
-		g.executionState.AppendForwardTransaction(txn)
-	}
+	g.executionState.MainShardHash = proposal.MainShardHash
+	g.executionState.PatchLevel = proposal.PatchLevel
+	g.executionState.RollbackCounter = proposal.RollbackCounter
+	return nil
+}
 
-	g.executionState.ChildShardBlocks = make(map[types.ShardId]common.Hash, len(proposal.ShardHashes))
-	for i, shardHash := range proposal.ShardHashes {
-		g.executionState.ChildShardBlocks[types.ShardId(i+1)] = shardHash
+// ReplayTransaction executes the in-transactions of the proposal in the same order as the block generation does
+// until the transaction with the given hash is handled, and returns its result.
+// The hooks are attached only while the transaction itself is executed,
+// so neither the preceding transactions nor the verification of an external transaction are traced.
+func (g *BlockGenerator) ReplayTransaction(
+	proposal *Proposal,
+	gasPrices []types.Uint256,
+	txnHash common.Hash,
+	hooks *tracing.Hooks,
+) (*ExecutionResult, error) {
+	if err := g.initExecutionState(proposal, gasPrices); err != nil {
+		return nil, err
 	}
 
-	g.counters.GasPrice = g.executionState.GasPrice
+	g.tracedTxn, g.tracedHooks = txnHash, hooks
+	defer func() { g.tracedTxn, g.tracedHooks = common.EmptyHash, nil }()
 
-	return nil
+	for _, txn := range slices.Concat(proposal.InternalTxns, proposal.ExternalTxns) {
+		res, err := g.handleTxn(txn)
+		if err != nil {
+			return nil, err
+		}
+		if txn.Hash() == txnHash {
+			return res, nil
+		}
+	}
+	return nil, fmt.Errorf("transaction %s is not included in the proposal", txnHash)
 }
 
-func (g *BlockGenerator) handleTxn(txn *types.Transaction) error {
+func (g *BlockGenerator) handleTxn(txn *types.Transaction) (*ExecutionResult, error) {
 	if txn.IsDeploy() {
 		g.counters.DeployTransactions++
 	}
@@ -303,7 +449,7 @@ func (g *BlockGenerator) handleTxn(txn *types.Transaction) error {
 	}
 
 	if res.FatalError != nil {
-		return res.FatalError
+		return nil, res.FatalError
 	}
 	g.handleResult(res)
 	g.counters.CoinsUsed = g.counters.CoinsUsed.Add(res.CoinsUsed())
@@ -320,7 +466,7 @@ func (g *BlockGenerator) handleTxn(txn *types.Transaction) error {
 				seqno, newSeqno, res.GasUsed)
 		}
 	}
-	return nil
+	return res, nil
 }
 
 func (g *BlockGenerator) BuildBlock(proposal *Proposal, gasPrices []types.Uint256) (*BlockGenerationResult, error) {
@@ -355,7 +501,7 @@ func (g *BlockGenerator) handleInternalInTransaction(txn *types.Transaction) *Ex
 		return NewExecutionResult().SetError(types.KeepOrWrapError(types.ErrorValidation, err))
 	}
 
-	return g.executionState.HandleTransaction(g.ctx, txn, NewTransactionPayer(txn, g.executionState))
+	return g.executeTransaction(txn, NewTransactionPayer(txn, g.executionState))
 }
 
 func (g *BlockGenerator) handleExternalTransaction(txn *types.Transaction) *ExecutionResult {
@@ -369,11 +515,20 @@ func (g *BlockGenerator) handleExternalTransaction(txn *types.Transaction) *Exec
 	// Validation cached the account.
 	check.PanicIfErr(err)
 
-	res := g.executionState.HandleTransaction(g.ctx, txn, NewAccountPayer(acc, txn))
+	res := g.executeTransaction(txn, NewAccountPayer(acc, txn))
 	res.AddUsed(verifyResult.GasUsed)
 	return res
 }
 
+func (g *BlockGenerator) executeTransaction(txn *types.Transaction, payer Payer) *ExecutionResult {
+	if g.tracedHooks != nil && g.executionState.InTransactionHash == g.tracedTxn {
+		hooks := g.executionState.EvmTracingHooks
+		g.executionState.EvmTracingHooks = g.tracedHooks
+		defer func() { g.executionState.EvmTracingHooks = hooks }()
+	}
+	return g.executionState.HandleTransaction(g.ctx, txn, payer)
+}
+
 func (g *BlockGenerator) handleResult(execResult *ExecutionResult) {
 	check.PanicIfNot(execResult.FatalError == nil)
 
@@ -0,0 +1,267 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/contracts"
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type BlockGeneratorTestSuite struct {
+	suite.Suite
+
+	db        db.DB
+	params    BlockGeneratorParams
+	lastBlock *BlockGenerationResult
+	// txs are the read transactions opened by the test, they are closed before the database.
+	txs []db.RoTx
+}
+
+func (s *BlockGeneratorTestSuite) SetupTest() {
+	var err error
+	s.db, err = db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+	s.params = NewBlockGeneratorParams(types.MainShardId, 2)
+}
+
+func (s *BlockGeneratorTestSuite) TearDownTest() {
+	for _, tx := range s.txs {
+		tx.Rollback()
+	}
+	s.txs = nil
+	s.db.Close()
+}
+
+func (s *BlockGeneratorTestSuite) newRoTx() db.RoTx {
+	s.T().Helper()
+
+	tx, err := s.db.CreateRoTx(s.T().Context())
+	s.Require().NoError(err)
+	s.txs = append(s.txs, tx)
+	return tx
+}
+
+func (s *BlockGeneratorTestSuite) newValidator() config.ValidatorInfo {
+	s.T().Helper()
+
+	pubkey, err := bls.NewRandomKey().PublicKey().Marshal()
+	s.Require().NoError(err)
+
+	var res config.ValidatorInfo
+	copy(res.PublicKey[:], pubkey)
+	return res
+}
+
+func (s *BlockGeneratorTestSuite) newZeroStateConfig(validators ...config.ValidatorInfo) *ZeroStateConfig {
+	return &ZeroStateConfig{
+		ConfigParams: ConfigParams{
+			Validators: config.ParamValidators{
+				Validators: []config.ListValidators{{List: validators}},
+			},
+			GasPrice: config.ParamGasPrice{
+				Shards: []types.Uint256{*types.NewUint256(10), *types.NewUint256(10)},
+			},
+			EpochLength: 2,
+		},
+	}
+}
+
+func (s *BlockGeneratorTestSuite) addGovernance(zeroState *ZeroStateConfig) {
+	s.T().Helper()
+
+	value, err := types.NewValueFromDecimal("1000000000000000000000")
+	s.Require().NoError(err)
+	zeroState.Contracts = append(zeroState.Contracts, &ContractDescr{
+		Name:     "Governance",
+		Contract: contracts.NameGovernance,
+		Address:  types.GovernanceAddress,
+		Value:    value,
+		CtorArgs: []any{hexutil.Encode(MainPublicKey)},
+	})
+}
+
+func (s *BlockGeneratorTestSuite) generateZeroState(params BlockGeneratorParams, zeroState *ZeroStateConfig) *types.Block {
+	s.T().Helper()
+
+	gen, err := NewBlockGenerator(s.T().Context(), params, s.db, nil)
+	s.Require().NoError(err)
+	defer gen.Rollback()
+
+	block, err := gen.GenerateZeroState(zeroState)
+	s.Require().NoError(err)
+	if params.ShardId.IsMainShard() {
+		s.lastBlock = &BlockGenerationResult{Block: block, BlockHash: block.Hash(types.MainShardId)}
+	}
+	return block
+}
+
+// generateBlock generates the next main shard block by the proposal, which is linked to the last block.
+// The state of the generator can be changed by prepare before the block is generated.
+func (s *BlockGeneratorTestSuite) generateBlock(proposal *Proposal, prepare func(es *ExecutionState)) {
+	s.T().Helper()
+
+	gen, err := NewBlockGenerator(s.T().Context(), s.params, s.db, s.lastBlock.Block)
+	s.Require().NoError(err)
+	defer gen.Rollback()
+
+	if prepare != nil {
+		prepare(gen.executionState)
+	}
+
+	proposal.PrevBlockId = s.lastBlock.Block.Id
+	proposal.PrevBlockHash = s.lastBlock.BlockHash
+	s.lastBlock, err = gen.GenerateBlock(proposal, &types.ConsensusParams{})
+	s.Require().NoError(err)
+}
+
+func (s *BlockGeneratorTestSuite) newState() *ExecutionState {
+	s.T().Helper()
+
+	es, err := NewExecutionState(s.newRoTx(), types.MainShardId, StateParams{
+		Block:          s.lastBlock.Block,
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	s.Require().NoError(err)
+	return es
+}
+
+func (s *BlockGeneratorTestSuite) getGovernanceCode() types.Code {
+	s.T().Helper()
+
+	code, _, err := s.newState().GetCode(types.GovernanceAddress)
+	s.Require().NoError(err)
+	return code
+}
+
+func (s *BlockGeneratorTestSuite) getConfig() config.ConfigAccessor {
+	s.T().Helper()
+
+	hash := s.lastBlock.BlockHash
+	c, err := config.NewConfigReader(s.newRoTx(), &hash)
+	s.Require().NoError(err)
+	return c
+}
+
+func (s *BlockGeneratorTestSuite) TestValidatorChangeActivation() {
+	v1, v2 := s.newValidator(), s.newValidator()
+	zeroState := s.newZeroStateConfig(v1)
+	s.addGovernance(zeroState)
+	s.generateZeroState(s.params, zeroState)
+
+	// Block 1 emulates a network created before the validator management methods were added to Governance.
+	oldCode := hexutil.FromHex("600160005260206000f3")
+	s.generateBlock(&Proposal{}, func(es *ExecutionState) {
+		s.Require().NoError(es.SetCode(types.GovernanceAddress, oldCode))
+	})
+	s.Equal(types.Code(oldCode), s.getGovernanceCode())
+
+	// Block 2 raises the patch level, which upgrades the contract.
+	s.generateBlock(&Proposal{PatchLevel: PatchLevelGovernanceUpgrade}, nil)
+	s.Equal(uint32(PatchLevelGovernanceUpgrade), s.lastBlock.Block.PatchLevel)
+	runtimeCode, err := contracts.GetRuntimeCode(contracts.NameGovernance)
+	s.Require().NoError(err)
+	s.Equal(runtimeCode, s.getGovernanceCode())
+
+	// Block 3 schedules the change through the contract and the precompile.
+	abi, err := contracts.GetAbi(contracts.NameGovernance)
+	s.Require().NoError(err)
+	calldata, err := abi.Pack("addValidator", uint32(1), v2.PublicKey[:], types.EmptyAddress, uint64(5))
+	s.Require().NoError(err)
+
+	txn := types.NewEmptyTransaction()
+	txn.To = types.GovernanceAddress
+	txn.Data = calldata
+	txn.FeeCredit = types.GasToValue(10_000_000)
+	txn.MaxFeePerGas = types.MaxFeePerGasDefault
+	s.Require().NoError(txn.Sign(MainPrivateKey))
+	s.generateBlock(&Proposal{PatchLevel: PatchLevelGovernanceUpgrade, ExternalTxns: []*types.Transaction{txn}}, nil)
+
+	schedule, err := config.GetParamValidatorSchedule(s.getConfig())
+	s.Require().NoError(err)
+	s.Require().Len(schedule.Pending, 1)
+	validators, err := config.GetParamValidators(s.getConfig())
+	s.Require().NoError(err)
+	s.Equal([]config.ValidatorInfo{v1}, validators.Validators[0].List)
+
+	// Block 4 starts the next epoch and activates the change.
+	s.generateBlock(&Proposal{PatchLevel: PatchLevelGovernanceUpgrade}, nil)
+
+	schedule, err = config.GetParamValidatorSchedule(s.getConfig())
+	s.Require().NoError(err)
+	s.Empty(schedule.Pending)
+	validators, err = config.GetParamValidators(s.getConfig())
+	s.Require().NoError(err)
+	v2.Weight = 5
+	s.Equal([]config.ValidatorInfo{v1, v2}, validators.Validators[0].List)
+}
+
+func (s *BlockGeneratorTestSuite) TestGovernanceUpgradeOnce() {
+	zeroState := s.newZeroStateConfig(s.newValidator())
+	s.addGovernance(zeroState)
+	s.generateZeroState(s.params, zeroState)
+	s.generateBlock(&Proposal{PatchLevel: PatchLevelGovernanceUpgrade}, nil)
+
+	// The contract is not touched by the blocks that don't raise the patch level.
+	code := hexutil.FromHex("600160005260206000f3")
+	s.generateBlock(&Proposal{PatchLevel: PatchLevelGovernanceUpgrade}, func(es *ExecutionState) {
+		s.Require().NoError(es.SetCode(types.GovernanceAddress, code))
+	})
+	s.generateBlock(&Proposal{PatchLevel: PatchLevelGovernanceUpgrade}, nil)
+	s.Equal(types.Code(code), s.getGovernanceCode())
+}
+
+func (s *BlockGeneratorTestSuite) TestShardValidators() {
+	v1, v2 := s.newValidator(), s.newValidator()
+	zeroState := s.newZeroStateConfig(v1)
+	s.generateZeroState(s.params, zeroState)
+	shardBlock := s.generateZeroState(NewBlockGeneratorParams(types.BaseShardId, 2), zeroState)
+
+	// The change scheduled in block 1 is activated by block 2.
+	shardHashes := []common.Hash{shardBlock.Hash(types.BaseShardId)}
+	s.generateBlock(&Proposal{ShardHashes: shardHashes}, func(es *ExecutionState) {
+		s.Require().NoError(config.ScheduleValidatorChange(es.GetConfigAccessor(), config.ValidatorChange{
+			ShardId:   uint32(types.BaseShardId),
+			Validator: v2,
+		}))
+	})
+	s.generateBlock(&Proposal{ShardHashes: shardHashes}, nil)
+	s.generateBlock(&Proposal{ShardHashes: shardHashes}, nil)
+	// The validators of the past heights are taken from the config, not from the blocks.
+	es, err := NewExecutionState(s.newRoTx(), types.MainShardId, StateParams{
+		Block:          s.lastBlock.Block,
+		ConfigAccessor: s.getConfig(),
+	})
+	s.Require().NoError(err)
+
+	// The block of the base shard was generated before the change.
+	validators, err := es.GetShardValidators(types.BaseShardId, 1)
+	s.Require().NoError(err)
+	s.Equal([]config.ValidatorInfo{v1}, validators)
+
+	// The validators of a height are the ones that the consensus uses for it.
+	validators, err = es.GetShardValidators(types.MainShardId, 3)
+	s.Require().NoError(err)
+	s.Equal([]config.ValidatorInfo{v1}, validators)
+	validators, err = es.GetShardValidators(types.MainShardId, 4)
+	s.Require().NoError(err)
+	s.Equal([]config.ValidatorInfo{v1, v2}, validators)
+
+	_, err = es.GetShardValidators(types.BaseShardId, 2)
+	s.Require().ErrorContains(err, "is not known to the main shard")
+	_, err = es.GetShardValidators(types.MainShardId, 5)
+	s.Require().ErrorContains(err, "is not known to the main shard")
+	_, err = es.GetShardValidators(types.MainShardId, 0)
+	s.Require().Error(err)
+}
+
+func TestBlockGenerator(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(BlockGeneratorTestSuite))
+}
@@ -0,0 +1,140 @@
+package execution
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// logsIndexRebuildBatchSize is the number of blocks visited between the commits of the rebuild progress.
+const logsIndexRebuildBatchSize = 1000
+
+// RebuildLogsIndex fills the logs bloom index for the blocks that were committed before the index existed
+// (or were fetched in a snapshot without it). The new blocks are indexed on commit, so the chain is walked
+// back from the head down to the first local block. The lowest visited block is committed along with each batch
+// of the index entries, and the next run resumes below it, so an interrupted rebuild leaves no gaps.
+// Once the walk reaches the first local block, a run costs a couple of lookups.
+func RebuildLogsIndex(ctx context.Context, database db.DB, shardId types.ShardId, logger logging.Logger) error {
+	roTx, err := database.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer roTx.Rollback()
+
+	block, hash, err := logsIndexRebuildStart(roTx, shardId)
+	if err != nil || block == nil {
+		return err
+	}
+
+	type entry struct {
+		id    types.BlockNumber
+		hash  common.Hash
+		bloom types.Bloom
+	}
+	batch := make([]entry, 0, logsIndexRebuildBatchSize)
+	var visited uint64
+	var lowestHash common.Hash
+	flush := func() error {
+		if lowestHash.Empty() {
+			return nil
+		}
+		rwTx, err := database.CreateRwTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer rwTx.Rollback()
+		for _, e := range batch {
+			if err := db.WriteBlockLogsBloom(rwTx, shardId, e.id, e.hash, e.bloom); err != nil {
+				return err
+			}
+		}
+		if err := db.WriteLogsIndexLowestBlock(rwTx, shardId, lowestHash); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		lowestHash = common.EmptyHash
+		return rwTx.Commit()
+	}
+
+	var indexed uint64
+	for block != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// The blocks indexed on commit are only skipped: an indexed block doesn't mean that the ones below it are.
+		indexedHash, _, err := db.ReadBlockLogsBloom(roTx, shardId, block.Id)
+		if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+			return err
+		}
+		if err != nil || indexedHash != hash {
+			batch = append(batch, entry{id: block.Id, hash: hash, bloom: block.LogsBloom})
+			indexed++
+		}
+		lowestHash = hash
+		visited++
+		if visited%logsIndexRebuildBatchSize == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+
+		if block, hash, err = readPrevLocalBlock(roTx, shardId, block); err != nil {
+			return err
+		}
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+
+	if indexed > 0 {
+		logger.Info().
+			Stringer(logging.FieldShardId, shardId).
+			Uint64("blocks", indexed).
+			Msg("Rebuilt logs bloom index")
+	}
+	return nil
+}
+
+// logsIndexRebuildStart returns the block the rebuild continues from: the one below the lowest block reached
+// by the previous runs or the head if there were none. It returns nil if there is nothing to index.
+func logsIndexRebuildStart(tx db.RoTx, shardId types.ShardId) (*types.Block, common.Hash, error) {
+	lowestHash, err := db.ReadLogsIndexLowestBlock(tx, shardId)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		block, hash, err := db.ReadLastBlock(tx, shardId)
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil, common.EmptyHash, nil
+		}
+		return block, hash, err
+	}
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+
+	lowest, err := db.ReadBlock(tx, shardId, lowestHash)
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+	return readPrevLocalBlock(tx, shardId, lowest)
+}
+
+// readPrevLocalBlock returns the parent of the block or nil if the block is the first one stored locally
+// (the genesis block or the first block fetched in a snapshot).
+func readPrevLocalBlock(tx db.RoTx, shardId types.ShardId, block *types.Block) (*types.Block, common.Hash, error) {
+	if block.Id == 0 || block.PrevBlock.Empty() {
+		return nil, common.EmptyHash, nil
+	}
+	prev, err := db.ReadBlock(tx, shardId, block.PrevBlock)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, common.EmptyHash, nil
+	}
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+	return prev, block.PrevBlock, nil
+}
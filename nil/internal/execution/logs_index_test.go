@@ -0,0 +1,117 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebuildLogsIndex(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	shardId := types.BaseShardId
+	const numBlocks = 5
+
+	// Only the first blocks are indexed, as if the node was updated in the middle of the chain.
+	hashes, blooms := writeLogsIndexTestChain(t, database, shardId, numBlocks, func(i int) bool { return i < 2 })
+
+	require.NoError(t, RebuildLogsIndex(ctx, database, shardId, logging.Nop()))
+
+	roTx, err := database.CreateRoTx(ctx)
+	require.NoError(t, err)
+	defer roTx.Rollback()
+
+	for i := range numBlocks {
+		hash, bloom, err := db.ReadBlockLogsBloom(roTx, shardId, types.BlockNumber(i))
+		require.NoError(t, err)
+		require.Equal(t, hashes[i], hash)
+		require.Equal(t, blooms[i], bloom)
+	}
+
+	var visited []types.BlockNumber
+	require.NoError(t, db.IterBlockLogsBloom(roTx, shardId, 1, 3,
+		func(id types.BlockNumber, _ common.Hash, _ types.Bloom) (bool, error) {
+			visited = append(visited, id)
+			return true, nil
+		}))
+	require.Equal(t, []types.BlockNumber{1, 2, 3}, visited)
+}
+
+func TestRebuildLogsIndexInterrupted(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	shardId := types.BaseShardId
+	const numBlocks = 6
+
+	// The previous run indexed blocks 4 and 3 and was interrupted, then block 5 was indexed on commit.
+	hashes, blooms := writeLogsIndexTestChain(t, database, shardId, numBlocks, func(i int) bool { return i >= 3 })
+	tx, err := database.CreateRwTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, db.WriteLogsIndexLowestBlock(tx, shardId, hashes[3]))
+	require.NoError(t, tx.Commit())
+
+	require.NoError(t, RebuildLogsIndex(ctx, database, shardId, logging.Nop()))
+
+	roTx, err := database.CreateRoTx(ctx)
+	require.NoError(t, err)
+	defer roTx.Rollback()
+
+	for i := range numBlocks {
+		hash, bloom, err := db.ReadBlockLogsBloom(roTx, shardId, types.BlockNumber(i))
+		require.NoError(t, err)
+		require.Equal(t, hashes[i], hash)
+		require.Equal(t, blooms[i], bloom)
+	}
+
+	lowest, err := db.ReadLogsIndexLowestBlock(roTx, shardId)
+	require.NoError(t, err)
+	require.Equal(t, hashes[0], lowest)
+}
+
+// writeLogsIndexTestChain writes a chain of blocks with distinct logs blooms and indexes the ones selected by indexed.
+func writeLogsIndexTestChain(
+	t *testing.T, database db.DB, shardId types.ShardId, numBlocks int, indexed func(i int) bool,
+) ([]common.Hash, []types.Bloom) {
+	t.Helper()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	hashes := make([]common.Hash, numBlocks)
+	blooms := make([]types.Bloom, numBlocks)
+	prevHash := common.EmptyHash
+	for i := range numBlocks {
+		blooms[i] = types.CreateBloom(types.Receipts{{
+			Logs: []*types.Log{{Address: types.HexToAddress("0x1234"), Topics: []common.Hash{{byte(i)}}}},
+		}})
+		block := &types.Block{
+			BlockData: types.BlockData{Id: types.BlockNumber(i), PrevBlock: prevHash},
+			LogsBloom: blooms[i],
+		}
+		hashes[i] = block.Hash(shardId)
+		prevHash = hashes[i]
+		require.NoError(t, db.WriteBlock(tx, shardId, hashes[i], block))
+		require.NoError(t, db.WriteLastBlockHash(tx, shardId, hashes[i]))
+
+		if indexed(i) {
+			require.NoError(t, db.WriteBlockLogsBloom(tx, shardId, block.Id, hashes[i], blooms[i]))
+		}
+	}
+	require.NoError(t, tx.Commit())
+	return hashes, blooms
+}
@@ -30,6 +30,7 @@ func (pp *blockPostprocessor) Postprocess() error {
 		pp.fillLastBlockTable,
 		pp.fillBlockHashByNumberIndex,
 		pp.fillBlockHashAndTransactionIndexByTransactionHash,
+		pp.fillLogsBloomIndex,
 	} {
 		if err := postpocessor(); err != nil {
 			return err
@@ -47,6 +48,11 @@ func (pp *blockPostprocessor) fillBlockHashByNumberIndex() error {
 		pp.shardId, db.BlockHashByNumberIndex, pp.blockResult.Block.Id.Bytes(), pp.blockResult.BlockHash.Bytes())
 }
 
+func (pp *blockPostprocessor) fillLogsBloomIndex() error {
+	return db.WriteBlockLogsBloom(
+		pp.tx, pp.shardId, pp.blockResult.Block.Id, pp.blockResult.BlockHash, pp.blockResult.Block.LogsBloom)
+}
+
 func (pp *blockPostprocessor) fillBlockHashAndTransactionIndexByTransactionHash() error {
 	fill := func(txnHashes []common.Hash, table db.ShardedTableName) error {
 		for i, hash := range txnHashes {
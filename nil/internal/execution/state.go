@@ -1904,6 +1904,70 @@ func (es *ExecutionState) GetRollback() *RollbackParams {
 	return es.rollback
 }
 
+// lastKnownBlock returns the number of the last block of the shard known to the main shard before this block.
+// Every node that runs the main shard keeps these blocks: the gas prices are collected from them,
+// and state sync downloads them along with the pivot block.
+func (es *ExecutionState) lastKnownBlock(shardId types.ShardId) (types.BlockNumber, error) {
+	mainBlock, err := db.ReadBlock(es.tx, types.MainShardId, es.PrevBlock)
+	if err != nil {
+		return 0, err
+	}
+	if shardId.IsMainShard() {
+		return mainBlock.Id, nil
+	}
+
+	treeShards := NewDbShardBlocksTrieReader(es.tx, types.MainShardId, mainBlock.Id)
+	treeShards.SetRootHash(mainBlock.ChildBlocksRootHash)
+	shardHash, err := treeShards.Fetch(shardId)
+	if err != nil {
+		return 0, fmt.Errorf("shard %s is not known to the main shard: %w", shardId, err)
+	}
+	shardBlock, err := db.ReadBlock(es.tx, shardId, *shardHash)
+	if err != nil {
+		return 0, err
+	}
+	return shardBlock.Id, nil
+}
+
+// lastReplacedHeight returns the last height of the shard that is verified by the validator set
+// replaced in this block for sure. The validators of a main shard block are defined by the configuration
+// of the block before the previous one, so the next block still uses the replaced set.
+// A block of another shard uses the configuration of the main shard block that its previous block refers to,
+// so the block following the last known one is the last that surely does it.
+func (es *ExecutionState) lastReplacedHeight(shardId types.ShardId) (uint64, error) {
+	lastKnown, err := es.lastKnownBlock(shardId)
+	if err != nil {
+		return 0, err
+	}
+	if shardId.IsMainShard() {
+		return uint64(lastKnown) + 2, nil
+	}
+	return uint64(lastKnown) + 1, nil
+}
+
+// GetShardValidators returns the validators that run the consensus of the shard block with the given number.
+// The validators are taken from the history in the config, so the result doesn't depend on the blocks
+// the node has received on its own. Only the heights up to the one following the last block of the shard
+// known to the main shard can be checked.
+func (es *ExecutionState) GetShardValidators(shardId types.ShardId, height uint64) ([]config.ValidatorInfo, error) {
+	if !es.ShardId.IsMainShard() {
+		return nil, errors.New("validators can be checked only in the main shard")
+	}
+	if height == 0 {
+		return nil, errors.New("zero state has no validators")
+	}
+
+	lastKnown, err := es.lastKnownBlock(shardId)
+	if err != nil {
+		return nil, err
+	}
+	// The validators of a height are defined by the configuration of the previous block.
+	if prevId := types.BlockNumber(height - 1); prevId > lastKnown {
+		return nil, fmt.Errorf("block %d of shard %s is not known to the main shard yet", prevId, shardId)
+	}
+	return config.GetShardValidatorsAt(es.GetConfigAccessor(), shardId, height)
+}
+
 func (es *ExecutionState) SetTokenTransfer(tokens []types.TokenBalance) {
 	es.evm.SetTokenTransfer(tokens)
 }
@@ -2010,7 +2074,7 @@ func (es *ExecutionState) DeleteOutTransaction(index int, txnHash common.Hash) {
 }
 
 func (es *ExecutionState) preTxHookCall(txn *types.Transaction) {
-	if es.EvmTracingHooks != nil && es.EvmTracingHooks.OnTxEnd != nil {
+	if es.EvmTracingHooks != nil && es.EvmTracingHooks.OnTxStart != nil {
 		es.EvmTracingHooks.OnTxStart(es.evm.GetVMContext(), txn)
 	}
 }
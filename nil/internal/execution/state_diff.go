@@ -0,0 +1,179 @@
+package execution
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// ContractDiff holds the changes of a contract between two states of a shard, the unchanged fields are nil.
+// All the fields are set for a created contract, only Deleted is set for a removed one.
+// The removed entries of the storage and the tokens have zero values, the ones of the async context are nil.
+type ContractDiff struct {
+	Address      types.Address                                  `json:"address"`
+	Deleted      bool                                           `json:"deleted,omitempty"`
+	Balance      *types.Value                                   `json:"balance,omitempty"`
+	Seqno        *types.Seqno                                   `json:"seqno,omitempty"`
+	ExtSeqno     *types.Seqno                                   `json:"extSeqno,omitempty"`
+	Code         types.Code                                     `json:"code,omitempty"`
+	Storage      map[common.Hash]common.Hash                    `json:"storage,omitempty"`
+	Tokens       map[types.TokenId]types.Value                  `json:"tokens,omitempty"`
+	AsyncContext map[types.TransactionIndex]*types.AsyncContext `json:"asyncContext,omitempty"`
+}
+
+type trieDiffEntry[K any, VPtr any] struct {
+	key      K
+	from, to VPtr
+}
+
+// diffTries returns the entries that differ in the tries with the given roots, the missing values are nil.
+func diffTries[K any, V any, VPtr MPTValue[V]](
+	newReader func() *BaseMPTReader[K, V, VPtr], fromRoot, toRoot common.Hash,
+) ([]trieDiffEntry[K, VPtr], error) {
+	from, to := newReader(), newReader()
+	from.SetRootHash(fromRoot)
+	to.SetRootHash(toRoot)
+
+	entries, err := mpt.Diff(from.Reader, to.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	decode := func(data []byte) (VPtr, error) {
+		if data == nil {
+			return nil, nil
+		}
+		v := from.newV()
+		return v, v.UnmarshalSSZ(data)
+	}
+
+	res := make([]trieDiffEntry[K, VPtr], 0, len(entries))
+	for _, entry := range entries {
+		key := from.keyFromBytes(entry.Key)
+		if key == nil {
+			continue
+		}
+		fromValue, err := decode(entry.From)
+		if err != nil {
+			return nil, err
+		}
+		toValue, err := decode(entry.To)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, trieDiffEntry[K, VPtr]{key: *key, from: fromValue, to: toValue})
+	}
+	return res, nil
+}
+
+// GetStateDiff returns the contracts changed in the shard by the blocks after `from` up to `to` inclusive,
+// sorted by address. Nil `from` means the empty state, so all the contracts of `to` are returned.
+// Only the tries that differ are read, so the cost is proportional to the size of the changes.
+func GetStateDiff(tx db.RoTx, shardId types.ShardId, from, to *types.Block) ([]*ContractDiff, error) {
+	fromRoot := common.EmptyHash
+	if from != nil {
+		fromRoot = from.SmartContractsRoot
+	}
+	entries, err := diffTries(
+		func() *ContractTrieReader { return NewDbContractTrieReader(tx, shardId) },
+		fromRoot, to.SmartContractsRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*ContractDiff, 0, len(entries))
+	for _, entry := range entries {
+		if entry.to == nil {
+			res = append(res, &ContractDiff{Address: entry.from.Address, Deleted: true})
+			continue
+		}
+		diff, err := newContractDiff(tx, shardId, entry.from, entry.to)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, diff)
+	}
+
+	slices.SortFunc(res, func(a, b *ContractDiff) int {
+		return bytes.Compare(a.Address.Bytes(), b.Address.Bytes())
+	})
+	return res, nil
+}
+
+func newContractDiff(tx db.RoTx, shardId types.ShardId, from, to *types.SmartContract) (*ContractDiff, error) {
+	// The created contract is compared with the empty one, so that all its fields are set.
+	created := from == nil
+	if created {
+		from = &types.SmartContract{}
+	}
+
+	diff := &ContractDiff{Address: to.Address}
+	if created || from.Balance.Cmp(to.Balance) != 0 {
+		diff.Balance = &to.Balance
+	}
+	if created || from.Seqno != to.Seqno {
+		diff.Seqno = &to.Seqno
+	}
+	if created || from.ExtSeqno != to.ExtSeqno {
+		diff.ExtSeqno = &to.ExtSeqno
+	}
+	if from.CodeHash != to.CodeHash && to.CodeHash != common.EmptyHash {
+		code, err := db.ReadCode(tx, shardId, to.CodeHash)
+		if err != nil {
+			return nil, err
+		}
+		diff.Code = code
+	}
+
+	storage, err := diffTries(
+		func() *StorageTrieReader { return NewDbStorageTrieReader(tx, shardId) },
+		from.StorageRoot, to.StorageRoot)
+	if err != nil {
+		return nil, err
+	}
+	if len(storage) > 0 {
+		diff.Storage = make(map[common.Hash]common.Hash, len(storage))
+		for _, entry := range storage {
+			var value common.Hash
+			if entry.to != nil {
+				value = entry.to.Bytes32()
+			}
+			diff.Storage[entry.key] = value
+		}
+	}
+
+	tokens, err := diffTries(
+		func() *TokenTrieReader { return NewDbTokenTrieReader(tx, shardId) },
+		from.TokenRoot, to.TokenRoot)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) > 0 {
+		diff.Tokens = make(map[types.TokenId]types.Value, len(tokens))
+		for _, entry := range tokens {
+			value := types.NewZeroValue()
+			if entry.to != nil {
+				value = *entry.to
+			}
+			diff.Tokens[entry.key] = value
+		}
+	}
+
+	asyncContext, err := diffTries(
+		func() *AsyncContextTrieReader { return NewDbAsyncContextTrieReader(tx, shardId) },
+		from.AsyncContextRoot, to.AsyncContextRoot)
+	if err != nil {
+		return nil, err
+	}
+	if len(asyncContext) > 0 {
+		diff.AsyncContext = make(map[types.TransactionIndex]*types.AsyncContext, len(asyncContext))
+		for _, entry := range asyncContext {
+			diff.AsyncContext[entry.key] = entry.to
+		}
+	}
+	return diff, nil
+}
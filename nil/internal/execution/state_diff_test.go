@@ -0,0 +1,138 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStateDiff(t *testing.T) {
+	t.Parallel()
+
+	const shardId = types.BaseShardId
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	commit := func(prev *types.Block, blockId types.BlockNumber, update func(es *ExecutionState)) *types.Block {
+		t.Helper()
+
+		es, err := NewExecutionState(tx, shardId, StateParams{
+			Block:          prev,
+			ConfigAccessor: config.GetStubAccessor(),
+		})
+		require.NoError(t, err)
+		update(es)
+		res, err := es.Commit(blockId, &types.ConsensusParams{})
+		require.NoError(t, err)
+		return res.Block
+	}
+
+	accA := types.ShardAndHexToAddress(shardId, "0x0a")
+	accB := types.ShardAndHexToAddress(shardId, "0x0b")
+	accC := types.ShardAndHexToAddress(shardId, "0x0c")
+	slot1, slot2 := common.HexToHash("0x01"), common.HexToHash("0x02")
+	token1, token2 := *types.TokenIdForAddress(accB), *types.TokenIdForAddress(accC)
+	code := types.Code{0x60, 0x01}
+
+	block0 := commit(nil, 0, func(es *ExecutionState) {
+		require.NoError(t, es.CreateAccount(accA))
+		require.NoError(t, es.SetBalance(accA, types.NewValueFromUint64(100)))
+		require.NoError(t, es.SetState(accA, slot1, common.IntToHash(1)))
+		require.NoError(t, es.AddToken(accA, token1, types.NewValueFromUint64(10)))
+		require.NoError(t, es.SetAsyncContext(accA, 1, &types.AsyncContext{ResponseProcessingGas: 5}))
+		require.NoError(t, es.CreateAccount(accB))
+		require.NoError(t, es.SetBalance(accB, types.NewValueFromUint64(7)))
+	})
+	block1 := commit(block0, 1, func(es *ExecutionState) {
+		require.NoError(t, es.SetSeqno(accA, 1))
+		require.NoError(t, es.SetState(accA, slot2, common.IntToHash(2)))
+		require.NoError(t, es.SubToken(accA, token1, types.NewValueFromUint64(10)))
+		require.NoError(t, es.AddToken(accA, token2, types.NewValueFromUint64(3)))
+		acc, err := es.getOrNewAccount(accA)
+		require.NoError(t, err)
+		_, err = acc.GetAndRemoveAsyncContext(1)
+		require.NoError(t, err)
+		require.NoError(t, es.SetAsyncContext(accA, 2, &types.AsyncContext{ResponseProcessingGas: 6}))
+		require.NoError(t, es.CreateAccount(accC))
+		require.NoError(t, es.SetCode(accC, code))
+	})
+	block2 := commit(block1, 2, func(es *ExecutionState) {
+		require.NoError(t, es.SetBalance(accB, types.NewValueFromUint64(8)))
+	})
+
+	balance := func(v uint64) *types.Value {
+		res := types.NewValueFromUint64(v)
+		return &res
+	}
+	seqno := func(v types.Seqno) *types.Seqno {
+		return &v
+	}
+
+	t.Run("FromEmptyState", func(t *testing.T) {
+		diff, err := GetStateDiff(tx, shardId, nil, block0)
+		require.NoError(t, err)
+		require.Equal(t, []*ContractDiff{
+			{
+				Address:      accA,
+				Balance:      balance(100),
+				Seqno:        seqno(0),
+				ExtSeqno:     seqno(0),
+				Storage:      map[common.Hash]common.Hash{slot1: common.IntToHash(1)},
+				Tokens:       map[types.TokenId]types.Value{token1: types.NewValueFromUint64(10)},
+				AsyncContext: map[types.TransactionIndex]*types.AsyncContext{1: {ResponseProcessingGas: 5}},
+			},
+			{
+				Address:  accB,
+				Balance:  balance(7),
+				Seqno:    seqno(0),
+				ExtSeqno: seqno(0),
+			},
+		}, diff)
+	})
+
+	t.Run("SeveralBlocks", func(t *testing.T) {
+		diff, err := GetStateDiff(tx, shardId, block0, block2)
+		require.NoError(t, err)
+		require.Equal(t, []*ContractDiff{
+			{
+				Address: accA,
+				Seqno:   seqno(1),
+				Storage: map[common.Hash]common.Hash{slot2: common.IntToHash(2)},
+				Tokens: map[types.TokenId]types.Value{
+					token1: types.NewZeroValue(),
+					token2: types.NewValueFromUint64(3),
+				},
+				AsyncContext: map[types.TransactionIndex]*types.AsyncContext{
+					1: nil,
+					2: {ResponseProcessingGas: 6},
+				},
+			},
+			{
+				Address: accB,
+				Balance: balance(8),
+			},
+			{
+				Address:  accC,
+				Balance:  balance(0),
+				Seqno:    seqno(0),
+				ExtSeqno: seqno(0),
+				Code:     code,
+			},
+		}, diff)
+	})
+
+	t.Run("SameBlock", func(t *testing.T) {
+		diff, err := GetStateDiff(tx, shardId, block2, block2)
+		require.NoError(t, err)
+		require.Empty(t, diff)
+	})
+}
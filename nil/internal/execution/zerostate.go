@@ -32,6 +32,8 @@ type MainKeys struct {
 type ConfigParams struct {
 	Validators config.ParamValidators `yaml:"validators,omitempty"`
 	GasPrice   config.ParamGasPrice   `yaml:"gasPrice"`
+	// EpochLength is the number of main shard blocks between the activations of scheduled validator changes.
+	EpochLength uint64 `yaml:"epochLength,omitempty"`
 }
 
 type ZeroStateConfig struct {
@@ -152,6 +154,15 @@ func (es *ExecutionState) GenerateZeroState(stateConfig *ZeroStateConfig) error
 		if err != nil {
 			return err
 		}
+		// The schedule is written only if configured, otherwise the genesis hash would differ from the existing networks.
+		if stateConfig.ConfigParams.EpochLength != 0 {
+			err = config.SetParamValidatorSchedule(cfgAccessor, &config.ParamValidatorSchedule{
+				EpochLength: stateConfig.ConfigParams.EpochLength,
+			})
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	if len(stateConfig.ConfigParams.GasPrice.Shards) != 0 {
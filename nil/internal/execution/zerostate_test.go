@@ -253,3 +253,35 @@ func TestSuiteZeroState(t *testing.T) {
 
 	suite.Run(t, new(SuiteZeroState))
 }
+
+// TestZeroStateHash checks that the zero state of a network is not changed by the new config params,
+// as the genesis blocks of the existing networks wouldn't match otherwise.
+func TestZeroStateHash(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	zeroState := &ZeroStateConfig{
+		ConfigParams: ConfigParams{
+			GasPrice: config.ParamGasPrice{
+				Shards: []types.Uint256{*types.NewUint256(10), *types.NewUint256(10)},
+			},
+		},
+	}
+	// The main shard goes first, as the zero states of the other shards refer to it.
+	for _, test := range []struct {
+		shardId types.ShardId
+		hash    string
+	}{
+		{types.MainShardId, "0x00008894918aa743d4ac1cc4793e750cb21a8ff7cec8a23d7bac9dd19604df08"},
+		{types.BaseShardId, "0x000115971df44ad8036a6bf1dce63b3a49c8d9c14fe998a976c9836004b947a5"},
+	} {
+		g, err := NewBlockGenerator(t.Context(), NewBlockGeneratorParams(test.shardId, 2), database, nil)
+		require.NoError(t, err)
+		block, err := g.GenerateZeroState(zeroState)
+		require.NoError(t, err)
+		require.Equal(t, common.HexToHash(test.hash), block.Hash(test.shardId), "shard %d", test.shardId)
+	}
+}
@@ -0,0 +1,124 @@
+package mpt
+
+import (
+	"bytes"
+
+	"github.com/NilFoundation/nil/nil/common"
+)
+
+// DiffEntry is a key that has different values in two tries. The value is nil if the key is missing in the trie.
+type DiffEntry struct {
+	Key  []byte
+	From []byte
+	To   []byte
+}
+
+// Diff returns the entries that were added, removed or changed in the `to` trie comparing to the `from` one.
+// The subtrees with equal references are skipped, so only the nodes on the paths to the changes are read.
+func Diff(from, to *Reader) ([]DiffEntry, error) {
+	var res []DiffEntry
+	if err := diffNodes(from, to, from.root, to.root, newPath(nil, false), &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func isEmptyRef(ref Reference) bool {
+	return !ref.IsValid() || bytes.Equal(ref, common.EmptyHash.Bytes())
+}
+
+func diffNodes(from, to *Reader, fromRef, toRef Reference, path *Path, res *[]DiffEntry) error {
+	if bytes.Equal(fromRef, toRef) || (isEmptyRef(fromRef) && isEmptyRef(toRef)) {
+		return nil
+	}
+	if !isEmptyRef(fromRef) && !isEmptyRef(toRef) {
+		fromNode, err := from.getNode(fromRef)
+		if err != nil {
+			return err
+		}
+		toNode, err := to.getNode(toRef)
+		if err != nil {
+			return err
+		}
+
+		// Descend while both tries have the same structure, the refs of the unchanged subtrees are equal then.
+		fromBranch, fromIsBranch := fromNode.(*BranchNode)
+		toBranch, toIsBranch := toNode.(*BranchNode)
+		if fromIsBranch && toIsBranch {
+			appendDiffEntry(res, path.Data, fromBranch.Data(), toBranch.Data())
+			for i := range BranchesNum {
+				nibble := path.Combine(newPath([]byte{byte(i)}, true))
+				if err := diffNodes(from, to, fromBranch.Branches[i], toBranch.Branches[i], nibble, res); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		fromExt, fromIsExt := fromNode.(*ExtensionNode)
+		toExt, toIsExt := toNode.(*ExtensionNode)
+		if fromIsExt && toIsExt && fromExt.Path().Equal(toExt.Path()) {
+			return diffNodes(from, to, fromExt.NextRef, toExt.NextRef, path.Combine(fromExt.Path()), res)
+		}
+	}
+
+	// The structure differs, so the entries of both subtrees are compared by keys.
+	fromEntries := make(map[string][]byte)
+	if err := collectEntries(from, fromRef, path, fromEntries); err != nil {
+		return err
+	}
+	toEntries := make(map[string][]byte)
+	if err := collectEntries(to, toRef, path, toEntries); err != nil {
+		return err
+	}
+	for key, value := range fromEntries {
+		appendDiffEntry(res, []byte(key), value, toEntries[key])
+	}
+	for key, value := range toEntries {
+		if _, ok := fromEntries[key]; !ok {
+			appendDiffEntry(res, []byte(key), nil, value)
+		}
+	}
+	return nil
+}
+
+func appendDiffEntry(res *[]DiffEntry, key, from, to []byte) {
+	if bytes.Equal(from, to) {
+		return
+	}
+	entry := DiffEntry{Key: bytes.Clone(key)}
+	if len(from) > 0 {
+		entry.From = from
+	}
+	if len(to) > 0 {
+		entry.To = to
+	}
+	*res = append(*res, entry)
+}
+
+// collectEntries works the same way as Iterate, but fails if a node of the subtree can't be read.
+func collectEntries(m *Reader, ref Reference, path *Path, res map[string][]byte) error {
+	if isEmptyRef(ref) {
+		return nil
+	}
+	node, err := m.getNode(ref)
+	if err != nil {
+		return err
+	}
+	if npath := node.Path(); npath != nil {
+		path = path.Combine(npath)
+	}
+	if data := node.Data(); len(data) > 0 {
+		res[string(path.Data)] = data
+	}
+	switch node := node.(type) {
+	case *BranchNode:
+		for i, br := range node.Branches {
+			if err := collectEntries(m, br, path.Combine(newPath([]byte{byte(i)}, true)), res); err != nil {
+				return err
+			}
+		}
+	case *ExtensionNode:
+		return collectEntries(m, node.NextRef, path, res)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package mpt_test
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/stretchr/testify/require"
+)
+
+func collectDiff(t *testing.T, holder mpt.InMemHolder, from, to *mpt.MerklePatriciaTrie) map[string][2]string {
+	t.Helper()
+
+	fromReader := mpt.NewMPTFromMap(holder)
+	fromReader.SetRootHash(from.RootHash())
+	toReader := mpt.NewMPTFromMap(holder)
+	toReader.SetRootHash(to.RootHash())
+
+	diff, err := mpt.Diff(fromReader.Reader, toReader.Reader)
+	require.NoError(t, err)
+
+	res := make(map[string][2]string, len(diff))
+	for _, entry := range diff {
+		require.NotContains(t, res, string(entry.Key))
+		res[string(entry.Key)] = [2]string{string(entry.From), string(entry.To)}
+	}
+	return res
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	holder := mpt.NewInMemHolder()
+	trie := mpt.NewMPTFromMap(holder)
+	empty := mpt.NewMPTFromMap(holder)
+
+	keys := [][]byte{[]byte("do"), []byte("dog"), []byte("doge"), []byte("horse")}
+	values := [][]byte{[]byte("verb"), []byte("puppy"), []byte("coin"), []byte("stallion")}
+	for i := range keys {
+		require.NoError(t, trie.Set(keys[i], values[i]))
+	}
+	old := mpt.NewMPTFromMap(holder)
+	old.SetRootHash(trie.RootHash())
+
+	require.Empty(t, collectDiff(t, holder, trie, old))
+	require.Equal(t, map[string][2]string{
+		"do":    {"", "verb"},
+		"dog":   {"", "puppy"},
+		"doge":  {"", "coin"},
+		"horse": {"", "stallion"},
+	}, collectDiff(t, holder, empty, trie))
+
+	require.NoError(t, trie.Delete([]byte("dog")))
+	require.NoError(t, trie.Set([]byte("do"), []byte("noun")))
+	require.NoError(t, trie.Set([]byte("dot"), []byte("point")))
+	require.Equal(t, map[string][2]string{
+		"do":  {"verb", "noun"},
+		"dog": {"puppy", ""},
+		"dot": {"", "point"},
+	}, collectDiff(t, holder, old, trie))
+}
+
+func TestDiffRandom(t *testing.T) {
+	t.Parallel()
+
+	gen := newRandGen()
+	holder := mpt.NewInMemHolder()
+	trie := mpt.NewMPTFromMap(holder)
+
+	state := make(map[string]string)
+	for _, op := range generateTestCase(gen, 1000, 1, 8, "abcdefgh") {
+		require.NoError(t, trie.Set(op.key, op.value))
+		state[string(op.key)] = string(op.value)
+	}
+	old := mpt.NewMPTFromMap(holder)
+	old.SetRootHash(trie.RootHash())
+	oldState := maps.Clone(state)
+
+	for i, op := range generateTestCase(gen, 300, 1, 8, "abcdefgh") {
+		if _, ok := state[string(op.key)]; ok && i%2 == 0 {
+			require.NoError(t, trie.Delete(op.key))
+			delete(state, string(op.key))
+			continue
+		}
+		require.NoError(t, trie.Set(op.key, op.value))
+		state[string(op.key)] = string(op.value)
+	}
+
+	expected := make(map[string][2]string)
+	for key, value := range oldState {
+		if state[key] != value {
+			expected[key] = [2]string{value, state[key]}
+		}
+	}
+	for key, value := range state {
+		if _, ok := oldState[key]; !ok {
+			expected[key] = [2]string{"", value}
+		}
+	}
+	require.NotEmpty(t, expected)
+	require.Equal(t, expected, collectDiff(t, holder, old, trie))
+}
@@ -126,6 +126,9 @@ func (n *BranchNode) Encode() ([]byte, error) {
 }
 
 func DecodeNode(data []byte) (Node, error) {
+	if len(data) == 0 {
+		return nil, ssz.ErrSize
+	}
 	nodeKind := ssz.UnmarshallUint8(data)
 	data = data[1:]
 
@@ -234,12 +234,19 @@ func (p *Proof) Encode() ([]byte, error) {
 func DecodeProof(data []byte) (Proof, error) {
 	// here we deserialize proof from the data piece by piece
 	// and each time advance the offset on correct amount of bytes
+	// the data may come from an untrusted party, so every length is checked before use
 
 	p := Proof{}
+	if len(data) < 5 {
+		return p, ssz.ErrSize
+	}
 	p.operation = MPTOperation(ssz.UnmarshallUint32(data))
 	data = data[4:]
 
-	keyLen := ssz.UnmarshallUint8(data)
+	keyLen := int(ssz.UnmarshallUint8(data))
+	if len(data) < 2+keyLen {
+		return p, ssz.ErrSize
+	}
 	p.key = data[1 : 1+keyLen]
 	data = data[1+keyLen:]
 
@@ -247,7 +254,13 @@ func DecodeProof(data []byte) (Proof, error) {
 	data = data[1:]
 
 	for range pathLen {
-		nodeLen := ssz.UnmarshallUint32(data)
+		if len(data) < 4 {
+			return p, ssz.ErrSize
+		}
+		nodeLen := uint64(ssz.UnmarshallUint32(data))
+		if uint64(len(data)-4) < nodeLen {
+			return p, ssz.ErrSize
+		}
 
 		node, err := DecodeNode(data[4 : 4+nodeLen])
 		if err != nil {
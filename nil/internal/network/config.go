@@ -30,6 +30,11 @@ type Config struct {
 
 	ConnectionManagerConfig *cm.Config `yaml:"connectionManager,omitempty"`
 
+	// PubSubTraceContext enables publishing the messages with the trace context of the publisher.
+	// Such messages go to the versioned topics that older nodes don't read,
+	// so it must be enabled only after all the nodes of the network are able to receive them.
+	PubSubTraceContext bool `yaml:"pubSubTraceContext,omitempty"`
+
 	// Test-only
 	Reachability  network.Reachability `yaml:"-"`
 	PubSubOptions []pubsub.Option      `yaml:"-"`
@@ -14,6 +14,9 @@ type reputationChangeReason string
 
 const (
 	ReputationChangeInvalidBlockSignature = reputationChangeReason("invalid block signature")
+	ReputationChangeInvalidStateSyncData  = reputationChangeReason("invalid state sync data")
+	ReputationChangeInvalidBlocksRange    = reputationChangeReason("invalid blocks range")
+	ReputationChangeSlowResponse          = reputationChangeReason("slow response")
 )
 
 type ReputationChangeSettings = map[reputationChangeReason]Reputation
@@ -21,6 +24,9 @@ type ReputationChangeSettings = map[reputationChangeReason]Reputation
 func DefaultReputationChangeSettings() ReputationChangeSettings {
 	return ReputationChangeSettings{
 		ReputationChangeInvalidBlockSignature: -100,
+		ReputationChangeInvalidStateSyncData:  -100,
+		ReputationChangeInvalidBlocksRange:    -100,
+		ReputationChangeSlowResponse:          -20,
 	}
 }
 
@@ -3,6 +3,8 @@ package network
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"sync"
 	"sync/atomic"
 
@@ -10,13 +12,21 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
 	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const subscriptionChannelSize = 100
 
+// tracedTopicSuffix is appended to the topic of the messages enveloped with the trace context of the publisher.
+// The messages are published to a separate topic, so that the nodes that don't know the envelope
+// never receive them, and the un-enveloped messages of such nodes are still received from the original topic.
+const tracedTopicSuffix = "/traced/1"
+
 type PubSub struct {
 	impl   *pubsub.PubSub // +checklocksignore: mu is not required, it just happens to be held always.
 	prefix string
+	// traceContext enables publishing the messages to the traced topics.
+	traceContext bool
 
 	mu     sync.Mutex
 	topics map[string]*pubsub.Topic // +checklocks:mu
@@ -25,6 +35,7 @@ type PubSub struct {
 	meter         telemetry.Meter
 	published     telemetry.Counter
 	publishedSize telemetry.Counter
+	tracer        telemetry.Tracer
 
 	logger logging.Logger
 }
@@ -36,11 +47,22 @@ type SubscriptionCounters struct {
 type PubSubMessage struct {
 	Data         []byte
 	ReceivedFrom PeerID
+
+	// SpanContext is the context of the span the message was published from, it is invalid if the publisher
+	// does not trace it.
+	SpanContext telemetry.SpanContext
+}
+
+// Context returns a copy of ctx which continues the trace of the message publisher.
+func (m PubSubMessage) Context(ctx context.Context) context.Context {
+	return telemetry.ContextWithRemoteSpanContext(ctx, m.SpanContext)
 }
 
 type Subscription struct {
 	impl *pubsub.Subscription
-	self PeerID
+	// traced is the subscription to the messages with the trace context.
+	traced *pubsub.Subscription
+	self   PeerID
 
 	received     telemetry.Counter
 	receivedSize telemetry.Counter
@@ -67,12 +89,14 @@ func newPubSub(ctx context.Context, h Host, conf *Config, logger logging.Logger)
 
 	return &PubSub{
 		prefix:        conf.Prefix,
+		traceContext:  conf.PubSubTraceContext,
 		impl:          impl,
 		topics:        make(map[string]*pubsub.Topic),
 		self:          h.ID(),
 		meter:         meter,
 		published:     published,
 		publishedSize: publishedSize,
+		tracer:        telemetry.NewTracer("github.com/NilFoundation/nil/nil/internal/network/pubsub"),
 		logger: logger.With().
 			Str(logging.FieldComponent, "pub-sub").
 			Logger(),
@@ -110,16 +134,31 @@ func (ps *PubSub) Topics() []string {
 }
 
 // Publish publishes a message to the given topic.
+// The message carries the trace context of ctx if publishing it is enabled in the config.
 func (ps *PubSub) Publish(ctx context.Context, topic string, data []byte) error {
 	ps.logger.Trace().Str(logging.FieldTopic, topic).Msg("Publishing message...")
 
-	t, err := ps.getTopic(topic)
-	if err != nil {
-		return err
-	}
+	if !ps.traceContext {
+		t, err := ps.getTopic(topic)
+		if err != nil {
+			return err
+		}
+		if err := t.Publish(ctx, data); err != nil {
+			return err
+		}
+	} else {
+		t, err := ps.getTopic(topic + tracedTopicSuffix)
+		if err != nil {
+			return err
+		}
 
-	if err := t.Publish(ctx, data); err != nil {
-		return err
+		ctx, span := ps.tracer.Start(ctx, "pubsub.Publish",
+			trace.WithSpanKind(trace.SpanKindProducer), trace.WithAttributes(telattr.Topic(topic)))
+		err = t.Publish(ctx, marshalPubSubEnvelope(span.SpanContext(), data))
+		telemetry.EndSpan(span, err)
+		if err != nil {
+			return err
+		}
 	}
 
 	attrs := telattr.With(telattr.Topic(topic), telattr.P2PIdentity(ps.self))
@@ -129,17 +168,27 @@ func (ps *PubSub) Publish(ctx context.Context, topic string, data []byte) error
 	return nil
 }
 
-// Subscribe subscribes to the given topic. The subscription must be closed after use.
+// Subscribe subscribes to the given topic, both to the messages with and without the trace context.
+// The subscription must be closed after use.
 func (ps *PubSub) Subscribe(topic string) (*Subscription, error) {
 	t, err := ps.getTopic(topic)
 	if err != nil {
 		return nil, err
 	}
+	tracedTopic, err := ps.getTopic(topic + tracedTopicSuffix)
+	if err != nil {
+		return nil, err
+	}
 
 	impl, err := t.Subscribe()
 	if err != nil {
 		return nil, err
 	}
+	traced, err := tracedTopic.Subscribe()
+	if err != nil {
+		impl.Cancel()
+		return nil, err
+	}
 
 	received, err := ps.meter.Int64Counter("received_messages")
 	if err != nil {
@@ -157,6 +206,7 @@ func (ps *PubSub) Subscribe(topic string) (*Subscription, error) {
 	logger.Debug().Msg("Subscribed to topic")
 	return &Subscription{
 		impl:         impl,
+		traced:       traced,
 		self:         ps.self,
 		received:     received,
 		receivedSize: receivedSize,
@@ -164,13 +214,21 @@ func (ps *PubSub) Subscribe(topic string) (*Subscription, error) {
 	}, nil
 }
 
+// ListPeers returns the peers subscribed to the topic, with or without the trace context.
 func (ps *PubSub) ListPeers(topic string) []PeerID {
-	t, err := ps.getTopic(topic)
-	if err != nil {
-		return nil
+	var peers []PeerID
+	for _, name := range []string{topic, topic + tracedTopicSuffix} {
+		t, err := ps.getTopic(name)
+		if err != nil {
+			return nil
+		}
+		for _, peer := range t.ListPeers() {
+			if !slices.Contains(peers, peer) {
+				peers = append(peers, peer)
+			}
+		}
 	}
-
-	return t.ListPeers()
+	return peers
 }
 
 func (ps *PubSub) getTopic(topic string) (*pubsub.Topic, error) {
@@ -197,44 +255,72 @@ func (ps *PubSub) getTopic(topic string) (*pubsub.Topic, error) {
 func (s *Subscription) Start(ctx context.Context, skipSelfMessages bool) <-chan PubSubMessage {
 	msgCh := make(chan PubSubMessage, subscriptionChannelSize)
 
+	var wg sync.WaitGroup
+	wg.Add(2)
 	go func() {
-		s.logger.Debug().Msg("Starting subscription loop...")
-
-		for {
-			msg, err := s.impl.Next(ctx)
-			if err != nil {
-				if ctx.Err() != nil {
-					s.logger.Debug().Err(err).Msg("Closing subscription loop due to context cancellation")
-					break
-				}
-				if errors.Is(err, pubsub.ErrSubscriptionCancelled) {
-					s.logger.Debug().Err(err).Msg("Quitting subscription loop")
-					break
-				}
-				s.logger.Error().Err(err).Msg("Error reading message")
-				continue
-			}
+		defer wg.Done()
+		s.loop(ctx, s.impl, false, skipSelfMessages, msgCh)
+	}()
+	go func() {
+		defer wg.Done()
+		s.loop(ctx, s.traced, true, skipSelfMessages, msgCh)
+	}()
+	go func() {
+		wg.Wait()
+		close(msgCh)
 
-			if skipSelfMessages && msg.ReceivedFrom == s.self {
-				s.logger.Trace().Msg("Skip message from self")
-				s.counters.SkippedMessages.Add(1)
-				continue
-			}
+		s.logger.Debug().Msg("Subscription loop closed.")
+	}()
 
-			attrs := telattr.With(telattr.Topic(s.impl.Topic()), telattr.P2PIdentity(s.self))
-			s.received.Add(ctx, 1, attrs)
-			s.receivedSize.Add(ctx, int64(len(msg.Data)), attrs)
-			s.logger.Trace().Msg("Received message")
+	return msgCh
+}
 
-			msgCh <- PubSubMessage{msg.Data, msg.ReceivedFrom}
+// loop reads the messages of the subscription until it is cancelled.
+// The messages of the traced subscription are unwrapped from the envelope with the trace context.
+func (s *Subscription) loop(
+	ctx context.Context, sub *pubsub.Subscription, traced bool, skipSelfMessages bool, msgCh chan<- PubSubMessage,
+) {
+	s.logger.Debug().Str(logging.FieldTopic, sub.Topic()).Msg("Starting subscription loop...")
+
+	for {
+		msg, err := sub.Next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				s.logger.Debug().Err(err).Msg("Closing subscription loop due to context cancellation")
+				return
+			}
+			if errors.Is(err, pubsub.ErrSubscriptionCancelled) {
+				s.logger.Debug().Err(err).Msg("Quitting subscription loop")
+				return
+			}
+			s.logger.Error().Err(err).Msg("Error reading message")
+			continue
 		}
 
-		close(msgCh)
+		if skipSelfMessages && msg.ReceivedFrom == s.self {
+			s.logger.Trace().Msg("Skip message from self")
+			s.counters.SkippedMessages.Add(1)
+			continue
+		}
 
-		s.logger.Debug().Msg("Subscription loop closed.")
-	}()
+		attrs := telattr.With(telattr.Topic(s.impl.Topic()), telattr.P2PIdentity(s.self))
+		s.received.Add(ctx, 1, attrs)
+		s.receivedSize.Add(ctx, int64(len(msg.Data)), attrs)
+		s.logger.Trace().Msg("Received message")
 
-	return msgCh
+		received := PubSubMessage{
+			Data:         msg.Data,
+			ReceivedFrom: msg.ReceivedFrom,
+		}
+		if traced {
+			received.SpanContext, received.Data, err = unmarshalPubSubEnvelope(msg.Data)
+			if err != nil {
+				s.logger.Error().Err(err).Stringer(logging.FieldPeerId, msg.ReceivedFrom).Msg("Malformed message")
+				continue
+			}
+		}
+		msgCh <- received
+	}
 }
 
 func (s *Subscription) Counters() *SubscriptionCounters {
@@ -243,4 +329,31 @@ func (s *Subscription) Counters() *SubscriptionCounters {
 
 func (s *Subscription) Close() {
 	s.impl.Cancel()
+	s.traced.Cancel()
+}
+
+// marshalPubSubEnvelope prepends the data with the span context of the publisher,
+// so that the trace is continued by the subscribers.
+// Layout: [span context size (1 byte)][span context][data].
+func marshalPubSubEnvelope(sc telemetry.SpanContext, data []byte) []byte {
+	spanContext := telemetry.MarshalSpanContext(sc)
+	envelope := make([]byte, 0, 1+len(spanContext)+len(data))
+	envelope = append(envelope, byte(len(spanContext)))
+	envelope = append(envelope, spanContext...)
+	return append(envelope, data...)
+}
+
+func unmarshalPubSubEnvelope(envelope []byte) (telemetry.SpanContext, []byte, error) {
+	if len(envelope) == 0 {
+		return telemetry.SpanContext{}, nil, errors.New("empty message envelope")
+	}
+	size := int(envelope[0])
+	if len(envelope) < 1+size {
+		return telemetry.SpanContext{}, nil, fmt.Errorf("message envelope is too short: %d bytes", len(envelope))
+	}
+	sc, err := telemetry.UnmarshalSpanContext(envelope[1 : 1+size])
+	if err != nil {
+		return telemetry.SpanContext{}, nil, err
+	}
+	return sc, envelope[1+size:], nil
 }
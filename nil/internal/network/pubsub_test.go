@@ -4,7 +4,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type PubSubSuite struct {
@@ -93,6 +95,104 @@ func (s *PubSubSuite) TestTwoHosts() {
 	s.receive(ch, msg)
 }
 
+func (s *PubSubSuite) TestTraceContext() {
+	manager := s.newManagerWithBaseConfig(&Config{PubSubTraceContext: true})
+	defer manager.Close()
+
+	const topic = "test-trace"
+	msg := []byte("hello")
+
+	sub, err := manager.PubSub().Subscribe(topic)
+	s.Require().NoError(err)
+	defer sub.Close()
+	ch := sub.Start(s.context, false)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	err = manager.PubSub().Publish(trace.ContextWithSpanContext(s.context, sc), topic, msg)
+	s.Require().NoError(err)
+
+	var received PubSubMessage
+	s.Require().Eventually(func() bool {
+		select {
+		case received = <-ch:
+			return true
+		default:
+			return false
+		}
+	}, 10*time.Second, 100*time.Millisecond)
+
+	s.Equal(msg, received.Data)
+	s.Equal(sc.TraceID(), received.SpanContext.TraceID())
+	s.True(received.SpanContext.IsRemote())
+	s.Equal(sc.TraceID(), trace.SpanContextFromContext(received.Context(s.context)).TraceID())
+}
+
+func (s *PubSubSuite) TestMixedVersions() {
+	traced := s.newManagerWithBaseConfig(&Config{PubSubTraceContext: true})
+	defer traced.Close()
+	untraced := s.newManager()
+	defer untraced.Close()
+
+	ConnectManagers(s.T(), traced, untraced)
+
+	const topic = "test-versions"
+
+	sub, err := traced.PubSub().Subscribe(topic)
+	s.Require().NoError(err)
+	defer sub.Close()
+	ch := sub.Start(s.context, true)
+
+	untracedSub, err := untraced.PubSub().Subscribe(topic)
+	s.Require().NoError(err)
+	defer untracedSub.Close()
+	untracedCh := untracedSub.Start(s.context, true)
+
+	// Older nodes read the raw messages from the original topic.
+	t, err := untraced.PubSub().getTopic(topic)
+	s.Require().NoError(err)
+	legacySub, err := t.Subscribe()
+	s.Require().NoError(err)
+	defer legacySub.Cancel()
+
+	s.Require().Eventually(func() bool {
+		return len(s.listPeers(traced, topic+tracedTopicSuffix)) == 1 && len(s.listPeers(untraced, topic)) == 1
+	}, 10*time.Second, 100*time.Millisecond)
+	s.Len(traced.PubSub().ListPeers(topic), 1)
+
+	// The first messages may be published before the peers set up the streams, so they are repeated.
+	publish := func(m *BasicManager, ch <-chan PubSubMessage, data []byte) PubSubMessage {
+		var received PubSubMessage
+		s.Require().Eventually(func() bool {
+			s.Require().NoError(m.PubSub().Publish(s.context, topic, data))
+			select {
+			case received = <-ch:
+				return true
+			case <-time.After(time.Second):
+				return false
+			}
+		}, 10*time.Second, 100*time.Millisecond)
+		return received
+	}
+
+	// Messages with the trace context are received by the upgraded nodes only.
+	msg := []byte("traced")
+	s.Equal(msg, publish(traced, untracedCh, msg).Data)
+
+	// Messages of the nodes that don't publish the trace context are received un-enveloped.
+	msg = []byte("untraced")
+	received := publish(untraced, ch, msg)
+	s.Equal(msg, received.Data)
+	s.False(received.SpanContext.IsValid())
+
+	legacyMsg, err := legacySub.Next(s.context)
+	s.Require().NoError(err)
+	s.Equal(msg, legacyMsg.Data)
+}
+
 func (s *PubSubSuite) TestComplexScenario() {
 	// todo: this test often fails in CI but works locally
 	s.T().SkipNow()
@@ -227,6 +327,32 @@ func (s *PubSubSuite) TestComplexScenario() {
 	})
 }
 
+func TestPubSubEnvelope(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("payload")
+
+	sc, received, err := unmarshalPubSubEnvelope(marshalPubSubEnvelope(trace.SpanContext{}, data))
+	require.NoError(t, err)
+	require.False(t, sc.IsValid())
+	require.Equal(t, data, received)
+
+	expected := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	sc, received, err = unmarshalPubSubEnvelope(marshalPubSubEnvelope(expected, data))
+	require.NoError(t, err)
+	require.Equal(t, expected.TraceID(), sc.TraceID())
+	require.Equal(t, expected.SpanID(), sc.SpanID())
+	require.Equal(t, data, received)
+
+	_, _, err = unmarshalPubSubEnvelope(nil)
+	require.Error(t, err)
+	_, _, err = unmarshalPubSubEnvelope([]byte{25, 1, 2})
+	require.Error(t, err)
+}
+
 func TestPubSub(t *testing.T) {
 	t.Parallel()
 
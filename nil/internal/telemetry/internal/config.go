@@ -9,6 +9,10 @@ type Config struct {
 	GrpcEndpoint  string `yaml:"grpcEndpoint,omitempty"`
 
 	PrometheusPort int `yaml:"prometheusPort,omitempty"`
+
+	ExportTraces bool `yaml:"exportTraces,omitempty"`
+	// TraceSampleRatio is the fraction of the traces sampled at their roots, 0 means that all traces are sampled.
+	TraceSampleRatio float64 `yaml:"traceSampleRatio,omitempty"`
 }
 
 func (c *Config) GetServiceName() string {
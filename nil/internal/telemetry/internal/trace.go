@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func InitTracing(ctx context.Context, config *Config) error {
+	if !config.ExportTraces {
+		return nil
+	}
+
+	exporter, err := newTraceGrpcExporter(ctx, config)
+	if err != nil {
+		return fmt.Errorf("failed to initialize trace exporter: %w", err)
+	}
+
+	tp, err := newTracerProvider(exporter, config)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracer provider: %w", err)
+	}
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return nil
+}
+
+func ShutdownTracing(ctx context.Context) {
+	tp, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	if !ok {
+		// mb tracing was not initialized
+		return
+	}
+	// nothing to do with the error
+	_ = tp.Shutdown(context.WithoutCancel(ctx))
+}
+
+func newTraceGrpcExporter(ctx context.Context, config *Config) (sdktrace.SpanExporter, error) {
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithInsecure()}
+	if config.GrpcEndpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(config.GrpcEndpoint))
+	}
+	return otlptracegrpc.New(ctx, opts...)
+}
+
+func newTracerProvider(exporter sdktrace.SpanExporter, config *Config) (*sdktrace.TracerProvider, error) {
+	res, err := NewResource(config)
+	if err != nil {
+		return nil, err
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if config.TraceSampleRatio > 0 && config.TraceSampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(config.TraceSampleRatio)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+		sdktrace.WithResource(res),
+	), nil
+}
@@ -1,6 +1,7 @@
 package telattr
 
 import (
+	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -38,3 +39,23 @@ func Type(t string) attribute.KeyValue {
 func RpcMethod(method string) attribute.KeyValue {
 	return attribute.String(logging.FieldRpcMethod, method)
 }
+
+func TransactionHash(hash common.Hash) attribute.KeyValue {
+	return attribute.Stringer(logging.FieldTransactionHash, hash)
+}
+
+func BlockNumber(number types.BlockNumber) attribute.KeyValue {
+	return attribute.Int64(logging.FieldBlockNumber, int64(number))
+}
+
+func BlockHash(hash common.Hash) attribute.KeyValue {
+	return attribute.Stringer(logging.FieldBlockHash, hash)
+}
+
+func Height(height uint64) attribute.KeyValue {
+	return attribute.Int64(logging.FieldHeight, int64(height))
+}
+
+func Round(round uint64) attribute.KeyValue {
+	return attribute.Int64(logging.FieldRound, int64(round))
+}
@@ -27,11 +27,16 @@ func Init(ctx context.Context, config *Config) error {
 
 	internal.StartPrometheusServer(config)
 
-	return internal.InitMetrics(ctx, config)
+	if err := internal.InitMetrics(ctx, config); err != nil {
+		return err
+	}
+
+	return internal.InitTracing(ctx, config)
 }
 
 func Shutdown(ctx context.Context) {
 	internal.ShutdownMetrics(ctx)
+	internal.ShutdownTracing(ctx)
 }
 
 func NewMeter(name string) Meter {
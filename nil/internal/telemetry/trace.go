@@ -0,0 +1,100 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	Tracer      = trace.Tracer
+	Span        = trace.Span
+	SpanContext = trace.SpanContext
+	SpanOption  = trace.SpanStartOption
+)
+
+// spanContextSize is the size of the binary encoded span context: trace id, span id and trace flags.
+const spanContextSize = 16 + 8 + 1
+
+func NewTracer(name string) Tracer {
+	return otel.Tracer(name)
+}
+
+// RecordError marks the span as failed with the given error.
+func RecordError(span Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// EndSpan ends the span marking it as failed if err is set.
+func EndSpan(span Span, err error) {
+	if err != nil {
+		RecordError(span, err)
+	}
+	span.End()
+}
+
+// WithLinks links the span being started to the given span contexts, invalid ones are skipped.
+func WithLinks(spanContexts ...SpanContext) SpanOption {
+	links := make([]trace.Link, 0, len(spanContexts))
+	for _, sc := range spanContexts {
+		if sc.IsValid() {
+			links = append(links, trace.Link{SpanContext: sc})
+		}
+	}
+	return trace.WithLinks(links...)
+}
+
+// SpanContextFromContext returns the span context of the span stored in ctx.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	return trace.SpanContextFromContext(ctx)
+}
+
+// ContextWithRemoteSpanContext returns a copy of ctx with the span context received from another process
+// set as the parent of the spans started from it. Invalid span context is ignored.
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	if !sc.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// MarshalSpanContext encodes the span context to be passed over the network.
+// Invalid span context (i.e. tracing is disabled or there is no span) is encoded as empty slice.
+func MarshalSpanContext(sc SpanContext) []byte {
+	if !sc.IsValid() {
+		return nil
+	}
+
+	traceId := sc.TraceID()
+	spanId := sc.SpanID()
+	data := make([]byte, 0, spanContextSize)
+	data = append(data, traceId[:]...)
+	data = append(data, spanId[:]...)
+	return append(data, byte(sc.TraceFlags()))
+}
+
+// UnmarshalSpanContext decodes the span context encoded by MarshalSpanContext.
+func UnmarshalSpanContext(data []byte) (SpanContext, error) {
+	if len(data) == 0 {
+		return SpanContext{}, nil
+	}
+	if len(data) != spanContextSize {
+		return SpanContext{}, fmt.Errorf("invalid span context size %d, expected %d", len(data), spanContextSize)
+	}
+
+	var config trace.SpanContextConfig
+	copy(config.TraceID[:], data[:16])
+	copy(config.SpanID[:], data[16:24])
+	config.TraceFlags = trace.TraceFlags(data[24])
+	config.Remote = true
+
+	sc := trace.NewSpanContext(config)
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("invalid span context %x", data)
+	}
+	return sc, nil
+}
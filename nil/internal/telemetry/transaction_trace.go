@@ -0,0 +1,44 @@
+package telemetry
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/check"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// transactionSpansCacheSize limits the number of the transactions whose spans are remembered.
+const transactionSpansCacheSize = 100_000
+
+// transactionSpans holds the span of the latest processing stage of the recently seen transactions.
+// It is shared by all the shards of the process, so the processing of an internal transaction
+// continues the trace of the transaction that has emitted it.
+var transactionSpans = func() *lru.Cache[common.Hash, SpanContext] {
+	cache, err := lru.New[common.Hash, SpanContext](transactionSpansCacheSize)
+	check.PanicIfErr(err)
+	return cache
+}()
+
+// RegisterTransactionSpan remembers the span as the latest processing stage of the transaction.
+// Nothing is stored if tracing is disabled or the span is not sampled.
+func RegisterTransactionSpan(hash common.Hash, span Span) {
+	if sc := span.SpanContext(); sc.IsValid() && sc.IsSampled() {
+		transactionSpans.Add(hash, sc)
+	}
+}
+
+// TransactionSpanContext returns the context of the latest registered span of the transaction.
+func TransactionSpanContext(hash common.Hash) (SpanContext, bool) {
+	return transactionSpans.Get(hash)
+}
+
+// ContextWithTransactionSpan returns a copy of ctx with the latest registered span of the transaction set
+// as the parent of the spans started from it. If the transaction is unknown, ctx is returned as is.
+func ContextWithTransactionSpan(ctx context.Context, hash common.Hash) context.Context {
+	sc, ok := TransactionSpanContext(hash)
+	if !ok {
+		return ctx
+	}
+	return ContextWithRemoteSpanContext(ctx, sc)
+}
@@ -0,0 +1,182 @@
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+)
+
+// AsyncCallType is the type of the frames that represent asynchronous transactions sent by the traced transaction.
+const AsyncCallType = "ASYNC"
+
+// CallFrame is a single call of the call tracer output.
+type CallFrame struct {
+	Type    string         `json:"type"`
+	From    types.Address  `json:"from"`
+	To      types.Address  `json:"to"`
+	Value   *types.Value   `json:"value,omitempty"`
+	Gas     hexutil.Uint64 `json:"gas"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	Input   hexutil.Bytes  `json:"input"`
+	Output  hexutil.Bytes  `json:"output,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Calls   []*CallFrame   `json:"calls,omitempty"`
+
+	// The fields below are set only for the asynchronous frames.
+	TxnHash     *common.Hash       `json:"txnHash,omitempty"`
+	FeeCredit   *types.Value       `json:"feeCredit,omitempty"`
+	ForwardKind *types.ForwardKind `json:"forwardKind,omitempty"`
+	Bounce      bool               `json:"bounce,omitempty"`
+	Refund      bool               `json:"refund,omitempty"`
+	Deploy      bool               `json:"deploy,omitempty"`
+}
+
+type CallTracerConfig struct {
+	// OnlyTopCall disables tracing of the nested calls.
+	OnlyTopCall bool `json:"onlyTopCall"`
+}
+
+type callTracer struct {
+	cfg CallTracerConfig
+
+	// stack holds the frames that are not exited yet, the first one is the top call.
+	stack []*CallFrame
+	root  *CallFrame
+	// reverted marks the frames whose state changes were reverted by themselves or by one of their callers.
+	reverted map[*CallFrame]bool
+}
+
+func newCallTracer(config json.RawMessage) (*Tracer, error) {
+	t := &callTracer{
+		reverted: make(map[*CallFrame]bool),
+	}
+	if err := parseConfig(config, &t.cfg); err != nil {
+		return nil, err
+	}
+	return &Tracer{
+		Hooks: &tracing.Hooks{
+			OnEnter: t.onEnter,
+			OnExit:  t.onExit,
+		},
+		OnOutTransactions: t.onOutTransactions,
+		GetResult:         t.getResult,
+	}, nil
+}
+
+func (t *callTracer) onEnter(
+	depth int, typ byte, from types.Address, to types.Address, input []byte, gas uint64, value *big.Int,
+) {
+	if t.cfg.OnlyTopCall && depth > 0 {
+		return
+	}
+
+	frame := &CallFrame{
+		Type:  vm.OpCode(typ).String(),
+		From:  from,
+		To:    to,
+		Value: bigToValue(value),
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}
+	if len(t.stack) == 0 {
+		t.root = frame
+	} else {
+		parent := t.stack[len(t.stack)-1]
+		parent.Calls = append(parent.Calls, frame)
+	}
+	t.stack = append(t.stack, frame)
+}
+
+func (t *callTracer) onExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.cfg.OnlyTopCall && depth > 0 {
+		return
+	}
+	if len(t.stack) == 0 {
+		return
+	}
+
+	frame := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+
+	frame.GasUsed = hexutil.Uint64(gasUsed)
+	frame.Output = common.CopyBytes(output)
+	if err != nil {
+		frame.Error = err.Error()
+	}
+	if reverted {
+		t.markReverted(frame)
+	}
+}
+
+func (t *callTracer) markReverted(frame *CallFrame) {
+	t.reverted[frame] = true
+	for _, call := range frame.Calls {
+		t.markReverted(call)
+	}
+}
+
+// onOutTransactions turns the calls of the asyncCall precompile into asynchronous frames.
+// The outbound transactions are emitted in the order of these calls, and the calls that were reverted
+// didn't emit anything. The remaining transactions (e.g., bounces) are attached to the top call.
+func (t *callTracer) onOutTransactions(txns []*types.OutboundTransaction) {
+	if t.root == nil {
+		return
+	}
+
+	var visit func(frame *CallFrame)
+	visit = func(frame *CallFrame) {
+		for _, call := range frame.Calls {
+			if len(txns) > 0 && call.To == vm.AsyncCallAddress && !t.reverted[call] {
+				setAsyncFrame(call, txns[0])
+				txns = txns[1:]
+				continue
+			}
+			visit(call)
+		}
+	}
+	if !t.cfg.OnlyTopCall {
+		visit(t.root)
+	}
+
+	for _, txn := range txns {
+		frame := &CallFrame{}
+		setAsyncFrame(frame, txn)
+		t.root.Calls = append(t.root.Calls, frame)
+	}
+}
+
+func setAsyncFrame(frame *CallFrame, txn *types.OutboundTransaction) {
+	value := txn.Value
+	feeCredit := txn.FeeCredit
+	forwardKind := txn.ForwardKind
+	txnHash := txn.TxnHash
+
+	frame.Type = AsyncCallType
+	frame.From = txn.From
+	frame.To = txn.To
+	frame.Value = &value
+	frame.Input = hexutil.Bytes(txn.Data)
+	frame.TxnHash = &txnHash
+	frame.FeeCredit = &feeCredit
+	frame.ForwardKind = &forwardKind
+	frame.Bounce = txn.IsBounce()
+	frame.Refund = txn.IsRefund()
+	frame.Deploy = txn.IsDeploy()
+}
+
+func (t *callTracer) getResult() (json.RawMessage, error) {
+	return json.Marshal(t.root)
+}
+
+func bigToValue(v *big.Int) *types.Value {
+	if v == nil {
+		return nil
+	}
+	value := types.NewValueFromBigMust(v)
+	return &value
+}
@@ -0,0 +1,252 @@
+package tracers
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+)
+
+// PrestateAccount is the state of an account touched by the traced transaction.
+type PrestateAccount struct {
+	Balance  *types.Value                `json:"balance,omitempty"`
+	Seqno    hexutil.Uint64              `json:"seqno,omitempty"`
+	ExtSeqno hexutil.Uint64              `json:"extSeqno,omitempty"`
+	Code     hexutil.Bytes               `json:"code,omitempty"`
+	Storage  map[common.Hash]common.Hash `json:"storage,omitempty"`
+
+	exists bool
+}
+
+// PrestateDiff is the output of the prestate tracer in the diff mode.
+type PrestateDiff struct {
+	Pre  map[types.Address]*PrestateAccount `json:"pre"`
+	Post map[types.Address]*PrestateAccount `json:"post"`
+}
+
+type PrestateTracerConfig struct {
+	// DiffMode makes the tracer return the changes made by the transaction instead of the prestate.
+	DiffMode bool `json:"diffMode"`
+}
+
+// prestateTracer records the accounts and storage slots that are accessed by the transaction
+// as they were at the moment of the first access. Only the accounts of the shard of the transaction are recorded,
+// since the execution can't access the state of other shards.
+type prestateTracer struct {
+	cfg PrestateTracerConfig
+	env *tracing.VMContext
+
+	shardId types.ShardId
+	pre     map[types.Address]*PrestateAccount
+	post    map[types.Address]*PrestateAccount
+	err     error
+}
+
+func newPrestateTracer(config json.RawMessage) (*Tracer, error) {
+	t := &prestateTracer{
+		pre: make(map[types.Address]*PrestateAccount),
+	}
+	if err := parseConfig(config, &t.cfg); err != nil {
+		return nil, err
+	}
+	return &Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: t.onTxStart,
+			OnTxEnd:   t.onTxEnd,
+			OnEnter:   t.onEnter,
+			OnOpcode:  t.onOpcode,
+		},
+		GetResult: t.getResult,
+	}, nil
+}
+
+func (t *prestateTracer) onTxStart(env *tracing.VMContext, txn *types.Transaction) {
+	t.env = env
+	t.shardId = txn.To.ShardId()
+	t.lookupAccount(txn.To)
+	t.lookupAccount(txn.From)
+}
+
+func (t *prestateTracer) onEnter(_ int, _ byte, from types.Address, to types.Address, _ []byte, _ uint64, _ *big.Int) {
+	t.lookupAccount(from)
+	t.lookupAccount(to)
+}
+
+func (t *prestateTracer) onOpcode(
+	_ uint64, op byte, _, _ uint64, scope tracing.OpContext, _ []byte, _ int, err error,
+) {
+	if err != nil {
+		return
+	}
+	stack := scope.StackData()
+	if len(stack) == 0 {
+		return
+	}
+	top := stack[len(stack)-1]
+
+	switch vm.OpCode(op) {
+	case vm.SLOAD, vm.SSTORE:
+		t.lookupStorage(scope.Address(), top.Bytes32())
+	case vm.BALANCE, vm.EXTCODESIZE, vm.EXTCODECOPY, vm.EXTCODEHASH, vm.SELFDESTRUCT:
+		t.lookupAccount(top.Bytes20())
+	}
+}
+
+func (t *prestateTracer) onTxEnd(_ *tracing.VMContext, _ *types.Transaction, _ types.ExecError) {
+	if !t.cfg.DiffMode || t.env == nil {
+		return
+	}
+
+	t.post = make(map[types.Address]*PrestateAccount, len(t.pre))
+	for addr, pre := range t.pre {
+		post := t.readAccount(addr)
+		for key := range pre.Storage {
+			t.readStorage(post, addr, key)
+		}
+		t.post[addr] = post
+	}
+}
+
+func (t *prestateTracer) lookupAccount(addr types.Address) {
+	if t.env == nil || addr.ShardId() != t.shardId {
+		return
+	}
+	if _, ok := t.pre[addr]; ok {
+		return
+	}
+	t.pre[addr] = t.readAccount(addr)
+}
+
+func (t *prestateTracer) lookupStorage(addr types.Address, key common.Hash) {
+	t.lookupAccount(addr)
+	acc, ok := t.pre[addr]
+	if !ok {
+		return
+	}
+	if _, ok := acc.Storage[key]; ok {
+		return
+	}
+	t.readStorage(acc, addr, key)
+}
+
+func (t *prestateTracer) readAccount(addr types.Address) *PrestateAccount {
+	state := t.env.StateDB
+	acc := &PrestateAccount{Storage: make(map[common.Hash]common.Hash)}
+
+	exists, err := state.Exists(addr)
+	if err != nil {
+		t.setErr(err)
+		return acc
+	}
+	if !exists {
+		return acc
+	}
+	acc.exists = true
+
+	balance, err := state.GetBalance(addr)
+	t.setErr(err)
+	acc.Balance = &balance
+
+	seqno, err := state.GetSeqno(addr)
+	t.setErr(err)
+	acc.Seqno = hexutil.Uint64(seqno)
+
+	extSeqno, err := state.GetExtSeqno(addr)
+	t.setErr(err)
+	acc.ExtSeqno = hexutil.Uint64(extSeqno)
+
+	code, _, err := state.GetCode(addr)
+	t.setErr(err)
+	acc.Code = common.CopyBytes(code)
+
+	return acc
+}
+
+func (t *prestateTracer) readStorage(acc *PrestateAccount, addr types.Address, key common.Hash) {
+	value, err := t.env.StateDB.GetState(addr, key)
+	t.setErr(err)
+	acc.Storage[key] = value
+}
+
+func (t *prestateTracer) setErr(err error) {
+	if t.err == nil {
+		t.err = err
+	}
+}
+
+func (t *prestateTracer) getResult() (json.RawMessage, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+
+	if !t.cfg.DiffMode {
+		res := make(map[types.Address]*PrestateAccount, len(t.pre))
+		for addr, acc := range t.pre {
+			if acc.exists {
+				res[addr] = acc
+			}
+		}
+		return json.Marshal(res)
+	}
+
+	res := PrestateDiff{
+		Pre:  make(map[types.Address]*PrestateAccount),
+		Post: make(map[types.Address]*PrestateAccount),
+	}
+	for addr, pre := range t.pre {
+		if pre, post, changed := diffAccounts(pre, t.post[addr]); changed {
+			if pre.exists {
+				res.Pre[addr] = pre
+			}
+			if post.exists {
+				res.Post[addr] = post
+			}
+		}
+	}
+	return json.Marshal(&res)
+}
+
+// diffAccounts returns the prestate with the modified slots only and the poststate with the modified fields only.
+func diffAccounts(pre, post *PrestateAccount) (*PrestateAccount, *PrestateAccount, bool) {
+	if post == nil {
+		return pre, nil, false
+	}
+	if !pre.exists || !post.exists {
+		return pre, post, pre.exists != post.exists
+	}
+
+	preDiff := *pre
+	preDiff.Storage = make(map[common.Hash]common.Hash)
+	postDiff := &PrestateAccount{exists: post.exists, Storage: make(map[common.Hash]common.Hash)}
+	changed := false
+
+	if pre.Balance.Cmp(*post.Balance) != 0 {
+		postDiff.Balance = post.Balance
+		changed = true
+	}
+	if pre.Seqno != post.Seqno {
+		postDiff.Seqno = post.Seqno
+		changed = true
+	}
+	if pre.ExtSeqno != post.ExtSeqno {
+		postDiff.ExtSeqno = post.ExtSeqno
+		changed = true
+	}
+	if !bytes.Equal(pre.Code, post.Code) {
+		postDiff.Code = post.Code
+		changed = true
+	}
+	for key, value := range post.Storage {
+		if pre.Storage[key] != value {
+			preDiff.Storage[key] = pre.Storage[key]
+			postDiff.Storage[key] = value
+			changed = true
+		}
+	}
+	return &preDiff, postDiff, changed
+}
@@ -0,0 +1,146 @@
+package tracers
+
+import (
+	"encoding/json"
+	"maps"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+)
+
+// StructLog is a single EVM step recorded by the struct logger.
+type StructLog struct {
+	Pc            uint64                      `json:"pc"`
+	Op            string                      `json:"op"`
+	Gas           uint64                      `json:"gas"`
+	GasCost       uint64                      `json:"gasCost"`
+	Depth         int                         `json:"depth"`
+	Error         string                      `json:"error,omitempty"`
+	Stack         []string                    `json:"stack,omitempty"`
+	Memory        []string                    `json:"memory,omitempty"`
+	ReturnData    hexutil.Bytes               `json:"returnData,omitempty"`
+	Storage       map[common.Hash]common.Hash `json:"storage,omitempty"`
+	RefundCounter uint64                      `json:"refund,omitempty"`
+}
+
+// StructLoggerResult is the output of the struct logger.
+type StructLoggerResult struct {
+	Gas         uint64        `json:"gas"`
+	Failed      bool          `json:"failed"`
+	ReturnValue hexutil.Bytes `json:"returnValue"`
+	StructLogs  []StructLog   `json:"structLogs"`
+}
+
+type structLogger struct {
+	cfg *TraceConfig
+	env *tracing.VMContext
+
+	storage map[types.Address]map[common.Hash]common.Hash
+	result  StructLoggerResult
+}
+
+func newStructLogger(cfg *TraceConfig) *Tracer {
+	l := &structLogger{
+		cfg:     cfg,
+		storage: make(map[types.Address]map[common.Hash]common.Hash),
+		result:  StructLoggerResult{StructLogs: []StructLog{}},
+	}
+	return &Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: l.onTxStart,
+			OnTxEnd:   l.onTxEnd,
+			OnExit:    l.onExit,
+			OnOpcode:  l.onOpcode,
+		},
+		GetResult: l.getResult,
+	}
+}
+
+func (l *structLogger) onTxStart(env *tracing.VMContext, _ *types.Transaction) {
+	l.env = env
+}
+
+func (l *structLogger) onTxEnd(_ *tracing.VMContext, _ *types.Transaction, err types.ExecError) {
+	if err != nil {
+		l.result.Failed = true
+	}
+}
+
+func (l *structLogger) onExit(depth int, output []byte, gasUsed uint64, err error, _ bool) {
+	if depth != 0 {
+		return
+	}
+	l.result.Gas = gasUsed
+	l.result.Failed = err != nil
+	l.result.ReturnValue = common.CopyBytes(output)
+}
+
+func (l *structLogger) onOpcode(
+	pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error,
+) {
+	if l.cfg.Limit != 0 && len(l.result.StructLogs) >= l.cfg.Limit {
+		return
+	}
+
+	opCode := vm.OpCode(op)
+	log := StructLog{
+		Pc:      pc,
+		Op:      opCode.String(),
+		Gas:     gas,
+		GasCost: cost,
+		Depth:   depth,
+	}
+	if err != nil {
+		log.Error = err.Error()
+	}
+	if l.env != nil {
+		log.RefundCounter = l.env.StateDB.GetRefund()
+	}
+
+	stack := scope.StackData()
+	if !l.cfg.DisableStack {
+		log.Stack = make([]string, len(stack))
+		for i, item := range stack {
+			log.Stack[i] = item.Hex()
+		}
+	}
+
+	if l.cfg.EnableMemory {
+		memory := scope.MemoryData()
+		log.Memory = make([]string, 0, (len(memory)+31)/32)
+		for i := 0; i < len(memory); i += 32 {
+			log.Memory = append(log.Memory, hexutil.Encode(memory[i:min(i+32, len(memory))]))
+		}
+	}
+
+	if l.cfg.EnableReturnData {
+		log.ReturnData = common.CopyBytes(rData)
+	}
+
+	if !l.cfg.DisableStorage && (opCode == vm.SLOAD || opCode == vm.SSTORE) {
+		addr := scope.Address()
+		if l.storage[addr] == nil {
+			l.storage[addr] = make(map[common.Hash]common.Hash)
+		}
+		switch {
+		case opCode == vm.SLOAD && len(stack) >= 1 && l.env != nil:
+			key := common.Hash(stack[len(stack)-1].Bytes32())
+			if value, err := l.env.StateDB.GetState(addr, key); err == nil {
+				l.storage[addr][key] = value
+			}
+		case opCode == vm.SSTORE && len(stack) >= 2:
+			key := common.Hash(stack[len(stack)-1].Bytes32())
+			l.storage[addr][key] = common.Hash(stack[len(stack)-2].Bytes32())
+		}
+		log.Storage = maps.Clone(l.storage[addr])
+	}
+
+	l.result.StructLogs = append(l.result.StructLogs, log)
+}
+
+func (l *structLogger) getResult() (json.RawMessage, error) {
+	return json.Marshal(&l.result)
+}
@@ -0,0 +1,73 @@
+package tracers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+const (
+	StructLoggerName   = "structLogger"
+	CallTracerName     = "callTracer"
+	PrestateTracerName = "prestateTracer"
+)
+
+var ErrUnknownTracer = errors.New("unknown tracer")
+
+// TraceConfig holds the options of debug_traceTransaction and debug_traceCall.
+// The struct logger options are ignored by the other tracers, which are configured with TracerConfig instead.
+type TraceConfig struct {
+	// Tracer is the name of the tracer to use, the struct logger is used if it is empty.
+	Tracer string `json:"tracer,omitempty"`
+	// TracerConfig is the tracer-specific configuration.
+	TracerConfig json.RawMessage `json:"tracerConfig,omitempty"`
+
+	EnableMemory     bool `json:"enableMemory,omitempty"`
+	DisableStack     bool `json:"disableStack,omitempty"`
+	DisableStorage   bool `json:"disableStorage,omitempty"`
+	EnableReturnData bool `json:"enableReturnData,omitempty"`
+	// Limit is the maximum number of struct logs to collect, zero means no limit.
+	Limit int `json:"limit,omitempty"`
+}
+
+// Tracer collects the trace of a single transaction through the EVM hooks.
+type Tracer struct {
+	*tracing.Hooks
+
+	// OnOutTransactions is called after the execution with the outbound transactions
+	// emitted by the traced transaction. They are executed asynchronously, possibly in other shards.
+	OnOutTransactions func(txns []*types.OutboundTransaction)
+
+	// GetResult returns the JSON-encoded trace.
+	GetResult func() (json.RawMessage, error)
+}
+
+// New creates the tracer selected by the config.
+func New(cfg *TraceConfig) (*Tracer, error) {
+	if cfg == nil {
+		cfg = &TraceConfig{}
+	}
+
+	switch cfg.Tracer {
+	case "", StructLoggerName:
+		return newStructLogger(cfg), nil
+	case CallTracerName:
+		return newCallTracer(cfg.TracerConfig)
+	case PrestateTracerName:
+		return newPrestateTracer(cfg.TracerConfig)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownTracer, cfg.Tracer)
+}
+
+func parseConfig(data json.RawMessage, cfg any) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("invalid tracer config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTracer(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"", StructLoggerName, CallTracerName, PrestateTracerName} {
+		tracer, err := New(&TraceConfig{Tracer: name})
+		require.NoError(t, err, name)
+		require.NotNil(t, tracer.Hooks, name)
+		require.NotNil(t, tracer.GetResult, name)
+	}
+
+	_, err := New(&TraceConfig{Tracer: "unknown"})
+	require.ErrorIs(t, err, ErrUnknownTracer)
+
+	_, err = New(&TraceConfig{Tracer: CallTracerName, TracerConfig: json.RawMessage(`{"onlyTopCall": 1}`)})
+	require.ErrorContains(t, err, "invalid tracer config")
+}
+
+func TestCallTracerAsyncFrames(t *testing.T) {
+	t.Parallel()
+
+	shardId := types.ShardId(1)
+	caller := types.GenerateRandomAddress(shardId)
+	callee := types.GenerateRandomAddress(shardId)
+	nested := types.GenerateRandomAddress(shardId)
+
+	newOutTxn := func(to types.Address, flags ...int) *types.OutboundTransaction {
+		txn := &types.Transaction{
+			TransactionDigest: types.TransactionDigest{
+				Flags: types.NewTransactionFlags(flags...),
+				To:    to,
+			},
+			From:  callee,
+			Value: types.NewValueFromUint64(10),
+		}
+		return &types.OutboundTransaction{Transaction: txn, TxnHash: txn.Hash()}
+	}
+
+	run := func(t *testing.T, cfg string) *CallFrame {
+		t.Helper()
+
+		tracer, err := New(&TraceConfig{Tracer: CallTracerName, TracerConfig: json.RawMessage(cfg)})
+		require.NoError(t, err)
+
+		tracer.OnEnter(0, byte(vm.CALL), caller, callee, []byte{0x1}, 1000, big.NewInt(5))
+		// The first async call succeeds.
+		tracer.OnEnter(1, byte(vm.CALL), callee, vm.AsyncCallAddress, nil, 100, big.NewInt(0))
+		tracer.OnExit(1, nil, 10, nil, false)
+		// The nested call sends an async transaction and reverts, so nothing is emitted.
+		tracer.OnEnter(1, byte(vm.CALL), callee, nested, nil, 300, big.NewInt(0))
+		tracer.OnEnter(2, byte(vm.CALL), nested, vm.AsyncCallAddress, nil, 100, big.NewInt(0))
+		tracer.OnExit(2, nil, 10, nil, false)
+		tracer.OnExit(1, nil, 50, vm.ErrExecutionReverted, true)
+		// The second async call succeeds.
+		tracer.OnEnter(1, byte(vm.CALL), callee, vm.AsyncCallAddress, nil, 100, big.NewInt(0))
+		tracer.OnExit(1, nil, 10, nil, false)
+		tracer.OnExit(0, []byte{0x2}, 200, nil, false)
+
+		tracer.OnOutTransactions([]*types.OutboundTransaction{
+			newOutTxn(types.GenerateRandomAddress(2)),
+			newOutTxn(types.GenerateRandomAddress(3)),
+			newOutTxn(caller, types.TransactionFlagBounce),
+		})
+
+		data, err := tracer.GetResult()
+		require.NoError(t, err)
+
+		var root CallFrame
+		require.NoError(t, json.Unmarshal(data, &root))
+		return &root
+	}
+
+	t.Run("Full", func(t *testing.T) {
+		t.Parallel()
+
+		root := run(t, `{}`)
+		require.Equal(t, "CALL", root.Type)
+		require.Equal(t, callee, root.To)
+		require.EqualValues(t, 200, root.GasUsed)
+		require.Equal(t, []byte{0x2}, []byte(root.Output))
+		require.Len(t, root.Calls, 4)
+
+		require.Equal(t, AsyncCallType, root.Calls[0].Type)
+		require.Equal(t, types.ShardId(2), root.Calls[0].To.ShardId())
+		require.NotNil(t, root.Calls[0].TxnHash)
+		require.False(t, root.Calls[0].Bounce)
+
+		reverted := root.Calls[1]
+		require.Equal(t, "CALL", reverted.Type)
+		require.NotEmpty(t, reverted.Error)
+		require.Len(t, reverted.Calls, 1)
+		require.Equal(t, "CALL", reverted.Calls[0].Type)
+		require.Equal(t, vm.AsyncCallAddress, reverted.Calls[0].To)
+
+		require.Equal(t, AsyncCallType, root.Calls[2].Type)
+		require.Equal(t, types.ShardId(3), root.Calls[2].To.ShardId())
+
+		require.Equal(t, AsyncCallType, root.Calls[3].Type)
+		require.Equal(t, caller, root.Calls[3].To)
+		require.True(t, root.Calls[3].Bounce)
+	})
+
+	t.Run("OnlyTopCall", func(t *testing.T) {
+		t.Parallel()
+
+		root := run(t, `{"onlyTopCall": true}`)
+		require.Len(t, root.Calls, 3)
+		for _, call := range root.Calls {
+			require.Equal(t, AsyncCallType, call.Type)
+			require.NotEqual(t, common.EmptyHash, *call.TxnHash)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+var ErrInvalidDoubleSignEvidence = errors.New("invalid double sign evidence")
+
+// consensusSigningDomain separates the consensus message signatures from the other signatures made with the same key.
+const consensusSigningDomain = "nil/ibft/v1"
+
+// ConsensusSigningData returns the data that is signed for the payload of the shard's consensus message.
+// The shard is bound into the signature, so a message of one shard can't be presented as a message of another one,
+// and the messages of the validator that participates in the consensus of several shards don't conflict.
+// The consensus signs the payload as is until the chain reaches execution.PatchLevelConsensusSigningDomain,
+// so the evidence is verifiable only for the messages signed after that.
+func ConsensusSigningData(shardId ShardId, payload []byte) []byte {
+	data := make([]byte, 0, len(consensusSigningDomain)+4+len(payload))
+	data = append(data, consensusSigningDomain...)
+	data = binary.BigEndian.AppendUint32(data, uint32(shardId))
+	return append(data, payload...)
+}
+
+// DoubleSignEvidence proves that a validator signed two conflicting consensus messages
+// of the same type (proposals or commit seals) for the same height and round of the shard.
+// The messages are kept as they were gossiped, i.e., marshaled with the signatures,
+// so anyone knowing the public key of the validator can check the evidence.
+type DoubleSignEvidence struct {
+	ShardId   ShardId       `json:"shardId"`
+	PublicKey hexutil.Bytes `json:"publicKey" ssz-max:"128"`
+	Height    uint64        `json:"height"`
+	Round     uint64        `json:"round"`
+	// Type is the go-ibft type of the messages.
+	Type   uint32        `json:"type"`
+	First  hexutil.Bytes `json:"first" ssz-max:"16777216"`
+	Second hexutil.Bytes `json:"second" ssz-max:"16777216"`
+}
+
+// ConflictingProposalHash returns the hash of the proposal that the message votes for
+// if signing two such messages with different hashes for the same view is an equivocation.
+// Otherwise, it returns nil.
+func ConflictingProposalHash(msg *proto.IbftMessage) []byte {
+	switch msg.GetType() {
+	case proto.MessageType_PREPREPARE:
+		return msg.GetPreprepareData().GetProposalHash()
+	case proto.MessageType_COMMIT:
+		return msg.GetCommitData().GetProposalHash()
+	default:
+		return nil
+	}
+}
+
+// NewDoubleSignEvidence creates the evidence from two conflicting messages of the same validator.
+// The messages are expected to be validated already, the result can be checked with Verify.
+func NewDoubleSignEvidence(shardId ShardId, first, second *proto.IbftMessage) (*DoubleSignEvidence, error) {
+	firstData, err := protobuf.Marshal(first)
+	if err != nil {
+		return nil, err
+	}
+	secondData, err := protobuf.Marshal(second)
+	if err != nil {
+		return nil, err
+	}
+	return &DoubleSignEvidence{
+		ShardId:   shardId,
+		PublicKey: first.GetFrom(),
+		Height:    first.GetView().GetHeight(),
+		Round:     first.GetView().GetRound(),
+		Type:      uint32(first.GetType()),
+		First:     firstData,
+		Second:    secondData,
+	}, nil
+}
+
+func (e *DoubleSignEvidence) verifyMessage(pubkey bls.PublicKey, data []byte) ([]byte, error) {
+	msg := &proto.IbftMessage{}
+	if err := protobuf.Unmarshal(data, msg); err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(msg.GetFrom(), e.PublicKey) {
+		return nil, errors.New("message is sent by another validator")
+	}
+	if msg.GetView().GetHeight() != e.Height || msg.GetView().GetRound() != e.Round {
+		return nil, errors.New("message is sent for another view")
+	}
+	if uint32(msg.GetType()) != e.Type {
+		return nil, errors.New("message has another type")
+	}
+
+	hash := ConflictingProposalHash(msg)
+	if hash == nil {
+		return nil, fmt.Errorf("messages of type %s can't conflict", msg.GetType())
+	}
+
+	payload, err := msg.PayloadNoSig()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := bls.SignatureFromBytes(msg.GetSignature())
+	if err != nil {
+		return nil, err
+	}
+	if err := sig.Verify(pubkey, common.KeccakHash(ConsensusSigningData(e.ShardId, payload)).Bytes()); err != nil {
+		return nil, err
+	}
+	return hash, nil
+}
+
+// Verify checks that both messages are signed by the validator for the same view of the shard
+// and that they vote for different proposals.
+// It doesn't check that the key belongs to a validator of the shard at that height.
+func (e *DoubleSignEvidence) Verify() error {
+	pubkey, err := bls.PublicKeyFromBytes(e.PublicKey)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDoubleSignEvidence, err)
+	}
+
+	firstHash, err := e.verifyMessage(pubkey, e.First)
+	if err != nil {
+		return fmt.Errorf("%w: first message: %w", ErrInvalidDoubleSignEvidence, err)
+	}
+	secondHash, err := e.verifyMessage(pubkey, e.Second)
+	if err != nil {
+		return fmt.Errorf("%w: second message: %w", ErrInvalidDoubleSignEvidence, err)
+	}
+
+	if bytes.Equal(firstHash, secondHash) {
+		return fmt.Errorf("%w: messages don't conflict", ErrInvalidDoubleSignEvidence)
+	}
+	return nil
+}
+
+//go:generate go run github.com/NilFoundation/fastssz/sszgen --path evidence.go -include ../../common/hexutil/bytes.go,shard.go --objs DoubleSignEvidence
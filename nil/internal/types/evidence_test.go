@@ -0,0 +1,101 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
+	"github.com/NilFoundation/nil/nil/internal/crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func newSignedCommit(
+	t *testing.T, shardId ShardId, key bls.PrivateKey, height, round uint64, proposalHash []byte,
+) *proto.IbftMessage {
+	t.Helper()
+
+	from, err := key.PublicKey().Marshal()
+	require.NoError(t, err)
+
+	msg := &proto.IbftMessage{
+		View: &proto.View{Height: height, Round: round},
+		From: from,
+		Type: proto.MessageType_COMMIT,
+		Payload: &proto.IbftMessage_CommitData{
+			CommitData: &proto.CommitMessage{ProposalHash: proposalHash},
+		},
+	}
+	payload, err := msg.PayloadNoSig()
+	require.NoError(t, err)
+	sig, err := key.Sign(common.KeccakHash(ConsensusSigningData(shardId, payload)).Bytes())
+	require.NoError(t, err)
+	msg.Signature, err = sig.Marshal()
+	require.NoError(t, err)
+	return msg
+}
+
+func TestDoubleSignEvidence(t *testing.T) {
+	t.Parallel()
+
+	key := bls.NewRandomKey()
+	first := newSignedCommit(t, MainShardId, key, 10, 1, []byte{1})
+	second := newSignedCommit(t, MainShardId, key, 10, 1, []byte{2})
+
+	evidence, err := NewDoubleSignEvidence(MainShardId, first, second)
+	require.NoError(t, err)
+	require.NoError(t, evidence.Verify())
+
+	// The evidence survives the serialization used by the governance contract.
+	data, err := evidence.MarshalSSZ()
+	require.NoError(t, err)
+	var decoded DoubleSignEvidence
+	require.NoError(t, decoded.UnmarshalSSZ(data))
+	require.Equal(t, evidence, &decoded)
+	require.NoError(t, decoded.Verify())
+
+	t.Run("NoConflict", func(t *testing.T) {
+		t.Parallel()
+
+		evidence, err := NewDoubleSignEvidence(MainShardId, first, newSignedCommit(t, MainShardId, key, 10, 1, []byte{1}))
+		require.NoError(t, err)
+		require.ErrorIs(t, evidence.Verify(), ErrInvalidDoubleSignEvidence)
+	})
+
+	t.Run("AnotherView", func(t *testing.T) {
+		t.Parallel()
+
+		evidence, err := NewDoubleSignEvidence(MainShardId, first, newSignedCommit(t, MainShardId, key, 10, 2, []byte{2}))
+		require.NoError(t, err)
+		require.ErrorIs(t, evidence.Verify(), ErrInvalidDoubleSignEvidence)
+	})
+
+	t.Run("AnotherSigner", func(t *testing.T) {
+		t.Parallel()
+
+		other := newSignedCommit(t, MainShardId, bls.NewRandomKey(), 10, 1, []byte{2})
+		evidence, err := NewDoubleSignEvidence(MainShardId, first, other)
+		require.NoError(t, err)
+		require.ErrorIs(t, evidence.Verify(), ErrInvalidDoubleSignEvidence)
+
+		// The message is attributed to the validator but signed by someone else.
+		forged := newSignedCommit(t, MainShardId, bls.NewRandomKey(), 10, 1, []byte{2})
+		forged.From = first.GetFrom()
+		evidence, err = NewDoubleSignEvidence(MainShardId, first, forged)
+		require.NoError(t, err)
+		require.ErrorIs(t, evidence.Verify(), ErrInvalidDoubleSignEvidence)
+	})
+	t.Run("AnotherShard", func(t *testing.T) {
+		t.Parallel()
+
+		// The messages of the base shard can't be presented as the messages of the main shard.
+		first := newSignedCommit(t, BaseShardId, key, 10, 1, []byte{1})
+		second := newSignedCommit(t, BaseShardId, key, 10, 1, []byte{2})
+		evidence, err := NewDoubleSignEvidence(MainShardId, first, second)
+		require.NoError(t, err)
+		require.ErrorIs(t, evidence.Verify(), ErrInvalidDoubleSignEvidence)
+
+		evidence, err = NewDoubleSignEvidence(BaseShardId, first, second)
+		require.NoError(t, err)
+		require.NoError(t, evidence.Verify())
+	})
+}
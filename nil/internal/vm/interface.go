@@ -101,6 +101,9 @@ type StateDB interface {
 
 	GetConfigAccessor() config.ConfigAccessor
 
+	// GetShardValidators returns the validators of the shard at the height.
+	GetShardValidators(shardId types.ShardId, height uint64) ([]config.ValidatorInfo, error)
+
 	Rollback(counter, patchLevel uint32, mainBlock uint64) error
 }
 
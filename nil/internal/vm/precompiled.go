@@ -17,6 +17,7 @@
 package vm
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
@@ -589,7 +590,20 @@ func (g *governance) Run(evm *EVM, input []byte, value *uint256.Int, caller Cont
 	if caller.Address() != types.GovernanceAddress {
 		return nil, types.NewVmError(types.ErrorPrecompileWrongCaller)
 	}
+	if len(input) < 4 {
+		return nil, types.NewVmError(types.ErrorPrecompileTooShortCallData)
+	}
+
+	if bytes.Equal(input[:4], getPrecompiledMethod("precompileScheduleValidatorChange").ID) {
+		return g.scheduleValidatorChange(evm, input)
+	}
+	if bytes.Equal(input[:4], getPrecompiledMethod("precompileReportDoubleSign").ID) {
+		return g.reportDoubleSign(evm, input)
+	}
+	return g.rollback(evm, input)
+}
 
+func (g *governance) rollback(evm *EVM, input []byte) ([]byte, error) {
 	args, err := precompiledArgs("precompileRollback", input, 4)
 	if err != nil {
 		return nil, err
@@ -621,6 +635,111 @@ func (g *governance) Run(evm *EVM, input []byte, value *uint256.Int, caller Cont
 	return res, err
 }
 
+// scheduleValidatorChange adds or removes a validator of the shard starting from the next epoch of the main shard.
+func (g *governance) scheduleValidatorChange(evm *EVM, input []byte) ([]byte, error) {
+	args, err := precompiledArgs("precompileScheduleValidatorChange", input, 6)
+	if err != nil {
+		return nil, err
+	}
+
+	version, ok := args[0].(uint32)
+	if !ok || version != 1 {
+		return nil, types.NewVmError(types.ErrorPrecompileWrongVersion)
+	}
+
+	shardId, ok1 := args[1].(uint32)
+	remove, ok2 := args[2].(bool)
+	pubkey, ok3 := args[3].([]byte)
+	withdrawalAddress, ok4 := args[4].(types.Address)
+	weight, ok5 := args[5].(uint64)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 {
+		return nil, types.NewVmError(types.ErrorAbiUnpackFailed)
+	}
+
+	if !evm.StateDB.GetShardID().IsMainShard() {
+		return nil, types.NewVmError(types.ErrorOnlyMainShardContractsCanChangeConfig)
+	}
+
+	change := config.ValidatorChange{
+		ShardId: shardId,
+		Remove:  remove,
+		Validator: config.ValidatorInfo{
+			WithdrawalAddress: withdrawalAddress,
+		},
+		Weight: weight,
+	}
+	if len(pubkey) != len(change.Validator.PublicKey) {
+		return nil, types.NewVmVerboseError(types.ErrorPrecompileBadArgument,
+			fmt.Sprintf("public key must be %d bytes long", len(change.Validator.PublicKey)))
+	}
+	copy(change.Validator.PublicKey[:], pubkey)
+
+	if err := config.ScheduleValidatorChange(evm.StateDB.GetConfigAccessor(), change); err != nil {
+		return nil, types.NewVmVerboseError(types.ErrorPrecompileConfigSetParamFailed, err.Error())
+	}
+
+	res := make([]byte, 32)
+	res[31] = 1
+	return res, nil
+}
+
+// reportDoubleSign verifies the evidence of the validator's equivocation
+// and schedules the removal of the validator from the shard starting from the next epoch of the main shard.
+func (g *governance) reportDoubleSign(evm *EVM, input []byte) ([]byte, error) {
+	args, err := precompiledArgs("precompileReportDoubleSign", input, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	version, ok := args[0].(uint32)
+	if !ok || version != 1 {
+		return nil, types.NewVmError(types.ErrorPrecompileWrongVersion)
+	}
+
+	data, ok := args[1].([]byte)
+	if !ok {
+		return nil, types.NewVmError(types.ErrorAbiUnpackFailed)
+	}
+
+	if !evm.StateDB.GetShardID().IsMainShard() {
+		return nil, types.NewVmError(types.ErrorOnlyMainShardContractsCanChangeConfig)
+	}
+
+	var evidence types.DoubleSignEvidence
+	if err := evidence.UnmarshalSSZ(data); err != nil {
+		return nil, types.NewVmVerboseError(types.ErrorPrecompileBadArgument, err.Error())
+	}
+	if err := evidence.Verify(); err != nil {
+		return nil, types.NewVmVerboseError(types.ErrorPrecompileBadArgument, err.Error())
+	}
+
+	var pubkey config.Pubkey
+	if len(evidence.PublicKey) != len(pubkey) {
+		return nil, types.NewVmVerboseError(types.ErrorPrecompileBadArgument,
+			fmt.Sprintf("public key must be %d bytes long", len(pubkey)))
+	}
+	copy(pubkey[:], evidence.PublicKey)
+
+	// The signatures prove that the messages belong to the shard, but only a validator of the shard
+	// at that height can equivocate in its consensus.
+	validators, err := evm.StateDB.GetShardValidators(evidence.ShardId, evidence.Height)
+	if err != nil {
+		return nil, types.NewVmVerboseError(types.ErrorPrecompileBadArgument, err.Error())
+	}
+	if !slices.ContainsFunc(validators, func(v config.ValidatorInfo) bool { return v.PublicKey == pubkey }) {
+		return nil, types.NewVmVerboseError(types.ErrorPrecompileBadArgument,
+			fmt.Sprintf("%x is not a validator of shard %s at height %d", pubkey, evidence.ShardId, evidence.Height))
+	}
+
+	if err := config.ScheduleValidatorRemoval(evm.StateDB.GetConfigAccessor(), evidence.ShardId, pubkey); err != nil {
+		return nil, types.NewVmVerboseError(types.ErrorPrecompileConfigSetParamFailed, err.Error())
+	}
+
+	res := make([]byte, 32)
+	res[31] = 1
+	return res, nil
+}
+
 type checkIsResponse struct{}
 
 var _ ReadOnlyPrecompiledContract = (*checkIsResponse)(nil)
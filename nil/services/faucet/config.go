@@ -0,0 +1,39 @@
+package faucet
+
+import (
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// Config defines the abuse protection of the faucet.
+// The amounts are counted separately for each faucet in the units of its token, zero values disable the limits.
+type Config struct {
+	// DbPath is the path to the database storing the quota state, the state is kept in memory if empty.
+	DbPath string `yaml:"dbPath,omitempty"`
+
+	// MaxAmount is the maximum amount of a single top-up.
+	MaxAmount types.Value `yaml:"maxAmount,omitempty"`
+	// QuotaWindow is the sliding window the quotas are counted over.
+	QuotaWindow time.Duration `yaml:"quotaWindow,omitempty"`
+	// RecipientQuota is the maximum amount a single address may receive within the window.
+	RecipientQuota types.Value `yaml:"recipientQuota,omitempty"`
+	// ClientQuota is the maximum amount a single client may request within the window.
+	ClientQuota types.Value `yaml:"clientQuota,omitempty"`
+	// Cooldown is the minimum interval between the top-ups of the same address.
+	Cooldown time.Duration `yaml:"cooldown,omitempty"`
+
+	// ClientIpHeader is the HTTP header carrying the client IP set by a reverse proxy (e.g., X-Real-IP).
+	// The remote address of the connection is used if empty.
+	ClientIpHeader string `yaml:"clientIpHeader,omitempty"`
+}
+
+func NewDefaultConfig() *Config {
+	return &Config{
+		QuotaWindow: 24 * time.Hour,
+	}
+}
+
+func (c *Config) quotasEnabled() bool {
+	return c.Cooldown > 0 || (c.QuotaWindow > 0 && (!c.RecipientQuota.IsZero() || !c.ClientQuota.IsZero()))
+}
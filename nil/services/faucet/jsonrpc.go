@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/client"
 	"github.com/NilFoundation/nil/nil/client/rpc"
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/internal/contracts"
+	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
@@ -23,6 +25,9 @@ type API interface {
 
 type APIImpl struct {
 	client client.Client
+	cfg    *Config
+	// quotas is nil if the quotas are disabled.
+	quotas *quotas
 
 	// Requests are served by one which is the easiest way to avoid seqno gaps.
 	mu sync.Mutex
@@ -33,11 +38,17 @@ type APIImpl struct {
 
 var _ API = (*APIImpl)(nil)
 
-func NewAPI(client client.Client) *APIImpl {
-	return &APIImpl{
+// NewAPI creates the faucet API. The database stores the quota state, it's required only if the quotas are enabled.
+func NewAPI(client client.Client, cfg *Config, database db.DB) *APIImpl {
+	api := &APIImpl{
 		client: client,
+		cfg:    cfg,
 		seqnos: make(map[types.Address]types.Seqno),
 	}
+	if cfg.quotasEnabled() {
+		api.quotas = newQuotas(cfg, database)
+	}
+	return api
 }
 
 func (c *APIImpl) fetchSeqno(ctx context.Context, addr types.Address) (types.Seqno, error) {
@@ -68,9 +79,28 @@ func (c *APIImpl) TopUpViaFaucet(
 	contractAddressTo types.Address,
 	amount types.Value,
 ) (common.Hash, error) {
+	if !c.cfg.MaxAmount.IsZero() && amount.Cmp(c.cfg.MaxAmount) > 0 {
+		return common.EmptyHash, &AmountTooLargeError{Amount: amount, Limit: c.cfg.MaxAmount}
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	// Requests are served one by one, so the quotas can't be exceeded by the concurrent requests.
+	client, _ := ctx.Value(transport.ClientKeyContextKey).(string)
+	req := &topUpRequest{
+		faucet:    faucetAddress,
+		recipient: contractAddressTo,
+		client:    client,
+		amount:    amount,
+	}
+	now := time.Now()
+	if c.quotas != nil {
+		if err := c.quotas.check(ctx, req, now); err != nil {
+			return common.EmptyHash, err
+		}
+	}
+
 	seqno, err := c.getOrFetchSeqno(ctx, faucetAddress)
 	if err != nil {
 		return common.EmptyHash, err
@@ -126,6 +156,12 @@ func (c *APIImpl) TopUpViaFaucet(
 
 	c.seqnos[faucetAddress] = seqno + 1
 
+	if c.quotas != nil {
+		if err := c.quotas.record(ctx, req, now); err != nil {
+			return common.EmptyHash, fmt.Errorf("transaction %s is sent, but failed to update the quotas: %w", hash, err)
+		}
+	}
+
 	return hash, nil
 }
 
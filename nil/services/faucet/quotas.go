@@ -0,0 +1,255 @@
+package faucet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+const (
+	// recipientQuotasTable stores the recent top-ups of the addresses.
+	// Key: faucet address + recipient address, Value: quotaState.
+	recipientQuotasTable db.TableName = "faucet_recipient_quotas"
+	// clientQuotasTable stores the recent top-ups requested by the clients.
+	// Key: faucet address + client key, Value: quotaState.
+	clientQuotasTable db.TableName = "faucet_client_quotas"
+)
+
+// AmountTooLargeError is returned when the requested amount exceeds the limit of a single top-up.
+type AmountTooLargeError struct {
+	Amount types.Value
+	Limit  types.Value
+}
+
+func (e *AmountTooLargeError) ErrorCode() int { return -32602 }
+
+func (e *AmountTooLargeError) Error() string {
+	return fmt.Sprintf("requested amount %s exceeds the faucet limit of %s", e.Amount, e.Limit)
+}
+
+// QuotaExceededError is returned when the top-up exceeds the quota of the recipient or the client.
+type QuotaExceededError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *QuotaExceededError) ErrorCode() int { return -32005 }
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("faucet %s exceeded, retry in %s", e.Reason, e.RetryAfter.Round(time.Second))
+}
+
+func (e *QuotaExceededError) ErrorData() any {
+	return map[string]int64{"retryAfterMs": e.RetryAfter.Milliseconds()}
+}
+
+// quotaRecord is a top-up counted against the quotas.
+type quotaRecord struct {
+	Time   time.Time   `json:"time"`
+	Amount types.Value `json:"amount"`
+}
+
+// quotaState holds the top-ups within the retention period ordered by time.
+type quotaState []quotaRecord
+
+type topUpRequest struct {
+	faucet    types.Address
+	recipient types.Address
+	client    string
+	amount    types.Value
+}
+
+func (r *topUpRequest) recipientKey() []byte {
+	return append(r.faucet.Bytes(), r.recipient.Bytes()...)
+}
+
+func (r *topUpRequest) clientKey() []byte {
+	return append(r.faucet.Bytes(), r.client...)
+}
+
+// quotas tracks the top-ups over a sliding window in the database, so the quotas survive restarts.
+type quotas struct {
+	cfg      *Config
+	database db.DB
+}
+
+func newQuotas(cfg *Config, database db.DB) *quotas {
+	return &quotas{
+		cfg:      cfg,
+		database: database,
+	}
+}
+
+// retention is the period the top-ups are kept for.
+func (q *quotas) retention() time.Duration {
+	return max(q.cfg.QuotaWindow, q.cfg.Cooldown)
+}
+
+// check returns an error if the top-up doesn't fit into the quotas.
+func (q *quotas) check(ctx context.Context, req *topUpRequest, now time.Time) error {
+	tx, err := q.database.CreateRoTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	recipient, err := q.readState(tx, recipientQuotasTable, req.recipientKey(), now)
+	if err != nil {
+		return err
+	}
+	if q.cfg.Cooldown > 0 && len(recipient) > 0 {
+		if next := recipient[len(recipient)-1].Time.Add(q.cfg.Cooldown); next.After(now) {
+			return &QuotaExceededError{Reason: "recipient cooldown", RetryAfter: next.Sub(now)}
+		}
+	}
+	if err := q.checkQuota(recipient, q.cfg.RecipientQuota, "recipient quota", req.amount, now); err != nil {
+		return err
+	}
+
+	if req.client == "" {
+		return nil
+	}
+	client, err := q.readState(tx, clientQuotasTable, req.clientKey(), now)
+	if err != nil {
+		return err
+	}
+	return q.checkQuota(client, q.cfg.ClientQuota, "client quota", req.amount, now)
+}
+
+func (q *quotas) checkQuota(
+	state quotaState, quota types.Value, reason string, amount types.Value, now time.Time,
+) error {
+	if quota.IsZero() || q.cfg.QuotaWindow <= 0 {
+		return nil
+	}
+	if amount.Cmp(quota) > 0 {
+		return &AmountTooLargeError{Amount: amount, Limit: quota}
+	}
+
+	windowStart := now.Add(-q.cfg.QuotaWindow)
+	used := types.NewZeroValue()
+	for _, r := range state {
+		if r.Time.After(windowStart) {
+			used = used.Add(r.Amount)
+		}
+	}
+	excess, fits := used.Add(amount).SubOverflow(quota)
+	if fits || excess.IsZero() {
+		return nil
+	}
+
+	// The quota is released as the oldest top-ups leave the window.
+	for _, r := range state {
+		if !r.Time.After(windowStart) {
+			continue
+		}
+		if r.Amount.Cmp(excess) >= 0 {
+			return &QuotaExceededError{Reason: reason, RetryAfter: r.Time.Add(q.cfg.QuotaWindow).Sub(now)}
+		}
+		excess = excess.Sub(r.Amount)
+	}
+	return &QuotaExceededError{Reason: reason, RetryAfter: q.cfg.QuotaWindow}
+}
+
+// record counts the top-up against the quotas.
+func (q *quotas) record(ctx context.Context, req *topUpRequest, now time.Time) error {
+	tx, err := q.database.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := q.appendRecord(tx, recipientQuotasTable, req.recipientKey(), req.amount, now); err != nil {
+		return err
+	}
+	if req.client != "" {
+		if err := q.appendRecord(tx, clientQuotasTable, req.clientKey(), req.amount, now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (q *quotas) appendRecord(tx db.RwTx, table db.TableName, key []byte, amount types.Value, now time.Time) error {
+	state, err := q.readState(tx, table, key, now)
+	if err != nil {
+		return err
+	}
+	state = append(state, quotaRecord{Time: now, Amount: amount})
+
+	value, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return tx.Put(table, key, value)
+}
+
+// readState returns the top-ups within the retention period.
+func (q *quotas) readState(tx db.RoTx, table db.TableName, key []byte, now time.Time) (quotaState, error) {
+	value, err := tx.Get(table, key)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state quotaState
+	if err := json.Unmarshal(value, &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal quota state: %w", err)
+	}
+	return q.expire(state, now), nil
+}
+
+func (q *quotas) expire(state quotaState, now time.Time) quotaState {
+	retentionStart := now.Add(-q.retention())
+	for i, r := range state {
+		if r.Time.After(retentionStart) {
+			return state[i:]
+		}
+	}
+	return nil
+}
+
+// prune removes the states that have no top-ups within the retention period.
+func (q *quotas) prune(ctx context.Context, now time.Time) (int, error) {
+	tx, err := q.database.CreateRwTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	pruned := 0
+	for _, table := range []db.TableName{recipientQuotasTable, clientQuotasTable} {
+		var expired [][]byte
+		iter, err := tx.Range(table, nil, nil)
+		if err != nil {
+			return 0, err
+		}
+		for iter.HasNext() {
+			key, value, err := iter.Next()
+			if err != nil {
+				iter.Close()
+				return 0, err
+			}
+			var state quotaState
+			if err := json.Unmarshal(value, &state); err != nil || len(q.expire(state, now)) == 0 {
+				expired = append(expired, key)
+			}
+		}
+		iter.Close()
+
+		for _, key := range expired {
+			if err := tx.Delete(table, key); err != nil {
+				return 0, err
+			}
+		}
+		pruned += len(expired)
+	}
+	return pruned, tx.Commit()
+}
@@ -0,0 +1,135 @@
+package faucet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestQuotas(t *testing.T, cfg *Config) *quotas {
+	t.Helper()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+	return newQuotas(cfg, database)
+}
+
+func TestRecipientQuota(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	q := newTestQuotas(t, &Config{
+		QuotaWindow:    time.Hour,
+		RecipientQuota: types.NewValueFromUint64(100),
+	})
+
+	start := time.Now()
+	req := &topUpRequest{
+		faucet:    types.FaucetAddress,
+		recipient: types.GenerateRandomAddress(1),
+		amount:    types.NewValueFromUint64(40),
+	}
+
+	// 40 + 40 fit, the third top-up doesn't.
+	require.NoError(t, q.check(ctx, req, start))
+	require.NoError(t, q.record(ctx, req, start))
+	require.NoError(t, q.check(ctx, req, start.Add(time.Minute)))
+	require.NoError(t, q.record(ctx, req, start.Add(time.Minute)))
+
+	var quotaErr *QuotaExceededError
+	require.ErrorAs(t, q.check(ctx, req, start.Add(2*time.Minute)), &quotaErr)
+	// The first top-up leaves the window in 58 minutes.
+	require.Equal(t, 58*time.Minute, quotaErr.RetryAfter)
+
+	// The quotas are counted per recipient and per faucet.
+	other := *req
+	other.recipient = types.GenerateRandomAddress(1)
+	require.NoError(t, q.check(ctx, &other, start.Add(2*time.Minute)))
+	other = *req
+	other.faucet = types.EthFaucetAddress
+	require.NoError(t, q.check(ctx, &other, start.Add(2*time.Minute)))
+
+	// The window slides.
+	require.NoError(t, q.check(ctx, req, start.Add(time.Hour+time.Second)))
+
+	// The amount never fitting into the quota is rejected.
+	big := *req
+	big.amount = types.NewValueFromUint64(101)
+	var amountErr *AmountTooLargeError
+	require.ErrorAs(t, q.check(ctx, &big, start.Add(2*time.Hour)), &amountErr)
+}
+
+func TestClientQuotaAndCooldown(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	q := newTestQuotas(t, &Config{
+		QuotaWindow: time.Hour,
+		ClientQuota: types.NewValueFromUint64(100),
+		Cooldown:    10 * time.Minute,
+	})
+
+	start := time.Now()
+	newReq := func() *topUpRequest {
+		return &topUpRequest{
+			faucet:    types.FaucetAddress,
+			recipient: types.GenerateRandomAddress(1),
+			client:    "ip:127.0.0.1",
+			amount:    types.NewValueFromUint64(60),
+		}
+	}
+
+	req := newReq()
+	require.NoError(t, q.check(ctx, req, start))
+	require.NoError(t, q.record(ctx, req, start))
+
+	// The same recipient is in cooldown.
+	var quotaErr *QuotaExceededError
+	require.ErrorAs(t, q.check(ctx, req, start.Add(time.Minute)), &quotaErr)
+	require.Equal(t, 9*time.Minute, quotaErr.RetryAfter)
+
+	// Another recipient requested by the same client exceeds the client quota.
+	require.ErrorAs(t, q.check(ctx, newReq(), start.Add(time.Minute)), &quotaErr)
+	require.Equal(t, "client quota", quotaErr.Reason)
+
+	// Requests without the client key are limited only by the recipient limits.
+	anonymous := newReq()
+	anonymous.client = ""
+	require.NoError(t, q.check(ctx, anonymous, start.Add(time.Minute)))
+}
+
+func TestQuotaPrune(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	q := newTestQuotas(t, &Config{
+		QuotaWindow:    time.Hour,
+		RecipientQuota: types.NewValueFromUint64(100),
+		ClientQuota:    types.NewValueFromUint64(100),
+	})
+
+	start := time.Now()
+	for i := range 3 {
+		req := &topUpRequest{
+			faucet:    types.FaucetAddress,
+			recipient: types.GenerateRandomAddress(1),
+			client:    "ip:127.0.0.1",
+			amount:    types.NewValueFromUint64(10),
+		}
+		require.NoError(t, q.record(ctx, req, start.Add(time.Duration(i)*time.Hour)))
+	}
+
+	// The first two recipients expired, the client has a top-up within the window.
+	pruned, err := q.prune(ctx, start.Add(2*time.Hour+time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 2, pruned)
+
+	pruned, err = q.prune(ctx, start.Add(4*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 2, pruned)
+}
@@ -2,36 +2,96 @@ package faucet
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/common/concurrent"
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/services/rpc"
 	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 )
 
+// quotaPruneInterval is the interval of removing the expired quota states from the database.
+const quotaPruneInterval = time.Hour
+
 type Service struct {
-	impl API
+	impl     *APIImpl
+	cfg      *Config
+	database db.DB
+	logger   logging.Logger
 }
 
-func NewService(client client.Client) (*Service, error) {
-	return &Service{impl: NewAPI(client)}, nil
+func NewService(client client.Client, cfg *Config) (*Service, error) {
+	s := &Service{
+		cfg:    cfg,
+		logger: logging.NewLogger("faucet"),
+	}
+
+	if cfg.quotasEnabled() {
+		var err error
+		if cfg.DbPath != "" {
+			s.database, err = db.NewBadgerDb(cfg.DbPath)
+		} else {
+			s.database, err = db.NewBadgerDbInMemory()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open quota database: %w", err)
+		}
+	}
+
+	s.impl = NewAPI(client, cfg, s.database)
+	return s, nil
 }
 
 func (s *Service) Run(ctx context.Context, endpoint string) error {
-	err := s.startRpcServer(ctx, endpoint)
-	return err
+	if s.database == nil {
+		return s.startRpcServer(ctx, endpoint)
+	}
+	defer s.database.Close()
+
+	s.pruneQuotas(ctx)
+	return concurrent.Run(
+		ctx,
+		concurrent.MakeTask("faucet-rpc", func(ctx context.Context) error {
+			return s.startRpcServer(ctx, endpoint)
+		}),
+		concurrent.MakeTask("faucet-quota-pruner", func(ctx context.Context) error {
+			concurrent.RunTickerLoop(ctx, quotaPruneInterval, s.pruneQuotas)
+			return nil
+		}),
+	)
+}
+
+func (s *Service) pruneQuotas(ctx context.Context) {
+	pruned, err := s.impl.quotas.prune(ctx, time.Now())
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to prune faucet quotas")
+		return
+	}
+	if pruned > 0 {
+		s.logger.Debug().Int("pruned", pruned).Msg("Pruned expired faucet quotas")
+	}
 }
 
 func (s *Service) GetRpcApi() transport.API {
 	return transport.API{
 		Namespace: "faucet",
 		Public:    true,
-		Service:   s.impl,
+		Service:   API(s.impl),
 		Version:   "1.0",
 	}
 }
 
+// limits identify the clients of the quotas by the IP set by the proxy, if configured.
+func (s *Service) limits() httpcfg.LimitsCfg {
+	return httpcfg.LimitsCfg{
+		ClientIpHeader: s.cfg.ClientIpHeader,
+	}
+}
+
 func (s *Service) startRpcServer(ctx context.Context, endpoint string) error {
 	logger := logging.NewLogger("RPC")
 
@@ -41,6 +101,7 @@ func (s *Service) startRpcServer(ctx context.Context, endpoint string) error {
 		TraceRequests:   true,
 		HTTPTimeouts:    httpcfg.DefaultHTTPTimeouts,
 		HttpCORSDomain:  []string{"*"},
+		Limits:          s.limits(),
 	}
 
 	apiList := []transport.API{
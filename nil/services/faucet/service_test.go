@@ -0,0 +1,86 @@
+package faucet
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientQuotaBehindProxy(t *testing.T) {
+	t.Parallel()
+
+	clientMock := &client.ClientMock{
+		GetTransactionCountFunc: func(context.Context, types.Address, any) (types.Seqno, error) {
+			return 0, nil
+		},
+		SendRawTransactionFunc: func(context.Context, []byte) (common.Hash, error) {
+			return common.HexToHash("0x01"), nil
+		},
+	}
+	s, err := NewService(clientMock, &Config{
+		QuotaWindow:    time.Hour,
+		ClientQuota:    types.NewValueFromUint64(100),
+		ClientIpHeader: "X-Real-IP",
+	})
+	require.NoError(t, err)
+	t.Cleanup(s.database.Close)
+
+	srv := transport.NewServer(false /* traceRequests */, false /* traceSingleRequest */, logging.NewLogger("test"), 0, nil)
+	srv.SetLimits(s.limits())
+	require.NoError(t, srv.RegisterName("faucet", s.impl))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.ServeSingleRequest(r.Context(), r, w)
+	}))
+	t.Cleanup(func() {
+		ts.Close()
+		srv.Stop()
+	})
+
+	// All the requests come from the proxy address, the clients are told apart by the header.
+	topUp := func(clientIp string) int {
+		t.Helper()
+
+		params, err := json.Marshal([]any{types.FaucetAddress, types.GenerateRandomAddress(1), types.NewValueFromUint64(60)})
+		require.NoError(t, err)
+		body := `{"jsonrpc":"2.0","id":1,"method":"faucet_topUpViaFaucet","params":` + string(params) + `}`
+		req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Real-IP", clientIp)
+
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var res struct {
+			Error *struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+		if res.Error == nil {
+			return 0
+		}
+		require.Contains(t, res.Error.Message, "client quota")
+		return res.Error.Code
+	}
+
+	require.Zero(t, topUp("10.0.0.1"))
+	require.Zero(t, topUp("10.0.0.2"))
+	// 60 + 60 exceed the quota of each client.
+	quotaExceeded := (&QuotaExceededError{}).ErrorCode()
+	require.Equal(t, quotaExceeded, topUp("10.0.0.1"))
+	require.Equal(t, quotaExceeded, topUp("10.0.0.2"))
+}
@@ -0,0 +1,160 @@
+package nilservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/collate"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+)
+
+func (c *Config) chainDataShardIds() []types.ShardId {
+	if len(c.ChainData.Shards) == 0 {
+		shardIds := make([]types.ShardId, c.NShards)
+		for i := range shardIds {
+			shardIds[i] = types.ShardId(i)
+		}
+		return shardIds
+	}
+
+	shardIds := make([]types.ShardId, len(c.ChainData.Shards))
+	for i, shard := range c.ChainData.Shards {
+		shardIds[i] = types.ShardId(shard)
+	}
+	return shardIds
+}
+
+// ExportChain writes the configured block range of the local database into the chain data file.
+func ExportChain(ctx context.Context, cfg *Config, database db.DB) error {
+	logger := logging.NewLogger("chain-export")
+
+	if cfg.ChainData.Path == "" {
+		return errors.New("chain data file is not specified")
+	}
+
+	shardIds := cfg.chainDataShardIds()
+	for _, shardId := range shardIds {
+		if uint32(shardId) >= cfg.NShards {
+			return fmt.Errorf("shard %s is out of range (nShards = %d)", shardId, cfg.NShards)
+		}
+	}
+
+	f, err := os.Create(cfg.ChainData.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	exported, err := collate.ExportChain(ctx, database, f, collate.ChainExportParams{
+		ShardIds:   shardIds,
+		FirstBlock: cfg.ChainData.FirstBlock,
+		LastBlock:  cfg.ChainData.LastBlock,
+	})
+	if err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	for _, shardId := range shardIds {
+		logger.Info().
+			Stringer(logging.FieldShardId, shardId).
+			Uint64("blocks", exported[shardId]).
+			Msg("Exported blocks")
+	}
+	return nil
+}
+
+// ImportChain replays the blocks from the chain data file into the local database.
+// Empty shards are initialized with the configured zero state first,
+// so the node has to be configured the same way as the one the file was exported from.
+func ImportChain(ctx context.Context, cfg *Config, database db.DB) error {
+	logger := logging.NewLogger("chain-import")
+
+	if cfg.ChainData.Path == "" {
+		return errors.New("chain data file is not specified")
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if cfg.CollatorTickPeriodMs == 0 {
+		cfg.CollatorTickPeriodMs = defaultCollatorTickPeriodMs
+	}
+	if cfg.SyncTimeoutFactor == 0 {
+		cfg.SyncTimeoutFactor = defaultSyncTimeoutFactor
+	}
+	if cfg.ZeroState == nil {
+		var err error
+		cfg.ZeroState, err = execution.CreateDefaultZeroStateConfig(nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Open(cfg.ChainData.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	collatorTickPeriod := time.Millisecond * time.Duration(cfg.CollatorTickPeriodMs)
+	validators := make([]*collate.Validator, cfg.NShards)
+	for i := range cfg.NShards {
+		shardId := types.ShardId(i)
+		params := createCollateParams(shardId, cfg, collatorTickPeriod)
+
+		// Imported blocks are not announced anywhere, so neither pool nor network are needed.
+		var pool *txnpool.TxnPool
+		validators[i], err = collate.NewValidator(params, validators[0], database, pool, nil)
+		if err != nil {
+			return err
+		}
+
+		syncer, err := collate.NewSyncer(getSyncerConfig("chain-import", cfg, shardId), validators[i], database, nil)
+		if err != nil {
+			return err
+		}
+		if err := syncer.GenerateZerostateIfShardIsEmpty(ctx); err != nil {
+			return err
+		}
+	}
+
+	imported, err := collate.ImportChain(ctx, f, validators, logger)
+	if err != nil {
+		return err
+	}
+
+	for shardId := range types.ShardId(cfg.NShards) {
+		logger.Info().
+			Stringer(logging.FieldShardId, shardId).
+			Uint64("blocks", imported[shardId]).
+			Msg("Imported blocks")
+	}
+	return nil
+}
+
+func runChainDataCommand(ctx context.Context, cfg *Config, database db.DB) int {
+	var err error
+	switch cfg.RunMode {
+	case ExportChainRunMode:
+		err = ExportChain(ctx, cfg, database)
+	case ImportChainRunMode:
+		err = ImportChain(ctx, cfg, database)
+	default:
+		panic("unsupported run mode")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to process chain data: %s\n", err.Error())
+		return 1
+	}
+	return 0
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/collate"
@@ -18,8 +19,10 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/tracing"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/cometa"
+	"github.com/NilFoundation/nil/nil/services/faucet"
 	"github.com/NilFoundation/nil/nil/services/indexer"
 	"github.com/NilFoundation/nil/nil/services/rollup"
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
 )
 
 type RunMode int
@@ -30,6 +33,8 @@ const (
 	BlockReplayRunMode
 	ArchiveRunMode
 	RpcRunMode
+	ExportChainRunMode
+	ImportChainRunMode
 )
 
 type Config struct {
@@ -42,13 +47,22 @@ type Config struct {
 	SplitShards bool   `yaml:"splitShards,omitempty"`
 
 	// RPC
-	RPCPort        int                   `yaml:"rpcPort,omitempty"`
-	BootstrapPeers network.AddrInfoSlice `yaml:"bootstrapPeers,omitempty"`
-	EnableDevApi   bool                  `yaml:"enableDevApi,omitempty"`
+	RPCPort         int                   `yaml:"rpcPort,omitempty"`
+	BootstrapPeers  network.AddrInfoSlice `yaml:"bootstrapPeers,omitempty"`
+	EnableDevApi    bool                  `yaml:"enableDevApi,omitempty"`
+	EnableWebsocket bool                  `yaml:"enableWebsocket,omitempty"`
+	RPCLimits       httpcfg.LimitsCfg     `yaml:"rpcLimits,omitempty"`
 
 	// Profiling
 	PprofPort int `yaml:"pprofPort,omitempty"`
 
+	// Transaction pool
+	TxnPoolJournalDir   string        `yaml:"txnPoolJournalDir,omitempty"`
+	TxnPoolRejournal    time.Duration `yaml:"txnPoolRejournal,omitempty"`
+	TxnPoolAccountSlots uint64        `yaml:"txnPoolAccountSlots,omitempty"`
+	TxnPoolAccountQueue uint64        `yaml:"txnPoolAccountQueue,omitempty"`
+	TxnPoolLifetime     time.Duration `yaml:"txnPoolLifetime,omitempty"`
+
 	// Admin
 	AdminSocketPath string `yaml:"adminSocket,omitempty"`
 	AllowDbDrop     bool   `yaml:"allowDbDrop,omitempty"`
@@ -81,9 +95,13 @@ type Config struct {
 	Telemetry *telemetry.Config          `yaml:"telemetry,omitempty"`
 	ZeroState *execution.ZeroStateConfig `yaml:"zeroState,omitempty"`
 	Replay    *ReplayConfig              `yaml:"replay,omitempty"`
+	ChainData *ChainDataConfig           `yaml:"chainData,omitempty"`
 	Cometa    *cometa.Config             `yaml:"cometa,omitempty"`
 	Indexer   *indexer.Config            `yaml:"indexer,omitempty"`
 	RpcNode   *RpcNodeConfig             `yaml:"rpcNode,omitempty"`
+	L1        *rollup.L1FetcherConfig    `yaml:"l1,omitempty"`
+	StateSync *collate.StateSyncConfig   `yaml:"stateSync,omitempty"`
+	Faucet    *faucet.Config             `yaml:"faucet,omitempty"`
 
 	L1Fetcher rollup.L1BlockFetcher `yaml:"-"`
 
@@ -113,7 +131,11 @@ func NewDefaultConfig() *Config {
 		Network:   network.NewDefaultConfig(),
 		Telemetry: telemetry.NewDefaultConfig(),
 		Replay:    NewDefaultReplayConfig(),
+		ChainData: NewDefaultChainDataConfig(),
 		RpcNode:   NewDefaultRpcNodeConfig(),
+		L1:        rollup.NewDefaultL1FetcherConfig(),
+		StateSync: collate.NewDefaultStateSyncConfig(),
+		Faucet:    faucet.NewDefaultConfig(),
 		PprofPort: int(DefaultPprofPort),
 	}
 }
@@ -131,6 +153,19 @@ func NewDefaultReplayConfig() *ReplayConfig {
 	}
 }
 
+type ChainDataConfig struct {
+	Path       string            `yaml:"path"`
+	FirstBlock types.BlockNumber `yaml:"firstBlock"`
+	LastBlock  types.BlockNumber `yaml:"lastBlock"`
+	Shards     []uint            `yaml:"shards,omitempty"`
+}
+
+func NewDefaultChainDataConfig() *ChainDataConfig {
+	return &ChainDataConfig{
+		LastBlock: types.InvalidBlockNumber,
+	}
+}
+
 type RpcNodeConfig struct {
 	ArchiveNodeList network.AddrInfoSlice `yaml:"archiveNodeList,omitempty"`
 }
@@ -190,6 +225,21 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.ZeroState != nil {
+		if err := c.ZeroState.ConfigParams.Validators.CheckWeights(); err != nil {
+			return err
+		}
+	}
+	for _, validators := range c.Validators {
+		if err := config.CheckValidatorWeights(validators); err != nil {
+			return err
+		}
+	}
+
+	if err := c.RPCLimits.Validate(); err != nil {
+		return fmt.Errorf("invalid rpc limits: %w", err)
+	}
+
 	return nil
 }
 
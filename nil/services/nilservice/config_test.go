@@ -2,8 +2,11 @@ package nilservice
 
 import (
 	"testing"
+	"time"
 
+	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestValidateDefaultConfig(t *testing.T) {
@@ -39,3 +42,38 @@ func TestValidateInvalidNShards(t *testing.T) {
 	cfg.NShards = 2
 	require.NoError(t, cfg.Validate())
 }
+
+func TestValidateRpcLimits(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewDefaultConfig()
+	cfg.RPCLimits.RateLimit = 10
+	cfg.RPCLimits.RateBurst = 5
+	require.ErrorContains(t, cfg.Validate(), "rate burst 5 is less than the highest method cost")
+
+	cfg.RPCLimits.RateBurst = cfg.RPCLimits.MaxCost()
+	require.NoError(t, cfg.Validate())
+
+	// The burst fits the most expensive method by default.
+	cfg.RPCLimits.RateBurst = 0
+	require.NoError(t, cfg.Validate())
+}
+
+func TestFaucetConfigFromYaml(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewDefaultConfig()
+	err := yaml.Unmarshal([]byte(`
+faucet:
+  dbPath: /var/lib/faucet
+  recipientQuota: "1000"
+  cooldown: 1m
+  clientIpHeader: X-Real-IP
+`), cfg)
+	require.NoError(t, err)
+
+	require.Equal(t, "/var/lib/faucet", cfg.Faucet.DbPath)
+	require.Equal(t, types.NewValueFromUint64(1000), cfg.Faucet.RecipientQuota)
+	require.Equal(t, time.Minute, cfg.Faucet.Cooldown)
+	require.Equal(t, "X-Real-IP", cfg.Faucet.ClientIpHeader)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"sync"
 	"syscall"
 	"time"
@@ -32,6 +33,7 @@ import (
 	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 	"github.com/NilFoundation/nil/nil/services/txnpool"
 	dht "github.com/libp2p/go-libp2p-kad-dht"
@@ -45,6 +47,7 @@ func startRpcServer(
 	cfg *Config,
 	rawApi rawapi.NodeApi,
 	db db.ReadOnlyDB,
+	txnPools map[types.ShardId]txnpool.Pool,
 	client client.Client,
 ) error {
 	logger := logging.NewLogger("RPC").With().
@@ -63,6 +66,8 @@ func startRpcServer(
 		HTTPTimeouts:    httpcfg.DefaultHTTPTimeouts,
 		HttpCORSDomain:  []string{"*"},
 		KeepHeaders:     []string{"Client-Version", "Client-Type", "X-UID"},
+		WSEnabled:       cfg.EnableWebsocket,
+		Limits:          cfg.RPCLimits,
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -70,11 +75,13 @@ func startRpcServer(
 
 	var ethApiService any
 	if cfg.RunMode == NormalRunMode || cfg.RunMode == RpcRunMode {
-		ethImpl := jsonrpc.NewEthAPI(ctx, rawApi, db, pollBlocksForLogs, cfg.LogClientRpcEvents)
+		ethImpl := jsonrpc.NewEthAPI(
+			ctx, rawApi, db, txnPools, cfg.FeeCalculator, pollBlocksForLogs, cfg.LogClientRpcEvents)
 		defer ethImpl.Shutdown()
 		ethApiService = ethImpl
 	} else {
-		ethImpl := jsonrpc.NewEthAPIRo(ctx, rawApi, db, pollBlocksForLogs, cfg.LogClientRpcEvents)
+		ethImpl := jsonrpc.NewEthAPIRo(
+			ctx, rawApi, db, txnPools, cfg.FeeCalculator, pollBlocksForLogs, cfg.LogClientRpcEvents)
 		defer ethImpl.Shutdown()
 		ethApiService = ethImpl
 	}
@@ -153,7 +160,11 @@ func startRpcServer(
 	}
 
 	if cfg.IsFaucetApiEnabled() {
-		f, err := faucet.NewService(client)
+		faucetCfg := cfg.Faucet
+		if faucetCfg == nil {
+			faucetCfg = faucet.NewDefaultConfig()
+		}
+		f, err := faucet.NewService(client, faucetCfg)
 		if err != nil {
 			return fmt.Errorf("failed to create faucet service: %w", err)
 		}
@@ -194,8 +205,14 @@ func getRawApi(
 	networkManager network.Manager,
 	database db.DB,
 	txnPools map[types.ShardId]txnpool.Pool,
+	syncers []*collate.Syncer,
 ) rawapi.NodeApi {
 	nodeApiBuilder := rawapi.NodeApiBuilder(database, networkManager)
+	for shardId, syncer := range syncers {
+		nodeApiBuilder.WithSyncProgress(types.ShardId(shardId), func() rawapitypes.SyncProgress {
+			return getSyncProgress(syncer)
+		})
+	}
 
 	switch cfg.RunMode {
 	case RpcRunMode:
@@ -232,6 +249,21 @@ func getRawApi(
 	return nodeApiBuilder.BuildAndReset()
 }
 
+func getSyncProgress(syncer *collate.Syncer) rawapitypes.SyncProgress {
+	progress := syncer.Progress()
+	peers := make([]string, len(progress.Peers))
+	for i, peer := range progress.Peers {
+		peers[i] = peer.String()
+	}
+	return rawapitypes.SyncProgress{
+		Syncing:       progress.Syncing,
+		StartingBlock: progress.StartingBlock,
+		CurrentBlock:  progress.CurrentBlock,
+		HighestBlock:  progress.HighestBlock,
+		Peers:         peers,
+	}
+}
+
 func validateArchiveNodeConfig(_ *Config, nm network.Manager) error {
 	if nm == nil {
 		return errors.New("failed to start archive node without network configuration")
@@ -262,6 +294,7 @@ func getSyncerConfig(name string, cfg *Config, shardId types.ShardId) *collate.S
 		BootstrapPeers:       cfg.BootstrapPeers,
 		BlockGeneratorParams: cfg.BlockGeneratorParams(shardId),
 		ZeroStateConfig:      cfg.ZeroState,
+		StateSync:            cfg.StateSync,
 	}
 }
 
@@ -327,6 +360,25 @@ func createSyncers(
 			}
 			return
 		}))
+	res.funcs = append(res.funcs, concurrent.MakeTask(
+		"rebuild logs index",
+		func(ctx context.Context) error {
+			if err := res.Wait(); err != nil { // Wait for syncers initialization
+				return err
+			}
+			for i := range cfg.NShards {
+				if err := execution.RebuildLogsIndex(ctx, database, types.ShardId(i), logger); err != nil {
+					if errors.Is(err, context.Canceled) {
+						return nil
+					}
+					logger.Error().
+						Err(err).
+						Stringer(logging.FieldShardId, types.ShardId(i)).
+						Msg("Failed to rebuild logs index")
+				}
+			}
+			return nil
+		}))
 	res.funcs = append(res.funcs, concurrent.MakeTask(
 		"set syncer handlers",
 		func(ctx context.Context) error {
@@ -383,32 +435,32 @@ func runNormalOrCollatorsOnly(
 	database db.DB,
 	networkManager network.Manager,
 	logger logging.Logger,
-) ([]concurrent.Task, map[types.ShardId]txnpool.Pool, error) {
+) ([]concurrent.Task, map[types.ShardId]txnpool.Pool, []*collate.Syncer, error) {
 	if err := cfg.LoadValidatorKeys(); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	if !cfg.SplitShards && len(cfg.ZeroState.GetValidators()) == 0 {
 		if err := initDefaultValidator(cfg); err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 	}
 
 	validators, err := createValidators(ctx, cfg, database, networkManager)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	syncersResult, err := createSyncers("sync", cfg, validators, networkManager, database, logger)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	funcs = append(funcs, syncersResult.funcs...)
 
 	shardFuncs, err := createShards(cfg, validators, syncersResult, database, networkManager, logger)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to create collators")
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	txPools := make(map[types.ShardId]txnpool.Pool)
@@ -421,7 +473,7 @@ func runNormalOrCollatorsOnly(
 	}
 
 	funcs = append(funcs, shardFuncs...)
-	return funcs, txPools, nil
+	return funcs, txPools, syncersResult.syncers, nil
 }
 
 func CreateNode(
@@ -452,7 +504,12 @@ func CreateNode(
 	}
 
 	if cfg.L1Fetcher == nil && (cfg.RunMode == NormalRunMode || cfg.RunMode == CollatorsOnlyRunMode) {
-		cfg.L1Fetcher = rollup.NewL1BlockFetcherRpc(ctx)
+		l1Fetcher, err := rollup.NewL1BlockFetcher(ctx, cfg.L1)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to create L1 block fetcher")
+			return nil, err
+		}
+		cfg.L1Fetcher = l1Fetcher
 	}
 
 	funcs := make([]concurrent.Task, 0, int(cfg.NShards)+2+len(workers))
@@ -486,9 +543,10 @@ func CreateNode(
 
 	var txnPools map[types.ShardId]txnpool.Pool
 	var syncersResult *syncersResult
+	var syncers []*collate.Syncer
 	switch cfg.RunMode {
 	case NormalRunMode, CollatorsOnlyRunMode:
-		funcs, txnPools, err = runNormalOrCollatorsOnly(ctx, funcs, cfg, database, networkManager, logger)
+		funcs, txnPools, syncers, err = runNormalOrCollatorsOnly(ctx, funcs, cfg, database, networkManager, logger)
 		if err != nil {
 			return nil, err
 		}
@@ -506,6 +564,7 @@ func CreateNode(
 			return nil, err
 		}
 		funcs = append(funcs, syncersResult.funcs...)
+		syncers = syncersResult.syncers
 	case BlockReplayRunMode:
 		replayer := collate.NewReplayScheduler(database, collate.ReplayParams{
 			BlockGeneratorParams: cfg.BlockGeneratorParams(cfg.Replay.ShardId),
@@ -556,8 +615,8 @@ func CreateNode(
 			return nil
 		}))
 
-	rawApi := getRawApi(cfg, networkManager, database, txnPools)
-	funcs = addRpcServerWorkerIfEnabled(funcs, cfg, rawApi, syncersResult, database, logger)
+	rawApi := getRawApi(cfg, networkManager, database, txnPools, syncers)
+	funcs = addRpcServerWorkerIfEnabled(funcs, cfg, rawApi, syncersResult, database, txnPools, logger)
 
 	if cfg.RunMode != CollatorsOnlyRunMode && cfg.RunMode != RpcRunMode {
 		if err := rawApi.SetP2pRequestHandlers(ctx, networkManager, logger); err != nil {
@@ -585,6 +644,7 @@ func addRpcServerWorkerIfEnabled(
 	rawApi rawapi.NodeApi,
 	syncersResult *syncersResult,
 	database db.DB,
+	txnPools map[types.ShardId]txnpool.Pool,
 	logger logging.Logger,
 ) []concurrent.Task {
 	if (cfg.RPCPort == 0 && cfg.HttpUrl == "") || rawApi == nil {
@@ -608,7 +668,7 @@ func addRpcServerWorkerIfEnabled(
 					return fmt.Errorf("failed to create node client: %w", err)
 				}
 			}
-			if err := startRpcServer(ctx, cfg, rawApi, database, cl); err != nil {
+			if err := startRpcServer(ctx, cfg, rawApi, database, txnPools, cl); err != nil {
 				logger.Error().Err(err).Msg("RPC server goroutine failed")
 				return err
 			}
@@ -638,6 +698,10 @@ func Run(
 
 	logging.ApplyComponentsFilterEnv()
 
+	if cfg.RunMode == ExportChainRunMode || cfg.RunMode == ImportChainRunMode {
+		return runChainDataCommand(ctx, cfg, database)
+	}
+
 	node, err := CreateNode(ctx, "nil", cfg, database, interop, workers...)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create node: %s", err.Error())
@@ -699,7 +763,7 @@ func createValidators(
 		var err error
 		var txpool *txnpool.TxnPool
 		if cfg.IsShardActive(shardId) {
-			txpool, err = txnpool.New(ctx, txnpool.NewConfig(shardId), networkManager)
+			txpool, err = txnpool.New(ctx, createTxnPoolConfig(shardId, cfg, database), networkManager)
 			if err != nil {
 				return nil, err
 			}
@@ -713,6 +777,27 @@ func createValidators(
 	return list, nil
 }
 
+func createTxnPoolConfig(shardId types.ShardId, cfg *Config, database db.DB) txnpool.Config {
+	poolCfg := txnpool.NewConfig(shardId)
+	poolCfg.State = txnpool.NewDbStateReader(database, shardId)
+	if cfg.TxnPoolJournalDir != "" {
+		poolCfg.Journal = filepath.Join(cfg.TxnPoolJournalDir, fmt.Sprintf("txnpool-%d.journal", shardId))
+	}
+	if cfg.TxnPoolRejournal != 0 {
+		poolCfg.Rejournal = cfg.TxnPoolRejournal
+	}
+	if cfg.TxnPoolAccountSlots != 0 {
+		poolCfg.AccountSlots = cfg.TxnPoolAccountSlots
+	}
+	if cfg.TxnPoolAccountQueue != 0 {
+		poolCfg.AccountQueue = cfg.TxnPoolAccountQueue
+	}
+	if cfg.TxnPoolLifetime != 0 {
+		poolCfg.Lifetime = cfg.TxnPoolLifetime
+	}
+	return poolCfg
+}
+
 func createShards(
 	cfg *Config,
 	validators []*collate.Validator,
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/relayer/internal/l2"
+	"github.com/NilFoundation/nil/nil/services/relayer/internal/status"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// AdminApi allows the operators to resolve the dead-letter events.
+// It is served only on the admin endpoint.
+type AdminApi interface {
+	// RequeueEvent returns the dead-letter event to the queue of the events to be sent to L2
+	RequeueEvent(ctx context.Context, hash ethcommon.Hash) (*status.EventStatus, error)
+
+	// DropEvent removes the dead-letter event, it is never relayed then
+	DropEvent(ctx context.Context, hash ethcommon.Hash) (*status.EventStatus, error)
+}
+
+type adminApi struct {
+	statusStorage *status.Storage
+	l2Storage     *l2.EventStorage
+	logger        logging.Logger
+}
+
+var _ AdminApi = (*adminApi)(nil)
+
+func NewAdminApi(statusStorage *status.Storage, l2Storage *l2.EventStorage, logger logging.Logger) AdminApi {
+	return &adminApi{
+		statusStorage: statusStorage,
+		l2Storage:     l2Storage,
+		logger:        logger,
+	}
+}
+
+func (a *adminApi) RequeueEvent(ctx context.Context, hash ethcommon.Hash) (*status.EventStatus, error) {
+	evt, err := a.l2Storage.RequeueDeadLetterEvent(ctx, hash)
+	if err != nil {
+		if errors.Is(err, l2.ErrEventNotFound) {
+			// events which failed validation on L1 never reach the L2 queues
+			return nil, fmt.Errorf("%w: only events failed on L2 can be requeued", err)
+		}
+		return nil, err
+	}
+
+	a.logger.Info().Stringer("event_hash", hash).Msg("dead-letter event requeued by operator")
+
+	upd := status.Update{Stage: status.StageFinalized, ResetAttempts: true}
+	if err := a.statusStorage.Record(ctx, upd, evt.Hash); err != nil {
+		return nil, err
+	}
+	return a.statusStorage.Get(ctx, hash)
+}
+
+func (a *adminApi) DropEvent(ctx context.Context, hash ethcommon.Hash) (*status.EventStatus, error) {
+	if err := a.l2Storage.DropDeadLetterEvent(ctx, hash); err != nil {
+		return nil, err
+	}
+
+	a.logger.Info().Stringer("event_hash", hash).Msg("dead-letter event dropped by operator")
+
+	if err := a.statusStorage.Record(ctx, status.Update{Stage: status.StageDropped}, hash); err != nil {
+		return nil, err
+	}
+	return a.statusStorage.Get(ctx, hash)
+}
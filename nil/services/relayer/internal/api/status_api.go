@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/services/relayer/internal/status"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	StatusNamespace = "relayer"
+
+	DefaultListLimit = 100
+	MaxListLimit     = 1000
+)
+
+// StatusApi exposes the lifecycle of the deposit events to the operators
+type StatusApi interface {
+	// GetEventStatus returns the status of the event with the given hash
+	GetEventStatus(ctx context.Context, hash ethcommon.Hash) (*status.EventStatus, error)
+
+	// ListEvents returns the statuses of the events in the given stage (any stage if empty)
+	ListEvents(ctx context.Context, stage status.Stage, limit int) ([]*status.EventStatus, error)
+
+	// ListDeadLetterEvents returns the statuses of the events which require operator attention
+	ListDeadLetterEvents(ctx context.Context, limit int) ([]*status.EventStatus, error)
+
+	// GetSummary returns the number of the events in each stage
+	GetSummary(ctx context.Context) (map[status.Stage]int, error)
+}
+
+type statusApi struct {
+	statusStorage *status.Storage
+}
+
+var _ StatusApi = (*statusApi)(nil)
+
+func NewStatusApi(statusStorage *status.Storage) StatusApi {
+	return &statusApi{
+		statusStorage: statusStorage,
+	}
+}
+
+func (a *statusApi) GetEventStatus(ctx context.Context, hash ethcommon.Hash) (*status.EventStatus, error) {
+	return a.statusStorage.Get(ctx, hash)
+}
+
+func (a *statusApi) ListEvents(ctx context.Context, stage status.Stage, limit int) ([]*status.EventStatus, error) {
+	limit, err := checkLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	if stage == "" {
+		return a.statusStorage.List(ctx, limit)
+	}
+	return a.statusStorage.List(ctx, limit, stage)
+}
+
+func (a *statusApi) ListDeadLetterEvents(ctx context.Context, limit int) ([]*status.EventStatus, error) {
+	return a.ListEvents(ctx, status.StageDeadLetter, limit)
+}
+
+func (a *statusApi) GetSummary(ctx context.Context) (map[status.Stage]int, error) {
+	return a.statusStorage.CountByStage(ctx)
+}
+
+func checkLimit(limit int) (int, error) {
+	switch {
+	case limit == 0:
+		return DefaultListLimit, nil
+	case limit < 0 || limit > MaxListLimit:
+		return 0, fmt.Errorf("limit must be in range [1, %d], got %d", MaxListLimit, limit)
+	default:
+		return limit, nil
+	}
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/services/relayer/internal/l2"
+	"github.com/NilFoundation/nil/nil/services/relayer/internal/status"
+	"github.com/NilFoundation/nil/nil/services/relayer/internal/storage"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/suite"
+)
+
+type StatusApiTestSuite struct {
+	suite.Suite
+
+	ctx           context.Context
+	database      db.DB
+	l2Storage     *l2.EventStorage
+	statusStorage *status.Storage
+
+	clock    *clockwork.FakeClock
+	api      StatusApi
+	adminApi AdminApi
+}
+
+func TestStatusApi(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(StatusApiTestSuite))
+}
+
+func (s *StatusApiTestSuite) SetupTest() {
+	var err error
+
+	s.ctx = context.Background()
+	logger := logging.NewLogger("status_api_test")
+
+	s.database, err = db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+
+	storageMetrics, err := storage.NewTableMetrics()
+	s.Require().NoError(err)
+
+	s.clock = clockwork.NewFakeClock()
+	s.l2Storage = l2.NewEventStorage(s.ctx, s.database, s.clock, storageMetrics, logger)
+	s.statusStorage = status.NewStorage(s.ctx, s.database, s.clock, storageMetrics, logger)
+	s.api = NewStatusApi(s.statusStorage)
+	s.adminApi = NewAdminApi(s.statusStorage, s.l2Storage, logger)
+}
+
+func (s *StatusApiTestSuite) TearDownTest() {
+	s.database.Close()
+}
+
+// addDeadLetterEvent puts the event to the dead-letter queue as the transaction sender does
+func (s *StatusApiTestSuite) addDeadLetterEvent(seqNo uint64) ethcommon.Hash {
+	s.T().Helper()
+
+	evt := &l2.Event{
+		Hash:           ethcommon.BigToHash(new(big.Int).SetUint64(seqNo)),
+		SequenceNumber: seqNo,
+		RelayAttempts:  5,
+	}
+	s.Require().NoError(s.l2Storage.StoreEvents(s.ctx, []*l2.Event{evt}))
+	s.Require().NoError(s.l2Storage.DeadLetterPendingEvent(s.ctx, evt))
+
+	upd := status.Update{Stage: status.StageDeadLetter, L1BlockNumber: seqNo, RelayAttempts: evt.RelayAttempts}
+	s.Require().NoError(s.statusStorage.Record(s.ctx, upd, evt.Hash))
+	return evt.Hash
+}
+
+func (s *StatusApiTestSuite) TestRequeueAndDrop() {
+	requeued := s.addDeadLetterEvent(1)
+	dropped := s.addDeadLetterEvent(2)
+
+	deadLetter, err := s.api.ListDeadLetterEvents(s.ctx, 0)
+	s.Require().NoError(err)
+	s.Require().Len(deadLetter, 2)
+
+	evtStatus, err := s.adminApi.RequeueEvent(s.ctx, requeued)
+	s.Require().NoError(err)
+	s.Require().Equal(status.StageFinalized, evtStatus.Stage)
+	s.Require().Zero(evtStatus.RelayAttempts)
+	s.Require().EqualValues(1, evtStatus.L1BlockNumber)
+
+	var pending []*l2.Event
+	s.Require().NoError(s.l2Storage.IterateEventsByBatch(s.ctx, 100, func(events []*l2.Event) error {
+		pending = append(pending, events...)
+		return nil
+	}))
+	s.Require().Len(pending, 1)
+	s.Require().Equal(requeued, pending[0].Hash)
+
+	evtStatus, err = s.adminApi.DropEvent(s.ctx, dropped)
+	s.Require().NoError(err)
+	s.Require().Equal(status.StageDropped, evtStatus.Stage)
+
+	// neither of the events is in the dead-letter queue anymore
+	_, err = s.adminApi.RequeueEvent(s.ctx, requeued)
+	s.Require().ErrorIs(err, l2.ErrEventNotFound)
+	_, err = s.adminApi.DropEvent(s.ctx, dropped)
+	s.Require().ErrorIs(err, l2.ErrEventNotFound)
+
+	summary, err := s.api.GetSummary(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(map[status.Stage]int{status.StageFinalized: 1, status.StageDropped: 1}, summary)
+}
+
+func (s *StatusApiTestSuite) TestGetAndList() {
+	hash := s.addDeadLetterEvent(1)
+	upd := status.Update{Stage: status.StageSeenOnL1}
+	s.Require().NoError(s.statusStorage.Record(s.ctx, upd, ethcommon.HexToHash("0x2")))
+
+	evtStatus, err := s.api.GetEventStatus(s.ctx, hash)
+	s.Require().NoError(err)
+	s.Require().Equal(status.StageDeadLetter, evtStatus.Stage)
+	s.Require().Len(evtStatus.History, 1)
+
+	_, err = s.api.GetEventStatus(s.ctx, ethcommon.HexToHash("0x3"))
+	s.Require().ErrorIs(err, status.ErrStatusNotFound)
+
+	all, err := s.api.ListEvents(s.ctx, "", 0)
+	s.Require().NoError(err)
+	s.Require().Len(all, 2)
+
+	seen, err := s.api.ListEvents(s.ctx, status.StageSeenOnL1, 10)
+	s.Require().NoError(err)
+	s.Require().Len(seen, 1)
+
+	_, err = s.api.ListEvents(s.ctx, "", MaxListLimit+1)
+	s.Require().Error(err)
+}
+
+func (s *StatusApiTestSuite) TestPrune() {
+	dropped := s.addDeadLetterEvent(1)
+	_, err := s.adminApi.DropEvent(s.ctx, dropped)
+	s.Require().NoError(err)
+	deadLetter := s.addDeadLetterEvent(2)
+
+	s.clock.Advance(time.Hour)
+	executed := ethcommon.HexToHash("0x3")
+	s.Require().NoError(s.statusStorage.Record(s.ctx, status.Update{Stage: status.StageExecutedOnL2}, executed))
+
+	// only the finished events not updated within the retention period are removed
+	pruned, err := s.statusStorage.Prune(s.ctx, s.clock.Now().Add(-time.Minute))
+	s.Require().NoError(err)
+	s.Require().Equal(1, pruned)
+
+	_, err = s.api.GetEventStatus(s.ctx, dropped)
+	s.Require().ErrorIs(err, status.ErrStatusNotFound)
+	_, err = s.api.GetEventStatus(s.ctx, deadLetter)
+	s.Require().NoError(err)
+	_, err = s.api.GetEventStatus(s.ctx, executed)
+	s.Require().NoError(err)
+}
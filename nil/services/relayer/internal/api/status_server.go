@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/rpc"
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"golang.org/x/sync/errgroup"
+)
+
+type StatusServerConfig struct {
+	HttpEndpoint string
+
+	// AdminEndpoint serves AdminApi, it must be reachable by the operators only (e.g. a unix socket).
+	// The admin methods are disabled if it is empty.
+	AdminEndpoint string
+}
+
+// StatusServer serves StatusApi and AdminApi over JSON-RPC on separate endpoints
+type StatusServer struct {
+	config   *StatusServerConfig
+	api      StatusApi
+	adminApi AdminApi
+	logger   logging.Logger
+}
+
+func NewStatusServer(
+	config *StatusServerConfig,
+	api StatusApi,
+	adminApi AdminApi,
+	logger logging.Logger,
+) *StatusServer {
+	srv := &StatusServer{
+		config:   config,
+		api:      api,
+		adminApi: adminApi,
+	}
+	srv.logger = logger.With().Str(logging.FieldComponent, srv.Name()).Logger()
+	return srv
+}
+
+func (*StatusServer) Name() string {
+	return "status-server"
+}
+
+func (s *StatusServer) Run(ctx context.Context, started chan<- struct{}) error {
+	eg, gCtx := errgroup.WithContext(ctx)
+
+	var statusStarted, adminStarted chan struct{}
+	if s.config.HttpEndpoint != "" {
+		statusStarted = make(chan struct{})
+		eg.Go(func() error {
+			s.logger.Info().Msgf("Open relayer status endpoint %v", s.config.HttpEndpoint)
+			return s.serve(gCtx, s.config.HttpEndpoint, s.api, statusStarted)
+		})
+	}
+	if s.config.AdminEndpoint != "" {
+		adminStarted = make(chan struct{})
+		eg.Go(func() error {
+			s.logger.Info().Msgf("Open relayer admin endpoint %v", s.config.AdminEndpoint)
+			return s.serve(gCtx, s.config.AdminEndpoint, s.adminApi, adminStarted)
+		})
+	}
+
+	eg.Go(func() error {
+		for _, ch := range []chan struct{}{statusStarted, adminStarted} {
+			if ch == nil {
+				continue
+			}
+			select {
+			case <-ch:
+			case <-gCtx.Done():
+				return nil
+			}
+		}
+		close(started)
+		return nil
+	})
+
+	return eg.Wait()
+}
+
+func (s *StatusServer) serve(ctx context.Context, endpoint string, service any, started chan<- struct{}) error {
+	httpConfig := &httpcfg.HttpCfg{
+		HttpURL:         endpoint,
+		HttpCompression: true,
+		TraceRequests:   true,
+		HTTPTimeouts:    httpcfg.DefaultHTTPTimeouts,
+	}
+
+	apiList := []transport.API{
+		{
+			Namespace: StatusNamespace,
+			Public:    true,
+			Service:   service,
+			Version:   "1.0",
+		},
+	}
+	return rpc.StartRpcServer(ctx, httpConfig, apiList, s.logger, started)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/relayer/internal/status"
 	"github.com/NilFoundation/nil/nil/services/relayer/internal/storage"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/jonboulle/clockwork"
@@ -50,9 +51,10 @@ type EventListener struct {
 	contractBinding L1Contract
 	clock           clockwork.Clock
 
-	config       *EventListenerConfig
-	metrics      EventListenerMetrics
-	eventStorage *EventStorage
+	config        *EventListenerConfig
+	metrics       EventListenerMetrics
+	eventStorage  *EventStorage
+	statusStorage *status.Storage
 
 	state struct {
 		emitter chan struct{} // signals when new event is put to storage
@@ -71,6 +73,7 @@ func NewEventListener(
 	ethClient EthClient,
 	contractClient L1Contract,
 	storage *EventStorage,
+	statusStorage *status.Storage,
 	metrics EventListenerMetrics,
 	logger logging.Logger,
 ) (*EventListener, error) {
@@ -80,6 +83,7 @@ func NewEventListener(
 		clock:           clock,
 		config:          config,
 		eventStorage:    storage,
+		statusStorage:   statusStorage,
 		metrics:         metrics,
 	}
 
@@ -317,13 +321,21 @@ func (el *EventListener) processEvent(ctx context.Context, ethEvent *L1MessageSe
 	event := el.convertEvent(ethEvent)
 
 	if err := event.validate(); err != nil {
-		return err
-	}
-
-	// all retryable errors should be handled inside storage, otherwise we should interrupt service work
-	err := el.eventStorage.StoreEvent(ctx, event)
-	if err := ignoreErrors(err, storage.ErrKeyExists); err != nil {
-		return err
+		// invalid event can't be relayed, it is left for the operator instead of breaking the listener
+		el.logger.Error().Err(err).
+			Stringer("event_hash", event.Hash).
+			Uint64("block_number", event.BlockNumber).
+			Msg("invalid event moved to dead-letter queue")
+		el.recordStatus(ctx, event, status.Update{Stage: status.StageDeadLetter, Error: err})
+	} else {
+		// all retryable errors should be handled inside storage, otherwise we should interrupt service work
+		err := el.eventStorage.StoreEvent(ctx, event)
+		if err := ignoreErrors(err, storage.ErrKeyExists); err != nil {
+			return err
+		}
+		if err == nil {
+			el.recordStatus(ctx, event, status.Update{Stage: status.StageSeenOnL1})
+		}
 	}
 
 	if el.state.currentBlockNumber != ethEvent.Raw.BlockNumber {
@@ -346,6 +358,14 @@ func (el *EventListener) processEvent(ctx context.Context, ethEvent *L1MessageSe
 	return nil
 }
 
+// recordStatus saves the event status, failures are not critical for relaying
+func (el *EventListener) recordStatus(ctx context.Context, event *Event, upd status.Update) {
+	upd.L1BlockNumber = event.BlockNumber
+	if err := el.statusStorage.Record(ctx, upd, event.Hash); err != nil {
+		el.logger.Warn().Err(err).Stringer("event_hash", event.Hash).Msg("failed to record event status")
+	}
+}
+
 func (el *EventListener) convertEvent(ethEvent *L1MessageSent) *Event {
 	event := &Event{
 		Hash:        ethEvent.MessageHash,
@@ -8,6 +8,7 @@ import (
 
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/services/relayer/internal/status"
 	"github.com/NilFoundation/nil/nil/services/relayer/internal/storage"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
@@ -82,6 +83,7 @@ func (s *EventListenerTestSuite) SetupTest() {
 		s.ethClientMock,
 		s.l1ContractMock,
 		s.storage,
+		status.NewStorage(s.ctx, s.database, s.clock, s.storageMetrics, s.logger),
 		s.listenerMetrics,
 		s.logger,
 	)
@@ -15,6 +15,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/relayer/internal/l2"
+	"github.com/NilFoundation/nil/nil/services/relayer/internal/status"
 	"github.com/NilFoundation/nil/nil/services/relayer/internal/storage"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -76,6 +77,7 @@ type FinalityEnsurer struct {
 	clock         clockwork.Clock
 	l1Storage     *EventStorage
 	l2Storage     *l2.EventStorage
+	statusStorage *status.Storage
 	metrics       FinalityEnsurerMetrics
 	eventProvider eventProvider
 
@@ -89,6 +91,7 @@ func NewFinalityEnsurer(
 	logger logging.Logger,
 	l1Storage *EventStorage,
 	l2Storage *l2.EventStorage,
+	statusStorage *status.Storage,
 	metrics FinalityEnsurerMetrics,
 	eventProvider eventProvider,
 ) (*FinalityEnsurer, error) {
@@ -104,6 +107,7 @@ func NewFinalityEnsurer(
 		clock:         clock,
 		l1Storage:     l1Storage,
 		l2Storage:     l2Storage,
+		statusStorage: statusStorage,
 		eventProvider: eventProvider,
 		metrics:       metrics,
 		emitter:       make(chan struct{}, config.EventEmitterCapacity),
@@ -315,6 +319,9 @@ func (fe *FinalityEnsurer) forwardFinalizedEvents(ctx context.Context) error {
 		}
 	}
 
+	fe.recordStatus(ctx, finalized, eventByBlock, status.StageFinalized)
+	fe.recordStatus(ctx, orphaned, eventByBlock, status.StageOrphaned)
+
 	fe.metrics.AddFinalizedEvents(ctx, uint64(finalizedEventCount))
 	fe.metrics.AddOrphanedEvents(ctx, uint64(orphanedEventCount))
 
@@ -334,6 +341,28 @@ func (fe *FinalityEnsurer) forwardFinalizedEvents(ctx context.Context) error {
 	return nil
 }
 
+// recordStatus saves the stage of the events of the given blocks, failures are not critical for relaying
+func (fe *FinalityEnsurer) recordStatus(
+	ctx context.Context,
+	blocks []ProcessedBlock,
+	eventByBlock map[ProcessedBlock][]*Event,
+	stage status.Stage,
+) {
+	for _, blk := range blocks {
+		hashes := make([]ethcommon.Hash, 0, len(eventByBlock[blk]))
+		for _, evt := range eventByBlock[blk] {
+			hashes = append(hashes, evt.Hash)
+		}
+		upd := status.Update{Stage: stage, L1BlockNumber: blk.BlockNumber}
+		if err := fe.statusStorage.Record(ctx, upd, hashes...); err != nil {
+			fe.logger.Warn().Err(err).
+				Uint64("block_number", blk.BlockNumber).
+				Str("stage", string(stage)).
+				Msg("failed to record event status")
+		}
+	}
+}
+
 func (fe *FinalityEnsurer) checkBlocksFinality(
 	ctx context.Context,
 	base *ProcessedBlock,
@@ -10,6 +10,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/services/relayer/internal/l2"
+	"github.com/NilFoundation/nil/nil/services/relayer/internal/status"
 	"github.com/NilFoundation/nil/nil/services/relayer/internal/storage"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
@@ -55,6 +56,7 @@ type FinalityEnsurerTestSuite struct {
 	database       db.DB
 	l1Storage      *EventStorage
 	l2Storage      *l2.EventStorage
+	statusStorage  *status.Storage
 	storageMetrics storage.TableMetrics
 	logger         logging.Logger
 
@@ -114,6 +116,7 @@ func (s *FinalityEnsurerTestSuite) SetupTest() {
 	s.Require().NoError(err, "failed to initialize L1 storage")
 
 	s.l2Storage = l2.NewEventStorage(s.ctx, s.database, s.clockMock, s.storageMetrics, s.logger)
+	s.statusStorage = status.NewStorage(s.ctx, s.database, s.clockMock, s.storageMetrics, s.logger)
 
 	cfg := DefaultFinalityEnsurerConfig()
 	cfg.EventEmitterCapacity = 100
@@ -130,6 +133,7 @@ func (s *FinalityEnsurerTestSuite) SetupTest() {
 		s.logger,
 		s.l1Storage,
 		s.l2Storage,
+		s.statusStorage,
 		s.ensurerMetrics,
 		s.eventListenerStub,
 	)
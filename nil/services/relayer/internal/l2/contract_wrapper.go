@@ -11,6 +11,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/abi"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -49,8 +50,14 @@ func (cfg *ContractConfig) Validate() error {
 	return nil
 }
 
+// ErrInvalidRelayMessage is returned if the event can't be turned into a relay transaction,
+// sending it again won't help
+var ErrInvalidRelayMessage = errors.New("invalid relay message")
+
 type L2Contract interface {
 	RelayMessage(ctx context.Context, event *Event) (common.Hash, error)
+	// GetReceipt returns the receipt of the relay transaction, nil is returned if it is not processed yet
+	GetReceipt(ctx context.Context, txHash common.Hash) (*jsonrpc.RPCReceipt, error)
 }
 
 type l2ContractWrapper struct {
@@ -124,7 +131,7 @@ func (w *l2ContractWrapper) RelayMessage(
 		evt.ExpiryTime,
 	)
 	if err != nil {
-		return common.EmptyHash, err
+		return common.EmptyHash, fmt.Errorf("%w: %w", ErrInvalidRelayMessage, err)
 	}
 
 	w.logger.Trace().Stringer("event_hash", evt.Hash).Msg("relaying event")
@@ -142,3 +149,7 @@ func (w *l2ContractWrapper) RelayMessage(
 		false,
 	)
 }
+
+func (w *l2ContractWrapper) GetReceipt(ctx context.Context, txHash common.Hash) (*jsonrpc.RPCReceipt, error) {
+	return w.nilClient.GetInTransactionReceipt(ctx, txHash)
+}
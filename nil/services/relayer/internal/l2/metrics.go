@@ -11,13 +11,15 @@ import (
 type TransactionSenderMetrics interface {
 	AddRelayedEvents(ctx context.Context, count uint64)
 	AddRelayError(ctx context.Context)
+	AddDeadLetterEvents(ctx context.Context, count uint64)
 }
 
 type transactionSenderMetrics struct {
 	attrs metric.MeasurementOption
 
-	relayErrors   telemetry.Counter
-	relayedEvents telemetry.Counter
+	relayErrors      telemetry.Counter
+	relayedEvents    telemetry.Counter
+	deadLetterEvents telemetry.Counter
 }
 
 func NewTransactionSenderMetrics() (TransactionSenderMetrics, error) {
@@ -41,6 +43,11 @@ func (tsm *transactionSenderMetrics) Init(name string, meter telemetry.Meter, at
 		return err
 	}
 
+	tsm.deadLetterEvents, err = meter.Int64Counter(name + ".dead_letter_events")
+	if err != nil {
+		return err
+	}
+
 	tsm.attrs = attrs
 	return nil
 }
@@ -52,3 +59,7 @@ func (tsm *transactionSenderMetrics) AddRelayError(ctx context.Context) {
 func (tsm *transactionSenderMetrics) AddRelayedEvents(ctx context.Context, count uint64) {
 	tsm.relayedEvents.Add(ctx, int64(count), tsm.attrs)
 }
+
+func (tsm *transactionSenderMetrics) AddDeadLetterEvents(ctx context.Context, count uint64) {
+	tsm.deadLetterEvents.Add(ctx, int64(count), tsm.attrs)
+}
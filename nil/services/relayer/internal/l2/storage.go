@@ -17,8 +17,19 @@ const (
 	// pendingEventsTable stores events that are finalized on L1 and ready to be forwarded to L2
 	// Key: Hash of the Event
 	pendingEventsTable = "pending_l2_events"
+
+	// sentEventsTable stores events relayed to L2 waiting for the relay transaction receipt
+	// Key: Hash of the Event
+	sentEventsTable = "sent_l2_events"
+
+	// deadLetterEventsTable stores events which failed to be relayed too many times,
+	// they are kept until requeued or dropped by an operator
+	// Key: Hash of the Event
+	deadLetterEventsTable = "dead_letter_l2_events"
 )
 
+var ErrEventNotFound = errors.New("event not found")
+
 type EventStorage struct {
 	*storage.BaseStorage
 }
@@ -64,6 +75,31 @@ func (es *EventStorage) IterateEventsByBatch(
 	ctx context.Context,
 	batchSize int,
 	callback func([]*Event) error,
+) error {
+	return es.iterateEventsByBatch(ctx, pendingEventsTable, batchSize, callback)
+}
+
+func (es *EventStorage) IterateSentEventsByBatch(
+	ctx context.Context,
+	batchSize int,
+	callback func([]*Event) error,
+) error {
+	return es.iterateEventsByBatch(ctx, sentEventsTable, batchSize, callback)
+}
+
+func (es *EventStorage) IterateDeadLetterEventsByBatch(
+	ctx context.Context,
+	batchSize int,
+	callback func([]*Event) error,
+) error {
+	return es.iterateEventsByBatch(ctx, deadLetterEventsTable, batchSize, callback)
+}
+
+func (es *EventStorage) iterateEventsByBatch(
+	ctx context.Context,
+	table db.TableName,
+	batchSize int,
+	callback func([]*Event) error,
 ) error {
 	return es.RetryRunner.Do(ctx, func(ctx context.Context) error {
 		tx, err := es.Database.CreateRoTx(ctx)
@@ -72,7 +108,7 @@ func (es *EventStorage) IterateEventsByBatch(
 		}
 		defer tx.Rollback()
 
-		iter, err := tx.Range(pendingEventsTable, nil, nil)
+		iter, err := tx.Range(table, nil, nil)
 		if err != nil {
 			return err
 		}
@@ -86,6 +122,7 @@ func (es *EventStorage) IterateEventsByBatch(
 			if err != nil {
 				return err
 			}
+			batch[idx] = nil
 			if err := json.Unmarshal(val, &batch[idx]); err != nil {
 				return fmt.Errorf("%w: %w", storage.ErrSerializationFailed, err)
 			}
@@ -103,13 +140,17 @@ func (es *EventStorage) IterateEventsByBatch(
 			return callback(batch[:idx])
 		}
 
-		es.Metrics.SetTableSize(ctx, pendingEventsTable, count)
+		es.Metrics.SetTableSize(ctx, table, count)
 
 		return nil
 	})
 }
 
 func (es *EventStorage) DeleteEvents(ctx context.Context, hashes []ethcommon.Hash) error {
+	return es.deleteEvents(ctx, pendingEventsTable, hashes)
+}
+
+func (es *EventStorage) deleteEvents(ctx context.Context, table db.TableName, hashes []ethcommon.Hash) error {
 	return es.RetryRunner.Do(ctx, func(ctx context.Context) error {
 		tx, err := es.Database.CreateRwTx(ctx)
 		if err != nil {
@@ -118,13 +159,132 @@ func (es *EventStorage) DeleteEvents(ctx context.Context, hashes []ethcommon.Has
 		defer tx.Rollback()
 
 		for _, hash := range hashes {
-			if err := tx.Delete(pendingEventsTable, hash.Bytes()); err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+			if err := tx.Delete(table, hash.Bytes()); err != nil && !errors.Is(err, db.ErrKeyNotFound) {
 				return err
 			}
 		}
 
 		return es.Commit(tx, func() {
-			es.Metrics.RecordDeletes(ctx, pendingEventsTable, len(hashes))
+			es.Metrics.RecordDeletes(ctx, table, len(hashes))
+		})
+	})
+}
+
+// UpdateEvent overwrites the relaying state of the pending event
+func (es *EventStorage) UpdateEvent(ctx context.Context, evt *Event) error {
+	return es.RetryRunner.Do(ctx, func(ctx context.Context) error {
+		writer := storage.NewJSONWriter[*Event](pendingEventsTable, es.BaseStorage, true)
+		return writer.PutTx(ctx, evt.Hash.Bytes(), evt)
+	})
+}
+
+// MarkEventSent moves the pending event to the events waiting for the relay transaction receipt
+func (es *EventStorage) MarkEventSent(ctx context.Context, evt *Event) error {
+	return es.moveEvent(ctx, evt, pendingEventsTable, sentEventsTable)
+}
+
+// RequeueSentEvent moves the sent event back to the pending ones to be relayed again
+func (es *EventStorage) RequeueSentEvent(ctx context.Context, evt *Event) error {
+	return es.moveEvent(ctx, evt, sentEventsTable, pendingEventsTable)
+}
+
+func (es *EventStorage) DeleteSentEvents(ctx context.Context, hashes []ethcommon.Hash) error {
+	return es.deleteEvents(ctx, sentEventsTable, hashes)
+}
+
+// DeadLetterPendingEvent moves the pending event to the dead-letter queue
+func (es *EventStorage) DeadLetterPendingEvent(ctx context.Context, evt *Event) error {
+	return es.moveEvent(ctx, evt, pendingEventsTable, deadLetterEventsTable)
+}
+
+// DeadLetterSentEvent moves the sent event to the dead-letter queue
+func (es *EventStorage) DeadLetterSentEvent(ctx context.Context, evt *Event) error {
+	return es.moveEvent(ctx, evt, sentEventsTable, deadLetterEventsTable)
+}
+
+// RequeueDeadLetterEvent moves the event from the dead-letter queue back to the pending ones
+// and resets its relay attempts
+func (es *EventStorage) RequeueDeadLetterEvent(ctx context.Context, hash ethcommon.Hash) (*Event, error) {
+	var evt *Event
+	err := es.RetryRunner.Do(ctx, func(ctx context.Context) error {
+		tx, err := es.Database.CreateRoTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		data, err := tx.Get(deadLetterEventsTable, hash.Bytes())
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return fmt.Errorf("%w: %s", ErrEventNotFound, hash)
+		}
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &evt); err != nil {
+			return fmt.Errorf("%w: %w", storage.ErrSerializationFailed, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	evt.RelayAttempts = 0
+	if err := es.moveEvent(ctx, evt, deadLetterEventsTable, pendingEventsTable); err != nil {
+		return nil, err
+	}
+	return evt, nil
+}
+
+// DropDeadLetterEvent removes the event from the dead-letter queue
+func (es *EventStorage) DropDeadLetterEvent(ctx context.Context, hash ethcommon.Hash) error {
+	return es.RetryRunner.Do(ctx, func(ctx context.Context) error {
+		tx, err := es.Database.CreateRwTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		exists, err := tx.Exists(deadLetterEventsTable, hash.Bytes())
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: %s", ErrEventNotFound, hash)
+		}
+		if err := tx.Delete(deadLetterEventsTable, hash.Bytes()); err != nil {
+			return err
+		}
+
+		return es.Commit(tx, func() {
+			es.Metrics.RecordDeletes(ctx, deadLetterEventsTable, 1)
+		})
+	})
+}
+
+func (es *EventStorage) moveEvent(ctx context.Context, evt *Event, from, to db.TableName) error {
+	return es.RetryRunner.Do(ctx, func(ctx context.Context) error {
+		data, err := json.Marshal(evt)
+		if err != nil {
+			return fmt.Errorf("%w: %w", storage.ErrSerializationFailed, err)
+		}
+
+		tx, err := es.Database.CreateRwTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := tx.Delete(from, evt.Hash.Bytes()); err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+			return err
+		}
+		if err := tx.Put(to, evt.Hash.Bytes(), data); err != nil {
+			return err
+		}
+
+		return es.Commit(tx, func() {
+			es.Metrics.RecordDeletes(ctx, from, 1)
+			es.Metrics.RecordInserts(ctx, to, 1)
 		})
 	})
 }
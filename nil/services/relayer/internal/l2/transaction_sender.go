@@ -4,10 +4,13 @@ import (
 	"cmp"
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common/heap"
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/relayer/internal/status"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jonboulle/clockwork"
 )
@@ -15,6 +18,14 @@ import (
 type TransactionSenderConfig struct {
 	DbPollInterval  time.Duration
 	EventBufferSize int
+
+	// events failed to be relayed this many times are moved to the dead-letter queue,
+	// only invalid events and relay transactions failed on L2 are counted
+	MaxRelayAttempts int
+	// relay transaction without receipt for this long is sent again
+	ReceiptTimeout time.Duration
+	// upper bound of the delay between the retries of relay transactions failed to be sent to L2
+	MaxSendBackoff time.Duration
 }
 
 func (cfg *TransactionSenderConfig) Validate() error {
@@ -24,13 +35,25 @@ func (cfg *TransactionSenderConfig) Validate() error {
 	if cfg.EventBufferSize == 0 {
 		return errors.New("no event buffer size for the poll heap is set")
 	}
+	if cfg.MaxRelayAttempts == 0 {
+		return errors.New("no max relay attempts set")
+	}
+	if cfg.ReceiptTimeout == 0 {
+		return errors.New("no relay transaction receipt timeout set")
+	}
+	if cfg.MaxSendBackoff < cfg.DbPollInterval {
+		return errors.New("max send backoff is less than storage poll interval")
+	}
 	return nil
 }
 
 func DefaultTransactionSenderConfig() *TransactionSenderConfig {
 	return &TransactionSenderConfig{
-		DbPollInterval:  time.Second * 10,
-		EventBufferSize: 500,
+		DbPollInterval:   time.Second * 10,
+		EventBufferSize:  500,
+		MaxRelayAttempts: 5,
+		ReceiptTimeout:   time.Minute * 10,
+		MaxSendBackoff:   time.Minute * 5,
 	}
 }
 
@@ -43,14 +66,20 @@ type TransactionSender struct {
 	clock            clockwork.Clock
 	logger           logging.Logger
 	storage          *EventStorage
+	statusStorage    *status.Storage
 	eventFinProvider eventFinalizedProvider
 	metrics          TransactionSenderMetrics
 	contractBinding  L2Contract
+
+	// consecutive failures to send a relay transaction and the time relaying is resumed after them
+	sendFailures int
+	retryAt      time.Time
 }
 
 func NewTransactionSender(
 	config *TransactionSenderConfig,
 	storage *EventStorage,
+	statusStorage *status.Storage,
 	logger logging.Logger,
 	clock clockwork.Clock,
 	eventFinProvider eventFinalizedProvider,
@@ -65,6 +94,7 @@ func NewTransactionSender(
 		config:           config,
 		clock:            clock,
 		storage:          storage,
+		statusStorage:    statusStorage,
 		eventFinProvider: eventFinProvider,
 		metrics:          metrics,
 		contractBinding:  contractBinding,
@@ -92,6 +122,10 @@ func (ts *TransactionSender) Run(ctx context.Context, started chan<- struct{}) e
 		case <-ts.eventFinProvider.EventFinalized():
 			ts.logger.Debug().Msg("wake up by event emitter")
 		}
+		if err := ts.checkSentEvents(ctx); err != nil {
+			ts.logger.Error().Err(err).Msg("error occurred during checking relay transaction receipts")
+			ts.metrics.AddRelayError(ctx)
+		}
 		if err := ts.relayEvents(ctx); err != nil {
 			ts.logger.Error().Err(err).Msg("error occurred during relaying events to L2")
 			ts.metrics.AddRelayError(ctx)
@@ -100,6 +134,11 @@ func (ts *TransactionSender) Run(ctx context.Context, started chan<- struct{}) e
 }
 
 func (ts *TransactionSender) relayEvents(ctx context.Context) error {
+	if now := ts.clock.Now(); now.Before(ts.retryAt) {
+		ts.logger.Debug().Dur("retry_in", ts.retryAt.Sub(now)).Msg("relaying is postponed after send failures")
+		return nil
+	}
+
 	eventBySeqNumber := heap.NewBoundedMaxHeap(
 		ts.config.EventBufferSize,
 		func(a, b *Event) int {
@@ -130,36 +169,248 @@ func (ts *TransactionSender) relayEvents(ctx context.Context) error {
 		Int("checked_events_count", eventsIterated).
 		Msg("fetched some events ready to be relayed to L2")
 
-	droppingEvents := make([]common.Hash, 0, len(events))
+	for i, evt := range events {
+		txHash, err := ts.contractBinding.RelayMessage(ctx, evt)
+		if err != nil {
+			ts.logger.Error().Err(err).
+				Int("event_index", i).
+				Uint64("event_seqno", evt.SequenceNumber).
+				Stringer("event_hash", evt.Hash).
+				Msg("failed to relay event to L2")
+
+			if !errors.Is(err, ErrInvalidRelayMessage) {
+				// L2 is not reachable or rejected the transaction, the event itself is not to blame
+				ts.postponeRelaying()
+				ts.recordStatus(ctx, evt.Hash, status.Update{
+					Stage:         status.StageFinalized,
+					Error:         err,
+					RelayAttempts: evt.RelayAttempts,
+				})
+				return err
+			}
+
+			evt.RelayAttempts++
+			if evt.RelayAttempts >= ts.config.MaxRelayAttempts {
+				if err := ts.storage.DeadLetterPendingEvent(ctx, evt); err != nil {
+					return err
+				}
+				ts.onDeadLetter(ctx, evt, err)
+				continue
+			}
+
+			if err := ts.storage.UpdateEvent(ctx, evt); err != nil {
+				ts.logger.Warn().Err(err).Msg("failed to update event relay attempts")
+			}
+			ts.recordStatus(ctx, evt.Hash, status.Update{
+				Stage:         status.StageFinalized,
+				Error:         err,
+				RelayAttempts: evt.RelayAttempts,
+			})
+			return err
+		}
+		ts.sendFailures = 0
 
+		evt.L2TxHash = txHash
+		evt.SentAt = ts.clock.Now()
+		if err := ts.storage.MarkEventSent(ctx, evt); err != nil {
+			return err
+		}
+		ts.logger.Debug().
+			Stringer("event_hash", evt.Hash).
+			Stringer("tx_hash", txHash).
+			Msg("event relayed to L2")
+		ts.recordStatus(ctx, evt.Hash, status.Update{
+			Stage:    status.StageSentToL2,
+			L2TxHash: txHash,
+		})
+
+		ts.metrics.AddRelayedEvents(ctx, 1)
+	}
+
+	return nil
+}
+
+// postponeRelaying delays the next relay attempt exponentially with the number of consecutive send failures
+func (ts *TransactionSender) postponeRelaying() {
+	ts.sendFailures++
+	backoff := ts.config.DbPollInterval
+	for i := 1; i < ts.sendFailures && backoff < ts.config.MaxSendBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, ts.config.MaxSendBackoff)
+	ts.retryAt = ts.clock.Now().Add(backoff)
+
+	ts.logger.Warn().
+		Int("send_failures", ts.sendFailures).
+		Dur("backoff", backoff).
+		Msg("postponing relaying after send failure")
+}
+
+// checkSentEvents checks receipts of the relay transactions,
+// events failed on L2 are requeued or moved to the dead-letter queue,
+// events not processed on L2 in time are sent again
+func (ts *TransactionSender) checkSentEvents(ctx context.Context) error {
+	var sent []*Event
+	if err := ts.storage.IterateSentEventsByBatch(ctx, 100, func(batch []*Event) error {
+		sent = append(sent, batch...)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	var executed []common.Hash
 	defer func() {
-		if len(droppingEvents) == 0 {
+		if len(executed) == 0 {
 			return
 		}
 		ts.logger.Debug().
-			Int("event_count", len(droppingEvents)).
-			Msg("dropping events from L2 storage")
+			Int("event_count", len(executed)).
+			Msg("dropping executed events from L2 storage")
 
-		if err := ts.storage.DeleteEvents(ctx, droppingEvents); err != nil {
-			ts.logger.Warn().Err(err).Msg("failed to drop events from L2 storage")
+		if err := ts.storage.DeleteSentEvents(ctx, executed); err != nil {
+			ts.logger.Warn().Err(err).Msg("failed to drop executed events from L2 storage")
 		}
 	}()
 
-	for i, evt := range events {
-		if _, err := ts.contractBinding.RelayMessage(ctx, evt); err != nil {
-			ts.logger.Error().Err(err).
-				Int("event_index", i).
-				Uint64("event_seqno", evt.SequenceNumber).
-				Stringer("event_hash", evt.Hash).
-				Msg("failed to relay event to L2")
+	for _, evt := range sent {
+		// the relay transactions sent before may still be executed,
+		// then the message is already relayed and the latest transaction fails as a duplicate
+		idx, err := ts.findExecutedTimedOut(ctx, evt)
+		if err != nil {
+			return err
+		}
+		txHash := evt.L2TxHash
+		if idx >= 0 {
+			txHash = evt.TimedOutL2TxHashes[idx]
+		}
 
+		receipt, err := ts.contractBinding.GetReceipt(ctx, txHash)
+		if err != nil {
 			return err
 		}
-		ts.logger.Debug().Stringer("event_hash", evt.Hash).Msg("event relayed to L2")
-		droppingEvents = append(droppingEvents, evt.Hash)
 
-		ts.metrics.AddRelayedEvents(ctx, 1)
+		var execErr error
+		switch {
+		case receipt.IsComplete() && receipt.AllSuccess():
+			executed = append(executed, evt.Hash)
+			ts.recordStatus(ctx, evt.Hash, status.Update{
+				Stage:           status.StageExecutedOnL2,
+				L2TxHash:        txHash,
+				L2ReceiptStatus: receiptStatusSuccess,
+			})
+			continue
+		case receipt.IsComplete():
+			execErr = fmt.Errorf("relay transaction %s failed on L2: %s", evt.L2TxHash, receiptError(receipt))
+		case ts.clock.Since(evt.SentAt) >= ts.config.ReceiptTimeout:
+			if err := ts.resendTimedOut(ctx, evt); err != nil {
+				return err
+			}
+			continue
+		default:
+			continue
+		}
+
+		ts.logger.Error().Err(execErr).
+			Stringer("event_hash", evt.Hash).
+			Msg("relayed event failed on L2")
+
+		evt.RelayAttempts++
+		if evt.RelayAttempts >= ts.config.MaxRelayAttempts {
+			if err := ts.storage.DeadLetterSentEvent(ctx, evt); err != nil {
+				return err
+			}
+			ts.onDeadLetter(ctx, evt, execErr)
+			continue
+		}
+
+		if err := ts.storage.RequeueSentEvent(ctx, evt); err != nil {
+			return err
+		}
+		ts.recordStatus(ctx, evt.Hash, status.Update{
+			Stage:           status.StageFinalized,
+			Error:           execErr,
+			L2ReceiptStatus: receiptStatusFailed,
+			RelayAttempts:   evt.RelayAttempts,
+		})
 	}
 
 	return nil
 }
+
+// findExecutedTimedOut returns the index of the timed out relay transaction of the event executed on L2,
+// -1 is returned if there is no such transaction
+func (ts *TransactionSender) findExecutedTimedOut(ctx context.Context, evt *Event) (int, error) {
+	for i, txHash := range evt.TimedOutL2TxHashes {
+		receipt, err := ts.contractBinding.GetReceipt(ctx, txHash)
+		if err != nil {
+			return -1, err
+		}
+		if receipt.IsComplete() && receipt.AllSuccess() {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// resendTimedOut requeues the event whose relay transaction has not been processed on L2 in time,
+// it is not an execution failure, so the attempt is not counted
+func (ts *TransactionSender) resendTimedOut(ctx context.Context, evt *Event) error {
+	timeoutErr := fmt.Errorf("relay transaction %s has not been processed on L2 in time", evt.L2TxHash)
+	ts.logger.Warn().Err(timeoutErr).
+		Stringer("event_hash", evt.Hash).
+		Msg("relay transaction timed out, sending it again")
+
+	evt.TimedOutL2TxHashes = append(evt.TimedOutL2TxHashes, evt.L2TxHash)
+	if err := ts.storage.RequeueSentEvent(ctx, evt); err != nil {
+		return err
+	}
+	ts.recordStatus(ctx, evt.Hash, status.Update{
+		Stage:         status.StageFinalized,
+		Error:         timeoutErr,
+		RelayAttempts: evt.RelayAttempts,
+	})
+	return nil
+}
+
+func (ts *TransactionSender) onDeadLetter(ctx context.Context, evt *Event, reason error) {
+	ts.logger.Warn().
+		Err(reason).
+		Stringer("event_hash", evt.Hash).
+		Int("relay_attempts", evt.RelayAttempts).
+		Msg("event moved to dead-letter queue")
+
+	ts.recordStatus(ctx, evt.Hash, status.Update{
+		Stage:         status.StageDeadLetter,
+		Error:         reason,
+		RelayAttempts: evt.RelayAttempts,
+	})
+	ts.metrics.AddDeadLetterEvents(ctx, 1)
+}
+
+// recordStatus saves the event status, failures are not critical for relaying
+func (ts *TransactionSender) recordStatus(ctx context.Context, hash common.Hash, upd status.Update) {
+	if err := ts.statusStorage.Record(ctx, upd, hash); err != nil {
+		ts.logger.Warn().Err(err).Stringer("event_hash", hash).Msg("failed to record event status")
+	}
+}
+
+const (
+	receiptStatusSuccess = "success"
+	receiptStatusFailed  = "failed"
+)
+
+// receiptError returns the error of the first failed transaction in the receipt tree
+func receiptError(receipt *jsonrpc.RPCReceipt) string {
+	if !receipt.Success {
+		if receipt.ErrorMessage != "" {
+			return receipt.ErrorMessage
+		}
+		return receipt.Status
+	}
+	for _, out := range receipt.OutReceipts {
+		if !out.AllSuccess() {
+			return receiptError(out)
+		}
+	}
+	return ""
+}
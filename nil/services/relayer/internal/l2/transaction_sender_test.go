@@ -2,13 +2,16 @@ package l2
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/services/relayer/internal/status"
 	"github.com/NilFoundation/nil/nil/services/relayer/internal/storage"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/jonboulle/clockwork"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/suite"
@@ -49,6 +52,7 @@ type TransactionSenderTestSuite struct {
 	database       db.DB
 	logger         logging.Logger
 	l2Storage      *EventStorage
+	statusStorage  *status.Storage
 	storageMetrics storage.TableMetrics
 
 	// testing entity
@@ -88,8 +92,10 @@ func (s *TransactionSenderTestSuite) SetupTest() {
 	s.Require().NoError(err)
 
 	s.l2Storage = NewEventStorage(s.ctx, s.database, s.clockMock, s.storageMetrics, s.logger)
+	s.statusStorage = status.NewStorage(s.ctx, s.database, s.clockMock, s.storageMetrics, s.logger)
 
 	cfg := DefaultTransactionSenderConfig()
+	cfg.MaxRelayAttempts = 2
 
 	s.eventFinalizer = newEventFinalizerStub()
 
@@ -99,6 +105,7 @@ func (s *TransactionSenderTestSuite) SetupTest() {
 	s.transactionSender, err = NewTransactionSender(
 		cfg,
 		s.l2Storage,
+		s.statusStorage,
 		s.logger,
 		s.clockMock,
 		s.eventFinalizer,
@@ -106,6 +113,10 @@ func (s *TransactionSenderTestSuite) SetupTest() {
 		s.contractMock,
 	)
 	s.Require().NoError(err, "failed to initialize transaction sender")
+
+	// closed for tests which don't run the sender loop
+	s.transactionSenderStopped = make(chan struct{})
+	close(s.transactionSenderStopped)
 }
 
 func (s *TransactionSenderTestSuite) TearDownTest() {
@@ -228,6 +239,9 @@ func (s *TransactionSenderTestSuite) TestFailure() {
 	})
 	s.Require().NoError(err)
 
+	// relaying is resumed after the backoff
+	s.clockMock.Advance(DefaultTransactionSenderConfig().MaxSendBackoff)
+
 	s.runSenderWithExpectedEvents([]uint64{4, 6}, nil)
 
 	err = s.l2Storage.IterateEventsByBatch(s.ctx, 3, func(events []*Event) error {
@@ -237,6 +251,248 @@ func (s *TransactionSenderTestSuite) TestFailure() {
 	s.Require().NoError(err)
 }
 
+func (s *TransactionSenderTestSuite) collectEvents(
+	iterate func(context.Context, int, func([]*Event) error) error,
+) []*Event {
+	s.T().Helper()
+
+	var ret []*Event
+	s.Require().NoError(iterate(s.ctx, 100, func(events []*Event) error {
+		ret = append(ret, events...)
+		return nil
+	}))
+	return ret
+}
+
+// relayOnce runs a single iteration of the sender and returns sequence numbers of the relayed events,
+// the event with failOnSeqNo is rejected as invalid
+func (s *TransactionSenderTestSuite) relayOnce(failOnSeqNo uint64) []uint64 {
+	s.T().Helper()
+
+	var relayed []uint64
+	s.contractMock.RelayMessageFunc = func(ctx context.Context, event *Event) (common.Hash, error) {
+		if event.SequenceNumber == failOnSeqNo {
+			return common.EmptyHash, fmt.Errorf("%w: managed failure on %d seqno",
+				ErrInvalidRelayMessage, event.SequenceNumber)
+		}
+		relayed = append(relayed, event.SequenceNumber)
+		return getTxHash(event.SequenceNumber), nil
+	}
+
+	if err := s.transactionSender.checkSentEvents(s.ctx); err != nil {
+		s.Require().ErrorContains(err, "managed failure")
+	}
+	if err := s.transactionSender.relayEvents(s.ctx); err != nil {
+		s.Require().ErrorContains(err, "managed failure")
+	}
+	return relayed
+}
+
+func (s *TransactionSenderTestSuite) requireStage(seqNo int, stage status.Stage) *status.EventStatus {
+	s.T().Helper()
+
+	evtStatus, err := s.statusStorage.Get(s.ctx, getMsgHash(seqNo))
+	s.Require().NoError(err)
+	s.Require().Equal(stage, evtStatus.Stage)
+	return evtStatus
+}
+
+func (s *TransactionSenderTestSuite) TestDeadLetterOnRelayFailure() {
+	l2Events := []*Event{
+		{
+			Hash:           getMsgHash(1),
+			SequenceNumber: 1,
+		},
+		{
+			Hash:           getMsgHash(2),
+			SequenceNumber: 2,
+		},
+	}
+	s.Require().NoError(s.l2Storage.StoreEvents(s.ctx, l2Events))
+
+	// the first failure keeps the event in the queue and stops relaying
+	s.Require().Empty(s.relayOnce(1))
+	s.Require().Len(s.collectEvents(s.l2Storage.IterateEventsByBatch), 2)
+	s.Require().Empty(s.collectEvents(s.l2Storage.IterateDeadLetterEventsByBatch))
+
+	evtStatus := s.requireStage(1, status.StageFinalized)
+	s.Require().Equal(1, evtStatus.RelayAttempts)
+	s.Require().Contains(evtStatus.LastError, "managed failure")
+
+	// the second failure moves the event to the dead-letter queue and doesn't block the rest
+	s.Require().Equal([]uint64{2}, s.relayOnce(1))
+	s.Require().Empty(s.collectEvents(s.l2Storage.IterateEventsByBatch))
+
+	deadLetter := s.collectEvents(s.l2Storage.IterateDeadLetterEventsByBatch)
+	s.Require().Len(deadLetter, 1)
+	s.Require().EqualValues(1, deadLetter[0].SequenceNumber)
+
+	evtStatus = s.requireStage(1, status.StageDeadLetter)
+	s.Require().Equal(2, evtStatus.RelayAttempts)
+	evtStatus = s.requireStage(2, status.StageSentToL2)
+	s.Require().Equal(getTxHash(2), evtStatus.L2TxHash)
+
+	// requeued event is relayed again
+	requeued, err := s.l2Storage.RequeueDeadLetterEvent(s.ctx, getMsgHash(1))
+	s.Require().NoError(err)
+	s.Require().Zero(requeued.RelayAttempts)
+	s.Require().Empty(s.collectEvents(s.l2Storage.IterateDeadLetterEventsByBatch))
+
+	s.Require().Equal([]uint64{1}, s.relayOnce(0))
+	s.Require().Empty(s.collectEvents(s.l2Storage.IterateEventsByBatch))
+	s.requireStage(1, status.StageSentToL2)
+
+	_, err = s.l2Storage.RequeueDeadLetterEvent(s.ctx, getMsgHash(1))
+	s.Require().ErrorIs(err, ErrEventNotFound)
+}
+
+func (s *TransactionSenderTestSuite) TestReceiptCheck() {
+	l2Events := []*Event{
+		{
+			Hash:           getMsgHash(1),
+			SequenceNumber: 1,
+		},
+		{
+			Hash:           getMsgHash(2),
+			SequenceNumber: 2,
+		},
+		{
+			Hash:           getMsgHash(3),
+			SequenceNumber: 3,
+		},
+	}
+	s.Require().NoError(s.l2Storage.StoreEvents(s.ctx, l2Events))
+
+	s.Require().Equal([]uint64{1, 2, 3}, s.relayOnce(0))
+	s.Require().Len(s.collectEvents(s.l2Storage.IterateSentEventsByBatch), 3)
+
+	// the first relay transaction succeeds, the second one fails on L2, the third one is not processed yet
+	s.contractMock.GetReceiptFunc = func(ctx context.Context, txHash common.Hash) (*jsonrpc.RPCReceipt, error) {
+		switch txHash {
+		case getTxHash(1):
+			return &jsonrpc.RPCReceipt{Success: true}, nil
+		case getTxHash(2):
+			return &jsonrpc.RPCReceipt{ErrorMessage: "out of gas"}, nil
+		default:
+			return nil, nil
+		}
+	}
+	s.Require().Equal([]uint64{2}, s.relayOnce(0))
+
+	s.Require().Len(s.collectEvents(s.l2Storage.IterateSentEventsByBatch), 2)
+	evtStatus := s.requireStage(1, status.StageExecutedOnL2)
+	s.Require().Equal(receiptStatusSuccess, evtStatus.L2ReceiptStatus)
+	evtStatus = s.requireStage(2, status.StageSentToL2)
+	s.Require().Equal(1, evtStatus.RelayAttempts)
+	s.Require().Contains(evtStatus.LastError, "out of gas")
+
+	// the failed event reaches the attempt limit, the unprocessed one times out and is sent again
+	s.clockMock.Advance(DefaultTransactionSenderConfig().ReceiptTimeout)
+	s.Require().Equal([]uint64{3}, s.relayOnce(0))
+
+	deadLetter := s.collectEvents(s.l2Storage.IterateDeadLetterEventsByBatch)
+	s.Require().Len(deadLetter, 1)
+	s.Require().EqualValues(2, deadLetter[0].SequenceNumber)
+	s.requireStage(2, status.StageDeadLetter)
+
+	// the timeout is not counted as a failed attempt
+	evtStatus = s.requireStage(3, status.StageSentToL2)
+	s.Require().Zero(evtStatus.RelayAttempts)
+	s.Require().Contains(evtStatus.LastError, "has not been processed on L2 in time")
+
+	sent := s.collectEvents(s.l2Storage.IterateSentEventsByBatch)
+	s.Require().Len(sent, 1)
+	s.Require().Equal([]common.Hash{getTxHash(3)}, sent[0].TimedOutL2TxHashes)
+}
+
+func (s *TransactionSenderTestSuite) TestTransientSendFailures() {
+	s.Require().NoError(s.l2Storage.StoreEvents(s.ctx, []*Event{
+		{
+			Hash:           getMsgHash(1),
+			SequenceNumber: 1,
+		},
+	}))
+
+	sendAttempts := 0
+	s.contractMock.RelayMessageFunc = func(ctx context.Context, event *Event) (common.Hash, error) {
+		sendAttempts++
+		return common.EmptyHash, errors.New("connection refused")
+	}
+
+	// L2 is not reachable for longer than the attempt limit allows
+	const failures = 6
+	for i := range failures {
+		s.Require().ErrorContains(s.transactionSender.relayEvents(s.ctx), "connection refused")
+		s.Require().Equal(i+1, sendAttempts)
+
+		// relaying is postponed until the backoff expires
+		s.Require().NoError(s.transactionSender.relayEvents(s.ctx))
+		s.Require().Equal(i+1, sendAttempts)
+
+		s.clockMock.Advance(DefaultTransactionSenderConfig().MaxSendBackoff)
+	}
+
+	pending := s.collectEvents(s.l2Storage.IterateEventsByBatch)
+	s.Require().Len(pending, 1)
+	s.Require().Zero(pending[0].RelayAttempts)
+	s.Require().Empty(s.collectEvents(s.l2Storage.IterateDeadLetterEventsByBatch))
+
+	evtStatus := s.requireStage(1, status.StageFinalized)
+	s.Require().Zero(evtStatus.RelayAttempts)
+	s.Require().Contains(evtStatus.LastError, "connection refused")
+
+	// the event is relayed once L2 is back
+	s.Require().Equal([]uint64{1}, s.relayOnce(0))
+	s.requireStage(1, status.StageSentToL2)
+}
+
+func (s *TransactionSenderTestSuite) TestTimedOutTransactionExecuted() {
+	s.Require().NoError(s.l2Storage.StoreEvents(s.ctx, []*Event{
+		{
+			Hash:           getMsgHash(1),
+			SequenceNumber: 1,
+		},
+	}))
+
+	sendAttempts := 0
+	s.contractMock.RelayMessageFunc = func(ctx context.Context, event *Event) (common.Hash, error) {
+		sendAttempts++
+		return getTxHash(uint64(sendAttempts)), nil
+	}
+	s.contractMock.GetReceiptFunc = func(ctx context.Context, txHash common.Hash) (*jsonrpc.RPCReceipt, error) {
+		return nil, nil
+	}
+
+	s.Require().NoError(s.transactionSender.relayEvents(s.ctx))
+
+	// the first relay transaction times out, the event is sent again
+	s.clockMock.Advance(DefaultTransactionSenderConfig().ReceiptTimeout)
+	s.Require().NoError(s.transactionSender.checkSentEvents(s.ctx))
+	s.Require().NoError(s.transactionSender.relayEvents(s.ctx))
+	s.Require().Equal(2, sendAttempts)
+
+	// the first relay transaction is executed after all, the second one fails as a duplicate
+	s.contractMock.GetReceiptFunc = func(ctx context.Context, txHash common.Hash) (*jsonrpc.RPCReceipt, error) {
+		if txHash == getTxHash(1) {
+			return &jsonrpc.RPCReceipt{Success: true}, nil
+		}
+		return &jsonrpc.RPCReceipt{ErrorMessage: "ErrorDuplicateMessageRelayed"}, nil
+	}
+	s.Require().NoError(s.transactionSender.checkSentEvents(s.ctx))
+
+	s.Require().Empty(s.collectEvents(s.l2Storage.IterateSentEventsByBatch))
+	s.Require().Empty(s.collectEvents(s.l2Storage.IterateEventsByBatch))
+	s.Require().Empty(s.collectEvents(s.l2Storage.IterateDeadLetterEventsByBatch))
+
+	evtStatus := s.requireStage(1, status.StageExecutedOnL2)
+	s.Require().Equal(getTxHash(1), evtStatus.L2TxHash)
+	s.Require().Zero(evtStatus.RelayAttempts)
+}
+
+func getTxHash(seqNo uint64) common.Hash {
+	return common.BytesToHash([]byte{byte(seqNo)})
+}
+
 func getMsgHash(seqNo int) [32]byte {
 	var hash [32]byte
 	for i := range hash {
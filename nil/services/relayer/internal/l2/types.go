@@ -2,7 +2,9 @@ package l2
 
 import (
 	"math/big"
+	"time"
 
+	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 )
@@ -19,4 +21,12 @@ type Event struct {
 	Nonce          *big.Int          `json:"nonce"`
 	Type           uint8             `json:"messageType"`
 	ExpiryTime     *big.Int          `json:"expiryTime"`
+
+	// Relaying state
+	RelayAttempts int         `json:"relayAttempts,omitempty"` // number of failed attempts to relay the event
+	L2TxHash      common.Hash `json:"l2TxHash"`                // last relay transaction sent to L2
+	SentAt        time.Time   `json:"sentAt"`                  // time the last relay transaction was sent
+
+	// relay transactions sent earlier which were not processed on L2 in time, they still may be executed
+	TimedOutL2TxHashes []common.Hash `json:"timedOutL2TxHashes,omitempty"`
 }
@@ -0,0 +1,90 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/jonboulle/clockwork"
+)
+
+type PrunerConfig struct {
+	// RetentionPeriod is the time the statuses of the finished events are kept for, zero disables the pruning
+	RetentionPeriod time.Duration
+	PollInterval    time.Duration
+}
+
+func (cfg *PrunerConfig) Validate() error {
+	if cfg.RetentionPeriod != 0 && cfg.PollInterval == 0 {
+		return errors.New("zero prune poll interval")
+	}
+	return nil
+}
+
+func DefaultPrunerConfig() *PrunerConfig {
+	return &PrunerConfig{
+		RetentionPeriod: 7 * 24 * time.Hour,
+		PollInterval:    time.Hour,
+	}
+}
+
+// Pruner periodically removes the statuses of the finished events to keep the storage bounded
+type Pruner struct {
+	config  *PrunerConfig
+	storage *Storage
+	clock   clockwork.Clock
+	logger  logging.Logger
+}
+
+func NewPruner(config *PrunerConfig, storage *Storage, clock clockwork.Clock, logger logging.Logger) (*Pruner, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	p := &Pruner{
+		config:  config,
+		storage: storage,
+		clock:   clock,
+	}
+	p.logger = logger.With().Str(logging.FieldComponent, p.Name()).Logger()
+	return p, nil
+}
+
+func (*Pruner) Name() string {
+	return "status-pruner"
+}
+
+func (p *Pruner) Run(ctx context.Context, started chan<- struct{}) error {
+	p.logger.Info().Msg("initializing component")
+
+	if p.config.RetentionPeriod == 0 {
+		p.logger.Info().Msg("status pruning is disabled")
+		close(started)
+		return nil
+	}
+
+	ticker := p.clock.NewTicker(p.config.PollInterval)
+	defer ticker.Stop()
+	close(started)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.Chan():
+		}
+		p.prune(ctx)
+	}
+}
+
+// prune removes the expired statuses, failures are not critical for relaying
+func (p *Pruner) prune(ctx context.Context) {
+	pruned, err := p.storage.Prune(ctx, p.clock.Now().Add(-p.config.RetentionPeriod))
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("failed to prune event statuses")
+		return
+	}
+	if pruned > 0 {
+		p.logger.Info().Int("pruned_count", pruned).Msg("pruned statuses of finished events")
+	}
+}
@@ -0,0 +1,254 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/services/relayer/internal/storage"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/jonboulle/clockwork"
+)
+
+const (
+	// eventStatusTable stores lifecycle of the deposit events
+	// Key: Hash of the Event
+	eventStatusTable = "event_status"
+)
+
+var ErrStatusNotFound = errors.New("event status not found")
+
+// Storage keeps the lifecycle of the deposit events for the operators
+type Storage struct {
+	*storage.BaseStorage
+}
+
+func NewStorage(
+	ctx context.Context,
+	database db.DB,
+	clock clockwork.Clock,
+	metrics storage.TableMetrics,
+	logger logging.Logger,
+) *Storage {
+	return &Storage{
+		BaseStorage: storage.NewBaseStorage(ctx, database, clock, logger, metrics),
+	}
+}
+
+// Record applies the update to the statuses of the events, creating them if needed.
+// Zero fields of the update don't overwrite the stored ones.
+func (s *Storage) Record(ctx context.Context, upd Update, hashes ...ethcommon.Hash) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	return s.RetryRunner.Do(ctx, func(ctx context.Context) error {
+		tx, err := s.Database.CreateRwTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		now := s.Clock.Now()
+		for _, hash := range hashes {
+			status, err := s.get(tx, hash)
+			if errors.Is(err, ErrStatusNotFound) {
+				status = &EventStatus{Hash: hash}
+			} else if err != nil {
+				return err
+			}
+
+			status.apply(upd, now)
+
+			data, err := json.Marshal(status)
+			if err != nil {
+				return fmt.Errorf("%w: %w", storage.ErrSerializationFailed, err)
+			}
+			if err := tx.Put(eventStatusTable, hash.Bytes(), data); err != nil {
+				return err
+			}
+		}
+		return s.Commit(tx, nil)
+	})
+}
+
+func (s *Storage) Get(ctx context.Context, hash ethcommon.Hash) (*EventStatus, error) {
+	var ret *EventStatus
+	err := s.RetryRunner.Do(ctx, func(ctx context.Context) error {
+		tx, err := s.Database.CreateRoTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		ret, err = s.get(tx, hash)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *Storage) get(tx db.RoTx, hash ethcommon.Hash) (*EventStatus, error) {
+	data, err := tx.Get(eventStatusTable, hash.Bytes())
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrStatusNotFound, hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var status EventStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("%w: %w", storage.ErrSerializationFailed, err)
+	}
+	return &status, nil
+}
+
+// List returns up to limit statuses of the events in the given stages (any stage if none is given)
+func (s *Storage) List(ctx context.Context, limit int, stages ...Stage) ([]*EventStatus, error) {
+	var ret []*EventStatus
+	err := s.RetryRunner.Do(ctx, func(ctx context.Context) error {
+		tx, err := s.Database.CreateRoTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		iter, err := tx.Range(eventStatusTable, nil, nil)
+		if err != nil {
+			return err
+		}
+		defer iter.Close()
+
+		ret = nil
+		count := 0
+		for iter.HasNext() {
+			_, val, err := iter.Next()
+			if err != nil {
+				return err
+			}
+			count++
+			if len(ret) >= limit {
+				continue
+			}
+
+			var status EventStatus
+			if err := json.Unmarshal(val, &status); err != nil {
+				return fmt.Errorf("%w: %w", storage.ErrSerializationFailed, err)
+			}
+			if len(stages) == 0 || slices.Contains(stages, status.Stage) {
+				ret = append(ret, &status)
+			}
+		}
+
+		s.Metrics.SetTableSize(ctx, eventStatusTable, count)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// CountByStage returns the number of the events in each stage
+func (s *Storage) CountByStage(ctx context.Context) (map[Stage]int, error) {
+	var ret map[Stage]int
+	err := s.RetryRunner.Do(ctx, func(ctx context.Context) error {
+		tx, err := s.Database.CreateRoTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		iter, err := tx.Range(eventStatusTable, nil, nil)
+		if err != nil {
+			return err
+		}
+		defer iter.Close()
+
+		ret = make(map[Stage]int)
+		for iter.HasNext() {
+			_, val, err := iter.Next()
+			if err != nil {
+				return err
+			}
+			var status struct {
+				Stage Stage `json:"stage"`
+			}
+			if err := json.Unmarshal(val, &status); err != nil {
+				return fmt.Errorf("%w: %w", storage.ErrSerializationFailed, err)
+			}
+			ret[status.Stage]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Prune removes the statuses of the finished events which haven't been updated since the given time.
+// It returns the number of the removed statuses.
+func (s *Storage) Prune(ctx context.Context, before time.Time) (int, error) {
+	var pruned int
+	err := s.RetryRunner.Do(ctx, func(ctx context.Context) error {
+		tx, err := s.Database.CreateRwTx(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		keys, err := s.finishedBefore(tx, before)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := tx.Delete(eventStatusTable, key); err != nil {
+				return err
+			}
+		}
+		pruned = len(keys)
+		return s.Commit(tx, func() {
+			s.Metrics.RecordDeletes(ctx, eventStatusTable, len(keys))
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return pruned, nil
+}
+
+func (s *Storage) finishedBefore(tx db.RoTx, before time.Time) ([][]byte, error) {
+	iter, err := tx.Range(eventStatusTable, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var keys [][]byte
+	for iter.HasNext() {
+		key, val, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		var status struct {
+			Stage     Stage     `json:"stage"`
+			UpdatedAt time.Time `json:"updatedAt"`
+		}
+		if err := json.Unmarshal(val, &status); err != nil {
+			return nil, fmt.Errorf("%w: %w", storage.ErrSerializationFailed, err)
+		}
+		if status.Stage.Finished() && status.UpdatedAt.Before(before) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
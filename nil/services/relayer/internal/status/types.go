@@ -0,0 +1,106 @@
+package status
+
+import (
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// Stage is a step of the deposit lifecycle
+type Stage string
+
+const (
+	// StageSeenOnL1 is set once the event is received from L1BridgeMessenger
+	StageSeenOnL1 Stage = "seen_on_l1"
+	// StageFinalized is set once the block containing the event is finalized on L1,
+	// the event is waiting to be sent to L2 since then
+	StageFinalized Stage = "finalized"
+	// StageOrphaned is set if the block containing the event was reorged out from L1
+	StageOrphaned Stage = "orphaned"
+	// StageSentToL2 is set once the relay transaction is sent to L2, the receipt is awaited since then
+	StageSentToL2 Stage = "sent_to_l2"
+	// StageExecutedOnL2 is set once the relay transaction is successfully executed on L2
+	StageExecutedOnL2 Stage = "executed_on_l2"
+	// StageDeadLetter is set for events which failed validation or L2 execution too many times,
+	// they are not processed until requeued by an operator
+	StageDeadLetter Stage = "dead_letter"
+	// StageDropped is set for dead-letter events dropped by an operator
+	StageDropped Stage = "dropped"
+)
+
+// Finished reports whether the event is not processed in this stage anymore
+func (s Stage) Finished() bool {
+	switch s {
+	case StageExecutedOnL2, StageOrphaned, StageDropped:
+		return true
+	default:
+		return false
+	}
+}
+
+// Transition is a change of the event stage
+type Transition struct {
+	Stage Stage     `json:"stage"`
+	Time  time.Time `json:"time"`
+	Error string    `json:"error,omitempty"`
+}
+
+// EventStatus describes the lifecycle of a single deposit event
+type EventStatus struct {
+	Hash          ethcommon.Hash `json:"hash"`
+	Stage         Stage          `json:"stage"`
+	L1BlockNumber uint64         `json:"l1BlockNumber"`
+
+	// the last relay transaction sent to L2 and its receipt status
+	L2TxHash        common.Hash `json:"l2TxHash"`
+	L2ReceiptStatus string      `json:"l2ReceiptStatus,omitempty"`
+
+	// number of failed attempts to relay the event to L2
+	RelayAttempts int    `json:"relayAttempts"`
+	LastError     string `json:"lastError,omitempty"`
+
+	UpdatedAt time.Time    `json:"updatedAt"`
+	History   []Transition `json:"history"`
+}
+
+// Update describes the change of the event status
+type Update struct {
+	Stage Stage
+	// Error is saved as the last error and attached to the transition
+	Error error
+
+	L1BlockNumber   uint64
+	L2TxHash        common.Hash
+	L2ReceiptStatus string
+	RelayAttempts   int
+	// ResetAttempts clears the relay attempts, e.g. when the event is requeued by an operator
+	ResetAttempts bool
+}
+
+func (es *EventStatus) apply(upd Update, now time.Time) {
+	transition := Transition{Stage: upd.Stage, Time: now}
+	if upd.Error != nil {
+		transition.Error = upd.Error.Error()
+		es.LastError = transition.Error
+	}
+	if upd.Stage != es.Stage || upd.Error != nil {
+		es.History = append(es.History, transition)
+	}
+	es.Stage = upd.Stage
+	es.UpdatedAt = now
+
+	if upd.L1BlockNumber != 0 {
+		es.L1BlockNumber = upd.L1BlockNumber
+	}
+	if !upd.L2TxHash.Empty() {
+		es.L2TxHash = upd.L2TxHash
+		es.L2ReceiptStatus = ""
+	}
+	if upd.L2ReceiptStatus != "" {
+		es.L2ReceiptStatus = upd.L2ReceiptStatus
+	}
+	if upd.RelayAttempts != 0 || upd.ResetAttempts {
+		es.RelayAttempts = upd.RelayAttempts
+	}
+}
@@ -7,8 +7,10 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/services/relayer/internal/api"
 	"github.com/NilFoundation/nil/nil/services/relayer/internal/l1"
 	"github.com/NilFoundation/nil/nil/services/relayer/internal/l2"
+	"github.com/NilFoundation/nil/nil/services/relayer/internal/status"
 	"github.com/NilFoundation/nil/nil/services/relayer/internal/storage"
 	"github.com/jonboulle/clockwork"
 	"golang.org/x/sync/errgroup"
@@ -20,6 +22,10 @@ type RelayerConfig struct {
 	TransactionSenderConfig *l2.TransactionSenderConfig
 	L2ContractConfig        *l2.ContractConfig
 	TelemetryConfig         *telemetry.Config
+
+	// status and admin APIs are served only if the endpoints are set
+	StatusServerConfig *api.StatusServerConfig
+	StatusPrunerConfig *status.PrunerConfig
 }
 
 func DefaultRelayerConfig() *RelayerConfig {
@@ -31,6 +37,8 @@ func DefaultRelayerConfig() *RelayerConfig {
 		TelemetryConfig: &telemetry.Config{
 			ServiceName: "relayer",
 		},
+		StatusServerConfig: &api.StatusServerConfig{},
+		StatusPrunerConfig: status.DefaultPrunerConfig(),
 	}
 }
 
@@ -39,6 +47,10 @@ type RelayerService struct {
 	L1EventListener     *l1.EventListener
 	L1FinalityEnsurer   *l1.FinalityEnsurer
 	L2TransactionSender *l2.TransactionSender
+	StatusPruner        *status.Pruner
+
+	// set only if the status or admin API endpoint is configured
+	StatusServer *api.StatusServer
 }
 
 func New(
@@ -72,6 +84,14 @@ func New(
 		return nil, err
 	}
 
+	statusStorage := status.NewStorage(
+		ctx,
+		database,
+		clock,
+		storageMetrics,
+		rs.Logger,
+	)
+
 	l1Contract, err := l1.NewL1ContractWrapper(
 		l1Client,
 		config.EventListenerConfig.BridgeMessengerContractAddress,
@@ -92,6 +112,7 @@ func New(
 		l1Client,
 		l1Contract,
 		l1Storage,
+		statusStorage,
 		eventListenerMetrics,
 		rs.Logger,
 	)
@@ -119,6 +140,7 @@ func New(
 		rs.Logger,
 		l1Storage,
 		l2Storage,
+		statusStorage,
 		finalityEnsurerMetrics,
 		rs.L1EventListener,
 	)
@@ -155,6 +177,7 @@ func New(
 	rs.L2TransactionSender, err = l2.NewTransactionSender(
 		config.TransactionSenderConfig,
 		l2Storage,
+		statusStorage,
 		rs.Logger,
 		clock,
 		rs.L1FinalityEnsurer,
@@ -165,6 +188,20 @@ func New(
 		return nil, err
 	}
 
+	rs.StatusPruner, err = status.NewPruner(config.StatusPrunerConfig, statusStorage, clock, rs.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.StatusServerConfig.HttpEndpoint != "" || config.StatusServerConfig.AdminEndpoint != "" {
+		rs.StatusServer = api.NewStatusServer(
+			config.StatusServerConfig,
+			api.NewStatusApi(statusStorage),
+			api.NewAdminApi(statusStorage, l2Storage, rs.Logger),
+			rs.Logger,
+		)
+	}
+
 	return rs, nil
 }
 
@@ -186,5 +223,17 @@ func (rs *RelayerService) Run(ctx context.Context) error {
 		return rs.L2TransactionSender.Run(ctx, transactionSenderStarted)
 	})
 
+	statusPrunerStarted := make(chan struct{})
+	eg.Go(func() error {
+		return rs.StatusPruner.Run(gCtx, statusPrunerStarted)
+	})
+
+	if rs.StatusServer != nil {
+		statusServerStarted := make(chan struct{})
+		eg.Go(func() error {
+			return rs.StatusServer.Run(gCtx, statusServerStarted)
+		})
+	}
+
 	return eg.Wait()
 }
@@ -0,0 +1,81 @@
+package rollup
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// L1BackendRpc fetches L1 headers from the JSON-RPC endpoints, switching to the next one on failures
+	L1BackendRpc = "rpc"
+	// L1BackendSimulated generates a deterministic L1 chain locally, for networks without access to L1
+	L1BackendSimulated = "simulated"
+	// L1BackendFile serves L1 headers from a JSON file, re-reading it on every poll
+	L1BackendFile = "file"
+)
+
+const (
+	DefaultL1PollInterval       = 5 * time.Second
+	DefaultL1Confirmations      = 6
+	DefaultSimulatedL1BlockTime = 12 * time.Second
+)
+
+var defaultL1Endpoints = []string{
+	"https://eth.llamarpc.com",
+	"https://eth-mainnet.public.blastapi.io",
+	"https://rpc.ankr.com/eth",
+	"https://rpc.flashbots.net",
+	"https://cloudflare-eth.com",
+}
+
+type L1FetcherConfig struct {
+	// Backend is the source of L1 headers: "rpc", "simulated" or "file".
+	Backend string `yaml:"backend,omitempty"`
+	// Endpoints are the L1 JSON-RPC endpoints used by the "rpc" backend in the order of preference.
+	Endpoints []string `yaml:"endpoints,omitempty"`
+	// PollInterval is the interval of fetching the latest L1 header.
+	PollInterval time.Duration `yaml:"pollInterval,omitempty"`
+	// Confirmations is the number of L1 blocks built on top of the header before it is published.
+	Confirmations uint64 `yaml:"confirmations,omitempty"`
+
+	// HeadersFile is the JSON file with the array of L1 headers (as returned by eth_getBlockByNumber)
+	// used by the "file" backend.
+	HeadersFile string `yaml:"headersFile,omitempty"`
+	// SimulatedBlockTime is the block time of the chain generated by the "simulated" backend.
+	SimulatedBlockTime time.Duration `yaml:"simulatedBlockTime,omitempty"`
+}
+
+func NewDefaultL1FetcherConfig() *L1FetcherConfig {
+	return &L1FetcherConfig{
+		Backend:            L1BackendRpc,
+		Endpoints:          defaultL1Endpoints,
+		PollInterval:       DefaultL1PollInterval,
+		Confirmations:      DefaultL1Confirmations,
+		SimulatedBlockTime: DefaultSimulatedL1BlockTime,
+	}
+}
+
+func (c *L1FetcherConfig) Validate() error {
+	if c.PollInterval <= 0 {
+		return errors.New("L1 poll interval must be positive")
+	}
+
+	switch c.Backend {
+	case L1BackendRpc:
+		if len(c.Endpoints) == 0 {
+			return errors.New("no L1 endpoints set")
+		}
+	case L1BackendSimulated:
+		if c.SimulatedBlockTime <= 0 {
+			return errors.New("simulated L1 block time must be positive")
+		}
+	case L1BackendFile:
+		if c.HeadersFile == "" {
+			return errors.New("no L1 headers file set")
+		}
+	default:
+		return fmt.Errorf("unknown L1 backend %q", c.Backend)
+	}
+	return nil
+}
@@ -4,51 +4,82 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
-	"time"
 
 	"github.com/NilFoundation/nil/nil/common/concurrent"
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/ethereum/go-ethereum/common"
 	l1types "github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/jonboulle/clockwork"
 )
 
 //go:generate go run github.com/matryer/moq -out l1_fetcher_generated_mock.go -rm -stub -with-resets . L1BlockFetcher
 
-const pollInterval = 5 * time.Second
+var ErrInvalidL1Header = errors.New("invalid L1 header")
 
 type L1BlockFetcher interface {
 	GetLastBlockInfo(ctx context.Context) (*l1types.Header, error)
 }
 
-type L1BlockFetcherRpc struct {
-	client    *rpc.Client
-	header    *l1types.Header
-	logger    logging.Logger
-	nodeIndex int
-	lock      sync.RWMutex
-}
+// ConfirmedBlockFetcher tracks the recent L1 chain and publishes the header the configured number of
+// confirmations deep. The headers are linked by the parent hashes, so reorgs of L1 are detected
+// and the replaced headers are never published unless the reorg is deeper than the confirmation depth.
+type ConfirmedBlockFetcher struct {
+	config *L1FetcherConfig
+	source L1HeaderSource
+	logger logging.Logger
+
+	// chain holds the canonical headers from the published one up to the L1 tip.
+	// It is accessed only by the fetching routine.
+	chain []*l1types.Header
 
-var rpcNodeList = []string{
-	"https://eth.llamarpc.com",
-	"https://eth-mainnet.public.blastapi.io",
-	"https://rpc.ankr.com/eth",
-	"https://eth-mainnet.public.blastapi.io",
-	"https://rpc.flashbots.net",
-	"https://cloudflare-eth.com",
+	header *l1types.Header
+	lock   sync.RWMutex
 }
 
-func NewL1BlockFetcherRpc(ctx context.Context) L1BlockFetcher {
-	p := &L1BlockFetcherRpc{
-		logger: logging.NewLogger("l1fetcher"),
+var _ L1BlockFetcher = (*ConfirmedBlockFetcher)(nil)
+
+// NewL1BlockFetcher creates the fetcher for the configured backend and starts polling L1 in background.
+// The default configuration is used if config is nil.
+func NewL1BlockFetcher(ctx context.Context, config *L1FetcherConfig) (*ConfirmedBlockFetcher, error) {
+	if config == nil {
+		config = NewDefaultL1FetcherConfig()
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid L1 fetcher config: %w", err)
+	}
+
+	logger := logging.NewLogger("l1fetcher")
+
+	var source L1HeaderSource
+	switch config.Backend {
+	case L1BackendRpc:
+		source = newRpcHeaderSource(config.Endpoints, logger)
+	case L1BackendSimulated:
+		source = NewSimulatedL1(clockwork.NewRealClock(), config.SimulatedBlockTime)
+	case L1BackendFile:
+		source = newFileHeaderSource(config.HeadersFile)
 	}
+
+	p := NewConfirmedBlockFetcher(config, source, logger)
 	go func() {
 		p.Run(ctx)
 	}()
-	return p
+	return p, nil
+}
+
+func NewConfirmedBlockFetcher(
+	config *L1FetcherConfig, source L1HeaderSource, logger logging.Logger,
+) *ConfirmedBlockFetcher {
+	return &ConfirmedBlockFetcher{
+		config: config,
+		source: source,
+		logger: logger,
+	}
 }
 
-func (p *L1BlockFetcherRpc) GetLastBlockInfo(ctx context.Context) (*l1types.Header, error) {
+func (p *ConfirmedBlockFetcher) GetLastBlockInfo(ctx context.Context) (*l1types.Header, error) {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
 	if p.header == nil {
@@ -57,64 +88,153 @@ func (p *L1BlockFetcherRpc) GetLastBlockInfo(ctx context.Context) (*l1types.Head
 	return p.header, nil
 }
 
-func (p *L1BlockFetcherRpc) Run(ctx context.Context) {
-	p.fetch()
-	concurrent.RunTickerLoop(ctx, pollInterval, func(ctx context.Context) {
-		p.fetch()
-	})
+func (p *ConfirmedBlockFetcher) Run(ctx context.Context) {
+	p.logger.Info().
+		Str("backend", p.config.Backend).
+		Uint64("confirmations", p.config.Confirmations).
+		Msg("Starting L1 block fetcher")
+
+	p.fetchAndLog(ctx)
+	concurrent.RunTickerLoop(ctx, p.config.PollInterval, p.fetchAndLog)
 }
 
-func (p *L1BlockFetcherRpc) fetch() {
-	if p.client == nil {
-		if err := p.connect(); err != nil {
-			p.logger.Error().Err(err).Msg("failed to connect to L1")
-			return
-		}
+func (p *ConfirmedBlockFetcher) fetchAndLog(ctx context.Context) {
+	if err := p.Fetch(ctx); err != nil {
+		p.logger.Warn().Err(err).Msg("failed to fetch L1 block")
 	}
+}
 
-	var header *l1types.Header
-	if err := p.client.Call(&header, "eth_getBlockByNumber", "latest", false); err != nil {
-		p.logger.Warn().
-			Err(err).
-			Str("node", rpcNodeList[p.nodeIndex]).
-			Msg("failed to get L1 last block")
-		if err = p.switchNode(); err != nil {
-			p.logger.Error().Err(err).Msg("failed to switch node")
-		}
-	} else {
-		p.lock.Lock()
-		defer p.lock.Unlock()
-		p.header = header
+// Fetch updates the tracked chain up to the latest L1 header and publishes the confirmed one.
+func (p *ConfirmedBlockFetcher) Fetch(ctx context.Context) error {
+	latest, err := p.source.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get latest L1 header: %w", err)
+	}
+	if latest == nil || latest.Number == nil {
+		return fmt.Errorf("%w: empty latest header", ErrInvalidL1Header)
+	}
+
+	if n := len(p.chain); n > 0 && p.chain[n-1].Hash() == latest.Hash() {
+		return nil
 	}
-}
 
-func (p *L1BlockFetcherRpc) connect() error {
-	if p.client != nil {
-		p.client.Close()
+	var base uint64
+	if number := latest.Number.Uint64(); number > p.config.Confirmations {
+		base = number - p.config.Confirmations
 	}
-	var err error
-	if p.client, err = rpc.Dial(rpcNodeList[p.nodeIndex]); err != nil {
-		return fmt.Errorf("failed to connect to L1: %w", err)
+
+	chain, err := p.linkChain(ctx, latest, base)
+	if err != nil {
+		return err
 	}
+
+	p.checkReorg(chain)
+	p.chain = chain
+	p.publish(chain[0])
 	return nil
 }
 
-func (p *L1BlockFetcherRpc) switchNode() error {
-	initialIndex := p.nodeIndex
-	for {
-		if p.nodeIndex == len(rpcNodeList)-1 {
-			p.nodeIndex = 0
-		} else {
-			p.nodeIndex++
+// linkChain returns the canonical headers with numbers from base up to latest.
+// The headers are linked by the parent hashes, the ones not tracked yet are fetched from the source.
+func (p *ConfirmedBlockFetcher) linkChain(
+	ctx context.Context, latest *l1types.Header, base uint64,
+) ([]*l1types.Header, error) {
+	known := make(map[common.Hash]int, len(p.chain))
+	for i, header := range p.chain {
+		known[header.Hash()] = i
+	}
+
+	reversed := []*l1types.Header{latest}
+	for cur := latest; cur.Number.Uint64() > base; {
+		parent, err := p.parentOf(ctx, cur, known)
+		if err != nil {
+			return nil, err
 		}
-		p.logger.Info().Msgf("Switch to new provider: %s", rpcNodeList[p.nodeIndex])
-		err := p.connect()
-		if err == nil {
-			break
+		reversed = append(reversed, parent)
+		cur = parent
+	}
+
+	slices.Reverse(reversed)
+	return reversed, nil
+}
+
+// parentOf returns the known parent of the header or fetches and validates it.
+func (p *ConfirmedBlockFetcher) parentOf(
+	ctx context.Context, header *l1types.Header, known map[common.Hash]int,
+) (*l1types.Header, error) {
+	if i, ok := known[header.ParentHash]; ok {
+		// the known part of the chain has already been validated
+		return p.chain[i], nil
+	}
+
+	parent, err := p.source.HeaderByHash(ctx, header.ParentHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L1 header %s: %w", header.ParentHash, err)
+	}
+	if err := validateLink(parent, header); err != nil {
+		return nil, err
+	}
+	return parent, nil
+}
+
+// checkReorg reports the tracked headers replaced in the new chain.
+func (p *ConfirmedBlockFetcher) checkReorg(chain []*l1types.Header) {
+	base := chain[0].Number.Uint64()
+	var dropped []*l1types.Header
+	for _, header := range p.chain {
+		number := header.Number.Uint64()
+		if number < base {
+			continue
 		}
-		if p.nodeIndex == initialIndex {
-			return fmt.Errorf("all nodes are down, last error: %w", err)
+		if idx := number - base; idx >= uint64(len(chain)) || chain[idx].Hash() != header.Hash() {
+			dropped = append(dropped, header)
 		}
 	}
+	if len(dropped) == 0 {
+		return
+	}
+
+	reorgedFrom := dropped[0].Number.Uint64()
+	p.logger.Warn().
+		Uint64("reorged_from", reorgedFrom).
+		Int("depth", len(dropped)).
+		Uint64("new_tip", chain[len(chain)-1].Number.Uint64()).
+		Msg("L1 reorg detected")
+
+	if published := p.published(); published != nil && published.Number.Uint64() >= reorgedFrom {
+		p.logger.Error().
+			Uint64("published_block", published.Number.Uint64()).
+			Uint64("reorged_from", reorgedFrom).
+			Msg("L1 reorg is deeper than the confirmation depth, the published block has been replaced")
+	}
+}
+
+func (p *ConfirmedBlockFetcher) published() *l1types.Header {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.header
+}
+
+func (p *ConfirmedBlockFetcher) publish(header *l1types.Header) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.header = header
+}
+
+func validateLink(parent, child *l1types.Header) error {
+	if parent == nil || parent.Number == nil {
+		return fmt.Errorf("%w: empty parent of %s", ErrInvalidL1Header, child.Hash())
+	}
+	if parent.Hash() != child.ParentHash {
+		return fmt.Errorf("%w: header %d hash %s doesn't match the parent hash %s of its child",
+			ErrInvalidL1Header, parent.Number, parent.Hash(), child.ParentHash)
+	}
+	if parent.Number.Uint64()+1 != child.Number.Uint64() {
+		return fmt.Errorf("%w: parent of header %d has number %d",
+			ErrInvalidL1Header, child.Number, parent.Number)
+	}
+	if parent.Time > child.Time {
+		return fmt.Errorf("%w: header %d is older than its parent", ErrInvalidL1Header, child.Number)
+	}
 	return nil
 }
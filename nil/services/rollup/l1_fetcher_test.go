@@ -0,0 +1,149 @@
+package rollup
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	l1types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFetcher(t *testing.T, source L1HeaderSource, confirmations uint64) *ConfirmedBlockFetcher {
+	t.Helper()
+
+	config := NewDefaultL1FetcherConfig()
+	config.Confirmations = confirmations
+	return NewConfirmedBlockFetcher(config, source, logging.NewLogger("l1fetcher_test"))
+}
+
+func requirePublished(t *testing.T, fetcher *ConfirmedBlockFetcher, source L1HeaderSource, number uint64) {
+	t.Helper()
+
+	ctx := context.Background()
+	require.NoError(t, fetcher.Fetch(ctx))
+
+	header, err := fetcher.GetLastBlockInfo(ctx)
+	require.NoError(t, err)
+	canonical, err := source.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	require.NoError(t, err)
+	require.Equal(t, canonical.Hash(), header.Hash())
+}
+
+func TestL1FetcherConfirmations(t *testing.T) {
+	t.Parallel()
+
+	l1 := NewSimulatedL1(clockwork.NewFakeClock(), 0)
+	fetcher := newTestFetcher(t, l1, 3)
+
+	_, err := fetcher.GetLastBlockInfo(t.Context())
+	require.Error(t, err)
+
+	// the chain is shorter than the confirmation depth
+	l1.Mine(2)
+	requirePublished(t, fetcher, l1, 0)
+
+	l1.Mine(8)
+	requirePublished(t, fetcher, l1, 7)
+
+	l1.Mine(1)
+	requirePublished(t, fetcher, l1, 8)
+
+	// nothing changes without new blocks
+	requirePublished(t, fetcher, l1, 8)
+}
+
+func TestL1FetcherReorg(t *testing.T) {
+	t.Parallel()
+
+	l1 := NewSimulatedL1(clockwork.NewFakeClock(), 0)
+	fetcher := newTestFetcher(t, l1, 3)
+
+	l1.Mine(10)
+	requirePublished(t, fetcher, l1, 7)
+	published, err := fetcher.GetLastBlockInfo(t.Context())
+	require.NoError(t, err)
+
+	// the reorg within the confirmation depth doesn't affect the published block
+	l1.Reorg(2, 2)
+	requirePublished(t, fetcher, l1, 7)
+	header, err := fetcher.GetLastBlockInfo(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, published.Hash(), header.Hash())
+
+	// the shorter branch is followed as well
+	l1.Reorg(3, 1)
+	requirePublished(t, fetcher, l1, 5)
+
+	// the reorg deeper than the confirmation depth replaces the published block
+	l1.Reorg(5, 8)
+	requirePublished(t, fetcher, l1, 8)
+	header, err = fetcher.GetLastBlockInfo(t.Context())
+	require.NoError(t, err)
+	require.NotEqual(t, published.Hash(), header.Hash())
+}
+
+func TestL1FetcherSimulatedBlockTime(t *testing.T) {
+	t.Parallel()
+
+	clock := clockwork.NewFakeClock()
+	l1 := NewSimulatedL1(clock, 12*time.Second)
+	fetcher := newTestFetcher(t, l1, 2)
+
+	clock.Advance(time.Minute)
+	requirePublished(t, fetcher, l1, 3)
+
+	header, err := fetcher.GetLastBlockInfo(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, uint64(clock.Now().Add(-24*time.Second).Unix()), header.Time)
+	_, err = GetBlobGasPrice(header)
+	require.NoError(t, err)
+}
+
+func TestL1FetcherFileBackend(t *testing.T) {
+	t.Parallel()
+
+	l1 := NewSimulatedL1(clockwork.NewFakeClock(), 0)
+	l1.Mine(5)
+
+	headers := make([]*l1types.Header, 0, 6)
+	for i := range 6 {
+		header, err := l1.HeaderByNumber(t.Context(), big.NewInt(int64(i)))
+		require.NoError(t, err)
+		headers = append(headers, header)
+	}
+
+	path := filepath.Join(t.TempDir(), "l1.json")
+	writeHeaders := func(headers []*l1types.Header) {
+		data, err := json.Marshal(headers)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+	}
+
+	writeHeaders(headers)
+	source := newFileHeaderSource(path)
+	fetcher := newTestFetcher(t, source, 2)
+	requirePublished(t, fetcher, source, 3)
+
+	// the header older than its parent is rejected
+	l1.Mine(1)
+	tip, err := l1.HeaderByNumber(t.Context(), nil)
+	require.NoError(t, err)
+	next := *tip
+	broken := *headers[5]
+	broken.Time = next.Time + 1
+	next.ParentHash = broken.Hash()
+	writeHeaders(append(headers[:5:5], &broken, &next))
+	require.ErrorIs(t, fetcher.Fetch(t.Context()), ErrInvalidL1Header)
+
+	// the published block is kept
+	header, err := fetcher.GetLastBlockInfo(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, headers[3].Hash(), header.Hash())
+}
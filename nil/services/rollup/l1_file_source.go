@@ -0,0 +1,73 @@
+package rollup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	l1types "github.com/ethereum/go-ethereum/core/types"
+)
+
+// fileHeaderSource serves the L1 headers from a JSON file with an array of headers.
+// The file is re-read on every call, so it can be rewritten to extend the chain or to simulate reorgs.
+type fileHeaderSource struct {
+	path string
+}
+
+var _ L1HeaderSource = (*fileHeaderSource)(nil)
+
+func newFileHeaderSource(path string) *fileHeaderSource {
+	return &fileHeaderSource{path: path}
+}
+
+func (s *fileHeaderSource) HeaderByNumber(_ context.Context, number *big.Int) (*l1types.Header, error) {
+	headers, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *l1types.Header
+	for _, header := range headers {
+		if number == nil {
+			if ret == nil || header.Number.Cmp(ret.Number) > 0 {
+				ret = header
+			}
+		} else if header.Number.Cmp(number) == 0 {
+			ret = header
+		}
+	}
+	if ret == nil {
+		return nil, fmt.Errorf("%w: number %s in %s", ErrL1HeaderNotFound, number, s.path)
+	}
+	return ret, nil
+}
+
+func (s *fileHeaderSource) HeaderByHash(_ context.Context, hash common.Hash) (*l1types.Header, error) {
+	headers, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, header := range headers {
+		if header.Hash() == hash {
+			return header, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: hash %s in %s", ErrL1HeaderNotFound, hash, s.path)
+}
+
+func (s *fileHeaderSource) load() ([]*l1types.Header, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read L1 headers file: %w", err)
+	}
+
+	var headers []*l1types.Header
+	if err := json.Unmarshal(data, &headers); err != nil {
+		return nil, fmt.Errorf("failed to parse L1 headers file %s: %w", s.path, err)
+	}
+	return headers, nil
+}
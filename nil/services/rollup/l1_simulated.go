@@ -0,0 +1,124 @@
+package rollup
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	l1types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/jonboulle/clockwork"
+)
+
+const simulatedL1BaseFee = 1_000_000_000
+
+// SimulatedL1 is a deterministic in-memory L1 chain for devnets and tests.
+// If the block time is set, the chain grows by a block per block time since the genesis,
+// otherwise the blocks are produced only by Mine.
+type SimulatedL1 struct {
+	mu sync.Mutex
+
+	clock       clockwork.Clock
+	blockTime   time.Duration
+	genesisTime time.Time
+
+	canonical []*l1types.Header
+	byHash    map[common.Hash]*l1types.Header
+	// forks counts reorgs, it makes the headers of the new branches differ from the replaced ones
+	forks uint64
+}
+
+var _ L1HeaderSource = (*SimulatedL1)(nil)
+
+func NewSimulatedL1(clock clockwork.Clock, blockTime time.Duration) *SimulatedL1 {
+	s := &SimulatedL1{
+		clock:       clock,
+		blockTime:   blockTime,
+		genesisTime: clock.Now(),
+		byHash:      make(map[common.Hash]*l1types.Header),
+	}
+	s.add(s.newHeader(nil))
+	return s
+}
+
+// Mine appends n blocks to the canonical chain.
+func (s *SimulatedL1) Mine(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mine(n)
+}
+
+// Reorg replaces the last depth canonical blocks with n new ones.
+func (s *SimulatedL1) Reorg(depth, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the genesis is never replaced
+	depth = min(depth, len(s.canonical)-1)
+	s.canonical = s.canonical[:len(s.canonical)-depth]
+	s.forks++
+	s.mine(n)
+}
+
+func (s *SimulatedL1) HeaderByNumber(_ context.Context, number *big.Int) (*l1types.Header, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.blockTime > 0 {
+		height := int(s.clock.Since(s.genesisTime) / s.blockTime)
+		s.mine(height + 1 - len(s.canonical))
+	}
+
+	if number == nil {
+		return s.canonical[len(s.canonical)-1], nil
+	}
+	if !number.IsUint64() || number.Uint64() >= uint64(len(s.canonical)) {
+		return nil, fmt.Errorf("%w: number %s", ErrL1HeaderNotFound, number)
+	}
+	return s.canonical[number.Uint64()], nil
+}
+
+func (s *SimulatedL1) HeaderByHash(_ context.Context, hash common.Hash) (*l1types.Header, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	header, ok := s.byHash[hash]
+	if !ok {
+		return nil, fmt.Errorf("%w: hash %s", ErrL1HeaderNotFound, hash)
+	}
+	return header, nil
+}
+
+func (s *SimulatedL1) mine(n int) {
+	for range n {
+		s.add(s.newHeader(s.canonical[len(s.canonical)-1]))
+	}
+}
+
+func (s *SimulatedL1) add(header *l1types.Header) {
+	s.canonical = append(s.canonical, header)
+	s.byHash[header.Hash()] = header
+}
+
+func (s *SimulatedL1) newHeader(parent *l1types.Header) *l1types.Header {
+	excessBlobGas := uint64(0)
+	header := &l1types.Header{
+		Number:        big.NewInt(0),
+		Difficulty:    big.NewInt(0),
+		GasLimit:      30_000_000,
+		Time:          uint64(s.genesisTime.Unix()),
+		BaseFee:       big.NewInt(simulatedL1BaseFee),
+		ExcessBlobGas: &excessBlobGas,
+		Extra:         binary.BigEndian.AppendUint64(nil, s.forks),
+	}
+	if parent != nil {
+		header.ParentHash = parent.Hash()
+		header.Number = new(big.Int).Add(parent.Number, big.NewInt(1))
+		header.Time = parent.Time + uint64(max(s.blockTime, time.Second)/time.Second)
+	}
+	return header
+}
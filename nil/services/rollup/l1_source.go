@@ -0,0 +1,104 @@
+package rollup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/ethereum/go-ethereum/common"
+	l1types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var ErrL1HeaderNotFound = errors.New("L1 header not found")
+
+// L1HeaderSource provides the headers of the L1 chain.
+type L1HeaderSource interface {
+	// HeaderByNumber returns the canonical header with the given number, or the latest one if the number is nil.
+	HeaderByNumber(ctx context.Context, number *big.Int) (*l1types.Header, error)
+	// HeaderByHash returns the header with the given hash.
+	HeaderByHash(ctx context.Context, hash common.Hash) (*l1types.Header, error)
+}
+
+// rpcHeaderSource fetches headers from the list of L1 endpoints, switching to the next one on failures.
+// It is not safe for concurrent use.
+type rpcHeaderSource struct {
+	endpoints []string
+	client    *ethclient.Client
+	nodeIndex int
+	logger    logging.Logger
+}
+
+var _ L1HeaderSource = (*rpcHeaderSource)(nil)
+
+func newRpcHeaderSource(endpoints []string, logger logging.Logger) *rpcHeaderSource {
+	return &rpcHeaderSource{
+		endpoints: endpoints,
+		logger:    logger,
+	}
+}
+
+func (s *rpcHeaderSource) HeaderByNumber(ctx context.Context, number *big.Int) (*l1types.Header, error) {
+	return s.call(ctx, func(client *ethclient.Client) (*l1types.Header, error) {
+		return client.HeaderByNumber(ctx, number)
+	})
+}
+
+func (s *rpcHeaderSource) HeaderByHash(ctx context.Context, hash common.Hash) (*l1types.Header, error) {
+	return s.call(ctx, func(client *ethclient.Client) (*l1types.Header, error) {
+		return client.HeaderByHash(ctx, hash)
+	})
+}
+
+func (s *rpcHeaderSource) call(
+	ctx context.Context, fetch func(*ethclient.Client) (*l1types.Header, error),
+) (*l1types.Header, error) {
+	if s.client == nil {
+		if err := s.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	header, err := fetch(s.client)
+	if err != nil {
+		s.logger.Warn().
+			Err(err).
+			Str("node", s.endpoints[s.nodeIndex]).
+			Msg("failed to get L1 header")
+		if err := s.switchNode(ctx); err != nil {
+			s.logger.Error().Err(err).Msg("failed to switch node")
+		}
+		return nil, err
+	}
+	return header, nil
+}
+
+func (s *rpcHeaderSource) connect(ctx context.Context) error {
+	if s.client != nil {
+		s.client.Close()
+		s.client = nil
+	}
+	client, err := ethclient.DialContext(ctx, s.endpoints[s.nodeIndex])
+	if err != nil {
+		return fmt.Errorf("failed to connect to L1: %w", err)
+	}
+	s.client = client
+	return nil
+}
+
+func (s *rpcHeaderSource) switchNode(ctx context.Context) error {
+	initialIndex := s.nodeIndex
+	for {
+		s.nodeIndex = (s.nodeIndex + 1) % len(s.endpoints)
+		s.logger.Info().Msgf("Switch to new provider: %s", s.endpoints[s.nodeIndex])
+		err := s.connect(ctx)
+		if err == nil {
+			return nil
+		}
+		if s.nodeIndex == initialIndex {
+			return fmt.Errorf("all nodes are down, last error: %w", err)
+		}
+	}
+}
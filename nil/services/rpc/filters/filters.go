@@ -26,6 +26,16 @@ import (
 
 var logger = logging.NewLogger("filters")
 
+const (
+	filterBufferSize = 100
+
+	// liveBufferSize is the number of pending events a subscriber of a live manager may lag behind
+	// before it is closed.
+	liveBufferSize = 1024
+)
+
+var errFilterOverflow = errors.New("filter buffer is full")
+
 type MetaLog struct {
 	Log     *types.Log
 	BlockId types.BlockNumber
@@ -39,7 +49,7 @@ type Filter struct {
 // FilterQuery contains options for contract log filtering.
 type FilterQuery struct {
 	BlockHash *common.Hash    // used by eth_getLogs, return logs only from block with this hash
-	FromBlock *uint256.Int    // beginning of the queried range, nil means genesis block
+	FromBlock *uint256.Int    // beginning of the queried range, nil means a block tag such as "latest"
 	ToBlock   *uint256.Int    // end of the range, nil means the latest block
 	Addresses []types.Address // restricts matches to events created by specific contracts
 
@@ -70,23 +80,62 @@ type FiltersManager struct {
 	mutex     sync.RWMutex
 	lastHash  common.Hash
 	wg        sync.WaitGroup
+
+	// live managers serve subscriptions: they deliver new blocks in ascending order and close a filter
+	// or a blocks listener whose buffer is full instead of waiting for it.
+	live bool
 }
 
 func NewFiltersManager(ctx context.Context, db db.ReadOnlyDB, noPolling bool) *FiltersManager {
-	f := &FiltersManager{
+	f := newFiltersManager(ctx, db, types.MainShardId)
+	if !noPolling {
+		f.startPolling()
+	}
+	return f
+}
+
+// NewShardFiltersManager creates a live manager that tracks new blocks and logs of the given shard.
+// Only the blocks committed after the current head are delivered, in ascending order.
+// A filter or a blocks listener that falls behind by a full buffer is closed and removed.
+func NewShardFiltersManager(
+	ctx context.Context, database db.ReadOnlyDB, shardId types.ShardId,
+) (*FiltersManager, error) {
+	f := newFiltersManager(ctx, database, shardId)
+	f.live = true
+
+	lastHash, err := f.getLastBlockHash()
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		f.lastHash = lastHash
+	}
+
+	f.startPolling()
+	return f, nil
+}
+
+func newFiltersManager(ctx context.Context, db db.ReadOnlyDB, shardId types.ShardId) *FiltersManager {
+	return &FiltersManager{
 		ctx:       ctx,
 		db:        db,
+		shardId:   shardId,
 		filters:   make(map[SubscriptionID]*Filter),
 		blockSubs: make(map[SubscriptionID]chan<- *types.Block),
 		lastHash:  common.EmptyHash,
 	}
+}
 
-	if !noPolling {
-		f.wg.Add(1)
-		go f.PollBlocks(200 * time.Millisecond)
-	}
+func (m *FiltersManager) startPolling() {
+	m.wg.Add(1)
+	go m.PollBlocks(200 * time.Millisecond)
+}
 
-	return f
+func (m *FiltersManager) bufferSize() int {
+	if m.live {
+		return liveBufferSize
+	}
+	return filterBufferSize
 }
 
 func (m *FiltersManager) WaitForShutdown() {
@@ -103,7 +152,7 @@ func (m *FiltersManager) NewFilter(query *FilterQuery) (SubscriptionID, *Filter)
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	filter := &Filter{query: query, output: make(chan *MetaLog, 100)}
+	filter := &Filter{query: query, output: make(chan *MetaLog, m.bufferSize())}
 	m.filters[id] = filter
 
 	if query.FromBlock != nil || query.ToBlock != nil {
@@ -130,7 +179,7 @@ func (m *FiltersManager) RemoveFilter(id SubscriptionID) bool {
 
 func (m *FiltersManager) AddBlocksListener() (SubscriptionID, <-chan *types.Block) {
 	id := generateSubscriptionID()
-	ch := make(chan *types.Block, 100)
+	ch := make(chan *types.Block, m.bufferSize())
 
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -170,30 +219,67 @@ func (m *FiltersManager) PollBlocks(delay time.Duration) {
 
 		if m.lastHash != lastHash {
 			m.mutex.Lock()
-			for currHash := lastHash; m.lastHash != currHash; {
-				block, err := m.processBlockHash(currHash)
-				if err != nil {
-					logger.Warn().Err(err).Msg("processBlockHash failed")
-					continue
-				}
-				for _, ch := range m.blockSubs {
-					// Don't send if the channel is full.
-					// Probably subscriber just disconnected, and it shouldn't block us.
-					if len(ch) < cap(ch) {
-						ch <- block
-					}
-				}
-				currHash = block.PrevBlock
-				if currHash == common.EmptyHash {
-					break
-				}
+			if err := m.processNewBlocks(lastHash); err != nil {
+				logger.Warn().Err(err).Msg("processNewBlocks failed")
 			}
-			m.lastHash = lastHash
 			m.mutex.Unlock()
 		}
 	}
 }
 
+type blockWithReceipts struct {
+	block    *types.Block
+	receipts types.Receipts
+}
+
+// processNewBlocks delivers the blocks from the last processed one up to lastHash to the filters and the listeners.
+// The blocks are read before anything is delivered, so a failed read is retried on the next poll without duplicates.
+func (m *FiltersManager) processNewBlocks(lastHash common.Hash) error {
+	tx, err := m.db.CreateRoTx(m.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var blocks []blockWithReceipts
+	for currHash := lastHash; currHash != m.lastHash && currHash != common.EmptyHash; {
+		block, err := db.ReadBlock(tx, m.shardId, currHash)
+		if err != nil {
+			return err
+		}
+		receipts, err := m.readReceipts(tx, block)
+		if err != nil {
+			return err
+		}
+		blocks = append(blocks, blockWithReceipts{block: block, receipts: receipts})
+		currHash = block.PrevBlock
+	}
+	if m.live {
+		slices.Reverse(blocks)
+	}
+
+	for _, b := range blocks {
+		if err := m.process(b.block, b.receipts); err != nil {
+			return err
+		}
+		for id, ch := range m.blockSubs {
+			if len(ch) < cap(ch) {
+				ch <- b.block
+				continue
+			}
+			// Don't send if the channel is full.
+			// Probably subscriber just disconnected, and it shouldn't block us.
+			// A live listener misses the block, so it is closed to let the subscriber know.
+			if m.live {
+				close(ch)
+				delete(m.blockSubs, id)
+			}
+		}
+	}
+	m.lastHash = lastHash
+	return nil
+}
+
 // / If FromBlock is set in the filter, then processBlocksRange processes all blocks in the range [FromBlock..ToBlock].
 func (m *FiltersManager) processBlocksRange(filter *Filter) error {
 	tx, err := m.db.CreateRoTx(m.ctx)
@@ -242,26 +328,6 @@ func (m *FiltersManager) readReceipts(tx db.RoTx, block *types.Block) ([]*types.
 	return reader.Values()
 }
 
-func (m *FiltersManager) processBlockHash(lastHash common.Hash) (*types.Block, error) {
-	tx, err := m.db.CreateRoTx(m.ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer tx.Rollback()
-
-	block, err := db.ReadBlock(tx, m.shardId, lastHash)
-	if err != nil {
-		return nil, err
-	}
-
-	receipts, err := m.readReceipts(tx, block)
-	if err != nil {
-		return nil, err
-	}
-
-	return block, m.process(block, receipts)
-}
-
 func (m *FiltersManager) processFilter(block *types.Block, filter *Filter, receipts types.Receipts) error {
 	if filter.query.ToBlock != nil && uint64(block.Id) > filter.query.ToBlock.Uint64() {
 		return nil
@@ -277,31 +343,44 @@ func (m *FiltersManager) processFilter(block *types.Block, filter *Filter, recei
 					found = false
 					break
 				}
-				switch len(topics) {
-				case 0:
-					continue
-				case 1: // valid case, process after switch block
-				default:
-					panic("TODO: Topics disjunction isn't supported yet")
-				}
-
-				logTopic := log.Topics[i]
-				if logTopic != topics[0] {
+				if len(topics) > 0 && !slices.Contains(topics, log.Topics[i]) {
 					found = false
 					break
 				}
 			}
 			if found {
-				filter.output <- &MetaLog{log, block.Id}
+				if err := m.send(filter, &MetaLog{log, block.Id}); err != nil {
+					return err
+				}
 			}
 		}
 	}
 	return nil
 }
 
+// send delivers the log to the filter. A live manager never waits for the filter's reader:
+// it fails with errFilterOverflow if the buffer is full.
+func (m *FiltersManager) send(filter *Filter, log *MetaLog) error {
+	if !m.live {
+		filter.output <- log
+		return nil
+	}
+	select {
+	case filter.output <- log:
+		return nil
+	default:
+		return errFilterOverflow
+	}
+}
+
 func (m *FiltersManager) process(block *types.Block, receipts types.Receipts) error {
-	for _, filter := range m.filters {
+	for id, filter := range m.filters {
 		err := m.processFilter(block, filter, receipts)
+		if errors.Is(err, errFilterOverflow) {
+			close(filter.output)
+			delete(m.filters, id)
+			continue
+		}
 		if err != nil {
 			return err
 		}
@@ -365,11 +444,12 @@ func (args *FilterQuery) UnmarshalJSON(data []byte) error {
 		}
 		args.BlockHash = raw.BlockHash
 	} else {
-		if raw.FromBlock != nil {
+		// Block tags ("latest", "pending", etc.) are left unset and resolve to the latest block.
+		if raw.FromBlock != nil && !raw.FromBlock.IsSpecial() {
 			args.FromBlock = uint256.NewInt(raw.FromBlock.Uint64())
 		}
 
-		if raw.ToBlock != nil {
+		if raw.ToBlock != nil && !raw.ToBlock.IsSpecial() {
 			args.ToBlock = uint256.NewInt(raw.ToBlock.Uint64())
 		}
 	}
@@ -446,6 +526,39 @@ func (args *FilterQuery) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalJSON encodes the filter query in the format accepted by UnmarshalJSON.
+func (args FilterQuery) MarshalJSON() ([]byte, error) {
+	type output struct {
+		BlockHash *common.Hash           `json:"blockHash,omitempty"`
+		FromBlock *transport.BlockNumber `json:"fromBlock,omitempty"`
+		ToBlock   *transport.BlockNumber `json:"toBlock,omitempty"`
+		Addresses []types.Address        `json:"address,omitempty"`
+		Topics    []any                  `json:"topics,omitempty"`
+	}
+
+	out := output{
+		BlockHash: args.BlockHash,
+		Addresses: args.Addresses,
+	}
+	if args.FromBlock != nil {
+		from := transport.BlockNumber(args.FromBlock.Uint64())
+		out.FromBlock = &from
+	}
+	if args.ToBlock != nil {
+		to := transport.BlockNumber(args.ToBlock.Uint64())
+		out.ToBlock = &to
+	}
+	if len(args.Topics) > 0 {
+		out.Topics = make([]any, len(args.Topics))
+		for i, topics := range args.Topics {
+			if len(topics) > 0 {
+				out.Topics[i] = topics
+			}
+		}
+	}
+	return json.Marshal(out)
+}
+
 func decodeAddress(s string) (types.Address, error) {
 	b, err := hexutil.Decode(s)
 	if err == nil && len(b) != types.AddrSize {
@@ -1,7 +1,11 @@
 package httpcfg
 
 import (
+	"fmt"
+	"maps"
 	"time"
+
+	"github.com/NilFoundation/nil/nil/services/rpc/transport/rpccfg"
 )
 
 // HTTPTimeouts represents the configuration params for the HTTP RPC server.
@@ -36,6 +40,76 @@ var DefaultHTTPTimeouts = HTTPTimeouts{
 	IdleTimeout:  120 * time.Second,
 }
 
+// LimitsCfg restricts the resources the RPC clients can consume.
+type LimitsCfg struct {
+	// RateLimit is the number of request cost units replenished per second for each client.
+	// Zero disables the rate limiting.
+	RateLimit float64 `yaml:"rateLimit,omitempty"`
+	// RateBurst is the maximum number of cost units a client can spend at once.
+	// By default, it's enough for a second of requests or for the most expensive method, whichever is larger.
+	RateBurst int `yaml:"rateBurst,omitempty"`
+	// ClientKeyHeader is the header with the API key identifying the client.
+	// The client IP is used if the header is not configured, not present in the request
+	// or holds a key that is not listed in ClientKeys.
+	ClientKeyHeader string `yaml:"clientKeyHeader,omitempty"`
+	// ClientKeys are the API keys that get their own rate limit budget.
+	ClientKeys []string `yaml:"clientKeys,omitempty"`
+	// ClientIpHeader is the header with the client IP set by a trusted reverse proxy (e.g., X-Real-IP).
+	// It's used instead of the remote address if present, so set it only if the server is reachable
+	// through the proxy alone: the clients can set any value otherwise.
+	ClientIpHeader string `yaml:"clientIpHeader,omitempty"`
+	// MethodCosts overrides the costs of the methods, see rpccfg.MethodCosts for the defaults.
+	MethodCosts map[string]int `yaml:"methodCosts,omitempty"`
+
+	// MaxResponseSize is the maximum size of a call response in bytes. Zero means unlimited.
+	MaxResponseSize int `yaml:"maxResponseSize,omitempty"`
+	// MethodTimeouts limits the execution time of the methods.
+	MethodTimeouts map[string]time.Duration `yaml:"methodTimeouts,omitempty"`
+	// MaxConnRequests is the maximum number of requests processed concurrently for a single streaming
+	// (e.g., WebSocket) connection, the following ones are not read until some of them are done.
+	// DefaultMaxConnRequests is used if it's not set.
+	MaxConnRequests int `yaml:"maxConnRequests,omitempty"`
+}
+
+// DefaultMaxConnRequests is the default limit of requests processed concurrently for a single connection.
+const DefaultMaxConnRequests = 16
+
+// ConnRequests returns the maximum number of requests processed concurrently for a single connection.
+func (c *LimitsCfg) ConnRequests() int {
+	if c.MaxConnRequests <= 0 {
+		return DefaultMaxConnRequests
+	}
+	return c.MaxConnRequests
+}
+
+// Costs returns the rate limiting costs of the methods, the configured ones override rpccfg.MethodCosts.
+func (c *LimitsCfg) Costs() map[string]int {
+	costs := make(map[string]int, len(rpccfg.MethodCosts)+len(c.MethodCosts))
+	maps.Copy(costs, rpccfg.MethodCosts)
+	maps.Copy(costs, c.MethodCosts)
+	return costs
+}
+
+// MaxCost returns the cost of the most expensive method.
+func (c *LimitsCfg) MaxCost() int {
+	maxCost := rpccfg.DefaultMethodCost
+	for _, cost := range c.Costs() {
+		maxCost = max(maxCost, cost)
+	}
+	return maxCost
+}
+
+// Validate checks that the burst is enough for every method, a method costing more could never be called.
+func (c *LimitsCfg) Validate() error {
+	if c.RateLimit <= 0 || c.RateBurst <= 0 {
+		return nil
+	}
+	if maxCost := c.MaxCost(); c.RateBurst < maxCost {
+		return fmt.Errorf("rate burst %d is less than the highest method cost %d", c.RateBurst, maxCost)
+	}
+	return nil
+}
+
 type HttpCfg struct {
 	HttpURL         string
 	HttpCORSDomain  []string
@@ -48,4 +122,8 @@ type HttpCfg struct {
 	RPCSlowLogThreshold time.Duration
 
 	KeepHeaders []string // List of headers to pass to the request handler
+
+	WSEnabled bool // Accept WebSocket connections on the HTTP endpoint
+
+	Limits LimitsCfg // Per-client rate limits, response size and execution time limits
 }
@@ -2,6 +2,7 @@ package jsonrpc
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -26,6 +27,18 @@ type DebugAPI interface {
 		contractAddr types.Address,
 		blockNrOrHash transport.BlockNumberOrHash,
 	) (*DebugRPCContract, error)
+	TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (json.RawMessage, error)
+	TraceCall(
+		ctx context.Context,
+		args CallArgs,
+		mainBlockNrOrHash transport.BlockNumberOrHash,
+		overrides *StateOverrides,
+		config *TraceConfig,
+	) (json.RawMessage, error)
+	GetDoubleSignEvidence(
+		ctx context.Context, shardId types.ShardId, fromHeight uint64) ([]*DebugRPCDoubleSignEvidence, error)
+	GetStateDiff(
+		ctx context.Context, shardId types.ShardId, from, to common.Hash) ([]*DebugRPCContractDiff, error)
 }
 
 type DebugAPIImpl struct {
@@ -121,3 +134,73 @@ func (api *DebugAPIImpl) GetContract(
 		AsyncContext: contract.AsyncContext,
 	}, nil
 }
+
+// TraceTransaction implements debug_traceTransaction.
+// Re-executes a committed transaction on top of the state of the parent block and returns its trace.
+func (api *DebugAPIImpl) TraceTransaction(
+	ctx context.Context,
+	hash common.Hash,
+	config *TraceConfig,
+) (json.RawMessage, error) {
+	return api.rawApi.TraceTransaction(ctx, types.ShardIdFromHash(hash), hash, config)
+}
+
+// TraceCall implements debug_traceCall.
+// Executes a transaction call the same way as eth_call does and returns its trace.
+func (api *DebugAPIImpl) TraceCall(
+	ctx context.Context,
+	args CallArgs,
+	mainBlockNrOrHash transport.BlockNumberOrHash,
+	overrides *StateOverrides,
+	config *TraceConfig,
+) (json.RawMessage, error) {
+	blockRef := rawapitypes.BlockReferenceAsBlockReferenceOrHashWithChildren(toBlockReference(mainBlockNrOrHash))
+	if args.Fee.FeeCredit.IsZero() {
+		args.Fee = types.NewFeePackFromGas(1_000_000_000_000_000_000)
+	}
+	return api.rawApi.TraceCall(ctx, args, blockRef, overrides, config)
+}
+
+// GetDoubleSignEvidence implements debug_getDoubleSignEvidence.
+// Returns the evidence of validators' equivocation observed by the node starting from the given height.
+func (api *DebugAPIImpl) GetDoubleSignEvidence(
+	ctx context.Context,
+	shardId types.ShardId,
+	fromHeight uint64,
+) ([]*DebugRPCDoubleSignEvidence, error) {
+	evidence, err := api.rawApi.GetDoubleSignEvidence(ctx, shardId, fromHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*DebugRPCDoubleSignEvidence, len(evidence))
+	for i, e := range evidence {
+		data, err := e.MarshalSSZ()
+		if err != nil {
+			return nil, err
+		}
+		res[i] = &DebugRPCDoubleSignEvidence{DoubleSignEvidence: e, Data: data}
+	}
+	return res, nil
+}
+
+// GetStateDiff implements debug_getStateDiff.
+// Returns the contracts changed in the shard by the blocks after `from` up to `to` inclusive.
+// The empty `from` hash means the empty state.
+func (api *DebugAPIImpl) GetStateDiff(
+	ctx context.Context,
+	shardId types.ShardId,
+	from common.Hash,
+	to common.Hash,
+) ([]*DebugRPCContractDiff, error) {
+	diff, err := api.rawApi.GetStateDiff(ctx, shardId, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*DebugRPCContractDiff, len(diff))
+	for i, d := range diff {
+		res[i] = &DebugRPCContractDiff{ContractDiff: d}
+	}
+	return res, nil
+}
@@ -2,14 +2,17 @@ package jsonrpc
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
@@ -214,3 +217,159 @@ func TestSuiteDbgContracts(t *testing.T) {
 
 	suite.Run(t, new(SuiteDbgContracts))
 }
+
+type SuiteDbgTrace struct {
+	suite.Suite
+	db       db.DB
+	debugApi *DebugAPIImpl
+
+	contract types.Address
+	callHash common.Hash
+}
+
+// counterCode increments the slot 0 of the contract.
+const counterCode = "60005460010160005500"
+
+func (s *SuiteDbgTrace) SetupSuite() {
+	shardId := types.BaseShardId
+
+	var err error
+	s.db, err = db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+
+	mainBlockHash := execution.GenerateBlockFromTransactions(
+		s.T(), types.MainShardId, 0, common.EmptyHash, s.db, nil)
+	shardBlockHash := execution.GenerateBlockFromTransactions(s.T(), shardId, 0, common.EmptyHash, s.db, nil)
+
+	// The init code returns the runtime code that follows it.
+	initCode := hexutil.FromHex("600a600c600039600a6000f3" + counterCode)
+	deployTxn := execution.NewDeployTransaction(
+		types.BuildDeployPayload(initCode, common.EmptyHash),
+		shardId,
+		types.GenerateRandomAddress(shardId),
+		0,
+		types.Value{})
+	deployTxn.RefundTo = deployTxn.From
+	s.contract = deployTxn.To
+
+	// The call is sent from another shard, so that it doesn't share the transaction ids with the deployment.
+	callTxn := execution.NewExecutionTransaction(types.GenerateRandomAddress(types.MainShardId), s.contract, 0, nil)
+	callTxn.Flags = types.NewTransactionFlags(types.TransactionFlagInternal)
+	callTxn.RefundTo = callTxn.From
+
+	shardBlockHash = execution.GenerateBlockFromTransactions(
+		s.T(), shardId, 1, shardBlockHash, s.db, nil, deployTxn, callTxn)
+	// The transaction id is assigned during the block generation.
+	s.callHash = callTxn.Hash()
+	execution.GenerateBlockFromTransactions(
+		s.T(), types.MainShardId, 1, mainBlockHash, s.db, map[types.ShardId]common.Hash{shardId: shardBlockHash})
+
+	s.debugApi = NewDebugAPI(
+		rawapi.NodeApiBuilder(s.db, nil).
+			WithLocalShardApiRo(types.MainShardId).
+			WithLocalShardApiRo(shardId).
+			BuildAndReset(),
+		logging.NewLogger("Test"))
+}
+
+func (s *SuiteDbgTrace) TearDownSuite() {
+	s.db.Close()
+}
+
+func (s *SuiteDbgTrace) TestTraceCall() {
+	ctx := s.T().Context()
+
+	args := CallArgs{
+		To:  s.contract,
+		Fee: types.NewFeePackFromGas(100_000),
+	}
+	latest := transport.BlockNumberOrHash{BlockNumber: transport.LatestBlock.BlockNumber}
+
+	s.Run("StructLogger", func() {
+		data, err := s.debugApi.TraceCall(ctx, args, latest, nil, nil)
+		s.Require().NoError(err)
+
+		var res tracers.StructLoggerResult
+		s.Require().NoError(json.Unmarshal(data, &res))
+		s.False(res.Failed)
+		s.Require().Len(res.StructLogs, 7)
+		s.Equal("PUSH1", res.StructLogs[0].Op)
+		s.Equal("SLOAD", res.StructLogs[1].Op)
+		s.Equal(map[common.Hash]common.Hash{{}: common.IntToHash(1)}, res.StructLogs[1].Storage)
+		s.Equal("SSTORE", res.StructLogs[5].Op)
+		s.Equal(map[common.Hash]common.Hash{{}: common.IntToHash(2)}, res.StructLogs[5].Storage)
+		s.Equal("STOP", res.StructLogs[6].Op)
+	})
+
+	s.Run("Limit", func() {
+		data, err := s.debugApi.TraceCall(ctx, args, latest, nil, &TraceConfig{Limit: 2, DisableStack: true})
+		s.Require().NoError(err)
+
+		var res tracers.StructLoggerResult
+		s.Require().NoError(json.Unmarshal(data, &res))
+		s.Require().Len(res.StructLogs, 2)
+		s.Empty(res.StructLogs[1].Stack)
+	})
+
+	s.Run("CallTracer", func() {
+		data, err := s.debugApi.TraceCall(ctx, args, latest, nil, &TraceConfig{Tracer: tracers.CallTracerName})
+		s.Require().NoError(err)
+
+		var res tracers.CallFrame
+		s.Require().NoError(json.Unmarshal(data, &res))
+		s.Equal(s.contract, res.To)
+		s.NotZero(res.GasUsed)
+		s.Empty(res.Error)
+	})
+
+	s.Run("PrestateTracer", func() {
+		data, err := s.debugApi.TraceCall(ctx, args, latest, nil, &TraceConfig{
+			Tracer:       tracers.PrestateTracerName,
+			TracerConfig: json.RawMessage(`{"diffMode": true}`),
+		})
+		s.Require().NoError(err)
+
+		var res tracers.PrestateDiff
+		s.Require().NoError(json.Unmarshal(data, &res))
+		s.Require().Contains(res.Pre, s.contract)
+		s.Require().Contains(res.Post, s.contract)
+		s.Equal(map[common.Hash]common.Hash{{}: common.IntToHash(1)}, res.Pre[s.contract].Storage)
+		s.Equal(map[common.Hash]common.Hash{{}: common.IntToHash(2)}, res.Post[s.contract].Storage)
+	})
+
+	s.Run("UnknownTracer", func() {
+		_, err := s.debugApi.TraceCall(ctx, args, latest, nil, &TraceConfig{Tracer: "unknown"})
+		s.Require().ErrorContains(err, tracers.ErrUnknownTracer.Error())
+	})
+}
+
+func (s *SuiteDbgTrace) TestTraceTransaction() {
+	ctx := s.T().Context()
+
+	data, err := s.debugApi.TraceTransaction(ctx, s.callHash, &TraceConfig{Tracer: tracers.CallTracerName})
+	s.Require().NoError(err)
+
+	var res tracers.CallFrame
+	s.Require().NoError(json.Unmarshal(data, &res))
+	s.Equal(s.contract, res.To)
+	s.Empty(res.Error)
+
+	data, err = s.debugApi.TraceTransaction(ctx, s.callHash, nil)
+	s.Require().NoError(err)
+
+	var logs tracers.StructLoggerResult
+	s.Require().NoError(json.Unmarshal(data, &logs))
+	// The transaction sees the state before its block, so the counter is incremented from zero.
+	s.Require().Len(logs.StructLogs, 7)
+	s.Equal("SSTORE", logs.StructLogs[5].Op)
+	s.Equal(map[common.Hash]common.Hash{{}: common.IntToHash(1)}, logs.StructLogs[5].Storage)
+
+	_, err = s.debugApi.TraceTransaction(ctx, common.Hash{0x1}, nil)
+	s.Require().Error(err)
+}
+
+func TestSuiteDbgTrace(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(SuiteDbgTrace))
+}
@@ -3,6 +3,9 @@ package jsonrpc
 // @component Address address string "The address of the account or contract."
 // @component TransactionCount transactionCount integer "The transaction count of the account."
 // @component ContractBytecode contractBytecode string "The bytecode of the contract."
+// @component StorageKey key string "The key of the storage slot."
+// @component StorageKeys keys array "The keys of the storage slots to be proven."
+// @component StorageValue value string "The value of the storage slot."
 // @component Balance balance integer "The balance of the account."
 // @component BlockShardId shardId integer "The ID of the shard where the block was generated."
 // @component TransactionShardId shardId integer "The ID of the shard where the transaction was recorded."
@@ -15,8 +18,8 @@ package jsonrpc
 // @component Encoded encoded string "The encoded bytecode of the transaction."
 // @component FilterQuery filterQuery object "The query structure of the filter."
 // @componentprop BlockHash blockHash string false "The hash of the blocks whose logs should be retrieved by the filter."
-// @componentprop FromBlock fromBlock integer false "The beginning of the range of the blocks whose logs should be retrieved by the filter."
-// @componentprop ToBlock toBlock integer false "The end of the range of the blocks whose logs should be retrieved by the filter."
+// @componentprop FromBlock fromBlock integer false "The beginning of the range of the blocks whose logs should be retrieved by the filter. Defaults to the latest block."
+// @componentprop ToBlock toBlock integer false "The end of the range of the blocks whose logs should be retrieved by the filter. Defaults to the latest block."
 // @componentprop Addresses addresses array true "The addresses of the accounts/contracts the logs for whose events should be retrieved by the filter."
 // @componentprop Topics topics array true "The topics of the events whose lgos should be retrieved by the filter."
 // @component Value value integer "The amount of tokens."
@@ -25,13 +28,22 @@ package jsonrpc
 // @component UninstallFilterId id string "The ID of the filter that should be uninstalled."
 // @component FilterId id string "The ID of the filter."
 // @component FilterChanges filterChanges array "The array of logs, block headers or pending transactions that have occurred since the last poll of the filter."
+// @component LogsShardId shardId integer "The ID of the shard whose logs should be retrieved."
 // @component FilterLogs filterLogs array "The array of logs that have been recorded since the last poll of the filter."
+// @component Logs logs array "The array of logs matching the filter query."
+// @component SubscriptionKind kind string "The kind of the subscription: newHeads, logs or newPendingTransactions."
+// @component SubscriptionShardId shardId integer "The ID of the shard whose events should be delivered."
+// @component SubscriptionId subscriptionId string "The ID of the subscription."
 // @component ShardIds shardIds array "The array of shard IDs."
 // @component NumShards numShards integer "The number of shards."
 // @component GasShardId shardId integer "The ID of the shard whose gas price is requested."
 // @component BaseFee baseFee integer "The current base fee the given shard."
 // @component GasPrice gasPrice integer "The current gas price in the given shard."
+// @component FeeHistoryBlockCount blockCount integer "The number of blocks in the requested range."
+// @component RewardPercentiles rewardPercentiles array "The increasing percentiles of the gas used to sample the priority fees at."
+// @component MaxPriorityFeePerGas maxPriorityFeePerGas integer "The suggested priority fee per gas."
 // @component ChainId chainId integer "The chain ID of the network."
+// @component SyncShardId shardId integer "The ID of the shard whose sync progress is requested."
 // @component ReturnedValue returnedValue string "The returned value of the executed contract."
 // @component FullTx fullTx boolean "The flag that determines whether full transaction information is returned in the output."
 // @component BlockNumberOrHash blockNumberOrHash object "The number/hash of the block."
@@ -3,6 +3,7 @@ package jsonrpc
 import (
 	"context"
 
+	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/internal/types"
@@ -59,6 +60,30 @@ func (api *APIImplRo) GetCode(
 	return hexutil.Bytes(code), nil
 }
 
+// GetStorageAt implements eth_getStorageAt. Returns the value of a storage slot of the contract.
+func (api *APIImplRo) GetStorageAt(
+	ctx context.Context,
+	address types.Address,
+	key common.Hash,
+	blockNrOrHash transport.BlockNumberOrHash,
+) (common.Hash, error) {
+	return api.rawapi.GetStorageAt(ctx, address, key, toBlockReference(blockNrOrHash))
+}
+
+// GetProof implements eth_getProof. Returns the account and storage values together with their MPT proofs.
+func (api *APIImplRo) GetProof(
+	ctx context.Context,
+	address types.Address,
+	keys []common.Hash,
+	blockNrOrHash transport.BlockNumberOrHash,
+) (*RPCAccountProof, error) {
+	proof, err := api.rawapi.GetProof(ctx, address, keys, toBlockReference(blockNrOrHash))
+	if err != nil {
+		return nil, err
+	}
+	return NewRPCAccountProof(address, proof)
+}
+
 func blockNrToBlockReference(num transport.BlockNumber) rawapitypes.BlockReference {
 	var ref rawapitypes.BlockReference
 	if num <= 0 {
@@ -9,6 +9,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -24,7 +25,10 @@ type SuiteAccountsBase struct {
 
 type SuiteEthAccounts struct {
 	SuiteAccountsBase
-	api *APIImpl
+	api           *APIImpl
+	contractsRoot common.Hash
+	storageKey    common.Hash
+	storageValue  common.Hash
 }
 
 func (suite *SuiteAccountsBase) SetupSuite() {
@@ -63,9 +67,14 @@ func (suite *SuiteEthAccounts) SetupSuite() {
 	suite.Require().NoError(es.SetBalance(suite.smcAddr, types.NewValueFromUint64(1234)))
 	suite.Require().NoError(es.SetExtSeqno(suite.smcAddr, 567))
 
+	suite.storageKey = common.HexToHash("0x01")
+	suite.storageValue = common.HexToHash("0xdeadbeef")
+	suite.Require().NoError(es.SetState(suite.smcAddr, suite.storageKey, suite.storageValue))
+
 	blockRes, err := es.Commit(0, nil)
 	suite.Require().NoError(err)
 	suite.blockHash = blockRes.BlockHash
+	suite.contractsRoot = blockRes.Block.SmartContractsRoot
 
 	err = execution.PostprocessBlock(tx, shardId, blockRes, execution.ModeVerify)
 	suite.Require().NotNil(blockRes.Block)
@@ -187,6 +196,80 @@ func (suite *SuiteEthAccounts) TestGetSeqno() {
 	suite.Equal(hexutil.Uint64(1), res)
 }
 
+func (suite *SuiteEthAccounts) TestGetStorageAt() {
+	ctx := context.Background()
+
+	blockNum := transport.BlockNumberOrHash{BlockNumber: transport.LatestBlock.BlockNumber}
+	res, err := suite.api.GetStorageAt(ctx, suite.smcAddr, suite.storageKey, blockNum)
+	suite.Require().NoError(err)
+	suite.Equal(suite.storageValue, res)
+
+	blockHash := transport.BlockNumberOrHash{BlockHash: &suite.blockHash}
+	res, err = suite.api.GetStorageAt(ctx, suite.smcAddr, suite.storageKey, blockHash)
+	suite.Require().NoError(err)
+	suite.Equal(suite.storageValue, res)
+
+	res, err = suite.api.GetStorageAt(ctx, suite.smcAddr, common.HexToHash("0x02"), blockNum)
+	suite.Require().NoError(err)
+	suite.Equal(common.EmptyHash, res)
+
+	res, err = suite.api.GetStorageAt(ctx, types.GenerateRandomAddress(types.BaseShardId), suite.storageKey, blockNum)
+	suite.Require().NoError(err)
+	suite.Equal(common.EmptyHash, res)
+}
+
+func (suite *SuiteEthAccounts) TestGetProof() {
+	ctx := context.Background()
+	blockHash := transport.BlockNumberOrHash{BlockHash: &suite.blockHash}
+
+	verifyRead := func(encoded []byte, key []byte, value []byte, root common.Hash) {
+		suite.T().Helper()
+
+		proof, err := mpt.DecodeProof(encoded)
+		suite.Require().NoError(err)
+		ok, err := proof.VerifyRead(key, value, root)
+		suite.Require().NoError(err)
+		suite.True(ok)
+	}
+
+	suite.Run("Exists", func() {
+		emptyKey := common.HexToHash("0x02")
+		res, err := suite.api.GetProof(ctx, suite.smcAddr, []common.Hash{suite.storageKey, emptyKey}, blockHash)
+		suite.Require().NoError(err)
+
+		suite.Equal(suite.smcAddr, res.Address)
+		suite.Equal(types.NewValueFromUint64(1234), res.Balance)
+		suite.Equal(hexutil.Uint64(567), res.ExtSeqno)
+		suite.Equal(types.Code("some code").Hash(), res.CodeHash)
+		verifyRead(res.AccountProof, suite.smcAddr.Hash().Bytes(), res.Contract, suite.contractsRoot)
+
+		suite.Require().Len(res.StorageProof, 2)
+		suite.Equal(suite.storageKey, res.StorageProof[0].Key)
+		suite.Equal(suite.storageValue, res.StorageProof[0].Value)
+		value, err := (*types.Uint256)(suite.storageValue.Uint256()).MarshalSSZ()
+		suite.Require().NoError(err)
+		verifyRead(res.StorageProof[0].Proof, suite.storageKey.Bytes(), value, res.StorageRoot)
+
+		suite.Equal(emptyKey, res.StorageProof[1].Key)
+		suite.Equal(common.EmptyHash, res.StorageProof[1].Value)
+		verifyRead(res.StorageProof[1].Proof, emptyKey.Bytes(), nil, res.StorageRoot)
+	})
+
+	suite.Run("Absent", func() {
+		addr := types.GenerateRandomAddress(types.BaseShardId)
+		res, err := suite.api.GetProof(ctx, addr, []common.Hash{suite.storageKey}, blockHash)
+		suite.Require().NoError(err)
+
+		suite.Empty(res.Contract)
+		suite.True(res.Balance.IsZero())
+		verifyRead(res.AccountProof, addr.Hash().Bytes(), nil, suite.contractsRoot)
+
+		suite.Require().Len(res.StorageProof, 1)
+		suite.Equal(common.EmptyHash, res.StorageProof[0].Value)
+		suite.Empty(res.StorageProof[0].Proof)
+	})
+}
+
 func TestSuiteEthAccounts(t *testing.T) {
 	t.Parallel()
 
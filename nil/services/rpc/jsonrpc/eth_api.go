@@ -12,6 +12,7 @@ import (
 	"github.com/NilFoundation/nil/nil/services/rpc/filters"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
 )
 
 type EthAPIRo interface {
@@ -153,6 +154,16 @@ type EthAPIRo interface {
 	*/
 	GetInTransactionReceipt(ctx context.Context, hash common.Hash) (*RPCReceipt, error)
 
+	/*
+		@name GetTransactionTree
+		@summary Returns the tree of the transactions spawned by the transaction with the given hash across all shards.
+		@description Implements eth_getTransactionTree. Follows the outbound transactions (including refunds and bounces) and their receipts. The status of the tree is "failed" if any of its transactions failed, "pending" if some of them are not executed yet or are not included in the main chain, and "settled" otherwise. Returns null if the root transaction is not executed yet.
+		@tags [Receipts]
+		@param hash TransactionHash
+		@returns transactionTree RPCTransactionTree
+	*/
+	GetTransactionTree(ctx context.Context, hash common.Hash) (*RPCTransactionTree, error)
+
 	/*
 		@name GetBalance
 		@summary Returns the balance of the account with the given address and at the given block.
@@ -175,6 +186,35 @@ type EthAPIRo interface {
 	*/
 	GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error)
 
+	/*
+		@name FeeHistory
+		@summary Returns the fee history of the range of blocks in the given shard.
+		@description Implements eth_feeHistory. Returns the base fees and the gas used ratios of up to 1024 blocks ending with the given one, and the priority fees paid by the external transactions of these blocks at the given percentiles of their gas used. The base fees include one more entry for the block following the newest one.
+		@tags [Transactions]
+		@param shardId GasShardId
+		@param blockCount FeeHistoryBlockCount
+		@param newestBlock BlockNumber
+		@param rewardPercentiles RewardPercentiles
+		@returns feeHistory FeeHistory
+	*/
+	FeeHistory(
+		ctx context.Context,
+		shardId types.ShardId,
+		blockCount uint64,
+		newestBlock transport.BlockNumber,
+		rewardPercentiles []float64,
+	) (*FeeHistory, error)
+
+	/*
+		@name MaxPriorityFeePerGas
+		@summary Returns the suggested priority fee per gas for the given shard.
+		@description Implements eth_maxPriorityFeePerGas. The suggestion is based on the priority fees paid by the external transactions of the latest blocks. Returns zero if there are no such transactions.
+		@tags [Transactions]
+		@param shardId GasShardId
+		@returns maxPriorityFeePerGas MaxPriorityFeePerGas
+	*/
+	MaxPriorityFeePerGas(ctx context.Context, shardId types.ShardId) (types.Value, error)
+
 	/*
 		@name GetTransactionCount
 		@summary Returns the transaction count of the account with the given address and at the given block.
@@ -199,6 +239,34 @@ type EthAPIRo interface {
 	GetCode(
 		ctx context.Context, address types.Address, blockNrOrHash transport.BlockNumberOrHash) (hexutil.Bytes, error)
 
+	/*
+		@name GetStorageAt
+		@summary Returns the value of the storage slot of the contract with the given address and at the given block.
+		@description Implements eth_getStorageAt. Returns zero if the slot or the contract doesn't exist.
+		@tags [Accounts]
+		@param address Address
+		@param key StorageKey
+		@param blockNumberOrHash BlockNumberOrHash
+		@returns value StorageValue
+	*/
+	GetStorageAt(
+		ctx context.Context, address types.Address, key common.Hash, blockNrOrHash transport.BlockNumberOrHash,
+	) (common.Hash, error)
+
+	/*
+		@name GetProof
+		@summary Returns the account and the given storage slots together with the Merkle proofs of their values.
+		@description Implements eth_getProof. The account proof is built against the contract trie root of the block, the storage proofs are built against the storage root of the account. If the account doesn't exist, the account proof proves its absence and the storage proofs are empty.
+		@tags [Accounts]
+		@param address Address
+		@param keys StorageKeys
+		@param blockNumberOrHash BlockNumberOrHash
+		@returns proof RPCAccountProof
+	*/
+	GetProof(
+		ctx context.Context, address types.Address, keys []common.Hash, blockNrOrHash transport.BlockNumberOrHash,
+	) (*RPCAccountProof, error)
+
 	/*
 		@name NewFilter
 		@summary Creates a new filter.
@@ -257,6 +325,41 @@ type EthAPIRo interface {
 	*/
 	GetFilterLogs(_ context.Context, id string) ([]*RPCLog, error)
 
+	/*
+		@name GetLogs
+		@summary Returns the logs matching the given filter query.
+		@description Implements eth_getLogs. The logs are looked up in the persistent per-shard index, so the query is not limited to the logs recorded since the last poll.
+		@tags [Filters]
+		@param shardId LogsShardId
+		@param query FilterQuery
+		@returns logs Logs
+	*/
+	GetLogs(ctx context.Context, shardId types.ShardId, query filters.FilterQuery) ([]*RPCLog, error)
+
+	/*
+		@name Subscribe
+		@summary Creates a new subscription to the events of the given shard.
+		@description Implements eth_subscribe. Available over WebSocket connections only. Supported kinds are "newHeads", "logs" and "newPendingTransactions". Notifications are delivered as eth_subscription calls.
+		@tags [Filters]
+		@param kind SubscriptionKind
+		@param shardId SubscriptionShardId
+		@param query FilterQuery
+		@returns subscriptionId SubscriptionId
+	*/
+	Subscribe(
+		ctx context.Context, kind string, shardId types.ShardId, query *filters.FilterQuery,
+	) (transport.SubscriptionID, error)
+
+	/*
+		@name Unsubscribe
+		@summary Cancels the subscription with the given id.
+		@description Implements eth_unsubscribe. The subscription must have been created over the same connection.
+		@tags [Filters]
+		@param id SubscriptionId
+		@returns isDeleted IsDeleted
+	*/
+	Unsubscribe(ctx context.Context, id transport.SubscriptionID) (bool, error)
+
 	/*
 		@name GetShardsIdList
 		@summary Retrieves a list of IDs of all shards.
@@ -316,6 +419,16 @@ type EthAPIRo interface {
 	*/
 	ChainId(ctx context.Context) (hexutil.Uint64, error)
 
+	/*
+		@name Syncing
+		@summary Returns the sync progress of the given shard.
+		@description Implements eth_syncing. Returns false if the shard is not catching up with its peers. Otherwise, returns the range of blocks being downloaded and the peers serving them.
+		@tags [System]
+		@param shardId SyncShardId
+		@returns syncStatus SyncStatus
+	*/
+	Syncing(ctx context.Context, shardId types.ShardId) (any, error)
+
 	/*
 		@name GetTokens
 		@summary Returns the token balances of the account with the given address and at the given block.
@@ -352,9 +465,11 @@ type APIImplRo struct {
 	accessor *execution.StateAccessor
 
 	logs            *LogsAggregator
+	subscriptions   *subscriptionsManager
 	logger          logging.Logger
 	clientEventsLog logging.Logger
 	rawapi          rawapi.NodeApi
+	feeCalculator   execution.FeeCalculator
 }
 
 // APIImpl is implementation of the EthAPI interface based on remote Db access
@@ -371,17 +486,24 @@ func NewEthAPIRo(
 	ctx context.Context,
 	rawapi rawapi.NodeApi,
 	db db.ReadOnlyDB,
+	txnPools map[types.ShardId]txnpool.Pool,
+	feeCalculator execution.FeeCalculator,
 	pollBlocksForLogs bool,
 	logClientEvents bool,
 ) *APIImplRo {
+	if feeCalculator == nil {
+		feeCalculator = &execution.MainFeeCalculator{}
+	}
 	accessor := execution.NewStateAccessor()
 	api := &APIImplRo{
 		logger:          logging.NewLogger("eth-api"),
 		accessor:        accessor,
 		rawapi:          rawapi,
+		feeCalculator:   feeCalculator,
 		clientEventsLog: logging.NewLogger("eth-api-rpc-requests"),
 	}
 	api.logs = NewLogsAggregator(ctx, db, pollBlocksForLogs)
+	api.subscriptions = newSubscriptionsManager(ctx, db, txnPools)
 	if !logClientEvents {
 		api.clientEventsLog = logging.Nop()
 	}
@@ -393,13 +515,16 @@ func NewEthAPI(
 	ctx context.Context,
 	rawapi rawapi.NodeApi,
 	db db.ReadOnlyDB,
+	txnPools map[types.ShardId]txnpool.Pool,
+	feeCalculator execution.FeeCalculator,
 	pollBlocksForLogs bool,
 	logClientEvents bool,
 ) *APIImpl {
-	roApi := NewEthAPIRo(ctx, rawapi, db, pollBlocksForLogs, logClientEvents)
+	roApi := NewEthAPIRo(ctx, rawapi, db, txnPools, feeCalculator, pollBlocksForLogs, logClientEvents)
 	return &APIImpl{roApi}
 }
 
 func (api *APIImplRo) Shutdown() {
 	api.logs.WaitForShutdown()
+	api.subscriptions.waitForShutdown()
 }
@@ -36,7 +36,7 @@ func NewTestEthAPI(ctx context.Context, t *testing.T, db db.DB, nShards int) *AP
 			WithLocalShardApiRo(shardId).
 			WithLocalShardApiRw(shardId, pools[shardId])
 	}
-	return NewEthAPI(ctx, nodeApiBuilder.BuildAndReset(), db, true, false)
+	return NewEthAPI(ctx, nodeApiBuilder.BuildAndReset(), db, pools, nil, true, false)
 }
 
 func TestGetTransactionReceipt(t *testing.T) {
@@ -10,6 +10,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/filters"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 )
 
 type LogsAggregator struct {
@@ -158,3 +159,32 @@ func (api *APIImplRo) GetFilterLogs(_ context.Context, id string) ([]*RPCLog, er
 	}
 	return result, nil
 }
+
+func (api *APIImplRo) GetLogs(
+	ctx context.Context, shardId types.ShardId, query filters.FilterQuery,
+) ([]*RPCLog, error) {
+	filter := rawapitypes.LogsFilter{
+		BlockHash: query.BlockHash,
+		Addresses: query.Addresses,
+		Topics:    query.Topics,
+	}
+	if query.FromBlock != nil {
+		from := types.BlockNumber(query.FromBlock.Uint64())
+		filter.FromBlock = &from
+	}
+	if query.ToBlock != nil {
+		to := types.BlockNumber(query.ToBlock.Uint64())
+		filter.ToBlock = &to
+	}
+
+	logs, err := api.rawapi.GetLogs(ctx, shardId, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*RPCLog, len(logs))
+	for i, info := range logs {
+		result[i] = NewRPCLogFromInfo(info)
+	}
+	return result, nil
+}
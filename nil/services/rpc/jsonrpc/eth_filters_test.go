@@ -2,6 +2,8 @@ package jsonrpc
 
 import (
 	"context"
+	"encoding/binary"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/filters"
+	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -263,6 +266,151 @@ func (s *SuiteEthFilters) TestBlocks() {
 	s.Require().NoError(err)
 }
 
+func (s *SuiteEthFilters) TestGetLogs() {
+	tx, err := s.db.CreateRwTx(s.ctx)
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	address1 := types.HexToAddress("0x1111111111")
+	address2 := types.HexToAddress("0x2222222222")
+
+	blocksLogs := [][]*types.Receipt{
+		{
+			{TxnHash: common.Hash{0x10}, Logs: []*types.Log{
+				{Address: address1, Topics: []common.Hash{{0x01}}, Data: []byte{0x00}},
+			}},
+		},
+		{
+			{TxnHash: common.Hash{0x20}, Logs: []*types.Log{
+				{Address: address2, Topics: []common.Hash{{0x01}}, Data: []byte{0x01}},
+			}},
+			{TxnHash: common.Hash{0x21}, Logs: []*types.Log{
+				{Address: address1, Topics: []common.Hash{{0x02}}, Data: []byte{0x02}},
+				{Address: address1, Topics: []common.Hash{{0x01}, {0x03}}, Data: []byte{0x03}},
+			}},
+		},
+		{
+			{TxnHash: common.Hash{0x30}, Logs: []*types.Log{}},
+		},
+	}
+
+	blockHashes := make([]common.Hash, len(blocksLogs))
+	prevHash := common.EmptyHash
+	for i, receipts := range blocksLogs {
+		receiptsMpt := execution.NewDbReceiptTrie(tx, s.shardId)
+		for j, receipt := range receipts {
+			s.Require().NoError(receiptsMpt.Update(types.TransactionIndex(j), receipt))
+		}
+		block := &types.Block{
+			BlockData: types.BlockData{
+				Id:           types.BlockNumber(i),
+				PrevBlock:    prevHash,
+				ReceiptsRoot: receiptsMpt.RootHash(),
+			},
+			LogsBloom: types.CreateBloom(receipts),
+		}
+		blockHashes[i] = block.Hash(s.shardId)
+		prevHash = blockHashes[i]
+		s.Require().NoError(db.WriteBlock(tx, s.shardId, blockHashes[i], block))
+		s.Require().NoError(execution.PostprocessBlock(tx, s.shardId, &execution.BlockGenerationResult{
+			Block:     block,
+			BlockHash: blockHashes[i],
+		}, execution.ModeVerify))
+	}
+	s.Require().NoError(tx.Commit())
+
+	s.Run("All", func() {
+		logs, err := s.api.GetLogs(s.ctx, s.shardId, filters.FilterQuery{FromBlock: uint256.NewInt(0)})
+		s.Require().NoError(err)
+		s.Require().Len(logs, 4)
+		for i, log := range logs {
+			s.EqualValues([]byte{byte(i)}, log.Data)
+		}
+
+		s.Equal(blockHashes[1], *logs[3].BlockHash)
+		s.Equal(common.Hash{0x21}, *logs[3].TxnHash)
+		s.Equal(types.TransactionIndex(1), *logs[3].TxnIndex)
+		s.EqualValues(2, *logs[3].LogIndex)
+	})
+
+	s.Run("ByAddressAndTopic", func() {
+		logs, err := s.api.GetLogs(s.ctx, s.shardId, filters.FilterQuery{
+			FromBlock: uint256.NewInt(0),
+			Addresses: []types.Address{address1},
+			Topics:    [][]common.Hash{{{0x01}}},
+		})
+		s.Require().NoError(err)
+		s.Require().Len(logs, 2)
+		s.EqualValues([]byte{0x00}, logs[0].Data)
+		s.EqualValues([]byte{0x03}, logs[1].Data)
+	})
+
+	s.Run("ByRange", func() {
+		logs, err := s.api.GetLogs(s.ctx, s.shardId, filters.FilterQuery{
+			FromBlock: uint256.NewInt(1),
+			ToBlock:   uint256.NewInt(1),
+			Topics:    [][]common.Hash{{{0x01}, {0x02}}},
+		})
+		s.Require().NoError(err)
+		s.Require().Len(logs, 3)
+		for i, log := range logs {
+			s.EqualValues([]byte{byte(i + 1)}, log.Data)
+		}
+	})
+
+	s.Run("DefaultsToLatest", func() {
+		logs, err := s.api.GetLogs(s.ctx, s.shardId, filters.FilterQuery{})
+		s.Require().NoError(err)
+		s.Empty(logs)
+
+		logs, err = s.api.GetLogs(s.ctx, s.shardId, filters.FilterQuery{ToBlock: uint256.NewInt(1)})
+		s.Require().NoError(err)
+		s.Empty(logs)
+	})
+
+	s.Run("Tags", func() {
+		for _, tag := range []string{"latest", "pending"} {
+			var query filters.FilterQuery
+			s.Require().NoError(json.Unmarshal([]byte(`{"fromBlock":"`+tag+`","toBlock":"`+tag+`"}`), &query))
+			s.Nil(query.FromBlock)
+			s.Nil(query.ToBlock)
+
+			logs, err := s.api.GetLogs(s.ctx, s.shardId, query)
+			s.Require().NoError(err)
+			s.Empty(logs)
+		}
+
+		var query filters.FilterQuery
+		s.Require().NoError(json.Unmarshal([]byte(`{"fromBlock":"earliest","toBlock":"latest"}`), &query))
+		logs, err := s.api.GetLogs(s.ctx, s.shardId, query)
+		s.Require().NoError(err)
+		s.Len(logs, 4)
+	})
+
+	s.Run("ByBlockHash", func() {
+		logs, err := s.api.GetLogs(s.ctx, s.shardId, filters.FilterQuery{BlockHash: &blockHashes[0]})
+		s.Require().NoError(err)
+		s.Require().Len(logs, 1)
+		s.Equal(types.BlockNumber(0), logs[0].BlockNumber)
+	})
+
+	s.Run("NotIndexed", func() {
+		tx, err := s.db.CreateRwTx(s.ctx)
+		s.Require().NoError(err)
+		defer tx.Rollback()
+		s.Require().NoError(tx.DeleteFromShard(s.shardId, db.LogsBloomIndex, binary.BigEndian.AppendUint64(nil, 1)))
+		s.Require().NoError(tx.Commit())
+
+		logs, err := s.api.GetLogs(s.ctx, s.shardId, filters.FilterQuery{
+			FromBlock: uint256.NewInt(0),
+			Addresses: []types.Address{address2},
+		})
+		s.Require().NoError(err)
+		s.Require().Len(logs, 1)
+		s.EqualValues([]byte{0x01}, logs[0].Data)
+	})
+}
+
 func TestEthFilters(t *testing.T) {
 	t.Parallel()
 
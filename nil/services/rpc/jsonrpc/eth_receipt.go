@@ -2,9 +2,11 @@ package jsonrpc
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 )
 
 func (api *APIImplRo) GetInTransactionReceipt(ctx context.Context, hash common.Hash) (*RPCReceipt, error) {
@@ -14,3 +16,98 @@ func (api *APIImplRo) GetInTransactionReceipt(ctx context.Context, hash common.H
 	}
 	return NewRPCReceipt(info)
 }
+
+// GetTransactionTree implements eth_getTransactionTree.
+func (api *APIImplRo) GetTransactionTree(ctx context.Context, hash common.Hash) (*RPCTransactionTree, error) {
+	// The receipt of the root transaction already includes the receipts of all its descendants.
+	info, err := api.rawapi.GetInTransactionReceipt(ctx, types.ShardIdFromHash(hash), hash)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, nil
+	}
+
+	tree := &RPCTransactionTree{Status: TransactionTreeSettled}
+	tree.Root, err = api.newTransactionTreeNode(ctx, tree, hash, info)
+	if err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// newTransactionTreeNode builds the subtree of the transaction from its receipt, nil receipt means
+// that the transaction is not executed yet.
+func (api *APIImplRo) newTransactionTreeNode(
+	ctx context.Context, tree *RPCTransactionTree, hash common.Hash, info *rawapitypes.ReceiptInfo,
+) (*RPCTransactionTreeNode, error) {
+	node := &RPCTransactionTreeNode{
+		TxnHash:  hash,
+		ShardId:  types.ShardIdFromHash(hash),
+		Status:   TransactionTreePending,
+		Children: []*RPCTransactionTreeNode{},
+	}
+	if info == nil {
+		tree.addNode(node)
+		return node, nil
+	}
+
+	receipt := &types.Receipt{}
+	if err := receipt.UnmarshalSSZ(info.ReceiptSSZ); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal receipt of %s: %w", hash, err)
+	}
+
+	node.Flags = info.Flags
+	node.Status = TransactionTreeSuccess
+	if !receipt.Success {
+		node.Status = TransactionTreeFailed
+		node.ReceiptStatus = receipt.Status.String()
+		node.ErrorMessage = info.ErrorMessage
+	}
+	node.GasUsed = receipt.GasUsed
+	node.BlockHash = info.BlockHash
+	node.BlockNumber = info.BlockId
+	node.IncludedInMain = info.IncludedInMain
+
+	// Temporary receipts of the transactions that failed before the inclusion into a block
+	// don't have the transaction stored.
+	if !info.Temporary && info.BlockHash != common.EmptyHash {
+		res, err := api.rawapi.GetInTransaction(ctx, node.ShardId, makeRequestByHash(hash))
+		if err != nil {
+			return nil, err
+		}
+		txn := &types.Transaction{}
+		if err := txn.UnmarshalSSZ(res.TransactionSSZ); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal transaction %s: %w", hash, err)
+		}
+		node.From = txn.From
+		node.To = txn.To
+		node.Value = txn.Value
+	}
+	tree.addNode(node)
+
+	for i, outHash := range info.OutTransactions {
+		var outInfo *rawapitypes.ReceiptInfo
+		if i < len(info.OutReceipts) {
+			outInfo = info.OutReceipts[i]
+		}
+		child, err := api.newTransactionTreeNode(ctx, tree, outHash, outInfo)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+	}
+	return node, nil
+}
+
+func (tree *RPCTransactionTree) addNode(node *RPCTransactionTreeNode) {
+	tree.GasUsed = tree.GasUsed.Add(node.GasUsed)
+
+	switch {
+	case node.Status == TransactionTreeFailed:
+		tree.Status = TransactionTreeFailed
+	case tree.Status == TransactionTreeFailed:
+	case node.Status == TransactionTreePending || !node.IncludedInMain:
+		tree.Status = TransactionTreePending
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"testing"
 
+	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -81,3 +83,111 @@ func TestSuiteEthReceipt(t *testing.T) {
 
 	suite.Run(t, new(SuiteEthReceipt))
 }
+
+func TestGetTransactionTree(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	api := NewTestEthAPI(ctx, t, database, 2)
+
+	shardId := types.BaseShardId
+	root := types.NewEmptyTransaction()
+	root.To = types.GenerateRandomAddress(shardId)
+	rootReceipt := &types.Receipt{
+		Success: true, GasUsed: 100, TxnHash: root.Hash(), Logs: []*types.Log{}, OutTxnIndex: 0, OutTxnNum: 2,
+	}
+
+	executed := &types.Transaction{
+		TransactionDigest: types.TransactionDigest{
+			Flags: types.NewTransactionFlags(types.TransactionFlagInternal),
+			To:    types.GenerateRandomAddress(shardId),
+		},
+		From:  root.To,
+		Value: types.NewValueFromUint64(10),
+	}
+	executedReceipt := &types.Receipt{Success: true, GasUsed: 50, TxnHash: executed.Hash(), Logs: []*types.Log{}}
+
+	pending := &types.Transaction{
+		TransactionDigest: types.TransactionDigest{
+			Flags: types.NewTransactionFlags(types.TransactionFlagInternal, types.TransactionFlagRefund),
+			To:    types.GenerateRandomAddress(shardId),
+		},
+		From: root.To,
+		TxId: 1,
+	}
+
+	tx, err := database.CreateRwTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	blockRes := writeTestBlock(t, tx, shardId, 0, []*types.Transaction{root},
+		[]*types.Receipt{rootReceipt}, []*types.Transaction{executed, pending})
+	require.NoError(t, execution.PostprocessBlock(tx, shardId, blockRes, execution.ModeVerify))
+
+	blockRes = writeTestBlock(t, tx, shardId, 1, []*types.Transaction{executed},
+		[]*types.Receipt{executedReceipt}, nil)
+	require.NoError(t, execution.PostprocessBlock(tx, shardId, blockRes, execution.ModeVerify))
+
+	require.NoError(t, tx.Commit())
+
+	tree, err := api.GetTransactionTree(ctx, root.Hash())
+	require.NoError(t, err)
+	require.NotNil(t, tree)
+
+	require.Equal(t, TransactionTreePending, tree.Status)
+	require.Equal(t, types.Gas(150), tree.GasUsed)
+
+	require.Equal(t, root.Hash(), tree.Root.TxnHash)
+	require.Equal(t, TransactionTreeSuccess, tree.Root.Status)
+	require.Equal(t, root.To, tree.Root.To)
+	require.Len(t, tree.Root.Children, 2)
+
+	child := tree.Root.Children[0]
+	require.Equal(t, executed.Hash(), child.TxnHash)
+	require.Equal(t, TransactionTreeSuccess, child.Status)
+	require.Equal(t, root.To, child.From)
+	require.Equal(t, executed.To, child.To)
+	require.Equal(t, executed.Value, child.Value)
+	require.Equal(t, types.Gas(50), child.GasUsed)
+	require.Equal(t, types.BlockNumber(1), child.BlockNumber)
+	require.Empty(t, child.Children)
+
+	child = tree.Root.Children[1]
+	require.Equal(t, pending.Hash(), child.TxnHash)
+	require.Equal(t, shardId, child.ShardId)
+	require.Equal(t, TransactionTreePending, child.Status)
+	require.Empty(t, child.Children)
+
+	// Unknown transaction
+	tree, err = api.GetTransactionTree(ctx, common.Hash{0x0, 0x1, 0x2})
+	require.NoError(t, err)
+	require.Nil(t, tree)
+}
+
+func TestTransactionTreeStatus(t *testing.T) {
+	t.Parallel()
+
+	status := func(nodes ...*RPCTransactionTreeNode) TransactionTreeStatus {
+		tree := &RPCTransactionTree{Status: TransactionTreeSettled}
+		for _, node := range nodes {
+			tree.addNode(node)
+		}
+		return tree.Status
+	}
+
+	committed := &RPCTransactionTreeNode{Status: TransactionTreeSuccess, IncludedInMain: true}
+	notCommitted := &RPCTransactionTreeNode{Status: TransactionTreeSuccess}
+	pending := &RPCTransactionTreeNode{Status: TransactionTreePending}
+	failed := &RPCTransactionTreeNode{Status: TransactionTreeFailed}
+
+	require.Equal(t, TransactionTreeSettled, status(committed, committed))
+	require.Equal(t, TransactionTreePending, status(committed, notCommitted))
+	require.Equal(t, TransactionTreePending, status(committed, pending))
+	require.Equal(t, TransactionTreeFailed, status(committed, failed, pending))
+	require.Equal(t, TransactionTreeFailed, status(pending, failed, committed))
+}
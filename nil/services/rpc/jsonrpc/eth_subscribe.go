@@ -0,0 +1,261 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+)
+
+const (
+	SubscriptionNewHeads               = "newHeads"
+	SubscriptionLogs                   = "logs"
+	SubscriptionNewPendingTransactions = "newPendingTransactions"
+
+	pendingTransactionsBufferSize = 100
+)
+
+var (
+	errSubscriptionsUnavailable = errors.New("subscriptions are not available on this node")
+	errLogsSubscriptionRange    = errors.New(
+		"logs subscription receives new logs only, use eth_getLogs to query historical blocks")
+)
+
+// subscriptionsManager holds the per-shard sources of the subscription events.
+type subscriptionsManager struct {
+	ctx      context.Context
+	db       db.ReadOnlyDB
+	txnPools map[types.ShardId]txnpool.Pool
+
+	mu       sync.Mutex
+	managers map[types.ShardId]*filters.FiltersManager
+}
+
+func newSubscriptionsManager(
+	ctx context.Context, db db.ReadOnlyDB, txnPools map[types.ShardId]txnpool.Pool,
+) *subscriptionsManager {
+	return &subscriptionsManager{
+		ctx:      ctx,
+		db:       db,
+		txnPools: txnPools,
+		managers: make(map[types.ShardId]*filters.FiltersManager),
+	}
+}
+
+// filtersManager returns the manager tracking new blocks of the shard. Managers are started on first use,
+// so shards without subscribers are not polled, and deliver only the blocks committed after that.
+func (m *subscriptionsManager) filtersManager(shardId types.ShardId) (*filters.FiltersManager, error) {
+	if m.db == nil {
+		return nil, errSubscriptionsUnavailable
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	manager, ok := m.managers[shardId]
+	if !ok {
+		var err error
+		manager, err = filters.NewShardFiltersManager(m.ctx, m.db, shardId)
+		if err != nil {
+			return nil, err
+		}
+		m.managers[shardId] = manager
+	}
+	return manager, nil
+}
+
+func (m *subscriptionsManager) waitForShutdown() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, manager := range m.managers {
+		manager.WaitForShutdown()
+	}
+}
+
+// Subscribe implements eth_subscribe.
+func (api *APIImplRo) Subscribe(
+	ctx context.Context, kind string, shardId types.ShardId, query *filters.FilterQuery,
+) (transport.SubscriptionID, error) {
+	notifier, ok := transport.NotifierFromContext(ctx)
+	if !ok {
+		return "", transport.ErrNotificationsUnsupported
+	}
+
+	if err := api.checkShardId(ctx, shardId); err != nil {
+		return "", err
+	}
+
+	switch kind {
+	case SubscriptionNewHeads:
+		return api.subscribeNewHeads(notifier, shardId)
+	case SubscriptionLogs:
+		if query == nil {
+			query = &filters.FilterQuery{}
+		}
+		return api.subscribeLogs(notifier, shardId, query)
+	case SubscriptionNewPendingTransactions:
+		return api.subscribeNewPendingTransactions(notifier, shardId)
+	}
+	return "", fmt.Errorf("unsupported subscription kind %q", kind)
+}
+
+// Unsubscribe implements eth_unsubscribe.
+func (api *APIImplRo) Unsubscribe(ctx context.Context, id transport.SubscriptionID) (bool, error) {
+	notifier, ok := transport.NotifierFromContext(ctx)
+	if !ok {
+		return false, transport.ErrNotificationsUnsupported
+	}
+
+	if err := notifier.Unsubscribe(id); err != nil {
+		if errors.Is(err, transport.ErrSubscriptionNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (api *APIImplRo) checkShardId(ctx context.Context, shardId types.ShardId) error {
+	numShards, err := api.rawapi.GetNumShards(ctx)
+	if err != nil {
+		return err
+	}
+	if uint64(shardId) >= numShards {
+		return fmt.Errorf("shard %d doesn't exist", shardId)
+	}
+	return nil
+}
+
+func (api *APIImplRo) subscribeNewHeads(
+	notifier *transport.Notifier, shardId types.ShardId,
+) (transport.SubscriptionID, error) {
+	manager, err := api.subscriptions.filtersManager(shardId)
+	if err != nil {
+		return "", err
+	}
+	sub, err := notifier.CreateSubscription()
+	if err != nil {
+		return "", err
+	}
+
+	listenerId, blocks := manager.AddBlocksListener()
+	go func() {
+		defer manager.RemoveBlocksListener(listenerId)
+
+		for {
+			select {
+			case block, ok := <-blocks:
+				if !ok {
+					api.dropSubscription(notifier, sub.ID)
+					return
+				}
+				header, err := NewRPCBlock(shardId, &BlockWithEntities{Block: block}, false)
+				if err != nil {
+					api.logger.Error().Err(err).Msg("Failed to convert block for subscription")
+					continue
+				}
+				if err := notifier.Notify(sub.ID, header); err != nil {
+					return
+				}
+			case <-sub.Err():
+				return
+			case <-api.subscriptions.ctx.Done():
+				return
+			}
+		}
+	}()
+	return sub.ID, nil
+}
+
+func (api *APIImplRo) subscribeLogs(
+	notifier *transport.Notifier, shardId types.ShardId, query *filters.FilterQuery,
+) (transport.SubscriptionID, error) {
+	if query.BlockHash != nil || query.FromBlock != nil || query.ToBlock != nil {
+		return "", errLogsSubscriptionRange
+	}
+
+	manager, err := api.subscriptions.filtersManager(shardId)
+	if err != nil {
+		return "", err
+	}
+	sub, err := notifier.CreateSubscription()
+	if err != nil {
+		return "", err
+	}
+
+	filterId, filter := manager.NewFilter(query)
+	if filter == nil {
+		_ = notifier.Unsubscribe(sub.ID)
+		return "", errors.New("cannot create new filter")
+	}
+	go func() {
+		defer manager.RemoveFilter(filterId)
+
+		for {
+			select {
+			case log, ok := <-filter.LogsChannel():
+				if !ok {
+					api.dropSubscription(notifier, sub.ID)
+					return
+				}
+				if err := notifier.Notify(sub.ID, NewRPCLog(log.Log, log.BlockId)); err != nil {
+					return
+				}
+			case <-sub.Err():
+				return
+			case <-api.subscriptions.ctx.Done():
+				return
+			}
+		}
+	}()
+	return sub.ID, nil
+}
+
+// dropSubscription closes the subscription whose events are no longer delivered by the manager
+// because the subscriber fell behind.
+func (api *APIImplRo) dropSubscription(notifier *transport.Notifier, id transport.SubscriptionID) {
+	if err := notifier.Unsubscribe(id); err == nil {
+		api.logger.Debug().Str("subscription", string(id)).Msg("Subscriber fell behind, subscription is closed")
+	}
+}
+
+func (api *APIImplRo) subscribeNewPendingTransactions(
+	notifier *transport.Notifier, shardId types.ShardId,
+) (transport.SubscriptionID, error) {
+	pool, ok := api.subscriptions.txnPools[shardId]
+	if !ok || pool == nil {
+		return "", fmt.Errorf("pending transactions of shard %d are not available on this node", shardId)
+	}
+	sub, err := notifier.CreateSubscription()
+	if err != nil {
+		return "", err
+	}
+
+	hashes := make(chan common.Hash, pendingTransactionsBufferSize)
+	unsubscribe := pool.SubscribeNewTransactions(hashes)
+	go func() {
+		defer unsubscribe()
+
+		for {
+			select {
+			case hash := <-hashes:
+				if err := notifier.Notify(sub.ID, hash); err != nil {
+					return
+				}
+			case <-sub.Err():
+				return
+			case <-api.subscriptions.ctx.Done():
+				return
+			}
+		}
+	}()
+	return sub.ID, nil
+}
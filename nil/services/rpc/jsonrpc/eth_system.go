@@ -2,17 +2,210 @@ package jsonrpc
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 
 	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 )
 
+const (
+	// maxFeeHistoryBlocks limits the number of blocks processed by a single eth_feeHistory request.
+	maxFeeHistoryBlocks = 1024
+	// priorityFeeBlocks is the number of the latest blocks whose priority fees are used by eth_maxPriorityFeePerGas.
+	priorityFeeBlocks = 20
+	// priorityFeePercentile is the percentile of the recent priority fees suggested by eth_maxPriorityFeePerGas.
+	priorityFeePercentile = 60
+)
+
+var errInvalidRewardPercentile = errors.New("invalid reward percentile")
+
 // ChainId implements eth_chainId. Returns the current ethereum chainId.
 func (api *APIImplRo) ChainId(_ context.Context) (hexutil.Uint64, error) {
 	return hexutil.Uint64(types.DefaultChainId), nil
 }
 
+// Syncing implements eth_syncing. Returns false if the shard is not catching up with its peers,
+// and the progress of the sync otherwise.
+func (api *APIImplRo) Syncing(ctx context.Context, shardId types.ShardId) (any, error) {
+	progress, err := api.rawapi.GetSyncProgress(ctx, shardId)
+	if err != nil {
+		return nil, err
+	}
+	if !progress.Syncing {
+		return false, nil
+	}
+	return &SyncStatus{
+		StartingBlock: progress.StartingBlock,
+		CurrentBlock:  progress.CurrentBlock,
+		HighestBlock:  progress.HighestBlock,
+		Peers:         progress.Peers,
+	}, nil
+}
+
 // GasPrice implements Eth_gasPrice. Returns the current gas price in the network for a given shard.
 func (api *APIImplRo) GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error) {
 	return api.rawapi.GasPrice(ctx, shardId)
 }
+
+// FeeHistory implements eth_feeHistory. Returns the base fees and the gas used ratios of the range of blocks
+// ending with newestBlock, and the percentiles of the priority fees paid by their external transactions.
+func (api *APIImplRo) FeeHistory(
+	ctx context.Context,
+	shardId types.ShardId,
+	blockCount uint64,
+	newestBlock transport.BlockNumber,
+	rewardPercentiles []float64,
+) (*FeeHistory, error) {
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 || (i > 0 && p < rewardPercentiles[i-1]) {
+			return nil, fmt.Errorf("%w: %v", errInvalidRewardPercentile, p)
+		}
+	}
+	if newestBlock < transport.LatestBlockNumber {
+		return nil, errNotImplemented
+	}
+
+	blocks, err := api.getRecentBlocks(ctx, shardId, min(blockCount, maxFeeHistoryBlocks), newestBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &FeeHistory{
+		BaseFeePerGas: make([]types.Value, 0, len(blocks)+1),
+		GasUsedRatio:  make([]float64, 0, len(blocks)),
+	}
+	if len(blocks) == 0 {
+		return res, nil
+	}
+
+	res.OldestBlock = blocks[0].Id
+	// The blocks are collated and their base fees are adjusted against the default gas limit.
+	for _, block := range blocks {
+		res.BaseFeePerGas = append(res.BaseFeePerGas, block.BaseFee)
+		res.GasUsedRatio = append(res.GasUsedRatio, float64(block.GasUsed)/float64(types.DefaultMaxGasInBlock))
+		if len(rewardPercentiles) > 0 {
+			res.Reward = append(res.Reward, priorityFeeRewards(block, rewardPercentiles))
+		}
+	}
+	res.BaseFeePerGas = append(res.BaseFeePerGas, api.nextBaseFee(shardId, blocks[len(blocks)-1].Block))
+	return res, nil
+}
+
+// MaxPriorityFeePerGas implements eth_maxPriorityFeePerGas. Returns the priority fee that is high enough
+// for most of the external transactions included into the latest blocks of the shard.
+func (api *APIImplRo) MaxPriorityFeePerGas(ctx context.Context, shardId types.ShardId) (types.Value, error) {
+	blocks, err := api.getRecentBlocks(ctx, shardId, priorityFeeBlocks, transport.LatestBlockNumber)
+	if err != nil {
+		return types.Value{}, err
+	}
+
+	var fees []types.Value
+	for _, block := range blocks {
+		for _, tip := range externalPriorityFees(block) {
+			fees = append(fees, tip.fee)
+		}
+	}
+	if len(fees) == 0 {
+		return types.Value0, nil
+	}
+	slices.SortFunc(fees, func(a, b types.Value) int { return a.Cmp(b) })
+	return fees[(len(fees)-1)*priorityFeePercentile/100], nil
+}
+
+// getRecentBlocks returns up to count blocks ending with the newest one, the oldest block goes first.
+func (api *APIImplRo) getRecentBlocks(
+	ctx context.Context, shardId types.ShardId, count uint64, newest transport.BlockNumber,
+) ([]*types.BlockWithExtractedData, error) {
+	if count == 0 {
+		return nil, nil
+	}
+
+	blocks := make([]*types.BlockWithExtractedData, 0, count)
+	ref := blockNrToBlockReference(newest)
+	for {
+		raw, err := api.rawapi.GetFullBlockData(ctx, shardId, ref)
+		if err != nil {
+			return nil, err
+		}
+		block, err := raw.DecodeSSZ()
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+
+		if uint64(len(blocks)) == count || block.Id == 0 {
+			break
+		}
+		ref = rawapitypes.BlockNumberAsBlockReference(block.Id - 1)
+	}
+	slices.Reverse(blocks)
+	return blocks, nil
+}
+
+type priorityFee struct {
+	fee     types.Value
+	gasUsed types.Gas
+}
+
+// externalPriorityFees returns the effective priority fees of the external transactions of the block.
+// Only they compete for the block space in the txnpool, the internal transactions are always included.
+func externalPriorityFees(block *types.BlockWithExtractedData) []priorityFee {
+	var fees []priorityFee
+	for i, txn := range block.InTransactions {
+		if txn.IsInternal() || i >= len(block.Receipts) {
+			continue
+		}
+		fee, ok := execution.GetEffectivePriorityFee(block.BaseFee, txn)
+		if !ok {
+			continue
+		}
+		fees = append(fees, priorityFee{fee: fee, gasUsed: block.Receipts[i].GasUsed})
+	}
+	return fees
+}
+
+// priorityFeeRewards returns the priority fees at the given percentiles of the gas used
+// by the external transactions of the block.
+func priorityFeeRewards(block *types.BlockWithExtractedData, percentiles []float64) []types.Value {
+	rewards := make([]types.Value, len(percentiles))
+	fees := externalPriorityFees(block)
+	if len(fees) == 0 {
+		for i := range rewards {
+			rewards[i] = types.Value0
+		}
+		return rewards
+	}
+
+	slices.SortStableFunc(fees, func(a, b priorityFee) int { return a.fee.Cmp(b.fee) })
+
+	var totalGasUsed types.Gas
+	for _, fee := range fees {
+		totalGasUsed = totalGasUsed.Add(fee.gasUsed)
+	}
+
+	idx := 0
+	sumGasUsed := fees[0].gasUsed
+	for i, p := range percentiles {
+		threshold := types.Gas(float64(totalGasUsed) * p / 100)
+		for sumGasUsed < threshold && idx < len(fees)-1 {
+			idx++
+			sumGasUsed = sumGasUsed.Add(fees[idx].gasUsed)
+		}
+		rewards[i] = fees[idx].fee
+	}
+	return rewards
+}
+
+// nextBaseFee returns the base fee of the block following the given one, as the node's fee calculator sets it.
+func (api *APIImplRo) nextBaseFee(shardId types.ShardId, block *types.Block) types.Value {
+	// The base fee isn't updated in the main shard.
+	if shardId.IsMainShard() {
+		return types.DefaultGasPrice
+	}
+	return api.feeCalculator.CalculateBaseFee(block)
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"testing"
 
+	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -42,8 +46,154 @@ func (suite *SuiteEthSystem) TestChainId() {
 	suite.EqualValues(types.DefaultChainId, chainId)
 }
 
+func (suite *SuiteEthSystem) TestSyncing() {
+	// The shard without a syncer is never reported as syncing.
+	syncing, err := suite.api.Syncing(context.Background(), types.MainShardId)
+	suite.Require().NoError(err)
+	suite.Equal(false, syncing)
+}
+
 func TestSuiteEthSystem(t *testing.T) {
 	t.Parallel()
 
 	suite.Run(t, new(SuiteEthSystem))
 }
+
+type feeTestTxn struct {
+	internal    bool
+	priorityFee uint64
+	gasUsed     types.Gas
+}
+
+func writeFeeTestBlock(
+	t *testing.T, tx db.RwTx, shardId types.ShardId, id types.BlockNumber, baseFee uint64, txns ...feeTestTxn,
+) *types.Block {
+	t.Helper()
+
+	transactions := make([]*types.Transaction, len(txns))
+	receipts := make([]*types.Receipt, len(txns))
+	var gasUsed types.Gas
+	for i, txn := range txns {
+		transaction := types.NewEmptyTransaction()
+		transaction.To = types.GenerateRandomAddress(shardId)
+		transaction.MaxFeePerGas = types.NewValueFromUint64(1_000_000)
+		transaction.MaxPriorityFeePerGas = types.NewValueFromUint64(txn.priorityFee)
+		if txn.internal {
+			transaction.Flags = types.NewTransactionFlags(types.TransactionFlagInternal)
+		}
+		transactions[i] = transaction
+		receipts[i] = &types.Receipt{
+			Success: true, GasUsed: txn.gasUsed, TxnHash: transaction.Hash(), Logs: []*types.Log{},
+		}
+		gasUsed = gasUsed.Add(txn.gasUsed)
+	}
+
+	block := &types.Block{
+		BlockData: types.BlockData{
+			Id:                 id,
+			InTransactionsRoot: writeTransactions(t, tx, shardId, transactions).RootHash(),
+			ReceiptsRoot:       writeReceipts(t, tx, shardId, receipts).RootHash(),
+			BaseFee:            types.NewValueFromUint64(baseFee),
+			GasUsed:            gasUsed,
+		},
+	}
+	hash := block.Hash(shardId)
+	require.NoError(t, db.WriteBlock(tx, shardId, hash, block))
+
+	inTxnHashes := make([]common.Hash, len(transactions))
+	for i, r := range receipts {
+		inTxnHashes[i] = r.TxnHash
+	}
+	require.NoError(t, execution.PostprocessBlock(tx, shardId, &execution.BlockGenerationResult{
+		BlockHash:   hash,
+		Block:       block,
+		InTxns:      transactions,
+		InTxnHashes: inTxnHashes,
+	}, execution.ModeVerify))
+	return block
+}
+
+func TestFeeHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	api := NewTestEthAPI(ctx, t, database, 2)
+	shardId := types.BaseShardId
+
+	tx, err := database.CreateRwTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	writeFeeTestBlock(t, tx, shardId, 0, 10)
+	writeFeeTestBlock(t, tx, shardId, 1, 10,
+		feeTestTxn{priorityFee: 5, gasUsed: 300},
+		feeTestTxn{priorityFee: 1, gasUsed: 100},
+		// The internal transactions don't affect the rewards.
+		feeTestTxn{internal: true, priorityFee: 100, gasUsed: types.DefaultMaxGasInBlock/2 - 400})
+	last := writeFeeTestBlock(t, tx, shardId, 2, 20, feeTestTxn{priorityFee: 3, gasUsed: 100})
+	require.NoError(t, tx.Commit())
+
+	values := func(vs ...uint64) []types.Value {
+		res := make([]types.Value, len(vs))
+		for i, v := range vs {
+			res[i] = types.NewValueFromUint64(v)
+		}
+		return res
+	}
+
+	t.Run("All", func(t *testing.T) {
+		res, err := api.FeeHistory(ctx, shardId, 10, transport.LatestBlockNumber, []float64{0, 50, 100})
+		require.NoError(t, err)
+
+		require.Equal(t, types.BlockNumber(0), res.OldestBlock)
+		nextBaseFee := (&execution.MainFeeCalculator{}).CalculateBaseFee(last)
+		require.Equal(t, append(values(10, 10, 20), nextBaseFee), res.BaseFeePerGas)
+		require.Equal(t, []float64{0, 0.5, float64(100) / float64(types.DefaultMaxGasInBlock)}, res.GasUsedRatio)
+		require.Equal(t, [][]types.Value{values(0, 0, 0), values(1, 5, 5), values(3, 3, 3)}, res.Reward)
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		res, err := api.FeeHistory(ctx, shardId, 1, transport.BlockNumber(1), nil)
+		require.NoError(t, err)
+
+		require.Equal(t, types.BlockNumber(1), res.OldestBlock)
+		require.Len(t, res.BaseFeePerGas, 2)
+		require.Len(t, res.GasUsedRatio, 1)
+		require.Nil(t, res.Reward)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		res, err := api.FeeHistory(ctx, shardId, 0, transport.LatestBlockNumber, nil)
+		require.NoError(t, err)
+		require.Empty(t, res.BaseFeePerGas)
+	})
+
+	t.Run("FeeCalculator", func(t *testing.T) {
+		// The next base fee is calculated by the fee calculator the node is configured with.
+		constApi := *api.APIImplRo
+		constApi.feeCalculator = &execution.ConstFeeCalculator{Value: types.NewValueFromUint64(42)}
+
+		res, err := constApi.FeeHistory(ctx, shardId, 1, transport.LatestBlockNumber, nil)
+		require.NoError(t, err)
+		require.Equal(t, values(20, 42), res.BaseFeePerGas)
+	})
+
+	t.Run("InvalidPercentiles", func(t *testing.T) {
+		_, err := api.FeeHistory(ctx, shardId, 1, transport.LatestBlockNumber, []float64{50, 10})
+		require.ErrorIs(t, err, errInvalidRewardPercentile)
+
+		_, err = api.FeeHistory(ctx, shardId, 1, transport.LatestBlockNumber, []float64{101})
+		require.ErrorIs(t, err, errInvalidRewardPercentile)
+	})
+
+	t.Run("MaxPriorityFeePerGas", func(t *testing.T) {
+		res, err := api.MaxPriorityFeePerGas(ctx, shardId)
+		require.NoError(t, err)
+		require.Equal(t, types.NewValueFromUint64(3), res)
+	})
+}
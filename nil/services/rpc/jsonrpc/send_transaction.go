@@ -6,20 +6,32 @@ import (
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/txnpool"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = telemetry.NewTracer("github.com/NilFoundation/nil/nil/services/rpc/jsonrpc")
+
 // SendRawTransaction implements eth_sendRawTransaction.
 // Creates new transaction or a contract creation for previously-signed transaction.
-func (api *APIImpl) SendRawTransaction(ctx context.Context, encoded hexutil.Bytes) (common.Hash, error) {
+func (api *APIImpl) SendRawTransaction(ctx context.Context, encoded hexutil.Bytes) (hash common.Hash, err error) {
+	ctx, span := tracer.Start(ctx, "rpc.SendRawTransaction", trace.WithSpanKind(trace.SpanKindServer))
+	defer func() {
+		telemetry.EndSpan(span, err)
+	}()
+
 	var extTxn types.ExternalTransaction
-	if err := extTxn.UnmarshalSSZ(encoded); err != nil {
+	if err = extTxn.UnmarshalSSZ(encoded); err != nil {
 		return common.EmptyHash, fmt.Errorf("failed to decode transaction: %w", err)
 	}
 
 	shardId := extTxn.To.ShardId()
+	span.SetAttributes(telattr.ShardId(shardId), telattr.TransactionHash(extTxn.Hash()))
+
 	reason, err := api.rawapi.SendTransaction(ctx, shardId, encoded)
 	if err != nil {
 		return common.EmptyHash, err
@@ -3,6 +3,7 @@ package jsonrpc
 import (
 	"context"
 
+	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
@@ -16,6 +17,9 @@ type TxPoolStatus struct {
 type TxPoolContent struct {
 	Pending map[string]map[string]*Transaction `json:"pending"`
 	Queued  map[string]map[string]*Transaction `json:"queued"`
+	// Discarded maps the hashes of the recently discarded transactions to the discard reasons,
+	// e.g., "expired" or "future queue overflow".
+	Discarded map[common.Hash]string `json:"discarded"`
 }
 
 // TxPoolAPI The txpool API gives access to several non-standard RPC methods to inspect the contents of the txpool
@@ -39,32 +43,40 @@ func NewTxPoolAPI(rawApi rawapi.NodeApi, logger logging.Logger) *TxPoolAPIImpl {
 }
 
 // GetTxpoolStatus inspection property can be queried for the number of transactions currently pending for inclusion
-// in the next block(s).
+// in the next block(s), as well as the ones queued until a seqno gap is filled.
 func (api *TxPoolAPIImpl) GetTxpoolStatus(ctx context.Context, shardId types.ShardId) (TxPoolStatus, error) {
-	getPendingTxCount, err := api.rawApi.GetTxpoolStatus(ctx, shardId)
+	status, err := api.rawApi.GetTxpoolStatus(ctx, shardId)
 	if err != nil {
 		return TxPoolStatus{}, err
 	}
-	return TxPoolStatus{Pending: getPendingTxCount, Queued: 0}, nil
+	return TxPoolStatus{Pending: status.Pending, Queued: status.Queued}, nil
 }
 
 // GetTxpoolContent inspection property can be queried to list the exact details of all the transactions
-// currently pending for inclusion in the next block(s).
+// currently pending for inclusion in the next block(s), as well as the ones queued until a seqno gap is filled.
+// The reasons of the recently discarded transactions are reported too.
 func (api *TxPoolAPIImpl) GetTxpoolContent(ctx context.Context, shardId types.ShardId) (TxPoolContent, error) {
-	txPool, err := api.rawApi.GetTxpoolContent(ctx, shardId)
+	content, err := api.rawApi.GetTxpoolContent(ctx, shardId)
 	if err != nil {
 		return TxPoolContent{}, err
 	}
-	pendingTx := make(map[string]map[string]*Transaction)
+	return TxPoolContent{
+		Pending:   groupTxpoolTransactions(content.Pending),
+		Queued:    groupTxpoolTransactions(content.Queued),
+		Discarded: content.Discarded,
+	}, nil
+}
 
-	for _, tx := range txPool {
+func groupTxpoolTransactions(txns []*types.Transaction) map[string]map[string]*Transaction {
+	res := make(map[string]map[string]*Transaction)
+	for _, tx := range txns {
 		fromAddr := tx.From.String()
 
-		if _, exists := pendingTx[fromAddr]; !exists {
-			pendingTx[fromAddr] = make(map[string]*Transaction)
+		if _, exists := res[fromAddr]; !exists {
+			res[fromAddr] = make(map[string]*Transaction)
 		}
 
-		pendingTx[fromAddr][tx.Seqno.String()] = NewTransaction(tx)
+		res[fromAddr][tx.Seqno.String()] = NewTransaction(tx)
 	}
-	return TxPoolContent{Pending: pendingTx}, nil
+	return res
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/types"
@@ -68,26 +69,44 @@ func (suite *SuiteTxnPoolApi) TestTnxApi() {
 		suite.Require().NoError(err)
 	}
 
+	// The transaction with a seqno gap is queued.
+	queuedAddr := types.ShardAndHexToAddress(0, "deadbeef00")
+	reasons, err := suite.pool.Add(ctx, newTransaction(queuedAddr, 2, 123, types.Code{0xff}))
+	suite.Require().NoError(err)
+	suite.Require().Equal([]txnpool.DiscardReason{txnpool.NotSet}, reasons)
+
+	// The discard reasons of the removed transactions are reported.
+	discarded := newTransaction(types.ShardAndHexToAddress(0, "deadbeefff"), 0, 123, types.Code{0xfe})
+	_, err = suite.pool.Add(ctx, discarded)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.pool.Discard(ctx, []common.Hash{discarded.Hash()}, txnpool.Expired))
+
 	suite.Run("NodeApi", func() {
-		txAmountRes, err := suite.api.GetTxpoolStatus(ctx, types.MainShardId)
+		status, err := suite.api.GetTxpoolStatus(ctx, types.MainShardId)
 		suite.Require().NoError(err)
-		suite.Require().Equal(transactionAmount, txAmountRes)
+		suite.Require().Equal(transactionAmount, status.Pending)
+		suite.Require().Equal(uint64(1), status.Queued)
 
-		txs, err := suite.api.GetTxpoolContent(ctx, types.MainShardId)
+		content, err := suite.api.GetTxpoolContent(ctx, types.MainShardId)
 		suite.Require().NoError(err)
-		txsContentAmount := uint64(len(txs))
-		suite.Require().Equal(transactionAmount, txsContentAmount)
+		suite.Require().Len(content.Pending, int(transactionAmount))
+		suite.Require().Len(content.Queued, 1)
+		suite.Require().Equal(map[common.Hash]string{discarded.Hash(): "expired"}, content.Discarded)
 	})
 
 	suite.Run("TxnpoolApi", func() {
-		txAmountRes, err := suite.txnpoolApi.GetTxpoolStatus(ctx, types.MainShardId)
+		status, err := suite.txnpoolApi.GetTxpoolStatus(ctx, types.MainShardId)
 		suite.Require().NoError(err)
-		suite.Require().Equal(transactionAmount, txAmountRes.Pending)
+		suite.Require().Equal(transactionAmount, status.Pending)
+		suite.Require().Equal(uint64(1), status.Queued)
 
 		txs, err := suite.txnpoolApi.GetTxpoolContent(ctx, types.MainShardId)
 		suite.Require().NoError(err)
 		txsContentAmount := uint64(len(txs.Pending))
 		suite.Require().Equal(transactionAmount, txsContentAmount)
+		suite.Require().Len(txs.Queued, 1)
+		suite.Require().Contains(txs.Queued[queuedAddr.String()], types.Seqno(2).String())
+		suite.Require().Equal(map[common.Hash]string{discarded.Hash(): "expired"}, txs.Discarded)
 	})
 }
 
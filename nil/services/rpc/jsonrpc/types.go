@@ -7,6 +7,8 @@ import (
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -16,6 +18,7 @@ type (
 	Contract       = rpctypes.Contract
 	CallArgs       = rpctypes.CallArgs
 	StateOverrides = rpctypes.StateOverrides
+	TraceConfig    = tracers.TraceConfig
 )
 
 // @component RPCInTransaction rpcInTransaction object "The transaction whose information is requested."
@@ -271,7 +274,11 @@ type RPCReceipt struct {
 
 type RPCLog struct {
 	*types.Log
-	BlockNumber types.BlockNumber `json:"blockNumber"`
+	BlockNumber types.BlockNumber       `json:"blockNumber"`
+	BlockHash   *common.Hash            `json:"blockHash,omitempty"`
+	TxnHash     *common.Hash            `json:"transactionHash,omitempty"`
+	TxnIndex    *types.TransactionIndex `json:"transactionIndex,omitempty"`
+	LogIndex    *hexutil.Uint64         `json:"logIndex,omitempty"`
 }
 
 type RPCDebugLog struct {
@@ -429,7 +436,51 @@ func NewRPCLog(
 		return nil
 	}
 
-	return &RPCLog{log, blockId}
+	return &RPCLog{Log: log, BlockNumber: blockId}
+}
+
+func NewRPCLogFromInfo(info *rawapitypes.LogInfo) *RPCLog {
+	if info == nil || info.Log == nil {
+		return nil
+	}
+
+	logIndex := hexutil.Uint64(info.LogIndex)
+	return &RPCLog{
+		Log:         info.Log,
+		BlockNumber: info.BlockId,
+		BlockHash:   &info.BlockHash,
+		TxnHash:     &info.TxnHash,
+		TxnIndex:    &info.TxnIndex,
+		LogIndex:    &logIndex,
+	}
+}
+
+func NewRPCAccountProof(address types.Address, proof *rawapitypes.AccountProof) (*RPCAccountProof, error) {
+	res := &RPCAccountProof{
+		Address:      address,
+		Balance:      types.NewZeroValue(),
+		Contract:     proof.ContractSSZ,
+		AccountProof: proof.ProofEncoded,
+		StorageProof: make([]RPCStorageProof, len(proof.StorageProofs)),
+	}
+	for i, sp := range proof.StorageProofs {
+		res.StorageProof[i] = RPCStorageProof{Key: sp.Key, Value: sp.Value, Proof: sp.ProofEncoded}
+	}
+
+	if len(proof.ContractSSZ) == 0 {
+		return res, nil
+	}
+
+	var contract types.SmartContract
+	if err := contract.UnmarshalSSZ(proof.ContractSSZ); err != nil {
+		return nil, err
+	}
+	res.Balance = contract.Balance
+	res.CodeHash = contract.CodeHash
+	res.Seqno = hexutil.Uint64(contract.Seqno)
+	res.ExtSeqno = hexutil.Uint64(contract.ExtSeqno)
+	res.StorageRoot = contract.StorageRoot
+	return res, nil
 }
 
 func NewRPCReceipt(info *rawapitypes.ReceiptInfo) (*RPCReceipt, error) {
@@ -506,6 +557,148 @@ type DebugRPCContract struct {
 	AsyncContext map[types.TransactionIndex]types.AsyncContext `json:"asyncContext"`
 }
 
+// @component DebugRPCDoubleSignEvidence debugRpcDoubleSignEvidence object "The evidence of a validator signing conflicting consensus messages."
+// @componentprop ShardId shardId integer true "The shard where the messages were signed."
+// @componentprop PublicKey publicKey string true "The BLS public key of the validator."
+// @componentprop Height height integer true "The height of the conflicting messages."
+// @componentprop Round round integer true "The round of the conflicting messages."
+// @componentprop Type type integer true "The IBFT type of the messages (0 for proposals, 2 for commit seals)."
+// @componentprop First first string true "The first signed message."
+// @componentprop Second second string true "The second signed message."
+// @componentprop Data data string true "The serialized evidence to be submitted to Governance.reportDoubleSign."
+type DebugRPCDoubleSignEvidence struct {
+	*types.DoubleSignEvidence
+	Data hexutil.Bytes `json:"data"`
+}
+
+// @component DebugRPCContractDiff debugRpcContractDiff object "The changes of a contract between two blocks of a shard."
+// @componentprop Address address string true "The address of the contract."
+// @componentprop Deleted deleted boolean false "Whether the contract was removed."
+// @componentprop Balance balance integer false "The new balance of the contract."
+// @componentprop Seqno seqno integer false "The new seqno of the contract."
+// @componentprop ExtSeqno extSeqno integer false "The new external seqno of the contract."
+// @componentprop Code code string false "The new code of the contract."
+// @componentprop Storage storage object false "The changed storage slots, the removed ones are zero."
+// @componentprop Tokens tokens object false "The changed token balances, the removed ones are zero."
+// @componentprop AsyncContext asyncContext object false "The changed async contexts, the removed ones are null."
+type DebugRPCContractDiff struct {
+	*execution.ContractDiff
+}
+
+// @component RPCStorageProof rpcStorageProof object "The proof of the value of a storage slot."
+// @componentprop Key key string true "The key of the storage slot."
+// @componentprop Value value string true "The value of the storage slot (zero if the slot is empty)."
+// @componentprop Proof proof string true "The serialized MPT proof of the slot against the storage root of the contract."
+type RPCStorageProof struct {
+	Key   common.Hash   `json:"key"`
+	Value common.Hash   `json:"value"`
+	Proof hexutil.Bytes `json:"proof"`
+}
+
+// @component RPCAccountProof rpcAccountProof object "The state of the account together with the proofs of its inclusion."
+// @componentprop Address address string true "The address of the account."
+// @componentprop Balance balance string true "The balance of the account."
+// @componentprop CodeHash codeHash string true "The hash of the account code."
+// @componentprop Seqno seqno string true "The sequence number of the account."
+// @componentprop ExtSeqno extSeqno string true "The external sequence number of the account."
+// @componentprop StorageRoot storageRoot string true "The root of the account storage trie."
+// @componentprop Contract contract string true "The serialized types.SmartContract structure (empty if the account doesn't exist)."
+// @componentprop AccountProof accountProof string true "The serialized MPT proof of the account against the contract trie root of the block."
+// @componentprop StorageProof storageProof array true "The proofs of the requested storage slots."
+type RPCAccountProof struct {
+	Address      types.Address     `json:"address"`
+	Balance      types.Value       `json:"balance"`
+	CodeHash     common.Hash       `json:"codeHash"`
+	Seqno        hexutil.Uint64    `json:"seqno"`
+	ExtSeqno     hexutil.Uint64    `json:"extSeqno"`
+	StorageRoot  common.Hash       `json:"storageRoot"`
+	Contract     hexutil.Bytes     `json:"contract"`
+	AccountProof hexutil.Bytes     `json:"accountProof"`
+	StorageProof []RPCStorageProof `json:"storageProof"`
+}
+
+// @component FeeHistory feeHistory object "The fee history of the range of blocks."
+// @componentprop OldestBlock oldestBlock integer true "The number of the oldest block in the range."
+// @componentprop BaseFeePerGas baseFeePerGas array true "The base fees of the blocks and of the block following the newest one."
+// @componentprop GasUsedRatio gasUsedRatio array true "The ratios of the gas used to the gas limit of the blocks."
+// @componentprop Reward reward array false "The priority fees at the requested percentiles for each block."
+type FeeHistory struct {
+	OldestBlock   types.BlockNumber `json:"oldestBlock"`
+	BaseFeePerGas []types.Value     `json:"baseFeePerGas"`
+	GasUsedRatio  []float64         `json:"gasUsedRatio"`
+	Reward        [][]types.Value   `json:"reward,omitempty"`
+}
+
+// @component SyncStatus syncStatus object "The progress of the shard sync, or false if the shard is not syncing."
+// @componentprop StartingBlock startingBlock integer true "The number of the last block the sync started from."
+// @componentprop CurrentBlock currentBlock integer true "The number of the last synced block."
+// @componentprop HighestBlock highestBlock integer true "The number of the highest block known to the peers."
+// @componentprop Peers peers array true "The IDs of the peers the blocks are downloaded from."
+type SyncStatus struct {
+	StartingBlock types.BlockNumber `json:"startingBlock"`
+	CurrentBlock  types.BlockNumber `json:"currentBlock"`
+	HighestBlock  types.BlockNumber `json:"highestBlock"`
+	Peers         []string          `json:"peers"`
+}
+
+// TransactionTreeStatus is the status of a transaction in the transaction tree or the verdict for the whole tree.
+type TransactionTreeStatus string
+
+const (
+	// TransactionTreePending is set for the transactions that are not executed yet.
+	// The tree is pending if any of its transactions is pending or is not included in the main chain yet.
+	TransactionTreePending TransactionTreeStatus = "pending"
+	// TransactionTreeSuccess is set for the transactions that are executed successfully.
+	TransactionTreeSuccess TransactionTreeStatus = "success"
+	// TransactionTreeFailed is set for the failed transactions and for the trees that contain any of them.
+	TransactionTreeFailed TransactionTreeStatus = "failed"
+	// TransactionTreeSettled is set for the trees whose transactions are all executed successfully
+	// and included in the main chain.
+	TransactionTreeSettled TransactionTreeStatus = "settled"
+)
+
+// @component RPCTransactionTreeNode rpcTransactionTreeNode object "The transaction of the transaction tree."
+// @componentprop TransactionHash transactionHash string true "The hash of the transaction."
+// @componentprop ShardId shardId integer true "The ID of the shard where the transaction is executed."
+// @componentprop Flags flags string true "The array of transaction flags (empty for pending transactions)."
+// @componentprop From from string true "The address from where the transaction was sent (empty for pending transactions)."
+// @componentprop To to string true "The address where the transaction was sent (empty for pending transactions)."
+// @componentprop Value value string true "The transaction value (zero for pending transactions)."
+// @componentprop Status status string true "The status of the transaction: pending, success or failed."
+// @componentprop ReceiptStatus receiptStatus string false "The concrete error of the executed transaction."
+// @componentprop ErrorMessage errorMessage string false "The error in case the transaction processing was unsuccessful."
+// @componentprop GasUsed gasUsed string true "The amount of gas spent on the transaction."
+// @componentprop BlockHash blockHash string true "The hash of the block containing the transaction."
+// @componentprop BlockNumber blockNumber integer true "The number of the block containing the transaction."
+// @componentprop IncludedInMain includedInMain boolean true "The flag that shows whether the block is included in the main chain."
+// @componentprop Children children array true "The outbound transactions of the transaction."
+type RPCTransactionTreeNode struct {
+	TxnHash        common.Hash               `json:"transactionHash"`
+	ShardId        types.ShardId             `json:"shardId"`
+	Flags          types.TransactionFlags    `json:"flags"`
+	From           types.Address             `json:"from"`
+	To             types.Address             `json:"to"`
+	Value          types.Value               `json:"value"`
+	Status         TransactionTreeStatus     `json:"status"`
+	ReceiptStatus  string                    `json:"receiptStatus,omitempty"`
+	ErrorMessage   string                    `json:"errorMessage,omitempty"`
+	GasUsed        types.Gas                 `json:"gasUsed"`
+	BlockHash      common.Hash               `json:"blockHash"`
+	BlockNumber    types.BlockNumber         `json:"blockNumber"`
+	IncludedInMain bool                      `json:"includedInMain"`
+	Children       []*RPCTransactionTreeNode `json:"children"`
+}
+
+// @component RPCTransactionTree rpcTransactionTree object "The tree of the transactions spawned by the transaction."
+// @componentprop Status status string true "The verdict for the whole tree: settled, pending or failed."
+// @componentprop GasUsed gasUsed string true "The amount of gas spent on all executed transactions of the tree."
+// @componentprop Root root object true "The root transaction of the tree."
+type RPCTransactionTree struct {
+	Status  TransactionTreeStatus   `json:"status"`
+	GasUsed types.Gas               `json:"gasUsed"`
+	Root    *RPCTransactionTreeNode `json:"root"`
+}
+
 // @component OutTransaction outTransaction object "Outbound transaction produced by eth_call and result of its execution."
 // @componentprop Transaction transaction object true "Transaction data"
 // @componentprop Data data string false "Result of VM execution."
@@ -2,11 +2,14 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -87,6 +90,20 @@ func (api *shardApiClientRo) GetContract(
 		ctx, api, "GetContract", address, blockReference)
 }
 
+func (api *shardApiClientRo) GetStorageAt(
+	ctx context.Context, address types.Address, key common.Hash, blockReference rawapitypes.BlockReference,
+) (common.Hash, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[common.Hash](
+		ctx, api, "GetStorageAt", address, key, blockReference)
+}
+
+func (api *shardApiClientRo) GetProof(
+	ctx context.Context, address types.Address, keys []common.Hash, blockReference rawapitypes.BlockReference,
+) (*rawapitypes.AccountProof, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.AccountProof](
+		ctx, api, "GetProof", address, keys, blockReference)
+}
+
 func (api *shardApiClientRo) Call(
 	ctx context.Context,
 	args rpctypes.CallArgs,
@@ -97,6 +114,23 @@ func (api *shardApiClientRo) Call(
 		ctx, api, "Call", args, mainBlockReferenceOrHashWithChildren, overrides)
 }
 
+func (api *shardApiClientRo) TraceTransaction(
+	ctx context.Context, hash common.Hash, config *tracers.TraceConfig,
+) (json.RawMessage, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[json.RawMessage](ctx, api, "TraceTransaction", hash, config)
+}
+
+func (api *shardApiClientRo) TraceCall(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	config *tracers.TraceConfig,
+) (json.RawMessage, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[json.RawMessage](
+		ctx, api, "TraceCall", args, mainBlockReferenceOrHashWithChildren, overrides, config)
+}
+
 func (api *shardApiClientRo) GetInTransaction(
 	ctx context.Context, request rawapitypes.TransactionRequest,
 ) (*rawapitypes.TransactionInfo, error) {
@@ -111,6 +145,27 @@ func (api *shardApiClientRo) GetInTransactionReceipt(
 		ctx, api, "GetInTransactionReceipt", hash)
 }
 
+func (api *shardApiClientRo) GetLogs(
+	ctx context.Context, filter rawapitypes.LogsFilter,
+) ([]*rawapitypes.LogInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]*rawapitypes.LogInfo](
+		ctx, api, "GetLogs", filter)
+}
+
+func (api *shardApiClientRo) GetDoubleSignEvidence(
+	ctx context.Context, fromHeight uint64,
+) ([]*types.DoubleSignEvidence, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]*types.DoubleSignEvidence](
+		ctx, api, "GetDoubleSignEvidence", fromHeight)
+}
+
+func (api *shardApiClientRo) GetStateDiff(
+	ctx context.Context, from, to common.Hash,
+) ([]*execution.ContractDiff, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]*execution.ContractDiff](
+		ctx, api, "GetStateDiff", from, to)
+}
+
 func (api *shardApiClientRo) GasPrice(ctx context.Context) (types.Value, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[types.Value](ctx, api, "GasPrice")
 }
@@ -123,6 +178,10 @@ func (api *shardApiClientRo) GetNumShards(ctx context.Context) (uint64, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[uint64](ctx, api, "GetNumShards")
 }
 
+func (api *shardApiClientRo) GetSyncProgress(ctx context.Context) (rawapitypes.SyncProgress, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[rawapitypes.SyncProgress](ctx, api, "GetSyncProgress")
+}
+
 func (api *shardApiClientRo) ClientVersion(ctx context.Context) (string, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[string](ctx, api, "ClientVersion")
 }
@@ -48,10 +48,10 @@ func (api *shardApiClientRw) GetTransactionCount(
 		ctx, api, "GetTransactionCount", address, blockReference)
 }
 
-func (api *shardApiClientRw) GetTxpoolStatus(ctx context.Context) (uint64, error) {
-	return sendRequestAndGetResponseWithCallerMethodName[uint64](ctx, api, "GetTxpoolStatus")
+func (api *shardApiClientRw) GetTxpoolStatus(ctx context.Context) (rawapitypes.TxPoolStatus, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[rawapitypes.TxPoolStatus](ctx, api, "GetTxpoolStatus")
 }
 
-func (api *shardApiClientRw) GetTxpoolContent(ctx context.Context) ([]*types.Transaction, error) {
-	return sendRequestAndGetResponseWithCallerMethodName[[]*types.Transaction](ctx, api, "GetTxpoolContent")
+func (api *shardApiClientRw) GetTxpoolContent(ctx context.Context) (rawapitypes.TxPoolContent, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[rawapitypes.TxPoolContent](ctx, api, "GetTxpoolContent")
 }
@@ -13,6 +13,9 @@ import (
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 )
 
+// MaxProofStorageKeys limits the number of storage slots proven by a single GetProof request.
+const MaxProofStorageKeys = 1024
+
 var errBlockNotFound = errors.New("block not found")
 
 func (api *localShardApiRo) GetBalance(
@@ -189,6 +192,118 @@ func (api *localShardApiRo) GetContract(
 	}, nil
 }
 
+func (api *localShardApiRo) GetStorageAt(
+	ctx context.Context,
+	address types.Address,
+	key common.Hash,
+	blockReference rawapitypes.BlockReference,
+) (common.Hash, error) {
+	shardId := address.ShardId()
+	if shardId != api.shardId() {
+		return common.EmptyHash, fmt.Errorf("address is not in the shard %d", api.shard)
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return common.EmptyHash, fmt.Errorf("cannot open tx to find account: %w", err)
+	}
+	defer tx.Rollback()
+
+	acc, err := api.getSmartContract(tx, address, blockReference)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return common.EmptyHash, nil
+		}
+		return common.EmptyHash, err
+	}
+
+	storageReader := execution.NewDbStorageTrieReader(tx, shardId)
+	storageReader.SetRootHash(acc.StorageRoot)
+	value, err := storageReader.Fetch(key)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return common.EmptyHash, nil
+		}
+		return common.EmptyHash, err
+	}
+	return value.Bytes32(), nil
+}
+
+func (api *localShardApiRo) GetProof(
+	ctx context.Context,
+	address types.Address,
+	keys []common.Hash,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.AccountProof, error) {
+	shardId := address.ShardId()
+	if shardId != api.shardId() {
+		return nil, fmt.Errorf("address is not in the shard %d", api.shard)
+	}
+	if len(keys) > MaxProofStorageKeys {
+		return nil, fmt.Errorf("too many storage keys requested: %d > %d", len(keys), MaxProofStorageKeys)
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open tx to find account: %w", err)
+	}
+	defer tx.Rollback()
+
+	contractRaw, proofBuilder, err := api.getRawSmartContract(tx, address, blockReference)
+	if err != nil && proofBuilder == nil {
+		return nil, err
+	}
+
+	accountProof, err := proofBuilder(mpt.ReadMPTOperation)
+	if err != nil {
+		return nil, err
+	}
+	encodedProof, err := accountProof.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &rawapitypes.AccountProof{
+		ContractSSZ:   contractRaw,
+		ProofEncoded:  encodedProof,
+		StorageProofs: make([]rawapitypes.StorageProof, len(keys)),
+	}
+	for i, key := range keys {
+		res.StorageProofs[i].Key = key
+	}
+
+	// The absence of the contract is already proven, so the storage proofs are left empty.
+	if contractRaw == nil {
+		return res, nil
+	}
+
+	contract := new(types.SmartContract)
+	if err := contract.UnmarshalSSZ(contractRaw); err != nil {
+		return nil, err
+	}
+
+	storageReader := execution.NewDbStorageTrieReader(tx, shardId)
+	storageReader.SetRootHash(contract.StorageRoot)
+	for i, key := range keys {
+		value, err := storageReader.Fetch(key)
+		switch {
+		case err == nil:
+			res.StorageProofs[i].Value = value.Bytes32()
+		case !errors.Is(err, db.ErrKeyNotFound):
+			return nil, err
+		}
+
+		proof, err := mpt.BuildProof(storageReader.Reader, key.Bytes(), mpt.ReadMPTOperation)
+		if err != nil {
+			return nil, err
+		}
+		if res.StorageProofs[i].ProofEncoded, err = proof.Encode(); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
 type proofBuilder = func(operation mpt.MPTOperation) (mpt.Proof, error)
 
 func makeProofBuilder(root *mpt.Reader, key []byte) proofBuilder {
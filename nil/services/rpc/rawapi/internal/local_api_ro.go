@@ -9,6 +9,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 )
 
 type localShardApiRo struct {
@@ -16,6 +17,9 @@ type localShardApiRo struct {
 	accessor *execution.StateAccessor
 	shard    types.ShardId
 
+	// syncProgress reports the progress of the shard syncer; it is nil if the node does not sync the shard.
+	syncProgress func() rawapitypes.SyncProgress
+
 	nodeApi NodeApi
 	logger  logging.Logger
 }
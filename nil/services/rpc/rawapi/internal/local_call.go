@@ -163,17 +163,23 @@ func (api *localShardApiRo) handleOutTransactions(
 	return outTransactions, nil
 }
 
-func (api *localShardApiRo) Call(
-	ctx context.Context, args rpctypes.CallArgs,
+// callContext is the state prepared for the execution of eth_call-like requests.
+type callContext struct {
+	es            *execution.ExecutionState
+	txn           *types.Transaction
+	payer         execution.Payer
+	block         *types.Block
+	mainBlockHash common.Hash
+	childBlocks   []common.Hash
+}
+
+func (api *localShardApiRo) prepareCall(
+	ctx context.Context,
+	tx db.RoTx,
+	args rpctypes.CallArgs,
 	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
 	overrides *rpctypes.StateOverrides,
-) (*rpctypes.CallResWithGasPrice, error) {
-	tx, err := api.db.CreateRoTx(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer tx.Rollback()
-
+) (*callContext, error) {
 	txn, err := args.ToTransaction()
 	if err != nil {
 		return nil, err
@@ -267,8 +273,36 @@ func (api *localShardApiRo) Call(
 	}
 
 	txn.TxId = es.InTxCounts[txn.From.ShardId()]
+
+	return &callContext{
+		es:            es,
+		txn:           txn,
+		payer:         payer,
+		block:         block,
+		mainBlockHash: mainBlockHash,
+		childBlocks:   childBlocks,
+	}, nil
+}
+
+func (api *localShardApiRo) Call(
+	ctx context.Context, args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+) (*rpctypes.CallResWithGasPrice, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	call, err := api.prepareCall(ctx, tx, args, mainBlockReferenceOrHashWithChildren, overrides)
+	if err != nil {
+		return nil, err
+	}
+	es, txn := call.es, call.txn
+
 	txnHash := es.AddInTransaction(txn)
-	res := es.HandleTransaction(ctx, txn, payer)
+	res := es.HandleTransaction(ctx, txn, call.payer)
 
 	result := &rpctypes.CallResWithGasPrice{
 		Data:      res.ReturnData,
@@ -282,8 +316,8 @@ func (api *localShardApiRo) Call(
 		return result, nil
 	}
 
-	esOld, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
-		Block:          block,
+	esOld, err := execution.NewExecutionState(tx, es.ShardId, execution.StateParams{
+		Block:          call.block,
 		ConfigAccessor: config.GetStubAccessor(),
 		Mode:           execution.ModeReadOnly,
 	})
@@ -299,8 +333,8 @@ func (api *localShardApiRo) Call(
 	outTransactions, err := api.handleOutTransactions(
 		ctx,
 		execOutTransactions,
-		mainBlockHash,
-		childBlocks,
+		call.mainBlockHash,
+		call.childBlocks,
 		&stateOverrides,
 	)
 	if err != nil {
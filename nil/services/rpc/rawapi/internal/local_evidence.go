@@ -0,0 +1,24 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// MaxDoubleSignEvidenceResults limits the number of evidence entries returned by a single request.
+const MaxDoubleSignEvidenceResults = 1000
+
+func (api *localShardApiRo) GetDoubleSignEvidence(
+	ctx context.Context, fromHeight uint64,
+) ([]*types.DoubleSignEvidence, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	return db.ReadDoubleSignEvidence(tx, api.shardId(), fromHeight, MaxDoubleSignEvidenceResults)
+}
@@ -0,0 +1,207 @@
+package internal
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+const (
+	// MaxLogsBlockRange limits the number of blocks scanned by a single GetLogs request.
+	MaxLogsBlockRange = 10_000
+	// MaxLogsResults limits the number of logs returned by a single GetLogs request.
+	MaxLogsResults = 10_000
+)
+
+var (
+	errLogsBlockRangeTooWide = fmt.Errorf("block range is too wide, maximum is %d blocks", MaxLogsBlockRange)
+	errLogsTooManyResults    = fmt.Errorf(
+		"query returned more than %d results, narrow the block range", MaxLogsResults)
+)
+
+func (api *localShardApiRo) GetLogs(
+	ctx context.Context,
+	filter rawapitypes.LogsFilter,
+) ([]*rawapitypes.LogInfo, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	collector := logsCollector{
+		tx:      tx,
+		shardId: api.shardId(),
+		filter:  &filter,
+		result:  make([]*rawapitypes.LogInfo, 0),
+	}
+
+	if filter.BlockHash != nil {
+		block, err := db.ReadBlock(tx, api.shardId(), *filter.BlockHash)
+		if err != nil {
+			return nil, err
+		}
+		if err := collector.collectBlock(block, *filter.BlockHash); err != nil {
+			return nil, err
+		}
+		return collector.result, nil
+	}
+
+	from, to, err := api.resolveLogsRange(tx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return collector.result, nil
+	}
+
+	// Blocks are checked against the bloom index first. Blocks absent from the index (e.g., those committed
+	// before the index was introduced and not rebuilt yet) are checked by the bloom stored in the block itself.
+	next := from
+	err = db.IterBlockLogsBloom(tx, api.shardId(), from, to,
+		func(id types.BlockNumber, hash common.Hash, bloom types.Bloom) (bool, error) {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
+			if err := collector.collectRange(next, id); err != nil {
+				return false, err
+			}
+			next = id + 1
+			if !collector.matchBloom(bloom) {
+				return true, nil
+			}
+			block, err := db.ReadBlock(tx, api.shardId(), hash)
+			if err != nil {
+				return false, err
+			}
+			return true, collector.collectBlock(block, hash)
+		})
+	if err != nil {
+		return nil, err
+	}
+	if next <= to {
+		if err := collector.collectRange(next, to+1); err != nil {
+			return nil, err
+		}
+	}
+	return collector.result, nil
+}
+
+func (api *localShardApiRo) resolveLogsRange(
+	tx db.RoTx, filter rawapitypes.LogsFilter,
+) (types.BlockNumber, types.BlockNumber, error) {
+	lastBlock, _, err := db.ReadLastBlock(tx, api.shardId())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	from, to := lastBlock.Id, lastBlock.Id
+	if filter.FromBlock != nil {
+		from = *filter.FromBlock
+	}
+	if filter.ToBlock != nil && *filter.ToBlock < to {
+		to = *filter.ToBlock
+	}
+
+	if to >= from && to-from >= MaxLogsBlockRange {
+		return 0, 0, errLogsBlockRangeTooWide
+	}
+	return from, to, nil
+}
+
+type logsCollector struct {
+	tx      db.RoTx
+	shardId types.ShardId
+	filter  *rawapitypes.LogsFilter
+	result  []*rawapitypes.LogInfo
+}
+
+// collectRange processes blocks in [from, to) by reading them directly, bypassing the bloom index.
+func (c *logsCollector) collectRange(from, to types.BlockNumber) error {
+	for id := from; id < to; id++ {
+		block, err := db.ReadBlockByNumber(c.tx, c.shardId, id)
+		if errors.Is(err, db.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !c.matchBloom(block.LogsBloom) {
+			continue
+		}
+		if err := c.collectBlock(block, block.Hash(c.shardId)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *logsCollector) collectBlock(block *types.Block, blockHash common.Hash) error {
+	reader := execution.NewDbReceiptTrieReader(c.tx, c.shardId)
+	reader.SetRootHash(block.ReceiptsRoot)
+	entries, err := reader.Entries()
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(entries, func(a, b execution.Entry[types.TransactionIndex, *types.Receipt]) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+
+	var logIndex uint64
+	for _, entry := range entries {
+		for _, log := range entry.Val.Logs {
+			if c.match(log) {
+				if len(c.result) == MaxLogsResults {
+					return errLogsTooManyResults
+				}
+				c.result = append(c.result, &rawapitypes.LogInfo{
+					Log:       log,
+					BlockId:   block.Id,
+					BlockHash: blockHash,
+					TxnHash:   entry.Val.TxnHash,
+					TxnIndex:  entry.Key,
+					LogIndex:  logIndex,
+				})
+			}
+			logIndex++
+		}
+	}
+	return nil
+}
+
+func (c *logsCollector) matchBloom(bloom types.Bloom) bool {
+	if len(c.filter.Addresses) > 0 &&
+		!slices.ContainsFunc(c.filter.Addresses, func(addr types.Address) bool { return bloom.Test(addr.Bytes()) }) {
+		return false
+	}
+	for _, topics := range c.filter.Topics {
+		if len(topics) > 0 &&
+			!slices.ContainsFunc(topics, func(topic common.Hash) bool { return bloom.Test(topic.Bytes()) }) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *logsCollector) match(log *types.Log) bool {
+	if len(c.filter.Addresses) > 0 && !slices.Contains(c.filter.Addresses, log.Address) {
+		return false
+	}
+	if len(c.filter.Topics) > log.TopicsNum() {
+		return false
+	}
+	for i, topics := range c.filter.Topics {
+		if len(topics) > 0 && !slices.Contains(topics, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// GetStateDiff returns the contracts changed by the blocks after `from` up to `to` inclusive.
+// The empty `from` hash means the empty state.
+func (api *localShardApiRo) GetStateDiff(
+	ctx context.Context, from, to common.Hash,
+) ([]*execution.ContractDiff, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	shardId := api.shardId()
+	var fromBlock *types.Block
+	if !from.Empty() {
+		fromBlock, err = db.ReadBlock(tx, shardId, from)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read block %s: %w", from, err)
+		}
+	}
+	toBlock, err := db.ReadBlock(tx, shardId, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block %s: %w", to, err)
+	}
+	if fromBlock != nil && fromBlock.Id > toBlock.Id {
+		return nil, fmt.Errorf("block %s is older than block %s", to, from)
+	}
+	return execution.GetStateDiff(tx, shardId, fromBlock, toBlock)
+}
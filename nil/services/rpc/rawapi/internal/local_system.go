@@ -9,6 +9,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 )
 
 func (api *localShardApiRo) GasPrice(ctx context.Context) (types.Value, error) {
@@ -29,6 +30,13 @@ func (api *localShardApiRo) GasPrice(ctx context.Context) (types.Value, error) {
 	return types.Value{Uint256: &param.Shards[api.shardId()]}, nil
 }
 
+func (api *localShardApiRo) GetSyncProgress(ctx context.Context) (rawapitypes.SyncProgress, error) {
+	if api.syncProgress == nil {
+		return rawapitypes.SyncProgress{}, nil
+	}
+	return api.syncProgress(), nil
+}
+
 func (api *localShardApiRo) GetShardIdList(ctx context.Context) ([]types.ShardId, error) {
 	if api.shardId() != types.MainShardId {
 		return nil, errors.New("GetShardIdList is only supported for the main shard")
@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+)
+
+var errCannotTraceGenesis = errors.New("transactions of the genesis block can't be traced")
+
+// TraceTransaction re-executes the block that includes the transaction on top of the state of its parent block,
+// so that the transaction sees exactly the same state as during the block generation.
+func (api *localShardApiRo) TraceTransaction(
+	ctx context.Context, hash common.Hash, traceConfig *tracers.TraceConfig,
+) (json.RawMessage, error) {
+	tracer, err := tracers.New(traceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	shardId := api.shardId()
+	txnData, err := api.accessor.Access(tx, shardId).GetInTransaction().ByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	block := txnData.Block()
+	if block.Id == 0 {
+		return nil, errCannotTraceGenesis
+	}
+
+	blockData, err := api.accessor.Access(tx, shardId).GetBlock().WithInTransactions().ByHash(block.Hash(shardId))
+	if err != nil {
+		return nil, err
+	}
+	prevBlock, err := db.ReadBlock(tx, shardId, block.PrevBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read previous block %s: %w", block.PrevBlock, err)
+	}
+
+	configAccessor, err := config.NewConfigAccessorFromBlockWithTx(tx, prevBlock, shardId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config accessor: %w", err)
+	}
+
+	// The state is not read-only so that the transactions are validated the same way as during the block generation.
+	// It is never committed, so nothing is written to the database.
+	rwTx := &db.RwWrapper{RoTx: tx}
+	es, err := execution.NewExecutionState(rwTx, shardId, execution.StateParams{
+		Block:          prevBlock,
+		ConfigAccessor: configAccessor,
+		Mode:           execution.ModeReadOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	gen, err := execution.NewBlockGeneratorWithEs(
+		ctx, execution.NewBlockGeneratorParams(shardId, 0), nil, rwTx, es)
+	if err != nil {
+		return nil, err
+	}
+	gasPrices, err := gen.CollectGasPrices(prevBlock.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect gas prices: %w", err)
+	}
+
+	proposal := &execution.Proposal{
+		PrevBlockId:     prevBlock.Id,
+		PrevBlockHash:   block.PrevBlock,
+		PatchLevel:      block.PatchLevel,
+		RollbackCounter: block.RollbackCounter,
+		MainShardHash:   block.MainShardHash,
+	}
+	proposal.InternalTxns, proposal.ExternalTxns = execution.SplitInTransactions(blockData.InTransactions())
+
+	res, err := gen.ReplayTransaction(proposal, gasPrices, hash, tracer.Hooks)
+	if err != nil {
+		return nil, err
+	}
+	if res.FatalError != nil {
+		return nil, res.FatalError
+	}
+
+	if tracer.OnOutTransactions != nil {
+		tracer.OnOutTransactions(es.OutTransactions[hash])
+	}
+	return tracer.GetResult()
+}
+
+// TraceCall executes the call the same way as eth_call does and traces it.
+// The outbound transactions are not executed, but they are included in the trace if the tracer supports them.
+func (api *localShardApiRo) TraceCall(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	traceConfig *tracers.TraceConfig,
+) (json.RawMessage, error) {
+	tracer, err := tracers.New(traceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	call, err := api.prepareCall(ctx, tx, args, mainBlockReferenceOrHashWithChildren, overrides)
+	if err != nil {
+		return nil, err
+	}
+	es := call.es
+
+	txnHash := es.AddInTransaction(call.txn)
+	es.EvmTracingHooks = tracer.Hooks
+	res := es.HandleTransaction(ctx, call.txn, call.payer)
+	if res.FatalError != nil {
+		return nil, res.FatalError
+	}
+
+	if tracer.OnOutTransactions != nil {
+		tracer.OnOutTransactions(es.OutTransactions[txnHash])
+	}
+	return tracer.GetResult()
+}
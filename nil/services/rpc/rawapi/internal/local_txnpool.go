@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	"github.com/NilFoundation/nil/nil/services/txnpool"
 )
 
@@ -26,18 +28,16 @@ func (api *localShardApiRw) SendTransaction(ctx context.Context, encoded []byte)
 	return reasons[0], nil
 }
 
-func (api *localShardApiRw) GetTxpoolStatus(ctx context.Context) (uint64, error) {
-	return uint64(api.txnpool.GetSize()), nil
+func (api *localShardApiRw) GetTxpoolStatus(ctx context.Context) (rawapitypes.TxPoolStatus, error) {
+	pending, queued := api.txnpool.Stats()
+	return rawapitypes.TxPoolStatus{Pending: uint64(pending), Queued: uint64(queued)}, nil
 }
 
-func (api *localShardApiRw) GetTxpoolContent(ctx context.Context) ([]*types.Transaction, error) {
-	txns, err := api.txnpool.Peek(api.txnpool.GetSize())
-	if err != nil {
-		return nil, err
-	}
-	res := make([]*types.Transaction, len(txns))
-	for i, txn := range txns {
-		res[i] = txn.Transaction
+func (api *localShardApiRw) GetTxpoolContent(ctx context.Context) (rawapitypes.TxPoolContent, error) {
+	pending, queued := api.txnpool.Content()
+	discarded := make(map[common.Hash]string)
+	for hash, reason := range api.txnpool.Discarded() {
+		discarded[hash] = reason.String()
 	}
-	return res, nil
+	return rawapitypes.TxPoolContent{Pending: pending, Queued: queued, Discarded: discarded}, nil
 }
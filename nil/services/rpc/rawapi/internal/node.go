@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -9,7 +10,9 @@ import (
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -167,6 +170,44 @@ func (api *nodeApiOverShardApis) GetContract(
 	return result, nil
 }
 
+func (api *nodeApiOverShardApis) GetStorageAt(
+	ctx context.Context,
+	address types.Address,
+	key common.Hash,
+	blockReference rawapitypes.BlockReference,
+) (common.Hash, error) {
+	methodName := methodNameChecked("GetStorageAt")
+	shardId := address.ShardId()
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return common.EmptyHash, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetStorageAt(ctx, address, key, blockReference)
+	if err != nil {
+		return common.EmptyHash, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetProof(
+	ctx context.Context,
+	address types.Address,
+	keys []common.Hash,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.AccountProof, error) {
+	methodName := methodNameChecked("GetProof")
+	shardId := address.ShardId()
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetProof(ctx, address, keys, blockReference)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *nodeApiOverShardApis) Call(
 	ctx context.Context,
 	args rpctypes.CallArgs,
@@ -192,6 +233,50 @@ func (api *nodeApiOverShardApis) Call(
 	return result, nil
 }
 
+func (api *nodeApiOverShardApis) TraceTransaction(
+	ctx context.Context,
+	shardId types.ShardId,
+	hash common.Hash,
+	config *tracers.TraceConfig,
+) (json.RawMessage, error) {
+	methodName := methodNameChecked("TraceTransaction")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.TraceTransaction(ctx, hash, config)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) TraceCall(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	config *tracers.TraceConfig,
+) (json.RawMessage, error) {
+	methodName := methodNameChecked("TraceCall")
+
+	txn, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	shardId := txn.To.ShardId()
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.TraceCall(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, config)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *nodeApiOverShardApis) GetInTransaction(
 	ctx context.Context,
 	shardId types.ShardId,
@@ -226,6 +311,58 @@ func (api *nodeApiOverShardApis) GetInTransactionReceipt(
 	return result, nil
 }
 
+func (api *nodeApiOverShardApis) GetLogs(
+	ctx context.Context,
+	shardId types.ShardId,
+	filter rawapitypes.LogsFilter,
+) ([]*rawapitypes.LogInfo, error) {
+	methodName := methodNameChecked("GetLogs")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetLogs(ctx, filter)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetDoubleSignEvidence(
+	ctx context.Context,
+	shardId types.ShardId,
+	fromHeight uint64,
+) ([]*types.DoubleSignEvidence, error) {
+	methodName := methodNameChecked("GetDoubleSignEvidence")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetDoubleSignEvidence(ctx, fromHeight)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetStateDiff(
+	ctx context.Context,
+	shardId types.ShardId,
+	from common.Hash,
+	to common.Hash,
+) ([]*execution.ContractDiff, error) {
+	methodName := methodNameChecked("GetStateDiff")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetStateDiff(ctx, from, to)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *nodeApiOverShardApis) GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error) {
 	methodName := methodNameChecked("GasPrice")
 	shardApi, ok := api.apisRo[shardId]
@@ -267,6 +404,21 @@ func (api *nodeApiOverShardApis) GetNumShards(ctx context.Context) (uint64, erro
 	return result, nil
 }
 
+func (api *nodeApiOverShardApis) GetSyncProgress(
+	ctx context.Context, shardId types.ShardId,
+) (rawapitypes.SyncProgress, error) {
+	methodName := methodNameChecked("GetSyncProgress")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return rawapitypes.SyncProgress{}, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetSyncProgress(ctx)
+	if err != nil {
+		return rawapitypes.SyncProgress{}, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
 func (api *nodeApiOverShardApis) GetTransactionCount(
 	ctx context.Context,
 	address types.Address,
@@ -325,15 +477,18 @@ func (api *nodeApiOverShardApis) DoPanicOnShard(ctx context.Context, shardId typ
 	return shardApi.DoPanicOnShard(ctx)
 }
 
-func (api *nodeApiOverShardApis) GetTxpoolStatus(ctx context.Context, shardId types.ShardId) (uint64, error) {
+func (api *nodeApiOverShardApis) GetTxpoolStatus(
+	ctx context.Context,
+	shardId types.ShardId,
+) (rawapitypes.TxPoolStatus, error) {
 	methodName := methodNameChecked("GetTxpoolStatus")
 	shardApi, ok := api.apisRw[shardId]
 	if !ok {
-		return 0, makeShardNotFoundError(methodName, shardId)
+		return rawapitypes.TxPoolStatus{}, makeShardNotFoundError(methodName, shardId)
 	}
 	result, err := shardApi.GetTxpoolStatus(ctx)
 	if err != nil {
-		return 0, makeCallError(methodName, shardId, err)
+		return rawapitypes.TxPoolStatus{}, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
@@ -341,15 +496,15 @@ func (api *nodeApiOverShardApis) GetTxpoolStatus(ctx context.Context, shardId ty
 func (api *nodeApiOverShardApis) GetTxpoolContent(
 	ctx context.Context,
 	shardId types.ShardId,
-) ([]*types.Transaction, error) {
+) (rawapitypes.TxPoolContent, error) {
 	methodName := methodNameChecked("GetTxpoolContent")
 	shardApi, ok := api.apisRw[shardId]
 	if !ok {
-		return nil, makeShardNotFoundError(methodName, shardId)
+		return rawapitypes.TxPoolContent{}, makeShardNotFoundError(methodName, shardId)
 	}
 	result, err := shardApi.GetTxpoolContent(ctx)
 	if err != nil {
-		return nil, makeCallError(methodName, shardId, err)
+		return rawapitypes.TxPoolContent{}, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
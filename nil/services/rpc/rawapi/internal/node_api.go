@@ -2,11 +2,14 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -34,6 +37,12 @@ type NodeApi interface {
 	) (*rawapitypes.TransactionInfo, error)
 	GetInTransactionReceipt(
 		ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.ReceiptInfo, error)
+	GetLogs(
+		ctx context.Context, shardId types.ShardId, filter rawapitypes.LogsFilter) ([]*rawapitypes.LogInfo, error)
+	GetDoubleSignEvidence(
+		ctx context.Context, shardId types.ShardId, fromHeight uint64) ([]*types.DoubleSignEvidence, error)
+	GetStateDiff(
+		ctx context.Context, shardId types.ShardId, from, to common.Hash) ([]*execution.ContractDiff, error)
 
 	GetBalance(
 		ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Value, error)
@@ -50,6 +59,18 @@ type NodeApi interface {
 		address types.Address,
 		blockReference rawapitypes.BlockReference,
 	) (*rawapitypes.SmartContract, error)
+	GetStorageAt(
+		ctx context.Context,
+		address types.Address,
+		key common.Hash,
+		blockReference rawapitypes.BlockReference,
+	) (common.Hash, error)
+	GetProof(
+		ctx context.Context,
+		address types.Address,
+		keys []common.Hash,
+		blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.AccountProof, error)
 
 	Call(
 		ctx context.Context,
@@ -57,15 +78,26 @@ type NodeApi interface {
 		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
 		overrides *rpctypes.StateOverrides,
 	) (*rpctypes.CallResWithGasPrice, error)
+	TraceTransaction(
+		ctx context.Context, shardId types.ShardId, hash common.Hash, config *tracers.TraceConfig,
+	) (json.RawMessage, error)
+	TraceCall(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+		config *tracers.TraceConfig,
+	) (json.RawMessage, error)
 
 	GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error)
 	GetShardIdList(ctx context.Context) ([]types.ShardId, error)
 	GetNumShards(ctx context.Context) (uint64, error)
+	GetSyncProgress(ctx context.Context, shardId types.ShardId) (rawapitypes.SyncProgress, error)
 
 	ClientVersion(ctx context.Context) (string, error)
 
-	GetTxpoolStatus(ctx context.Context, shardId types.ShardId) (uint64, error)
-	GetTxpoolContent(ctx context.Context, shardId types.ShardId) ([]*types.Transaction, error)
+	GetTxpoolStatus(ctx context.Context, shardId types.ShardId) (rawapitypes.TxPoolStatus, error)
+	GetTxpoolContent(ctx context.Context, shardId types.ShardId) (rawapitypes.TxPoolContent, error)
 
 	SendTransaction(ctx context.Context, shardId types.ShardId, transaction []byte) (txnpool.DiscardReason, error)
 	DoPanicOnShard(ctx context.Context, shardId types.ShardId) (uint64, error)
@@ -5,6 +5,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	"github.com/NilFoundation/nil/nil/services/txnpool"
 )
 
@@ -14,6 +15,8 @@ type nodeApiBuilder struct {
 	// common dependencies
 	db             db.ReadOnlyDB
 	networkManager network.Manager
+
+	syncProgress map[types.ShardId]func() rawapitypes.SyncProgress
 }
 
 func NodeApiBuilder(db db.DB, networkManager network.Manager) *nodeApiBuilder {
@@ -26,6 +29,7 @@ func NodeApiBuilder(db db.DB, networkManager network.Manager) *nodeApiBuilder {
 		},
 		db:             db,
 		networkManager: networkManager,
+		syncProgress:   make(map[types.ShardId]func() rawapitypes.SyncProgress),
 	}
 }
 
@@ -38,8 +42,19 @@ func (nb *nodeApiBuilder) BuildAndReset() NodeApi {
 	return &rv
 }
 
+// WithSyncProgress sets the source of the sync progress of the shard.
+// It must be called before the local API of the shard is added.
+func (nb *nodeApiBuilder) WithSyncProgress(
+	shardId types.ShardId, progress func() rawapitypes.SyncProgress,
+) *nodeApiBuilder {
+	nb.syncProgress[shardId] = progress
+	return nb
+}
+
 func (nb *nodeApiBuilder) WithLocalShardApiRo(shardId types.ShardId) *nodeApiBuilder {
-	var localShardApi shardApiRo = newLocalShardApiRo(shardId, nb.db)
+	api := newLocalShardApiRo(shardId, nb.db)
+	api.syncProgress = nb.syncProgress[shardId]
+	var localShardApi shardApiRo = api
 	if assert.Enable {
 		localShardApi = newShardApiClientDirectEmulatorRo(localShardApi)
 	}
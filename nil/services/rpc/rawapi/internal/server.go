@@ -25,17 +25,25 @@ type NetworkTransportProtocolRo interface {
 
 	GetInTransaction(pb.TransactionRequest) pb.TransactionResponse
 	GetInTransactionReceipt(pb.Hash) pb.ReceiptResponse
+	GetLogs(pb.LogsRequest) pb.LogsResponse
+	GetDoubleSignEvidence(pb.DoubleSignEvidenceRequest) pb.DoubleSignEvidenceResponse
+	GetStateDiff(pb.StateDiffRequest) pb.StateDiffResponse
 
 	GetBalance(request pb.AccountRequest) pb.BalanceResponse
 	GetCode(request pb.AccountRequest) pb.CodeResponse
 	GetTokens(request pb.AccountRequest) pb.TokensResponse
 	GetContract(request pb.AccountRequest) pb.RawContractResponse
+	GetStorageAt(request pb.StorageAtRequest) pb.StorageAtResponse
+	GetProof(request pb.ProofRequest) pb.AccountProofResponse
 
 	Call(pb.CallRequest) pb.CallResponse
+	TraceTransaction(pb.TraceTransactionRequest) pb.TraceResponse
+	TraceCall(pb.TraceCallRequest) pb.TraceResponse
 
 	GasPrice() pb.GasPriceResponse
 	GetShardIdList() pb.ShardIdListResponse
 	GetNumShards() pb.Uint64Response
+	GetSyncProgress() pb.SyncProgressResponse
 
 	ClientVersion() pb.StringResponse
 }
@@ -44,8 +52,8 @@ type NetworkTransportProtocolRw interface {
 	SendTransaction(pb.SendTransactionRequest) pb.SendTransactionResponse
 	GetTransactionCount(pb.AccountRequest) pb.Uint64Response
 
-	GetTxpoolStatus() pb.Uint64Response
-	GetTxpoolContent() pb.RawTxnsResponse
+	GetTxpoolStatus() pb.TxPoolStatusResponse
+	GetTxpoolContent() pb.TxPoolContentResponse
 }
 
 type NetworkTransportProtocolDev interface {
@@ -2,11 +2,14 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -32,6 +35,9 @@ type shardApiRo interface {
 	GetInTransaction(
 		ctx context.Context, transactionRequest rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error)
 	GetInTransactionReceipt(ctx context.Context, hash common.Hash) (*rawapitypes.ReceiptInfo, error)
+	GetLogs(ctx context.Context, filter rawapitypes.LogsFilter) ([]*rawapitypes.LogInfo, error)
+	GetDoubleSignEvidence(ctx context.Context, fromHeight uint64) ([]*types.DoubleSignEvidence, error)
+	GetStateDiff(ctx context.Context, from, to common.Hash) ([]*execution.ContractDiff, error)
 
 	GetBalance(
 		ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Value, error)
@@ -46,6 +52,18 @@ type shardApiRo interface {
 		address types.Address,
 		blockReference rawapitypes.BlockReference,
 	) (*rawapitypes.SmartContract, error)
+	GetStorageAt(
+		ctx context.Context,
+		address types.Address,
+		key common.Hash,
+		blockReference rawapitypes.BlockReference,
+	) (common.Hash, error)
+	GetProof(
+		ctx context.Context,
+		address types.Address,
+		keys []common.Hash,
+		blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.AccountProof, error)
 
 	Call(
 		ctx context.Context,
@@ -53,10 +71,19 @@ type shardApiRo interface {
 		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
 		overrides *rpctypes.StateOverrides,
 	) (*rpctypes.CallResWithGasPrice, error)
+	TraceTransaction(ctx context.Context, hash common.Hash, config *tracers.TraceConfig) (json.RawMessage, error)
+	TraceCall(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+		config *tracers.TraceConfig,
+	) (json.RawMessage, error)
 
 	GasPrice(ctx context.Context) (types.Value, error)
 	GetShardIdList(ctx context.Context) ([]types.ShardId, error)
 	GetNumShards(ctx context.Context) (uint64, error)
+	GetSyncProgress(ctx context.Context) (rawapitypes.SyncProgress, error)
 
 	ClientVersion(ctx context.Context) (string, error)
 }
@@ -70,8 +97,8 @@ type shardApiRw interface {
 	GetTransactionCount(
 		ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (uint64, error)
 
-	GetTxpoolStatus(ctx context.Context) (uint64, error)
-	GetTxpoolContent(ctx context.Context) ([]*types.Transaction, error)
+	GetTxpoolStatus(ctx context.Context) (rawapitypes.TxPoolStatus, error)
+	GetTxpoolContent(ctx context.Context) (rawapitypes.TxPoolContent, error)
 }
 
 const apiNameDev = "rawapi_dev"
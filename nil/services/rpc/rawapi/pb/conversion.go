@@ -2,6 +2,7 @@ package pb
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"unicode/utf8"
 
@@ -11,6 +12,8 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/common/sszx"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/tracing/tracers"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -607,6 +610,148 @@ func (rcr *RawContractResponse) UnpackProtoMessage() (*rawapitypes.SmartContract
 	return nil, errors.New("unexpected response type")
 }
 
+// StorageAtRequest converters
+
+func (r *StorageAtRequest) PackProtoMessage(
+	address types.Address, key common.Hash, blockReference rawapitypes.BlockReference,
+) error {
+	r.Address = new(Address).PackProtoMessage(address)
+	r.Key = new(Hash)
+	if err := r.Key.PackProtoMessage(key); err != nil {
+		return err
+	}
+	r.BlockReference = &BlockReference{}
+	return r.GetBlockReference().PackProtoMessage(blockReference)
+}
+
+func (r *StorageAtRequest) UnpackProtoMessage() (types.Address, common.Hash, rawapitypes.BlockReference, error) {
+	key, err := r.GetKey().UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, common.EmptyHash, rawapitypes.BlockReference{}, err
+	}
+	blockReference, err := r.GetBlockReference().UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, common.EmptyHash, rawapitypes.BlockReference{}, err
+	}
+	return r.GetAddress().UnpackProtoMessage(), key, blockReference, nil
+}
+
+// StorageAtResponse converters
+
+func (r *StorageAtResponse) PackProtoMessage(value common.Hash, err error) error {
+	if err != nil {
+		r.Result = &StorageAtResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := new(Hash)
+	if err := data.PackProtoMessage(value); err != nil {
+		return err
+	}
+	r.Result = &StorageAtResponse_Data{Data: data}
+	return nil
+}
+
+func (r *StorageAtResponse) UnpackProtoMessage() (common.Hash, error) {
+	switch r.GetResult().(type) {
+	case *StorageAtResponse_Error:
+		return common.EmptyHash, r.GetError().UnpackProtoMessage()
+
+	case *StorageAtResponse_Data:
+		return r.GetData().UnpackProtoMessage()
+	}
+	return common.EmptyHash, errors.New("unexpected response type")
+}
+
+// ProofRequest converters
+
+func (r *ProofRequest) PackProtoMessage(
+	address types.Address, keys []common.Hash, blockReference rawapitypes.BlockReference,
+) error {
+	r.Address = new(Address).PackProtoMessage(address)
+	r.Keys = PackHashes(keys)
+	r.BlockReference = &BlockReference{}
+	return r.GetBlockReference().PackProtoMessage(blockReference)
+}
+
+func (r *ProofRequest) UnpackProtoMessage() (types.Address, []common.Hash, rawapitypes.BlockReference, error) {
+	blockReference, err := r.GetBlockReference().UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, nil, rawapitypes.BlockReference{}, err
+	}
+	return r.GetAddress().UnpackProtoMessage(), UnpackHashes(r.GetKeys()), blockReference, nil
+}
+
+// AccountProof converters
+
+func (p *AccountProof) PackProtoMessage(proof *rawapitypes.AccountProof) error {
+	p.ContractSSZ = proof.ContractSSZ
+	p.ProofEncoded = proof.ProofEncoded
+	p.StorageProofs = make([]*StorageProof, len(proof.StorageProofs))
+	for i, storageProof := range proof.StorageProofs {
+		sp := &StorageProof{Key: new(Hash), Value: new(Hash), ProofEncoded: storageProof.ProofEncoded}
+		if err := sp.Key.PackProtoMessage(storageProof.Key); err != nil {
+			return err
+		}
+		if err := sp.Value.PackProtoMessage(storageProof.Value); err != nil {
+			return err
+		}
+		p.StorageProofs[i] = sp
+	}
+	return nil
+}
+
+func (p *AccountProof) UnpackProtoMessage() (*rawapitypes.AccountProof, error) {
+	proof := &rawapitypes.AccountProof{
+		ContractSSZ:   p.GetContractSSZ(),
+		ProofEncoded:  p.GetProofEncoded(),
+		StorageProofs: make([]rawapitypes.StorageProof, len(p.GetStorageProofs())),
+	}
+	for i, sp := range p.GetStorageProofs() {
+		key, err := sp.GetKey().UnpackProtoMessage()
+		if err != nil {
+			return nil, err
+		}
+		value, err := sp.GetValue().UnpackProtoMessage()
+		if err != nil {
+			return nil, err
+		}
+		proof.StorageProofs[i] = rawapitypes.StorageProof{
+			Key:          key,
+			Value:        value,
+			ProofEncoded: sp.GetProofEncoded(),
+		}
+	}
+	return proof, nil
+}
+
+// AccountProofResponse converters
+
+func (r *AccountProofResponse) PackProtoMessage(proof *rawapitypes.AccountProof, err error) error {
+	if err != nil {
+		r.Result = &AccountProofResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := new(AccountProof)
+	if err := data.PackProtoMessage(proof); err != nil {
+		return err
+	}
+	r.Result = &AccountProofResponse_Data{Data: data}
+	return nil
+}
+
+func (r *AccountProofResponse) UnpackProtoMessage() (*rawapitypes.AccountProof, error) {
+	switch r.GetResult().(type) {
+	case *AccountProofResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *AccountProofResponse_Data:
+		return r.GetData().UnpackProtoMessage()
+	}
+	return nil, errors.New("unexpected response type")
+}
+
 func (x *Contract) PackProtoMessage(contract rpctypes.Contract) *Contract {
 	if contract.Seqno != nil {
 		x.Seqno = (*uint64)(contract.Seqno)
@@ -1393,36 +1538,465 @@ func (r *SendTransactionRequest) UnpackProtoMessage() ([]byte, error) {
 	return r.GetTransactionSSZ(), nil
 }
 
-func (txn *RawTxnsResponse) PackProtoMessage(txns []*types.Transaction, err error) error {
+func (r *RawTxns) PackProtoMessage(txns []*types.Transaction) error {
+	var err error
+	r.Data, err = sszx.EncodeContainer[*types.Transaction](txns)
+	return err
+}
+
+func (r *RawTxns) UnpackProtoMessage() ([]*types.Transaction, error) {
+	data := r.GetData()
+	if data == nil {
+		return []*types.Transaction{}, nil
+	}
+	return sszx.DecodeContainer[*types.Transaction](data)
+}
+
+// TxPoolStatusResponse converters
+
+func (r *TxPoolStatusResponse) PackProtoMessage(status rawapitypes.TxPoolStatus, err error) error {
 	if err != nil {
-		txn.Result = &RawTxnsResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		r.Result = &TxPoolStatusResponse_Error{Error: new(Error).PackProtoMessage(err)}
 		return nil
 	}
 
-	var rawTxns RawTxns
-	rawTxns.Data, err = sszx.EncodeContainer[*types.Transaction](txns)
+	r.Result = &TxPoolStatusResponse_Data{Data: &TxPoolStatus{Pending: status.Pending, Queued: status.Queued}}
+	return nil
+}
+
+func (r *TxPoolStatusResponse) UnpackProtoMessage() (rawapitypes.TxPoolStatus, error) {
+	switch r.GetResult().(type) {
+	case *TxPoolStatusResponse_Error:
+		return rawapitypes.TxPoolStatus{}, r.GetError().UnpackProtoMessage()
+
+	case *TxPoolStatusResponse_Data:
+		data := r.GetData()
+		if data == nil {
+			return rawapitypes.TxPoolStatus{}, errors.New("unexpected response")
+		}
+		return rawapitypes.TxPoolStatus{Pending: data.GetPending(), Queued: data.GetQueued()}, nil
+	}
+	return rawapitypes.TxPoolStatus{}, errors.New("unexpected response type")
+}
+
+// SyncProgressResponse converters
+
+func (r *SyncProgressResponse) PackProtoMessage(progress rawapitypes.SyncProgress, err error) error {
 	if err != nil {
-		return err
+		r.Result = &SyncProgressResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
 	}
-	txn.Result = &RawTxnsResponse_Data{Data: &rawTxns}
+
+	r.Result = &SyncProgressResponse_Data{Data: &SyncProgress{
+		Syncing:       progress.Syncing,
+		StartingBlock: uint64(progress.StartingBlock),
+		CurrentBlock:  uint64(progress.CurrentBlock),
+		HighestBlock:  uint64(progress.HighestBlock),
+		Peers:         progress.Peers,
+	}}
 	return nil
 }
 
-func (txn *RawTxnsResponse) UnpackProtoMessage() ([]*types.Transaction, error) {
-	switch txn.GetResult().(type) {
-	case *RawTxnsResponse_Error:
-		return nil, txn.GetError().UnpackProtoMessage()
+func (r *SyncProgressResponse) UnpackProtoMessage() (rawapitypes.SyncProgress, error) {
+	switch r.GetResult().(type) {
+	case *SyncProgressResponse_Error:
+		return rawapitypes.SyncProgress{}, r.GetError().UnpackProtoMessage()
 
-	case *RawTxnsResponse_Data:
-		dataResult := txn.GetData()
-		if dataResult == nil {
-			return nil, errors.New("unexpected response")
+	case *SyncProgressResponse_Data:
+		data := r.GetData()
+		if data == nil {
+			return rawapitypes.SyncProgress{}, errors.New("unexpected response")
 		}
-		data := dataResult.GetData()
+		return rawapitypes.SyncProgress{
+			Syncing:       data.GetSyncing(),
+			StartingBlock: types.BlockNumber(data.GetStartingBlock()),
+			CurrentBlock:  types.BlockNumber(data.GetCurrentBlock()),
+			HighestBlock:  types.BlockNumber(data.GetHighestBlock()),
+			Peers:         data.GetPeers(),
+		}, nil
+	}
+	return rawapitypes.SyncProgress{}, errors.New("unexpected response type")
+}
+
+// TxPoolContentResponse converters
+
+func (r *TxPoolContentResponse) PackProtoMessage(content rawapitypes.TxPoolContent, err error) error {
+	if err != nil {
+		r.Result = &TxPoolContentResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &TxPoolContent{Pending: new(RawTxns), Queued: new(RawTxns)}
+	if err := data.Pending.PackProtoMessage(content.Pending); err != nil {
+		return err
+	}
+	if err := data.Queued.PackProtoMessage(content.Queued); err != nil {
+		return err
+	}
+	data.Discarded = make([]*DiscardedTxn, 0, len(content.Discarded))
+	for hash, reason := range content.Discarded {
+		txn := &DiscardedTxn{Hash: new(Hash), Reason: reason}
+		if err := txn.Hash.PackProtoMessage(hash); err != nil {
+			return err
+		}
+		data.Discarded = append(data.Discarded, txn)
+	}
+	r.Result = &TxPoolContentResponse_Data{Data: data}
+	return nil
+}
+
+func (r *TxPoolContentResponse) UnpackProtoMessage() (rawapitypes.TxPoolContent, error) {
+	switch r.GetResult().(type) {
+	case *TxPoolContentResponse_Error:
+		return rawapitypes.TxPoolContent{}, r.GetError().UnpackProtoMessage()
+
+	case *TxPoolContentResponse_Data:
+		data := r.GetData()
 		if data == nil {
-			return []*types.Transaction{}, nil
+			return rawapitypes.TxPoolContent{}, errors.New("unexpected response")
+		}
+
+		pending, err := data.GetPending().UnpackProtoMessage()
+		if err != nil {
+			return rawapitypes.TxPoolContent{}, err
+		}
+		queued, err := data.GetQueued().UnpackProtoMessage()
+		if err != nil {
+			return rawapitypes.TxPoolContent{}, err
+		}
+		discarded := make(map[common.Hash]string, len(data.GetDiscarded()))
+		for _, txn := range data.GetDiscarded() {
+			hash, err := txn.GetHash().UnpackProtoMessage()
+			if err != nil {
+				return rawapitypes.TxPoolContent{}, err
+			}
+			discarded[hash] = txn.GetReason()
+		}
+		return rawapitypes.TxPoolContent{Pending: pending, Queued: queued, Discarded: discarded}, nil
+	}
+	return rawapitypes.TxPoolContent{}, errors.New("unexpected response type")
+}
+
+// Logs converters
+func (r *LogsRequest) PackProtoMessage(filter rawapitypes.LogsFilter) error {
+	if filter.BlockHash != nil {
+		r.BlockHash = new(Hash)
+		if err := r.BlockHash.PackProtoMessage(*filter.BlockHash); err != nil {
+			return err
+		}
+	}
+	if filter.FromBlock != nil {
+		r.FromBlock = (*uint64)(filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		r.ToBlock = (*uint64)(filter.ToBlock)
+	}
+	r.Addresses = make([]*Address, len(filter.Addresses))
+	for i, addr := range filter.Addresses {
+		r.Addresses[i] = new(Address).PackProtoMessage(addr)
+	}
+	r.Topics = make([]*TopicAlternatives, len(filter.Topics))
+	for i, topics := range filter.Topics {
+		r.Topics[i] = &TopicAlternatives{Topics: PackHashes(topics)}
+	}
+	return nil
+}
+
+func (r *LogsRequest) UnpackProtoMessage() (rawapitypes.LogsFilter, error) {
+	var filter rawapitypes.LogsFilter
+	if r.BlockHash != nil {
+		hash, err := r.GetBlockHash().UnpackProtoMessage()
+		if err != nil {
+			return filter, err
+		}
+		filter.BlockHash = &hash
+	}
+	if r.FromBlock != nil {
+		filter.FromBlock = (*types.BlockNumber)(r.FromBlock)
+	}
+	if r.ToBlock != nil {
+		filter.ToBlock = (*types.BlockNumber)(r.ToBlock)
+	}
+	if len(r.GetAddresses()) > 0 {
+		filter.Addresses = make([]types.Address, len(r.GetAddresses()))
+		for i, addr := range r.GetAddresses() {
+			filter.Addresses[i] = addr.UnpackProtoMessage()
+		}
+	}
+	if len(r.GetTopics()) > 0 {
+		filter.Topics = make([][]common.Hash, len(r.GetTopics()))
+		for i, topics := range r.GetTopics() {
+			if len(topics.GetTopics()) > 0 {
+				filter.Topics[i] = UnpackHashes(topics.GetTopics())
+			}
+		}
+	}
+	return filter, nil
+}
+
+func (l *LogInfo) PackProtoMessage(info *rawapitypes.LogInfo) *LogInfo {
+	l.Log = new(Log)
+	l.Log.PackProtoMessage(info.Log)
+	l.BlockId = uint64(info.BlockId)
+	l.BlockHash = new(Hash)
+	check.PanicIfErr(l.BlockHash.PackProtoMessage(info.BlockHash))
+	l.TxnHash = new(Hash)
+	check.PanicIfErr(l.TxnHash.PackProtoMessage(info.TxnHash))
+	l.TxnIndex = uint64(info.TxnIndex)
+	l.LogIndex = info.LogIndex
+	return l
+}
+
+func (l *LogInfo) UnpackProtoMessage() (*rawapitypes.LogInfo, error) {
+	blockHash, err := l.GetBlockHash().UnpackProtoMessage()
+	if err != nil {
+		return nil, err
+	}
+	txnHash, err := l.GetTxnHash().UnpackProtoMessage()
+	if err != nil {
+		return nil, err
+	}
+	return &rawapitypes.LogInfo{
+		Log:       l.GetLog().UnpackProtoMessage(),
+		BlockId:   types.BlockNumber(l.GetBlockId()),
+		BlockHash: blockHash,
+		TxnHash:   txnHash,
+		TxnIndex:  types.TransactionIndex(l.GetTxnIndex()),
+		LogIndex:  l.GetLogIndex(),
+	}, nil
+}
+
+func (r *LogsResponse) PackProtoMessage(logs []*rawapitypes.LogInfo, err error) error {
+	if err != nil {
+		r.Result = &LogsResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &LogInfos{Logs: make([]*LogInfo, len(logs))}
+	for i, info := range logs {
+		data.Logs[i] = new(LogInfo).PackProtoMessage(info)
+	}
+	r.Result = &LogsResponse_Data{Data: data}
+	return nil
+}
+
+func (r *LogsResponse) UnpackProtoMessage() ([]*rawapitypes.LogInfo, error) {
+	switch r.GetResult().(type) {
+	case *LogsResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *LogsResponse_Data:
+		data := r.GetData()
+		logs := make([]*rawapitypes.LogInfo, len(data.GetLogs()))
+		for i, info := range data.GetLogs() {
+			var err error
+			if logs[i], err = info.UnpackProtoMessage(); err != nil {
+				return nil, err
+			}
+		}
+		return logs, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// TraceConfig converters
+
+func (c *TraceConfig) PackProtoMessage(config *tracers.TraceConfig) *TraceConfig {
+	if config == nil {
+		return nil
+	}
+	c.Tracer = config.Tracer
+	c.TracerConfig = config.TracerConfig
+	c.EnableMemory = config.EnableMemory
+	c.DisableStack = config.DisableStack
+	c.DisableStorage = config.DisableStorage
+	c.EnableReturnData = config.EnableReturnData
+	c.Limit = uint64(config.Limit)
+	return c
+}
+
+func (c *TraceConfig) UnpackProtoMessage() *tracers.TraceConfig {
+	if c == nil {
+		return nil
+	}
+	return &tracers.TraceConfig{
+		Tracer:           c.GetTracer(),
+		TracerConfig:     c.GetTracerConfig(),
+		EnableMemory:     c.GetEnableMemory(),
+		DisableStack:     c.GetDisableStack(),
+		DisableStorage:   c.GetDisableStorage(),
+		EnableReturnData: c.GetEnableReturnData(),
+		Limit:            int(c.GetLimit()),
+	}
+}
+
+// TraceTransactionRequest converters
+
+func (r *TraceTransactionRequest) PackProtoMessage(hash common.Hash, config *tracers.TraceConfig) error {
+	r.Hash = new(Hash)
+	if err := r.GetHash().PackProtoMessage(hash); err != nil {
+		return err
+	}
+	r.Config = new(TraceConfig).PackProtoMessage(config)
+	return nil
+}
+
+func (r *TraceTransactionRequest) UnpackProtoMessage() (common.Hash, *tracers.TraceConfig, error) {
+	hash, err := r.GetHash().UnpackProtoMessage()
+	if err != nil {
+		return common.EmptyHash, nil, err
+	}
+	return hash, r.GetConfig().UnpackProtoMessage(), nil
+}
+
+// TraceCallRequest converters
+
+func (r *TraceCallRequest) PackProtoMessage(
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	config *tracers.TraceConfig,
+) error {
+	r.Call = new(CallRequest)
+	if err := r.GetCall().PackProtoMessage(args, mainBlockReferenceOrHashWithChildren, overrides); err != nil {
+		return err
+	}
+	r.Config = new(TraceConfig).PackProtoMessage(config)
+	return nil
+}
+
+func (r *TraceCallRequest) UnpackProtoMessage() (
+	rpctypes.CallArgs,
+	rawapitypes.BlockReferenceOrHashWithChildren,
+	*rpctypes.StateOverrides,
+	*tracers.TraceConfig,
+	error,
+) {
+	args, br, overrides, err := r.GetCall().UnpackProtoMessage()
+	if err != nil {
+		return rpctypes.CallArgs{}, rawapitypes.BlockReferenceOrHashWithChildren{}, nil, nil, err
+	}
+	return args, br, overrides, r.GetConfig().UnpackProtoMessage(), nil
+}
+
+// TraceResponse converters
+
+func (r *TraceResponse) PackProtoMessage(trace json.RawMessage, err error) error {
+	if err != nil {
+		r.Result = &TraceResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+	r.Result = &TraceResponse_Data{Data: trace}
+	return nil
+}
+
+func (r *TraceResponse) UnpackProtoMessage() (json.RawMessage, error) {
+	switch r.GetResult().(type) {
+	case *TraceResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *TraceResponse_Data:
+		return r.GetData(), nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// DoubleSignEvidence converters
+
+func (r *DoubleSignEvidenceRequest) PackProtoMessage(fromHeight uint64) error {
+	r.FromHeight = fromHeight
+	return nil
+}
+
+func (r *DoubleSignEvidenceRequest) UnpackProtoMessage() (uint64, error) {
+	return r.GetFromHeight(), nil
+}
+
+func (r *DoubleSignEvidenceResponse) PackProtoMessage(evidence []*types.DoubleSignEvidence, err error) error {
+	if err != nil {
+		r.Result = &DoubleSignEvidenceResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &DoubleSignEvidenceList{Evidence: make([][]byte, len(evidence))}
+	for i, e := range evidence {
+		encoded, err := e.MarshalSSZ()
+		if err != nil {
+			return err
+		}
+		data.Evidence[i] = encoded
+	}
+	r.Result = &DoubleSignEvidenceResponse_Data{Data: data}
+	return nil
+}
+
+func (r *DoubleSignEvidenceResponse) UnpackProtoMessage() ([]*types.DoubleSignEvidence, error) {
+	switch r.GetResult().(type) {
+	case *DoubleSignEvidenceResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *DoubleSignEvidenceResponse_Data:
+		data := r.GetData().GetEvidence()
+		evidence := make([]*types.DoubleSignEvidence, len(data))
+		for i, encoded := range data {
+			evidence[i] = &types.DoubleSignEvidence{}
+			if err := evidence[i].UnmarshalSSZ(encoded); err != nil {
+				return nil, err
+			}
+		}
+		return evidence, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// StateDiff converters
+
+func (r *StateDiffRequest) PackProtoMessage(from, to common.Hash) error {
+	r.From = new(Hash)
+	if err := r.GetFrom().PackProtoMessage(from); err != nil {
+		return err
+	}
+	r.To = new(Hash)
+	return r.GetTo().PackProtoMessage(to)
+}
+
+func (r *StateDiffRequest) UnpackProtoMessage() (common.Hash, common.Hash, error) {
+	from, err := r.GetFrom().UnpackProtoMessage()
+	if err != nil {
+		return common.EmptyHash, common.EmptyHash, err
+	}
+	to, err := r.GetTo().UnpackProtoMessage()
+	if err != nil {
+		return common.EmptyHash, common.EmptyHash, err
+	}
+	return from, to, nil
+}
+
+func (r *StateDiffResponse) PackProtoMessage(diff []*execution.ContractDiff, err error) error {
+	if err != nil {
+		r.Result = &StateDiffResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+	r.Result = &StateDiffResponse_Data{Data: data}
+	return nil
+}
+
+func (r *StateDiffResponse) UnpackProtoMessage() ([]*execution.ContractDiff, error) {
+	switch r.GetResult().(type) {
+	case *StateDiffResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *StateDiffResponse_Data:
+		var diff []*execution.ContractDiff
+		if err := json.Unmarshal(r.GetData(), &diff); err != nil {
+			return nil, err
 		}
-		return sszx.DecodeContainer[*types.Transaction](data)
+		return diff, nil
 	}
 	return nil, errors.New("unexpected response type")
 }
@@ -156,3 +156,58 @@ type SmartContract struct {
 	Tokens       map[types.TokenId]types.Value
 	AsyncContext map[types.TransactionIndex]types.AsyncContext
 }
+
+// StorageProof proves the value of a storage slot against the storage root of the contract.
+// ProofEncoded is empty if the contract doesn't exist.
+type StorageProof struct {
+	Key          common.Hash
+	Value        common.Hash
+	ProofEncoded []byte
+}
+
+// AccountProof proves the state of the contract against the contract trie root of the block.
+// ContractSSZ is empty if the contract doesn't exist, in which case ProofEncoded proves its absence.
+type AccountProof struct {
+	ContractSSZ   []byte
+	ProofEncoded  []byte
+	StorageProofs []StorageProof
+}
+
+// LogsFilter selects logs of a single shard. Either BlockHash or the [FromBlock, ToBlock] range is used.
+// Nil FromBlock and ToBlock mean the latest block.
+type LogsFilter struct {
+	BlockHash *common.Hash
+	FromBlock *types.BlockNumber
+	ToBlock   *types.BlockNumber
+	Addresses []types.Address
+	Topics    [][]common.Hash
+}
+
+type LogInfo struct {
+	Log       *types.Log
+	BlockId   types.BlockNumber
+	BlockHash common.Hash
+	TxnHash   common.Hash
+	TxnIndex  types.TransactionIndex
+	LogIndex  uint64
+}
+
+type TxPoolStatus struct {
+	Pending uint64
+	Queued  uint64
+}
+
+type SyncProgress struct {
+	Syncing       bool
+	StartingBlock types.BlockNumber
+	CurrentBlock  types.BlockNumber
+	HighestBlock  types.BlockNumber
+	Peers         []string
+}
+
+type TxPoolContent struct {
+	Pending []*types.Transaction
+	Queued  []*types.Transaction
+	// Discarded maps the recently discarded transactions to the discard reasons.
+	Discarded map[common.Hash]string
+}
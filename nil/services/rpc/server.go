@@ -24,6 +24,7 @@ func StartRpcServer(
 	// register apis and create handler stack
 	srv := transport.NewServer(
 		cfg.TraceRequests, cfg.DebugSingleRequest, logger, cfg.RPCSlowLogThreshold, cfg.KeepHeaders)
+	srv.SetLimits(cfg.Limits)
 
 	defer srv.Stop()
 
@@ -50,6 +51,10 @@ func StartRpcServer(
 			nil,
 			cfg.HttpCompression)
 	}
+	if cfg.WSEnabled {
+		// WebSocket upgrade requests bypass the HTTP stack, since compression would break the hijacked connection.
+		httpHandler = transport.NewWebsocketUpgradeHandler(httpHandler, srv.WebsocketHandler(cfg.HttpCORSDomain))
+	}
 
 	listener, httpAddr, err := http.StartHTTPEndpoint(httpEndpoint, &http.HttpEndpointConfig{
 		Timeouts: cfg.HTTPTimeouts,
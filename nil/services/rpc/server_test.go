@@ -11,9 +11,18 @@ import (
 	"testing"
 	"time"
 
+	rpc_client "github.com/NilFoundation/nil/nil/client/rpc"
+	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
 	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/require"
 )
 
@@ -116,3 +125,224 @@ func TestContextCancellation(t *testing.T) {
 
 	require.False(t, api.contextCancelled)
 }
+
+type testSubscriptionApi struct {
+	unsubscribed chan transport.SubscriptionID
+}
+
+// Subscribe sends the hashes 1, 2, ... to the subscriber until it unsubscribes.
+func (s *testSubscriptionApi) Subscribe(
+	ctx context.Context, _ string, _ types.ShardId, _ *filters.FilterQuery,
+) (transport.SubscriptionID, error) {
+	notifier, ok := transport.NotifierFromContext(ctx)
+	if !ok {
+		return "", transport.ErrNotificationsUnsupported
+	}
+	sub, err := notifier.CreateSubscription()
+	if err != nil {
+		return "", err
+	}
+	go func() {
+		for i := 1; ; i++ {
+			select {
+			case <-sub.Err():
+				s.unsubscribed <- sub.ID
+				return
+			case <-time.After(10 * time.Millisecond):
+				_ = notifier.Notify(sub.ID, common.BytesToHash([]byte{byte(i)}))
+			}
+		}
+	}()
+	return sub.ID, nil
+}
+
+func (s *testSubscriptionApi) Unsubscribe(ctx context.Context, id transport.SubscriptionID) (bool, error) {
+	notifier, ok := transport.NotifierFromContext(ctx)
+	if !ok {
+		return false, transport.ErrNotificationsUnsupported
+	}
+	return notifier.Unsubscribe(id) == nil, nil
+}
+
+func TestWebsocketSubscription(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	api := &testSubscriptionApi{unsubscribed: make(chan transport.SubscriptionID, 1)}
+	socketPath := GetSockPath(t)
+
+	started := make(chan struct{})
+	go func() {
+		_ = StartRpcServer(
+			ctx,
+			&httpcfg.HttpCfg{
+				HttpURL:      socketPath,
+				HTTPTimeouts: httpcfg.DefaultHTTPTimeouts,
+				WSEnabled:    true,
+			},
+			[]transport.API{{
+				Namespace: "eth",
+				Version:   "1.0",
+				Service:   api,
+				Public:    true,
+			}},
+			logging.NewLogger("Test server"),
+			started,
+		)
+	}()
+
+	select {
+	case <-time.After(serverStartTimeout):
+		t.Fatalf("rpc server did not start in time")
+	case <-started:
+	}
+
+	t.Run("HttpIsNotSupported", func(t *testing.T) {
+		c := rpc_client.NewRawClient(socketPath, logging.NewLogger("Test client"))
+		_, err := c.RawCall(ctx, rpc_client.Eth_subscribe, "newPendingTransactions", 0)
+		require.ErrorContains(t, err, transport.ErrNotificationsUnsupported.Error())
+	})
+
+	t.Run("Websocket", func(t *testing.T) {
+		c, err := rpc_client.NewWsClient(ctx, socketPath, logging.NewLogger("Test client"))
+		require.NoError(t, err)
+		defer c.Close()
+
+		sub, err := c.SubscribeNewPendingTransactions(ctx, 0)
+		require.NoError(t, err)
+		require.NotEmpty(t, sub.ID())
+
+		for i := 1; i <= 3; i++ {
+			select {
+			case hash := <-sub.Channel():
+				require.Equal(t, common.BytesToHash([]byte{byte(i)}), hash)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("notification %d was not received", i)
+			}
+		}
+
+		require.NoError(t, sub.Unsubscribe(ctx))
+		select {
+		case id := <-api.unsubscribed:
+			require.EqualValues(t, sub.ID(), id)
+		case <-time.After(5 * time.Second):
+			t.Fatal("subscription was not canceled on the server")
+		}
+		require.NoError(t, <-sub.Err())
+	})
+}
+
+// writeBlocksWithLogs appends the blocks with one log each to the main shard chain and moves its head
+// to the last of them in a single transaction.
+func writeBlocksWithLogs(t *testing.T, database db.DB, prevBlock common.Hash, ids ...types.BlockNumber) common.Hash {
+	t.Helper()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	for _, id := range ids {
+		receipts := execution.NewDbReceiptTrie(tx, types.MainShardId)
+		log := &types.Log{Address: types.HexToAddress("0x1111111111"), Data: []byte{byte(id)}}
+		require.NoError(t, receipts.Update(0, &types.Receipt{Logs: []*types.Log{log}}))
+
+		block := &types.Block{BlockData: types.BlockData{
+			Id:           id,
+			PrevBlock:    prevBlock,
+			ReceiptsRoot: receipts.RootHash(),
+		}}
+		prevBlock = block.Hash(types.MainShardId)
+		require.NoError(t, db.WriteBlock(tx, types.MainShardId, prevBlock, block))
+	}
+	require.NoError(t, db.WriteLastBlockHash(tx, types.MainShardId, prevBlock))
+	require.NoError(t, tx.Commit())
+	return prevBlock
+}
+
+func TestWebsocketEthSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	// The history must not be replayed to the new subscribers.
+	head := writeBlocksWithLogs(t, database, common.EmptyHash, 0, 1)
+
+	nodeApi := rawapi.NodeApiBuilder(database, nil).WithLocalShardApiRo(types.MainShardId).BuildAndReset()
+	api := jsonrpc.NewEthAPI(ctx, nodeApi, database, nil, nil, false, false)
+	socketPath := GetSockPath(t)
+
+	started := make(chan struct{})
+	go func() {
+		_ = StartRpcServer(
+			ctx,
+			&httpcfg.HttpCfg{
+				HttpURL:      socketPath,
+				HTTPTimeouts: httpcfg.DefaultHTTPTimeouts,
+				WSEnabled:    true,
+			},
+			[]transport.API{{
+				Namespace: "eth",
+				Version:   "1.0",
+				Service:   api,
+				Public:    true,
+			}},
+			logging.NewLogger("Test server"),
+			started,
+		)
+	}()
+
+	select {
+	case <-time.After(serverStartTimeout):
+		t.Fatalf("rpc server did not start in time")
+	case <-started:
+	}
+
+	c, err := rpc_client.NewWsClient(ctx, socketPath, logging.NewLogger("Test client"))
+	require.NoError(t, err)
+	defer c.Close()
+
+	heads, err := c.SubscribeNewHeads(ctx, types.MainShardId)
+	require.NoError(t, err)
+	logs, err := c.SubscribeLogs(ctx, types.MainShardId, filters.FilterQuery{})
+	require.NoError(t, err)
+
+	_, err = c.SubscribeLogs(ctx, types.MainShardId, filters.FilterQuery{FromBlock: uint256.NewInt(0)})
+	require.ErrorContains(t, err, "receives new logs only")
+
+	select {
+	case head := <-heads.Channel():
+		t.Fatalf("unexpected head %d before new blocks", head.Number)
+	case log := <-logs.Channel():
+		t.Fatalf("unexpected log of block %d before new blocks", log.BlockNumber)
+	case <-time.After(time.Second):
+	}
+
+	// Both blocks are committed between two polls and must be delivered in ascending order.
+	writeBlocksWithLogs(t, database, head, 2, 3)
+
+	for _, id := range []types.BlockNumber{2, 3} {
+		select {
+		case head := <-heads.Channel():
+			require.Equal(t, id, head.Number)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("head %d was not received", id)
+		}
+		select {
+		case log := <-logs.Channel():
+			require.Equal(t, id, log.BlockNumber)
+			require.Equal(t, []byte{byte(id)}, []byte(log.Data))
+		case <-time.After(5 * time.Second):
+			t.Fatalf("log of block %d was not received", id)
+		}
+	}
+
+	require.NoError(t, heads.Unsubscribe(ctx))
+	require.NoError(t, logs.Unsubscribe(ctx))
+}
@@ -1,6 +1,9 @@
 package transport
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 var (
 	_ Error = new(methodNotFoundError)
@@ -9,6 +12,11 @@ var (
 	_ Error = new(invalidMessageError)
 	_ Error = new(InvalidParamsError)
 	_ Error = new(CustomError)
+	_ Error = new(rateLimitError)
+	_ Error = new(responseTooLargeError)
+	_ Error = new(timeoutError)
+
+	_ DataError = new(rateLimitError)
 )
 
 const defaultErrorCode = -32000
@@ -49,6 +57,53 @@ func (e *InvalidParamsError) ErrorCode() int { return -32602 }
 
 func (e *InvalidParamsError) Error() string { return e.Message }
 
+// the client spent its request budget
+type rateLimitError struct {
+	cost       int
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) ErrorCode() int { return -32005 }
+
+func (e *rateLimitError) Error() string {
+	if e.retryAfter == 0 {
+		return fmt.Sprintf("request cost %d exceeds the rate limit burst", e.cost)
+	}
+	return fmt.Sprintf("rate limit exceeded, retry in %s", e.retryAfter.Round(time.Millisecond))
+}
+
+// ErrorData returns the delay after which the request fits into the budget.
+// There is no data if the request cost exceeds the burst, as retrying doesn't help then.
+func (e *rateLimitError) ErrorData() any {
+	if e.retryAfter == 0 {
+		return nil
+	}
+	return map[string]int64{"retryAfterMs": e.retryAfter.Milliseconds()}
+}
+
+// the response is larger than the configured limit
+type responseTooLargeError struct {
+	size  int
+	limit int
+}
+
+func (e *responseTooLargeError) ErrorCode() int { return -32005 }
+
+func (e *responseTooLargeError) Error() string {
+	return fmt.Sprintf("response size %d exceeds the limit of %d bytes", e.size, e.limit)
+}
+
+// the method didn't finish within the configured timeout
+type timeoutError struct {
+	timeout time.Duration
+}
+
+func (e *timeoutError) ErrorCode() int { return -32002 }
+
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("request timed out after %s", e.timeout)
+}
+
 type CustomError struct {
 	Code    int
 	Message string
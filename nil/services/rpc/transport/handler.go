@@ -33,8 +33,12 @@ type handler struct {
 	cancelRoot func()          // cancel function for rootCtx
 	conn       JsonWriter      // where responses will be sent
 	logger     logging.Logger
+	limiter    *requestLimiter
 	mh         *metricsHandler
 
+	// subs is set for connections that support notifications (e.g., WebSocket)
+	subs *subscriptionRegistry
+
 	maxBatchConcurrency uint
 	traceRequests       bool
 
@@ -63,7 +67,7 @@ func HandleError(err error, stream *jsoniter.Stream) {
 	stream.WriteObjectField("message")
 	stream.WriteString(err.Error())
 
-	if de := DataError(nil); errors.As(err, &de) {
+	if de := DataError(nil); errors.As(err, &de) && de.ErrorData() != nil {
 		stream.WriteMore()
 		stream.WriteObjectField("data")
 		data, derr := json.Marshal(de.ErrorData())
@@ -86,6 +90,7 @@ func newHandler(
 	traceRequests bool,
 	logger logging.Logger,
 	rpcSlowLogThreshold time.Duration,
+	limiter *requestLimiter,
 	mh *metricsHandler,
 ) *handler {
 	rootCtx, cancelRoot := context.WithCancel(connCtx)
@@ -96,6 +101,7 @@ func newHandler(
 		rootCtx:    rootCtx,
 		cancelRoot: cancelRoot,
 		logger:     logger,
+		limiter:    limiter,
 		mh:         mh,
 
 		maxBatchConcurrency: maxBatchConcurrency,
@@ -107,6 +113,14 @@ func newHandler(
 	}
 }
 
+// close cancels all requests in progress and drops the subscriptions of the connection.
+func (h *handler) close() {
+	h.cancelRoot()
+	if h.subs != nil {
+		h.subs.closeAll()
+	}
+}
+
 // some requests have heavy params which make logs harder to read
 func (h *handler) shouldLogRequestParams(method string, lvl zerolog.Level) bool {
 	if lvl == zerolog.TraceLevel {
@@ -190,6 +204,12 @@ func (h *handler) handleBatch(msgs []*Message) {
 	defer close(boundedConcurrency)
 	wg := sync.WaitGroup{}
 	wg.Add(len(msgs))
+	notifiers := make([]*Notifier, len(msgs))
+	defer func() {
+		for _, n := range notifiers {
+			n.activate()
+		}
+	}()
 	for i := range msgs {
 		boundedConcurrency <- struct{}{}
 		go func(i int) {
@@ -200,13 +220,22 @@ func (h *handler) handleBatch(msgs []*Message) {
 
 			buf := bytes.NewBuffer(nil)
 			stream := jsoniter.NewStream(jsoniter.ConfigDefault, buf, 4096)
-			if res := h.handleCallMsg(h.rootCtx, msgs[i], stream); res != nil {
+			var ctx context.Context
+			ctx, notifiers[i] = h.callContext(msgs[i])
+			if res := h.handleCallMsg(ctx, msgs[i], stream); res != nil {
 				answers[i] = res
 			}
 			_ = stream.Flush()
 			if buf.Len() > 0 && answers[i] == nil {
 				answers[i] = json.RawMessage(buf.Bytes())
 			}
+			size := buf.Len()
+			if res, ok := answers[i].(*Message); ok {
+				size = len(res.Result)
+			}
+			if err := h.checkResponseSize(msgs[i], size); err != nil {
+				answers[i] = msgs[i].errorResponse(err)
+			}
 		}(i)
 	}
 	wg.Wait()
@@ -217,13 +246,38 @@ func (h *handler) handleBatch(msgs []*Message) {
 
 // handleMsg handles a single message.
 func (h *handler) handleMsg(msg *Message) {
+	ctx, notifier := h.callContext(msg)
+	defer notifier.activate()
+
 	stream := jsoniter.NewStream(jsoniter.ConfigDefault, nil, 4096)
-	answer := h.handleCallMsg(h.rootCtx, msg, stream)
+	answer := h.handleCallMsg(ctx, msg, stream)
 	if answer != nil {
 		buffer, _ := json.Marshal(answer) //nolint: errchkjson
 		_, _ = stream.Write(buffer)
 	}
-	_ = h.conn.WriteJSON(h.rootCtx, json.RawMessage(stream.Buffer()))
+	response := stream.Buffer()
+	if err := h.checkResponseSize(msg, len(response)); err != nil {
+		response, _ = json.Marshal(msg.errorResponse(err)) //nolint: errchkjson
+	}
+	_ = h.conn.WriteJSON(h.rootCtx, json.RawMessage(response))
+}
+
+// callContext returns the context of the call. For connections that support notifications it carries
+// a notifier, which is to be activated after the response is written, nil notifier is returned otherwise.
+func (h *handler) callContext(msg *Message) (context.Context, *Notifier) {
+	if h.subs == nil {
+		return h.rootCtx, nil
+	}
+	return contextWithNotifier(h.rootCtx, h.subs, msg.Method)
+}
+
+// checkResponseSize returns an error if the response to the call exceeds the size limit.
+func (h *handler) checkResponseSize(msg *Message, size int) error {
+	err := h.limiter.checkResponseSize(size)
+	if err != nil {
+		h.mh.responseTooLarge.Add(h.rootCtx, 1, telattr.With(telattr.RpcMethod(msg.Method)))
+	}
+	return err
 }
 
 // handleCallMsg executes a call message and returns the answer.
@@ -280,6 +334,18 @@ func (h *handler) handleCall(ctx context.Context, msg *Message, stream *jsoniter
 		return msg.errorResponse(&InvalidParamsError{err.Error()})
 	}
 	methodOAttr := telattr.RpcMethod(msg.Method)
+	if err := h.limiter.allow(ctx, msg.Method); err != nil {
+		h.mh.rateLimited.Add(ctx, 1, telattr.With(methodOAttr))
+		return msg.errorResponse(err)
+	}
+	h.mh.cost.Add(ctx, int64(h.limiter.cost(msg.Method)), telattr.With(methodOAttr))
+
+	if timeout := h.limiter.timeout(msg.Method); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	measurer, err := telemetry.NewMeasurer(h.mh.meter, "rpc", methodOAttr)
 	if err == nil {
 		defer measurer.Measure(ctx)
@@ -302,7 +368,7 @@ func (h *handler) runMethod(
 	if !callb.streamable {
 		result, err := callb.call(ctx, msg.Method, args, stream)
 		if err != nil {
-			return msg.errorResponse(err)
+			return msg.errorResponse(h.checkTimeout(ctx, msg, err))
 		}
 		return msg.response(result)
 	}
@@ -321,13 +387,26 @@ func (h *handler) runMethod(
 	if err != nil {
 		writeNilIfNotPresent(stream)
 		stream.WriteMore()
-		HandleError(err, stream)
+		HandleError(h.checkTimeout(ctx, msg, err), stream)
 	}
 	stream.WriteObjectEnd()
 	_ = stream.Flush()
 	return nil
 }
 
+// checkTimeout replaces the error of the call with the timeout error if the call exceeded its execution timeout.
+func (h *handler) checkTimeout(ctx context.Context, msg *Message, err error) error {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	timeout := h.limiter.timeout(msg.Method)
+	if timeout == 0 {
+		return err
+	}
+	h.mh.timedOut.Add(h.rootCtx, 1, telattr.With(telattr.RpcMethod(msg.Method)))
+	return &timeoutError{timeout: timeout}
+}
+
 var nullAsBytes = []byte{110, 117, 108, 108}
 
 // there are many avenues that could lead to an error being handled in runMethod, so we need to check
@@ -0,0 +1,126 @@
+package transport
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport/rpccfg"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/time/rate"
+)
+
+// maxRateLimitedClients bounds the memory used for the client buckets, the least recently seen clients are forgotten.
+const maxRateLimitedClients = 1 << 16
+
+// ClientKeyContextKey holds the key identifying the client of the request, see requestLimiter.clientKey.
+var ClientKeyContextKey ContextKey = "clientKey"
+
+// requestLimiter enforces the per-client rate limits as well as the size and execution time limits of the requests.
+type requestLimiter struct {
+	cfg     httpcfg.LimitsCfg
+	costs   map[string]int
+	keys    map[string]struct{}
+	burst   int
+	clients *lru.Cache[string, *rate.Limiter]
+}
+
+func newRequestLimiter(cfg httpcfg.LimitsCfg) *requestLimiter {
+	burst := cfg.RateBurst
+	if burst <= 0 {
+		burst = max(int(math.Ceil(cfg.RateLimit)), cfg.MaxCost())
+	}
+
+	keys := make(map[string]struct{}, len(cfg.ClientKeys))
+	for _, key := range cfg.ClientKeys {
+		keys[key] = struct{}{}
+	}
+
+	clients, err := lru.New[string, *rate.Limiter](maxRateLimitedClients)
+	check.PanicIfErr(err)
+
+	return &requestLimiter{
+		cfg:     cfg,
+		costs:   cfg.Costs(),
+		keys:    keys,
+		burst:   burst,
+		clients: clients,
+	}
+}
+
+func (l *requestLimiter) cost(method string) int {
+	if cost, ok := l.costs[method]; ok {
+		return cost
+	}
+	return rpccfg.DefaultMethodCost
+}
+
+// allow charges the client of the request for the method call.
+// It returns an error without charging anything if the client doesn't have enough budget.
+func (l *requestLimiter) allow(ctx context.Context, method string) error {
+	if l.cfg.RateLimit <= 0 {
+		return nil
+	}
+
+	client, _ := ctx.Value(ClientKeyContextKey).(string)
+	limiter, ok := l.clients.Get(client)
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(l.cfg.RateLimit), l.burst)
+		if prev, found, _ := l.clients.PeekOrAdd(client, limiter); found {
+			limiter = prev
+		}
+	}
+
+	cost := l.cost(method)
+	now := time.Now()
+	reservation := limiter.ReserveN(now, cost)
+	if !reservation.OK() {
+		return &rateLimitError{cost: cost}
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return &rateLimitError{cost: cost, retryAfter: delay}
+	}
+	return nil
+}
+
+func (l *requestLimiter) timeout(method string) time.Duration {
+	return l.cfg.MethodTimeouts[method]
+}
+
+// checkResponseSize returns an error if the encoded response exceeds the size limit.
+func (l *requestLimiter) checkResponseSize(size int) error {
+	if l.cfg.MaxResponseSize > 0 && size > l.cfg.MaxResponseSize {
+		return &responseTooLargeError{size: size, limit: l.cfg.MaxResponseSize}
+	}
+	return nil
+}
+
+// clientKey identifies the client of the request for the rate limiting:
+// by the API key if it's one of the configured keys, by the IP set by the proxy or the remote IP otherwise.
+// Unknown keys don't get their own budget, so a client can't bypass the limit by varying the key.
+func (l *requestLimiter) clientKey(r *http.Request) string {
+	if l.cfg.ClientKeyHeader != "" {
+		key := r.Header.Get(l.cfg.ClientKeyHeader)
+		if _, ok := l.keys[key]; ok && key != "" {
+			return "key:" + key
+		}
+	}
+
+	if l.cfg.ClientIpHeader != "" {
+		if ip := strings.TrimSpace(r.Header.Get(l.cfg.ClientIpHeader)); ip != "" {
+			return "ip:" + ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return "ip:" + r.RemoteAddr
+	}
+	return "ip:" + host
+}
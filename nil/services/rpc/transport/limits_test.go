@@ -0,0 +1,192 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport/rpccfg"
+	"github.com/stretchr/testify/require"
+)
+
+type limitsTestService struct{}
+
+func (s *limitsTestService) Echo(_ context.Context, v string) string {
+	return v
+}
+
+func (s *limitsTestService) Heavy(_ context.Context) string {
+	return "heavy"
+}
+
+func (s *limitsTestService) Sleep(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+type limitsTestResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code int            `json:"code"`
+		Data map[string]any `json:"data"`
+	} `json:"error"`
+}
+
+func newLimitsTestServer(t *testing.T, cfg httpcfg.LimitsCfg) *httptest.Server {
+	t.Helper()
+
+	srv := NewServer(false /* traceRequests */, false /* traceSingleRequest */, logging.NewLogger("test"), 0, nil)
+	srv.SetLimits(cfg)
+	require.NoError(t, srv.RegisterName("test", &limitsTestService{}))
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.ServeSingleRequest(r.Context(), r, w)
+	}))
+	t.Cleanup(func() {
+		ts.Close()
+		srv.Stop()
+	})
+	return ts
+}
+
+func callLimitsTestServer(t *testing.T, ts *httptest.Server, apiKey, body string) []limitsTestResponse {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("X-Api-Key", apiKey)
+	}
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var res []limitsTestResponse
+	if strings.HasPrefix(body, "[") {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+	} else {
+		res = make([]limitsTestResponse, 1)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&res[0]))
+	}
+	return res
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	ts := newLimitsTestServer(t, httpcfg.LimitsCfg{
+		RateLimit:       0.001,
+		RateBurst:       5,
+		ClientKeyHeader: "X-Api-Key",
+		ClientKeys:      []string{"a", "b"},
+		MethodCosts:     map[string]int{"test_heavy": 3},
+	})
+
+	const echo = `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["hi"]}`
+	const heavy = `{"jsonrpc":"2.0","id":1,"method":"test_heavy","params":[]}`
+
+	// The heavy call costs 3 units of the burst of 5.
+	res := callLimitsTestServer(t, ts, "a", heavy)
+	require.Nil(t, res[0].Error)
+
+	// The next heavy call doesn't fit into the remaining budget and isn't charged.
+	res = callLimitsTestServer(t, ts, "a", heavy)
+	require.NotNil(t, res[0].Error)
+	require.Equal(t, -32005, res[0].Error.Code)
+	require.Contains(t, res[0].Error.Data, "retryAfterMs")
+
+	// The cheap calls use the rest of the budget, the batch calls are executed concurrently.
+	res = callLimitsTestServer(t, ts, "a", "["+echo+","+echo+","+echo+"]")
+	require.Len(t, res, 3)
+	limited := 0
+	for _, r := range res {
+		if r.Error != nil {
+			require.Equal(t, -32005, r.Error.Code)
+			limited++
+		}
+	}
+	require.Equal(t, 1, limited)
+
+	// Other clients have their own budget.
+	res = callLimitsTestServer(t, ts, "b", heavy)
+	require.Nil(t, res[0].Error)
+	res = callLimitsTestServer(t, ts, "", heavy)
+	require.Nil(t, res[0].Error)
+
+	// Unknown keys share the budget of the client IP.
+	res = callLimitsTestServer(t, ts, "c", heavy)
+	require.NotNil(t, res[0].Error)
+	require.Equal(t, -32005, res[0].Error.Code)
+}
+
+func TestRateLimitCostAboveBurst(t *testing.T) {
+	t.Parallel()
+
+	ts := newLimitsTestServer(t, httpcfg.LimitsCfg{
+		RateLimit:   1,
+		RateBurst:   2,
+		MethodCosts: map[string]int{"test_heavy": 3},
+	})
+
+	// The call never fits into the burst, so there is no delay to retry after.
+	res := callLimitsTestServer(t, ts, "", `{"jsonrpc":"2.0","id":1,"method":"test_heavy","params":[]}`)
+	require.NotNil(t, res[0].Error)
+	require.Equal(t, -32005, res[0].Error.Code)
+	require.NotContains(t, res[0].Error.Data, "retryAfterMs")
+}
+
+func TestMethodCosts(t *testing.T) {
+	t.Parallel()
+
+	l := newRequestLimiter(httpcfg.LimitsCfg{MethodCosts: map[string]int{"eth_getBlockByNumber": 7}})
+	require.Equal(t, 7, l.cost("eth_getBlockByNumber"))
+	require.Equal(t, rpccfg.MethodCosts["debug_getBlockByHash"], l.cost("debug_getBlockByHash"))
+	require.Greater(t, l.cost("debug_getBlockByHash"), rpccfg.DefaultMethodCost)
+	require.Equal(t, rpccfg.DefaultMethodCost, l.cost("eth_chainId"))
+}
+
+func TestResponseSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	ts := newLimitsTestServer(t, httpcfg.LimitsCfg{MaxResponseSize: 100})
+
+	res := callLimitsTestServer(t, ts, "", `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["small"]}`)
+	require.Nil(t, res[0].Error)
+	require.JSONEq(t, `"small"`, string(res[0].Result))
+
+	large := strings.Repeat("x", 200)
+	res = callLimitsTestServer(t, ts, "",
+		`[{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["small"]},`+
+			`{"jsonrpc":"2.0","id":2,"method":"test_echo","params":["`+large+`"]}]`)
+	require.Len(t, res, 2)
+	require.Nil(t, res[0].Error)
+	require.NotNil(t, res[1].Error)
+	require.Equal(t, -32005, res[1].Error.Code)
+
+	res = callLimitsTestServer(t, ts, "", `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["`+large+`"]}`)
+	require.NotNil(t, res[0].Error)
+	require.Equal(t, -32005, res[0].Error.Code)
+}
+
+func TestMethodTimeout(t *testing.T) {
+	t.Parallel()
+
+	ts := newLimitsTestServer(t, httpcfg.LimitsCfg{
+		MethodTimeouts: map[string]time.Duration{"test_sleep": 50 * time.Millisecond},
+	})
+
+	res := callLimitsTestServer(t, ts, "", `{"jsonrpc":"2.0","id":1,"method":"test_sleep","params":[]}`)
+	require.NotNil(t, res[0].Error)
+	require.Equal(t, -32002, res[0].Error.Code)
+
+	res = callLimitsTestServer(t, ts, "", `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["ok"]}`)
+	require.Nil(t, res[0].Error)
+}
@@ -26,3 +26,28 @@ var HeavyLogMethods = map[string]struct{}{
 	"eth_estimateGas":         {},
 	"eth_sendRawTransaction":  {},
 }
+
+// DefaultMethodCost is the rate limiting cost of the methods not listed in MethodCosts.
+const DefaultMethodCost = 1
+
+// MethodCosts are the rate limiting costs of the methods that are heavier than an ordinary lookup.
+var MethodCosts = map[string]int{
+	"eth_call":                  10,
+	"eth_estimateFee":           10,
+	"eth_getLogs":               20,
+	"eth_getFilterLogs":         20,
+	"eth_getProof":              5,
+	"eth_getBlockByNumber":      5,
+	"eth_getBlockByHash":        5,
+	"debug_getBlockByNumber":    5,
+	"debug_getBlockByHash":      5,
+	"txpool_getTxpoolContent":   10,
+	"indexer_getAddressActions": 20,
+	"eth_feeHistory":            5,
+	"eth_getTransactionTree":    5,
+	"eth_sendRawTransaction":    5,
+	"debug_getContract":         5,
+	"debug_traceTransaction":    50,
+	"debug_traceCall":           50,
+	"cometa_registerContract":   20,
+}
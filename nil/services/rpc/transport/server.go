@@ -8,14 +8,18 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
+	"slices"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
 	nil_http "github.com/NilFoundation/nil/nil/services/rpc/internal/http"
 )
 
@@ -30,8 +34,12 @@ type ContextKey string
 var HeadersContextKey ContextKey = "headers"
 
 type metricsHandler struct {
-	meter  telemetry.Meter
-	failed telemetry.Counter
+	meter            telemetry.Meter
+	failed           telemetry.Counter
+	cost             telemetry.Counter
+	rateLimited      telemetry.Counter
+	responseTooLarge telemetry.Counter
+	timedOut         telemetry.Counter
 }
 
 // Server is an RPC server.
@@ -39,6 +47,9 @@ type Server struct {
 	services serviceRegistry
 	run      int32
 
+	codecsMu sync.Mutex
+	codecs   map[ServerCodec]struct{}
+
 	batchConcurrency    uint
 	traceRequests       bool     // Whether to print requests at INFO level
 	debugSingleRequest  bool     // Whether to print requests at INFO level
@@ -46,6 +57,7 @@ type Server struct {
 	keepHeaders         []string // headers to pass to request handler
 	logger              logging.Logger
 	rpcSlowLogThreshold time.Duration
+	limiter             *requestLimiter
 	mh                  *metricsHandler
 }
 
@@ -60,10 +72,19 @@ func NewServer(
 	meter := telemetry.NewMeter("github.com/NilFoundation/nil/nil/services/rpc/transport")
 	failedCounter, err := meter.Int64Counter("failed")
 	check.PanicIfErr(err)
+	costCounter, err := meter.Int64Counter("cost")
+	check.PanicIfErr(err)
+	rateLimitedCounter, err := meter.Int64Counter("rate_limited")
+	check.PanicIfErr(err)
+	responseTooLargeCounter, err := meter.Int64Counter("response_too_large")
+	check.PanicIfErr(err)
+	timedOutCounter, err := meter.Int64Counter("timed_out")
+	check.PanicIfErr(err)
 
 	server := &Server{
 		services:            serviceRegistry{logger: logger},
 		run:                 1,
+		codecs:              make(map[ServerCodec]struct{}),
 		batchConcurrency:    defaultBatchConcurrency,
 		traceRequests:       traceRequests,
 		debugSingleRequest:  debugSingleRequest,
@@ -71,9 +92,14 @@ func NewServer(
 		keepHeaders:         keepHeaders,
 		logger:              logger,
 		rpcSlowLogThreshold: rpcSlowLogThreshold,
+		limiter:             newRequestLimiter(httpcfg.LimitsCfg{}),
 		mh: &metricsHandler{
-			meter:  meter,
-			failed: failedCounter,
+			meter:            meter,
+			failed:           failedCounter,
+			cost:             costCounter,
+			rateLimited:      rateLimitedCounter,
+			responseTooLarge: responseTooLargeCounter,
+			timedOut:         timedOutCounter,
 		},
 	}
 
@@ -95,6 +121,11 @@ func (s *Server) SetBatchLimit(limit int) {
 	s.batchLimit = limit
 }
 
+// SetLimits sets the per-client rate limits as well as the size and execution time limits of the requests.
+func (s *Server) SetLimits(cfg httpcfg.LimitsCfg) {
+	s.limiter = newRequestLimiter(cfg)
+}
+
 func newHTTPServerConn(r *http.Request, w http.ResponseWriter) ServerCodec {
 	conn := &nil_http.HttpServerConn{Writer: w, Request: r}
 	// if the request is a GET request, and the body is empty, we turn the request into fake json rpc request, see below
@@ -145,6 +176,7 @@ func (s *Server) ServeSingleRequest(ctx context.Context, r *http.Request, w http
 		headers.Add(h, r.Header.Get(h))
 	}
 	ctx = context.WithValue(ctx, HeadersContextKey, headers)
+	ctx = context.WithValue(ctx, ClientKeyContextKey, s.limiter.clientKey(r))
 
 	h := newHandler(
 		ctx,
@@ -154,6 +186,7 @@ func (s *Server) ServeSingleRequest(ctx context.Context, r *http.Request, w http
 		s.traceRequests,
 		s.logger,
 		s.rpcSlowLogThreshold,
+		s.limiter,
 		s.mh)
 
 	reqs, batch, err := codec.Read()
@@ -175,11 +208,105 @@ func (s *Server) ServeSingleRequest(ctx context.Context, r *http.Request, w http
 	}
 }
 
-// Stop stops reading new requests, waits for stopPendingRequestTimeout to allow pending
-// requests to finish, then closes all codecs that will cancel pending requests.
+// ServeCodec reads incoming requests from codec, calls the appropriate callback and writes
+// the response back using the given codec. Unlike ServeSingleRequest, the connection is kept open,
+// so subscriptions are supported. It blocks until the codec is closed or the server is stopped.
+func (s *Server) ServeCodec(ctx context.Context, codec ServerCodec) {
+	defer codec.Close()
+
+	// Don't serve if the server is stopped.
+	if !s.trackCodec(codec) {
+		return
+	}
+	defer s.untrackCodec(codec)
+
+	h := newHandler(
+		ctx,
+		codec,
+		&s.services,
+		s.batchConcurrency,
+		s.traceRequests,
+		s.logger,
+		s.rpcSlowLogThreshold,
+		s.limiter,
+		s.mh)
+	h.subs = newSubscriptionRegistry(codec)
+
+	// Requests in progress are canceled first, then we wait for them to return.
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer h.close()
+	inFlight := make(chan struct{}, s.limiter.cfg.ConnRequests())
+	for atomic.LoadInt32(&s.run) == 1 {
+		reqs, batch, err := codec.Read()
+		if err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				_ = codec.WriteJSON(ctx, errorMessage(&invalidMessageError{"parse error"}))
+			}
+			return
+		}
+
+		// Pipelined requests are processed concurrently up to the limit,
+		// the connection is not read further until some of them are done.
+		select {
+		case inFlight <- struct{}{}:
+		case <-codec.Closed():
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-inFlight
+				wg.Done()
+			}()
+			switch {
+			case !batch:
+				h.handleMsg(reqs[0])
+			case s.batchLimit > 0 && len(reqs) > s.batchLimit:
+				_ = codec.WriteJSON(ctx, errorMessage(fmt.Errorf(
+					"batch limit %d exceeded. Requested batch of size: %d", s.batchLimit, len(reqs))))
+			default:
+				h.handleBatch(reqs)
+			}
+		}()
+	}
+}
+
+// trackCodec registers the codec to be closed on Stop. It returns false if the server is already stopped.
+func (s *Server) trackCodec(codec ServerCodec) bool {
+	s.codecsMu.Lock()
+	defer s.codecsMu.Unlock()
+
+	if atomic.LoadInt32(&s.run) == 0 {
+		return false
+	}
+	s.codecs[codec] = struct{}{}
+	return true
+}
+
+func (s *Server) untrackCodec(codec ServerCodec) {
+	s.codecsMu.Lock()
+	defer s.codecsMu.Unlock()
+
+	delete(s.codecs, codec)
+}
+
+// Stop stops reading new requests and closes all codecs served by ServeCodec,
+// which cancels their pending requests and subscriptions.
 func (s *Server) Stop() {
-	if atomic.CompareAndSwapInt32(&s.run, 1, 0) {
-		s.logger.Info().Msg("RPC server shutting down")
+	if !atomic.CompareAndSwapInt32(&s.run, 1, 0) {
+		return
+	}
+	s.logger.Info().Msg("RPC server shutting down")
+
+	s.codecsMu.Lock()
+	codecs := slices.Collect(maps.Keys(s.codecs))
+	s.codecsMu.Unlock()
+
+	for _, codec := range codecs {
+		codec.Close()
 	}
 }
 
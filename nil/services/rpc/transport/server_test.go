@@ -1,10 +1,18 @@
 package transport
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
 	"github.com/NilFoundation/nil/nil/services/rpc/internal/http"
+	"github.com/stretchr/testify/require"
 )
 
 func TestServer(t *testing.T) {
@@ -26,3 +34,99 @@ func TestServer(t *testing.T) {
 			return &s, logger
 		})
 }
+
+func TestServerStopClosesCodecs(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(false /* traceRequests */, false /* traceSingleRequest */, logging.Nop(), 0, []string{})
+
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		server.ServeCodec(t.Context(), NewCodec(serverConn))
+	}()
+
+	// The connection stays open until the server is stopped.
+	require.Eventually(t, func() bool {
+		server.codecsMu.Lock()
+		defer server.codecsMu.Unlock()
+		return len(server.codecs) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	server.Stop()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ServeCodec did not return after Stop")
+	}
+
+	// No codecs are served after the server is stopped.
+	serverConn, clientConn = net.Pipe()
+	defer clientConn.Close()
+	server.ServeCodec(t.Context(), NewCodec(serverConn))
+	require.Empty(t, server.codecs)
+}
+
+type testBlockingService struct {
+	running atomic.Int32
+	release chan struct{}
+}
+
+func (s *testBlockingService) Block(ctx context.Context) (int, error) {
+	s.running.Add(1)
+	defer s.running.Add(-1)
+
+	select {
+	case <-s.release:
+		return 0, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func TestServeCodecLimitsConcurrentRequests(t *testing.T) {
+	t.Parallel()
+
+	const maxConnRequests = 2
+	server := NewServer(false /* traceRequests */, false /* traceSingleRequest */, logging.Nop(), 0, []string{})
+	server.SetLimits(httpcfg.LimitsCfg{MaxConnRequests: maxConnRequests})
+	service := &testBlockingService{release: make(chan struct{})}
+	require.NoError(t, server.RegisterName("test", service))
+	defer server.Stop()
+
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+	go server.ServeCodec(t.Context(), NewCodec(serverConn))
+
+	const requests = 5
+	go func() {
+		enc := json.NewEncoder(clientConn)
+		for i := range requests {
+			_ = enc.Encode(&Message{
+				Version: Version,
+				ID:      json.RawMessage(fmt.Sprint(i)),
+				Method:  "test_block",
+				Params:  json.RawMessage("[]"),
+			})
+		}
+	}()
+
+	// The pipelined requests are not processed beyond the limit.
+	require.Eventually(t, func() bool {
+		return service.running.Load() == maxConnRequests
+	}, time.Second, 10*time.Millisecond)
+	require.Never(t, func() bool {
+		return service.running.Load() > maxConnRequests
+	}, 100*time.Millisecond, 10*time.Millisecond)
+
+	close(service.release)
+	dec := json.NewDecoder(clientConn)
+	for range requests {
+		var msg Message
+		require.NoError(t, dec.Decode(&msg))
+		require.Nil(t, msg.Error)
+	}
+}
@@ -0,0 +1,207 @@
+package transport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	notificationMethodSuffix = "_subscription"
+
+	// notificationWriteTimeout limits the time spent writing a single notification to a slow client.
+	notificationWriteTimeout = 10 * time.Second
+)
+
+var (
+	// ErrNotificationsUnsupported is returned when the connection doesn't support notifications
+	// (e.g., it is a plain HTTP connection).
+	ErrNotificationsUnsupported = errors.New("notifications not supported")
+	// ErrSubscriptionNotFound is returned when the notification for the given id is not found.
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+)
+
+// SubscriptionID is the identifier of a subscription, unique within the server.
+type SubscriptionID string
+
+// NewSubscriptionID generates a random subscription identifier.
+func NewSubscriptionID() SubscriptionID {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return SubscriptionID("0x" + hex.EncodeToString(id[:]))
+}
+
+// Subscription is created by a Notifier and tied to it. The client can use it to receive notifications
+// about events until Err is closed (on unsubscribe or when the connection is closed).
+type Subscription struct {
+	ID        SubscriptionID
+	namespace string
+	err       chan error // closed on unsubscribe
+}
+
+// Err returns a channel that is closed when the client sends an unsubscribe request or the connection is closed.
+func (s *Subscription) Err() <-chan error {
+	return s.err
+}
+
+// subscriptionRegistry holds the subscriptions created over a single connection.
+type subscriptionRegistry struct {
+	conn JsonWriter
+
+	mu     sync.Mutex
+	subs   map[SubscriptionID]*Subscription
+	closed bool
+}
+
+func newSubscriptionRegistry(conn JsonWriter) *subscriptionRegistry {
+	return &subscriptionRegistry{
+		conn: conn,
+		subs: make(map[SubscriptionID]*Subscription),
+	}
+}
+
+func (r *subscriptionRegistry) closeAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, sub := range r.subs {
+		close(sub.err)
+		delete(r.subs, id)
+	}
+	r.closed = true
+}
+
+type notifierKey struct{}
+
+// Notifier is tied to an RPC connection that supports subscriptions.
+// Server callbacks use the notifier to send notifications.
+type Notifier struct {
+	reg       *subscriptionRegistry
+	namespace string
+
+	mu        sync.Mutex
+	activated bool
+	// buffer holds the notifications sent before the response to the subscribe call is written,
+	// the client doesn't know the subscription id until then and would drop them.
+	buffer []*Message
+}
+
+// NotifierFromContext returns the Notifier value stored in ctx, if any.
+// The notifier is available only for connections that support notifications (e.g., WebSocket).
+func NotifierFromContext(ctx context.Context) (*Notifier, bool) {
+	n, ok := ctx.Value(notifierKey{}).(*Notifier)
+	return n, ok
+}
+
+// contextWithNotifier returns the context of the call with a new notifier.
+// The notifier must be activated once the response to the call is written.
+func contextWithNotifier(ctx context.Context, reg *subscriptionRegistry, method string) (context.Context, *Notifier) {
+	namespace, _, _ := strings.Cut(method, serviceMethodSeparator)
+	n := &Notifier{reg: reg, namespace: namespace}
+	return context.WithValue(ctx, notifierKey{}, n), n
+}
+
+// CreateSubscription returns a new subscription that is coupled to the RPC connection.
+// Notifications for the subscription are sent as "<namespace>_subscription" calls.
+func (n *Notifier) CreateSubscription() (*Subscription, error) {
+	n.reg.mu.Lock()
+	defer n.reg.mu.Unlock()
+
+	if n.reg.closed {
+		return nil, ErrNotificationsUnsupported
+	}
+
+	sub := &Subscription{ID: NewSubscriptionID(), namespace: n.namespace, err: make(chan error)}
+	n.reg.subs[sub.ID] = sub
+	return sub, nil
+}
+
+// Unsubscribe removes the subscription with the given id and closes its Err channel.
+func (n *Notifier) Unsubscribe(id SubscriptionID) error {
+	n.reg.mu.Lock()
+	defer n.reg.mu.Unlock()
+
+	sub, ok := n.reg.subs[id]
+	if !ok {
+		return ErrSubscriptionNotFound
+	}
+	close(sub.err)
+	delete(n.reg.subs, id)
+	return nil
+}
+
+// Notify sends a notification to the client with the given data as payload.
+// The notifications sent before the response to the subscribe call are delayed until it is written.
+func (n *Notifier) Notify(id SubscriptionID, data any) error {
+	enc, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	n.reg.mu.Lock()
+	sub, ok := n.reg.subs[id]
+	n.reg.mu.Unlock()
+	if !ok {
+		return ErrSubscriptionNotFound
+	}
+
+	params, err := json.Marshal(&subscriptionResult{ID: id, Result: enc})
+	if err != nil {
+		return err
+	}
+
+	msg := &Message{
+		Version: Version,
+		Method:  sub.namespace + notificationMethodSuffix,
+		Params:  params,
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if !n.activated {
+		n.buffer = append(n.buffer, msg)
+		return nil
+	}
+	return n.send(msg)
+}
+
+// activate sends the buffered notifications, the following ones are sent right away.
+// It is called after the response to the subscribe call is written.
+func (n *Notifier) activate() {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.activated = true
+	for _, msg := range n.buffer {
+		if err := n.send(msg); err != nil {
+			break
+		}
+	}
+	n.buffer = nil
+}
+
+func (n *Notifier) send(msg *Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notificationWriteTimeout)
+	defer cancel()
+	return n.reg.conn.WriteJSON(ctx, msg)
+}
+
+// Closed returns a channel that is closed when the RPC connection is closed.
+func (n *Notifier) Closed() <-chan any {
+	return n.reg.conn.Closed()
+}
+
+type subscriptionResult struct {
+	ID     SubscriptionID  `json:"subscription"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
@@ -0,0 +1,88 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/stretchr/testify/require"
+)
+
+const testNotificationsPerSubscription = 10
+
+type testNotifyingService struct{}
+
+// Subscribe creates a subscription and sends the notifications before the id is returned to the client.
+func (s *testNotifyingService) Subscribe(ctx context.Context) (SubscriptionID, error) {
+	notifier, ok := NotifierFromContext(ctx)
+	if !ok {
+		return "", ErrNotificationsUnsupported
+	}
+	sub, err := notifier.CreateSubscription()
+	if err != nil {
+		return "", err
+	}
+	for i := range testNotificationsPerSubscription {
+		if err := notifier.Notify(sub.ID, i); err != nil {
+			return "", err
+		}
+	}
+	return sub.ID, nil
+}
+
+func TestSubscribeResponseGoesFirst(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(false /* traceRequests */, false /* traceSingleRequest */, logging.Nop(), 0, []string{})
+	require.NoError(t, server.RegisterName("test", &testNotifyingService{}))
+	defer server.Stop()
+
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+	go server.ServeCodec(t.Context(), NewCodec(serverConn))
+
+	const subscriptions = 50
+	go func() {
+		enc := json.NewEncoder(clientConn)
+		for i := range subscriptions {
+			_ = enc.Encode(&Message{
+				Version: Version,
+				ID:      json.RawMessage(fmt.Sprint(i)),
+				Method:  "test_subscribe",
+				Params:  json.RawMessage("[]"),
+			})
+		}
+	}()
+
+	dec := json.NewDecoder(clientConn)
+	known := make(map[SubscriptionID]int)
+	for range subscriptions * (testNotificationsPerSubscription + 1) {
+		var msg Message
+		require.NoError(t, dec.Decode(&msg))
+
+		if msg.Method == "" {
+			require.Nil(t, msg.Error)
+			var id SubscriptionID
+			require.NoError(t, json.Unmarshal(msg.Result, &id))
+			known[id] = 0
+			continue
+		}
+
+		require.Equal(t, "test"+notificationMethodSuffix, msg.Method)
+		var res subscriptionResult
+		require.NoError(t, json.Unmarshal(msg.Params, &res))
+
+		received, ok := known[res.ID]
+		require.True(t, ok, "notification for %s is received before the subscribe response", res.ID)
+		require.JSONEq(t, fmt.Sprint(received), string(res.Result), "notifications are reordered")
+		known[res.ID] = received + 1
+	}
+
+	require.Len(t, known, subscriptions)
+	for _, received := range known {
+		require.Equal(t, testNotificationsPerSubscription, received)
+	}
+}
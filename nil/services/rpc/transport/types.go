@@ -380,6 +380,21 @@ func AsBlockReference(ref any) (BlockReference, error) {
 	return BlockReference{}, nil
 }
 
+// ErrBlockNumberRequired is returned by AsBlockNumber if the reference is not a block number.
+var ErrBlockNumberRequired = errors.New("block number is required")
+
+// AsBlockNumber is like AsBlockReference, but accepts only references to block numbers.
+func AsBlockNumber(ref any) (BlockNumber, error) {
+	br, err := AsBlockReference(ref)
+	if err != nil {
+		return 0, err
+	}
+	if br.BlockNumber == nil {
+		return 0, ErrBlockNumberRequired
+	}
+	return *br.BlockNumber, nil
+}
+
 func IntBlockReference(blockNr *big.Int) BlockReference {
 	if blockNr == nil {
 		return BlockReference{}
@@ -0,0 +1,166 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	nil_http "github.com/NilFoundation/nil/nil/services/rpc/internal/http"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsReadBuffer       = 1024
+	wsWriteBuffer      = 1024
+	wsPingInterval     = 30 * time.Second
+	wsPingWriteTimeout = 5 * time.Second
+	wsPongTimeout      = 30 * time.Second
+)
+
+// IsWebsocket checks whether the request is a WebSocket upgrade request.
+func IsWebsocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
+
+// WebsocketHandler returns a handler that serves JSON-RPC over WebSocket connections.
+// allowedOrigins restricts the Origin header of the handshake; "*" allows any origin.
+func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  wsReadBuffer,
+		WriteBufferSize: wsWriteBuffer,
+		CheckOrigin:     wsHandshakeValidator(allowedOrigins),
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			s.logger.Debug().Err(err).Msg("WebSocket upgrade failed")
+			return
+		}
+
+		headers := http.Header{}
+		for _, h := range s.keepHeaders {
+			headers.Add(h, r.Header.Get(h))
+		}
+		// The connection outlives the request, so its context must not be canceled together with the request.
+		ctx := context.WithValue(context.WithoutCancel(r.Context()), HeadersContextKey, headers)
+		ctx = context.WithValue(ctx, ClientKeyContextKey, s.limiter.clientKey(r))
+
+		codec := newWebsocketCodec(conn, r)
+		s.logger.Debug().Str(logging.FieldUrl, codec.RemoteAddr()).Msg("WebSocket connection opened")
+		s.ServeCodec(ctx, codec)
+		s.logger.Debug().Str(logging.FieldUrl, codec.RemoteAddr()).Msg("WebSocket connection closed")
+	})
+}
+
+// NewWebsocketUpgradeHandler dispatches WebSocket upgrade requests to ws and all other requests to next.
+func NewWebsocketUpgradeHandler(next http.Handler, ws http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if IsWebsocket(r) {
+			ws.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func wsHandshakeValidator(allowedOrigins []string) func(*http.Request) bool {
+	allowAll := slices.Contains(allowedOrigins, "*")
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		// Non-browser clients usually don't send the Origin header.
+		if origin == "" || allowAll {
+			return true
+		}
+		if slices.ContainsFunc(allowedOrigins, func(allowed string) bool {
+			return strings.EqualFold(allowed, origin)
+		}) {
+			return true
+		}
+		// Same-origin requests are always allowed.
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, r.Host)
+	}
+}
+
+// websocketCodec is a ServerCodec over a WebSocket connection. It keeps the connection alive by sending pings
+// and closes it if the peer doesn't answer.
+type websocketCodec struct {
+	ServerCodec
+
+	conn *websocket.Conn
+	wg   sync.WaitGroup
+	stop chan struct{}
+	once sync.Once
+}
+
+func newWebsocketCodec(conn *websocket.Conn, r *http.Request) *websocketCodec {
+	conn.SetReadLimit(nil_http.MaxRequestContentLength)
+	// The connection may inherit the read deadline of the HTTP server, so it is reset here.
+	_ = conn.SetReadDeadline(time.Now().Add(wsPingInterval + wsPongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPingInterval + wsPongTimeout))
+	})
+
+	wc := &websocketCodec{
+		ServerCodec: NewFuncCodec(&wsConnAdapter{conn: conn, remote: r.RemoteAddr}, conn.WriteJSON, func(v any) error {
+			return conn.ReadJSON(v)
+		}),
+		conn: conn,
+		stop: make(chan struct{}),
+	}
+	wc.wg.Add(1)
+	go wc.pingLoop()
+	return wc
+}
+
+func (wc *websocketCodec) Close() {
+	wc.once.Do(func() {
+		close(wc.stop)
+	})
+	wc.ServerCodec.Close()
+	wc.wg.Wait()
+}
+
+func (wc *websocketCodec) pingLoop() {
+	defer wc.wg.Done()
+
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-wc.stop:
+			return
+		case <-ticker.C:
+			// WriteControl is safe to call concurrently with other writes.
+			if err := wc.conn.WriteControl(
+				websocket.PingMessage, nil, time.Now().Add(wsPingWriteTimeout)); err != nil {
+				wc.ServerCodec.Close()
+				return
+			}
+		}
+	}
+}
+
+// wsConnAdapter provides the part of net.Conn interface required by the codec.
+type wsConnAdapter struct {
+	conn   *websocket.Conn
+	remote string
+}
+
+func (a *wsConnAdapter) Close() error {
+	return a.conn.Close()
+}
+
+func (a *wsConnAdapter) SetWriteDeadline(t time.Time) error {
+	return a.conn.SetWriteDeadline(t)
+}
+
+func (a *wsConnAdapter) RemoteAddr() string {
+	return a.remote
+}
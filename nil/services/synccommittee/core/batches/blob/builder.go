@@ -31,7 +31,24 @@ func (bb *builderImpl) MakeBlobs(rd io.Reader, blobLimit uint) ([]kzg4844.Blob,
 	eof := false
 	writtenBits := 0
 
-	bitReader := bitio.NewReader(rd) // bit wrapper for reading 254-bit pieces of data to place into the blobs
+	data, err := io.ReadAll(rd)
+	if err != nil {
+		return nil, err
+	}
+	// bit wrapper for reading 254-bit pieces of data to place into the blobs
+	bitReader := bitio.NewReader(bytes.NewReader(data))
+	readBits := 0
+
+	// the bit reader drops the cached bits of the last byte when it hits EOF in the middle of the read,
+	// so the bits of the input which don't fill the last read are taken from it directly
+	writeTail := func(w *bitio.Writer) error {
+		tail := len(data)*8 - readBits
+		if tail <= 0 {
+			return nil
+		}
+		readBits += tail
+		return w.WriteBits(uint64(data[len(data)-1])&(1<<tail-1), uint8(tail))
+	}
 
 	var blobBuf bytes.Buffer
 	blobBuf.Grow(blobSize)
@@ -66,9 +83,17 @@ func (bb *builderImpl) MakeBlobs(rd io.Reader, blobLimit uint) ([]kzg4844.Blob,
 
 			if read < len(ethWordBuf) {
 				writtenBits += read * 8
+				readBits += read * 8
+				if err := writeTail(blobWriter); err != nil {
+					return nil, err
+				}
+				if _, err := blobWriter.Align(); err != nil {
+					return nil, err
+				}
 				eof = true
 				break
 			}
+			readBits += read * 8
 
 			const lastByteBits = 6 // each 2 last bits of every u256 word cannot be used
 
@@ -77,8 +102,15 @@ func (bb *builderImpl) MakeBlobs(rd io.Reader, blobLimit uint) ([]kzg4844.Blob,
 				return nil, err
 			}
 
-			if err := blobWriter.WriteBits(lastbyte, lastByteBits); err != nil {
-				return nil, err
+			if eof {
+				if err := writeTail(blobWriter); err != nil {
+					return nil, err
+				}
+			} else {
+				readBits += lastByteBits
+				if err := blobWriter.WriteBits(lastbyte, lastByteBits); err != nil {
+					return nil, err
+				}
 			}
 
 			aligned, err := blobWriter.Align()
@@ -75,3 +75,20 @@ func TestEmptyData(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, blobs)
 }
+
+func TestMakeBlobs_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// the lengths which end the data in the middle of the input byte, of the 6-bit word tail, and on the word end
+	for _, size := range []int{1, 7, 31, 32, 100, 127, 128, blobSize} {
+		input := bytes.Repeat([]byte{0xA5}, size)
+		blobs, err := NewBuilder().MakeBlobs(bytes.NewReader(input), 2)
+		require.NoError(t, err)
+
+		output := make([]byte, len(blobs)*blobSize)
+		n, err := NewReader(blobs).Read(output)
+		require.NoError(t, err)
+		require.Equal(t, input, output[:size], "size %d", size)
+		require.Empty(t, bytes.TrimRight(output[size:n], "\x00"), "size %d", size)
+	}
+}
@@ -0,0 +1,39 @@
+package da
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+type Config struct {
+	Backend types.DABackend `yaml:"daBackend,omitempty"`
+	// CalldataFallbackBlobFee is the blob base fee in wei above which the l1-blob backend publishes
+	// the batch data as calldata instead, zero disables the fallback
+	CalldataFallbackBlobFee uint64 `yaml:"daCalldataFallbackBlobFee,omitempty"`
+	// LocalDir is the directory the local backend stores the batch data in
+	LocalDir     string `yaml:"daLocalDir,omitempty"`
+	MaxBlobsInTx uint   `yaml:"-"`
+}
+
+func NewDefaultConfig() Config {
+	return Config{
+		Backend:      types.DABackendL1Blob,
+		LocalDir:     "batches",
+		MaxBlobsInTx: 6,
+	}
+}
+
+func (c *Config) Validate() error {
+	switch c.Backend {
+	case types.DABackendL1Blob, types.DABackendL1Calldata:
+	case types.DABackendLocal:
+		if c.LocalDir == "" {
+			return errors.New("local data availability backend requires a directory")
+		}
+	default:
+		return fmt.Errorf("unknown data availability backend %q", c.Backend)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package da
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/rollupcontract"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// DataAvailability publishes the encoded batches.
+// The data is prepared first, so the commitment can be stored along with the batch before the data is published.
+type DataAvailability interface {
+	Prepare(ctx context.Context, batchId types.BatchId, data []byte) (*PreparedBatch, error)
+	Publish(ctx context.Context, batch *PreparedBatch) error
+}
+
+// PreparedBatch is the batch data ready to be published.
+type PreparedBatch struct {
+	BatchId    types.BatchId
+	Commitment *types.DACommitment
+	// DataProofs are the proofs of the blobs data passed to the rollup contract on the state update
+	DataProofs types.DataProofs
+
+	data    []byte
+	sidecar *ethtypes.BlobTxSidecar
+}
+
+// New creates the data availability backend selected in the config.
+// Every backend commits the published batch to the rollup contract: the l1-blob backend with the blobs
+// the data proofs are verified against, the l1-calldata backend with the data itself, and the local backend
+// with the hash of the data only, which the contract accepts from the devnet proposers.
+func New(config Config, contractWrapper rollupcontract.Wrapper, logger logging.Logger) (DataAvailability, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid data availability config: %w", err)
+	}
+
+	logger = logger.With().Str(logging.FieldComponent, "data_availability").Logger()
+	switch config.Backend {
+	case types.DABackendL1Blob:
+		backend := NewL1BlobBackend(contractWrapper, config.MaxBlobsInTx, logger)
+		if config.CalldataFallbackBlobFee > 0 {
+			backend.SetCalldataFallback(NewL1CalldataBackend(contractWrapper, logger), config.CalldataFallbackBlobFee)
+		}
+		return backend, nil
+	case types.DABackendL1Calldata:
+		return NewL1CalldataBackend(contractWrapper, logger), nil
+	case types.DABackendLocal:
+		return NewLocalBackend(contractWrapper, config.LocalDir, logger), nil
+	}
+	return nil, fmt.Errorf("unknown data availability backend %q", config.Backend)
+}
+
+func newCommitment(backend types.DABackend, data []byte) *types.DACommitment {
+	return &types.DACommitment{
+		Backend:  backend,
+		DataHash: common.KeccakHash(data),
+	}
+}
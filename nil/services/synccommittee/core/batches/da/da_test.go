@@ -0,0 +1,154 @@
+package da
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/rollupcontract"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+// wrapperStub records the data published through the rollup contract wrapper.
+type wrapperStub struct {
+	rollupcontract.Wrapper
+
+	blobBaseFee int64
+	committed   []string
+	dataHashes  []common.Hash
+	calldata    [][]byte
+}
+
+func (w *wrapperStub) PrepareBlobs(
+	_ context.Context, blobs []kzg4844.Blob,
+) (*ethtypes.BlobTxSidecar, types.DataProofs, error) {
+	sidecar := &ethtypes.BlobTxSidecar{Blobs: blobs, Commitments: make([]kzg4844.Commitment, len(blobs))}
+	return sidecar, make(types.DataProofs, len(blobs)), nil
+}
+
+func (w *wrapperStub) CommitBatch(_ context.Context, _ *ethtypes.BlobTxSidecar, batchIndex string) error {
+	w.committed = append(w.committed, batchIndex)
+	return nil
+}
+
+func (w *wrapperStub) CommitBatchCalldata(_ context.Context, batchIndex string, data []byte) error {
+	w.committed = append(w.committed, batchIndex)
+	w.calldata = append(w.calldata, data)
+	return nil
+}
+
+func (w *wrapperStub) CommitBatchDataHash(_ context.Context, batchIndex string, dataHash common.Hash) error {
+	w.committed = append(w.committed, batchIndex)
+	w.dataHashes = append(w.dataHashes, dataHash)
+	return nil
+}
+
+func (w *wrapperStub) BlobBaseFee(context.Context) (*big.Int, error) {
+	return big.NewInt(w.blobBaseFee), nil
+}
+
+func newTestBackend(t *testing.T, config Config, wrapper rollupcontract.Wrapper) DataAvailability {
+	t.Helper()
+
+	backend, err := New(config, wrapper, logging.NewLogger("da_test"))
+	require.NoError(t, err)
+	return backend
+}
+
+func TestL1BlobCalldataFallback(t *testing.T) {
+	t.Parallel()
+
+	wrapper := &wrapperStub{blobBaseFee: 10}
+	config := NewDefaultConfig()
+	config.CalldataFallbackBlobFee = 100
+	backend := newTestBackend(t, config, wrapper)
+	data := []byte("batch data")
+
+	// the blob fee is below the threshold
+	batchId := types.NewBatchId()
+	prepared, err := backend.Prepare(t.Context(), batchId, data)
+	require.NoError(t, err)
+	require.Equal(t, types.DABackendL1Blob, prepared.Commitment.Backend)
+	require.Equal(t, common.KeccakHash(data), prepared.Commitment.DataHash)
+	require.Len(t, prepared.DataProofs, 1)
+
+	require.NoError(t, backend.Publish(t.Context(), prepared))
+	require.Equal(t, []string{batchId.String()}, wrapper.committed)
+	require.Empty(t, wrapper.calldata)
+
+	// the blob fee spikes
+	wrapper.blobBaseFee = 1000
+	batchId = types.NewBatchId()
+	prepared, err = backend.Prepare(t.Context(), batchId, data)
+	require.NoError(t, err)
+	require.Equal(t, types.DABackendL1Calldata, prepared.Commitment.Backend)
+	require.Empty(t, prepared.DataProofs)
+
+	// the batch is committed with the data itself, as there are no blobs
+	firstBatchId := wrapper.committed[0]
+	require.NoError(t, backend.Publish(t.Context(), prepared))
+	require.Equal(t, []string{firstBatchId, batchId.String()}, wrapper.committed)
+	require.Equal(t, [][]byte{data}, wrapper.calldata)
+	require.Empty(t, wrapper.dataHashes)
+}
+
+func TestL1CalldataTooLarge(t *testing.T) {
+	t.Parallel()
+
+	config := NewDefaultConfig()
+	config.Backend = types.DABackendL1Calldata
+	backend := newTestBackend(t, config, &wrapperStub{})
+
+	_, err := backend.Prepare(t.Context(), types.NewBatchId(), make([]byte, MaxCalldataSize+1))
+	require.ErrorIs(t, err, ErrCalldataTooLarge)
+}
+
+func TestLocalBackend(t *testing.T) {
+	t.Parallel()
+
+	config := NewDefaultConfig()
+	config.Backend = types.DABackendLocal
+	config.LocalDir = filepath.Join(t.TempDir(), "batches")
+	wrapper := &wrapperStub{}
+	backend := newTestBackend(t, config, wrapper)
+
+	batchId := types.NewBatchId()
+	data := []byte("batch data")
+	prepared, err := backend.Prepare(t.Context(), batchId, data)
+	require.NoError(t, err)
+	require.Equal(t, types.DABackendLocal, prepared.Commitment.Backend)
+	require.Equal(t, filepath.Join(config.LocalDir, batchId.String()+".bin"), prepared.Commitment.Location)
+
+	// nothing is written before publishing
+	_, err = os.Stat(prepared.Commitment.Location)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, backend.Publish(t.Context(), prepared))
+	stored, err := os.ReadFile(prepared.Commitment.Location)
+	require.NoError(t, err)
+	require.Equal(t, data, stored)
+	require.Equal(t, []string{batchId.String()}, wrapper.committed)
+	require.Equal(t, []common.Hash{common.KeccakHash(data)}, wrapper.dataHashes)
+}
+
+func TestInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	config := NewDefaultConfig()
+	config.Backend = "ipfs"
+	_, err := New(config, &wrapperStub{}, logging.NewLogger("da_test"))
+	require.Error(t, err)
+
+	config = NewDefaultConfig()
+	config.Backend = types.DABackendLocal
+	config.LocalDir = ""
+	_, err = New(config, &wrapperStub{}, logging.NewLogger("da_test"))
+	require.Error(t, err)
+}
@@ -0,0 +1,107 @@
+package da
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/blob"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/rollupcontract"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+// l1BlobBackend publishes the batch data as blobs of the rollup contract `commitBatch` transaction.
+// If the calldata fallback is set, the data is published as calldata while the blob base fee exceeds the threshold.
+type l1BlobBackend struct {
+	contractWrapper rollupcontract.Wrapper
+	blobBuilder     blob.Builder
+	maxBlobsInTx    uint
+
+	fallback        *l1CalldataBackend
+	fallbackBlobFee *big.Int
+
+	logger logging.Logger
+}
+
+var _ DataAvailability = (*l1BlobBackend)(nil)
+
+func NewL1BlobBackend(
+	contractWrapper rollupcontract.Wrapper, maxBlobsInTx uint, logger logging.Logger,
+) *l1BlobBackend {
+	return &l1BlobBackend{
+		contractWrapper: contractWrapper,
+		blobBuilder:     blob.NewBuilder(),
+		maxBlobsInTx:    maxBlobsInTx,
+		logger:          logger,
+	}
+}
+
+// SetCalldataFallback makes the backend publish the data as calldata when the blob base fee exceeds blobFee.
+func (b *l1BlobBackend) SetCalldataFallback(fallback *l1CalldataBackend, blobFee uint64) {
+	b.fallback = fallback
+	b.fallbackBlobFee = new(big.Int).SetUint64(blobFee)
+}
+
+func (b *l1BlobBackend) Prepare(ctx context.Context, batchId types.BatchId, data []byte) (*PreparedBatch, error) {
+	if b.useFallback(ctx, data) {
+		return b.fallback.Prepare(ctx, batchId, data)
+	}
+
+	blobs, err := b.blobBuilder.MakeBlobs(bytes.NewReader(data), b.maxBlobsInTx)
+	if err != nil {
+		return nil, err
+	}
+
+	sidecar, dataProofs, err := b.contractWrapper.PrepareBlobs(ctx, blobs)
+	if err != nil {
+		return nil, err
+	}
+
+	commitment := newCommitment(types.DABackendL1Blob, data)
+	if sidecar != nil {
+		for _, hash := range sidecar.BlobHashes() {
+			commitment.BlobHashes = append(commitment.BlobHashes, common.Hash(hash))
+		}
+	}
+
+	return &PreparedBatch{
+		BatchId:    batchId,
+		Commitment: commitment,
+		DataProofs: dataProofs,
+		data:       data,
+		sidecar:    sidecar,
+	}, nil
+}
+
+func (b *l1BlobBackend) useFallback(ctx context.Context, data []byte) bool {
+	if b.fallback == nil {
+		return false
+	}
+	if len(data) > MaxCalldataSize {
+		return false
+	}
+
+	blobFee, err := b.contractWrapper.BlobBaseFee(ctx)
+	if err != nil {
+		b.logger.Warn().Err(err).Msg("failed to get blob base fee, publishing blobs")
+		return false
+	}
+	if blobFee.Cmp(b.fallbackBlobFee) <= 0 {
+		return false
+	}
+
+	b.logger.Warn().
+		Stringer("blobBaseFee", blobFee).
+		Stringer("threshold", b.fallbackBlobFee).
+		Msg("blob base fee exceeds the threshold, publishing calldata")
+	return true
+}
+
+func (b *l1BlobBackend) Publish(ctx context.Context, batch *PreparedBatch) error {
+	if batch.Commitment.Backend == types.DABackendL1Calldata && b.fallback != nil {
+		return b.fallback.Publish(ctx, batch)
+	}
+	return b.contractWrapper.CommitBatch(ctx, batch.sidecar, batch.BatchId.String())
+}
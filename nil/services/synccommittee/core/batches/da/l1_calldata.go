@@ -0,0 +1,57 @@
+package da
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/rollupcontract"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+// MaxCalldataSize is the limit of the batch data published as calldata,
+// it keeps the transaction below the default size limit of the L1 transaction pool.
+const MaxCalldataSize = 120 * 1024
+
+var ErrCalldataTooLarge = errors.New("batch data is too large for calldata")
+
+// l1CalldataBackend publishes the batch data as calldata of the transaction committing the batch
+// to the rollup contract, the contract stores the hash of the data.
+type l1CalldataBackend struct {
+	contractWrapper rollupcontract.Wrapper
+	logger          logging.Logger
+}
+
+var _ DataAvailability = (*l1CalldataBackend)(nil)
+
+func NewL1CalldataBackend(contractWrapper rollupcontract.Wrapper, logger logging.Logger) *l1CalldataBackend {
+	return &l1CalldataBackend{
+		contractWrapper: contractWrapper,
+		logger:          logger,
+	}
+}
+
+func (b *l1CalldataBackend) Prepare(_ context.Context, batchId types.BatchId, data []byte) (*PreparedBatch, error) {
+	if len(data) > MaxCalldataSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrCalldataTooLarge, len(data))
+	}
+
+	return &PreparedBatch{
+		BatchId:    batchId,
+		Commitment: newCommitment(types.DABackendL1Calldata, data),
+		data:       data,
+	}, nil
+}
+
+func (b *l1CalldataBackend) Publish(ctx context.Context, batch *PreparedBatch) error {
+	if err := b.contractWrapper.CommitBatchCalldata(ctx, batch.BatchId.String(), batch.data); err != nil {
+		return fmt.Errorf("failed to publish batch data as calldata: %w", err)
+	}
+
+	b.logger.Info().
+		Stringer(logging.FieldBatchId, batch.BatchId).
+		Int("dataLen", len(batch.data)).
+		Msg("batch data published as calldata")
+	return nil
+}
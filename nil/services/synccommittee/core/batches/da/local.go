@@ -0,0 +1,67 @@
+package da
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/rollupcontract"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+// localBackend stores the batch data in the local directory, one file per batch.
+// It's intended for the devnets which can't publish the data to L1.
+// The batch is committed to the rollup contract with the hash of the data, which requires
+// the devnet proposer role, since L1 can't check that the data is available.
+type localBackend struct {
+	contractWrapper rollupcontract.Wrapper
+	dir             string
+	logger          logging.Logger
+}
+
+var _ DataAvailability = (*localBackend)(nil)
+
+func NewLocalBackend(contractWrapper rollupcontract.Wrapper, dir string, logger logging.Logger) *localBackend {
+	return &localBackend{
+		contractWrapper: contractWrapper,
+		dir:             dir,
+		logger:          logger,
+	}
+}
+
+func (b *localBackend) Prepare(_ context.Context, batchId types.BatchId, data []byte) (*PreparedBatch, error) {
+	commitment := newCommitment(types.DABackendLocal, data)
+	commitment.Location = filepath.Join(b.dir, batchId.String()+".bin")
+
+	return &PreparedBatch{
+		BatchId:    batchId,
+		Commitment: commitment,
+		data:       data,
+	}, nil
+}
+
+func (b *localBackend) Publish(ctx context.Context, batch *PreparedBatch) error {
+	if err := os.MkdirAll(b.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create batches directory: %w", err)
+	}
+
+	// write to the temporary file first, so the partially written data is never visible
+	path := batch.Commitment.Location
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, batch.data, 0o644); err != nil {
+		return fmt.Errorf("failed to write batch data: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write batch data: %w", err)
+	}
+
+	b.logger.Info().
+		Stringer(logging.FieldBatchId, batch.BatchId).
+		Str("path", path).
+		Int("dataLen", len(batch.data)).
+		Msg("batch data stored locally")
+
+	return b.contractWrapper.CommitBatchDataHash(ctx, batch.BatchId.String(), batch.Commitment.DataHash)
+}
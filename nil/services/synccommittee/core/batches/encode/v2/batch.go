@@ -0,0 +1,88 @@
+package v2
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+// Batch is the content of the v2 encoded batch: the blocks range and the state diff of each shard.
+// Transactions are not published, the state diff is enough to reconstruct the L2 state.
+type Batch struct {
+	BatchId types.BatchId `json:"batchId"`
+	Shards  []*ShardBatch `json:"shards"`
+}
+
+type ShardBatch struct {
+	ShardId coreTypes.ShardId `json:"shardId"`
+	// FirstBlock and BlockCount define the range of the shard blocks included into the batch
+	FirstBlock coreTypes.BlockNumber `json:"firstBlock"`
+	BlockCount uint64                `json:"blockCount"`
+	// PrevBlockHash is the hash of the block preceding the range, it links the batch to the previous one
+	PrevBlockHash common.Hash `json:"prevBlockHash"`
+	// Timestamp is the timestamp of the last block in the range
+	Timestamp uint64               `json:"timestamp"`
+	Accounts  types.ShardStateDiff `json:"accounts"`
+}
+
+// NewBatch collects the per-shard blocks ranges and state diffs of the pruned batch.
+func NewBatch(in *types.PrunedBatch) *Batch {
+	shards := make(map[coreTypes.ShardId]*ShardBatch)
+	getShard := func(shardId coreTypes.ShardId) *ShardBatch {
+		shard, ok := shards[shardId]
+		if !ok {
+			shard = &ShardBatch{ShardId: shardId, Accounts: make(types.ShardStateDiff)}
+			shards[shardId] = shard
+		}
+		return shard
+	}
+
+	for _, block := range in.Blocks {
+		shard := getShard(block.ShardId)
+		if shard.BlockCount == 0 || block.BlockNumber < shard.FirstBlock {
+			shard.FirstBlock = block.BlockNumber
+			shard.PrevBlockHash = block.PrevBlockHash
+		}
+		shard.BlockCount++
+		shard.Timestamp = max(shard.Timestamp, block.Timestamp)
+	}
+
+	for shardId, diff := range in.StateDiff {
+		maps.Copy(getShard(shardId).Accounts, diff)
+	}
+
+	out := &Batch{BatchId: in.BatchId}
+	for _, shardId := range slices.Sorted(maps.Keys(shards)) {
+		out.Shards = append(out.Shards, shards[shardId])
+	}
+	return out
+}
+
+func (b *Batch) AccountCount() int {
+	count := 0
+	for _, shard := range b.Shards {
+		count += len(shard.Accounts)
+	}
+	return count
+}
+
+func sortedAddresses(accounts types.ShardStateDiff) []coreTypes.Address {
+	return slices.SortedFunc(maps.Keys(accounts), func(a, b coreTypes.Address) int {
+		return slices.Compare(a[:], b[:])
+	})
+}
+
+func sortedKeys(storage map[common.Hash]common.Hash) []common.Hash {
+	return slices.SortedFunc(maps.Keys(storage), func(a, b common.Hash) int {
+		return slices.Compare(a[:], b[:])
+	})
+}
+
+func sortedTokens(tokens map[coreTypes.TokenId]coreTypes.Value) []coreTypes.TokenId {
+	return slices.SortedFunc(maps.Keys(tokens), func(a, b coreTypes.TokenId) int {
+		return slices.Compare(a[:], b[:])
+	})
+}
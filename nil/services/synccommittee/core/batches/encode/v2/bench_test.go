@@ -0,0 +1,84 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+// loadRecordedBatches reads the batches with the collected state diffs recorded in testdata as JSON.
+func loadRecordedBatches(tb testing.TB) map[string]*types.PrunedBatch {
+	tb.Helper()
+
+	files, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	require.NoError(tb, err)
+	require.NotEmpty(tb, files)
+
+	batches := make(map[string]*types.PrunedBatch, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(tb, err)
+
+		var batch types.PrunedBatch
+		require.NoError(tb, json.Unmarshal(data, &batch))
+		batches[filepath.Base(file)] = &batch
+	}
+	return batches
+}
+
+func TestRecordedBatchesRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	logger := logging.NewLogger("sc_batch_encoder_test")
+	for name, prunedBatch := range loadRecordedBatches(t) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			require.NoError(t, NewEncoder(logger).Encode(prunedBatch, &out))
+
+			batch, err := NewDecoder(logger).Decode(&out)
+			require.NoError(t, err)
+			require.Equal(t, NewBatch(prunedBatch), batch)
+		})
+	}
+}
+
+// BenchmarkEncoders compares the size and the speed of the batch encodings on the recorded batches.
+func BenchmarkEncoders(b *testing.B) {
+	logger := logging.Nop()
+	encoders := map[string]encode.BatchEncoder{
+		"v1": v1.NewEncoder(logger),
+		"v2": NewEncoder(logger),
+	}
+
+	for name, batch := range loadRecordedBatches(b) {
+		for encoding, encoder := range encoders {
+			b.Run(name+"/"+encoding, func(b *testing.B) {
+				var out bytes.Buffer
+				for b.Loop() {
+					out.Reset()
+					require.NoError(b, encoder.Encode(batch, &out))
+				}
+				b.ReportMetric(float64(out.Len()), "bytes/batch")
+				b.ReportMetric(float64(out.Len())/float64(countTransactions(batch)), "bytes/txn")
+			})
+		}
+	}
+}
+
+func countTransactions(batch *types.PrunedBatch) int {
+	count := 0
+	for _, block := range batch.Blocks {
+		count += len(block.Transactions)
+	}
+	return max(count, 1)
+}
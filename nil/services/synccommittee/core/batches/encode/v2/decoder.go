@@ -0,0 +1,65 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
+)
+
+type decompressor interface {
+	Decompress(from io.Reader, to io.Writer) error
+}
+
+type decoder struct {
+	decompressor decompressor
+	logger       logging.Logger
+}
+
+func NewDecoder(logger logging.Logger) *decoder {
+	return &decoder{
+		decompressor: v1.NewZstdDecompressor(logger),
+		logger:       logger,
+	}
+}
+
+// Decode reads the batch from the binary format.
+func (d *decoder) Decode(from io.Reader) (*Batch, error) {
+	if err := encode.CheckBatchVersion(from, version); err != nil {
+		return nil, err
+	}
+
+	var decompressed bytes.Buffer
+	if err := d.decompressor.Decompress(from, &decompressed); err != nil {
+		return nil, err
+	}
+
+	return deserialize(&decompressed)
+}
+
+// DecodeIntermediate decodes data from binary format into human readable JSON form.
+func (d *decoder) DecodeIntermediate(from io.Reader, to io.Writer) error {
+	batch, err := d.Decode(from)
+	if err != nil {
+		return err
+	}
+
+	humanReadableForm, err := json.MarshalIndent(batch, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	n, err := to.Write(humanReadableForm)
+	if err != nil {
+		return err
+	}
+
+	d.logger.Debug().
+		Int("bytes_written", n).
+		Stringer("batch_id", batch.BatchId).
+		Msg("serialized batch to json")
+	return nil
+}
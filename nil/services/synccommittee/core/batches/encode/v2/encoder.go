@@ -0,0 +1,59 @@
+package v2
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+const version uint16 = 0x0002
+
+var ErrMissingStateDiff = errors.New("batch state diff is not collected")
+
+type compressor interface {
+	Compress(from io.Reader, to io.Writer) error
+}
+
+// batchEncoder publishes the per-shard state diffs of the batch instead of its transactions.
+type batchEncoder struct {
+	compressor compressor
+	logger     logging.Logger
+}
+
+var _ encode.BatchEncoder = (*batchEncoder)(nil)
+
+func NewEncoder(logger logging.Logger) *batchEncoder {
+	return &batchEncoder{
+		compressor: v1.NewZstdCompressor(logger),
+		logger:     logger,
+	}
+}
+
+func (be *batchEncoder) Encode(in *types.PrunedBatch, out io.Writer) error {
+	if in.StateDiff == nil {
+		return ErrMissingStateDiff
+	}
+
+	header := encode.NewBatchHeader(version)
+	if err := header.EncodeTo(out); err != nil {
+		return err
+	}
+
+	batch := NewBatch(in)
+	be.logger.Info().
+		Int("shard_count", len(batch.Shards)).
+		Int("account_count", batch.AccountCount()).
+		Msg("packed state diff to batch")
+
+	var serialized bytes.Buffer
+	if err := serialize(batch, &serialized); err != nil {
+		return err
+	}
+
+	return be.compressor.Compress(&serialized, out)
+}
@@ -0,0 +1,129 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStateDiff() types.StateDiff {
+	stateDiff := types.NewStateDiff()
+
+	balance := coreTypes.NewValueFromUint64(1_000_000)
+	seqno := hexutil.Uint64(7)
+	stateDiff.Update(coreTypes.ShardAndHexToAddress(1, "0x01"), &types.AccountDiff{
+		Balance: &balance,
+		Seqno:   &seqno,
+	})
+
+	zero := hexutil.Uint64(0)
+	stateDiff.Update(coreTypes.ShardAndHexToAddress(2, "0x02"), &types.AccountDiff{
+		ExtSeqno: &zero,
+		Code:     []byte{0x60, 0x80, 0x60, 0x40},
+		Storage: map[common.Hash]common.Hash{
+			common.HexToHash("0x01"): common.HexToHash("0x2a"),
+			common.HexToHash("0x02"): {},
+		},
+	})
+
+	stateDiff.Update(coreTypes.ShardAndHexToAddress(3, "0x04"), &types.AccountDiff{
+		Tokens: map[coreTypes.TokenId]coreTypes.Value{
+			*coreTypes.TokenIdForAddress(coreTypes.ShardAndHexToAddress(1, "0x01")): coreTypes.NewValueFromUint64(500),
+			*coreTypes.TokenIdForAddress(coreTypes.ShardAndHexToAddress(2, "0x02")): coreTypes.NewZeroValue(),
+		},
+		AsyncContext: map[coreTypes.TransactionIndex]*coreTypes.AsyncContext{
+			3: {ResponseProcessingGas: 100_000},
+			5: nil,
+		},
+	})
+
+	stateDiff.Delete(coreTypes.ShardAndHexToAddress(2, "0x03"))
+	return stateDiff
+}
+
+func TestEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	logger := logging.NewLogger("sc_batch_encoder_test")
+	prunedBatch := types.NewPrunedBatch(testaide.NewBlockBatch(3))
+	prunedBatch.StateDiff = newTestStateDiff()
+
+	var out bytes.Buffer
+	require.NoError(t, NewEncoder(logger).Encode(prunedBatch, &out))
+	encoded := bytes.NewReader(out.Bytes())
+	require.NoError(t, encode.CheckBatchVersion(encoded, version))
+
+	batch, err := NewDecoder(logger).Decode(bytes.NewReader(out.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, prunedBatch.BatchId, batch.BatchId)
+
+	// main shard and 3 execution shards
+	require.Len(t, batch.Shards, 4)
+	for i, shard := range batch.Shards {
+		assert.Equal(t, coreTypes.ShardId(i), shard.ShardId)
+		assert.Equal(t, uint64(1), shard.BlockCount)
+		if expected, ok := prunedBatch.StateDiff[shard.ShardId]; ok {
+			assert.Equal(t, expected, shard.Accounts)
+		} else {
+			assert.Empty(t, shard.Accounts)
+		}
+	}
+	assert.Equal(t, 4, batch.AccountCount())
+
+	for _, block := range prunedBatch.Blocks {
+		shard := batch.Shards[block.ShardId]
+		assert.Equal(t, block.BlockNumber, shard.FirstBlock)
+		assert.Equal(t, block.PrevBlockHash, shard.PrevBlockHash)
+		assert.Equal(t, block.Timestamp, shard.Timestamp)
+	}
+}
+
+func TestDecodeIntermediate(t *testing.T) {
+	t.Parallel()
+
+	logger := logging.NewLogger("sc_batch_encoder_test")
+	prunedBatch := types.NewPrunedBatch(testaide.NewBlockBatch(1))
+	prunedBatch.StateDiff = newTestStateDiff()
+
+	var out bytes.Buffer
+	require.NoError(t, NewEncoder(logger).Encode(prunedBatch, &out))
+
+	var decoded bytes.Buffer
+	require.NoError(t, NewDecoder(logger).DecodeIntermediate(&out, &decoded))
+
+	var batch Batch
+	require.NoError(t, json.Unmarshal(decoded.Bytes(), &batch))
+	assert.Equal(t, prunedBatch.BatchId, batch.BatchId)
+	assert.Equal(t, 4, batch.AccountCount())
+}
+
+func TestEncodeWithoutStateDiff(t *testing.T) {
+	t.Parallel()
+
+	logger := logging.NewLogger("sc_batch_encoder_test")
+	prunedBatch := types.NewPrunedBatch(testaide.NewBlockBatch(1))
+
+	var out bytes.Buffer
+	require.ErrorIs(t, NewEncoder(logger).Encode(prunedBatch, &out), ErrMissingStateDiff)
+}
+
+func TestDecodeOtherVersion(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	header := encode.NewBatchHeader(version + 1)
+	require.NoError(t, header.EncodeTo(&out))
+
+	_, err := NewDecoder(logging.NewLogger("sc_batch_encoder_test")).Decode(&out)
+	require.ErrorIs(t, err, encode.ErrInvalidVersion)
+}
@@ -0,0 +1,325 @@
+package v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// recordEnv enables rewriting of the recorded batch instead of checking it.
+const recordEnv = "NIL_RECORD_TESTDATA"
+
+var recordedBatchFile = filepath.Join("testdata", "async_tokens.json")
+
+// asyncCall is an outbound transaction sent by the contract handling the transaction,
+// it is issued the same way the async call precompile does.
+type asyncCall struct {
+	payload               coreTypes.InternalTransactionPayload
+	responseProcessingGas coreTypes.Gas
+}
+
+type recordedTxn struct {
+	*coreTypes.Transaction
+	calls []asyncCall
+}
+
+// batchRecorder executes the blocks of the shards on top of the committed state and collects them into a batch.
+type batchRecorder struct {
+	t         *testing.T
+	tx        db.RwTx
+	parents   map[coreTypes.ShardId]*coreTypes.Block
+	latest    map[coreTypes.ShardId]*coreTypes.Block
+	blocks    []*types.PrunedBlock
+	timestamp uint64
+	// pending holds the outbound transactions not delivered yet grouped by the destination shard
+	pending map[coreTypes.ShardId][]*recordedTxn
+}
+
+func newBatchRecorder(t *testing.T, tx db.RwTx) *batchRecorder {
+	t.Helper()
+
+	return &batchRecorder{
+		t:         t,
+		tx:        tx,
+		parents:   make(map[coreTypes.ShardId]*coreTypes.Block),
+		latest:    make(map[coreTypes.ShardId]*coreTypes.Block),
+		timestamp: 1740000000,
+		pending:   make(map[coreTypes.ShardId][]*recordedTxn),
+	}
+}
+
+func (r *batchRecorder) newExecutionState(shardId coreTypes.ShardId) *execution.ExecutionState {
+	r.t.Helper()
+
+	es, err := execution.NewExecutionState(r.tx, shardId, execution.StateParams{
+		Block:          r.latest[shardId],
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	require.NoError(r.t, err)
+	es.BaseFee = coreTypes.DefaultGasPrice
+	return es
+}
+
+func (r *batchRecorder) commit(es *execution.ExecutionState) *execution.BlockGenerationResult {
+	r.t.Helper()
+
+	blockId := coreTypes.BlockNumber(0)
+	if prev := r.latest[es.ShardId]; prev != nil {
+		blockId = prev.Id + 1
+	}
+	res, err := es.Commit(blockId, nil)
+	require.NoError(r.t, err)
+	r.latest[es.ShardId] = res.Block
+	return res
+}
+
+// genesis commits the state the batch is started from, it is not included into the batch.
+func (r *batchRecorder) genesis(shardId coreTypes.ShardId, setup func(es *execution.ExecutionState)) {
+	r.t.Helper()
+
+	es := r.newExecutionState(shardId)
+	setup(es)
+	r.parents[shardId] = r.commit(es).Block
+}
+
+// execute generates the next block of the shard from the given and the pending transactions.
+func (r *batchRecorder) execute(shardId coreTypes.ShardId, txns ...*recordedTxn) {
+	r.t.Helper()
+
+	es := r.newExecutionState(shardId)
+	txns = append(r.pending[shardId], txns...)
+	delete(r.pending, shardId)
+
+	for _, txn := range txns {
+		txn.TxId = es.InTxCounts[txn.From.ShardId()]
+		require.NoError(r.t, es.AcceptInternalTransaction(txn.Transaction))
+		es.AddInTransaction(txn.Transaction)
+
+		res := es.HandleTransaction(r.t.Context(), txn.Transaction, execution.NewTransactionPayer(txn.Transaction, es))
+		require.False(r.t, res.Failed(), "transaction failed: %s", res.Error)
+		for _, call := range txn.calls {
+			withdraw := call.payload.FeeCredit.Add(call.payload.Value)
+			require.NoError(r.t, es.SubBalance(txn.To, withdraw, tracing.BalanceDecreasePrecompile))
+			_, err := es.AddOutTransaction(txn.To, &call.payload, call.responseProcessingGas)
+			require.NoError(r.t, err)
+		}
+		es.AddReceipt(res)
+	}
+	if shardId.IsMainShard() {
+		es.ChildShardBlocks = make(map[coreTypes.ShardId]common.Hash)
+		for id, block := range r.latest {
+			if !id.IsMainShard() {
+				es.ChildShardBlocks[id] = block.Hash(id)
+			}
+		}
+	}
+
+	res := r.commit(es)
+	for _, txn := range res.OutTxns {
+		r.pending[txn.To.ShardId()] = append(r.pending[txn.To.ShardId()], &recordedTxn{Transaction: txn})
+	}
+
+	block := &types.PrunedBlock{
+		ShardId:       shardId,
+		BlockNumber:   res.Block.Id,
+		Timestamp:     r.timestamp,
+		PrevBlockHash: res.Block.PrevBlock,
+	}
+	for _, txn := range res.InTxns {
+		block.Transactions = append(block.Transactions, types.PrunedTransaction{
+			Flags:    txn.Flags,
+			Seqno:    hexutil.Uint64(txn.Seqno),
+			From:     txn.From,
+			To:       txn.To,
+			BounceTo: txn.BounceTo,
+			RefundTo: txn.RefundTo,
+			Value:    txn.Value,
+			Data:     hexutil.Bytes(txn.Data),
+		})
+	}
+	r.blocks = append(r.blocks, block)
+	r.timestamp++
+}
+
+// batch returns the executed blocks along with the state diff between the genesis and the latest blocks.
+func (r *batchRecorder) batch(batchId types.BatchId) *types.PrunedBatch {
+	r.t.Helper()
+
+	stateDiff := types.NewStateDiff()
+	for shardId, latest := range r.latest {
+		diff, err := execution.GetStateDiff(r.tx, shardId, r.parents[shardId], latest)
+		require.NoError(r.t, err)
+		for _, contract := range diff {
+			stateDiff.Apply(contract)
+		}
+	}
+	return &types.PrunedBatch{
+		BatchId:   batchId,
+		Blocks:    r.blocks,
+		StateDiff: stateDiff,
+	}
+}
+
+// recordBatch executes the blocks of two shards exchanging value, tokens and async requests
+// and returns them as a batch with the state diff collected from the resulting state.
+func recordBatch(t *testing.T) *types.PrunedBatch {
+	t.Helper()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	const (
+		walletCount = 4
+		userCount   = 16
+	)
+	// the counter stores the first word of the calldata in the slot of the caller
+	counterCode := coreTypes.Code{
+		byte(vm.CALLER), byte(vm.PUSH1), 0, byte(vm.CALLDATALOAD), byte(vm.SWAP1), byte(vm.SSTORE), byte(vm.STOP),
+	}
+	counter := coreTypes.ShardAndHexToAddress(2, "0xc0")
+	operator := coreTypes.ShardAndHexToAddress(1, "0x0f")
+	wallets := make([]coreTypes.Address, walletCount)
+	for i := range wallets {
+		wallets[i] = coreTypes.ShardAndHexToAddress(1, fmt.Sprintf("0x%04x", 0xa0+i))
+	}
+	user := func(i int) coreTypes.Address {
+		return coreTypes.ShardAndHexToAddress(2, fmt.Sprintf("0x%04x", 0x100+i))
+	}
+	token := func(i int) coreTypes.TokenId {
+		return *coreTypes.TokenIdForAddress(wallets[i])
+	}
+	value := coreTypes.NewValueFromUint64
+
+	recorder := newBatchRecorder(t, tx)
+	recorder.genesis(coreTypes.MainShardId, func(*execution.ExecutionState) {})
+	recorder.genesis(1, func(es *execution.ExecutionState) {
+		for i, wallet := range wallets {
+			require.NoError(t, es.CreateAccount(wallet))
+			require.NoError(t, es.SetBalance(wallet, execution.DefaultSendValue.Mul64(100)))
+			require.NoError(t, es.AddToken(wallet, token(i), value(uint64(1000*(i+1)))))
+		}
+		// the request sent before the batch is answered within it
+		require.NoError(t, es.SetAsyncContext(wallets[1], 7, &coreTypes.AsyncContext{
+			ResponseProcessingGas: vm.MinGasReserveForAsyncRequest,
+		}))
+	})
+	recorder.genesis(2, func(es *execution.ExecutionState) {
+		require.NoError(t, es.CreateAccount(counter))
+		require.NoError(t, es.SetCode(counter, counterCode))
+		require.NoError(t, es.SetState(counter, wallets[0].Hash(), common.IntToHash(1)))
+	})
+
+	// the operator triggers the wallets to pay the users and to update the counter
+	var seqno coreTypes.Seqno
+	trigger := func(wallet coreTypes.Address, calls ...asyncCall) *recordedTxn {
+		txn := execution.NewExecutionTransaction(operator, wallet, seqno, nil)
+		txn.Flags = coreTypes.NewTransactionFlags(coreTypes.TransactionFlagInternal)
+		txn.RefundTo = wallet
+		txn.BounceTo = wallet
+		seqno++
+		return &recordedTxn{Transaction: txn, calls: calls}
+	}
+	transfer := func(from int, to coreTypes.Address, amount uint64, tokens ...coreTypes.TokenBalance) asyncCall {
+		return asyncCall{payload: coreTypes.InternalTransactionPayload{
+			Kind:      coreTypes.ExecutionTransactionKind,
+			FeeCredit: execution.DefaultGasCredit,
+			Value:     value(amount),
+			Token:     tokens,
+			To:        to,
+			RefundTo:  wallets[from],
+			BounceTo:  wallets[from],
+		}}
+	}
+	request := func(from int, requestId uint64, data uint64) asyncCall {
+		return asyncCall{
+			payload: coreTypes.InternalTransactionPayload{
+				Kind:      coreTypes.ExecutionTransactionKind,
+				FeeCredit: execution.DefaultGasCredit,
+				To:        counter,
+				RefundTo:  wallets[from],
+				BounceTo:  wallets[from],
+				Data:      common.IntToHash(int(data)).Bytes(),
+				RequestId: requestId,
+			},
+			responseProcessingGas: vm.MinGasReserveForAsyncRequest,
+		}
+	}
+
+	var txns []*recordedTxn
+	for i := range wallets {
+		var calls []asyncCall
+		for j := i; j < userCount; j += walletCount {
+			calls = append(calls, transfer(i, user(j), uint64(1000+j),
+				coreTypes.TokenBalance{Token: token(i), Balance: value(uint64(10 * (j + 1)))}))
+		}
+		calls = append(calls, request(i, 1, uint64(i+1)))
+		txns = append(txns, trigger(wallets[i], calls...))
+	}
+	// the first wallet gives away the rest of its tokens
+	txns = append(txns, trigger(wallets[0], transfer(0, user(0), 0,
+		coreTypes.TokenBalance{Token: token(0), Balance: value(1000 - 10*(1+5+9+13))})))
+
+	responseData, err := (&coreTypes.AsyncResponsePayload{Success: true}).MarshalSSZ()
+	require.NoError(t, err)
+	response := &coreTypes.Transaction{
+		TransactionDigest: coreTypes.TransactionDigest{
+			Flags: coreTypes.NewTransactionFlags(
+				coreTypes.TransactionFlagInternal, coreTypes.TransactionFlagResponse),
+			To:           wallets[1],
+			Data:         responseData,
+			FeeCredit:    execution.DefaultGasCredit,
+			MaxFeePerGas: coreTypes.MaxFeePerGasDefault,
+		},
+		From:      counter,
+		RefundTo:  wallets[1],
+		BounceTo:  wallets[1],
+		RequestId: 7,
+	}
+
+	recorder.execute(1, append(txns, &recordedTxn{Transaction: response})...)
+	recorder.execute(2)
+	// the responses are handled, the last wallet sends a request that is answered in the next batch
+	recorder.execute(1, trigger(wallets[walletCount-1], request(walletCount-1, 2, 42)))
+	recorder.execute(2)
+	recorder.execute(coreTypes.MainShardId)
+
+	return recorder.batch(types.BatchId(uuid.MustParse("5c0a4f1e-3b7d-4e2a-9f61-8d2b7c4e1a03")))
+}
+
+// TestRecordedBatch checks that the recorded batch matches the execution of its blocks.
+// Set NIL_RECORD_TESTDATA to record it again after the changes of the execution.
+func TestRecordedBatch(t *testing.T) {
+	t.Parallel()
+
+	batch := recordBatch(t)
+	require.Len(t, batch.StateDiff, 2)
+
+	data, err := json.MarshalIndent(batch, "", "\t")
+	require.NoError(t, err)
+	if os.Getenv(recordEnv) != "" {
+		require.NoError(t, os.WriteFile(recordedBatchFile, append(data, '\n'), 0o600))
+		return
+	}
+
+	recorded, err := os.ReadFile(recordedBatchFile)
+	require.NoError(t, err)
+	require.JSONEq(t, string(recorded), string(data))
+}
@@ -0,0 +1,352 @@
+package v2
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+// The batch is serialized as follows, all the integers are unsigned varints:
+//
+//	batch id (16 bytes) | shard count | shards sorted by id
+//	shard:   shard id | first block | block count | prev block hash (32 bytes) | timestamp |
+//	         account count | accounts sorted by address
+//	account: address (20 bytes) | field flags (1 byte) | present fields in the order of flags
+//	fields:  balance (length-prefixed big-endian) | seqno | ext seqno | code (length-prefixed) |
+//	         slot count, slots sorted by key: key (32 bytes) | value (length-prefixed big-endian) |
+//	         token count, tokens sorted by id: id (20 bytes) | value (length-prefixed big-endian) |
+//	         async context count, contexts sorted by index: index | present (1 byte) | gas if present
+const (
+	flagBalance byte = 1 << iota
+	flagSeqno
+	flagExtSeqno
+	flagCode
+	flagStorage
+	flagDeleted
+	flagTokens
+	flagAsyncContext
+)
+
+// maxFieldLen limits the length-prefixed fields to protect the decoder from malformed input.
+const maxFieldLen = 1 << 24
+
+var errFieldTooLong = errors.New("field is too long")
+
+type writer struct {
+	out *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+	err error
+}
+
+func newWriter(out io.Writer) *writer {
+	return &writer{out: bufio.NewWriter(out)}
+}
+
+func (w *writer) write(data []byte) {
+	if w.err == nil {
+		_, w.err = w.out.Write(data)
+	}
+}
+
+func (w *writer) uvarint(v uint64) {
+	n := binary.PutUvarint(w.buf[:], v)
+	w.write(w.buf[:n])
+}
+
+func (w *writer) bytes(data []byte) {
+	w.uvarint(uint64(len(data)))
+	w.write(data)
+}
+
+func (w *writer) flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	return w.out.Flush()
+}
+
+func serialize(batch *Batch, out io.Writer) error {
+	w := newWriter(out)
+	w.write(batch.BatchId[:])
+	w.uvarint(uint64(len(batch.Shards)))
+	for _, shard := range batch.Shards {
+		w.uvarint(uint64(shard.ShardId))
+		w.uvarint(uint64(shard.FirstBlock))
+		w.uvarint(shard.BlockCount)
+		w.write(shard.PrevBlockHash[:])
+		w.uvarint(shard.Timestamp)
+		w.uvarint(uint64(len(shard.Accounts)))
+		for _, addr := range sortedAddresses(shard.Accounts) {
+			w.write(addr[:])
+			serializeAccount(w, shard.Accounts[addr])
+		}
+	}
+	return w.flush()
+}
+
+func serializeAccount(w *writer, acc *types.AccountDiff) {
+	var flags byte
+	if acc.Balance != nil {
+		flags |= flagBalance
+	}
+	if acc.Seqno != nil {
+		flags |= flagSeqno
+	}
+	if acc.ExtSeqno != nil {
+		flags |= flagExtSeqno
+	}
+	if acc.Code != nil {
+		flags |= flagCode
+	}
+	if len(acc.Storage) > 0 {
+		flags |= flagStorage
+	}
+	if acc.Deleted {
+		flags |= flagDeleted
+	}
+	if len(acc.Tokens) > 0 {
+		flags |= flagTokens
+	}
+	if len(acc.AsyncContext) > 0 {
+		flags |= flagAsyncContext
+	}
+	w.write([]byte{flags})
+
+	if acc.Balance != nil {
+		w.bytes(acc.Balance.Bytes())
+	}
+	if acc.Seqno != nil {
+		w.uvarint(uint64(*acc.Seqno))
+	}
+	if acc.ExtSeqno != nil {
+		w.uvarint(uint64(*acc.ExtSeqno))
+	}
+	if acc.Code != nil {
+		w.bytes(acc.Code)
+	}
+	if len(acc.Storage) > 0 {
+		w.uvarint(uint64(len(acc.Storage)))
+		for _, key := range sortedKeys(acc.Storage) {
+			value := acc.Storage[key]
+			w.write(key[:])
+			w.bytes(bytes.TrimLeft(value[:], "\x00"))
+		}
+	}
+	if len(acc.Tokens) > 0 {
+		w.uvarint(uint64(len(acc.Tokens)))
+		for _, id := range sortedTokens(acc.Tokens) {
+			w.write(id[:])
+			w.bytes(acc.Tokens[id].Bytes())
+		}
+	}
+	if len(acc.AsyncContext) > 0 {
+		w.uvarint(uint64(len(acc.AsyncContext)))
+		for _, index := range slices.Sorted(maps.Keys(acc.AsyncContext)) {
+			w.uvarint(uint64(index))
+			if ctx := acc.AsyncContext[index]; ctx != nil {
+				w.write([]byte{1})
+				w.uvarint(uint64(ctx.ResponseProcessingGas))
+			} else {
+				w.write([]byte{0})
+			}
+		}
+	}
+}
+
+type reader struct {
+	in *bufio.Reader
+}
+
+func (r *reader) read(data []byte) error {
+	_, err := io.ReadFull(r.in, data)
+	return err
+}
+
+func (r *reader) uvarint() (uint64, error) {
+	return binary.ReadUvarint(r.in)
+}
+
+func (r *reader) bytes() ([]byte, error) {
+	size, err := r.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if size > maxFieldLen {
+		return nil, fmt.Errorf("%w: %d bytes", errFieldTooLong, size)
+	}
+	data := make([]byte, size)
+	return data, r.read(data)
+}
+
+func deserialize(in io.Reader) (*Batch, error) {
+	r := &reader{in: bufio.NewReader(in)}
+
+	batch := &Batch{}
+	if err := r.read(batch.BatchId[:]); err != nil {
+		return nil, fmt.Errorf("failed to read batch id: %w", err)
+	}
+	shardCount, err := r.uvarint()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read shard count: %w", err)
+	}
+	for range shardCount {
+		shard, err := deserializeShard(r)
+		if err != nil {
+			return nil, err
+		}
+		batch.Shards = append(batch.Shards, shard)
+	}
+	return batch, nil
+}
+
+func deserializeShard(r *reader) (*ShardBatch, error) {
+	var header [3]uint64
+	for i := range header {
+		var err error
+		if header[i], err = r.uvarint(); err != nil {
+			return nil, fmt.Errorf("failed to read shard header: %w", err)
+		}
+	}
+	shard := &ShardBatch{
+		ShardId:    coreTypes.ShardId(header[0]),
+		FirstBlock: coreTypes.BlockNumber(header[1]),
+		BlockCount: header[2],
+		Accounts:   make(types.ShardStateDiff),
+	}
+	if err := r.read(shard.PrevBlockHash[:]); err != nil {
+		return nil, fmt.Errorf("failed to read shard %d prev block hash: %w", shard.ShardId, err)
+	}
+	var err error
+	if shard.Timestamp, err = r.uvarint(); err != nil {
+		return nil, fmt.Errorf("failed to read shard %d timestamp: %w", shard.ShardId, err)
+	}
+	accountCount, err := r.uvarint()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read shard %d account count: %w", shard.ShardId, err)
+	}
+	for range accountCount {
+		var addr coreTypes.Address
+		if err := r.read(addr[:]); err != nil {
+			return nil, fmt.Errorf("failed to read shard %d account address: %w", shard.ShardId, err)
+		}
+		acc, err := deserializeAccount(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read account %s: %w", addr, err)
+		}
+		shard.Accounts[addr] = acc
+	}
+	return shard, nil
+}
+
+func deserializeAccount(r *reader) (*types.AccountDiff, error) {
+	var flags [1]byte
+	if err := r.read(flags[:]); err != nil {
+		return nil, err
+	}
+	acc := &types.AccountDiff{Deleted: flags[0]&flagDeleted != 0}
+
+	if flags[0]&flagBalance != 0 {
+		data, err := r.bytes()
+		if err != nil {
+			return nil, err
+		}
+		balance := coreTypes.NewValueFromBytes(data)
+		acc.Balance = &balance
+	}
+	if flags[0]&flagSeqno != 0 {
+		seqno, err := r.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		acc.Seqno = (*hexutil.Uint64)(&seqno)
+	}
+	if flags[0]&flagExtSeqno != 0 {
+		extSeqno, err := r.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		acc.ExtSeqno = (*hexutil.Uint64)(&extSeqno)
+	}
+	if flags[0]&flagCode != 0 {
+		code, err := r.bytes()
+		if err != nil {
+			return nil, err
+		}
+		acc.Code = code
+	}
+	if flags[0]&flagStorage != 0 {
+		slotCount, err := r.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		acc.Storage = make(map[common.Hash]common.Hash)
+		for range slotCount {
+			var key common.Hash
+			if err := r.read(key[:]); err != nil {
+				return nil, err
+			}
+			value, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			acc.Storage[key] = common.BytesToHash(value)
+		}
+	}
+	if flags[0]&flagTokens != 0 {
+		tokenCount, err := r.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		acc.Tokens = make(map[coreTypes.TokenId]coreTypes.Value)
+		for range tokenCount {
+			var id coreTypes.TokenId
+			if err := r.read(id[:]); err != nil {
+				return nil, err
+			}
+			value, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			acc.Tokens[id] = coreTypes.NewValueFromBytes(value)
+		}
+	}
+	if flags[0]&flagAsyncContext != 0 {
+		contextCount, err := r.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		acc.AsyncContext = make(map[coreTypes.TransactionIndex]*coreTypes.AsyncContext)
+		for range contextCount {
+			index, err := r.uvarint()
+			if err != nil {
+				return nil, err
+			}
+			var present [1]byte
+			if err := r.read(present[:]); err != nil {
+				return nil, err
+			}
+			if present[0] == 0 {
+				acc.AsyncContext[coreTypes.TransactionIndex(index)] = nil
+				continue
+			}
+			gas, err := r.uvarint()
+			if err != nil {
+				return nil, err
+			}
+			acc.AsyncContext[coreTypes.TransactionIndex(index)] = &coreTypes.AsyncContext{
+				ResponseProcessingGas: coreTypes.Gas(gas),
+			}
+		}
+	}
+	return acc, nil
+}
@@ -0,0 +1,82 @@
+package v3
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	protoTypes "github.com/NilFoundation/nil/nil/services/synccommittee/internal/types/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+type decompressor interface {
+	Decompress(from io.Reader, to io.Writer) error
+}
+
+type decoder struct {
+	decompressor decompressor
+	logger       logging.Logger
+}
+
+func NewDecoder(logger logging.Logger) *decoder {
+	return &decoder{
+		decompressor: v1.NewZstdDecompressor(logger),
+		logger:       logger,
+	}
+}
+
+// Decode reads the batch from the binary format.
+func (d *decoder) Decode(from io.Reader) (*types.PrunedBatch, error) {
+	protoBatch, err := d.decodeProto(from)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertFromProto(protoBatch)
+}
+
+// DecodeIntermediate decodes data from binary format into human readable protojson form.
+func (d *decoder) DecodeIntermediate(from io.Reader, to io.Writer) error {
+	protoBatch, err := d.decodeProto(from)
+	if err != nil {
+		return err
+	}
+
+	humanReadableForm, err := protojson.MarshalOptions{
+		Multiline: true,
+	}.Marshal(protoBatch)
+	if err != nil {
+		return err
+	}
+
+	n, err := to.Write(humanReadableForm)
+	if err != nil {
+		return err
+	}
+
+	d.logger.Debug().
+		Int("bytes_written", n).
+		Str("batch_id", protoBatch.GetBatchId()).
+		Msg("serialized batch to protojson")
+	return nil
+}
+
+func (d *decoder) decodeProto(from io.Reader) (*protoTypes.ExecutableBatch, error) {
+	if err := encode.CheckBatchVersion(from, version); err != nil {
+		return nil, err
+	}
+
+	var decompressed bytes.Buffer
+	if err := d.decompressor.Decompress(from, &decompressed); err != nil {
+		return nil, err
+	}
+
+	var protoBatch protoTypes.ExecutableBatch
+	if err := proto.Unmarshal(decompressed.Bytes(), &protoBatch); err != nil {
+		return nil, err
+	}
+	return &protoBatch, nil
+}
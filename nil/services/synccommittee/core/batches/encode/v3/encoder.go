@@ -0,0 +1,50 @@
+package v3
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"google.golang.org/protobuf/proto"
+)
+
+const version uint16 = 0x0003
+
+type compressor interface {
+	Compress(from io.Reader, to io.Writer) error
+}
+
+// batchEncoder publishes the transactions of the batch along with the other data required to re-execute them.
+type batchEncoder struct {
+	compressor compressor
+	logger     logging.Logger
+}
+
+var _ encode.BatchEncoder = (*batchEncoder)(nil)
+
+func NewEncoder(logger logging.Logger) *batchEncoder {
+	return &batchEncoder{
+		compressor: v1.NewZstdCompressor(logger),
+		logger:     logger,
+	}
+}
+
+func (be *batchEncoder) Encode(batch *types.PrunedBatch, out io.Writer) error {
+	header := encode.NewBatchHeader(version)
+	if err := header.EncodeTo(out); err != nil {
+		return err
+	}
+
+	protoBatch := ConvertToProto(batch)
+	be.logger.Info().Uint64("transaction_count", protoBatch.GetTotalTxCount()).Msg("packed transactions to batch")
+
+	serialized, err := proto.Marshal(protoBatch)
+	if err != nil {
+		return err
+	}
+
+	return be.compressor.Compress(bytes.NewReader(serialized), out)
+}
@@ -0,0 +1,133 @@
+package v3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	scProto "github.com/NilFoundation/nil/nil/services/synccommittee/internal/types/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+type noopCompressor struct{}
+
+func (nopc *noopCompressor) Compress(in io.Reader, out io.Writer) error {
+	_, err := io.Copy(out, in)
+	return err
+}
+
+func TestEncoderSimple(t *testing.T) {
+	t.Parallel()
+
+	const blockPerShard = 3
+	batch := testaide.NewBlockBatch(blockPerShard)
+	logger := logging.NewLogger("sc_batch_encoder_test")
+	encoder := NewEncoder(logger)
+	encoder.compressor = &noopCompressor{}
+
+	prunedBatch := types.NewPrunedBatch(batch)
+
+	var out bytes.Buffer
+	err := encoder.Encode(prunedBatch, &out)
+	require.NoError(t, err)
+
+	var temp uint16
+
+	require.NoError(t, binary.Read(&out, binary.LittleEndian, &temp))
+	assert.Equal(t, encode.BatchMagic, temp)
+
+	require.NoError(t, binary.Read(&out, binary.LittleEndian, &temp))
+	assert.Equal(t, version, temp)
+
+	var unwrappedBatch scProto.ExecutableBatch
+	err = proto.Unmarshal(out.Bytes(), &unwrappedBatch)
+	require.NoError(t, err)
+
+	deserializedBatch, err := ConvertFromProto(&unwrappedBatch)
+	require.NoError(t, err)
+	require.Len(t, deserializedBatch.Blocks, len(batch.BlockIds()))
+	assert.Equal(t, batch.Id, deserializedBatch.BatchId)
+	assert.ElementsMatch(t, prunedBatch.Blocks, deserializedBatch.Blocks)
+}
+
+func TestDecodeExecutableBlock(t *testing.T) {
+	t.Parallel()
+
+	value := func(v uint64) coreTypes.Value {
+		return coreTypes.NewValueFromUint64(v)
+	}
+	from := coreTypes.ShardAndHexToAddress(1, "0x0a")
+	to := coreTypes.ShardAndHexToAddress(2, "0x0b")
+
+	txn := &coreTypes.Transaction{
+		TransactionDigest: coreTypes.TransactionDigest{
+			Flags:                coreTypes.NewTransactionFlags(coreTypes.TransactionFlagInternal),
+			FeeCredit:            value(1000),
+			MaxPriorityFeePerGas: value(2),
+			MaxFeePerGas:         value(30),
+			To:                   to,
+			ChainId:              7,
+			Seqno:                3,
+			Data:                 coreTypes.Code{0x01, 0x02},
+		},
+		From:     from,
+		TxId:     11,
+		RefundTo: from,
+		Value:    value(5),
+		Token: []coreTypes.TokenBalance{
+			{Token: *coreTypes.TokenIdForAddress(from), Balance: value(9)},
+		},
+		RequestId:    4,
+		RequestChain: []*coreTypes.AsyncRequestInfo{{Id: 1, Caller: to}},
+		Signature:    coreTypes.Signature{0xaa, 0xbb},
+	}
+	forwarded := *txn
+	forwarded.BounceTo = to
+	forwarded.Token = nil
+	forwarded.RequestChain = nil
+
+	batch := &types.PrunedBatch{
+		BatchId: types.NewBatchId(),
+		Blocks: []*types.PrunedBlock{
+			{
+				ShardId:             coreTypes.MainShardId,
+				BlockNumber:         5,
+				Timestamp:           100,
+				PrevBlockHash:       common.HexToHash("0x01"),
+				PatchLevel:          2,
+				RollbackCounter:     1,
+				ChildBlocks:         []common.Hash{common.HexToHash("0x02"), common.HexToHash("0x03")},
+				GasPrices:           []coreTypes.Uint256{*value(10).Uint256, *value(20).Uint256},
+				Transactions:        []types.PrunedTransaction{types.NewPrunedTransaction(txn)},
+				ForwardTransactions: []types.PrunedTransaction{types.NewPrunedTransaction(&forwarded)},
+			},
+			{
+				ShardId:       1,
+				BlockNumber:   8,
+				Timestamp:     101,
+				PrevBlockHash: common.HexToHash("0x04"),
+				MainShardHash: common.HexToHash("0x05"),
+				Transactions:  []types.PrunedTransaction{types.NewPrunedTransaction(&forwarded)},
+			},
+		},
+	}
+
+	logger := logging.NewLogger("sc_batch_encoder_test")
+	var out bytes.Buffer
+	require.NoError(t, NewEncoder(logger).Encode(batch, &out))
+
+	decoded, err := NewDecoder(logger).Decode(&out)
+	require.NoError(t, err)
+	require.Equal(t, batch, decoded)
+	require.Equal(t, txn.Hash(), decoded.Blocks[0].Transactions[0].Transaction().Hash())
+	require.Equal(t, forwarded.Hash(), decoded.Blocks[0].ForwardTransactions[0].Transaction().Hash())
+}
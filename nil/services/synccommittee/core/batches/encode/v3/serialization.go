@@ -0,0 +1,204 @@
+package v3
+
+import (
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types/proto"
+)
+
+func uint256ToProtoUint256(u coreTypes.Uint256) *proto.Uint256 {
+	return &proto.Uint256{
+		WordParts: u[:],
+	}
+}
+
+func protoUint256ToUint256(pb *proto.Uint256) coreTypes.Uint256 {
+	var u coreTypes.Uint256
+	copy(u[:], pb.GetWordParts())
+	return u
+}
+
+// valueToProto keeps the unset values unset, so that they are restored as is.
+func valueToProto(v coreTypes.Value) *proto.Uint256 {
+	if v.Uint256 == nil {
+		return nil
+	}
+	return uint256ToProtoUint256(*v.Uint256)
+}
+
+func protoToValue(pb *proto.Uint256) coreTypes.Value {
+	if pb == nil {
+		return coreTypes.Value{}
+	}
+	u := protoUint256ToUint256(pb)
+	return coreTypes.Value{Uint256: &u}
+}
+
+func addressToProto(addr coreTypes.Address) *proto.Address {
+	return &proto.Address{AddressBytes: addr.Bytes()}
+}
+
+func protoToAddress(pb *proto.Address) coreTypes.Address {
+	return coreTypes.BytesToAddress(pb.GetAddressBytes())
+}
+
+func ConvertToProto(batch *types.PrunedBatch) *proto.ExecutableBatch {
+	var (
+		lastTs       uint64
+		totalTxCount uint64
+		protoBlocks  = make([]*proto.ExecutableBlock, 0, len(batch.Blocks))
+	)
+	for _, l2Blk := range batch.Blocks {
+		b := &proto.ExecutableBlock{
+			ShardId:         uint32(l2Blk.ShardId),
+			BlockNumber:     l2Blk.BlockNumber.Uint64(),
+			Timestamp:       l2Blk.Timestamp,
+			PrevBlockHash:   l2Blk.PrevBlockHash.Bytes(),
+			PatchLevel:      l2Blk.PatchLevel,
+			RollbackCounter: l2Blk.RollbackCounter,
+		}
+		if !l2Blk.MainShardHash.Empty() {
+			b.MainShardHash = l2Blk.MainShardHash.Bytes()
+		}
+		for _, hash := range l2Blk.ChildBlocks {
+			b.ChildBlocks = append(b.ChildBlocks, hash.Bytes())
+		}
+		for _, price := range l2Blk.GasPrices {
+			b.GasPrices = append(b.GasPrices, uint256ToProtoUint256(price))
+		}
+		for i := range l2Blk.Transactions {
+			b.Transactions = append(b.Transactions, transactionToProto(&l2Blk.Transactions[i]))
+		}
+		for i := range l2Blk.ForwardTransactions {
+			b.ForwardTransactions = append(b.ForwardTransactions, transactionToProto(&l2Blk.ForwardTransactions[i]))
+		}
+		lastTs = max(lastTs, b.GetTimestamp())
+		totalTxCount += uint64(len(b.GetTransactions()))
+		protoBlocks = append(protoBlocks, b)
+	}
+
+	return &proto.ExecutableBatch{
+		BatchId:            batch.BatchId.String(),
+		LastBlockTimestamp: lastTs,
+		TotalTxCount:       totalTxCount,
+		Blocks:             protoBlocks,
+	}
+}
+
+func transactionToProto(l2Tx *types.PrunedTransaction) *proto.ExecutableTransaction {
+	tx := &proto.ExecutableTransaction{
+		Flags:                uint32(l2Tx.Flags.Bits),
+		SeqNo:                l2Tx.Seqno.Uint64(),
+		AddrFrom:             addressToProto(l2Tx.From),
+		AddrTo:               addressToProto(l2Tx.To),
+		Value:                uint256ToProtoUint256(*l2Tx.Value.Uint256),
+		Data:                 []byte(l2Tx.Data),
+		FeeCredit:            valueToProto(l2Tx.FeeCredit),
+		MaxPriorityFeePerGas: valueToProto(l2Tx.MaxPriorityFeePerGas),
+		MaxFeePerGas:         valueToProto(l2Tx.MaxFeePerGas),
+		ChainId:              uint64(l2Tx.ChainId),
+		TxId:                 uint64(l2Tx.TxId),
+		RequestId:            l2Tx.RequestId,
+		Signature:            l2Tx.Signature,
+	}
+
+	// the addresses are omitted only if they are equal to the sender, the empty ones are kept:
+	// both are part of the transaction hash
+	if !l2Tx.From.Equal(l2Tx.RefundTo) {
+		tx.AddrRefundTo = addressToProto(l2Tx.RefundTo)
+	}
+	if !l2Tx.From.Equal(l2Tx.BounceTo) {
+		tx.AddrBounceTo = addressToProto(l2Tx.BounceTo)
+	}
+	for _, token := range l2Tx.Token {
+		tx.Tokens = append(tx.Tokens, &proto.TokenBalance{
+			Token:   &proto.Address{AddressBytes: token.Token[:]},
+			Balance: valueToProto(token.Balance),
+		})
+	}
+	for _, request := range l2Tx.RequestChain {
+		tx.RequestChain = append(tx.RequestChain, &proto.AsyncRequestInfo{
+			Id:     request.Id,
+			Caller: addressToProto(request.Caller),
+		})
+	}
+	return tx
+}
+
+func ConvertFromProto(batch *proto.ExecutableBatch) (*types.PrunedBatch, error) {
+	blocks := make([]*types.PrunedBlock, 0, len(batch.GetBlocks()))
+	for _, pblk := range batch.GetBlocks() {
+		b := &types.PrunedBlock{
+			ShardId:         coreTypes.ShardId(pblk.GetShardId()),
+			BlockNumber:     coreTypes.BlockNumber(pblk.GetBlockNumber()),
+			Timestamp:       pblk.GetTimestamp(),
+			PrevBlockHash:   common.BytesToHash(pblk.GetPrevBlockHash()),
+			MainShardHash:   common.BytesToHash(pblk.GetMainShardHash()),
+			PatchLevel:      pblk.GetPatchLevel(),
+			RollbackCounter: pblk.GetRollbackCounter(),
+		}
+		for _, hash := range pblk.GetChildBlocks() {
+			b.ChildBlocks = append(b.ChildBlocks, common.BytesToHash(hash))
+		}
+		for _, price := range pblk.GetGasPrices() {
+			b.GasPrices = append(b.GasPrices, protoUint256ToUint256(price))
+		}
+		for _, ptx := range pblk.GetTransactions() {
+			b.Transactions = append(b.Transactions, transactionFromProto(ptx))
+		}
+		for _, ptx := range pblk.GetForwardTransactions() {
+			b.ForwardTransactions = append(b.ForwardTransactions, transactionFromProto(ptx))
+		}
+		blocks = append(blocks, b)
+	}
+
+	var id types.BatchId
+	if err := id.UnmarshalText([]byte(batch.GetBatchId())); err != nil {
+		return nil, err
+	}
+	return &types.PrunedBatch{BatchId: id, Blocks: blocks}, nil
+}
+
+func transactionFromProto(ptx *proto.ExecutableTransaction) types.PrunedTransaction {
+	tx := types.PrunedTransaction{
+		Flags:                coreTypes.NewTransactionFlagsFromBits(uint8(ptx.GetFlags())),
+		Seqno:                hexutil.Uint64(ptx.GetSeqNo()),
+		From:                 protoToAddress(ptx.GetAddrFrom()),
+		To:                   protoToAddress(ptx.GetAddrTo()),
+		Value:                protoToValue(ptx.GetValue()),
+		Data:                 ptx.GetData(),
+		FeeCredit:            protoToValue(ptx.GetFeeCredit()),
+		MaxPriorityFeePerGas: protoToValue(ptx.GetMaxPriorityFeePerGas()),
+		MaxFeePerGas:         protoToValue(ptx.GetMaxFeePerGas()),
+		ChainId:              coreTypes.ChainId(ptx.GetChainId()),
+		TxId:                 coreTypes.TransactionIndex(ptx.GetTxId()),
+		RequestId:            ptx.GetRequestId(),
+		Signature:            ptx.GetSignature(),
+	}
+	if tx.Value.Uint256 == nil {
+		tx.Value = coreTypes.NewZeroValue()
+	}
+
+	tx.RefundTo, tx.BounceTo = tx.From, tx.From
+	if ptx.GetAddrRefundTo() != nil {
+		tx.RefundTo = protoToAddress(ptx.GetAddrRefundTo())
+	}
+	if ptx.GetAddrBounceTo() != nil {
+		tx.BounceTo = protoToAddress(ptx.GetAddrBounceTo())
+	}
+	for _, token := range ptx.GetTokens() {
+		tx.Token = append(tx.Token, coreTypes.TokenBalance{
+			Token:   coreTypes.TokenId(protoToAddress(token.GetToken())),
+			Balance: protoToValue(token.GetBalance()),
+		})
+	}
+	for _, request := range ptx.GetRequestChain() {
+		tx.RequestChain = append(tx.RequestChain, &coreTypes.AsyncRequestInfo{
+			Id:     request.GetId(),
+			Caller: protoToAddress(request.GetCaller()),
+		})
+	}
+	return tx
+}
@@ -86,13 +86,13 @@ func (s *BlockTasksIntegrationTestSuite) Test_Provide_Tasks_And_Handle_Success_R
 	executorId := testaide.RandomExecutorId()
 
 	// requesting batch proof task for execution
-	taskToExecute, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId))
+	taskToExecute, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId, nil))
 	s.Require().NoError(err)
 	s.Require().NotNil(taskToExecute)
 	s.Require().Equal(types.ProofBatch, taskToExecute.TaskType)
 
 	// no new tasks available yet
-	nonAvailableTask, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId))
+	nonAvailableTask, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId, nil))
 	s.Require().NoError(err)
 	s.Require().Nil(nonAvailableTask)
 
@@ -126,7 +126,7 @@ func (s *BlockTasksIntegrationTestSuite) Test_Provide_Tasks_And_Handle_Failure_R
 	executorId := testaide.RandomExecutorId()
 
 	// requesting batch proof task
-	taskToExecute, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId))
+	taskToExecute, err := s.scheduler.GetTask(s.ctx, api.NewTaskRequest(executorId, nil))
 	s.Require().NoError(err)
 	s.Require().NotNil(taskToExecute)
 	s.Require().Equal(types.ProofBatch, taskToExecute.TaskType)
@@ -2,8 +2,10 @@ package core
 
 import (
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/da"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/fetching"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/rollupcontract"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/scheduler"
 )
 
 const (
@@ -14,8 +16,10 @@ type Config struct {
 	RpcEndpoint             string                       `yaml:"endpoint,omitempty"`
 	TaskListenerRpcEndpoint string                       `yaml:"ownEndpoint,omitempty"`
 	AggregatorConfig        fetching.AggregatorConfig    `yaml:",inline"`
+	DAConfig                da.Config                    `yaml:",inline"`
 	ProposerParams          ProposerConfig               `yaml:"-"`
 	ContractWrapperConfig   rollupcontract.WrapperConfig `yaml:",inline"`
+	TaskArchiverConfig      scheduler.TaskArchiverConfig `yaml:",inline"`
 	Telemetry               *telemetry.Config            `yaml:",inline"`
 }
 
@@ -24,8 +28,10 @@ func NewDefaultConfig() *Config {
 		RpcEndpoint:             "tcp://127.0.0.1:8529",
 		TaskListenerRpcEndpoint: DefaultTaskRpcEndpoint,
 		AggregatorConfig:        fetching.NewDefaultAggregatorConfig(),
+		DAConfig:                da.NewDefaultConfig(),
 		ProposerParams:          NewDefaultProposerConfig(),
 		ContractWrapperConfig:   rollupcontract.NewDefaultWrapperConfig(),
+		TaskArchiverConfig:      scheduler.NewDefaultTaskArchiverConfig(),
 		Telemetry: &telemetry.Config{
 			ServiceName: "sync_committee",
 		},
@@ -11,16 +11,17 @@ import (
 	"github.com/NilFoundation/nil/nil/common/concurrent"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
-	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/blob"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/da"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
 	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
+	v2 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v2"
+	v3 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v3"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/reset"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/rollupcontract"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/srv"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/storage"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
-	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/jonboulle/clockwork"
 )
 
@@ -42,15 +43,25 @@ type AggregatorBlockStorage interface {
 	SetProvedStateRoot(ctx context.Context, stateRoot common.Hash) error
 }
 
+const (
+	// BatchEncodingV1 publishes the transactions of the batch
+	BatchEncodingV1 = "v1"
+	// BatchEncodingV2 publishes the per-shard state diffs of the batch
+	BatchEncodingV2 = "v2"
+	// BatchEncodingV3 publishes the transactions of the batch along with the other data required to re-execute them.
+	// Only the batches published with it can be used to reconstruct the L2 state.
+	BatchEncodingV3 = "v3"
+)
+
 type AggregatorConfig struct {
 	RpcPollingInterval time.Duration `yaml:"pollingDelay,omitempty"`
-	MaxBlobsInTx       uint          `yaml:"-"`
+	BatchEncoding      string        `yaml:"batchEncoding,omitempty"`
 }
 
 func NewAggregatorConfig(rpcPollingInterval time.Duration) AggregatorConfig {
 	return AggregatorConfig{
 		RpcPollingInterval: rpcPollingInterval,
-		MaxBlobsInTx:       6,
+		BatchEncoding:      BatchEncodingV1,
 	}
 }
 
@@ -64,7 +75,9 @@ type aggregator struct {
 	taskStorage     AggregatorTaskStorage
 	subgraphFetcher *subgraphFetcher
 	batchEncoder    encode.BatchEncoder
-	blobBuilder     blob.Builder
+	stateDiffs      *stateDiffCollector
+	blocks          *executableBlocksCollector
+	da              da.DataAvailability
 	rollupContract  rollupcontract.Wrapper
 	resetter        *reset.StateResetLauncher
 	clock           clockwork.Clock
@@ -75,23 +88,23 @@ type aggregator struct {
 }
 
 func NewAggregator(
-	rpcClient RpcBlockFetcher,
+	rpcClient AggregatorRpcClient,
 	blockStorage AggregatorBlockStorage,
 	taskStorage AggregatorTaskStorage,
 	resetter *reset.StateResetLauncher,
 	rollupContractWrapper rollupcontract.Wrapper,
+	dataAvailability da.DataAvailability,
 	clock clockwork.Clock,
 	logger logging.Logger,
 	metrics AggregatorMetrics,
 	config AggregatorConfig,
-) *aggregator {
+) (*aggregator, error) {
 	agg := &aggregator{
 		rpcClient:       rpcClient,
 		blockStorage:    blockStorage,
 		taskStorage:     taskStorage,
 		subgraphFetcher: newSubgraphFetcher(rpcClient, logger),
-		batchEncoder:    v1.NewEncoder(logger),
-		blobBuilder:     blob.NewBuilder(),
+		da:              dataAvailability,
 		rollupContract:  rollupContractWrapper,
 		resetter:        resetter,
 		clock:           clock,
@@ -99,9 +112,22 @@ func NewAggregator(
 		config:          config,
 	}
 
+	switch config.BatchEncoding {
+	case BatchEncodingV1, "":
+		agg.batchEncoder = v1.NewEncoder(logger)
+	case BatchEncodingV2:
+		agg.batchEncoder = v2.NewEncoder(logger)
+		agg.stateDiffs = newStateDiffCollector(rpcClient)
+	case BatchEncodingV3:
+		agg.batchEncoder = v3.NewEncoder(logger)
+		agg.blocks = newExecutableBlocksCollector(rpcClient)
+	default:
+		return nil, fmt.Errorf("unknown batch encoding %q", config.BatchEncoding)
+	}
+
 	agg.workerAction = concurrent.NewSuspendable(agg.runIteration, config.RpcPollingInterval)
 	agg.logger = srv.WorkerLogger(logger, agg)
-	return agg
+	return agg, nil
 }
 
 func (agg *aggregator) Name() string {
@@ -378,17 +404,18 @@ func (agg *aggregator) handleBlockBatch(ctx context.Context, batch *types.BlockB
 		return err
 	}
 
-	sidecar, dataProofs, err := agg.prepareForBatchCommit(ctx, batch)
+	prepared, err := agg.prepareForBatchCommit(ctx, batch)
 	if err != nil {
 		return err
 	}
-	batch.SetDataProofs(dataProofs)
+	batch.SetDataProofs(prepared.DataProofs)
+	batch.SetDACommitment(prepared.Commitment)
 
 	if err := agg.blockStorage.SetBlockBatch(ctx, batch); err != nil {
 		return fmt.Errorf("error storing block batch, latestMainHash=%s: %w", batch.LatestMainBlock().Hash, err)
 	}
 
-	if err := agg.rollupContract.CommitBatch(ctx, sidecar, batch.Id.String()); err != nil {
+	if err := agg.da.Publish(ctx, prepared); err != nil {
 		return agg.handleCommitBatchError(ctx, batch, err)
 	}
 
@@ -449,19 +476,30 @@ func (agg *aggregator) createProofTasks(ctx context.Context, batch *types.BlockB
 
 func (agg *aggregator) prepareForBatchCommit(
 	ctx context.Context, batch *types.BlockBatch,
-) (*ethtypes.BlobTxSidecar, types.DataProofs, error) {
-	var binTransactions bytes.Buffer
-	if err := agg.batchEncoder.Encode(types.NewPrunedBatch(batch), &binTransactions); err != nil {
-		return nil, nil, err
+) (*da.PreparedBatch, error) {
+	prunedBatch := types.NewPrunedBatch(batch)
+	if agg.blocks != nil {
+		blocks, err := agg.blocks.Collect(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		prunedBatch.Blocks = blocks
+	}
+	if agg.stateDiffs != nil {
+		stateDiff, err := agg.stateDiffs.Collect(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		prunedBatch.StateDiff = stateDiff
 	}
-	agg.logger.Debug().Int("compressed_batch_len", binTransactions.Len()).Msg("encoded transaction")
 
-	blobs, err := agg.blobBuilder.MakeBlobs(&binTransactions, agg.config.MaxBlobsInTx)
-	if err != nil {
-		return nil, nil, err
+	var binTransactions bytes.Buffer
+	if err := agg.batchEncoder.Encode(prunedBatch, &binTransactions); err != nil {
+		return nil, err
 	}
+	agg.logger.Debug().Int("compressed_batch_len", binTransactions.Len()).Msg("encoded transaction")
 
-	return agg.rollupContract.PrepareBlobs(ctx, blobs)
+	return agg.da.Prepare(ctx, batch.Id, binTransactions.Bytes())
 }
 
 // getLatestFinalizedRootFromL1 attempts to retrieve the finalized root from the following sources,
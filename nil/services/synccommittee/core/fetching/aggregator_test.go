@@ -7,6 +7,7 @@ import (
 	"github.com/NilFoundation/nil/nil/client"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/da"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/reset"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/rollupcontract"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
@@ -68,22 +69,27 @@ func (s *AggregatorTestSuite) newTestAggregator(
 	}
 	contractWrapper, err := rollupcontract.NewWrapper(s.ctx, contractWrapperConfig, logger)
 	s.Require().NoError(err)
+	dataAvailability, err := da.New(da.NewDefaultConfig(), contractWrapper, logger)
+	s.Require().NoError(err)
 
 	stateResetter := reset.NewStateResetter(logger, s.blockStorage, contractWrapper)
 	// syncCommittee := &core.SyncCommittee{}
 	resetLauncher := reset.NewResetLauncher(stateResetter, nil, logger)
 
-	return NewAggregator(
+	agg, err := NewAggregator(
 		s.rpcClientMock,
 		blockStorage,
 		s.taskStorage,
 		resetLauncher,
 		contractWrapper,
+		dataAvailability,
 		clock,
 		logger,
 		s.metrics,
 		NewDefaultAggregatorConfig(),
 	)
+	s.Require().NoError(err)
+	return agg
 }
 
 func (s *AggregatorTestSuite) newTestBlockStorage(config storage.BlockStorageConfig) *storage.BlockStorage {
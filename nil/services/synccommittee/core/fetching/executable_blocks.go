@@ -0,0 +1,53 @@
+package fetching
+
+import (
+	"context"
+	"fmt"
+
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+type DebugBlockFetcher interface {
+	GetDebugBlock(
+		ctx context.Context, shardId coreTypes.ShardId, blockId any, fullTx bool,
+	) (*jsonrpc.DebugRPCBlock, error)
+}
+
+// executableBlocksCollector fetches the full data of the batch blocks, so that the published batch
+// contains everything the blocks are generated from and can be re-executed from the data alone.
+type executableBlocksCollector struct {
+	rpcClient DebugBlockFetcher
+}
+
+func newExecutableBlocksCollector(rpcClient DebugBlockFetcher) *executableBlocksCollector {
+	return &executableBlocksCollector{rpcClient: rpcClient}
+}
+
+// Collect returns the blocks of the batch in the order of BlockBatch.BlocksIter.
+func (c *executableBlocksCollector) Collect(ctx context.Context, batch *types.BlockBatch) ([]*types.PrunedBlock, error) {
+	var blocks []*types.PrunedBlock
+	for block := range batch.BlocksIter() {
+		debugBlock, err := c.rpcClient.GetDebugBlock(ctx, block.ShardId, block.Hash, true)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to fetch block data, shardId=%d, hash=%s: %w", block.ShardId, block.Hash, err)
+		}
+		if debugBlock == nil {
+			return nil, fmt.Errorf("block data is not found, shardId=%d, hash=%s", block.ShardId, block.Hash)
+		}
+		data, err := debugBlock.DecodeSSZ()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to decode block data, shardId=%d, hash=%s: %w", block.ShardId, block.Hash, err)
+		}
+
+		pruned, err := types.NewExecutablePrunedBlock(block, data)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, pruned)
+	}
+	return blocks, nil
+}
@@ -0,0 +1,65 @@
+package fetching
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+type StateDiffFetcher interface {
+	GetStateDiff(
+		ctx context.Context,
+		shardId coreTypes.ShardId,
+		from common.Hash,
+		to common.Hash,
+	) ([]*jsonrpc.DebugRPCContractDiff, error)
+}
+
+type AggregatorRpcClient interface {
+	RpcBlockFetcher
+	DebugBlockFetcher
+	StateDiffFetcher
+}
+
+// stateDiffCollector builds the state diff of a batch from the states of the shards before and after the batch.
+type stateDiffCollector struct {
+	rpcClient StateDiffFetcher
+}
+
+func newStateDiffCollector(rpcClient StateDiffFetcher) *stateDiffCollector {
+	return &stateDiffCollector{rpcClient: rpcClient}
+}
+
+// Collect requests the difference between the state of the parent of the first batch block of each shard
+// and the state of the last one, so that all the changes made by the batch are included:
+// balances, seqnos, code, storage, tokens and async contexts.
+func (c *stateDiffCollector) Collect(ctx context.Context, batch *types.BlockBatch) (types.StateDiff, error) {
+	parentRefs := batch.ParentRefs()
+	latestRefs := batch.LatestRefs()
+
+	stateDiff := types.NewStateDiff()
+	for _, shardId := range slices.Sorted(maps.Keys(latestRefs)) {
+		latest := latestRefs[shardId]
+		// the batch starting from the genesis block is compared with the empty state
+		from := common.EmptyHash
+		if parent := parentRefs[shardId]; parent != nil {
+			from = parent.Hash
+		}
+
+		diff, err := c.rpcClient.GetStateDiff(ctx, shardId, from, latest.Hash)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to collect state diff, shardId=%d, from=%s, to=%s: %w", shardId, from, latest.Hash, err)
+		}
+		for _, contract := range diff {
+			stateDiff.Apply(contract.ContractDiff)
+		}
+	}
+	return stateDiff, nil
+}
@@ -0,0 +1,125 @@
+package fetching
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/client"
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
+	scTypes "github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateDiffCollector(t *testing.T) {
+	t.Parallel()
+
+	accA := types.ShardAndHexToAddress(1, "0x0a")
+	accB := types.ShardAndHexToAddress(1, "0x0b")
+	accC := types.ShardAndHexToAddress(2, "0x0c")
+	slot := common.HexToHash("0x01")
+	token := *types.TokenIdForAddress(accC)
+	value := func(v uint64) *types.Value {
+		res := types.NewValueFromUint64(v)
+		return &res
+	}
+	seqno := func(v types.Seqno) *types.Seqno {
+		return &v
+	}
+
+	batch := testaide.NewBlockBatch(2)
+	shardBlock := batch.Subgraphs[0].Children[1][0]
+	shardBlock.Number = 5
+	// the batch starting from the genesis block is compared with the empty state
+	genesisBlock := batch.Subgraphs[0].Children[2][0]
+	genesisBlock.Number = 0
+	genesisBlock.ParentHash = common.EmptyHash
+
+	diffs := map[types.ShardId][]*execution.ContractDiff{
+		1: {
+			{
+				Address: accA,
+				Balance: value(5),
+				Seqno:   seqno(2),
+				Storage: map[common.Hash]common.Hash{slot: {0x2a}},
+				Tokens:  map[types.TokenId]types.Value{token: types.NewValueFromUint64(3)},
+				AsyncContext: map[types.TransactionIndex]*types.AsyncContext{
+					1: nil,
+					2: {ResponseProcessingGas: 100},
+				},
+			},
+			{Address: accB, Deleted: true},
+		},
+		2: {
+			{
+				Address:  accC,
+				Balance:  value(7),
+				Seqno:    seqno(0),
+				ExtSeqno: seqno(0),
+				Code:     types.Code{0x60, 0x01},
+			},
+		},
+	}
+
+	var requested []types.ShardId
+	rpcClient := &client.ClientMock{
+		GetStateDiffFunc: func(
+			_ context.Context, shardId types.ShardId, from common.Hash, to common.Hash,
+		) ([]*jsonrpc.DebugRPCContractDiff, error) {
+			requested = append(requested, shardId)
+			switch shardId {
+			case types.MainShardId:
+				main := batch.Subgraphs[0].Main
+				require.Equal(t, main.ParentHash, from)
+				require.Equal(t, main.Hash, to)
+			case 1:
+				require.Equal(t, shardBlock.ParentHash, from)
+				require.Equal(t, shardBlock.Hash, to)
+			case 2:
+				require.Equal(t, common.EmptyHash, from)
+				require.Equal(t, genesisBlock.Hash, to)
+			}
+
+			res := make([]*jsonrpc.DebugRPCContractDiff, 0, len(diffs[shardId]))
+			for _, diff := range diffs[shardId] {
+				res = append(res, &jsonrpc.DebugRPCContractDiff{ContractDiff: diff})
+			}
+			return res, nil
+		},
+	}
+
+	stateDiff, err := newStateDiffCollector(rpcClient).Collect(t.Context(), batch)
+	require.NoError(t, err)
+	require.Equal(t, []types.ShardId{types.MainShardId, 1, 2}, requested)
+
+	seqno2 := hexutil.Uint64(2)
+	zero := hexutil.Uint64(0)
+	expected := scTypes.StateDiff{
+		1: {
+			accA: {
+				Balance: value(5),
+				Seqno:   &seqno2,
+				Storage: map[common.Hash]common.Hash{slot: {0x2a}},
+				Tokens:  map[types.TokenId]types.Value{token: types.NewValueFromUint64(3)},
+				AsyncContext: map[types.TransactionIndex]*types.AsyncContext{
+					1: nil,
+					2: {ResponseProcessingGas: 100},
+				},
+			},
+			accB: {Deleted: true},
+		},
+		2: {
+			accC: {
+				Balance:  value(7),
+				Seqno:    &zero,
+				ExtSeqno: &zero,
+				Code:     hexutil.Bytes{0x60, 0x01},
+			},
+		},
+	}
+	require.Equal(t, expected, stateDiff)
+}
@@ -0,0 +1,137 @@
+package reconstruct
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+// beaconClient fetches the blobs from the consensus layer API.
+// The nodes keep the blobs for about 18 days only, older batches require an archive node.
+type beaconClient struct {
+	endpoint string
+	client   *http.Client
+
+	genesisTime    uint64
+	secondsPerSlot uint64
+
+	verifyProof func(blob *kzg4844.Blob, commitment kzg4844.Commitment, proof kzg4844.Proof) error
+}
+
+func newBeaconClient(endpoint string) *beaconClient {
+	return &beaconClient{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   http.DefaultClient,
+
+		verifyProof: kzg4844.VerifyBlobProof,
+	}
+}
+
+type blobSidecar struct {
+	Blob          kzg4844.Blob       `json:"blob"`
+	KZGCommitment kzg4844.Commitment `json:"kzg_commitment"`
+	KZGProof      kzg4844.Proof      `json:"kzg_proof"`
+}
+
+// Blobs returns the blobs with the given versioned hashes included into the L1 block with the given timestamp.
+func (c *beaconClient) Blobs(
+	ctx context.Context, blockTime uint64, hashes []ethcommon.Hash,
+) ([]kzg4844.Blob, error) {
+	slot, err := c.slot(ctx, blockTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var sidecars []*blobSidecar
+	if err := c.get(ctx, "/eth/v1/beacon/blob_sidecars/"+strconv.FormatUint(slot, 10), &sidecars); err != nil {
+		return nil, fmt.Errorf("failed to get blob sidecars of slot %d: %w", slot, err)
+	}
+
+	byHash := make(map[ethcommon.Hash]*blobSidecar, len(sidecars))
+	for _, sidecar := range sidecars {
+		byHash[kzg4844.CalcBlobHashV1(sha256.New(), &sidecar.KZGCommitment)] = sidecar
+	}
+
+	blobs := make([]kzg4844.Blob, 0, len(hashes))
+	for _, hash := range hashes {
+		sidecar, ok := byHash[hash]
+		if !ok {
+			return nil, fmt.Errorf("blob %s is not found in slot %d", hash, slot)
+		}
+		if err := c.verifyProof(&sidecar.Blob, sidecar.KZGCommitment, sidecar.KZGProof); err != nil {
+			return nil, fmt.Errorf("invalid proof of blob %s: %w", hash, err)
+		}
+		blobs = append(blobs, sidecar.Blob)
+	}
+	return blobs, nil
+}
+
+func (c *beaconClient) slot(ctx context.Context, blockTime uint64) (uint64, error) {
+	if c.secondsPerSlot == 0 {
+		var genesis struct {
+			GenesisTime string `json:"genesis_time"`
+		}
+		if err := c.get(ctx, "/eth/v1/beacon/genesis", &genesis); err != nil {
+			return 0, fmt.Errorf("failed to get beacon genesis: %w", err)
+		}
+		var spec struct {
+			SecondsPerSlot string `json:"SECONDS_PER_SLOT"`
+		}
+		if err := c.get(ctx, "/eth/v1/config/spec", &spec); err != nil {
+			return 0, fmt.Errorf("failed to get beacon spec: %w", err)
+		}
+
+		genesisTime, err := strconv.ParseUint(genesis.GenesisTime, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid genesis time: %w", err)
+		}
+		secondsPerSlot, err := strconv.ParseUint(spec.SecondsPerSlot, 10, 64)
+		if err != nil || secondsPerSlot == 0 {
+			return 0, fmt.Errorf("invalid seconds per slot %q", spec.SecondsPerSlot)
+		}
+		c.genesisTime, c.secondsPerSlot = genesisTime, secondsPerSlot
+	}
+
+	if blockTime < c.genesisTime {
+		return 0, fmt.Errorf("block time %d is before the beacon genesis %d", blockTime, c.genesisTime)
+	}
+	return (blockTime - c.genesisTime) / c.secondsPerSlot, nil
+}
+
+// get fetches the `data` field of the API response.
+func (c *beaconClient) get(ctx context.Context, path string, data any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+	if len(body.Data) == 0 {
+		return errors.New("response has no data")
+	}
+	return json.Unmarshal(body.Data, data)
+}
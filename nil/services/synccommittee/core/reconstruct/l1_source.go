@@ -0,0 +1,266 @@
+package reconstruct
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/blob"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/rollupcontract"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+// maxBlobPadding is the number of the trailing zero bytes of the blobs data which may belong to the batch.
+// The blobs are padded with zeros and the length of the data isn't published, but the encoded batch
+// ends with the zstd checksum, so only a few of the trailing zeros are a part of it.
+const maxBlobPadding = 32
+
+// L1Client is the part of the L1 API the batches are loaded with, it is implemented by ethclient.Client.
+type L1Client interface {
+	bind.ContractBackend
+	TransactionByHash(ctx context.Context, hash ethcommon.Hash) (tx *ethtypes.Transaction, isPending bool, err error)
+}
+
+type L1SourceConfig struct {
+	ContractAddress ethcommon.Address
+	// FromBlock is the L1 block the committed batches are searched from
+	FromBlock uint64
+	// BeaconEndpoint is the consensus layer API the blobs are fetched from,
+	// the batches published as blobs can't be loaded without it
+	BeaconEndpoint string
+	// LocalDir is the directory of the local data availability backend,
+	// the batches committed with the data hash alone on devnets are loaded from it
+	LocalDir string
+}
+
+func NewDefaultL1SourceConfig() L1SourceConfig {
+	return L1SourceConfig{}
+}
+
+// L1Source loads the batches committed to the rollup contract from the data availability backends
+// they are published with: the blobs or the calldata of the commit transaction, or the local directory.
+// The data is checked against the commitment stored by the contract.
+type L1Source struct {
+	client   L1Client
+	contract *rollupcontract.Rollupcontract
+	abi      *abi.ABI
+	beacon   *beaconClient
+	config   L1SourceConfig
+	logger   logging.Logger
+}
+
+var (
+	_ BatchSource     = (*L1Source)(nil)
+	_ StateRootReader = (*L1Source)(nil)
+)
+
+func NewL1Source(client L1Client, config L1SourceConfig, logger logging.Logger) (*L1Source, error) {
+	contract, err := rollupcontract.NewRollupcontract(config.ContractAddress, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind rollup contract: %w", err)
+	}
+	contractAbi, err := rollupcontract.RollupcontractMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	source := &L1Source{
+		client:   client,
+		contract: contract,
+		abi:      contractAbi,
+		config:   config,
+		logger:   logger,
+	}
+	if config.BeaconEndpoint != "" {
+		source.beacon = newBeaconClient(config.BeaconEndpoint)
+	}
+	return source, nil
+}
+
+// Batches loads the committed batches in the order they were committed in.
+func (s *L1Source) Batches(ctx context.Context) ([]*types.PrunedBatch, error) {
+	iter, err := s.contract.FilterBatchCommitted(&bind.FilterOpts{Start: s.config.FromBlock, Context: ctx}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter committed batches: %w", err)
+	}
+	defer iter.Close()
+
+	var batches []*types.PrunedBatch
+	for iter.Next() {
+		batch, err := s.loadBatch(ctx, iter.Event.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load batch committed in tx %s: %w", iter.Event.Raw.TxHash, err)
+		}
+		batches = append(batches, batch)
+
+		s.logger.Info().
+			Stringer(logging.FieldBatchId, batch.BatchId).
+			Uint64("l1Block", iter.Event.Raw.BlockNumber).
+			Msg("batch is loaded from L1")
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to filter committed batches: %w", err)
+	}
+	return batches, nil
+}
+
+func (s *L1Source) loadBatch(ctx context.Context, log ethtypes.Log) (*types.PrunedBatch, error) {
+	tx, _, err := s.client.TransactionByHash(ctx, log.TxHash)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := s.decodeCommit(tx.Data())
+	if err != nil {
+		return nil, err
+	}
+	batchId := commit.batchId
+
+	info, err := s.contract.BatchInfoRecords(&bind.CallOpts{Context: ctx}, batchId.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch %s info: %w", batchId, err)
+	}
+
+	var batch *types.PrunedBatch
+	switch {
+	case info.BlobCount.Sign() > 0:
+		batch, err = s.loadBlobs(ctx, log.BlockNumber, tx.BlobHashes())
+	case commit.data != nil:
+		batch, err = s.loadCalldata(commit.data, common.Hash(info.DataHash))
+	default:
+		batch, err = s.loadLocal(batchId, common.Hash(info.DataHash))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if batch.BatchId != batchId {
+		return nil, fmt.Errorf("data of batch %s contains batch %s", batchId, batch.BatchId)
+	}
+	return batch, nil
+}
+
+// commitCall is the decoded input of the transaction committing the batch.
+type commitCall struct {
+	batchId types.BatchId
+	// data is the batch data passed to `commitBatchCalldata`, nil for the other methods
+	data []byte
+}
+
+// decodeCommit decodes the input of the `commitBatch`, `commitBatchCalldata` or `commitBatchDataHash` call.
+func (s *L1Source) decodeCommit(input []byte) (*commitCall, error) {
+	if len(input) < 4 {
+		return nil, errors.New("commit transaction has no input")
+	}
+	method, err := s.abi.MethodById(input[:4])
+	if err != nil {
+		return nil, err
+	}
+	if method.Name != "commitBatch" && method.Name != "commitBatchCalldata" && method.Name != "commitBatchDataHash" {
+		return nil, fmt.Errorf("unexpected commit method %s", method.Name)
+	}
+	args, err := method.Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, err
+	}
+	batchIndex, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected batch index type %T", args[0])
+	}
+
+	call := &commitCall{}
+	if err := call.batchId.UnmarshalText([]byte(batchIndex)); err != nil {
+		return nil, fmt.Errorf("invalid batch index %q: %w", batchIndex, err)
+	}
+	if method.Name == "commitBatchCalldata" {
+		if call.data, ok = args[1].([]byte); !ok {
+			return nil, fmt.Errorf("unexpected batch data type %T", args[1])
+		}
+	}
+	return call, nil
+}
+
+func (s *L1Source) loadBlobs(
+	ctx context.Context, blockNumber uint64, hashes []ethcommon.Hash,
+) (*types.PrunedBatch, error) {
+	if s.beacon == nil {
+		return nil, errors.New("beacon endpoint is required to load the batches published as blobs")
+	}
+	header, err := s.client.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	blobs, err := s.beacon.Blobs(ctx, header.Time, hashes)
+	if err != nil {
+		return nil, err
+	}
+	// every 32-byte word of the blob carries 254 bits of the data
+	data := make([]byte, len(blobs)*len(kzg4844.Blob{})/32*254/8)
+	n, err := blob.NewReader(blobs).Read(data)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBlobsData(data[:n], s.logger)
+}
+
+// decodeBlobsData decodes the batch from the data padded with zeros, the blobs are already verified
+// against the versioned hashes, so the first length the batch is decoded with is the right one.
+func decodeBlobsData(data []byte, logger logging.Logger) (*types.PrunedBatch, error) {
+	trimmed := len(bytes.TrimRight(data, "\x00"))
+	var lastErr error
+	for length := trimmed; length <= min(len(data), trimmed+maxBlobPadding); length++ {
+		batch, err := decodeBatch(data[:length], logger)
+		if err == nil || errors.Is(err, ErrUnsupportedEncoding) {
+			return batch, err
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("failed to decode blobs data: %w", lastErr)
+}
+
+// loadCalldata decodes the batch published as calldata of the commit transaction,
+// the contract computes the hash of the data, so the check guards against a misbehaving L1 endpoint only.
+func (s *L1Source) loadCalldata(data []byte, dataHash common.Hash) (*types.PrunedBatch, error) {
+	if common.KeccakHash(data) != dataHash {
+		return nil, errors.New("batch calldata doesn't match the committed hash")
+	}
+	return decodeBatch(data, s.logger)
+}
+
+// loadLocal loads the batch committed with the data hash alone, its data is kept by the local backend.
+func (s *L1Source) loadLocal(batchId types.BatchId, dataHash common.Hash) (*types.PrunedBatch, error) {
+	if s.config.LocalDir == "" {
+		return nil, fmt.Errorf("batch %s is committed without data, the local batches directory is required", batchId)
+	}
+	data, err := os.ReadFile(filepath.Join(s.config.LocalDir, batchId.String()+".bin"))
+	if err != nil {
+		return nil, err
+	}
+	if common.KeccakHash(data) != dataHash {
+		return nil, fmt.Errorf("local data of batch %s doesn't match the committed hash", batchId)
+	}
+	return decodeBatch(data, s.logger)
+}
+
+func (s *L1Source) FinalizedStateRoot(ctx context.Context, batchId types.BatchId) (common.Hash, error) {
+	return s.contract.FinalizedStateRoots(&bind.CallOpts{Context: ctx}, batchId.String())
+}
+
+func (s *L1Source) LatestFinalizedStateRoot(ctx context.Context) (common.Hash, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	batchIndex, err := s.contract.GetLastFinalizedBatchIndex(opts)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	return s.contract.FinalizedStateRoots(opts, batchIndex)
+}
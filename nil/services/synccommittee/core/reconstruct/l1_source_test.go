@@ -0,0 +1,282 @@
+package reconstruct
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/blob"
+	v3 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v3"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/rollupcontract"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testGenesisTime    = 1000
+	testSecondsPerSlot = 12
+)
+
+type batchRecord struct {
+	blobCount int64
+	dataHash  common.Hash
+	root      common.Hash
+}
+
+// l1ClientStub serves the rollup contract calls and the L1 data the batches are published with.
+type l1ClientStub struct {
+	L1Client
+
+	abi           *abi.ABI
+	logs          []ethtypes.Log
+	txs           map[ethcommon.Hash]*ethtypes.Transaction
+	records       map[string]*batchRecord
+	lastFinalized string
+}
+
+func newL1ClientStub(t *testing.T) *l1ClientStub {
+	t.Helper()
+
+	contractAbi, err := rollupcontract.RollupcontractMetaData.GetAbi()
+	require.NoError(t, err)
+	return &l1ClientStub{
+		abi:     contractAbi,
+		txs:     make(map[ethcommon.Hash]*ethtypes.Transaction),
+		records: make(map[string]*batchRecord),
+	}
+}
+
+// commit records the commit transaction of the batch included into the L1 block.
+func (c *l1ClientStub) commit(batchId types.BatchId, blockNumber uint64, tx ethtypes.TxData, record *batchRecord) {
+	txHash := ethcommon.BigToHash(big.NewInt(int64(len(c.logs) + 1)))
+	c.txs[txHash] = ethtypes.NewTx(tx)
+	c.logs = append(c.logs, ethtypes.Log{
+		Topics:      []ethcommon.Hash{c.abi.Events["BatchCommitted"].ID, {}},
+		BlockNumber: blockNumber,
+		TxHash:      txHash,
+	})
+	c.records[batchId.String()] = record
+}
+
+func (c *l1ClientStub) FilterLogs(context.Context, ethereum.FilterQuery) ([]ethtypes.Log, error) {
+	return c.logs, nil
+}
+
+func (c *l1ClientStub) TransactionByHash(_ context.Context, hash ethcommon.Hash) (*ethtypes.Transaction, bool, error) {
+	tx, ok := c.txs[hash]
+	if !ok {
+		return nil, false, ethereum.NotFound
+	}
+	return tx, false, nil
+}
+
+func (c *l1ClientStub) HeaderByNumber(_ context.Context, number *big.Int) (*ethtypes.Header, error) {
+	return &ethtypes.Header{Number: number, Time: testGenesisTime + number.Uint64()*testSecondsPerSlot}, nil
+}
+
+func (c *l1ClientStub) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method, err := c.abi.MethodById(msg.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.Unpack(msg.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	switch method.Name {
+	case "getLastFinalizedBatchIndex":
+		return method.Outputs.Pack(c.lastFinalized)
+	case "finalizedStateRoots":
+		var root common.Hash
+		if record, ok := c.records[args[0].(string)]; ok {
+			root = record.root
+		}
+		return method.Outputs.Pack(root)
+	case "batchInfoRecords":
+		record, ok := c.records[args[0].(string)]
+		if !ok {
+			return nil, fmt.Errorf("unknown batch %s", args[0])
+		}
+		return method.Outputs.Pack(
+			args[0], true, !record.root.Empty(), common.EmptyHash, record.root, []byte{},
+			rollupcontract.INilRollupPublicDataInfo{MessageCount: big.NewInt(0)},
+			big.NewInt(record.blobCount), record.dataHash,
+		)
+	}
+	return nil, fmt.Errorf("unexpected call of %s", method.Name)
+}
+
+// newBeaconStub serves the blob sidecars of the given slot.
+func newBeaconStub(t *testing.T, slot uint64, sidecars []*blobSidecar) *httptest.Server {
+	t.Helper()
+
+	responses := map[string]any{
+		"/eth/v1/beacon/genesis":                             map[string]string{"genesis_time": fmt.Sprint(testGenesisTime)},
+		"/eth/v1/config/spec":                                map[string]string{"SECONDS_PER_SLOT": fmt.Sprint(testSecondsPerSlot)},
+		fmt.Sprintf("/eth/v1/beacon/blob_sidecars/%d", slot): sidecars,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"data": data}))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func blobHashes(sidecars []*blobSidecar) []ethcommon.Hash {
+	hashes := make([]ethcommon.Hash, len(sidecars))
+	for i, sidecar := range sidecars {
+		hashes[i] = kzg4844.CalcBlobHashV1(sha256.New(), &sidecar.KZGCommitment)
+	}
+	return hashes
+}
+
+// fakeCommitment stands for the KZG commitment in the tests publishing the compressed batches:
+// the blob builder doesn't keep the field elements canonical, so such blobs can't be committed to.
+func fakeCommitment(blob *kzg4844.Blob) kzg4844.Commitment {
+	var commitment kzg4844.Commitment
+	copy(commitment[:], crypto.Keccak256(blob[:]))
+	return commitment
+}
+
+func verifyFakeCommitment(blob *kzg4844.Blob, commitment kzg4844.Commitment, _ kzg4844.Proof) error {
+	if fakeCommitment(blob) != commitment {
+		return errors.New("commitment mismatch")
+	}
+	return nil
+}
+
+func TestBeaconClient(t *testing.T) {
+	t.Parallel()
+
+	blobs, err := blob.NewBuilder().MakeBlobs(bytes.NewReader([]byte("hello, world")), 1)
+	require.NoError(t, err)
+	commitment, err := kzg4844.BlobToCommitment(&blobs[0])
+	require.NoError(t, err)
+	proof, err := kzg4844.ComputeBlobProof(&blobs[0], commitment)
+	require.NoError(t, err)
+
+	// the blobs of L1 block 5 are in slot 5
+	valid := []*blobSidecar{{Blob: blobs[0], KZGCommitment: commitment, KZGProof: proof}}
+	beacon := newBeaconClient(newBeaconStub(t, 5, valid).URL)
+	loaded, err := beacon.Blobs(t.Context(), testGenesisTime+5*testSecondsPerSlot, blobHashes(valid))
+	require.NoError(t, err)
+	require.Equal(t, blobs, loaded)
+
+	_, err = beacon.Blobs(t.Context(), testGenesisTime+5*testSecondsPerSlot, []ethcommon.Hash{{}})
+	require.ErrorContains(t, err, "is not found")
+
+	corrupted := []*blobSidecar{{Blob: blobs[0], KZGCommitment: commitment, KZGProof: proof}}
+	corrupted[0].Blob[0] ^= 1
+	beacon = newBeaconClient(newBeaconStub(t, 5, corrupted).URL)
+	_, err = beacon.Blobs(t.Context(), testGenesisTime+5*testSecondsPerSlot, blobHashes(corrupted))
+	require.ErrorContains(t, err, "invalid proof")
+}
+
+func encodeTestBatch(t *testing.T, batch *types.PrunedBatch) []byte {
+	t.Helper()
+
+	var encoded bytes.Buffer
+	require.NoError(t, v3.NewEncoder(logging.NewLogger("reconstruct_test")).Encode(batch, &encoded))
+	return encoded.Bytes()
+}
+
+func TestL1Source(t *testing.T) {
+	t.Parallel()
+
+	logger := logging.NewLogger("reconstruct_test")
+	n, batches := newTestBatches(t)
+	client := newL1ClientStub(t)
+
+	// the first batch is published as blobs in L1 block 5
+	blobs, err := blob.NewBuilder().MakeBlobs(bytes.NewReader(encodeTestBatch(t, batches[0])), 6)
+	require.NoError(t, err)
+	sidecars := make([]*blobSidecar, len(blobs))
+	for i := range blobs {
+		sidecars[i] = &blobSidecar{Blob: blobs[i], KZGCommitment: fakeCommitment(&blobs[i])}
+	}
+	beacon := newBeaconStub(t, 5, sidecars)
+	commitBatch, err := client.abi.Pack("commitBatch", batches[0].BatchId.String(), big.NewInt(int64(len(blobs))))
+	require.NoError(t, err)
+	client.commit(batches[0].BatchId, 5, &ethtypes.BlobTx{Data: commitBatch, BlobHashes: blobHashes(sidecars)}, &batchRecord{
+		blobCount: int64(len(blobs)),
+		root:      n.blocks[coreTypes.MainShardId][2].Hash(coreTypes.MainShardId),
+	})
+	client.lastFinalized = batches[0].BatchId.String()
+
+	// the second one is published as calldata of the commit transaction in L1 block 10
+	data := encodeTestBatch(t, batches[1])
+	commitCalldata, err := client.abi.Pack("commitBatchCalldata", batches[1].BatchId.String(), data)
+	require.NoError(t, err)
+	client.commit(batches[1].BatchId, 10, &ethtypes.LegacyTx{Data: commitCalldata}, &batchRecord{dataHash: common.KeccakHash(data)})
+
+	config := NewDefaultL1SourceConfig()
+	config.BeaconEndpoint = beacon.URL
+	source, err := NewL1Source(client, config, logger)
+	require.NoError(t, err)
+	source.beacon.verifyProof = verifyFakeCommitment
+
+	loaded, err := source.Batches(t.Context())
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	require.Equal(t, batches[0].BatchId, loaded[0].BatchId)
+	require.Equal(t, batches[1].BatchId, loaded[1].BatchId)
+
+	r, err := New(testShards, newTestZeroState(), source, logger)
+	require.NoError(t, err)
+	report, err := r.Run(t.Context(), loaded)
+	require.NoError(t, err)
+	require.Nil(t, report.Divergence)
+	require.Equal(t, 2, report.BatchesApplied)
+	require.Equal(t, &batches[0].BatchId, report.L1FinalizedBatch)
+
+	// the calldata doesn't match the hash stored by the contract
+	client.records[batches[1].BatchId.String()].dataHash = common.KeccakHash([]byte("other data"))
+	_, err = source.Batches(t.Context())
+	require.ErrorContains(t, err, "doesn't match the committed hash")
+	client.records[batches[1].BatchId.String()].dataHash = common.KeccakHash(data)
+
+	// on devnets the batch is committed with the data hash alone and its data is kept by the local backend
+	devnet := newL1ClientStub(t)
+	commitDataHash, err := devnet.abi.Pack(
+		"commitBatchDataHash", batches[1].BatchId.String(), [32]byte(common.KeccakHash(data)))
+	require.NoError(t, err)
+	devnet.commit(batches[1].BatchId, 10, &ethtypes.LegacyTx{Data: commitDataHash}, &batchRecord{dataHash: common.KeccakHash(data)})
+
+	source, err = NewL1Source(devnet, NewDefaultL1SourceConfig(), logger)
+	require.NoError(t, err)
+	_, err = source.Batches(t.Context())
+	require.ErrorContains(t, err, "local batches directory is required")
+
+	config = NewDefaultL1SourceConfig()
+	config.LocalDir = t.TempDir()
+	writeBatch(t, config.LocalDir, v3.NewEncoder(logger), batches[1])
+	source, err = NewL1Source(devnet, config, logger)
+	require.NoError(t, err)
+	loaded, err = source.Batches(t.Context())
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	require.Equal(t, batches[1].BatchId, loaded[0].BatchId)
+}
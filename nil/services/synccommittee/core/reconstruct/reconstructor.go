@@ -0,0 +1,328 @@
+package reconstruct
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+// StateRootReader provides the state roots finalized on L1, it is implemented by L1Source.
+type StateRootReader interface {
+	// FinalizedStateRoot returns the state root the batch is finalized with, empty if it isn't finalized yet
+	FinalizedStateRoot(ctx context.Context, batchId types.BatchId) (common.Hash, error)
+	LatestFinalizedStateRoot(ctx context.Context) (common.Hash, error)
+}
+
+// Divergence describes the first point where the reconstructed state doesn't match the recorded one.
+type Divergence struct {
+	// BatchId is empty if the divergence is found in the zero state
+	BatchId     types.BatchId         `json:"batchId"`
+	ShardId     coreTypes.ShardId     `json:"shardId"`
+	BlockNumber coreTypes.BlockNumber `json:"blockNumber"`
+	Expected    common.Hash           `json:"expected"`
+	Actual      common.Hash           `json:"actual"`
+	Reason      string                `json:"reason"`
+}
+
+func (d *Divergence) String() string {
+	return fmt.Sprintf(
+		"batch %s, shard %d, block %d: %s (expected %s, actual %s)",
+		d.BatchId, d.ShardId, d.BlockNumber, d.Reason, d.Expected, d.Actual,
+	)
+}
+
+// Report is the result of the reconstruction.
+type Report struct {
+	// BatchesApplied is the number of batches whose blocks match the recorded ones
+	BatchesApplied int `json:"batchesApplied"`
+	// Heads are the last reconstructed blocks of the shards
+	Heads map[coreTypes.ShardId]coreTypes.BlockNumber `json:"heads"`
+	// L1FinalizedRoot is the latest state root finalized on L1, empty if L1 is not checked
+	L1FinalizedRoot common.Hash `json:"l1FinalizedRoot"`
+	// L1FinalizedBatch is the batch producing L1FinalizedRoot, nil if the root is the zero state one
+	L1FinalizedBatch *types.BatchId `json:"l1FinalizedBatch,omitempty"`
+	Divergence       *Divergence    `json:"divergence,omitempty"`
+}
+
+type shardHead struct {
+	// block is the last reconstructed block of the shard
+	block *coreTypes.Block
+	hash  common.Hash
+	// batchId is the batch the block is reconstructed from, empty for the zero state
+	batchId types.BatchId
+}
+
+// Reconstructor rebuilds the L2 state from the published batches on an empty database seeded with the zero state.
+// The blocks are re-executed from the published transactions the same way the validators replay them,
+// and the hashes of the rebuilt blocks are compared with the recorded ones: each block refers to the hash
+// of its predecessor, the main shard blocks refer to the child shard blocks, and the hash of the last main shard
+// block of a batch is the state root the batch is finalized with on L1.
+// The blocks of the last batch which isn't finalized on L1 yet are checked only by the links within the batch.
+type Reconstructor struct {
+	database  db.DB
+	nShards   uint32
+	zeroState *execution.ZeroStateConfig
+	l1        StateRootReader
+	heads     map[coreTypes.ShardId]*shardHead
+	// rebuilt are the hashes of all the reconstructed blocks, the main shard blocks may refer only to them
+	rebuilt map[common.Hash]struct{}
+	logger  logging.Logger
+}
+
+// New creates the reconstructor, l1 is optional.
+func New(
+	nShards uint32,
+	zeroState *execution.ZeroStateConfig,
+	l1 StateRootReader,
+	logger logging.Logger,
+) (*Reconstructor, error) {
+	if nShards == 0 {
+		return nil, errors.New("number of shards must be positive")
+	}
+	return &Reconstructor{
+		nShards:   nShards,
+		zeroState: zeroState,
+		l1:        l1,
+		heads:     make(map[coreTypes.ShardId]*shardHead),
+		rebuilt:   make(map[common.Hash]struct{}),
+		logger:    logger,
+	}, nil
+}
+
+// Run rebuilds the state from scratch applying the batches in order and stops at the first divergence.
+// Errors are returned only if the reconstruction can't proceed, the divergences are reported.
+func (r *Reconstructor) Run(ctx context.Context, batches []*types.PrunedBatch) (*Report, error) {
+	database, err := db.NewBadgerDbInMemory()
+	if err != nil {
+		return nil, err
+	}
+	defer database.Close()
+	r.database = database
+	clear(r.heads)
+	clear(r.rebuilt)
+
+	report := &Report{Heads: make(map[coreTypes.ShardId]coreTypes.BlockNumber)}
+
+	if err := r.generateZeroState(ctx, report); err != nil {
+		return nil, err
+	}
+
+	// main shard block hashes reached by the batches, used to find the batch finalized on L1
+	mainHashes := map[common.Hash]*types.BatchId{r.heads[coreTypes.MainShardId].hash: nil}
+	for _, batch := range batches {
+		if err := r.applyBatch(ctx, batch, report); err != nil || report.Divergence != nil {
+			return report, err
+		}
+		mainHashes[r.heads[coreTypes.MainShardId].hash] = &batch.BatchId
+		report.BatchesApplied++
+
+		r.logger.Info().
+			Stringer(logging.FieldBatchId, batch.BatchId).
+			Msg("batch blocks match the recorded ones")
+	}
+
+	if r.l1 == nil {
+		return report, nil
+	}
+
+	finalizedRoot, err := r.l1.LatestFinalizedStateRoot(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to get finalized state root: %w", err)
+	}
+	report.L1FinalizedRoot = finalizedRoot
+	if finalizedRoot.Empty() {
+		return report, nil
+	}
+	batchId, ok := mainHashes[finalizedRoot]
+	if !ok {
+		head := r.heads[coreTypes.MainShardId]
+		report.Divergence = &Divergence{
+			BatchId:     head.batchId,
+			ShardId:     coreTypes.MainShardId,
+			BlockNumber: head.block.Id,
+			Expected:    finalizedRoot,
+			Actual:      head.hash,
+			Reason:      "state root finalized on L1 is not reached by the batches",
+		}
+		return report, nil
+	}
+	report.L1FinalizedBatch = batchId
+	return report, nil
+}
+
+func (r *Reconstructor) generateZeroState(ctx context.Context, report *Report) error {
+	// the main shard goes first, the other shards refer to its zero state block
+	for shardId := range coreTypes.ShardId(r.nShards) {
+		gen, err := execution.NewBlockGenerator(
+			ctx, execution.NewBlockGeneratorParams(shardId, r.nShards), r.database, nil)
+		if err != nil {
+			return err
+		}
+		block, err := gen.GenerateZeroState(r.zeroState)
+		gen.Rollback()
+		if err != nil {
+			return fmt.Errorf("failed to generate zero state of shard %d: %w", shardId, err)
+		}
+		r.advance(types.BatchId{}, shardId, block, block.Hash(shardId), report)
+	}
+	return nil
+}
+
+func (r *Reconstructor) applyBatch(ctx context.Context, batch *types.PrunedBatch, report *Report) error {
+	// the main shard blocks go first: the child shard blocks read the config from them
+	blocks := slices.Clone(batch.Blocks)
+	slices.SortStableFunc(blocks, func(a, b *types.PrunedBlock) int {
+		return cmp.Or(cmp.Compare(a.ShardId, b.ShardId), cmp.Compare(a.BlockNumber, b.BlockNumber))
+	})
+
+	var mainBlocks []*types.PrunedBlock
+	for _, block := range blocks {
+		head, ok := r.heads[block.ShardId]
+		if !ok {
+			return fmt.Errorf("batch %s contains unknown shard %d", batch.BatchId, block.ShardId)
+		}
+
+		if expected := head.block.Id + 1; block.BlockNumber != expected {
+			report.Divergence = &Divergence{
+				BatchId:     batch.BatchId,
+				ShardId:     block.ShardId,
+				BlockNumber: block.BlockNumber,
+				Reason:      fmt.Sprintf("batch contains block %d instead of %d", block.BlockNumber, expected),
+			}
+			return nil
+		}
+		// the recorded hash of the previous block is the reference for the block reconstructed before
+		if block.PrevBlockHash != head.hash {
+			report.Divergence = &Divergence{
+				BatchId:     head.batchId,
+				ShardId:     block.ShardId,
+				BlockNumber: head.block.Id,
+				Expected:    block.PrevBlockHash,
+				Actual:      head.hash,
+				Reason:      "reconstructed block doesn't match the recorded one",
+			}
+			return nil
+		}
+
+		res, err := r.execute(ctx, block, head.block)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to execute block %d of shard %d from batch %s: %w",
+				block.BlockNumber, block.ShardId, batch.BatchId, err)
+		}
+		r.advance(batch.BatchId, block.ShardId, res.Block, res.BlockHash, report)
+
+		if block.ShardId.IsMainShard() {
+			mainBlocks = append(mainBlocks, block)
+		}
+	}
+
+	if len(mainBlocks) == 0 {
+		return nil
+	}
+	r.checkChildBlocks(batch.BatchId, mainBlocks, report)
+	if report.Divergence != nil || r.l1 == nil {
+		return nil
+	}
+	return r.checkFinalizedRoot(ctx, batch.BatchId, report)
+}
+
+// execute generates the block from the published data on top of the previous reconstructed block.
+func (r *Reconstructor) execute(
+	ctx context.Context, block *types.PrunedBlock, prevBlock *coreTypes.Block,
+) (*execution.BlockGenerationResult, error) {
+	params := execution.NewBlockGeneratorParams(block.ShardId, r.nShards)
+	params.ExecutionMode = execution.ModeSyncReplay
+	gen, err := execution.NewBlockGenerator(ctx, params, r.database, prevBlock)
+	if err != nil {
+		return nil, err
+	}
+	defer gen.Rollback()
+
+	res, err := gen.BuildBlock(block.Proposal(), block.GasPrices)
+	if err != nil {
+		return nil, err
+	}
+	if err := gen.Finalize(res, nil); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (r *Reconstructor) advance(
+	batchId types.BatchId, shardId coreTypes.ShardId, block *coreTypes.Block, hash common.Hash, report *Report,
+) {
+	r.heads[shardId] = &shardHead{block: block, hash: hash, batchId: batchId}
+	r.rebuilt[hash] = struct{}{}
+	report.Heads[shardId] = block.Id
+}
+
+// checkChildBlocks verifies that the last main shard block of the batch refers to the last reconstructed blocks
+// of the child shards, as the batch includes all the child shard blocks up to the ones referenced by it,
+// and that the other main shard blocks refer only to the reconstructed blocks.
+func (r *Reconstructor) checkChildBlocks(batchId types.BatchId, mainBlocks []*types.PrunedBlock, report *Report) {
+	last := mainBlocks[len(mainBlocks)-1]
+	for i, hash := range last.ChildBlocks {
+		head, ok := r.heads[coreTypes.ShardId(i+1)]
+		if ok && head.hash == hash {
+			continue
+		}
+		report.Divergence = &Divergence{
+			BatchId:     batchId,
+			ShardId:     coreTypes.ShardId(i + 1),
+			BlockNumber: report.Heads[coreTypes.ShardId(i+1)],
+			Expected:    hash,
+			Reason:      "last main shard block of the batch doesn't refer to the last block of the shard",
+		}
+		if ok {
+			report.Divergence.Actual = head.hash
+		}
+		return
+	}
+
+	for _, block := range mainBlocks {
+		for i, hash := range block.ChildBlocks {
+			if _, ok := r.rebuilt[hash]; ok {
+				continue
+			}
+			report.Divergence = &Divergence{
+				BatchId:     batchId,
+				ShardId:     coreTypes.MainShardId,
+				BlockNumber: block.BlockNumber,
+				Expected:    hash,
+				Reason:      fmt.Sprintf("main shard block refers to unknown block of shard %d", i+1),
+			}
+			return
+		}
+	}
+}
+
+// checkFinalizedRoot compares the last main shard block of the batch with the state root finalized on L1.
+func (r *Reconstructor) checkFinalizedRoot(ctx context.Context, batchId types.BatchId, report *Report) error {
+	root, err := r.l1.FinalizedStateRoot(ctx, batchId)
+	if err != nil {
+		return fmt.Errorf("failed to get finalized state root of batch %s: %w", batchId, err)
+	}
+	head := r.heads[coreTypes.MainShardId]
+	if root.Empty() || root == head.hash {
+		return nil
+	}
+	report.Divergence = &Divergence{
+		BatchId:     batchId,
+		ShardId:     coreTypes.MainShardId,
+		BlockNumber: head.block.Id,
+		Expected:    root,
+		Actual:      head.hash,
+		Reason:      "state root finalized on L1 doesn't match",
+	}
+	return nil
+}
@@ -0,0 +1,356 @@
+package reconstruct
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	v3 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v3"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testShards = 2
+
+func newTestZeroState() *execution.ZeroStateConfig {
+	return &execution.ZeroStateConfig{
+		ConfigParams: execution.ConfigParams{
+			GasPrice: config.ParamGasPrice{
+				Shards: []coreTypes.Uint256{*coreTypes.NewUint256(10), *coreTypes.NewUint256(10)},
+			},
+		},
+	}
+}
+
+// counterCode is the init code of a contract storing 42 in slot 0, its runtime code increments the slot.
+var counterCode = hexutil.MustDecode("0x602a600055600a8060106000396000f3600054600101600055" + "00")
+
+// testNetwork produces the blocks the way the collators do and records them as the aggregator publishes them.
+type testNetwork struct {
+	t        *testing.T
+	database db.DB
+	blocks   map[coreTypes.ShardId][]*coreTypes.Block
+	// pruned are the published blocks, the zero state blocks are nil
+	pruned map[coreTypes.ShardId][]*types.PrunedBlock
+	// sender is the main shard account sending the transactions to the other shards
+	sender coreTypes.Address
+	txIds  map[coreTypes.ShardId]coreTypes.TransactionIndex
+}
+
+func newTestNetwork(t *testing.T) *testNetwork {
+	t.Helper()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	n := &testNetwork{
+		t:        t,
+		database: database,
+		blocks:   make(map[coreTypes.ShardId][]*coreTypes.Block),
+		pruned:   make(map[coreTypes.ShardId][]*types.PrunedBlock),
+		sender:   coreTypes.ShardAndHexToAddress(coreTypes.MainShardId, "0x01"),
+		txIds:    make(map[coreTypes.ShardId]coreTypes.TransactionIndex),
+	}
+	for shardId := range coreTypes.ShardId(testShards) {
+		gen, err := execution.NewBlockGenerator(
+			t.Context(), execution.NewBlockGeneratorParams(shardId, testShards), database, nil)
+		require.NoError(t, err)
+		block, err := gen.GenerateZeroState(newTestZeroState())
+		gen.Rollback()
+		require.NoError(t, err)
+		n.blocks[shardId] = []*coreTypes.Block{block}
+		n.pruned[shardId] = []*types.PrunedBlock{nil}
+	}
+	return n
+}
+
+func (n *testNetwork) head(shardId coreTypes.ShardId) *coreTypes.Block {
+	blocks := n.blocks[shardId]
+	return blocks[len(blocks)-1]
+}
+
+func (n *testNetwork) headHash(shardId coreTypes.ShardId) common.Hash {
+	return n.head(shardId).Hash(shardId)
+}
+
+// transaction creates the internal transaction sent from the main shard.
+func (n *testNetwork) transaction(to coreTypes.Address, value uint64, data []byte) *coreTypes.Transaction {
+	txn := coreTypes.NewEmptyTransaction()
+	txn.Flags = coreTypes.NewTransactionFlags(coreTypes.TransactionFlagInternal)
+	txn.From, txn.RefundTo, txn.BounceTo = n.sender, n.sender, n.sender
+	txn.To = to
+	txn.Value = coreTypes.NewValueFromUint64(value)
+	txn.FeeCredit = coreTypes.NewValueFromUint64(1_000_000_000_000_000)
+	txn.MaxFeePerGas = coreTypes.MaxFeePerGasDefault
+	txn.Data = data
+	txn.TxId = n.txIds[to.ShardId()]
+	n.txIds[to.ShardId()]++
+	return txn
+}
+
+// deploy creates the transaction deploying the counter contract.
+func (n *testNetwork) deploy(shardId coreTypes.ShardId, salt byte) (*coreTypes.Transaction, coreTypes.Address) {
+	payload := coreTypes.BuildDeployPayload(counterCode, common.BytesToHash([]byte{salt}))
+	addr := coreTypes.CreateAddress(shardId, payload)
+	txn := n.transaction(addr, 1000, payload.Bytes())
+	txn.Flags = coreTypes.NewTransactionFlags(coreTypes.TransactionFlagInternal, coreTypes.TransactionFlagDeploy)
+	return txn, addr
+}
+
+// produce generates the next block of the shard, the main shard block refers to the heads of the other shards.
+func (n *testNetwork) produce(shardId coreTypes.ShardId, txns ...*coreTypes.Transaction) {
+	n.t.Helper()
+
+	prev := n.head(shardId)
+	proposal := &execution.Proposal{
+		PrevBlockId:   prev.Id,
+		PrevBlockHash: prev.Hash(shardId),
+		InternalTxns:  txns,
+	}
+	if shardId.IsMainShard() {
+		for childId := coreTypes.ShardId(1); childId < testShards; childId++ {
+			proposal.ShardHashes = append(proposal.ShardHashes, n.headHash(childId))
+		}
+	} else {
+		proposal.MainShardHash = n.headHash(coreTypes.MainShardId)
+	}
+
+	gen, err := execution.NewBlockGenerator(
+		n.t.Context(), execution.NewBlockGeneratorParams(shardId, testShards), n.database, prev)
+	require.NoError(n.t, err)
+	defer gen.Rollback()
+	res, err := gen.GenerateBlock(proposal, &coreTypes.ConsensusParams{})
+	require.NoError(n.t, err)
+
+	data := &coreTypes.BlockWithExtractedData{
+		Block:           res.Block,
+		InTransactions:  res.InTxns,
+		OutTransactions: res.OutTxns,
+		ChildBlocks:     proposal.ShardHashes,
+		Config:          make(map[string]hexutil.Bytes),
+	}
+	for name, param := range res.ConfigParams {
+		data.Config[name] = param
+	}
+	pruned, err := types.NewExecutablePrunedBlock(&types.Block{
+		ShardId:    shardId,
+		Number:     res.Block.Id,
+		Hash:       res.BlockHash,
+		ParentHash: prev.Hash(shardId),
+	}, data)
+	require.NoError(n.t, err)
+
+	n.blocks[shardId] = append(n.blocks[shardId], res.Block)
+	n.pruned[shardId] = append(n.pruned[shardId], pruned)
+}
+
+// batch publishes the blocks produced since the previous batch, it is passed through the encoding
+// to make sure the published data is enough to re-execute the blocks.
+func (n *testNetwork) batch(published map[coreTypes.ShardId]coreTypes.BlockNumber) *types.PrunedBatch {
+	n.t.Helper()
+
+	batch := &types.PrunedBatch{BatchId: types.NewBatchId(), StateDiff: types.NewStateDiff()}
+	for shardId := range coreTypes.ShardId(testShards) {
+		last := n.head(shardId).Id
+		batch.Blocks = append(batch.Blocks, n.pruned[shardId][published[shardId]+1:last+1]...)
+		published[shardId] = last
+	}
+
+	logger := logging.NewLogger("reconstruct_test")
+	var encoded bytes.Buffer
+	require.NoError(n.t, v3.NewEncoder(logger).Encode(batch, &encoded))
+	decoded, err := decodeBatch(encoded.Bytes(), logger)
+	require.NoError(n.t, err)
+	return decoded
+}
+
+type l1Stub struct {
+	roots map[types.BatchId]common.Hash
+	root  common.Hash
+}
+
+func (s *l1Stub) FinalizedStateRoot(_ context.Context, batchId types.BatchId) (common.Hash, error) {
+	return s.roots[batchId], nil
+}
+
+func (s *l1Stub) LatestFinalizedStateRoot(context.Context) (common.Hash, error) {
+	return s.root, nil
+}
+
+// newTestBatches produces two batches deploying and calling the contracts of shard 1.
+func newTestBatches(t *testing.T) (*testNetwork, []*types.PrunedBatch) {
+	t.Helper()
+
+	n := newTestNetwork(t)
+	published := make(map[coreTypes.ShardId]coreTypes.BlockNumber)
+
+	n.produce(coreTypes.MainShardId)
+	deployA, addrA := n.deploy(1, 1)
+	deployB, addrB := n.deploy(1, 2)
+	n.produce(1, deployA, deployB)
+	n.produce(1, n.transaction(addrA, 10, nil))
+	n.produce(coreTypes.MainShardId)
+	first := n.batch(published)
+
+	n.produce(1, n.transaction(addrA, 20, nil), n.transaction(addrB, 30, nil))
+	n.produce(1)
+	n.produce(coreTypes.MainShardId)
+	second := n.batch(published)
+
+	return n, []*types.PrunedBatch{first, second}
+}
+
+func newFinalizedL1(n *testNetwork, batches []*types.PrunedBatch) *l1Stub {
+	return &l1Stub{
+		roots: map[types.BatchId]common.Hash{
+			batches[0].BatchId: n.blocks[coreTypes.MainShardId][2].Hash(coreTypes.MainShardId),
+			batches[1].BatchId: n.blocks[coreTypes.MainShardId][3].Hash(coreTypes.MainShardId),
+		},
+		root: n.headHash(coreTypes.MainShardId),
+	}
+}
+
+func findBlock(batch *types.PrunedBatch, shardId coreTypes.ShardId, number coreTypes.BlockNumber) *types.PrunedBlock {
+	for _, block := range batch.Blocks {
+		if block.ShardId == shardId && block.BlockNumber == number {
+			return block
+		}
+	}
+	return nil
+}
+
+func TestReconstruct(t *testing.T) {
+	t.Parallel()
+
+	n, batches := newTestBatches(t)
+	l1 := newFinalizedL1(n, batches)
+	r, err := New(testShards, newTestZeroState(), l1, logging.NewLogger("reconstruct_test"))
+	require.NoError(t, err)
+
+	report, err := r.Run(t.Context(), batches)
+	require.NoError(t, err)
+	require.Nil(t, report.Divergence)
+	require.Equal(t, 2, report.BatchesApplied)
+	require.Equal(t, map[coreTypes.ShardId]coreTypes.BlockNumber{0: 3, 1: 4}, report.Heads)
+	require.Equal(t, &batches[1].BatchId, report.L1FinalizedBatch)
+
+	// the root finalized on L1 may lag behind the published batches
+	delete(l1.roots, batches[1].BatchId)
+	l1.root = l1.roots[batches[0].BatchId]
+	report, err = r.Run(t.Context(), batches)
+	require.NoError(t, err)
+	require.Nil(t, report.Divergence)
+	require.Equal(t, &batches[0].BatchId, report.L1FinalizedBatch)
+
+	// the batches are checked by the links between the blocks alone without L1
+	r, err = New(testShards, newTestZeroState(), nil, logging.NewLogger("reconstruct_test"))
+	require.NoError(t, err)
+	report, err = r.Run(t.Context(), batches)
+	require.NoError(t, err)
+	require.Nil(t, report.Divergence)
+	require.Equal(t, 2, report.BatchesApplied)
+}
+
+func TestReconstructDivergence(t *testing.T) {
+	t.Parallel()
+
+	logger := logging.NewLogger("reconstruct_test")
+
+	run := func(t *testing.T, zeroState *execution.ZeroStateConfig, l1 StateRootReader, batches []*types.PrunedBatch) *Report {
+		t.Helper()
+
+		r, err := New(testShards, zeroState, l1, logger)
+		require.NoError(t, err)
+		report, err := r.Run(t.Context(), batches)
+		require.NoError(t, err)
+		require.NotNil(t, report.Divergence)
+		return report
+	}
+
+	t.Run("TransactionMismatch", func(t *testing.T) {
+		t.Parallel()
+
+		n, batches := newTestBatches(t)
+		block := findBlock(batches[1], 1, 3)
+		block.Transactions[0].Value = coreTypes.NewValueFromUint64(21)
+
+		report := run(t, newTestZeroState(), nil, batches)
+		require.Equal(t, 1, report.BatchesApplied)
+		require.Equal(t, batches[1].BatchId, report.Divergence.BatchId)
+		require.Equal(t, coreTypes.ShardId(1), report.Divergence.ShardId)
+		require.Equal(t, coreTypes.BlockNumber(3), report.Divergence.BlockNumber)
+		require.Equal(t, n.blocks[1][3].Hash(1), report.Divergence.Expected)
+	})
+
+	t.Run("LastChildBlockMismatch", func(t *testing.T) {
+		t.Parallel()
+
+		n, batches := newTestBatches(t)
+		block := findBlock(batches[0], 1, 2)
+		block.Transactions[0].Data = hexutil.Bytes{0x01}
+
+		report := run(t, newTestZeroState(), nil, batches)
+		require.Zero(t, report.BatchesApplied)
+		require.Equal(t, batches[0].BatchId, report.Divergence.BatchId)
+		require.Equal(t, coreTypes.ShardId(1), report.Divergence.ShardId)
+		require.Equal(t, coreTypes.BlockNumber(2), report.Divergence.BlockNumber)
+		require.Equal(t, n.blocks[1][2].Hash(1), report.Divergence.Expected)
+	})
+
+	t.Run("FinalizedRootMismatch", func(t *testing.T) {
+		t.Parallel()
+
+		n, batches := newTestBatches(t)
+		block := findBlock(batches[1], coreTypes.MainShardId, 3)
+		block.GasPrices[1] = *coreTypes.NewUint256(11)
+
+		report := run(t, newTestZeroState(), newFinalizedL1(n, batches), batches)
+		require.Equal(t, 1, report.BatchesApplied)
+		require.Equal(t, batches[1].BatchId, report.Divergence.BatchId)
+		require.Equal(t, coreTypes.MainShardId, report.Divergence.ShardId)
+		require.Equal(t, coreTypes.BlockNumber(3), report.Divergence.BlockNumber)
+		require.Equal(t, n.headHash(coreTypes.MainShardId), report.Divergence.Expected)
+	})
+
+	t.Run("ZeroStateMismatch", func(t *testing.T) {
+		t.Parallel()
+
+		_, batches := newTestBatches(t)
+		zeroState := newTestZeroState()
+		zeroState.ConfigParams.GasPrice.Shards[1] = *coreTypes.NewUint256(20)
+
+		report := run(t, zeroState, nil, batches)
+		require.Zero(t, report.BatchesApplied)
+		require.Equal(t, types.BatchId{}, report.Divergence.BatchId)
+		require.Equal(t, coreTypes.BlockNumber(0), report.Divergence.BlockNumber)
+	})
+
+	t.Run("MissingBatch", func(t *testing.T) {
+		t.Parallel()
+
+		_, batches := newTestBatches(t)
+
+		report := run(t, newTestZeroState(), nil, batches[1:])
+		require.Zero(t, report.BatchesApplied)
+		require.Equal(t, coreTypes.MainShardId, report.Divergence.ShardId)
+		require.Equal(t, coreTypes.BlockNumber(3), report.Divergence.BlockNumber)
+	})
+
+	t.Run("UnknownL1Root", func(t *testing.T) {
+		t.Parallel()
+
+		_, batches := newTestBatches(t)
+
+		report := run(t, newTestZeroState(), &l1Stub{root: common.HexToHash("0x01")}, batches)
+		require.Equal(t, 2, report.BatchesApplied)
+		require.Equal(t, common.HexToHash("0x01"), report.Divergence.Expected)
+	})
+}
@@ -0,0 +1,91 @@
+package reconstruct
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	v3 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v3"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+// ErrUnsupportedEncoding is returned for the batches which can't be re-executed (e.g. encoded with v1 or v2).
+var ErrUnsupportedEncoding = errors.New("batch encoding does not support state reconstruction")
+
+// BatchSource provides the published batches in the order they were committed in.
+type BatchSource interface {
+	Batches(ctx context.Context) ([]*types.PrunedBatch, error)
+}
+
+type dirSource struct {
+	dir    string
+	logger logging.Logger
+}
+
+// NewDirSource loads the batches with LoadBatches.
+func NewDirSource(dir string, logger logging.Logger) BatchSource {
+	return &dirSource{dir: dir, logger: logger}
+}
+
+func (s *dirSource) Batches(context.Context) ([]*types.PrunedBatch, error) {
+	return LoadBatches(s.dir, s.logger)
+}
+
+// LoadBatches reads the batches stored by the local data availability backend (`<batchId>.bin` files)
+// and orders them by the range of the main shard blocks.
+func LoadBatches(dir string, logger logging.Logger) ([]*types.PrunedBatch, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.bin"))
+	if err != nil {
+		return nil, err
+	}
+
+	batches := make([]*types.PrunedBatch, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		batch, err := decodeBatch(data, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read batch %s: %w", file, err)
+		}
+		batches = append(batches, batch)
+	}
+
+	SortBatches(batches)
+	return batches, nil
+}
+
+func decodeBatch(data []byte, logger logging.Logger) (*types.PrunedBatch, error) {
+	batch, err := v3.NewDecoder(logger).Decode(bytes.NewReader(data))
+	if errors.Is(err, encode.ErrInvalidVersion) {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedEncoding, err)
+	}
+	return batch, err
+}
+
+// SortBatches orders the batches by the first block of the main shard. The batches are produced
+// sequentially, so it is the order they were committed in.
+func SortBatches(batches []*types.PrunedBatch) {
+	slices.SortStableFunc(batches, func(a, b *types.PrunedBatch) int {
+		return cmp.Compare(mainFirstBlock(a), mainFirstBlock(b))
+	})
+}
+
+func mainFirstBlock(batch *types.PrunedBatch) coreTypes.BlockNumber {
+	first := coreTypes.BlockNumber(0)
+	for _, block := range batch.Blocks {
+		if block.ShardId.IsMainShard() && (first == 0 || block.BlockNumber < first) {
+			first = block.BlockNumber
+		}
+	}
+	return first
+}
@@ -0,0 +1,59 @@
+package reconstruct
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	coreTypes "github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode"
+	v1 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v1"
+	v2 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v2"
+	v3 "github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/encode/v3"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newPrunedBatch(mainBlock coreTypes.BlockNumber) *types.PrunedBatch {
+	return &types.PrunedBatch{
+		BatchId: types.NewBatchId(),
+		Blocks: []*types.PrunedBlock{
+			{ShardId: coreTypes.MainShardId, BlockNumber: mainBlock},
+			{ShardId: 1, BlockNumber: mainBlock * 2},
+		},
+		StateDiff: types.NewStateDiff(),
+	}
+}
+
+func writeBatch(t *testing.T, dir string, encoder encode.BatchEncoder, batch *types.PrunedBatch) {
+	t.Helper()
+
+	out, err := os.Create(filepath.Join(dir, batch.BatchId.String()+".bin"))
+	require.NoError(t, err)
+	defer out.Close()
+	require.NoError(t, encoder.Encode(batch, out))
+}
+
+func TestLoadBatches(t *testing.T) {
+	t.Parallel()
+
+	logger := logging.NewLogger("reconstruct_test")
+	dir := t.TempDir()
+	later, earlier := newPrunedBatch(5), newPrunedBatch(1)
+	writeBatch(t, dir, v3.NewEncoder(logger), later)
+	writeBatch(t, dir, v3.NewEncoder(logger), earlier)
+
+	batches, err := LoadBatches(dir, logger)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	require.Equal(t, earlier.BatchId, batches[0].BatchId)
+	require.Equal(t, later.BatchId, batches[1].BatchId)
+
+	for _, encoder := range []encode.BatchEncoder{v1.NewEncoder(logger), v2.NewEncoder(logger)} {
+		dir := t.TempDir()
+		writeBatch(t, dir, encoder, newPrunedBatch(7))
+		_, err = LoadBatches(dir, logger)
+		require.ErrorIs(t, err, ErrUnsupportedEncoding)
+	}
+}
@@ -0,0 +1,22 @@
+package rollupcontract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NilFoundation/nil/nil/services/rollup"
+)
+
+// BlobBaseFee returns the blob base fee of the latest L1 block.
+func (r *wrapperImpl) BlobBaseFee(ctx context.Context) (*big.Int, error) {
+	head, err := r.ethClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting header: %w", err)
+	}
+	if head.ExcessBlobGas == nil {
+		return nil, errors.New("L1 header has no excess blob gas")
+	}
+	return rollup.CalcBlobFee(*head.ExcessBlobGas), nil
+}
@@ -7,8 +7,10 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/services/rollup"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
@@ -87,6 +89,76 @@ func (r *wrapperImpl) CommitBatch(
 	return nil
 }
 
+// CommitBatchCalldata commits the batch whose data is published as calldata of the commit transaction,
+// the contract stores the hash of the data.
+// If such `batchIndex` is already submitted, returns `ErrBatchAlreadyCommitted`.
+func (r *wrapperImpl) CommitBatchCalldata(ctx context.Context, batchIndex string, data []byte) error {
+	return r.commitBatchWithoutBlobs(ctx, batchIndex, "CommitBatchCalldata",
+		func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+			return r.rollupContract.CommitBatchCalldata(opts, batchIndex, data)
+		})
+}
+
+// CommitBatchDataHash commits the batch whose data is kept off-chain with the hash of the data,
+// the contract accepts it only from the devnet proposers.
+// If such `batchIndex` is already submitted, returns `ErrBatchAlreadyCommitted`.
+func (r *wrapperImpl) CommitBatchDataHash(ctx context.Context, batchIndex string, dataHash common.Hash) error {
+	return r.commitBatchWithoutBlobs(ctx, batchIndex, "CommitBatchDataHash",
+		func(opts *bind.TransactOpts) (*ethtypes.Transaction, error) {
+			return r.rollupContract.CommitBatchDataHash(opts, batchIndex, dataHash)
+		})
+}
+
+func (r *wrapperImpl) commitBatchWithoutBlobs(
+	ctx context.Context,
+	batchIndex string,
+	method string,
+	commit func(opts *bind.TransactOpts) (*ethtypes.Transaction, error),
+) error {
+	isCommited, err := r.rollupContract.IsBatchCommitted(r.getEthCallOpts(ctx), batchIndex)
+	if err != nil {
+		return err
+	}
+	if isCommited {
+		return ErrBatchAlreadyCommitted
+	}
+
+	var tx *ethtypes.Transaction
+	if err := r.transactWithCtx(ctx, func(opts *bind.TransactOpts) error {
+		opts.NoSend = true
+		tx, err = commit(opts)
+		return err
+	}); err != nil {
+		return fmt.Errorf("simulation transaction creation failed: %w", err)
+	}
+	if err := r.simulateTx(ctx, tx, nil); err != nil {
+		return r.parseCommitBatchTxError(fmt.Errorf("pre-submition simulation: %w", err))
+	}
+
+	if err := r.transactWithCtx(ctx, func(opts *bind.TransactOpts) error {
+		tx, err = commit(opts)
+		return err
+	}); err != nil {
+		return fmt.Errorf("%s transaction failed: %w", method, err)
+	}
+
+	r.logger.Info().
+		Hex("txHash", tx.Hash().Bytes()).
+		Int("gasLimit", int(tx.Gas())).
+		Str("method", method).
+		Msg("commit transaction sent")
+
+	receipt, err := r.waitForReceipt(ctx, tx.Hash())
+	if err != nil {
+		return err
+	}
+	r.logReceiptDetails(receipt)
+	if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("%s tx failed", method)
+	}
+	return nil
+}
+
 func (r *wrapperImpl) parseCommitBatchTxError(err error) error {
 	var cerr contractError
 	if errors.As(err, &cerr) {
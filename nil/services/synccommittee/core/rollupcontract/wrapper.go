@@ -36,8 +36,11 @@ type Wrapper interface {
 		sidecar *ethtypes.BlobTxSidecar,
 		batchIndex string,
 	) error
+	CommitBatchCalldata(ctx context.Context, batchIndex string, data []byte) error
+	CommitBatchDataHash(ctx context.Context, batchIndex string, dataHash common.Hash) error
 	PrepareBlobs(ctx context.Context, blobs []kzg4844.Blob) (*ethtypes.BlobTxSidecar, types.DataProofs, error)
 	ResetState(ctx context.Context, targetRoot common.Hash) error
+	BlobBaseFee(ctx context.Context) (*big.Int, error)
 }
 
 type WrapperConfig struct {
@@ -348,7 +351,22 @@ func (w *noopWrapper) CommitBatch(
 	return nil
 }
 
+func (w *noopWrapper) CommitBatchCalldata(ctx context.Context, batchIndex string, data []byte) error {
+	w.logger.Debug().Msg("CommitBatchCalldata noop wrapper method called")
+	return nil
+}
+
+func (w *noopWrapper) CommitBatchDataHash(ctx context.Context, batchIndex string, dataHash common.Hash) error {
+	w.logger.Debug().Msg("CommitBatchDataHash noop wrapper method called")
+	return nil
+}
+
 func (w *noopWrapper) ResetState(ctx context.Context, targetRoot common.Hash) error {
 	w.logger.Debug().Msg("ResetState noop wrapper method called")
 	return nil
 }
+
+func (w *noopWrapper) BlobBaseFee(ctx context.Context) (*big.Int, error) {
+	w.logger.Debug().Msg("BlobBaseFee noop wrapper method called")
+	return big.NewInt(0), nil
+}
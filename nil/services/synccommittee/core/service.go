@@ -7,6 +7,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/core/batches/da"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/fetching"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/reset"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/core/rollupcontract"
@@ -58,17 +59,26 @@ func New(ctx context.Context, cfg *Config, database db.DB) (*SyncCommittee, erro
 	syncCommittee := &SyncCommittee{}
 	resetLauncher := reset.NewResetLauncher(stateResetter, syncCommittee, logger)
 
-	agg := fetching.NewAggregator(
+	dataAvailability, err := da.New(cfg.DAConfig, rollupContractWrapper, logger)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing data availability backend: %w", err)
+	}
+
+	agg, err := fetching.NewAggregator(
 		client,
 		blockStorage,
 		taskStorage,
 		resetLauncher,
 		rollupContractWrapper,
+		dataAvailability,
 		clock,
 		logger,
 		metricsHandler,
 		cfg.AggregatorConfig,
 	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aggregator: %w", err)
+	}
 
 	lagTracker := fetching.NewLagTracker(
 		client, blockStorage, metricsHandler, fetching.NewDefaultLagTrackerConfig(), logger,
@@ -102,9 +112,11 @@ func New(ctx context.Context, cfg *Config, database db.DB) (*SyncCommittee, erro
 		logger,
 	)
 
+	taskArchiver := scheduler.NewTaskArchiver(taskStorage, cfg.TaskArchiverConfig, logger)
+
 	syncCommittee.Service = srv.NewService(
 		logger,
-		proposer, agg, lagTracker, taskScheduler, taskListener,
+		proposer, agg, lagTracker, taskScheduler, taskListener, taskArchiver,
 	)
 
 	return syncCommittee, nil
@@ -11,14 +11,21 @@ const (
 	TaskRequestHandlerGetTask           = TaskRequestHandlerNamespace + "_getTask"
 	TaskRequestHandlerCheckIfTaskExists = TaskRequestHandlerNamespace + "_checkIfTaskExists"
 	TaskRequestHandlerSetTaskResult     = TaskRequestHandlerNamespace + "_setTaskResult"
+	TaskRequestHandlerRenewTaskLease    = TaskRequestHandlerNamespace + "_renewTaskLease"
 )
 
 type TaskRequest struct {
 	ExecutorId types.TaskExecutorId `json:"executorId"`
+
+	// Capabilities limit the tasks which can be assigned to the executor, nil means any task
+	Capabilities *types.ExecutorCapabilities `json:"capabilities,omitempty"`
 }
 
-func NewTaskRequest(executorId types.TaskExecutorId) *TaskRequest {
-	return &TaskRequest{ExecutorId: executorId}
+func NewTaskRequest(executorId types.TaskExecutorId, capabilities *types.ExecutorCapabilities) *TaskRequest {
+	return &TaskRequest{
+		ExecutorId:   executorId,
+		Capabilities: capabilities,
+	}
 }
 
 type TaskCheckRequest struct {
@@ -37,6 +44,10 @@ type TaskRequestHandler interface {
 	GetTask(context context.Context, request *TaskRequest) (*types.Task, error)
 	CheckIfTaskExists(context context.Context, request *TaskCheckRequest) (bool, error)
 	SetTaskResult(context context.Context, result *types.TaskResult) error
+
+	// RenewTaskLease extends the lease of the running task, prevents it from being rescheduled.
+	// Returns false if the task is not assigned to the executor anymore.
+	RenewTaskLease(context context.Context, request *TaskCheckRequest) (bool, error)
 }
 
 //go:generate bash ../scripts/generate_mock.sh TaskRequestHandler
@@ -3,7 +3,10 @@ package executor
 import (
 	"context"
 	"crypto/rand"
+	"errors"
+	"fmt"
 	"math/big"
+	"sync"
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common/logging"
@@ -17,16 +20,26 @@ import (
 )
 
 const (
-	DefaultTaskPollingInterval = time.Second
+	DefaultTaskPollingInterval  = time.Second
+	DefaultLeaseRenewalInterval = 30 * time.Second
 )
 
+var ErrTaskLeaseLost = errors.New("task lease is lost")
+
 type Config struct {
 	TaskPollingInterval time.Duration
+
+	// LeaseRenewalInterval defines how often the lease of the task being handled is renewed, 0 disables renewals
+	LeaseRenewalInterval time.Duration
+
+	// Capabilities are sent with each task request, nil means the executor is able to handle any task
+	Capabilities *types.ExecutorCapabilities
 }
 
 func DefaultConfig() *Config {
 	return &Config{
-		TaskPollingInterval: DefaultTaskPollingInterval,
+		TaskPollingInterval:  DefaultTaskPollingInterval,
+		LeaseRenewalInterval: DefaultLeaseRenewalInterval,
 	}
 }
 
@@ -96,7 +109,7 @@ func (p *taskExecutorImpl) fetchAndHandleTask(ctx context.Context) error {
 		return nil
 	}
 
-	taskRequest := api.NewTaskRequest(p.nonceId)
+	taskRequest := api.NewTaskRequest(p.nonceId, p.config.Capabilities)
 	task, err := p.requestHandler.GetTask(ctx, taskRequest)
 	if err != nil {
 		return err
@@ -108,7 +121,7 @@ func (p *taskExecutorImpl) fetchAndHandleTask(ctx context.Context) error {
 	}
 
 	log.NewTaskEvent(p.logger, zerolog.DebugLevel, task).Msg("Executing task")
-	err = p.taskHandler.Handle(ctx, p.nonceId, task)
+	err = p.handleWithLease(ctx, task)
 
 	if err == nil {
 		log.NewTaskEvent(p.logger, zerolog.DebugLevel, task).
@@ -120,6 +133,61 @@ func (p *taskExecutorImpl) fetchAndHandleTask(ctx context.Context) error {
 	return err
 }
 
+// handleWithLease handles the task renewing its lease in background.
+// Handling is cancelled if the lease is lost, i.e. the task is rescheduled or cancelled by the scheduler.
+func (p *taskExecutorImpl) handleWithLease(ctx context.Context, task *types.Task) error {
+	if p.config.LeaseRenewalInterval <= 0 {
+		return p.taskHandler.Handle(ctx, p.nonceId, task)
+	}
+
+	handleCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		p.renewLease(handleCtx, task, cancel)
+	}()
+
+	err := p.taskHandler.Handle(handleCtx, p.nonceId, task)
+	cancel(nil)
+	wg.Wait()
+
+	if cause := context.Cause(handleCtx); errors.Is(cause, ErrTaskLeaseLost) {
+		return cause
+	}
+	return err
+}
+
+func (p *taskExecutorImpl) renewLease(ctx context.Context, task *types.Task, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(p.config.LeaseRenewalInterval)
+	defer ticker.Stop()
+
+	request := api.NewTaskCheckRequest(task.Id, p.nonceId)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		renewed, err := p.requestHandler.RenewTaskLease(ctx, request)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			// the lease is still valid until the timeout, renewal is retried on the next tick
+			log.NewTaskEvent(p.logger, zerolog.WarnLevel, task).Err(err).Msg("failed to renew task lease")
+			p.metrics.RecordError(ctx, p.Name())
+		case !renewed:
+			log.NewTaskEvent(p.logger, zerolog.WarnLevel, task).Msg("task lease is lost, cancelling execution")
+			cancel(fmt.Errorf("%w: taskId=%s", ErrTaskLeaseLost, task.Id))
+			return
+		}
+	}
+}
+
 func generateNonceId() (*types.TaskExecutorId, error) {
 	bigInt, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt32))
 	if err != nil {
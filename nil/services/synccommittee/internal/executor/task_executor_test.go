@@ -2,6 +2,7 @@ package executor
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -68,7 +69,7 @@ func (s *TestSuite) Test_TaskExecutor_Executes_Tasks() {
 	err := testaide.WaitFor(s.context, started, 10*time.Second)
 	s.Require().NoError(err, "task executor did not start in time")
 
-	expectedTaskRequest := api.NewTaskRequest(s.taskExecutor.Id())
+	expectedTaskRequest := api.NewTaskRequest(s.taskExecutor.Id(), nil)
 	const tasksThreshold = 5
 
 	s.Require().Eventually(
@@ -134,3 +135,51 @@ func (s *TestSuite) Test_TaskExecutor_Busy_Handler() {
 		100*time.Millisecond,
 	)
 }
+
+func (s *TestSuite) Test_TaskExecutor_Renews_Lease() {
+	config := Config{
+		TaskPollingInterval:  10 * time.Millisecond,
+		LeaseRenewalInterval: 10 * time.Millisecond,
+	}
+	metricsHandler, err := metrics.NewSyncCommitteeMetrics()
+	s.Require().NoError(err)
+	taskExecutor, err := New(
+		&config, s.requestHandler, s.taskHandler, metricsHandler, logging.NewLogger("task-executor-test"))
+	s.Require().NoError(err)
+	s.taskExecutor = taskExecutor
+
+	const renewalsBeforeLoss = 3
+	var renewals atomic.Int32
+	s.requestHandler.RenewTaskLeaseFunc = func(_ context.Context, request *api.TaskCheckRequest) (bool, error) {
+		return renewals.Add(1) <= renewalsBeforeLoss, nil
+	}
+
+	handleCauses := make(chan error, 1)
+	s.taskHandler.HandleFunc = func(ctx context.Context, _ types.TaskExecutorId, _ *types.Task) error {
+		select {
+		case <-ctx.Done():
+			select {
+			case handleCauses <- context.Cause(ctx):
+			default:
+			}
+			return ctx.Err()
+		case <-time.After(10 * time.Second):
+			return nil
+		}
+	}
+
+	_, cancelFn := s.runTaskExecutor(s.context)
+	defer cancelFn()
+
+	var cause error
+	select {
+	case cause = <-handleCauses:
+	case <-time.After(10 * time.Second):
+		s.Fail("task handling was not cancelled after the lease loss")
+	}
+	s.Require().ErrorIs(cause, ErrTaskLeaseLost)
+
+	renewCalls := s.requestHandler.RenewTaskLeaseCalls()
+	s.Require().GreaterOrEqual(len(renewCalls), renewalsBeforeLoss+1)
+	s.Require().Equal(s.taskExecutor.Id(), renewCalls[0].Request.ExecutorId)
+}
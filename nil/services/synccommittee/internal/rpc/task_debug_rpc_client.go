@@ -39,3 +39,27 @@ func (c *taskDebugRpcClient) GetTaskTree(ctx context.Context, taskId types.TaskI
 		taskId,
 	)
 }
+
+func (c *taskDebugRpcClient) SetBatchPriority(
+	ctx context.Context,
+	request *public.SetBatchPriorityRequest,
+) (uint, error) {
+	return doRPCCall[*public.SetBatchPriorityRequest, uint](
+		ctx,
+		c.client,
+		public.DebugSetBatchPriority,
+		request,
+	)
+}
+
+func (c *taskDebugRpcClient) GetArchivedTasks(
+	ctx context.Context,
+	request *public.TaskArchiveRequest,
+) ([]*public.ArchivedTaskTree, error) {
+	return doRPCCall[*public.TaskArchiveRequest, []*public.ArchivedTaskTree](
+		ctx,
+		c.client,
+		public.DebugGetArchivedTasks,
+		request,
+	)
+}
@@ -456,3 +456,76 @@ func (s *TaskSchedulerDebugRpcTestSuite) requireAllPass(assertions []bool, id ty
 		s.FailNowf("", "assertion for task with id=%s failed", id.String())
 	}
 }
+
+func (s *TaskSchedulerDebugRpcTestSuite) Test_Get_Archived_Tasks() {
+	now := s.clock.Now()
+	entries := []*types.TaskEntry{
+		testaide.NewTaskEntry(now, running, someExecutor),
+		testaide.NewTaskEntry(now, running, testaide.RandomExecutorId()),
+		testaide.NewTaskEntry(now, running, someExecutor),
+	}
+	err := s.storage.AddTaskEntries(s.context, entries...)
+	s.Require().NoError(err)
+
+	// tasks are finished with an interval of one hour
+	for i, entry := range entries {
+		if i > 0 {
+			s.clock.Advance(time.Hour)
+		}
+		err := s.storage.ProcessTaskResult(
+			s.context,
+			types.NewFailureProverTaskResult(
+				entry.Task.Id, entry.Owner, types.NewTaskExecErrorf(types.TaskErrProofGenerationFailed, "failure %d", i)),
+		)
+		s.Require().NoError(err)
+	}
+
+	from, to := now.Add(30*time.Minute), now.Add(90*time.Minute)
+	testCases := []struct {
+		name     string
+		request  public.TaskArchiveRequest
+		expected []*types.TaskEntry
+	}{
+		{"NoFilter", public.DefaultTaskArchiveRequest(), entries},
+		{
+			"FilterByBatch",
+			public.TaskArchiveRequest{BatchId: &entries[1].Task.BatchId, Limit: public.DefaultTaskArchiveLimit},
+			[]*types.TaskEntry{entries[1]},
+		},
+		{
+			"FilterByExecutor",
+			public.TaskArchiveRequest{Owner: someExecutor, Limit: public.DefaultTaskArchiveLimit},
+			[]*types.TaskEntry{entries[0], entries[2]},
+		},
+		{
+			"FilterByTimeRange",
+			public.TaskArchiveRequest{From: &from, To: &to, Limit: public.DefaultTaskArchiveLimit},
+			[]*types.TaskEntry{entries[1]},
+		},
+	}
+
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			trees, err := s.rpcClient.GetArchivedTasks(s.context, &testCase.request)
+			s.Require().NoError(err)
+
+			var ids []types.TaskId
+			for _, tree := range trees {
+				s.Require().Len(tree.Tasks, 1)
+				s.Require().Equal(types.Failed, tree.Tasks[0].Status)
+				s.Require().NotEmpty(tree.Tasks[0].ErrorText)
+				ids = append(ids, tree.Tasks[0].Id)
+			}
+
+			var expectedIds []types.TaskId
+			for _, entry := range testCase.expected {
+				expectedIds = append(expectedIds, entry.Task.Id)
+			}
+			s.Require().ElementsMatch(expectedIds, ids)
+		})
+	}
+
+	invalidRequest := public.TaskArchiveRequest{Limit: public.TaskArchiveMaxLimit + 1}
+	_, err = s.rpcClient.GetArchivedTasks(s.context, &invalidRequest)
+	s.Require().Error(err)
+}
@@ -69,6 +69,9 @@ func newTaskSchedulerMock() *scheduler.TaskSchedulerMock {
 			predefinedTask := tasksForExecutors[request.ExecutorId]
 			return predefinedTask, nil
 		},
+		RenewTaskLeaseFunc: func(_ context.Context, request *api.TaskCheckRequest) (bool, error) {
+			return request.ExecutorId == firstExecutorId, nil
+		},
 	}
 }
 
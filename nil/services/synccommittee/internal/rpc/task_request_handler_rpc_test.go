@@ -35,7 +35,7 @@ func (s *TaskRequestHandlerTestSuite) Test_TaskRequestHandler_GetTask() {
 func (s *TaskRequestHandlerTestSuite) testGetTask(executorId types.TaskExecutorId) {
 	s.T().Helper()
 
-	request := api.NewTaskRequest(executorId)
+	request := api.NewTaskRequest(executorId, nil)
 	receivedTask, err := s.clientHandler.GetTask(s.context, request)
 	s.Require().NoError(err)
 	getTaskCalls := s.scheduler.GetTaskCalls()
@@ -91,3 +91,27 @@ func (s *TaskRequestHandlerTestSuite) testSetTaskStatus(resultToSend *types.Task
 	s.Require().Len(setResultCalls, 1, "expected one call to SetTaskResult")
 	s.Require().Equal(resultToSend, setResultCalls[0].Result)
 }
+
+func (s *TaskRequestHandlerTestSuite) Test_TaskRequestHandler_RenewTaskLease() {
+	testCases := []struct {
+		name       string
+		executorId types.TaskExecutorId
+		renewed    bool
+	}{
+		{"Lease_Renewed", firstExecutorId, true},
+		{"Lease_Lost", secondExecutorId, false},
+	}
+
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			request := api.NewTaskCheckRequest(types.NewTaskId(), testCase.executorId)
+			renewed, err := s.clientHandler.RenewTaskLease(s.context, request)
+			s.Require().NoError(err)
+			s.Require().Equal(testCase.renewed, renewed)
+
+			renewCalls := s.scheduler.RenewTaskLeaseCalls()
+			s.Require().Len(renewCalls, 1, "expected one call to RenewTaskLease")
+			s.Require().Equal(request, renewCalls[0].Request)
+		})
+	}
+}
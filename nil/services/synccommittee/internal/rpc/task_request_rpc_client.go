@@ -46,3 +46,12 @@ func (r *taskRequestRpcClient) SetTaskResult(ctx context.Context, result *types.
 	)
 	return err
 }
+
+func (r *taskRequestRpcClient) RenewTaskLease(ctx context.Context, request *api.TaskCheckRequest) (bool, error) {
+	return doRPCCall[*api.TaskCheckRequest, bool](
+		ctx,
+		r.client,
+		api.TaskRequestHandlerRenewTaskLease,
+		request,
+	)
+}
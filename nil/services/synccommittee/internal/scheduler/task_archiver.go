@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/srv"
+)
+
+type TaskArchiverConfig struct {
+	// RetainedBatches defines the number of the most recent batches whose task trees are kept in the task tables,
+	// the trees of older batches are moved into the task archive. Zero disables archival.
+	RetainedBatches uint32        `yaml:"taskRetainedBatches,omitempty"`
+	CheckInterval   time.Duration `yaml:"-"`
+}
+
+func NewDefaultTaskArchiverConfig() TaskArchiverConfig {
+	return TaskArchiverConfig{
+		RetainedBatches: 100,
+		CheckInterval:   10 * time.Minute,
+	}
+}
+
+type TaskArchiveStorage interface {
+	ArchiveTerminatedBatches(ctx context.Context, retainedBatches uint32) (uint, error)
+}
+
+// TaskArchiver periodically moves the terminated task trees of old batches into the task archive.
+type TaskArchiver struct {
+	srv.WorkerLoop
+
+	storage TaskArchiveStorage
+	config  TaskArchiverConfig
+	logger  logging.Logger
+}
+
+func NewTaskArchiver(
+	storage TaskArchiveStorage,
+	config TaskArchiverConfig,
+	logger logging.Logger,
+) *TaskArchiver {
+	archiver := &TaskArchiver{
+		storage: storage,
+		config:  config,
+	}
+
+	archiver.WorkerLoop = srv.NewWorkerLoop("task_archiver", config.CheckInterval, archiver.runIteration)
+	archiver.logger = srv.WorkerLogger(logger, archiver)
+	return archiver
+}
+
+func (a *TaskArchiver) runIteration(ctx context.Context) {
+	if a.config.RetainedBatches == 0 {
+		return
+	}
+
+	archived, err := a.storage.ArchiveTerminatedBatches(ctx, a.config.RetainedBatches)
+	if err != nil {
+		a.logger.Error().Err(err).Msg("failed to archive terminated task trees")
+		return
+	}
+	if archived > 0 {
+		a.logger.Info().Uint("batches", archived).Msg("archived terminated task trees")
+	}
+}
@@ -22,12 +22,14 @@ var ErrFailedToProcessTaskResult = errors.New("failed to process task result")
 type Config struct {
 	taskCheckInterval    time.Duration
 	taskExecutionTimeout time.Duration
+	taskLeaseTimeout     time.Duration
 }
 
 func DefaultConfig() Config {
 	return Config{
 		taskCheckInterval:    time.Minute,
 		taskExecutionTimeout: time.Hour,
+		taskLeaseTimeout:     5 * time.Minute,
 	}
 }
 
@@ -48,11 +50,26 @@ type Storage interface {
 
 	GetTaskTreeView(ctx context.Context, taskId types.TaskId) (*public.TaskTreeView, error)
 
-	RequestTaskToExecute(ctx context.Context, executor types.TaskExecutorId) (*types.Task, error)
+	GetArchivedTaskTrees(
+		ctx context.Context,
+		batchId *types.BatchId,
+		predicate func(*public.ArchivedTaskTree) bool,
+		limit int,
+	) ([]*public.ArchivedTaskTree, error)
+
+	RequestMatchingTaskToExecute(
+		ctx context.Context,
+		executor types.TaskExecutorId,
+		capabilities *types.ExecutorCapabilities,
+	) (*types.Task, error)
+
+	RenewTaskLease(ctx context.Context, taskId types.TaskId, executor types.TaskExecutorId) (bool, error)
+
+	SetBatchPriority(ctx context.Context, batchId types.BatchId, priority types.TaskPriority) (uint, error)
 
 	ProcessTaskResult(ctx context.Context, res *types.TaskResult) error
 
-	RescheduleHangingTasks(ctx context.Context, taskExecutionTimeout time.Duration) error
+	RescheduleHangingTasks(ctx context.Context, taskExecutionTimeout, taskLeaseTimeout time.Duration) error
 }
 
 type Metrics interface {
@@ -89,7 +106,7 @@ type taskSchedulerImpl struct {
 }
 
 func (s *taskSchedulerImpl) runIteration(ctx context.Context) {
-	err := s.storage.RescheduleHangingTasks(ctx, s.config.taskExecutionTimeout)
+	err := s.storage.RescheduleHangingTasks(ctx, s.config.taskExecutionTimeout, s.config.taskLeaseTimeout)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("failed to reschedule hanging tasks")
 		s.recordError(ctx)
@@ -99,7 +116,7 @@ func (s *taskSchedulerImpl) runIteration(ctx context.Context) {
 func (s *taskSchedulerImpl) GetTask(ctx context.Context, request *api.TaskRequest) (*types.Task, error) {
 	s.logger.Debug().Stringer(logging.FieldTaskExecutorId, request.ExecutorId).Msg("received new task request")
 
-	task, err := s.storage.RequestTaskToExecute(ctx, request.ExecutorId)
+	task, err := s.storage.RequestMatchingTaskToExecute(ctx, request.ExecutorId, request.Capabilities)
 	if err != nil {
 		s.logger.Error().
 			Err(err).
@@ -144,6 +161,33 @@ func (s *taskSchedulerImpl) CheckIfTaskExists(ctx context.Context, request *api.
 	return true, nil
 }
 
+func (s *taskSchedulerImpl) RenewTaskLease(ctx context.Context, request *api.TaskCheckRequest) (bool, error) {
+	s.logger.Trace().
+		Stringer(logging.FieldTaskId, request.TaskId).
+		Stringer(logging.FieldTaskExecutorId, request.ExecutorId).
+		Msg("received task lease renewal request")
+
+	renewed, err := s.storage.RenewTaskLease(ctx, request.TaskId, request.ExecutorId)
+	if err != nil {
+		s.logger.Error().
+			Err(err).
+			Stringer(logging.FieldTaskId, request.TaskId).
+			Stringer(logging.FieldTaskExecutorId, request.ExecutorId).
+			Msg("failed to renew task lease")
+		s.recordError(ctx)
+		return false, err
+	}
+
+	if !renewed {
+		s.logger.Warn().
+			Stringer(logging.FieldTaskId, request.TaskId).
+			Stringer(logging.FieldTaskExecutorId, request.ExecutorId).
+			Msg("task lease is lost by the executor")
+	}
+
+	return renewed, nil
+}
+
 func (s *taskSchedulerImpl) SetTaskResult(ctx context.Context, result *types.TaskResult) error {
 	log.NewTaskResultEvent(s.logger, zerolog.DebugLevel, result).Msgf("received task result update")
 
@@ -261,6 +305,40 @@ func (s *taskSchedulerImpl) GetTaskTree(ctx context.Context, taskId types.TaskId
 	return s.storage.GetTaskTreeView(ctx, taskId)
 }
 
+func (s *taskSchedulerImpl) GetArchivedTasks(
+	ctx context.Context,
+	request *public.TaskArchiveRequest,
+) ([]*public.ArchivedTaskTree, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	trees, err := s.storage.GetArchivedTaskTrees(ctx, request.BatchId, request.Matches, request.Limit)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to get archived tasks from the storage (GetArchivedTaskTrees)")
+		return nil, err
+	}
+	return trees, nil
+}
+
+func (s *taskSchedulerImpl) SetBatchPriority(
+	ctx context.Context,
+	request *public.SetBatchPriorityRequest,
+) (uint, error) {
+	updated, err := s.storage.SetBatchPriority(ctx, request.BatchId, request.Priority)
+	if err != nil {
+		s.logger.Error().Err(err).Stringer(logging.FieldBatchId, request.BatchId).Msg("failed to set batch priority")
+		return 0, err
+	}
+
+	s.logger.Info().
+		Stringer(logging.FieldBatchId, request.BatchId).
+		Stringer("priority", request.Priority).
+		Uint("updatedTasks", updated).
+		Msg("batch priority is updated")
+	return updated, nil
+}
+
 func (s *taskSchedulerImpl) onTaskResultError(ctx context.Context, cause error, result *types.TaskResult) error {
 	log.NewTaskResultEvent(s.logger, zerolog.ErrorLevel, result).Err(cause).Msg("Failed to process task result")
 	s.recordError(ctx)
@@ -133,6 +133,24 @@ func (bs *BlockStorage) BatchExists(ctx context.Context, batchId scTypes.BatchId
 	}
 }
 
+// GetBatchDACommitment returns the data availability commitment recorded for the batch.
+// The result is nil if the batch has been stored without a commitment.
+func (bs *BlockStorage) GetBatchDACommitment(
+	ctx context.Context, batchId scTypes.BatchId,
+) (*scTypes.DACommitment, error) {
+	tx, err := bs.database.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	entry, err := bs.ops.getBatch(tx, batchId)
+	if err != nil {
+		return nil, err
+	}
+	return entry.DACommitment, nil
+}
+
 func (bs *BlockStorage) GetLatestFetched(ctx context.Context) (scTypes.BlockRefs, error) {
 	tx, err := bs.database.CreateRoTx(ctx)
 	if err != nil {
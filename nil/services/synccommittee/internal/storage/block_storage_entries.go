@@ -23,6 +23,7 @@ type batchEntry struct {
 	LatestMainBlockHash common.Hash                         `json:"latestMainBlockHash"`
 	BlockIds            []scTypes.BlockId                   `json:"blockIds"`
 	DataProofs          scTypes.DataProofs                  `json:"dataProofs"`
+	DACommitment        *scTypes.DACommitment               `json:"daCommitment,omitempty"`
 
 	IsProved  bool      `json:"isProved,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
@@ -41,6 +42,7 @@ func newBatchEntry(batch *scTypes.BlockBatch, createdAt time.Time) *batchEntry {
 		LatestMainBlockHash: batch.LatestMainBlock().Hash,
 		BlockIds:            batch.BlockIds(),
 		DataProofs:          batch.DataProofs,
+		DACommitment:        batch.DACommitment,
 
 		IsProved:  false,
 		CreatedAt: createdAt,
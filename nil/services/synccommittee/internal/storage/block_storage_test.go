@@ -174,6 +174,25 @@ func (s *BlockStorageTestSuite) Test_BatchExists_False() {
 	s.Require().False(exists)
 }
 
+func (s *BlockStorageTestSuite) Test_GetBatchDACommitment() {
+	batch := testaide.NewBlockBatch(3)
+	commitment := &scTypes.DACommitment{
+		Backend:  scTypes.DABackendLocal,
+		DataHash: testaide.RandomHash(),
+		Location: "/tmp/batch.bin",
+	}
+	batch.SetDACommitment(commitment)
+	err := s.bs.SetBlockBatch(s.ctx, batch)
+	s.Require().NoError(err)
+
+	stored, err := s.bs.GetBatchDACommitment(s.ctx, batch.Id)
+	s.Require().NoError(err)
+	s.Require().Equal(commitment, stored)
+
+	_, err = s.bs.GetBatchDACommitment(s.ctx, scTypes.NewBatchId())
+	s.Require().ErrorIs(err, scTypes.ErrBatchNotFound)
+}
+
 func (s *BlockStorageTestSuite) Test_LatestBatchId_Mismatch() {
 	const batchesCount = 2
 	batches := testaide.NewBatchesSequence(batchesCount)
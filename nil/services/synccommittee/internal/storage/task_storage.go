@@ -282,8 +282,11 @@ func (st *TaskStorage) GetTaskTreeView(ctx context.Context, rootTaskId types.Tas
 	return getTaskTreeRec(rootTaskId, 0)
 }
 
-// Helper to find available task with higher priority
-func (st *TaskStorage) findTopPriorityTask(tx db.RoTx) (*types.TaskEntry, error) {
+// Helper to find available task with higher priority matching the executor capabilities
+func (st *TaskStorage) findTopPriorityTask(
+	tx db.RoTx,
+	capabilities *types.ExecutorCapabilities,
+) (*types.TaskEntry, error) {
 	var topPriorityTask *types.TaskEntry
 
 	for entry, err := range st.getStoredTasksSeq(tx) {
@@ -291,7 +294,7 @@ func (st *TaskStorage) findTopPriorityTask(tx db.RoTx) (*types.TaskEntry, error)
 			return nil, err
 		}
 
-		if entry.Status != types.WaitingForExecutor {
+		if entry.Status != types.WaitingForExecutor || !capabilities.CanExecute(&entry.Task) {
 			continue
 		}
 
@@ -305,10 +308,20 @@ func (st *TaskStorage) findTopPriorityTask(tx db.RoTx) (*types.TaskEntry, error)
 
 // RequestTaskToExecute Find task with no dependencies and higher priority and assign it to the executor
 func (st *TaskStorage) RequestTaskToExecute(ctx context.Context, executor types.TaskExecutorId) (*types.Task, error) {
+	return st.RequestMatchingTaskToExecute(ctx, executor, nil)
+}
+
+// RequestMatchingTaskToExecute Find task with no dependencies and higher priority among the ones
+// matching the executor capabilities and assign it to the executor. Nil capabilities match any task.
+func (st *TaskStorage) RequestMatchingTaskToExecute(
+	ctx context.Context,
+	executor types.TaskExecutorId,
+	capabilities *types.ExecutorCapabilities,
+) (*types.Task, error) {
 	var taskEntry *types.TaskEntry
 	err := st.retryRunner.Do(ctx, func(ctx context.Context) error {
 		var err error
-		taskEntry, err = st.requestTaskToExecuteImpl(ctx, executor)
+		taskEntry, err = st.requestTaskToExecuteImpl(ctx, executor, capabilities)
 		return err
 	})
 	if err != nil {
@@ -325,6 +338,7 @@ func (st *TaskStorage) RequestTaskToExecute(ctx context.Context, executor types.
 func (st *TaskStorage) requestTaskToExecuteImpl(
 	ctx context.Context,
 	executor types.TaskExecutorId,
+	capabilities *types.ExecutorCapabilities,
 ) (*types.TaskEntry, error) {
 	tx, err := st.database.CreateRwTx(ctx)
 	if err != nil {
@@ -332,7 +346,7 @@ func (st *TaskStorage) requestTaskToExecuteImpl(
 	}
 	defer tx.Rollback()
 
-	taskEntry, err := st.findTopPriorityTask(tx)
+	taskEntry, err := st.findTopPriorityTask(tx, capabilities)
 	if err != nil {
 		return nil, err
 	}
@@ -354,6 +368,107 @@ func (st *TaskStorage) requestTaskToExecuteImpl(
 	return taskEntry, nil
 }
 
+// RenewTaskLease extends the lease of the running task held by the executor.
+// Returns false if the task doesn't exist or is not held by the executor anymore.
+func (st *TaskStorage) RenewTaskLease(
+	ctx context.Context,
+	taskId types.TaskId,
+	executor types.TaskExecutorId,
+) (bool, error) {
+	var renewed bool
+	err := st.retryRunner.Do(ctx, func(ctx context.Context) error {
+		var err error
+		renewed, err = st.renewTaskLeaseImpl(ctx, taskId, executor)
+		return err
+	})
+	return renewed, err
+}
+
+func (st *TaskStorage) renewTaskLeaseImpl(
+	ctx context.Context,
+	taskId types.TaskId,
+	executor types.TaskExecutorId,
+) (bool, error) {
+	tx, err := st.database.CreateRwTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	entry, err := st.extractTaskEntry(tx, taskId)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	err = entry.RenewLease(executor, st.clock.Now())
+	if errors.Is(err, types.ErrTaskWrongExecutor) || errors.Is(err, types.ErrTaskInvalidStatus) {
+		st.logger.Debug().Err(err).Stringer(logging.FieldTaskId, taskId).Msg("Task lease is not renewed")
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := st.putTaskEntry(tx, entry, false); err != nil {
+		return false, err
+	}
+	return true, st.commit(tx)
+}
+
+// SetBatchPriority sets the priority class of all the stored tasks of the batch.
+// Returns the number of updated tasks.
+func (st *TaskStorage) SetBatchPriority(
+	ctx context.Context,
+	batchId types.BatchId,
+	priority types.TaskPriority,
+) (uint, error) {
+	var count uint
+	err := st.retryRunner.Do(ctx, func(ctx context.Context) error {
+		var err error
+		count, err = st.setBatchPriorityImpl(ctx, batchId, priority)
+		return err
+	})
+	return count, err
+}
+
+func (st *TaskStorage) setBatchPriorityImpl(
+	ctx context.Context,
+	batchId types.BatchId,
+	priority types.TaskPriority,
+) (uint, error) {
+	tx, err := st.database.CreateRwTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var updated []*types.TaskEntry
+	for entry, err := range st.getStoredTasksSeq(tx) {
+		if err != nil {
+			return 0, err
+		}
+		if entry.Task.BatchId == batchId && entry.Task.Priority != priority {
+			entry.Task.Priority = priority
+			updated = append(updated, entry)
+		}
+	}
+
+	// entries are updated after the iteration is finished to not modify the table being iterated
+	for _, entry := range updated {
+		if err := st.putTaskEntry(tx, entry, false); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := st.commit(tx); err != nil {
+		return 0, err
+	}
+	return uint(len(updated)), nil
+}
+
 // ProcessTaskResult checks task result and updates dependencies in case of success
 func (st *TaskStorage) ProcessTaskResult(ctx context.Context, res *types.TaskResult) error {
 	return st.retryRunner.Do(ctx, func(ctx context.Context) error {
@@ -419,10 +534,13 @@ func (st *TaskStorage) terminateTaskTx(tx db.RwTx, entry *types.TaskEntry, res *
 	log.NewTaskResultEvent(st.logger, zerolog.DebugLevel, res).
 		Msgf("Task execution is completed with status %s, removing it from the storage", res.StatusStr())
 
-	// We don't keep finished tasks in DB
+	// We don't keep finished tasks in DB, only the compact record is put into the archive
 	if err := tx.Delete(taskEntriesTable, res.TaskId.Bytes()); err != nil {
 		return err
 	}
+	if err := st.putArchivedTaskTx(tx, types.NewArchivedTask(entry, res), true); err != nil {
+		return err
+	}
 
 	if !res.IsSuccess() {
 		if err := st.putTaskEntry(tx, entry, true); err != nil {
@@ -466,12 +584,18 @@ type rescheduledTask struct {
 	previousExecutor types.TaskExecutorId
 }
 
-// RescheduleHangingTasks finds tasks that exceed execution timeout and reschedules them to be re-executed later.
-func (st *TaskStorage) RescheduleHangingTasks(ctx context.Context, taskExecutionTimeout time.Duration) error {
+// RescheduleHangingTasks finds tasks whose executors are considered lost and reschedules them to be re-executed later.
+// Tasks with renewed leases are checked against taskLeaseTimeout since the last renewal,
+// the other ones are checked against taskExecutionTimeout since the execution start.
+func (st *TaskStorage) RescheduleHangingTasks(
+	ctx context.Context,
+	taskExecutionTimeout time.Duration,
+	taskLeaseTimeout time.Duration,
+) error {
 	var rescheduled []rescheduledTask
 	err := st.retryRunner.Do(ctx, func(ctx context.Context) error {
 		var err error
-		rescheduled, err = st.rescheduleHangingTasksImpl(ctx, taskExecutionTimeout)
+		rescheduled, err = st.rescheduleHangingTasksImpl(ctx, taskExecutionTimeout, taskLeaseTimeout)
 		return err
 	})
 	if err != nil {
@@ -487,6 +611,7 @@ func (st *TaskStorage) RescheduleHangingTasks(ctx context.Context, taskExecution
 func (st *TaskStorage) rescheduleHangingTasksImpl(
 	ctx context.Context,
 	taskExecutionTimeout time.Duration,
+	taskLeaseTimeout time.Duration,
 ) (rescheduled []rescheduledTask, err error) {
 	tx, err := st.database.CreateRwTx(ctx)
 	if err != nil {
@@ -505,15 +630,13 @@ loop:
 		case len(rescheduled) == rescheduledTasksPerTxLimit:
 			break loop
 
-		case entry.Status != types.Running:
-			continue
-
-		case *entry.ExecutionTime(currentTime) <= taskExecutionTimeout:
-			continue
-
 		default:
+			timeoutErr := entry.CheckHanging(currentTime, taskExecutionTimeout, taskLeaseTimeout)
+			if timeoutErr == nil {
+				continue
+			}
+
 			previousExecutor := entry.Owner
-			timeoutErr := types.NewTaskErrTimeout(*entry.ExecutionTime(currentTime), taskExecutionTimeout)
 			if err := st.rescheduleTaskTx(tx, entry, timeoutErr); err != nil {
 				return nil, err
 			}
@@ -591,9 +714,17 @@ func (st *TaskStorage) CancelTasksByParentId(
 	return count, nil
 }
 
-func (*TaskStorage) getStoredTasksSeq(tx db.RoTx) iter.Seq2[*types.TaskEntry, error] {
+func (st *TaskStorage) getStoredTasksSeq(tx db.RoTx) iter.Seq2[*types.TaskEntry, error] {
+	return st.getTasksSeq(tx, taskEntriesTable)
+}
+
+func (st *TaskStorage) getFailedTasksSeq(tx db.RoTx) iter.Seq2[*types.TaskEntry, error] {
+	return st.getTasksSeq(tx, failedTaskEntriesTable)
+}
+
+func (*TaskStorage) getTasksSeq(tx db.RoTx, tableName db.TableName) iter.Seq2[*types.TaskEntry, error] {
 	return func(yield func(*types.TaskEntry, error) bool) {
-		txIter, err := tx.Range(taskEntriesTable, nil, nil)
+		txIter, err := tx.Range(tableName, nil, nil)
 		if err != nil {
 			yield(nil, err)
 			return
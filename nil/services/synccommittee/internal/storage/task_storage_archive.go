@@ -0,0 +1,256 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
+)
+
+const (
+	// taskArchiveTable BadgerDB table, BatchId + TaskId is used as a key
+	taskArchiveTable db.TableName = "task_archive"
+
+	// archivedBatchesPerTxLimit defines the maximum number of batches that can be archived
+	// in a single transaction of TaskStorage.ArchiveTerminatedBatches.
+	archivedBatchesPerTxLimit = 10
+)
+
+// retainedBatch holds the task entries of a batch kept in the task tables.
+type retainedBatch struct {
+	batchId     types.BatchId
+	lastCreated time.Time
+	hasActive   bool
+	entries     []*types.TaskEntry
+	failed      []*types.TaskEntry
+}
+
+func (b *retainedBatch) add(entry *types.TaskEntry, isFailedTask bool) {
+	if entry.Created.After(b.lastCreated) {
+		b.lastCreated = entry.Created
+	}
+	if isFailedTask {
+		b.failed = append(b.failed, entry)
+		return
+	}
+	b.entries = append(b.entries, entry)
+	if entry.Status == types.Running || entry.Status == types.WaitingForExecutor {
+		b.hasActive = true
+	}
+}
+
+// ArchiveTerminatedBatches moves the task trees of batches exceeding the retention limit into the task archive.
+// Batches are ordered by the creation time of their latest task, the trees of the retainedBatches
+// most recent batches and the trees with tasks which are still to be executed are kept.
+// Returns the number of archived batches.
+func (st *TaskStorage) ArchiveTerminatedBatches(ctx context.Context, retainedBatches uint32) (uint, error) {
+	var archived uint
+	err := st.retryRunner.Do(ctx, func(ctx context.Context) error {
+		var err error
+		archived, err = st.archiveTerminatedBatchesImpl(ctx, retainedBatches)
+		return err
+	})
+	return archived, err
+}
+
+func (st *TaskStorage) archiveTerminatedBatchesImpl(ctx context.Context, retainedBatches uint32) (uint, error) {
+	tx, err := st.database.CreateRwTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	batches := make(map[types.BatchId]*retainedBatch)
+	addEntries := func(entries iter.Seq2[*types.TaskEntry, error], isFailedTask bool) error {
+		for entry, err := range entries {
+			if err != nil {
+				return err
+			}
+			batch, ok := batches[entry.Task.BatchId]
+			if !ok {
+				batch = &retainedBatch{batchId: entry.Task.BatchId}
+				batches[entry.Task.BatchId] = batch
+			}
+			batch.add(entry, isFailedTask)
+		}
+		return nil
+	}
+	if err := addEntries(st.getStoredTasksSeq(tx), false); err != nil {
+		return 0, err
+	}
+	if err := addEntries(st.getFailedTasksSeq(tx), true); err != nil {
+		return 0, err
+	}
+
+	if len(batches) <= int(retainedBatches) {
+		return 0, nil
+	}
+
+	ordered := slices.SortedFunc(maps.Values(batches), func(l, r *retainedBatch) int {
+		return r.lastCreated.Compare(l.lastCreated)
+	})
+
+	var archived uint
+	for _, batch := range ordered[retainedBatches:] {
+		if archived == archivedBatchesPerTxLimit {
+			break
+		}
+		if batch.hasActive {
+			continue
+		}
+		if err := st.archiveBatchTx(tx, batch); err != nil {
+			return 0, err
+		}
+		archived++
+	}
+
+	if err := st.commit(tx); err != nil {
+		return 0, err
+	}
+	return archived, nil
+}
+
+func (st *TaskStorage) archiveBatchTx(tx db.RwTx, batch *retainedBatch) error {
+	st.logger.Debug().
+		Stringer(logging.FieldBatchId, batch.batchId).
+		Int("taskEntries", len(batch.entries)).
+		Int("failedTaskEntries", len(batch.failed)).
+		Msg("Archiving batch task tree")
+
+	for _, entry := range batch.entries {
+		// remaining entries are the abandoned dependents of the failed tasks
+		if err := st.putArchivedTaskTx(tx, types.NewArchivedTask(entry, nil), false); err != nil {
+			return err
+		}
+		if err := tx.Delete(taskEntriesTable, st.makeTaskKey(entry)); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range batch.failed {
+		// failed tasks are archived on termination, the record is only added for the entries stored before that
+		if err := st.putArchivedTaskTx(tx, types.NewArchivedTask(entry, nil), false); err != nil {
+			return err
+		}
+		if err := tx.Delete(failedTaskEntriesTable, st.makeTaskKey(entry)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetArchivedTaskTrees retrieves the archived task trees matching the given predicate, at most limit trees are returned.
+// If batchId is set, only the tree of the corresponding batch is checked.
+func (st *TaskStorage) GetArchivedTaskTrees(
+	ctx context.Context,
+	batchId *types.BatchId,
+	predicate func(*public.ArchivedTaskTree) bool,
+	limit int,
+) ([]*public.ArchivedTaskTree, error) {
+	tx, err := st.database.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var prefix []byte
+	if batchId != nil {
+		prefix = batchId.Bytes()
+	}
+
+	var trees []*public.ArchivedTaskTree
+	var current *public.ArchivedTaskTree
+	flush := func() {
+		if current != nil && predicate(current) {
+			trees = append(trees, current)
+		}
+		current = nil
+	}
+
+	// archived tasks are ordered by the batch id, so the tasks of each tree are iterated sequentially
+	for task, err := range st.getArchivedTasksSeq(tx, prefix) {
+		if err != nil {
+			return nil, err
+		}
+		if current != nil && current.BatchId != task.BatchId {
+			flush()
+			if len(trees) == limit {
+				return trees, nil
+			}
+		}
+		if current == nil {
+			current = &public.ArchivedTaskTree{BatchId: task.BatchId}
+		}
+		current.Tasks = append(current.Tasks, task)
+	}
+	flush()
+
+	return trees, nil
+}
+
+// Helper to encode and put archived task into DB, existing records are kept unless overwrite is set
+func (*TaskStorage) putArchivedTaskTx(tx db.RwTx, task *types.ArchivedTask, overwrite bool) error {
+	key := makeArchivedTaskKey(task.BatchId, task.Id)
+	if !overwrite {
+		exists, err := tx.Exists(taskArchiveTable, key)
+		if err != nil || exists {
+			return err
+		}
+	}
+
+	var inputBuffer bytes.Buffer
+	if err := gob.NewEncoder(&inputBuffer).Encode(task); err != nil {
+		return fmt.Errorf("%w: failed to encode archived task with id %s: %w", ErrSerializationFailed, task.Id, err)
+	}
+	if err := tx.Put(taskArchiveTable, key, inputBuffer.Bytes()); err != nil {
+		return fmt.Errorf("failed to put archived task with id %s: %w", task.Id, err)
+	}
+	return nil
+}
+
+func (*TaskStorage) getArchivedTasksSeq(tx db.RoTx, prefix []byte) iter.Seq2[*types.ArchivedTask, error] {
+	return func(yield func(*types.ArchivedTask, error) bool) {
+		txIter, err := tx.Range(taskArchiveTable, prefix, nil)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer txIter.Close()
+
+		for txIter.HasNext() {
+			key, val, err := txIter.Next()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !bytes.HasPrefix(key, prefix) {
+				return
+			}
+
+			task := &types.ArchivedTask{}
+			if err = gob.NewDecoder(bytes.NewBuffer(val)).Decode(task); err != nil {
+				err = fmt.Errorf("%w: failed to decode archived task %v: %w", ErrSerializationFailed, string(key), err)
+				yield(nil, err)
+				return
+			}
+
+			if !yield(task, nil) {
+				return
+			}
+		}
+	}
+}
+
+func makeArchivedTaskKey(batchId types.BatchId, taskId types.TaskId) []byte {
+	return append(batchId.Bytes(), taskId.Bytes()...)
+}
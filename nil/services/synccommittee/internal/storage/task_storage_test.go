@@ -12,12 +12,14 @@ import (
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/metrics"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/testaide"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/public"
 	"github.com/jonboulle/clockwork"
 	"github.com/stretchr/testify/suite"
 )
 
 const (
 	degreeOfParallelism = 10
+	leaseTimeout        = 10 * time.Second
 )
 
 type TaskStorageSuite struct {
@@ -148,7 +150,7 @@ func (s *TaskStorageSuite) Test_ProcessTaskResult_Retryable_Error() {
 
 func (s *TaskStorageSuite) Test_TaskRescheduling_NoEntries() {
 	executionTimeout := time.Minute
-	err := s.ts.RescheduleHangingTasks(s.ctx, executionTimeout)
+	err := s.ts.RescheduleHangingTasks(s.ctx, executionTimeout, leaseTimeout)
 	s.Require().NoError(err)
 
 	taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
@@ -168,7 +170,7 @@ func (s *TaskStorageSuite) Test_TaskRescheduling_NoActiveTasks() {
 	err := s.ts.AddTaskEntries(s.ctx, entries...)
 	s.Require().NoError(err)
 
-	err = s.ts.RescheduleHangingTasks(s.ctx, executionTimeout)
+	err = s.ts.RescheduleHangingTasks(s.ctx, executionTimeout, leaseTimeout)
 	s.Require().NoError(err)
 
 	// All existing tasks are still available for execution
@@ -188,7 +190,7 @@ func (s *TaskStorageSuite) Test_TaskRescheduling_SingleActiveTask() {
 	err := s.ts.AddTaskEntries(s.ctx, activeEntry)
 	s.Require().NoError(err)
 
-	err = s.ts.RescheduleHangingTasks(s.ctx, executionTimeout)
+	err = s.ts.RescheduleHangingTasks(s.ctx, executionTimeout, leaseTimeout)
 	s.Require().NoError(err)
 
 	// Active task wasn't rescheduled
@@ -211,7 +213,7 @@ func (s *TaskStorageSuite) Test_TaskRescheduling_MultipleTasks() {
 	)
 	s.Require().NoError(err)
 
-	err = s.ts.RescheduleHangingTasks(s.ctx, executionTimeout)
+	err = s.ts.RescheduleHangingTasks(s.ctx, executionTimeout, leaseTimeout)
 	s.Require().NoError(err)
 
 	// Outdated task was rescheduled and became available for execution
@@ -226,6 +228,211 @@ func (s *TaskStorageSuite) Test_TaskRescheduling_MultipleTasks() {
 	s.Require().Nil(taskToExecute)
 }
 
+func (s *TaskStorageSuite) Test_TaskRescheduling_RenewedLease() {
+	now := s.clock.Now()
+	executionTimeout := time.Minute
+	executorId := testaide.RandomExecutorId()
+
+	longRunningEntry := testaide.NewTaskEntry(now.Add(-executionTimeout*2), types.Running, executorId)
+
+	lostEntry := testaide.NewTaskEntry(now.Add(-time.Second), types.Running, testaide.RandomExecutorId())
+	lastRenewal := now.Add(-leaseTimeout * 2)
+	lostEntry.LeaseRenewed = &lastRenewal
+
+	err := s.ts.AddTaskEntries(s.ctx, longRunningEntry, lostEntry)
+	s.Require().NoError(err)
+
+	// Lease can't be renewed by another executor
+	renewed, err := s.ts.RenewTaskLease(s.ctx, longRunningEntry.Task.Id, testaide.RandomExecutorId())
+	s.Require().NoError(err)
+	s.Require().False(renewed)
+
+	renewed, err = s.ts.RenewTaskLease(s.ctx, longRunningEntry.Task.Id, executorId)
+	s.Require().NoError(err)
+	s.Require().True(renewed)
+
+	renewed, err = s.ts.RenewTaskLease(s.ctx, types.NewTaskId(), executorId)
+	s.Require().NoError(err)
+	s.Require().False(renewed)
+
+	err = s.ts.RescheduleHangingTasks(s.ctx, executionTimeout, leaseTimeout)
+	s.Require().NoError(err)
+
+	// Task with an expired lease was rescheduled
+	taskToExecute, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	s.Require().NoError(err)
+	s.Require().NotNil(taskToExecute)
+	s.Require().Equal(lostEntry.Task, *taskToExecute)
+
+	// Task exceeding the execution timeout wasn't rescheduled as its lease is renewed
+	fromStorage, err := s.ts.TryGetTaskEntry(s.ctx, longRunningEntry.Task.Id)
+	s.Require().NoError(err)
+	s.Require().Equal(types.Running, fromStorage.Status)
+	s.Require().Equal(executorId, fromStorage.Owner)
+}
+
+func (s *TaskStorageSuite) Test_RequestMatchingTaskToExecute() {
+	now := s.clock.Now()
+
+	partialProveEntry := testaide.NewTaskEntryOfType(
+		types.PartialProve, now.Add(-time.Minute), types.WaitingForExecutor, types.UnknownExecutorId)
+	partialProveEntry.Task.CircuitType = types.CircuitBytecode
+	mergeProofEntry := testaide.NewTaskEntryOfType(
+		types.MergeProof, now, types.WaitingForExecutor, types.UnknownExecutorId)
+
+	err := s.ts.AddTaskEntries(s.ctx, partialProveEntry, mergeProofEntry)
+	s.Require().NoError(err)
+
+	// Executor without enough memory gets the task created later
+	lowMemory := &types.ExecutorCapabilities{MemoryClass: types.MemoryClassLow}
+	task, err := s.ts.RequestMatchingTaskToExecute(s.ctx, testaide.RandomExecutorId(), lowMemory)
+	s.Require().NoError(err)
+	s.Require().NotNil(task)
+	s.Require().Equal(mergeProofEntry.Task.Id, task.Id)
+
+	// Tasks of other circuits are not assigned
+	otherCircuit := &types.ExecutorCapabilities{
+		TaskTypes:    []types.TaskType{types.PartialProve},
+		CircuitTypes: []types.CircuitType{types.CircuitReadWrite},
+		MemoryClass:  types.MemoryClassHigh,
+	}
+	task, err = s.ts.RequestMatchingTaskToExecute(s.ctx, testaide.RandomExecutorId(), otherCircuit)
+	s.Require().NoError(err)
+	s.Require().Nil(task)
+
+	otherCircuit.CircuitTypes = append(otherCircuit.CircuitTypes, types.CircuitBytecode)
+	task, err = s.ts.RequestMatchingTaskToExecute(s.ctx, testaide.RandomExecutorId(), otherCircuit)
+	s.Require().NoError(err)
+	s.Require().NotNil(task)
+	s.Require().Equal(partialProveEntry.Task.Id, task.Id)
+}
+
+func (s *TaskStorageSuite) Test_SetBatchPriority() {
+	now := s.clock.Now()
+
+	olderEntry := testaide.NewTaskEntry(now.Add(-time.Minute), types.WaitingForExecutor, types.UnknownExecutorId)
+	newerEntry := testaide.NewTaskEntry(now, types.WaitingForExecutor, types.UnknownExecutorId)
+
+	err := s.ts.AddTaskEntries(s.ctx, olderEntry, newerEntry)
+	s.Require().NoError(err)
+
+	updated, err := s.ts.SetBatchPriority(s.ctx, newerEntry.Task.BatchId, types.PriorityHigh)
+	s.Require().NoError(err)
+	s.Require().Equal(uint(1), updated)
+
+	// Task of the batch with a higher priority class goes first regardless of its creation time
+	task, err := s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	s.Require().NoError(err)
+	s.Require().NotNil(task)
+	s.Require().Equal(newerEntry.Task.Id, task.Id)
+	s.Require().Equal(types.PriorityHigh, task.Priority)
+
+	task, err = s.ts.RequestTaskToExecute(s.ctx, testaide.RandomExecutorId())
+	s.Require().NoError(err)
+	s.Require().NotNil(task)
+	s.Require().Equal(olderEntry.Task.Id, task.Id)
+}
+
+func (s *TaskStorageSuite) Test_ArchiveTerminatedBatches() {
+	now := s.clock.Now()
+	executorId := testaide.RandomExecutorId()
+
+	// Old batch: the failed dependency leaves its parent waiting for input forever
+	parent := testaide.NewTaskEntry(now.Add(-time.Hour), types.WaitingForInput, types.UnknownExecutorId)
+	child := testaide.NewTaskEntry(now.Add(-time.Hour), types.Running, executorId)
+	child.Task.BatchId = parent.Task.BatchId
+	parent.AddDependency(child)
+
+	// Recent batch is still being executed
+	active := testaide.NewTaskEntry(now, types.WaitingForExecutor, types.UnknownExecutorId)
+
+	err := s.ts.AddTaskEntries(s.ctx, parent, child, active)
+	s.Require().NoError(err)
+
+	err = s.ts.ProcessTaskResult(
+		s.ctx,
+		types.NewFailureProverTaskResult(
+			child.Task.Id, executorId, types.NewTaskExecError(types.TaskErrProofGenerationFailed, "something went wrong")),
+	)
+	s.Require().NoError(err)
+
+	// Both batches are within the retention limit
+	archived, err := s.ts.ArchiveTerminatedBatches(s.ctx, 2)
+	s.Require().NoError(err)
+	s.Require().Zero(archived)
+
+	// Active batch is never archived
+	archived, err = s.ts.ArchiveTerminatedBatches(s.ctx, 0)
+	s.Require().NoError(err)
+	s.Require().Equal(uint(1), archived)
+
+	for _, entry := range []*types.TaskEntry{parent, child} {
+		fromStorage, err := s.ts.TryGetTaskEntry(s.ctx, entry.Task.Id)
+		s.Require().NoError(err)
+		s.Require().Nil(fromStorage)
+	}
+	activeFromStorage, err := s.ts.TryGetTaskEntry(s.ctx, active.Task.Id)
+	s.Require().NoError(err)
+	s.Require().NotNil(activeFromStorage)
+
+	trees, err := s.ts.GetArchivedTaskTrees(
+		s.ctx, &parent.Task.BatchId, func(*public.ArchivedTaskTree) bool { return true }, 10)
+	s.Require().NoError(err)
+	s.Require().Len(trees, 1)
+	s.Require().Equal(parent.Task.BatchId, trees[0].BatchId)
+	s.Require().Len(trees[0].Tasks, 2)
+
+	archivedTasks := make(map[types.TaskId]*types.ArchivedTask)
+	for _, task := range trees[0].Tasks {
+		archivedTasks[task.Id] = task
+	}
+	s.Require().Equal(types.Failed, archivedTasks[child.Task.Id].Status)
+	s.Require().Equal(executorId, archivedTasks[child.Task.Id].Owner)
+	s.Require().Equal("something went wrong", archivedTasks[child.Task.Id].ErrorText)
+	s.Require().Equal(types.WaitingForInput, archivedTasks[parent.Task.Id].Status)
+	s.Require().Equal([]types.TaskId{child.Task.Id}, archivedTasks[parent.Task.Id].Dependencies)
+}
+
+func (s *TaskStorageSuite) Test_GetArchivedTaskTrees() {
+	now := s.clock.Now()
+	executorId := testaide.RandomExecutorId()
+
+	var entries []*types.TaskEntry
+	for i := range 3 {
+		entries = append(entries, testaide.NewTaskEntry(now.Add(time.Duration(i)*time.Minute), types.Running, executorId))
+	}
+	err := s.ts.AddTaskEntries(s.ctx, entries...)
+	s.Require().NoError(err)
+
+	// completed tasks are archived on termination
+	for _, entry := range entries {
+		err = s.ts.ProcessTaskResult(
+			s.ctx, types.NewSuccessProverTaskResult(entry.Task.Id, executorId, nil, nil))
+		s.Require().NoError(err)
+	}
+
+	all := func(*public.ArchivedTaskTree) bool { return true }
+	trees, err := s.ts.GetArchivedTaskTrees(s.ctx, nil, all, 10)
+	s.Require().NoError(err)
+	s.Require().Len(trees, 3)
+
+	trees, err = s.ts.GetArchivedTaskTrees(s.ctx, nil, all, 2)
+	s.Require().NoError(err)
+	s.Require().Len(trees, 2)
+
+	trees, err = s.ts.GetArchivedTaskTrees(s.ctx, &entries[1].Task.BatchId, all, 10)
+	s.Require().NoError(err)
+	s.Require().Len(trees, 1)
+	s.Require().Equal(entries[1].Task.Id, trees[0].Tasks[0].Id)
+	s.Require().Equal(types.Completed, trees[0].Tasks[0].Status)
+
+	trees, err = s.ts.GetArchivedTaskTrees(s.ctx, nil, func(tree *public.ArchivedTaskTree) bool {
+		return tree.HasOwner(testaide.RandomExecutorId())
+	}, 10)
+	s.Require().NoError(err)
+	s.Require().Empty(trees)
+}
+
 func (s *TaskStorageSuite) Test_AddSingleTaskEntry_Concurrently() {
 	now := s.clock.Now()
 
@@ -11,6 +11,7 @@ import (
 	"github.com/NilFoundation/nil/nil/client"
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	scTypes "github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
 )
 
@@ -75,6 +76,20 @@ func ClientMockSetBlocks(client *client.ClientMock, blocks iter.Seq[*scTypes.Blo
 		return idToBlocks[id], nil
 	}
 
+	client.GetDebugBlockFunc = func(
+		_ context.Context, shardId types.ShardId, blockId any, _ bool,
+	) (*jsonrpc.DebugRPCBlock, error) {
+		blockHash, ok := blockId.(common.Hash)
+		if !ok {
+			return nil, fmt.Errorf("unexpected blockId type: %v", blockId)
+		}
+		block, ok := idToBlocks[scTypes.NewBlockId(shardId, blockHash)]
+		if !ok {
+			return nil, nil
+		}
+		return NewDebugBlock(block)
+	}
+
 	client.GetBlocksRangeFunc = func(
 		_ context.Context, shardId types.ShardId, from types.BlockNumber, to types.BlockNumber, _ bool, _ int,
 	) ([]*scTypes.Block, error) {
@@ -92,3 +107,44 @@ func ClientMockSetBlocks(client *client.ClientMock, blocks iter.Seq[*scTypes.Blo
 		return shardIds, nil
 	}
 }
+
+// NewDebugBlock encodes the block data the way the debug API returns it.
+// The hash of the encoded block doesn't match the random hash of the test block.
+func NewDebugBlock(block *scTypes.Block) (*jsonrpc.DebugRPCBlock, error) {
+	data := &types.BlockWithExtractedData{
+		Block: &types.Block{
+			BlockData: types.BlockData{
+				Id:              block.Number,
+				PrevBlock:       block.ParentHash,
+				MainShardHash:   block.MainShardHash,
+				BaseFee:         types.NewZeroValue(),
+				PatchLevel:      block.PatchLevel,
+				RollbackCounter: block.RollbackCounter,
+			},
+		},
+		ChildBlocks: block.ChildBlocks,
+	}
+	for _, txn := range block.Transactions {
+		data.InTransactions = append(data.InTransactions, &types.Transaction{
+			TransactionDigest: types.TransactionDigest{
+				Flags:                txn.Flags,
+				FeeCredit:            types.NewZeroValue(),
+				MaxPriorityFeePerGas: types.NewZeroValue(),
+				MaxFeePerGas:         types.NewZeroValue(),
+				To:                   txn.To,
+				Seqno:                types.Seqno(txn.Seqno),
+				Data:                 types.Code(txn.Data),
+			},
+			From:     txn.From,
+			RefundTo: txn.RefundTo,
+			BounceTo: txn.BounceTo,
+			Value:    txn.Value,
+		})
+	}
+
+	raw, err := data.EncodeSSZ()
+	if err != nil {
+		return nil, err
+	}
+	return jsonrpc.EncodeRawBlockWithExtractedData(raw)
+}
@@ -54,6 +54,8 @@ type BlockBatch struct {
 	ParentId   *BatchId   `json:"parentId"`
 	Subgraphs  []Subgraph `json:"subgraphs"`
 	DataProofs DataProofs `json:"dataProofs"`
+	// DACommitment references the published batch data, it's set once the data is prepared for publishing
+	DACommitment *DACommitment `json:"daCommitment,omitempty"`
 }
 
 func NewBlockBatch(parentId *BatchId, subgraphs ...Subgraph) (*BlockBatch, error) {
@@ -222,9 +224,15 @@ func (b *BlockBatch) SetDataProofs(dataProofs DataProofs) {
 	b.DataProofs = dataProofs
 }
 
+func (b *BlockBatch) SetDACommitment(commitment *DACommitment) {
+	b.DACommitment = commitment
+}
+
 type PrunedBatch struct {
 	BatchId BatchId
 	Blocks  []*PrunedBlock
+	// StateDiff is the state changed by the batch blocks, it is set only if the encoder requires it
+	StateDiff StateDiff
 }
 
 func NewPrunedBatch(batch *BlockBatch) *PrunedBatch {
@@ -9,6 +9,8 @@ import (
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 )
@@ -224,6 +226,14 @@ type PrunedBlock struct {
 	Timestamp     uint64
 	PrevBlockHash common.Hash
 	Transactions  []PrunedTransaction
+
+	// The fields below are set by NewExecutablePrunedBlock only, they are required to re-execute the block.
+	MainShardHash       common.Hash
+	ChildBlocks         []common.Hash
+	PatchLevel          uint32
+	RollbackCounter     uint32
+	GasPrices           []types.Uint256
+	ForwardTransactions []PrunedTransaction
 }
 
 func NewPrunedBlock(block *Block) *PrunedBlock {
@@ -236,6 +246,67 @@ func NewPrunedBlock(block *Block) *PrunedBlock {
 	}
 }
 
+// NewExecutablePrunedBlock keeps everything the block is generated from, so that the block can be re-executed:
+// the incoming transactions with all their fields, the forwarded transactions, the child blocks
+// and the gas prices set by the main shard block.
+func NewExecutablePrunedBlock(block *Block, data *types.BlockWithExtractedData) (*PrunedBlock, error) {
+	if data.Id != block.Number {
+		return nil, fmt.Errorf("%w: block data number is %d, expected %d", ErrBlockMismatch, data.Id, block.Number)
+	}
+
+	pruned := &PrunedBlock{
+		ShardId:         block.ShardId,
+		BlockNumber:     block.Number,
+		Timestamp:       block.DbTimestamp,
+		PrevBlockHash:   block.ParentHash,
+		Transactions:    make([]PrunedTransaction, len(data.InTransactions)),
+		MainShardHash:   data.MainShardHash,
+		ChildBlocks:     data.ChildBlocks,
+		PatchLevel:      data.PatchLevel,
+		RollbackCounter: data.RollbackCounter,
+	}
+	for i, txn := range data.InTransactions {
+		pruned.Transactions[i] = NewPrunedTransaction(txn)
+	}
+
+	forwarded, _ := execution.SplitOutTransactions(data.OutTransactions, block.ShardId)
+	for _, txn := range forwarded {
+		pruned.ForwardTransactions = append(pruned.ForwardTransactions, NewPrunedTransaction(txn))
+	}
+
+	if gasPrices, ok := data.Config[config.NameGasPrice]; ok && block.ShardId.IsMainShard() {
+		param := &config.ParamGasPrice{}
+		if err := param.UnmarshalSSZ(gasPrices); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal gas prices: %w", err)
+		}
+		pruned.GasPrices = param.Shards
+	}
+	return pruned, nil
+}
+
+// Proposal builds the proposal the block is generated from.
+func (b *PrunedBlock) Proposal() *execution.Proposal {
+	proposal := &execution.Proposal{
+		PrevBlockId:     b.BlockNumber - 1,
+		PrevBlockHash:   b.PrevBlockHash,
+		PatchLevel:      b.PatchLevel,
+		RollbackCounter: b.RollbackCounter,
+		MainShardHash:   b.MainShardHash,
+		ShardHashes:     b.ChildBlocks,
+	}
+
+	transactions := make([]*types.Transaction, len(b.Transactions))
+	for i := range b.Transactions {
+		transactions[i] = b.Transactions[i].Transaction()
+	}
+	proposal.InternalTxns, proposal.ExternalTxns = execution.SplitInTransactions(transactions)
+
+	for i := range b.ForwardTransactions {
+		proposal.ForwardTxns = append(proposal.ForwardTxns, b.ForwardTransactions[i].Transaction())
+	}
+	return proposal
+}
+
 type PrunedTransaction struct {
 	Flags    types.TransactionFlags
 	Seqno    hexutil.Uint64
@@ -245,6 +316,64 @@ type PrunedTransaction struct {
 	RefundTo types.Address
 	Value    types.Value
 	Data     hexutil.Bytes
+
+	// The fields below are set by NewPrunedTransaction only, they are required to re-execute the transaction.
+	FeeCredit            types.Value
+	MaxPriorityFeePerGas types.Value
+	MaxFeePerGas         types.Value
+	ChainId              types.ChainId
+	TxId                 types.TransactionIndex
+	Token                []types.TokenBalance
+	RequestId            uint64
+	RequestChain         []*types.AsyncRequestInfo
+	Signature            types.Signature
+}
+
+func NewPrunedTransaction(txn *types.Transaction) PrunedTransaction {
+	return PrunedTransaction{
+		Flags:                txn.Flags,
+		Seqno:                hexutil.Uint64(txn.Seqno),
+		From:                 txn.From,
+		To:                   txn.To,
+		BounceTo:             txn.BounceTo,
+		RefundTo:             txn.RefundTo,
+		Value:                txn.Value,
+		Data:                 hexutil.Bytes(txn.Data),
+		FeeCredit:            txn.FeeCredit,
+		MaxPriorityFeePerGas: txn.MaxPriorityFeePerGas,
+		MaxFeePerGas:         txn.MaxFeePerGas,
+		ChainId:              txn.ChainId,
+		TxId:                 txn.TxId,
+		Token:                txn.Token,
+		RequestId:            txn.RequestId,
+		RequestChain:         txn.RequestChain,
+		Signature:            txn.Signature,
+	}
+}
+
+// Transaction restores the transaction, it's complete only if the pruned one is created by NewPrunedTransaction.
+func (t *PrunedTransaction) Transaction() *types.Transaction {
+	return &types.Transaction{
+		TransactionDigest: types.TransactionDigest{
+			Flags:                t.Flags,
+			FeeCredit:            t.FeeCredit,
+			MaxPriorityFeePerGas: t.MaxPriorityFeePerGas,
+			MaxFeePerGas:         t.MaxFeePerGas,
+			To:                   t.To,
+			ChainId:              t.ChainId,
+			Seqno:                types.Seqno(t.Seqno),
+			Data:                 types.Code(t.Data),
+		},
+		From:         t.From,
+		TxId:         t.TxId,
+		RefundTo:     t.RefundTo,
+		BounceTo:     t.BounceTo,
+		Value:        t.Value,
+		Token:        t.Token,
+		RequestId:    t.RequestId,
+		RequestChain: t.RequestChain,
+		Signature:    t.Signature,
+	}
 }
 
 func BlockTransactions(block *Block) []PrunedTransaction {
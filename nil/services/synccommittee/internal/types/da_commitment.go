@@ -0,0 +1,28 @@
+package types
+
+import (
+	"github.com/NilFoundation/nil/nil/common"
+)
+
+// DABackend is the data availability backend the batch data is published with.
+type DABackend string
+
+const (
+	// DABackendL1Blob publishes the batch data as EIP-4844 blobs committed to the rollup contract
+	DABackendL1Blob DABackend = "l1-blob"
+	// DABackendL1Calldata publishes the batch data as calldata of a regular L1 transaction
+	DABackendL1Calldata DABackend = "l1-calldata"
+	// DABackendLocal stores the batch data in the local filesystem
+	DABackendLocal DABackend = "local"
+)
+
+// DACommitment references the published data of a batch.
+type DACommitment struct {
+	Backend DABackend `json:"backend"`
+	// DataHash is the keccak256 hash of the encoded batch data
+	DataHash common.Hash `json:"dataHash"`
+	// BlobHashes are the versioned hashes of the blobs holding the data, set by the l1-blob backend only
+	BlobHashes []common.Hash `json:"blobHashes,omitempty"`
+	// Location is the path of the file holding the data, set by the local backend only
+	Location string `json:"location,omitempty"`
+}
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// MemoryClass is the amount of resources available to an executor or required by a task.
+type MemoryClass uint8
+
+const (
+	// MemoryClassNone means the executor doesn't advertise its resources and is able to handle any task
+	MemoryClassNone MemoryClass = iota
+	MemoryClassLow
+	MemoryClassMedium
+	MemoryClassHigh
+)
+
+var MemoryClasses = map[string]MemoryClass{
+	"Low":    MemoryClassLow,
+	"Medium": MemoryClassMedium,
+	"High":   MemoryClassHigh,
+}
+
+func (c *MemoryClass) Set(str string) error {
+	if v, ok := MemoryClasses[str]; ok {
+		*c = v
+		return nil
+	}
+	return fmt.Errorf("unknown memory class: %s", str)
+}
+
+func (*MemoryClass) Type() string {
+	return "MemoryClass"
+}
+
+func (*MemoryClass) PossibleValues() []string {
+	return slices.Collect(maps.Keys(MemoryClasses))
+}
+
+// taskMemoryClasses defines the resources required by the task types, the other types require MemoryClassLow.
+var taskMemoryClasses = map[TaskType]MemoryClass{
+	PartialProve:         MemoryClassHigh,
+	AggregatedFRI:        MemoryClassMedium,
+	CombinedQ:            MemoryClassMedium,
+	FRIConsistencyChecks: MemoryClassMedium,
+}
+
+// RequiredMemoryClass returns the minimal memory class of an executor able to handle the task.
+func (t *Task) RequiredMemoryClass() MemoryClass {
+	if class, ok := taskMemoryClasses[t.TaskType]; ok {
+		return class
+	}
+	return MemoryClassLow
+}
+
+// ExecutorCapabilities describes the tasks an executor is able to handle.
+// Empty lists mean that any value is supported.
+type ExecutorCapabilities struct {
+	TaskTypes    []TaskType    `json:"taskTypes,omitempty"`
+	CircuitTypes []CircuitType `json:"circuitTypes,omitempty"`
+	MemoryClass  MemoryClass   `json:"memoryClass,omitempty"`
+}
+
+// CanExecute checks if the task matches the capabilities.
+// Tasks which are not bound to a circuit (CircuitType is None) match any circuit list.
+func (c *ExecutorCapabilities) CanExecute(task *Task) bool {
+	if c == nil {
+		return true
+	}
+	if len(c.TaskTypes) > 0 && !slices.Contains(c.TaskTypes, task.TaskType) {
+		return false
+	}
+	if len(c.CircuitTypes) > 0 && task.CircuitType != None && !slices.Contains(c.CircuitTypes, task.CircuitType) {
+		return false
+	}
+	return c.MemoryClass == MemoryClassNone || c.MemoryClass >= task.RequiredMemoryClass()
+}
@@ -5,3 +5,5 @@ package types
 //go:generate stringer -type=TaskStatus -trimprefix=TaskStatus
 //go:generate stringer -type=CircuitType -trimprefix=Circuit
 //go:generate stringer -type=TaskErrType -trimprefix=TaskErr
+//go:generate stringer -type=TaskPriority -trimprefix=Priority
+//go:generate stringer -type=MemoryClass -trimprefix=MemoryClass
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"iter"
+	"maps"
+	"slices"
 	"strconv"
 	"time"
 
@@ -35,6 +37,29 @@ func Circuits() iter.Seq[CircuitType] {
 	}
 }
 
+var CircuitTypes = map[string]CircuitType{
+	"Bytecode":  CircuitBytecode,
+	"ReadWrite": CircuitReadWrite,
+	"ZKEVM":     CircuitZKEVM,
+	"Copy":      CircuitCopy,
+}
+
+func (c *CircuitType) Set(str string) error {
+	if v, ok := CircuitTypes[str]; ok {
+		*c = v
+		return nil
+	}
+	return fmt.Errorf("unknown circuit type: %s", str)
+}
+
+func (*CircuitType) Type() string {
+	return "CircuitType"
+}
+
+func (*CircuitType) PossibleValues() []string {
+	return slices.Collect(maps.Keys(CircuitTypes))
+}
+
 // TaskId Unique ID of a task, serves as a key in DB
 type TaskId uuid.UUID
 
@@ -115,6 +140,9 @@ type Task struct {
 	CircuitType  CircuitType `json:"circuitType"`
 	ParentTaskId *TaskId     `json:"parentTaskId"`
 
+	// Priority is the priority class of the batch, it is inherited by the tasks created for the batch
+	Priority TaskPriority `json:"priority,omitempty"`
+
 	// DependencyResults tracks the set of task results on which current task depends
 	DependencyResults map[TaskId]TaskResultDetails `json:"dependencyResults"`
 }
@@ -139,6 +167,9 @@ type TaskEntry struct {
 	// Finished time when the task execution was completed (successfully or not)
 	Finished *time.Time
 
+	// LeaseRenewed: time of the last lease renewal by the executor, nil if the executor doesn't renew leases
+	LeaseRenewed *time.Time
+
 	// Owner: identifier of the current task executor
 	Owner TaskExecutorId
 
@@ -221,6 +252,37 @@ func (t *TaskEntry) Terminate(result *TaskResult, currentTime time.Time) error {
 	return nil
 }
 
+// RenewLease extends the lease of the running task held by the executor.
+func (t *TaskEntry) RenewLease(executorId TaskExecutorId, currentTime time.Time) error {
+	if t.Status != Running {
+		return errTaskInvalidStatus(t, "RenewLease")
+	}
+	if t.Owner != executorId {
+		return fmt.Errorf("%w: id=%s, owner=%s, executor=%s", ErrTaskWrongExecutor, t.Task.Id, t.Owner, executorId)
+	}
+
+	t.LeaseRenewed = &currentTime
+	return nil
+}
+
+// CheckHanging returns the timeout error if the executor of the running task is considered lost.
+// If the executor renews the lease, the time since the last renewal is compared with leaseTimeout,
+// otherwise the whole execution time is compared with executionTimeout.
+func (t *TaskEntry) CheckHanging(currentTime time.Time, executionTimeout, leaseTimeout time.Duration) *TaskExecError {
+	if t.Status != Running || t.Started == nil {
+		return nil
+	}
+
+	lastSeen, timeout := *t.Started, executionTimeout
+	if t.LeaseRenewed != nil {
+		lastSeen, timeout = *t.LeaseRenewed, leaseTimeout
+	}
+	if elapsed := currentTime.Sub(lastSeen); elapsed > timeout {
+		return NewTaskErrTimeout(elapsed, timeout)
+	}
+	return nil
+}
+
 // ResetRunning resets a task's status from Running to WaitingForExecutor, clearing its start time
 // and executor ownership.
 func (t *TaskEntry) ResetRunning() error {
@@ -229,6 +291,7 @@ func (t *TaskEntry) ResetRunning() error {
 	}
 
 	t.Started = nil
+	t.LeaseRenewed = nil
 	t.Status = WaitingForExecutor
 	t.Owner = UnknownExecutorId
 	t.RetryCount++
@@ -259,6 +322,9 @@ func (t *TaskEntry) HasHigherPriorityThan(other *TaskEntry) bool {
 		return true
 	}
 
+	if t.Task.Priority != other.Task.Priority {
+		return t.Task.Priority > other.Task.Priority
+	}
 	if t.Created != other.Created {
 		return t.Created.Before(other.Created)
 	}
@@ -0,0 +1,126 @@
+package types
+
+import (
+	"maps"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// AccountDiff holds the fields of an account changed within a batch, the unchanged fields are nil.
+// Deleted means the account was removed from the state. If any fields are set along with it,
+// the account was created again and the fields are applied on top of the empty account.
+// The removed storage slots and tokens have zero values, the removed async contexts are nil.
+type AccountDiff struct {
+	Balance      *types.Value                                   `json:"balance,omitempty"`
+	Seqno        *hexutil.Uint64                                `json:"seqno,omitempty"`
+	ExtSeqno     *hexutil.Uint64                                `json:"extSeqno,omitempty"`
+	Code         hexutil.Bytes                                  `json:"code,omitempty"`
+	Storage      map[common.Hash]common.Hash                    `json:"storage,omitempty"`
+	Tokens       map[types.TokenId]types.Value                  `json:"tokens,omitempty"`
+	AsyncContext map[types.TransactionIndex]*types.AsyncContext `json:"asyncContext,omitempty"`
+	Deleted      bool                                           `json:"deleted,omitempty"`
+}
+
+// IsEmpty returns true if no fields of the account are changed.
+func (d *AccountDiff) IsEmpty() bool {
+	return d.Balance == nil && d.Seqno == nil && d.ExtSeqno == nil && d.Code == nil &&
+		len(d.Storage) == 0 && len(d.Tokens) == 0 && len(d.AsyncContext) == 0
+}
+
+// NewAccountDiff converts the changes of a contract reported by the node into the account diff.
+func NewAccountDiff(contract *execution.ContractDiff) *AccountDiff {
+	diff := &AccountDiff{
+		Balance:      contract.Balance,
+		Storage:      contract.Storage,
+		Tokens:       contract.Tokens,
+		AsyncContext: contract.AsyncContext,
+	}
+	if contract.Seqno != nil {
+		seqno := hexutil.Uint64(*contract.Seqno)
+		diff.Seqno = &seqno
+	}
+	if contract.ExtSeqno != nil {
+		extSeqno := hexutil.Uint64(*contract.ExtSeqno)
+		diff.ExtSeqno = &extSeqno
+	}
+	if contract.Code != nil {
+		diff.Code = hexutil.Bytes(contract.Code)
+	}
+	return diff
+}
+
+// ShardStateDiff holds the changed accounts of a single shard.
+type ShardStateDiff map[types.Address]*AccountDiff
+
+// StateDiff holds the accounts changed within a batch grouped by shards.
+type StateDiff map[types.ShardId]ShardStateDiff
+
+func NewStateDiff() StateDiff {
+	return make(StateDiff)
+}
+
+func (d StateDiff) account(addr types.Address) *AccountDiff {
+	shardDiff, ok := d[addr.ShardId()]
+	if !ok {
+		shardDiff = make(ShardStateDiff)
+		d[addr.ShardId()] = shardDiff
+	}
+	acc, ok := shardDiff[addr]
+	if !ok {
+		acc = &AccountDiff{}
+		shardDiff[addr] = acc
+	}
+	return acc
+}
+
+// Update merges the changed fields of the account into the diff, the later changes override the earlier ones.
+func (d StateDiff) Update(addr types.Address, upd *AccountDiff) {
+	acc := d.account(addr)
+	if upd.Balance != nil {
+		acc.Balance = upd.Balance
+	}
+	if upd.Seqno != nil {
+		acc.Seqno = upd.Seqno
+	}
+	if upd.ExtSeqno != nil {
+		acc.ExtSeqno = upd.ExtSeqno
+	}
+	if upd.Code != nil {
+		acc.Code = upd.Code
+	}
+	if len(upd.Storage) > 0 {
+		if acc.Storage == nil {
+			acc.Storage = make(map[common.Hash]common.Hash, len(upd.Storage))
+		}
+		maps.Copy(acc.Storage, upd.Storage)
+	}
+	if len(upd.Tokens) > 0 {
+		if acc.Tokens == nil {
+			acc.Tokens = make(map[types.TokenId]types.Value, len(upd.Tokens))
+		}
+		maps.Copy(acc.Tokens, upd.Tokens)
+	}
+	if len(upd.AsyncContext) > 0 {
+		if acc.AsyncContext == nil {
+			acc.AsyncContext = make(map[types.TransactionIndex]*types.AsyncContext, len(upd.AsyncContext))
+		}
+		maps.Copy(acc.AsyncContext, upd.AsyncContext)
+	}
+}
+
+// Delete marks the account as removed and drops the changes made to it before.
+func (d StateDiff) Delete(addr types.Address) {
+	*d.account(addr) = AccountDiff{Deleted: true}
+}
+
+// Apply merges the changes of a contract reported by the node into the diff.
+func (d StateDiff) Apply(contract *execution.ContractDiff) {
+	if contract.Deleted {
+		d.Delete(contract.Address)
+		return
+	}
+	d.Update(contract.Address, NewAccountDiff(contract))
+}
@@ -0,0 +1,74 @@
+package types
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ArchivedTask is a compact representation of a terminated task kept in the task archive.
+// Task inputs and results data are not archived.
+type ArchivedTask struct {
+	Id           TaskId      `json:"id"`
+	BatchId      BatchId     `json:"batchId"`
+	ParentTaskId *TaskId     `json:"parentTaskId,omitempty"`
+	TaskType     TaskType    `json:"type"`
+	CircuitType  CircuitType `json:"circuitType"`
+
+	// Dependencies: ids of the tasks the current one depends on
+	Dependencies []TaskId `json:"dependencies,omitempty"`
+
+	Owner      TaskExecutorId `json:"owner"`
+	Status     TaskStatus     `json:"status"`
+	RetryCount int            `json:"retryCount,omitempty"`
+	ErrorText  string         `json:"errorText,omitempty"`
+
+	Created  time.Time  `json:"createdAt"`
+	Started  *time.Time `json:"startedAt,omitempty"`
+	Finished *time.Time `json:"finishedAt,omitempty"`
+}
+
+// NewArchivedTask makes an archive record of the task entry.
+// Result is nil for the entries archived without being terminated, e.g. abandoned dependents of failed tasks.
+func NewArchivedTask(entry *TaskEntry, result *TaskResult) *ArchivedTask {
+	dependencies := make(TaskIdSet, len(entry.PendingDependencies)+len(entry.Task.DependencyResults))
+	maps.Copy(dependencies, entry.PendingDependencies)
+	for taskId := range entry.Task.DependencyResults {
+		dependencies.Put(taskId)
+	}
+
+	archived := &ArchivedTask{
+		Id:           entry.Task.Id,
+		BatchId:      entry.Task.BatchId,
+		ParentTaskId: entry.Task.ParentTaskId,
+		TaskType:     entry.Task.TaskType,
+		CircuitType:  entry.Task.CircuitType,
+		Dependencies: slices.SortedFunc(maps.Keys(dependencies), func(l, r TaskId) int {
+			return strings.Compare(l.String(), r.String())
+		}),
+		Owner:      entry.Owner,
+		Status:     entry.Status,
+		RetryCount: entry.RetryCount,
+		Created:    entry.Created,
+		Started:    entry.Started,
+		Finished:   entry.Finished,
+	}
+
+	if result != nil && result.Error != nil {
+		archived.ErrorText = result.Error.ErrText
+	}
+	return archived
+}
+
+// LastUpdated returns the time of the latest known state change of the task.
+func (t *ArchivedTask) LastUpdated() time.Time {
+	switch {
+	case t.Finished != nil:
+		return *t.Finished
+	case t.Started != nil:
+		return *t.Started
+	default:
+		return t.Created
+	}
+}
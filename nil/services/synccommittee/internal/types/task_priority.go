@@ -0,0 +1,39 @@
+package types
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// TaskPriority is the priority class of the batch tasks.
+// Tasks of a higher class are handed to executors first regardless of their creation time.
+type TaskPriority int8
+
+const (
+	PriorityLow TaskPriority = iota - 1
+	PriorityNormal
+	PriorityHigh
+)
+
+var TaskPriorities = map[string]TaskPriority{
+	"Low":    PriorityLow,
+	"Normal": PriorityNormal,
+	"High":   PriorityHigh,
+}
+
+func (p *TaskPriority) Set(str string) error {
+	if v, ok := TaskPriorities[str]; ok {
+		*p = v
+		return nil
+	}
+	return fmt.Errorf("unknown task priority: %s", str)
+}
+
+func (*TaskPriority) Type() string {
+	return "TaskPriority"
+}
+
+func (*TaskPriority) PossibleValues() []string {
+	return slices.Collect(maps.Keys(TaskPriorities))
+}
@@ -17,11 +17,12 @@ import (
 )
 
 type Config struct {
-	SyncCommitteeRpcEndpoint string            `yaml:"syncCommitteeEndpoint,omitempty"`
-	TaskListenerRpcEndpoint  string            `yaml:"ownEndpoint,omitempty"`
-	SkipRate                 int               `yaml:"skipRate,omitempty"`
-	MaxConcurrentBatches     uint32            `yaml:"maxConcurrentBatches,omitempty"`
-	Telemetry                *telemetry.Config `yaml:",inline"`
+	SyncCommitteeRpcEndpoint string                       `yaml:"syncCommitteeEndpoint,omitempty"`
+	TaskListenerRpcEndpoint  string                       `yaml:"ownEndpoint,omitempty"`
+	SkipRate                 int                          `yaml:"skipRate,omitempty"`
+	MaxConcurrentBatches     uint32                       `yaml:"maxConcurrentBatches,omitempty"`
+	TaskArchiverConfig       scheduler.TaskArchiverConfig `yaml:",inline"`
+	Telemetry                *telemetry.Config            `yaml:",inline"`
 }
 
 func NewDefaultConfig() *Config {
@@ -30,6 +31,7 @@ func NewDefaultConfig() *Config {
 		TaskListenerRpcEndpoint:  "tcp://127.0.0.1:8531",
 		SkipRate:                 0,
 		MaxConcurrentBatches:     1,
+		TaskArchiverConfig:       scheduler.NewDefaultTaskArchiverConfig(),
 		Telemetry: &telemetry.Config{
 			ServiceName: "proof_provider",
 		},
@@ -89,10 +91,12 @@ func New(config *Config, database db.DB) (*ProofProvider, error) {
 		logger,
 	)
 
+	taskArchiver := scheduler.NewTaskArchiver(taskStorage, config.TaskArchiverConfig, logger)
+
 	return &ProofProvider{
 		Service: srv.NewService(
 			logger,
-			taskExecutor, taskScheduler, taskListener, taskCancelChecker, taskResultSender,
+			taskExecutor, taskScheduler, taskListener, taskCancelChecker, taskResultSender, taskArchiver,
 		),
 	}, nil
 }
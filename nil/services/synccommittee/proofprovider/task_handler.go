@@ -87,6 +87,8 @@ func (h *taskHandler) Handle(ctx context.Context, executorId types.TaskExecutorI
 
 	for _, taskEntry := range blockTasks {
 		taskEntry.Task.ParentTaskId = &task.Id
+		// subtasks inherit the priority class of the batch
+		taskEntry.Task.Priority = task.Priority
 	}
 
 	err := h.taskStorage.AddTaskEntries(ctx, blockTasks...)
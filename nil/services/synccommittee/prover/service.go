@@ -14,6 +14,7 @@ import (
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/scheduler"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/srv"
 	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/storage"
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
 	"github.com/jonboulle/clockwork"
 )
 
@@ -21,6 +22,39 @@ type Config struct {
 	ProofProviderRpcEndpoint string            `yaml:"proofProviderEndpoint,omitempty"`
 	NilRpcEndpoint           string            `yaml:"nilEndpoint,omitempty"`
 	Telemetry                *telemetry.Config `yaml:",inline"`
+
+	// Capabilities advertised to the proof provider, empty values mean that any task is accepted
+	TaskTypes    []string `yaml:"taskTypes,omitempty"`
+	CircuitTypes []string `yaml:"circuitTypes,omitempty"`
+	MemoryClass  string   `yaml:"memoryClass,omitempty"`
+}
+
+func (c *Config) executorCapabilities() (*types.ExecutorCapabilities, error) {
+	if len(c.TaskTypes) == 0 && len(c.CircuitTypes) == 0 && c.MemoryClass == "" {
+		return nil, nil
+	}
+
+	capabilities := &types.ExecutorCapabilities{}
+	for _, name := range c.TaskTypes {
+		var taskType types.TaskType
+		if err := taskType.Set(name); err != nil {
+			return nil, err
+		}
+		capabilities.TaskTypes = append(capabilities.TaskTypes, taskType)
+	}
+	for _, name := range c.CircuitTypes {
+		var circuitType types.CircuitType
+		if err := circuitType.Set(name); err != nil {
+			return nil, err
+		}
+		capabilities.CircuitTypes = append(capabilities.CircuitTypes, circuitType)
+	}
+	if c.MemoryClass != "" {
+		if err := capabilities.MemoryClass.Set(c.MemoryClass); err != nil {
+			return nil, err
+		}
+	}
+	return capabilities, nil
 }
 
 func NewDefaultConfig() *Config {
@@ -60,8 +94,14 @@ func New(config Config, database db.DB) (*Prover, error) {
 		newTaskHandlerConfig(config.NilRpcEndpoint),
 	)
 
+	executorConfig := executor.DefaultConfig()
+	executorConfig.Capabilities, err = config.executorCapabilities()
+	if err != nil {
+		return nil, fmt.Errorf("invalid prover capabilities: %w", err)
+	}
+
 	taskExecutor, err := executor.New(
-		executor.DefaultConfig(),
+		executorConfig,
 		taskRpcClient,
 		handler,
 		metricsHandler,
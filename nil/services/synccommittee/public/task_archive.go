@@ -0,0 +1,84 @@
+package public
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/services/synccommittee/internal/types"
+)
+
+type ArchivedTask = types.ArchivedTask
+
+// ArchivedTaskTree holds the archived tasks of a single batch.
+type ArchivedTaskTree struct {
+	BatchId BatchId         `json:"batchId"`
+	Tasks   []*ArchivedTask `json:"tasks"`
+}
+
+// HasOwner checks if any task of the tree was executed by the given executor.
+func (t *ArchivedTaskTree) HasOwner(owner TaskExecutorId) bool {
+	for _, task := range t.Tasks {
+		if task.Owner == owner {
+			return true
+		}
+	}
+	return false
+}
+
+// Overlaps checks if any task of the tree was updated within the [from, to] time range.
+// Nil bounds are not checked.
+func (t *ArchivedTaskTree) Overlaps(from, to *time.Time) bool {
+	for _, task := range t.Tasks {
+		updated := task.LastUpdated()
+		if (from == nil || !updated.Before(*from)) && (to == nil || !updated.After(*to)) {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	TaskArchiveMinLimit     = 1
+	TaskArchiveMaxLimit     = 10_000
+	DefaultTaskArchiveLimit = 100
+)
+
+// TaskArchiveRequest defines the filter of the archived task trees, unset fields are not checked.
+type TaskArchiveRequest struct {
+	BatchId *BatchId       `json:"batchId,omitempty"`
+	From    *time.Time     `json:"from,omitempty"`
+	To      *time.Time     `json:"to,omitempty"`
+	Owner   TaskExecutorId `json:"owner,omitempty"`
+
+	// Limit defines the maximum number of trees returned
+	Limit int `json:"limit"`
+}
+
+func DefaultTaskArchiveRequest() TaskArchiveRequest {
+	return TaskArchiveRequest{
+		Owner: DefaultDebugTaskOwner,
+		Limit: DefaultTaskArchiveLimit,
+	}
+}
+
+func (r *TaskArchiveRequest) Validate() error {
+	if r.Limit < TaskArchiveMinLimit || r.Limit > TaskArchiveMaxLimit {
+		return fmt.Errorf(
+			"limit must be between %d and %d, actual is %d", TaskArchiveMinLimit, TaskArchiveMaxLimit, r.Limit)
+	}
+	if r.From != nil && r.To != nil && r.From.After(*r.To) {
+		return fmt.Errorf("invalid time range: from %s is after to %s", r.From, r.To)
+	}
+	return nil
+}
+
+// Matches checks if the tree satisfies the request filter.
+func (r *TaskArchiveRequest) Matches(tree *ArchivedTaskTree) bool {
+	if r.BatchId != nil && *r.BatchId != tree.BatchId {
+		return false
+	}
+	if r.Owner != types.UnknownExecutorId && !tree.HasOwner(r.Owner) {
+		return false
+	}
+	return r.From == nil && r.To == nil || tree.Overlaps(r.From, r.To)
+}
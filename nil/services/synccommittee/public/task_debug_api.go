@@ -10,9 +10,11 @@ import (
 )
 
 const (
-	DebugNamespace   = "Debug"
-	DebugGetTasks    = DebugNamespace + "_getTasks"
-	DebugGetTaskTree = DebugNamespace + "_getTaskTree"
+	DebugNamespace        = "Debug"
+	DebugGetTasks         = DebugNamespace + "_getTasks"
+	DebugGetTaskTree      = DebugNamespace + "_getTaskTree"
+	DebugSetBatchPriority = DebugNamespace + "_setBatchPriority"
+	DebugGetArchivedTasks = DebugNamespace + "_getArchivedTasks"
 )
 
 const (
@@ -126,6 +128,18 @@ func (r *TaskDebugRequest) Validate() error {
 	return nil
 }
 
+type SetBatchPriorityRequest struct {
+	BatchId  BatchId      `json:"batchId"`
+	Priority TaskPriority `json:"priority"`
+}
+
+func NewSetBatchPriorityRequest(batchId BatchId, priority TaskPriority) *SetBatchPriorityRequest {
+	return &SetBatchPriorityRequest{
+		BatchId:  batchId,
+		Priority: priority,
+	}
+}
+
 // TaskDebugApi provides methods to retrieve debug information on tasks.
 type TaskDebugApi interface {
 	// GetTasks retrieves a list of tasks based on the specified TaskDebugRequest criteria.
@@ -133,4 +147,11 @@ type TaskDebugApi interface {
 
 	// GetTaskTree retrieves the task tree structure for a specific task identified by taskId
 	GetTaskTree(ctx context.Context, taskId TaskId) (*TaskTreeView, error)
+
+	// SetBatchPriority sets the priority class of all the existing tasks of the batch
+	// and returns the number of updated tasks.
+	SetBatchPriority(ctx context.Context, request *SetBatchPriorityRequest) (uint, error)
+
+	// GetArchivedTasks retrieves the archived task trees matching the specified TaskArchiveRequest criteria.
+	GetArchivedTasks(ctx context.Context, request *TaskArchiveRequest) ([]*ArchivedTaskTree, error)
 }
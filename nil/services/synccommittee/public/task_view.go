@@ -17,6 +17,7 @@ type (
 
 	CircuitType    = types.CircuitType
 	TaskType       = types.TaskType
+	TaskPriority   = types.TaskPriority
 	TaskStatus     = types.TaskStatus
 	TaskExecutorId = types.TaskExecutorId
 )
@@ -52,6 +53,7 @@ type TaskView struct {
 
 	BatchId  BatchId         `json:"batchId"`
 	BlockIds []types.BlockId `json:"blockIds"`
+	Priority TaskPriority    `json:"priority"`
 
 	CreatedAt time.Time  `json:"createdAt"`
 	StartedAt *time.Time `json:"startedAt,omitempty"`
@@ -63,6 +65,7 @@ func NewTaskView(taskEntry *types.TaskEntry, currentTime time.Time) *TaskView {
 
 		BatchId:  taskEntry.Task.BatchId,
 		BlockIds: taskEntry.Task.BlockIds,
+		Priority: taskEntry.Task.Priority,
 
 		CreatedAt: taskEntry.Created,
 		StartedAt: taskEntry.Started,
@@ -0,0 +1,152 @@
+package txnpool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// maxJournalRecordSize limits the size of a single journal record to protect the replay from corrupted lengths.
+const maxJournalRecordSize = 16 * 1024 * 1024
+
+var (
+	errNoActiveJournal  = errors.New("no active journal")
+	errCorruptedJournal = errors.New("corrupted journal")
+)
+
+// journal is an append-only file of the transactions accepted by the pool.
+// Each record is the uvarint-encoded length of the SSZ-encoded transaction followed by the transaction itself.
+// The journal may contain transactions that already left the pool, so it is re-validated on replay
+// and periodically rotated to contain only the pending transactions.
+type journal struct {
+	path   string
+	writer *os.File
+}
+
+func newJournal(path string) *journal {
+	return &journal{path: path}
+}
+
+// load reads the journal and passes the transactions to add.
+// A truncated or corrupted record (e.g., after a crash in the middle of a write) stops the replay
+// with errCorruptedJournal, the transactions before it are already passed to add.
+func (j *journal) load(add func(txn *types.Transaction) error) (loaded int, err error) {
+	f, err := os.Open(j.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		txn, err := readJournalRecord(r)
+		if errors.Is(err, io.EOF) {
+			return loaded, nil
+		}
+		if err != nil {
+			return loaded, fmt.Errorf("%w: record %d: %w", errCorruptedJournal, loaded, err)
+		}
+		if err := add(txn); err != nil {
+			return loaded, err
+		}
+		loaded++
+	}
+}
+
+func readJournalRecord(r *bufio.Reader) (*types.Transaction, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > maxJournalRecordSize {
+		return nil, fmt.Errorf("journal record is too large: %d bytes", size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	txn := &types.Transaction{}
+	if err := txn.UnmarshalSSZ(data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal journaled transaction: %w", err)
+	}
+	return txn, nil
+}
+
+// insert appends the transaction to the active journal.
+func (j *journal) insert(txn *types.Transaction) error {
+	if j.writer == nil {
+		return errNoActiveJournal
+	}
+	return writeJournalRecord(j.writer, txn)
+}
+
+func writeJournalRecord(w io.Writer, txn *types.Transaction) error {
+	data, err := txn.MarshalSSZ()
+	if err != nil {
+		return err
+	}
+
+	record := binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+len(data)), uint64(len(data)))
+	record = append(record, data...)
+	_, err = w.Write(record)
+	return err
+}
+
+// rotate atomically replaces the journal with the given transactions and reopens it for appending.
+func (j *journal) rotate(txns []*types.Transaction) error {
+	if err := j.close(); err != nil {
+		return err
+	}
+
+	tmpPath := j.path + ".new"
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(tmp)
+	for _, txn := range txns {
+		if err := writeJournalRecord(w, txn); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return err
+	}
+
+	j.writer, err = os.OpenFile(j.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	return err
+}
+
+func (j *journal) close() error {
+	if j.writer == nil {
+		return nil
+	}
+	err := j.writer.Close()
+	j.writer = nil
+	return err
+}
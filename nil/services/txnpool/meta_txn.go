@@ -1,6 +1,8 @@
 package txnpool
 
 import (
+	"time"
+
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
@@ -10,6 +12,9 @@ type metaTxn struct {
 	effectivePriorityFee types.Value
 	bestIndex            int
 	valid                bool
+	// future is set if there is a seqno gap before the transaction, so it can't be included yet.
+	future  bool
+	addedAt time.Time
 }
 
 func newMetaTxn(txn *types.Transaction, baseFee types.Value) *metaTxn {
@@ -19,6 +24,7 @@ func newMetaTxn(txn *types.Transaction, baseFee types.Value) *metaTxn {
 		effectivePriorityFee: effectivePriorityFee,
 		valid:                valid,
 		bestIndex:            -1,
+		addedAt:              time.Now(),
 	}
 }
 
@@ -28,6 +34,8 @@ func (m *metaTxn) Clone() *metaTxn {
 		effectivePriorityFee: m.effectivePriorityFee,
 		bestIndex:            m.bestIndex,
 		valid:                m.valid,
+		future:               m.future,
+		addedAt:              m.addedAt,
 	}
 }
 
@@ -35,6 +43,10 @@ func (m *metaTxn) IsValid() bool {
 	return m.valid
 }
 
+func (m *metaTxn) IsFuture() bool {
+	return m.future
+}
+
 func (m *metaTxn) IsInQueue() bool {
 	return m.bestIndex >= 0
 }
@@ -0,0 +1,77 @@
+package txnpool
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// StateReader provides the committed state of the shard.
+// It is used to check the transactions against the account seqnos and to re-validate the journaled ones on replay.
+type StateReader interface {
+	// BaseFee returns the base fee of the last committed block.
+	BaseFee(ctx context.Context) (types.Value, error)
+	// ExtSeqno returns the next external seqno expected by the account.
+	ExtSeqno(ctx context.Context, addr types.Address) (types.Seqno, error)
+}
+
+type dbStateReader struct {
+	database db.ReadOnlyDB
+	shardId  types.ShardId
+}
+
+var _ StateReader = (*dbStateReader)(nil)
+
+func NewDbStateReader(database db.ReadOnlyDB, shardId types.ShardId) StateReader {
+	return &dbStateReader{
+		database: database,
+		shardId:  shardId,
+	}
+}
+
+func (r *dbStateReader) BaseFee(ctx context.Context) (types.Value, error) {
+	tx, err := r.database.CreateRoTx(ctx)
+	if err != nil {
+		return types.Value{}, err
+	}
+	defer tx.Rollback()
+
+	block, _, err := db.ReadLastBlock(tx, r.shardId)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return types.Value0, nil
+	}
+	if err != nil {
+		return types.Value{}, err
+	}
+	return block.BaseFee, nil
+}
+
+func (r *dbStateReader) ExtSeqno(ctx context.Context, addr types.Address) (types.Seqno, error) {
+	tx, err := r.database.CreateRoTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	block, _, err := db.ReadLastBlock(tx, r.shardId)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	contracts := execution.NewDbContractTrieReader(tx, r.shardId)
+	contracts.SetRootHash(block.SmartContractsRoot)
+	contract, err := contracts.Fetch(addr.Hash())
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return contract.ExtSeqno, nil
+}
@@ -72,7 +72,7 @@ func (b *ByReceiverAndSeqno) ascend(to types.Address, f func(*metaTxn) bool) {
 	})
 }
 
-func (b *ByReceiverAndSeqno) count(to types.Address) int { //nolint:unused
+func (b *ByReceiverAndSeqno) count(to types.Address) int {
 	return b.toTxnCount[to]
 }
 
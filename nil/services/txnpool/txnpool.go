@@ -3,15 +3,22 @@ package txnpool
 import (
 	"container/heap"
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // FeeBumpPercentage is the percentage of the priorityFee that a transaction must exceed to replace another transaction.
@@ -19,6 +26,12 @@ import (
 // priorityFee of at least 105 to replace the existing transaction.
 const FeeBumpPercentage = 5
 
+// evictionInterval is the interval of checking for the transactions that exceeded their lifetime.
+const evictionInterval = time.Minute
+
+// maxDiscardedRecords is the number of the recently discarded transactions whose discard reasons are kept.
+const maxDiscardedRecords = 1024
+
 type Pool interface {
 	Add(ctx context.Context, txns ...*types.Transaction) ([]DiscardReason, error)
 	Discard(ctx context.Context, txns []common.Hash, reason DiscardReason) error
@@ -32,6 +45,17 @@ type Pool interface {
 	Get(hash common.Hash) (*types.Transaction, error)
 	GetPendingLength() (int, error)
 	GetSize() int
+	// Content returns the pending transactions, which can be included in the next blocks,
+	// and the queued ones, which wait for a seqno gap to be filled.
+	Content() (pending, queued []*types.Transaction)
+	// Stats returns the number of the pending and queued transactions.
+	Stats() (pending, queued int)
+	// Discarded returns the reasons of the recently discarded transactions, except the committed ones.
+	Discarded() map[common.Hash]DiscardReason
+
+	// SubscribeNewTransactions registers ch to receive hashes of the transactions accepted by the pool.
+	// The returned function cancels the subscription.
+	SubscribeNewTransactions(ch chan<- common.Hash) (unsubscribe func())
 }
 
 type TxnPool struct {
@@ -39,6 +63,7 @@ type TxnPool struct {
 	cfg     Config
 	baseFee types.Value
 	// seqnoMap is a map of addresses to their current seqno. Seqno is updated when the transaction is committed.
+	// If the pool reads the seqnos from the state, it keeps only the accounts having transactions in the pool.
 	seqnoMap map[types.Address]types.Seqno
 
 	networkManager network.Manager
@@ -49,6 +74,16 @@ type TxnPool struct {
 	all    *ByReceiverAndSeqno // from => (sorted map of txn seqno => *txn)
 	queue  *TxnQueue
 	logger logging.Logger
+	// discarded keeps the reasons of the recently discarded transactions, e.g., the expired ones,
+	// since their senders can't learn them otherwise.
+	discarded *lru.Cache[common.Hash, DiscardReason]
+	tracer    telemetry.Tracer
+
+	// journal persists the accepted transactions, nil if journaling is disabled.
+	journal *journal
+
+	subsLock sync.Mutex
+	subs     map[chan<- common.Hash]struct{}
 }
 
 func New(ctx context.Context, cfg Config, networkManager network.Manager) (*TxnPool, error) {
@@ -56,6 +91,11 @@ func New(ctx context.Context, cfg Config, networkManager network.Manager) (*TxnP
 		Stringer(logging.FieldShardId, cfg.ShardId).
 		Logger()
 
+	discarded, err := lru.New[common.Hash, DiscardReason](maxDiscardedRecords)
+	if err != nil {
+		return nil, err
+	}
+
 	res := &TxnPool{
 		started:  true,
 		cfg:      cfg,
@@ -63,10 +103,29 @@ func New(ctx context.Context, cfg Config, networkManager network.Manager) (*TxnP
 
 		networkManager: networkManager,
 
-		byHash: map[string]*metaTxn{},
-		all:    NewBySenderAndSeqno(logger),
-		queue:  &TxnQueue{},
-		logger: logger,
+		byHash:    map[string]*metaTxn{},
+		all:       NewBySenderAndSeqno(logger),
+		queue:     &TxnQueue{},
+		logger:    logger,
+		discarded: discarded,
+		tracer:    telemetry.NewTracer("github.com/NilFoundation/nil/nil/services/txnpool"),
+
+		subs: make(map[chan<- common.Hash]struct{}),
+	}
+
+	if cfg.Journal != "" {
+		if err := res.replayJournal(ctx); err != nil {
+			return nil, fmt.Errorf("failed to replay transaction pool journal: %w", err)
+		}
+		go func() {
+			res.rejournalLoop(ctx)
+		}()
+	}
+
+	if cfg.Lifetime > 0 {
+		go func() {
+			res.evictionLoop(ctx)
+		}()
 	}
 
 	if networkManager == nil {
@@ -99,11 +158,17 @@ func (p *TxnPool) listen(ctx context.Context, sub *network.Subscription) {
 
 		mm := newMetaTxn(txn, p.GetBaseFee())
 
-		reasons, err := p.add(mm)
+		// the span continues the trace of the node which has published the transaction
+		_, span := p.tracer.Start(m.Context(ctx), "txnpool.Receive",
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(telattr.ShardId(p.cfg.ShardId), telattr.TransactionHash(mm.Hash())))
+
+		reasons, err := p.add(ctx, mm)
 		if err != nil {
 			p.logger.Error().Err(err).
 				Stringer(logging.FieldTransactionHash, mm.Hash()).
 				Msg("Failed to add transaction from network")
+			telemetry.EndSpan(span, err)
 			continue
 		}
 
@@ -111,7 +176,11 @@ func (p *TxnPool) listen(ctx context.Context, sub *network.Subscription) {
 			p.logger.Debug().
 				Stringer(logging.FieldTransactionHash, mm.Hash()).
 				Msgf("Discarded transaction from network with reason %s", reasons[0])
+			span.SetAttributes(discardReasonAttribute(reasons[0]))
+		} else {
+			telemetry.RegisterTransactionSpan(mm.Hash(), span)
 		}
+		span.End()
 	}
 }
 
@@ -126,15 +195,26 @@ func (p *TxnPool) Add(ctx context.Context, txns ...*types.Transaction) ([]Discar
 		mms[i] = newMetaTxn(txn, baseFee)
 	}
 
-	reasons, err := p.add(mms...)
+	ctx, span := p.tracer.Start(ctx, "txnpool.Add",
+		trace.WithAttributes(telattr.ShardId(p.cfg.ShardId), attribute.Int("txnNum", len(txns))))
+	defer span.End()
+	if len(mms) == 1 {
+		span.SetAttributes(telattr.TransactionHash(mms[0].Hash()))
+	}
+
+	reasons, err := p.add(ctx, mms...)
 	if err != nil {
+		telemetry.RecordError(span, err)
 		return nil, err
 	}
 
 	for i, mm := range mms {
 		if reasons[i] != NotSet {
+			span.AddEvent("discarded", trace.WithAttributes(
+				telattr.TransactionHash(mm.Hash()), discardReasonAttribute(reasons[i])))
 			continue
 		}
+		telemetry.RegisterTransactionSpan(mm.Hash(), span)
 
 		if err := PublishPendingTransaction(ctx, p.networkManager, p.cfg.ShardId, mm); err != nil {
 			p.logger.Error().Err(err).
@@ -146,7 +226,7 @@ func (p *TxnPool) Add(ctx context.Context, txns ...*types.Transaction) ([]Discar
 	return reasons, nil
 }
 
-func (p *TxnPool) add(txns ...*metaTxn) ([]DiscardReason, error) {
+func (p *TxnPool) add(ctx context.Context, txns ...*metaTxn) ([]DiscardReason, error) {
 	discardReasons := make([]DiscardReason, len(txns))
 
 	p.lock.Lock()
@@ -168,11 +248,24 @@ func (p *TxnPool) add(txns ...*metaTxn) ([]DiscardReason, error) {
 			continue
 		}
 
+		if err := p.loadSeqnoLocked(ctx, txn.To); err != nil {
+			return nil, err
+		}
+
+		if reason, ok := p.validateSeqno(txn); !ok {
+			discardReasons[i] = reason
+			p.forgetSeqnoLocked(txn.To)
+			continue
+		}
+
 		if reason := p.addLocked(txn); reason != NotSet {
 			discardReasons[i] = reason
+			p.forgetSeqnoLocked(txn.To)
 			continue
 		}
 		discardReasons[i] = NotSet // unnecessary
+		p.journalLocked(txn)
+		p.notifySubscribers(txn.Hash())
 		p.logger.Debug().
 			Stringer(logging.FieldTransactionHash, txn.Hash()).
 			Stringer(logging.FieldTransactionTo, txn.To).
@@ -184,11 +277,173 @@ func (p *TxnPool) add(txns ...*metaTxn) ([]DiscardReason, error) {
 	return discardReasons, nil
 }
 
+// loadSeqnoLocked reads the seqno of the account from the state when the account is seen for the first time,
+// so that its transactions are checked against the actual seqno rather than the lowest one in the pool.
+func (p *TxnPool) loadSeqnoLocked(ctx context.Context, addr types.Address) error {
+	if p.cfg.State == nil {
+		return nil
+	}
+	if _, ok := p.seqnoMap[addr]; ok {
+		return nil
+	}
+
+	seqno, err := p.cfg.State.ExtSeqno(ctx, addr)
+	if err != nil {
+		return fmt.Errorf("failed to read seqno of %s: %w", addr, err)
+	}
+	p.seqnoMap[addr] = seqno
+	return nil
+}
+
+// forgetSeqnoLocked drops the seqno of the account that has no transactions in the pool,
+// so that the map doesn't grow with every account seen once. The seqno is read from the state again if needed.
+func (p *TxnPool) forgetSeqnoLocked(addr types.Address) {
+	if p.cfg.State == nil || p.all.count(addr) != 0 {
+		return
+	}
+	delete(p.seqnoMap, addr)
+}
+
+// replayJournal restores the transactions persisted by the previous run and compacts the journal.
+// The transactions are re-validated against the committed seqnos and base fee, the stale ones are dropped.
+func (p *TxnPool) replayJournal(ctx context.Context) error {
+	if p.cfg.State != nil {
+		baseFee, err := p.cfg.State.BaseFee(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read base fee: %w", err)
+		}
+		p.baseFee = baseFee
+	}
+
+	j := newJournal(p.cfg.Journal)
+	dropped := 0
+	loaded, err := j.load(func(txn *types.Transaction) error {
+		if txn.To.ShardId() != p.cfg.ShardId {
+			dropped++
+			return nil
+		}
+		reasons, err := p.add(ctx, newMetaTxn(txn, p.baseFee))
+		if err != nil {
+			return err
+		}
+		if reasons[0] != NotSet {
+			dropped++
+		}
+		return nil
+	})
+	if errors.Is(err, errCorruptedJournal) {
+		// Everything before the corrupted record is recovered, the rest is dropped by the rotation below.
+		p.logger.Warn().Err(err).Msg("Transaction pool journal is corrupted")
+	} else if err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.journal = j
+	if err := p.rotateJournalLocked(); err != nil {
+		return fmt.Errorf("failed to rotate journal: %w", err)
+	}
+
+	p.logger.Info().
+		Int("loaded", loaded).
+		Int("dropped", dropped).
+		Msg("Replayed transaction pool journal")
+	return nil
+}
+
+func (p *TxnPool) rejournalLoop(ctx context.Context) {
+	var tick <-chan time.Time
+	if p.cfg.Rejournal > 0 {
+		ticker := time.NewTicker(p.cfg.Rejournal)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.lock.Lock()
+			defer p.lock.Unlock()
+
+			if err := p.journal.close(); err != nil {
+				p.logger.Error().Err(err).Msg("Failed to close transaction pool journal")
+			}
+			p.journal = nil
+			return
+		case <-tick:
+			p.lock.Lock()
+			if err := p.rotateJournalLocked(); err != nil {
+				p.logger.Error().Err(err).Msg("Failed to rotate transaction pool journal")
+			}
+			p.lock.Unlock()
+		}
+	}
+}
+
+// rotateJournalLocked rewrites the journal with the transactions currently in the pool.
+func (p *TxnPool) rotateJournalLocked() error {
+	txns := make([]*types.Transaction, 0, p.all.tree.Len())
+	p.all.ascendAll(func(txn *metaTxn) bool {
+		txns = append(txns, txn.Transaction)
+		return true
+	})
+	if err := p.journal.rotate(txns); err != nil {
+		return err
+	}
+
+	p.logger.Debug().
+		Int("count", len(txns)).
+		Msg("Rotated transaction pool journal")
+	return nil
+}
+
+func (p *TxnPool) journalLocked(txn *metaTxn) {
+	if p.journal == nil {
+		return
+	}
+	if err := p.journal.insert(txn.Transaction); err != nil {
+		p.logger.Error().Err(err).
+			Stringer(logging.FieldTransactionHash, txn.Hash()).
+			Msg("Failed to journal transaction")
+	}
+}
+
+func (p *TxnPool) SubscribeNewTransactions(ch chan<- common.Hash) func() {
+	p.subsLock.Lock()
+	defer p.subsLock.Unlock()
+
+	p.subs[ch] = struct{}{}
+	return func() {
+		p.subsLock.Lock()
+		defer p.subsLock.Unlock()
+		delete(p.subs, ch)
+	}
+}
+
+func (p *TxnPool) notifySubscribers(hash common.Hash) {
+	p.subsLock.Lock()
+	defer p.subsLock.Unlock()
+
+	for ch := range p.subs {
+		// Don't block the pool on a slow subscriber, the notification is just dropped.
+		select {
+		case ch <- hash:
+		default:
+		}
+	}
+}
+
 func (p *TxnPool) validateTxn(txn *metaTxn) (DiscardReason, bool) {
 	if txn.ChainId != types.DefaultChainId {
 		return InvalidChainId, false
 	}
+	return NotSet, true
+}
 
+// validateSeqno checks the transaction against the account seqno, which must be loaded before.
+func (p *TxnPool) validateSeqno(txn *metaTxn) (DiscardReason, bool) {
 	if seqno, ok := p.seqnoMap[txn.To]; ok && seqno > txn.Seqno {
 		p.logger.Debug().
 			Stringer(logging.FieldTransactionHash, txn.Hash()).
@@ -252,6 +507,48 @@ func (p *TxnPool) GetSize() int {
 	return p.all.tree.Len()
 }
 
+func (p *TxnPool) Content() (pending, queued []*types.Transaction) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	pending = make([]*types.Transaction, 0)
+	queued = make([]*types.Transaction, 0)
+	p.all.ascendAll(func(txn *metaTxn) bool {
+		if txn.IsFuture() {
+			queued = append(queued, txn.Transaction)
+		} else {
+			pending = append(pending, txn.Transaction)
+		}
+		return true
+	})
+	return pending, queued
+}
+
+func (p *TxnPool) Discarded() map[common.Hash]DiscardReason {
+	res := make(map[common.Hash]DiscardReason, p.discarded.Len())
+	for _, hash := range p.discarded.Keys() {
+		if reason, ok := p.discarded.Peek(hash); ok {
+			res[hash] = reason
+		}
+	}
+	return res
+}
+
+func (p *TxnPool) Stats() (pending, queued int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.all.ascendAll(func(txn *metaTxn) bool {
+		if txn.IsFuture() {
+			queued++
+		} else {
+			pending++
+		}
+		return true
+	})
+	return pending, queued
+}
+
 func (p *TxnPool) getLocked(hash common.Hash) *metaTxn {
 	txn, ok := p.byHash[string(hash.Bytes())]
 	if ok {
@@ -276,7 +573,7 @@ func (p *TxnPool) addLocked(txn *metaTxn) DiscardReason {
 		p.discardLocked(found, ReplacedByHigherTip)
 	}
 
-	if uint64(p.queue.Len()) >= p.cfg.Size {
+	if uint64(p.all.tree.Len()) >= p.cfg.Size {
 		return PoolOverflow
 	}
 
@@ -286,23 +583,63 @@ func (p *TxnPool) addLocked(txn *metaTxn) DiscardReason {
 	replaced := p.all.replaceOrInsert(txn)
 	check.PanicIfNot(replaced == nil)
 
-	if needToAdd := txn.valid; needToAdd {
-		for _, t := range p.queue.txns {
-			if t.To == txn.To {
-				if t.Seqno > txn.Seqno {
-					p.queue.Remove(t)
-				} else {
-					needToAdd = false
-				}
-				break
+	// The account limits are checked after the insertion since the transaction may fill a seqno gap
+	// and promote the future transactions.
+	pending, future := p.reorganizeLocked(txn.To)
+	if txn.IsFuture() && uint64(future) > p.cfg.AccountQueue {
+		p.discardLocked(txn, FutureQueueOverflow)
+		return FutureQueueOverflow
+	}
+	if !txn.IsFuture() && uint64(pending) > p.cfg.AccountSlots {
+		p.discardLocked(txn, AccountLimitExceeded)
+		return AccountLimitExceeded
+	}
+
+	return NotSet
+}
+
+// reorganizeLocked splits the transactions of the account into the pending ones, which form a contiguous seqno
+// sequence starting from the account seqno, and the future ones, which wait for a seqno gap to be filled.
+// The first valid pending transaction represents the account in the queue.
+func (p *TxnPool) reorganizeLocked(to types.Address) (pending, future int) {
+	next, known := p.seqnoMap[to]
+	var head, queued *metaTxn
+	p.all.ascend(to, func(txn *metaTxn) bool {
+		if !known {
+			// The account seqno is unknown only if the pool has no state to read it from,
+			// so the lowest seqno in the pool is trusted.
+			next, known = txn.Seqno, true
+		}
+
+		txn.future = txn.Seqno != next
+		if txn.future {
+			future++
+		} else {
+			pending++
+			next++
+			if head == nil && txn.IsValid() {
+				head = txn
 			}
 		}
-		if needToAdd {
-			heap.Push(p.queue, txn)
+
+		if txn.IsInQueue() {
+			queued = txn
 		}
-	}
+		return true
+	})
 
-	return NotSet
+	if queued != head {
+		if queued != nil {
+			p.queue.Remove(queued)
+		}
+		if head != nil {
+			heap.Push(p.queue, head)
+		}
+	}
+	if pending+future == 0 {
+		p.forgetSeqnoLocked(to)
+	}
+	return pending, future
 }
 
 // dropping transaction from all sub-structures and from db
@@ -311,11 +648,10 @@ func (p *TxnPool) discardLocked(txn *metaTxn, reason DiscardReason) {
 	hashStr := string(txn.Hash().Bytes())
 	delete(p.byHash, hashStr)
 	p.all.delete(txn, reason)
-	if txn.IsInQueue() {
-		p.queue.Remove(txn)
-		if t := p.nextSenderTxnLocked(txn.To, txn.Seqno); t != nil {
-			heap.Push(p.queue, t)
-		}
+	p.queue.Remove(txn)
+	p.reorganizeLocked(txn.To)
+	if reason != Committed {
+		p.discarded.Add(txn.Hash(), reason)
 	}
 }
 
@@ -368,15 +704,47 @@ func (p *TxnPool) updateTransactionsLocked() {
 		txn.effectivePriorityFee, txn.valid = execution.GetEffectivePriorityFee(p.baseFee, txn.Transaction)
 		return true
 	})
+	heap.Init(p.queue)
+	for to := range p.all.toTxnCount {
+		p.reorganizeLocked(to)
+	}
+}
+
+func (p *TxnPool) evictionLoop(ctx context.Context) {
+	ticker := time.NewTicker(evictionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			p.lock.Lock()
+			p.evictExpiredLocked(now)
+			p.lock.Unlock()
+		}
+	}
+}
+
+// evictExpiredLocked discards the transactions that stayed in the pool longer than the configured lifetime.
+func (p *TxnPool) evictExpiredLocked(now time.Time) {
+	var expired []*metaTxn // can't delete items while iterate them
 	p.all.ascendAll(func(txn *metaTxn) bool {
-		if !txn.valid && txn.bestIndex >= 0 {
-			p.queue.Remove(txn)
-			if t := p.nextSenderTxnLocked(txn.To, txn.Seqno); t != nil {
-				heap.Push(p.queue, t)
-			}
+		if now.Sub(txn.addedAt) > p.cfg.Lifetime {
+			expired = append(expired, txn)
 		}
 		return true
 	})
+
+	for _, txn := range expired {
+		p.discardLocked(txn, Expired)
+	}
+
+	if len(expired) > 0 {
+		p.logger.Debug().
+			Int("count", len(expired)).
+			Msg("Evicted expired transactions")
+	}
 }
 
 // removeCommitted - apply new highest block (or batch of blocks)
@@ -427,6 +795,9 @@ func (p *TxnPool) removeCommitted(bySeqno *ByReceiverAndSeqno, txns []*types.Tra
 			p.discardLocked(txn, Committed)
 		}
 		toDel = toDel[:0]
+
+		// The committed seqno may create a gap or fill it even if no transaction of the account was discarded.
+		p.reorganizeLocked(senderID)
 	}
 
 	if discarded > 0 {
@@ -461,3 +832,7 @@ func (p *TxnPool) Peek(n int) ([]*types.TxnWithHash, error) {
 
 	return res, nil
 }
+
+func discardReasonAttribute(reason DiscardReason) attribute.KeyValue {
+	return attribute.Stringer("discardReason", reason)
+}
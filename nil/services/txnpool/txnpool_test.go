@@ -3,6 +3,8 @@ package txnpool
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"slices"
 	"testing"
 	"time"
@@ -164,6 +166,29 @@ func (s *SuiteTxnPool) TestAddOverflow() {
 		newTransaction(defaultAddress, 1, 123), PoolOverflow)
 }
 
+func (s *SuiteTxnPool) TestSubscribeNewTransactions() {
+	hashes := make(chan common.Hash, 1)
+	unsubscribe := s.pool.SubscribeNewTransactions(hashes)
+
+	txn := newTransaction(defaultAddress, 0, 123)
+	s.addTransactionsSuccessfully(txn)
+	s.Require().Len(hashes, 1)
+	s.Equal(txn.Hash(), <-hashes)
+
+	// Rejected transactions are not reported.
+	s.addTransactionWithDiscardReason(newTransaction(defaultAddress, 0, 123), DuplicateHash)
+	s.Empty(hashes)
+
+	// A full channel doesn't block the pool.
+	s.addTransactionsSuccessfully(newTransaction(defaultAddress, 1, 123), newTransaction(defaultAddress, 2, 123))
+	s.Len(hashes, 1)
+	<-hashes
+
+	unsubscribe()
+	s.addTransactionsSuccessfully(newTransaction(defaultAddress, 3, 123))
+	s.Empty(hashes)
+}
+
 func (s *SuiteTxnPool) TestStarted() {
 	s.True(s.pool.Started())
 }
@@ -382,6 +407,239 @@ func (s *SuiteTxnPool) TestUnverifiedDuplicates() {
 	s.Require().NoError(err)
 }
 
+func (s *SuiteTxnPool) TestAccountLimits() {
+	s.pool.cfg.AccountSlots = 2
+	s.pool.cfg.AccountQueue = 1
+
+	address2 := types.ShardAndHexToAddress(0, "22")
+
+	s.addTransactionsSuccessfully(
+		newTransaction(address2, 0, 123),
+		newTransaction(address2, 1, 123))
+	s.addTransactionWithDiscardReason(newTransaction(address2, 2, 123), AccountLimitExceeded)
+
+	// The limits are per account, future transactions are limited separately.
+	s.addTransactionsSuccessfully(
+		newTransaction(defaultAddress, 0, 123),
+		newTransaction(defaultAddress, 2, 123))
+	overflowed := newTransaction(defaultAddress, 3, 123)
+	reasons := s.addTransactions(overflowed)
+	s.Equal([]DiscardReason{FutureQueueOverflow}, reasons)
+
+	// Filling the gap would promote the future transaction above the pending limit.
+	exceeded := newTransaction(defaultAddress, 1, 123)
+	reasons = s.addTransactions(exceeded)
+	s.Equal([]DiscardReason{AccountLimitExceeded}, reasons)
+
+	pending, queued := s.pool.Stats()
+	s.Equal(3, pending)
+	s.Equal(1, queued)
+
+	discarded := s.pool.Discarded()
+	s.Equal(FutureQueueOverflow, discarded[overflowed.Hash()])
+	s.Equal(AccountLimitExceeded, discarded[exceeded.Hash()])
+}
+
+func (s *SuiteTxnPool) TestFutureQueue() {
+	txn0 := newTransaction(defaultAddress, 0, 123)
+	txn1 := newTransaction(defaultAddress, 1, 123)
+	txn2 := newTransaction(defaultAddress, 2, 123)
+	txn3 := newTransaction(defaultAddress, 3, 123)
+
+	s.addTransactionsSuccessfully(txn0, txn2, txn3)
+
+	pending, queued := s.pool.Content()
+	s.Equal([]*types.Transaction{txn0}, pending)
+	s.Equal([]*types.Transaction{txn2, txn3}, queued)
+
+	// The gap is filled, so the future transactions are promoted.
+	s.addTransactionsSuccessfully(txn1)
+
+	pending, queued = s.pool.Content()
+	s.Equal([]*types.Transaction{txn0, txn1, txn2, txn3}, pending)
+	s.Empty(queued)
+
+	// The discarded transaction makes the following ones future again.
+	s.Require().NoError(s.pool.Discard(s.ctx, []common.Hash{txn1.Hash()}, Unverified))
+
+	pending, queued = s.pool.Content()
+	s.Equal([]*types.Transaction{txn0}, pending)
+	s.Equal([]*types.Transaction{txn2, txn3}, queued)
+	s.Len(s.getTransactions(), 1)
+
+	// The committed seqno defines the first pending transaction.
+	s.Require().NoError(s.pool.OnCommitted(s.ctx, defaultBaseFee, []*types.Transaction{txn0, txn1}))
+
+	pending, queued = s.pool.Content()
+	s.Equal([]*types.Transaction{txn2, txn3}, pending)
+	s.Empty(queued)
+	s.Len(s.getTransactions(), 2)
+}
+
+func (s *SuiteTxnPool) TestEviction() {
+	s.Require().NoError(s.pool.OnCommitted(s.ctx, defaultBaseFee, []*types.Transaction{
+		newTransaction(defaultAddress, 0, 123),
+	}))
+
+	txn1 := newTransaction(defaultAddress, 1, 123)
+	txn2 := newTransaction(defaultAddress, 2, 123)
+	s.addTransactionsSuccessfully(txn1, txn2)
+
+	s.pool.lock.Lock()
+	s.pool.getLocked(txn1.Hash()).addedAt = time.Now().Add(-s.pool.cfg.Lifetime - time.Second)
+	s.pool.evictExpiredLocked(time.Now())
+	s.pool.lock.Unlock()
+
+	s.Equal(1, s.pool.GetSize())
+	poolTxn, err := s.pool.Get(txn1.Hash())
+	s.Require().NoError(err)
+	s.Nil(poolTxn)
+
+	// The remaining transaction waits for the evicted seqno.
+	pending, queued := s.pool.Stats()
+	s.Equal(0, pending)
+	s.Equal(1, queued)
+	s.Empty(s.getTransactions())
+	s.Equal(map[common.Hash]DiscardReason{txn1.Hash(): Expired}, s.pool.Discarded())
+}
+
+type testStateReader struct {
+	baseFee types.Value
+	seqnos  map[types.Address]types.Seqno
+}
+
+func (r *testStateReader) BaseFee(context.Context) (types.Value, error) {
+	return r.baseFee, nil
+}
+
+func (r *testStateReader) ExtSeqno(_ context.Context, addr types.Address) (types.Seqno, error) {
+	return r.seqnos[addr], nil
+}
+
+func (s *SuiteTxnPool) TestJournal() {
+	address2 := types.ShardAndHexToAddress(0, "22")
+	state := &testStateReader{baseFee: defaultBaseFee, seqnos: map[types.Address]types.Seqno{}}
+
+	cfg := NewConfig(0)
+	cfg.Journal = filepath.Join(s.T().TempDir(), "txnpool.journal")
+	cfg.State = state
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	pool, err := New(ctx, cfg, nil)
+	s.Require().NoError(err)
+
+	txn10 := newTransaction(defaultAddress, 0, 123)
+	txn11 := newTransaction(defaultAddress, 1, 123)
+	txn12 := newTransaction(defaultAddress, 2, 123)
+	txn20 := newTransaction2(address2, 0, 123, 300, 0)
+	s.addTransactionsToPoolSuccessfully(pool, txn10, txn11, txn12, txn20)
+
+	cancel()
+	s.Require().Eventually(func() bool {
+		pool.lock.Lock()
+		defer pool.lock.Unlock()
+		return pool.journal == nil
+	}, time.Second, 10*time.Millisecond)
+
+	// Simulate a crash in the middle of a write.
+	f, err := os.OpenFile(cfg.Journal, os.O_WRONLY|os.O_APPEND, 0o644)
+	s.Require().NoError(err)
+	_, err = f.Write([]byte{0x10, 0x01})
+	s.Require().NoError(err)
+	s.Require().NoError(f.Close())
+
+	// While the node was down, the first transaction got committed and the base fee grew above the max fee of
+	// the transaction of the second address.
+	state.seqnos[defaultAddress] = 1
+	state.baseFee = types.NewValueFromUint64(400)
+
+	pool, err = New(s.ctx, cfg, nil)
+	s.Require().NoError(err)
+
+	s.Equal(state.baseFee, pool.GetBaseFee())
+	s.Equal(3, pool.GetSize())
+	for _, txn := range []*types.Transaction{txn11, txn12, txn20} {
+		known, err := pool.IdHashKnown(txn.Hash())
+		s.Require().NoError(err)
+		s.True(known)
+	}
+	known, err := pool.IdHashKnown(txn10.Hash())
+	s.Require().NoError(err)
+	s.False(known)
+
+	txns, err := pool.Peek(0)
+	s.Require().NoError(err)
+	s.Require().Len(txns, 2)
+	s.Equal(txn11.Hash(), txns[0].Hash())
+	s.Equal(txn12.Hash(), txns[1].Hash())
+
+	// The seqno taken from the state is used to validate the new transactions.
+	reasons, err := pool.Add(s.ctx, txn10)
+	s.Require().NoError(err)
+	s.Equal([]DiscardReason{SeqnoTooLow}, reasons)
+}
+
+func (s *SuiteTxnPool) TestFirstTransactionSeqnoFromState() {
+	cfg := NewConfig(0)
+	cfg.State = &testStateReader{baseFee: defaultBaseFee, seqnos: map[types.Address]types.Seqno{defaultAddress: 3}}
+	pool, err := New(s.ctx, cfg, nil)
+	s.Require().NoError(err)
+
+	// The first transaction of the account leaves a gap after the seqno in the state.
+	txn5 := newTransaction(defaultAddress, 5, 123)
+	s.addTransactionsToPoolSuccessfully(pool, txn5)
+
+	pending, queued := pool.Content()
+	s.Empty(pending)
+	s.Equal([]*types.Transaction{txn5}, queued)
+	txns, err := pool.Peek(0)
+	s.Require().NoError(err)
+	s.Empty(txns)
+
+	// The gap is filled.
+	txn3 := newTransaction(defaultAddress, 3, 123)
+	txn4 := newTransaction(defaultAddress, 4, 123)
+	s.addTransactionsToPoolSuccessfully(pool, txn3, txn4)
+
+	pending, queued = pool.Content()
+	s.Equal([]*types.Transaction{txn3, txn4, txn5}, pending)
+	s.Empty(queued)
+}
+
+func (s *SuiteTxnPool) TestSeqnoOfAccountsWithoutTransactions() {
+	address2 := types.ShardAndHexToAddress(0, "22")
+	cfg := NewConfig(0)
+	cfg.State = &testStateReader{baseFee: defaultBaseFee, seqnos: map[types.Address]types.Seqno{address2: 3}}
+	pool, err := New(s.ctx, cfg, nil)
+	s.Require().NoError(err)
+
+	seqnoMapLen := func() int {
+		pool.lock.Lock()
+		defer pool.lock.Unlock()
+		return len(pool.seqnoMap)
+	}
+
+	// The seqno is not kept for the discarded transactions.
+	invalidChainId := newTransaction(address2, 3, 123)
+	invalidChainId.ChainId = types.DefaultChainId + 1
+	tooLow := newTransaction(address2, 2, 123)
+	reasons, err := pool.Add(s.ctx, invalidChainId, tooLow)
+	s.Require().NoError(err)
+	s.Equal([]DiscardReason{InvalidChainId, SeqnoTooLow}, reasons)
+	s.Zero(seqnoMapLen())
+
+	txn1 := newTransaction(defaultAddress, 0, 123)
+	txn2 := newTransaction(address2, 3, 123)
+	s.addTransactionsToPoolSuccessfully(pool, txn1, txn2)
+	s.Equal(2, seqnoMapLen())
+
+	// The seqno is dropped once the account has no transactions in the pool.
+	s.Require().NoError(pool.OnCommitted(s.ctx, defaultBaseFee, []*types.Transaction{txn1}))
+	s.Equal(1, seqnoMapLen())
+	s.Require().NoError(pool.Discard(s.ctx, []common.Hash{txn2.Hash()}, Unverified))
+	s.Zero(seqnoMapLen())
+}
+
 func (s *SuiteTxnPool) checkTransactionsOrder(vals ...int) {
 	s.T().Helper()
 
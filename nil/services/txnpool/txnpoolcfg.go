@@ -2,21 +2,51 @@ package txnpool
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
 
-const defaultPoolSize = 10000
+const (
+	defaultPoolSize     = 10000
+	defaultAccountSlots = 64
+	defaultAccountQueue = 32
+	defaultLifetime     = 3 * time.Hour
+	defaultRejournal    = time.Hour
+)
 
 type Config struct {
 	ShardId types.ShardId
 	Size    uint64
+
+	// AccountSlots is the maximum number of pending transactions of a single account,
+	// i.e., the ones that can be included in the next blocks.
+	AccountSlots uint64
+	// AccountQueue is the maximum number of future transactions of a single account,
+	// i.e., the ones waiting for a seqno gap to be filled.
+	AccountQueue uint64
+	// Lifetime is the maximum time a transaction can stay in the pool. Zero disables the eviction.
+	Lifetime time.Duration
+
+	// Journal is the path of the file that persists the pending transactions across restarts.
+	// Journaling is disabled if empty.
+	Journal string
+	// Rejournal is the interval of the journal rotation.
+	Rejournal time.Duration
+	// State provides the account seqnos the transactions are checked against and the base fee
+	// the journaled transactions are re-validated with on replay.
+	// If nil, only the checks that don't require the state are performed.
+	State StateReader
 }
 
 func NewConfig(shardId types.ShardId) Config {
 	return Config{
-		ShardId: shardId,
-		Size:    defaultPoolSize,
+		ShardId:      shardId,
+		Size:         defaultPoolSize,
+		AccountSlots: defaultAccountSlots,
+		AccountQueue: defaultAccountQueue,
+		Lifetime:     defaultLifetime,
+		Rejournal:    defaultRejournal,
 	}
 }
 
@@ -32,8 +62,12 @@ const (
 	InvalidChainId      DiscardReason = 5
 	// ensure no one is able to specify a transaction with a negative value.
 	NegativeValue DiscardReason = 10
-	PoolOverflow  DiscardReason = 12
-	SeqnoTooLow   DiscardReason = 18
+	// The account has too many pending transactions
+	AccountLimitExceeded DiscardReason = 11
+	PoolOverflow         DiscardReason = 12
+	// The account has too many transactions waiting for a seqno gap to be filled
+	FutureQueueOverflow DiscardReason = 14
+	SeqnoTooLow         DiscardReason = 18
 	// There was an existing transaction with the same sender and seqno, not enough price bump to replace
 	NotReplaced DiscardReason = 20
 	// There was an existing transaction with the same hash
@@ -42,6 +76,8 @@ const (
 	Unverified DiscardReason = 22
 	// Transaction max fee is too small
 	TooSmallMaxFee DiscardReason = 23
+	// Transaction stayed in the pool longer than its lifetime
+	Expired DiscardReason = 24
 )
 
 func (r DiscardReason) String() string {
@@ -62,8 +98,12 @@ func (r DiscardReason) String() string {
 		return "not replaced"
 	case NegativeValue:
 		return "negative value"
+	case AccountLimitExceeded:
+		return "account limit exceeded"
 	case PoolOverflow:
 		return "pool overflow"
+	case FutureQueueOverflow:
+		return "future queue overflow"
 	case SeqnoTooLow:
 		return "seqno too low"
 	case DuplicateHash:
@@ -72,6 +112,8 @@ func (r DiscardReason) String() string {
 		return "verification failed"
 	case TooSmallMaxFee:
 		return "max fee too small"
+	case Expired:
+		return "expired"
 	default:
 		panic(fmt.Sprintf("discard reason: %d", r))
 	}
@@ -14,6 +14,7 @@ abstract contract NilAccessControlUpgradeable is
   INilAccessControlUpgradeable
 {
   error ErrorCallerIsNotProposer();
+  error ErrorCallerIsNotDevnetProposer();
   error ErrorCallerIsNotAdmin();
   error ErrorCallerNotAuthorised();
 
@@ -42,6 +43,13 @@ abstract contract NilAccessControlUpgradeable is
     _;
   }
 
+  modifier onlyDevnetProposer() {
+    if (!hasRole(NilConstants.DEVNET_PROPOSER_ROLE, msg.sender)) {
+      revert ErrorCallerIsNotDevnetProposer();
+    }
+    _;
+  }
+
   /*//////////////////////////////////////////////////////////////////////////
                            ADMIN MANAGEMENT FUNCTIONS
     //////////////////////////////////////////////////////////////////////////*/
@@ -281,6 +281,37 @@ contract NilRollup is OwnableUpgradeable, PausableUpgradeable, NilAccessControlU
         emit BatchCommitted(batchIndex);
     }
 
+    /// @inheritdoc INilRollup
+    function commitBatchCalldata(
+        string memory batchIndex,
+        bytes calldata batchData
+    )
+        external
+        override
+        whenNotPaused
+        onlyProposer
+    {
+        if (batchData.length == 0) {
+            revert ErrorEmptyBatchData(batchIndex);
+        }
+
+        // the data is a part of the commit transaction, so its hash is computed here
+        _commitBatchDataHash(batchIndex, keccak256(batchData));
+    }
+
+    /// @inheritdoc INilRollup
+    function commitBatchDataHash(
+        string memory batchIndex,
+        bytes32 dataHash
+    )
+        external
+        override
+        whenNotPaused
+        onlyDevnetProposer
+    {
+        _commitBatchDataHash(batchIndex, dataHash);
+    }
+
     function getBlobHash(uint256 index) public view virtual returns (bytes32) {
         bytes32 versionedHash;
         assembly {
@@ -289,6 +320,27 @@ contract NilRollup is OwnableUpgradeable, PausableUpgradeable, NilAccessControlU
         return versionedHash;
     }
 
+    function _commitBatchDataHash(string memory batchIndex, bytes32 dataHash) internal {
+        if (bytes(batchIndex).length == 0) {
+            revert ErrorInvalidBatchIndex();
+        }
+
+        if (batchInfoRecords[batchIndex].isFinalized) {
+            revert ErrorBatchAlreadyFinalized(batchIndex);
+        }
+
+        if (batchInfoRecords[batchIndex].isCommitted) {
+            revert ErrorBatchAlreadyCommitted(batchIndex);
+        }
+
+        // mark the batch as committed, it has no blobs to verify the data proofs against
+        batchInfoRecords[batchIndex].isCommitted = true;
+        batchInfoRecords[batchIndex].dataHash = dataHash;
+
+        // emit an event for the committed batch
+        emit BatchCommitted(batchIndex);
+    }
+
     /// @inheritdoc INilRollup
     function updateState(
         string memory batchIndex,
@@ -320,16 +372,16 @@ contract NilRollup is OwnableUpgradeable, PausableUpgradeable, NilAccessControlU
             revert ErrorInvalidValidityProof();
         }
 
-        // Check if dataProofs and validityProof are not zero values
-        if (dataProofs.length == 0) {
-            revert ErrorEmptyDataProofs();
-        }
-
         // Check if batchIndex has storage values of isCommitted true and isFinalized false
         if (!batchInfoRecords[batchIndex].isCommitted) {
             revert ErrorBatchNotCommitted(batchIndex);
         }
 
+        // Check if dataProofs are not empty, the batches committed without blobs have no data proofs
+        if (dataProofs.length == 0 && batchInfoRecords[batchIndex].blobCount != 0) {
+            revert ErrorEmptyDataProofs();
+        }
+
         if (batchInfoRecords[batchIndex].isFinalized) {
             revert ErrorBatchAlreadyFinalized(batchIndex);
         }
@@ -9,6 +9,10 @@ library NilConstants {
   bytes32 public constant PROPOSER_ROLE = keccak256("PROPOSER_ROLE");
   bytes32 public constant PROPOSER_ROLE_ADMIN = keccak256("PROPOSER_ROLE_ADMIN");
 
+  /// @notice Role of the devnet proposers allowed to commit batches with the hash of the data kept off-chain.
+  /// @dev It is never granted on initialization, the batches committed this way have no data availability on L1.
+  bytes32 public constant DEVNET_PROPOSER_ROLE = keccak256("DEVNET_PROPOSER_ROLE");
+
   bytes32 public constant RELAYER_ROLE_ADMIN = keccak256("RELAYER_ROLE_ADMIN");
   bytes32 public constant RELAYER_ROLE = keccak256("RELAYER_ROLE");
 
@@ -41,6 +41,9 @@ interface INilRollup is INilAccessControlUpgradeable {
     /// @dev Error when commitBatch is called on batchIndex which is already finalized
     error ErrorBatchAlreadyFinalized(string batchIndex);
 
+    /// @dev Error when commitBatchCalldata is called with empty batch data
+    error ErrorEmptyBatchData(string batchIndex);
+
     /// @dev Error when the versionHash for a blob at blobIndex in invalid
     error ErrorInvalidVersionedHash(string batchIndex, uint256 blobIndex);
 
@@ -153,6 +156,8 @@ interface INilRollup is INilAccessControlUpgradeable {
         PublicDataInfo publicDataInfo;
         /// @notice The number of blobs in the batch
         uint256 blobCount;
+        /// @notice The hash of the batch data published without blobs, zero for the batches committed with blobs
+        bytes32 dataHash;
     }
 
     /*//////////////////////////////////////////////////////////////////////////
@@ -236,6 +241,23 @@ interface INilRollup is INilAccessControlUpgradeable {
      */
     function commitBatch(string memory batchIndex, uint256 blobCount) external;
 
+    /**
+     * @notice Commits a new batch whose data is published as calldata of the commit transaction.
+     * @dev The contract stores the keccak256 hash of the data, the batch is finalized without data proofs.
+     * @param batchIndex The index of the batch.
+     * @param batchData The encoded batch data.
+     */
+    function commitBatchCalldata(string memory batchIndex, bytes calldata batchData) external;
+
+    /**
+     * @notice Commits a new batch whose data is kept off-chain, only on devnets.
+     * @dev This function can only be called by an account with the DEVNET_PROPOSER_ROLE, as L1 doesn't check
+     * that the data is available. The batch is finalized without data proofs.
+     * @param batchIndex The index of the batch.
+     * @param dataHash The keccak256 hash of the batch data.
+     */
+    function commitBatchDataHash(string memory batchIndex, bytes32 dataHash) external;
+
     /**
      * @notice Updates the state root for a batch.
      * @dev This function allows an account with the PROPOSER_ROLE to update the state root for a batch.
@@ -7,6 +7,7 @@ import { BaseTest } from "./BaseTest.sol";
 import { INilRollup } from "../contracts/interfaces/INilRollup.sol";
 import { NilRollup } from "../contracts/NilRollup.sol";
 import { NilAccessControlUpgradeable } from "../contracts/NilAccessControlUpgradeable.sol";
+import { NilConstants } from "../contracts/common/libraries/NilConstants.sol";
 import { NilRollupMockBlob } from "./mocks/NilRollupMockBlob.sol";
 import { NilRollupMockBlobInvalidScenario } from "./mocks/NilRollupMockBlobInvalidScenario.sol";
 import { ITransparentUpgradeableProxy } from "@openzeppelin/contracts/proxy/transparent/TransparentUpgradeableProxy.sol";
@@ -171,6 +172,79 @@ contract NilRollupTest is BaseTest {
     updateStateWithTestData(_proposer, batchData);
   }
 
+  /**
+   * @notice Tests the `updateState` function for a batch committed with the data in the calldata instead of blobs.
+   *
+   * @dev This test follows these steps:
+   * 1. Commits the batch with its data and verifies its committed state and the data hash computed by the contract.
+   * 2. Expects a revert on the second commit of the batch and on the commit of empty data.
+   * 3. Updates the state without data proofs and verifies the batch is finalized.
+   *
+   * The test ensures that the batches published as calldata can be finalized.
+   */
+  function test_UpdateState_with_commitBatchCalldata() external {
+    BatchData memory batchData = generateBatchData();
+    BatchDataItem memory batchDataItem = batchData.batches[0];
+    string memory batchIndex = batchDataItem.batchId;
+
+    vm.startPrank(_proposer);
+
+    vm.expectEmit(false, false, false, true);
+    emit BatchCommitted(batchIndex);
+    rollup.commitBatchCalldata(batchIndex, "batch data");
+
+    assertTrue(rollup.isBatchCommitted(batchIndex));
+    assertFalse(rollup.isBatchFinalized(batchIndex));
+    (, , , , , , , , bytes32 dataHash) = rollup.batchInfoRecords(batchIndex);
+    assertEq(dataHash, keccak256("batch data"));
+
+    vm.expectRevert(abi.encodeWithSelector(INilRollup.ErrorBatchAlreadyCommitted.selector, batchIndex));
+    rollup.commitBatchCalldata(batchIndex, "batch data");
+
+    vm.expectRevert(abi.encodeWithSelector(INilRollup.ErrorEmptyBatchData.selector, "BATCH_EMPTY"));
+    rollup.commitBatchCalldata("BATCH_EMPTY", "");
+
+    rollup.updateState(
+      batchIndex,
+      batchDataItem.oldStateRoot,
+      batchDataItem.newStateRoot,
+      new bytes[](0),
+      batchDataItem.validityProof,
+      publicDataInfoMock
+    );
+
+    vm.stopPrank();
+
+    assertTrue(rollup.isBatchFinalized(batchIndex));
+  }
+
+  /**
+   * @notice Tests that the `commitBatchDataHash` function is allowed only to the devnet proposers.
+   *
+   * @dev This test follows these steps:
+   * 1. Expects a revert on the commit by the proposer without the DEVNET_PROPOSER_ROLE.
+   * 2. Grants the role to the proposer and verifies the batch is committed with the given data hash.
+   *
+   * The test ensures that the batches with the data kept off-chain are committed only on devnets.
+   */
+  function test_commitBatchDataHash_requires_devnetProposer() external {
+    string memory batchIndex = "BATCH_1";
+
+    vm.prank(_proposer);
+    vm.expectRevert(NilAccessControlUpgradeable.ErrorCallerIsNotDevnetProposer.selector);
+    rollup.commitBatchDataHash(batchIndex, keccak256("batch data"));
+
+    vm.prank(_defaultAdmin);
+    rollup.grantAccess(NilConstants.DEVNET_PROPOSER_ROLE, _proposer);
+
+    vm.prank(_proposer);
+    rollup.commitBatchDataHash(batchIndex, keccak256("batch data"));
+
+    assertTrue(rollup.isBatchCommitted(batchIndex));
+    (, , , , , , , , bytes32 dataHash) = rollup.batchInfoRecords(batchIndex);
+    assertEq(dataHash, keccak256("batch data"));
+  }
+
   /**
    * @notice Tests the `commitBatch` function to ensure it reverts when called by a non-proposer.
    *
@@ -304,6 +304,36 @@ library Nil {
         __Precompile__(GOVERNANCE).precompileRollback(version, counter, patchLevel, mainBlockId /*, replayDepth, searchDepth */);
     }
 
+    /**
+     * @dev Schedules addition or removal of a shard validator.
+     * The change is activated at the next epoch boundary of the main shard.
+     * @param shardId Shard of the validator, the main shard validators are the union of all shards' ones.
+     * @param remove Whether the validator is removed or added.
+     * @param pubkey BLS public key of the validator.
+     * @param withdrawalAddress Withdrawal address of the validator.
+     * @param weight Voting power of the validator, zero means the default power of one.
+     */
+    function scheduleValidatorChange(
+        uint32 shardId,
+        bool remove,
+        bytes memory pubkey,
+        address withdrawalAddress,
+        uint64 weight
+    ) internal {
+        __Precompile__(GOVERNANCE).precompileScheduleValidatorChange(
+            1, shardId, remove, pubkey, withdrawalAddress, weight);
+    }
+
+    /**
+     * @dev Reports the double signing of a validator.
+     * The evidence is verified, and the validator is removed from the shard at the next epoch boundary
+     * of the main shard.
+     * @param evidence SSZ-encoded evidence as returned by debug_getDoubleSignEvidence.
+     */
+    function reportDoubleSign(bytes memory evidence) internal {
+        __Precompile__(GOVERNANCE).precompileReportDoubleSign(1, evidence);
+    }
+
     /**
      * @dev Sets a configuration parameter.
      * @param name Name of the parameter.
@@ -347,6 +377,31 @@ library Nil {
         bytes32 hash;
     }
 
+    struct ValidatorChange {
+        uint32 shardId;
+        bool remove;
+        ValidatorInfo validator;
+        uint64 weight;
+    }
+
+    struct ParamValidatorSchedule {
+        uint64 epochLength;
+        ValidatorChange[] pending;
+    }
+
+    struct ValidatorWeight {
+        uint8[33] PublicKey;
+        uint64 Weight;
+    }
+
+    struct ListValidatorWeights {
+        ValidatorWeight[] list;
+    }
+
+    struct ParamValidatorWeights {
+        ListValidatorWeights[] weights;
+    }
+
     /**
      * @dev Returns the current validators.
      * @return Struct containing the list of validators.
@@ -437,12 +492,16 @@ contract __Precompile__ {
     function precompileConfigParam(bool isSet, string calldata name, bytes calldata data) public returns(bytes memory) {}
     function precompileLog(string memory transaction, int[] memory data) public returns(bool) {}
     function precompileRollback(uint32, uint32, uint32, uint64 /*, uint32, uint32*/) public returns(bool) {}
+    function precompileScheduleValidatorChange(uint32, uint32, bool, bytes memory, address, uint64) public returns(bool) {}
+    function precompileReportDoubleSign(uint32, bytes memory) public returns(bool) {}
 }
 
 contract NilConfigAbi {
     function curr_validators(Nil.ParamValidators memory) public {}
     function gas_price(Nil.ParamGasPrice memory) public {}
     function l1block(Nil.ParamL1BlockInfo memory) public {}
+    function validator_schedule(Nil.ParamValidatorSchedule memory) public {}
+    function validator_weights(Nil.ParamValidatorWeights memory) public {}
 }
 
 function tokenIdEqual(TokenId a, TokenId b) pure returns (bool) {